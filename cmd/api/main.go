@@ -9,6 +9,7 @@ import (
 	"github.com/cmlabs-hris/hris-backend-go/internal/config"
 	appHTTP "github.com/cmlabs-hris/hris-backend-go/internal/handler/http"
 	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/middleware"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/cron"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/email"
@@ -20,6 +21,7 @@ import (
 	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
 	attendanceService "github.com/cmlabs-hris/hris-backend-go/internal/service/attendance"
 	serviceAuth "github.com/cmlabs-hris/hris-backend-go/internal/service/auth"
+	calendarService "github.com/cmlabs-hris/hris-backend-go/internal/service/calendar"
 	serviceCompany "github.com/cmlabs-hris/hris-backend-go/internal/service/company"
 	dashboardService "github.com/cmlabs-hris/hris-backend-go/internal/service/dashboard"
 	employeeService "github.com/cmlabs-hris/hris-backend-go/internal/service/employee"
@@ -31,6 +33,7 @@ import (
 	notificationService "github.com/cmlabs-hris/hris-backend-go/internal/service/notification"
 	payrollService "github.com/cmlabs-hris/hris-backend-go/internal/service/payroll"
 	reportService "github.com/cmlabs-hris/hris-backend-go/internal/service/report"
+	roleService "github.com/cmlabs-hris/hris-backend-go/internal/service/role"
 	scheduleService "github.com/cmlabs-hris/hris-backend-go/internal/service/schedule"
 	subscriptionService "github.com/cmlabs-hris/hris-backend-go/internal/service/subscription"
 )
@@ -43,16 +46,23 @@ func main() {
 	}
 
 	dsn := cfg.DatabaseURL()
-	db, err := database.NewPostgreSQLDB(dsn)
+	db, err := database.NewPostgreSQLDB(dsn, time.Duration(cfg.Database.StatementTimeout)*time.Millisecond)
 	if err != nil {
 		fmt.Println("Error connecting to database:", err)
 		return
 	}
+	db.TxRetry = database.TxRetryPolicy{
+		MaxRetries: cfg.Database.TxMaxRetries,
+		BaseDelay:  time.Duration(cfg.Database.TxRetryBaseDelay) * time.Millisecond,
+		MaxDelay:   time.Duration(cfg.Database.TxRetryMaxDelay) * time.Millisecond,
+	}
+	db.ReportStatementTimeout = time.Duration(cfg.Database.ReportStatementTimeout) * time.Millisecond
 
 	userRepo := postgresql.NewUserRepository(db)
 	companyRepo := postgresql.NewCompanyRepository(db)
 	JWTRepository := postgresql.NewJWTRepository(db)
 	passwordResetRepo := postgresql.NewPasswordResetRepository(db)
+	oauthLinkRepo := postgresql.NewOAuthLinkRepository(db)
 	leaveTypeRepo := postgresql.NewLeaveTypeRepository(db)
 	leaveQuotaRepo := postgresql.NewLeaveQuotaRepository(db)
 	leaveRequestRepo := postgresql.NewLeaveRequestRepository(db)
@@ -64,13 +74,18 @@ func main() {
 	workScheduleTimeRepo := postgresql.NewWorkScheduleTimeRepository(db)
 	workScheduleLocationRepo := postgresql.NewWorkScheduleLocationRepository(db)
 	employeeScheduleAssignmentRepo := postgresql.NewEmployeeScheduleAssignmentRepository(db)
+	shiftSwapRepo := postgresql.NewShiftSwapRepository(db)
 	attendanceRepo := postgresql.NewAttendanceRepository(db)
+	attendanceSettingsRepo := postgresql.NewAttendanceSettingsRepository(db)
 	invitationRepo := postgresql.NewInvitationRepository(db)
 	payrollRepo := postgresql.NewPayrollRepository(db)
+	auditRepo := postgresql.NewAuditRepository(db)
 	dashboardRepo := postgresql.NewDashboardRepository(db)
 	empDashboardRepo := postgresql.NewEmployeeDashboardRepository(db)
 	notificationRepo := postgresql.NewNotificationRepository(db)
 	reportRepo := postgresql.NewReportRepository(db)
+	roleRepo := postgresql.NewRoleRepository(db)
+	holidayRepo := postgresql.NewHolidayRepository(db)
 
 	// Subscription repositories
 	featureRepo := postgresql.NewFeatureRepository(db)
@@ -78,15 +93,27 @@ func main() {
 	subscriptionRepo := postgresql.NewSubscriptionRepository(db)
 	invoiceRepo := postgresql.NewInvoiceRepository(db)
 	employeeCounter := postgresql.NewEmployeeCounter(db)
+	billingContactFinder := postgresql.NewBillingContactFinder(db)
 
 	// Initialize SSE Hub for real-time notifications
 	sseHub := sse.NewHub()
 
+	// Shared system clock for time-dependent services
+	systemClock := clock.New()
+
+	// Company calendars (working weekdays and holidays) shared by working-day math
+	calendarSvc := calendarService.NewService(holidayRepo)
+
 	JWTService := jwt.NewJWTService(cfg.JWT.Secret, cfg.JWT.AccessExpiration, cfg.JWT.RefreshExpiration)
 	GoogleService := oauth.NewGoogleService(cfg.OAuth2Google.ClientID, cfg.OAuth2Google.ClientSecret, cfg.OAuth2Google.RedirectURL, cfg.OAuth2Google.Scopes)
-	quotaCalculatorService := leave.NewQuotaCalculator()
-	quotaService := leave.NewQuotaService(db, leaveTypeRepo, leaveQuotaRepo, employeeRepo, quotaCalculatorService)
-	requestService := leave.NewRequestService(db, leaveTypeRepo, leaveQuotaRepo, leaveRequestRepo, employeeRepo)
+	oauthProviders := []oauth.Provider{GoogleService}
+	if cfg.OAuth2Microsoft.ClientID != "" {
+		MicrosoftService := oauth.NewMicrosoftService(cfg.OAuth2Microsoft.ClientID, cfg.OAuth2Microsoft.ClientSecret, cfg.OAuth2Microsoft.RedirectURL, cfg.OAuth2Microsoft.Tenant, cfg.OAuth2Microsoft.Scopes)
+		oauthProviders = append(oauthProviders, MicrosoftService)
+	}
+	quotaCalculatorService := leave.NewQuotaCalculator(systemClock)
+	quotaService := leave.NewQuotaService(db, leaveTypeRepo, leaveQuotaRepo, employeeRepo, companyRepo, quotaCalculatorService, systemClock)
+	requestService := leave.NewRequestService(db, leaveTypeRepo, leaveQuotaRepo, leaveRequestRepo, employeeRepo, companyRepo, calendarSvc, systemClock)
 	var fileStorage storage.FileStorage
 	switch cfg.Storage.Type {
 	case "local":
@@ -104,15 +131,27 @@ func main() {
 		log.Fatal("Unsupported storage types: ", cfg.Storage.Type)
 	}
 
-	fileService := file.NewFileService(fileStorage)
+	fileService := file.NewFileService(fileStorage, file.URLExpiry{
+		Default: time.Duration(cfg.Storage.URLExpiryMinutes) * time.Minute,
+		Payslip: time.Duration(cfg.Storage.PayslipURLExpiryMinutes) * time.Minute,
+	})
 	emailService, err := email.NewEmailService(cfg.SMTP)
 	if err != nil {
 		log.Fatal("Failed to initialize email service:", err)
 	}
 
+	// Initialize notification service
+	notificationSvc := notificationService.NewNotificationService(notificationRepo, sseHub, notificationService.Config{
+		BatchSize:     100,
+		FlushInterval: 5 * time.Second,
+		WorkerCount:   2,
+		QueueSize:     1000,
+	})
+
 	// Initialize Xendit client
 	xenditClient := xendit.NewClient(cfg.Xendit)
 	webhookVerifier := xendit.NewWebhookVerifier(cfg.Xendit.WebhookToken)
+	paymentProvider := xendit.NewProvider(xenditClient, webhookVerifier)
 
 	// Initialize subscription service
 	subscriptionSvc := subscriptionService.NewSubscriptionService(
@@ -121,15 +160,26 @@ func main() {
 		subscriptionRepo,
 		invoiceRepo,
 		employeeCounter,
-		xenditClient,
+		paymentProvider,
+		billingContactFinder,
+		notificationSvc,
+		emailService,
+		subscriptionService.NewNotificationEventPublisher(billingContactFinder, notificationSvc),
 		db,
 		cfg,
+		systemClock,
 	)
 
 	// Initialize subscription middleware
 	subscriptionMiddleware := middleware.NewSubscriptionMiddleware(subscriptionSvc)
 
-	authService := serviceAuth.NewAuthService(db, userRepo, companyRepo, JWTService, JWTRepository, passwordResetRepo, employeeRepo, emailService, cfg.App.FrontendURL, subscriptionSvc)
+	// Initialize branch scope middleware (branch-scoped managers)
+	branchScopeMiddleware := middleware.NewBranchScopeMiddleware(userRepo)
+
+	// Initialize permission middleware (base role plus company role)
+	permissionMiddleware := middleware.NewPermissionMiddleware(roleRepo)
+
+	authService := serviceAuth.NewAuthService(db, userRepo, companyRepo, JWTService, JWTRepository, passwordResetRepo, oauthLinkRepo, employeeRepo, emailService, cfg.App.FrontendURL, subscriptionSvc)
 	companyService := serviceCompany.NewCompanyService(
 		db,
 		companyRepo,
@@ -145,23 +195,22 @@ func main() {
 		quotaService,
 		notificationRepo,
 		subscriptionSvc,
+		holidayRepo,
+		calendarSvc,
 	)
-	masterService := master.NewMasterService(branchRepo, gradeRepo, positionRepo)
-	notificationSvc := notificationService.NewNotificationService(notificationRepo, sseHub, notificationService.Config{
-		BatchSize:     100,
-		FlushInterval: 5 * time.Second,
-		WorkerCount:   2,
-		QueueSize:     1000,
-	})
-	leaveService := leave.NewLeaveService(db, leaveTypeRepo, leaveQuotaRepo, leaveRequestRepo, employeeRepo, attendanceRepo, quotaService, requestService, fileService, notificationSvc)
+	masterService := master.NewMasterService(db, branchRepo, gradeRepo, positionRepo)
+	leaveService := leave.NewLeaveService(db, leaveTypeRepo, leaveQuotaRepo, leaveRequestRepo, employeeRepo, attendanceRepo, quotaService, requestService, fileService, notificationSvc, systemClock, time.Duration(cfg.Notification.LeaveRequestWindowMinutes)*time.Minute, time.Duration(cfg.Leave.ReopenWindowDays)*24*time.Hour)
 	scheduleService := scheduleService.NewScheduleService(
 		db,
 		workScheduleRepo,
 		workScheduleTimeRepo,
 		workScheduleLocationRepo,
 		employeeScheduleAssignmentRepo,
+		shiftSwapRepo,
 		employeeRepo,
+		companyRepo,
 		notificationSvc,
+		systemClock,
 	)
 	attendanceService := attendanceService.NewAttendanceService(
 		db,
@@ -170,8 +219,11 @@ func main() {
 		workScheduleRepo,
 		workScheduleTimeRepo,
 		branchRepo,
+		attendanceSettingsRepo,
+		calendarSvc,
 		fileService,
 		notificationSvc,
+		systemClock,
 	)
 	invitationService := invitationService.NewInvitationService(
 		db,
@@ -182,24 +234,35 @@ func main() {
 		fileService,
 		cfg.Invitation,
 		notificationSvc,
+		subscriptionSvc,
 	)
 	employeeService := employeeService.NewEmployeeService(
 		db,
 		employeeRepo,
 		companyRepo,
+		gradeRepo,
+		branchRepo,
+		userRepo,
 		fileService,
 		invitationService,
 		quotaService,
 		subscriptionSvc,
+		attendanceRepo,
+		leaveTypeRepo,
+		leaveQuotaRepo,
+		leaveRequestRepo,
+		payrollRepo,
+		auditRepo,
 	)
 	payrollSvc := payrollService.NewPayrollService(db, payrollRepo, employeeRepo, notificationSvc)
-	dashboardSvc := dashboardService.NewDashboardService(dashboardRepo)
-	empDashboardSvc := employeeDashboardService.NewEmployeeDashboardService(empDashboardRepo)
+	dashboardSvc := dashboardService.NewDashboardService(db, dashboardRepo, systemClock)
+	empDashboardSvc := employeeDashboardService.NewEmployeeDashboardService(empDashboardRepo, employeeRepo)
 	reportSvc := reportService.NewReportService(reportRepo)
+	roleSvc := roleService.NewRoleService(roleRepo, employeeRepo)
 
-	authHandler := appHTTP.NewAuthHandler(JWTService, authService, GoogleService, cfg.App.FrontendURL)
+	authHandler := appHTTP.NewAuthHandler(JWTService, authService, cfg.App.FrontendURL, oauthProviders...)
 	companyHandler := appHTTP.NewCompanyHandler(JWTService, companyService, fileService)
-	leaveHandler := appHTTP.NewLeaveHandler(leaveService, fileService)
+	leaveHandler := appHTTP.NewLeaveHandler(leaveService, fileService, JWTService)
 	masterHandler := appHTTP.NewMasterHandler(masterService)
 	scheduleHandler := appHTTP.NewScheduleHandler(scheduleService)
 	attendanceHandler := appHTTP.NewAttendanceHandler(attendanceService)
@@ -210,22 +273,27 @@ func main() {
 	empDashboardHandler := appHTTP.NewEmployeeDashboardHandler(empDashboardSvc)
 	notificationHandler := appHTTP.NewNotificationHandler(notificationSvc, JWTService)
 	reportHandler := appHTTP.NewReportHandler(reportSvc)
-	subscriptionHandler := appHTTP.NewSubscriptionHandler(subscriptionSvc, webhookVerifier)
+	subscriptionHandler := appHTTP.NewSubscriptionHandler(subscriptionSvc, paymentProvider)
+	roleHandler := appHTTP.NewRoleHandler(roleSvc)
 
 	// Initialize cron scheduler
 	cronScheduler := cron.NewScheduler()
 	subscriptionJobs := cron.NewSubscriptionJobs(subscriptionSvc)
 	subscriptionJobs.RegisterJobs(cronScheduler)
 	attendanceJobs := cron.NewAttendanceJobs(
+		attendanceService,
 		attendanceRepo,
 		employeeRepo,
 		workScheduleRepo,
-		workScheduleTimeRepo,
 		branchRepo,
 		notificationSvc,
 		db,
 	)
 	attendanceJobs.RegisterJobs(cronScheduler)
+	leaveJobs := cron.NewLeaveJobs(quotaService, leaveService, db)
+	leaveJobs.RegisterJobs(cronScheduler)
+	notificationJobs := cron.NewNotificationJobs(notificationSvc)
+	notificationJobs.RegisterJobs(cronScheduler)
 	go cronScheduler.Start()
 	defer cronScheduler.Stop()
 
@@ -245,8 +313,12 @@ func main() {
 		notificationHandler,
 		reportHandler,
 		subscriptionHandler,
+		roleHandler,
 		subscriptionMiddleware,
+		branchScopeMiddleware,
+		permissionMiddleware,
 		cfg.Storage.BasePath,
+		cfg.App.MaxBodyBytes,
 	)
 
 	port := fmt.Sprintf(":%d", cfg.App.Port)
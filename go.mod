@@ -43,7 +43,11 @@ require (
 	golang.org/x/image v0.33.0
 )
 
-require github.com/xendit/xendit-go/v7 v7.0.0
+require (
+	github.com/go-pdf/fpdf v0.9.0
+	github.com/swaggo/http-swagger/v2 v2.0.2
+	github.com/xendit/xendit-go/v7 v7.0.0
+)
 
 require (
 	github.com/KyleBanks/depth v1.2.1 // indirect
@@ -61,7 +65,6 @@ require (
 	github.com/josharian/intern v1.0.0 // indirect
 	github.com/mailru/easyjson v0.9.2 // indirect
 	github.com/swaggo/files/v2 v2.0.2 // indirect
-	github.com/swaggo/http-swagger/v2 v2.0.2 // indirect
 	github.com/swaggo/swag v1.16.6 // indirect
 	go.yaml.in/yaml/v3 v3.0.4 // indirect
 	golang.org/x/mod v0.37.0 // indirect
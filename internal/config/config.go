@@ -11,14 +11,28 @@ import (
 )
 
 type Config struct {
-	Database     DatabaseConfig
-	JWT          JWTConfig
-	App          AppConfig
-	OAuth2Google OAuth2GoogleConfig
-	Storage      StorageConfig
-	SMTP         SMTPConfig
-	Invitation   InvitationConfig
-	Xendit       XenditConfig
+	Database        DatabaseConfig
+	JWT             JWTConfig
+	App             AppConfig
+	OAuth2Google    OAuth2GoogleConfig
+	OAuth2Microsoft OAuth2MicrosoftConfig
+	Storage         StorageConfig
+	SMTP            SMTPConfig
+	Invitation      InvitationConfig
+	Xendit          XenditConfig
+	Subscription    SubscriptionConfig
+	Notification    NotificationConfig
+	Leave           LeaveConfig
+}
+
+// LeaveConfig holds leave request configuration
+type LeaveConfig struct {
+	ReopenWindowDays int // Days after a rejection during which the employee may reopen the request (default: 7)
+}
+
+// NotificationConfig holds notification delivery configuration
+type NotificationConfig struct {
+	LeaveRequestWindowMinutes int // Window in which a manager's leave request notifications from one employee are coalesced (0 = off, default: 10)
 }
 
 // SMTPConfig holds SMTP configuration for sending emails
@@ -46,6 +60,40 @@ type XenditConfig struct {
 	InvoiceExpiry   int    // Invoice expiry in hours (default: 24)
 	SuccessRedirect string // URL to redirect after successful payment
 	FailureRedirect string // URL to redirect after failed payment
+	MaxRetries      *int   // Retries for transient API failures (default: 3); 0 disables retries
+	RetryBaseDelay  int    // Initial backoff delay in milliseconds (default: 200)
+	RetryMaxDelay   int    // Maximum backoff delay in milliseconds (default: 2000)
+	RequestTimeout  int    // Per-attempt API timeout in seconds (default: 15)
+}
+
+// Bounds for configured trial terms
+const (
+	MaxTrialDurationDays = 90
+	MaxTrialSeats        = 100
+)
+
+// TrialTerms is the length and seat allowance of a free trial
+type TrialTerms struct {
+	DurationDays int
+	MaxSeats     int
+}
+
+// SubscriptionConfig holds subscription lifecycle configuration
+type SubscriptionConfig struct {
+	TrialReminderDays []int                 // Days before trial end to send reminders (default: 3,1)
+	Trial             TrialTerms            // Default trial terms (default: 14 days, 5 seats)
+	TrialSources      map[string]TrialTerms // Overrides per signup source/campaign, e.g. "referral"
+	// Schedule a pending seat increase for active subscriptions over their seat limit instead of only flagging them
+	SeatReconcileAutoSchedule bool
+}
+
+// TrialTermsFor returns the trial terms for a signup source, falling back to the default
+// trial when the source is empty or has no override
+func (c SubscriptionConfig) TrialTermsFor(source string) TrialTerms {
+	if terms, ok := c.TrialSources[strings.ToLower(strings.TrimSpace(source))]; ok {
+		return terms
+	}
+	return c.Trial
 }
 
 type DatabaseConfig struct {
@@ -55,6 +103,13 @@ type DatabaseConfig struct {
 	Password string
 	Name     string
 	SSLMode  string
+
+	TxMaxRetries     int // Retries of a transaction aborted by a serialization failure or deadlock (default: 3)
+	TxRetryBaseDelay int // Initial backoff delay in milliseconds (default: 50)
+	TxRetryMaxDelay  int // Maximum backoff delay in milliseconds (default: 1000)
+
+	StatementTimeout       int // Longest any query may run, in milliseconds (0 = no limit, default: 30000)
+	ReportStatementTimeout int // Longest a dashboard or payroll summary query may run, in milliseconds (default: 10000)
 }
 
 // JWTConfig holds JWT configuration
@@ -70,8 +125,14 @@ type AppConfig struct {
 	Env         string
 	LogLevel    string
 	FrontendURL string
+	// MaxBodyBytes caps every request body; larger requests get 413 (default: 10MB)
+	MaxBodyBytes int64
 }
 
+// minBodyLimitMB keeps the request body cap above the 5MB per-file upload rule, so an oversized
+// file is reported by that rule's own error instead of a bare 413
+const minBodyLimitMB = 6
+
 type OAuth2GoogleConfig struct {
 	ClientID     string
 	ClientSecret string
@@ -79,11 +140,24 @@ type OAuth2GoogleConfig struct {
 	Scopes       []string
 }
 
+// OAuth2MicrosoftConfig configures Microsoft (Azure AD / Microsoft 365) login
+// Microsoft login is disabled when ClientID is empty
+type OAuth2MicrosoftConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Tenant       string // "common", "organizations" or a tenant ID
+	Scopes       []string
+}
+
 type StorageConfig struct {
 	Type     string // "local", "minio", "s3"
 	BasePath string // "./storage"
 	BaseURL  string // "http://localhost:8080/uploads"
 
+	URLExpiryMinutes        int // Lifetime of generated file URLs, e.g. leave attachments (default: 15)
+	PayslipURLExpiryMinutes int // Lifetime of downloadable payslip URLs (default: 1440)
+
 	// MinIO/S3 config (for future)
 	Endpoint  string
 	AccessKey string
@@ -106,6 +180,26 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid DB_PORT: %w", err)
 	}
 
+	txMaxRetries, err := strconv.Atoi(getEnv("DB_TX_MAX_RETRIES", "3"))
+	if err != nil || txMaxRetries < 0 {
+		return nil, fmt.Errorf("invalid DB_TX_MAX_RETRIES: must be a non-negative number")
+	}
+	txRetryBaseDelay, err := strconv.Atoi(getEnv("DB_TX_RETRY_BASE_DELAY_MS", "50"))
+	if err != nil || txRetryBaseDelay < 0 {
+		return nil, fmt.Errorf("invalid DB_TX_RETRY_BASE_DELAY_MS: must be a non-negative number of milliseconds")
+	}
+	txRetryMaxDelay, err := strconv.Atoi(getEnv("DB_TX_RETRY_MAX_DELAY_MS", "1000"))
+	if err != nil || txRetryMaxDelay < 0 {
+		return nil, fmt.Errorf("invalid DB_TX_RETRY_MAX_DELAY_MS: must be a non-negative number of milliseconds")
+	}
+	statementTimeout, err := strconv.Atoi(getEnv("DB_STATEMENT_TIMEOUT_MS", "30000"))
+	if err != nil || statementTimeout < 0 {
+		return nil, fmt.Errorf("invalid DB_STATEMENT_TIMEOUT_MS: must be a non-negative number of milliseconds")
+	}
+	reportStatementTimeout, err := strconv.Atoi(getEnv("DB_REPORT_STATEMENT_TIMEOUT_MS", "10000"))
+	if err != nil || reportStatementTimeout < 0 {
+		return nil, fmt.Errorf("invalid DB_REPORT_STATEMENT_TIMEOUT_MS: must be a non-negative number of milliseconds")
+	}
 	config.Database = DatabaseConfig{
 		Host:     getEnv("DB_HOST", "localhost"),
 		Port:     dbPort,
@@ -113,6 +207,13 @@ func Load() (*Config, error) {
 		Password: getEnv("DB_PASSWORD", ""),
 		Name:     getEnv("DB_NAME", "cmlasb-hris"),
 		SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+
+		TxMaxRetries:     txMaxRetries,
+		TxRetryBaseDelay: txRetryBaseDelay,
+		TxRetryMaxDelay:  txRetryMaxDelay,
+
+		StatementTimeout:       statementTimeout,
+		ReportStatementTimeout: reportStatementTimeout,
 	}
 
 	// Redis configuration
@@ -139,11 +240,17 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid APP_PORT: %w", err)
 	}
 
+	maxBodyMB, err := strconv.Atoi(getEnv("MAX_REQUEST_BODY_MB", "10"))
+	if err != nil || maxBodyMB < minBodyLimitMB {
+		return nil, fmt.Errorf("invalid MAX_REQUEST_BODY_MB: must be at least %d", minBodyLimitMB)
+	}
+
 	config.App = AppConfig{
-		Port:        appPort,
-		Env:         getEnv("APP_ENV", "development"),
-		LogLevel:    getEnv("LOG_LEVEL", "info"),
-		FrontendURL: getEnv("FRONTEND_URL", "http://localhost:3000"),
+		Port:         appPort,
+		Env:          getEnv("APP_ENV", "development"),
+		LogLevel:     getEnv("LOG_LEVEL", "info"),
+		FrontendURL:  getEnv("FRONTEND_URL", "http://localhost:3000"),
+		MaxBodyBytes: int64(maxBodyMB) << 20,
 	}
 
 	// JWT configuration
@@ -168,14 +275,37 @@ func Load() (*Config, error) {
 		Scopes:       GoogleScopes,
 	}
 
+	// OAuth2 Microsoft Configuration (optional)
+	microsoftScopes := getEnvSlice("MICROSOFT_SCOPES")
+	if len(microsoftScopes) == 0 {
+		microsoftScopes = []string{"openid", "email", "profile", "User.Read"}
+	}
+	config.OAuth2Microsoft = OAuth2MicrosoftConfig{
+		ClientID:     getEnv("MICROSOFT_CLIENT_ID", ""),
+		ClientSecret: getEnv("MICROSOFT_CLIENT_SECRET", ""),
+		RedirectURL:  getEnv("MICROSOFT_REDIRECT_URL", ""),
+		Tenant:       getEnv("MICROSOFT_TENANT", "common"),
+		Scopes:       microsoftScopes,
+	}
+
 	// Storage Configuration
 	storageType := getEnv("STORAGE_TYPE", "")
 	basePath := getEnv("BASE_PATH", "")
 	baseURL := getEnv("BASE_URL", "")
+	urlExpiry, err := strconv.Atoi(getEnv("FILE_URL_EXPIRY_MINUTES", "15"))
+	if err != nil || urlExpiry <= 0 {
+		return nil, fmt.Errorf("invalid FILE_URL_EXPIRY_MINUTES: must be a positive number of minutes")
+	}
+	payslipURLExpiry, err := strconv.Atoi(getEnv("PAYSLIP_URL_EXPIRY_MINUTES", "1440"))
+	if err != nil || payslipURLExpiry <= 0 {
+		return nil, fmt.Errorf("invalid PAYSLIP_URL_EXPIRY_MINUTES: must be a positive number of minutes")
+	}
 	config.Storage = StorageConfig{
-		Type:     storageType,
-		BasePath: basePath,
-		BaseURL:  baseURL,
+		Type:                    storageType,
+		BasePath:                basePath,
+		BaseURL:                 baseURL,
+		URLExpiryMinutes:        urlExpiry,
+		PayslipURLExpiryMinutes: payslipURLExpiry,
 	}
 
 	// SMTP Configuration
@@ -198,6 +328,22 @@ func Load() (*Config, error) {
 
 	// Xendit Configuration
 	xenditInvoiceExpiry, _ := strconv.Atoi(getEnv("XENDIT_INVOICE_EXPIRY_HOURS", "24"))
+	xenditMaxRetries, err := strconv.Atoi(getEnv("XENDIT_MAX_RETRIES", "3"))
+	if err != nil || xenditMaxRetries < 0 {
+		return nil, fmt.Errorf("invalid XENDIT_MAX_RETRIES: must be a non-negative number")
+	}
+	xenditRetryBaseDelay, err := strconv.Atoi(getEnv("XENDIT_RETRY_BASE_DELAY_MS", "200"))
+	if err != nil || xenditRetryBaseDelay <= 0 {
+		return nil, fmt.Errorf("invalid XENDIT_RETRY_BASE_DELAY_MS: must be a positive number of milliseconds")
+	}
+	xenditRetryMaxDelay, err := strconv.Atoi(getEnv("XENDIT_RETRY_MAX_DELAY_MS", "2000"))
+	if err != nil || xenditRetryMaxDelay <= 0 {
+		return nil, fmt.Errorf("invalid XENDIT_RETRY_MAX_DELAY_MS: must be a positive number of milliseconds")
+	}
+	xenditRequestTimeout, err := strconv.Atoi(getEnv("XENDIT_REQUEST_TIMEOUT_SECONDS", "15"))
+	if err != nil || xenditRequestTimeout <= 0 {
+		return nil, fmt.Errorf("invalid XENDIT_REQUEST_TIMEOUT_SECONDS: must be a positive number of seconds")
+	}
 	config.Xendit = XenditConfig{
 		APIKey:          getEnv("XENDIT_API_KEY", ""),
 		WebhookToken:    getEnv("XENDIT_WEBHOOK_TOKEN", ""),
@@ -206,6 +352,55 @@ func Load() (*Config, error) {
 		InvoiceExpiry:   xenditInvoiceExpiry,
 		SuccessRedirect: getEnv("XENDIT_SUCCESS_REDIRECT", "http://localhost:3000/subscription/success"),
 		FailureRedirect: getEnv("XENDIT_FAILURE_REDIRECT", "http://localhost:3000/subscription/failed"),
+		MaxRetries:      &xenditMaxRetries,
+		RetryBaseDelay:  xenditRetryBaseDelay,
+		RetryMaxDelay:   xenditRetryMaxDelay,
+		RequestTimeout:  xenditRequestTimeout,
+	}
+
+	// Subscription Configuration
+	trialDurationDays, err := strconv.Atoi(getEnv("TRIAL_DURATION_DAYS", "14"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRIAL_DURATION_DAYS: %w", err)
+	}
+	trialMaxSeats, err := strconv.Atoi(getEnv("TRIAL_MAX_SEATS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRIAL_MAX_SEATS: %w", err)
+	}
+	trialSources, err := parseTrialSources(getEnvSlice("TRIAL_SOURCE_OVERRIDES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRIAL_SOURCE_OVERRIDES: %w", err)
+	}
+	seatReconcileAutoSchedule, err := strconv.ParseBool(getEnv("SEAT_RECONCILE_AUTO_SCHEDULE", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SEAT_RECONCILE_AUTO_SCHEDULE: %w", err)
+	}
+	config.Subscription = SubscriptionConfig{
+		TrialReminderDays: getEnvIntSlice("TRIAL_REMINDER_DAYS", []int{3, 1}),
+		Trial: TrialTerms{
+			DurationDays: trialDurationDays,
+			MaxSeats:     trialMaxSeats,
+		},
+		TrialSources:              trialSources,
+		SeatReconcileAutoSchedule: seatReconcileAutoSchedule,
+	}
+
+	// Notification configuration
+	leaveRequestWindow, err := strconv.Atoi(getEnv("LEAVE_NOTIFICATION_WINDOW_MINUTES", "10"))
+	if err != nil || leaveRequestWindow < 0 {
+		return nil, fmt.Errorf("invalid LEAVE_NOTIFICATION_WINDOW_MINUTES: must be a non-negative number of minutes")
+	}
+	config.Notification = NotificationConfig{
+		LeaveRequestWindowMinutes: leaveRequestWindow,
+	}
+
+	// Leave configuration
+	reopenWindowDays, err := strconv.Atoi(getEnv("LEAVE_REOPEN_WINDOW_DAYS", "7"))
+	if err != nil || reopenWindowDays < 0 {
+		return nil, fmt.Errorf("invalid LEAVE_REOPEN_WINDOW_DAYS: must be a non-negative number of days")
+	}
+	config.Leave = LeaveConfig{
+		ReopenWindowDays: reopenWindowDays,
 	}
 
 	// Session configuration
@@ -249,6 +444,14 @@ func (c *Config) Validate() error {
 	if len(c.OAuth2Google.Scopes) == 0 {
 		return fmt.Errorf("SCOPES is required")
 	}
+	if c.OAuth2Microsoft.ClientID != "" {
+		if c.OAuth2Microsoft.ClientSecret == "" {
+			return fmt.Errorf("MICROSOFT_CLIENT_SECRET is required when MICROSOFT_CLIENT_ID is set")
+		}
+		if c.OAuth2Microsoft.RedirectURL == "" {
+			return fmt.Errorf("MICROSOFT_REDIRECT_URL is required when MICROSOFT_CLIENT_ID is set")
+		}
+	}
 	if c.Storage.Type == "" {
 		return fmt.Errorf("STORAGE_TYPE is required")
 	}
@@ -258,6 +461,14 @@ func (c *Config) Validate() error {
 	if c.Storage.BaseURL == "" {
 		return fmt.Errorf("BASE_URL is required")
 	}
+	if err := c.Subscription.Trial.validate(); err != nil {
+		return fmt.Errorf("TRIAL_DURATION_DAYS/TRIAL_MAX_SEATS: %w", err)
+	}
+	for source, terms := range c.Subscription.TrialSources {
+		if err := terms.validate(); err != nil {
+			return fmt.Errorf("TRIAL_SOURCE_OVERRIDES %q: %w", source, err)
+		}
+	}
 	// if c.Session.Secret == "" {
 	// 	return fmt.Errorf("SESSION_SECRET is required")
 	// }
@@ -283,6 +494,57 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// validate checks the trial terms are within the allowed bounds
+func (t TrialTerms) validate() error {
+	if t.DurationDays < 1 || t.DurationDays > MaxTrialDurationDays {
+		return fmt.Errorf("trial duration must be between 1 and %d days, got %d", MaxTrialDurationDays, t.DurationDays)
+	}
+	if t.MaxSeats < 1 || t.MaxSeats > MaxTrialSeats {
+		return fmt.Errorf("trial seats must be between 1 and %d, got %d", MaxTrialSeats, t.MaxSeats)
+	}
+	return nil
+}
+
+// parseTrialSources parses comma-separated "source:days:seats" entries, e.g. "referral:30:5,partner:21:10"
+func parseTrialSources(entries []string) (map[string]TrialTerms, error) {
+	sources := make(map[string]TrialTerms, len(entries))
+	for _, entry := range entries {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) != 3 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("entry %q must be source:days:seats", entry)
+		}
+		days, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("entry %q has invalid days: %w", entry, err)
+		}
+		seats, err := strconv.Atoi(strings.TrimSpace(parts[2]))
+		if err != nil {
+			return nil, fmt.Errorf("entry %q has invalid seats: %w", entry, err)
+		}
+		sources[strings.ToLower(strings.TrimSpace(parts[0]))] = TrialTerms{DurationDays: days, MaxSeats: seats}
+	}
+	return sources, nil
+}
+
+// getEnvIntSlice parses a comma-separated list of positive integers, ignoring invalid entries
+func getEnvIntSlice(env string, fallback []int) []int {
+	values := getEnvSlice(env)
+	if len(values) == 0 {
+		return fallback
+	}
+	var result []int
+	for _, v := range values {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err == nil && n > 0 {
+			result = append(result, n)
+		}
+	}
+	if len(result) == 0 {
+		return fallback
+	}
+	return result
+}
+
 func getEnvSlice(env string) []string {
 	value := getEnv(env, "")
 	if value == "" {
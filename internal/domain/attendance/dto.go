@@ -2,7 +2,9 @@ package attendance
 
 import (
 	"mime/multipart"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
 )
@@ -15,6 +17,7 @@ type ClockInRequest struct {
 	EmployeeID    string                `json:"employee_id"`
 	Latitude      float64               `json:"latitude"`
 	Longitude     float64               `json:"longitude"`
+	OverrideLeave bool                  `json:"override_leave"` // requires attendance.approve
 	ProofPhotoURL *string               `json:"-"`
 	File          multipart.File        `json:"-"`
 	FileHeader    *multipart.FileHeader `json:"-"`
@@ -44,14 +47,12 @@ func (r *ClockInRequest) Validate() error {
 		})
 	}
 
-	filename := r.FileHeader.Filename
-	ext := strings.ToLower(filename[strings.LastIndex(filename, "."):])
 	if r.FileHeader == nil {
 		errs = append(errs, validator.ValidationError{
 			Field:   "file",
 			Message: "attendance proof photo is required",
 		})
-	} else if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+	} else if ext := strings.ToLower(filepath.Ext(r.FileHeader.Filename)); ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
 		// Validate image format
 		errs = append(errs, validator.ValidationError{
 			Field:   "file",
@@ -104,14 +105,12 @@ func (r *ClockOutRequest) Validate() error {
 		})
 	}
 
-	filename := r.FileHeader.Filename
-	ext := strings.ToLower(filename[strings.LastIndex(filename, "."):])
 	if r.FileHeader == nil {
 		errs = append(errs, validator.ValidationError{
 			Field:   "file",
 			Message: "attendance proof photo is required",
 		})
-	} else if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+	} else if ext := strings.ToLower(filepath.Ext(r.FileHeader.Filename)); ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
 		// Validate image format
 		errs = append(errs, validator.ValidationError{
 			Field:   "file",
@@ -132,27 +131,33 @@ func (r *ClockOutRequest) Validate() error {
 }
 
 type AttendanceResponse struct {
-	ID                string   `json:"id"`
-	EmployeeID        string   `json:"employee_id"`
-	EmployeeName      string   `json:"employee_name"`
-	EmployeePosition  *string  `json:"employee_position,omitempty"`
-	Date              string   `json:"date"`
-	ClockInTime       *string  `json:"clock_in_time,omitempty"`
-	ClockOutTime      *string  `json:"clock_out_time,omitempty"`
-	ClockInLatitude   *float64 `json:"clock_in_latitude,omitempty"`
-	ClockInLongitude  *float64 `json:"clock_in_longitude,omitempty"`
-	ClockOutLatitude  *float64 `json:"clock_out_latitude,omitempty"`
-	ClockOutLongitude *float64 `json:"clock_out_longitude,omitempty"`
-	ClockInProofURL   *string  `json:"clock_in_proof_url,omitempty"`
-	ClockOutProofURL  *string  `json:"clock_out_proof_url,omitempty"`
-	WorkingHours      *float64 `json:"working_hours,omitempty"`
-	Status            string   `json:"status"`
-	IsLate            *bool    `json:"is_late,omitempty"`
-	IsEarlyLeave      *bool    `json:"is_early_leave,omitempty"`
-	LateMinutes       *int     `json:"late_minutes,omitempty"`
-	EarlyLeaveMinutes *int     `json:"early_leave_minutes,omitempty"`
-	CreatedAt         string   `json:"created_at"`
-	UpdatedAt         string   `json:"updated_at"`
+	ID                     string   `json:"id"`
+	EmployeeID             string   `json:"employee_id"`
+	EmployeeName           string   `json:"employee_name"`
+	EmployeePosition       *string  `json:"employee_position,omitempty"`
+	Date                   string   `json:"date"`
+	ClockInTime            *string  `json:"clock_in_time,omitempty"`
+	ClockOutTime           *string  `json:"clock_out_time,omitempty"`
+	WorkScheduleLocationID *string  `json:"work_schedule_location_id,omitempty"`
+	ClockInLatitude        *float64 `json:"clock_in_latitude,omitempty"`
+	ClockInLongitude       *float64 `json:"clock_in_longitude,omitempty"`
+	ClockOutLatitude       *float64 `json:"clock_out_latitude,omitempty"`
+	ClockOutLongitude      *float64 `json:"clock_out_longitude,omitempty"`
+	ClockInProofURL        *string  `json:"clock_in_proof_url,omitempty"`
+	ClockOutProofURL       *string  `json:"clock_out_proof_url,omitempty"`
+	WorkingHours           *float64 `json:"working_hours,omitempty"`
+	Status                 string   `json:"status"`
+	IsLate                 *bool    `json:"is_late,omitempty"`
+	IsEarlyLeave           *bool    `json:"is_early_leave,omitempty"`
+	LateMinutes            *int     `json:"late_minutes,omitempty"`
+	RawLateMinutes         *int     `json:"raw_late_minutes,omitempty"`
+	EarlyLeaveMinutes      *int     `json:"early_leave_minutes,omitempty"`
+	OvertimeMinutes        *int     `json:"overtime_minutes,omitempty"`
+	RawOvertimeMinutes     *int     `json:"raw_overtime_minutes,omitempty"`
+	OvertimeMultiplier     *float64 `json:"overtime_multiplier,omitempty"`
+	AutoClosed             bool     `json:"auto_closed"`
+	CreatedAt              string   `json:"created_at"`
+	UpdatedAt              string   `json:"updated_at"`
 }
 
 type AttendanceFilter struct {
@@ -164,6 +169,9 @@ type AttendanceFilter struct {
 	EndDate      *string `json:"end_date,omitempty"`   // YYYY-MM-DD
 	Status       *string `json:"status,omitempty"`
 
+	// BranchID restricts results to one branch; set from the caller's scope, never from input
+	BranchID *string `json:"-"`
+
 	// Pagination
 	Page  int `json:"page"`
 	Limit int `json:"limit"`
@@ -530,3 +538,163 @@ type ActiveScheduleInfo struct {
 	LocationType       string `json:"location_type"`
 	GracePeriodMinutes int    `json:"grace_period_minutes"`
 }
+
+// ========================================
+// SETTINGS DTOs
+// ========================================
+
+type AttendanceSettingsResponse struct {
+	ID                      string  `json:"id,omitempty"`
+	CompanyID               string  `json:"company_id"`
+	LateRoundingMode        string  `json:"late_rounding_mode"`
+	LateRoundingMinutes     int     `json:"late_rounding_minutes"`
+	OvertimeRoundingMode    string  `json:"overtime_rounding_mode"`
+	OvertimeRoundingMinutes int     `json:"overtime_rounding_minutes"`
+	AutoCloseMaxWorkMinutes int     `json:"auto_close_max_work_minutes"`
+	OvertimeMinMinutes      int     `json:"overtime_min_minutes"`
+	OvertimeDailyCapMinutes int     `json:"overtime_daily_cap_minutes"`
+	WeekendOvertimeRate     float64 `json:"weekend_overtime_rate"`
+	HolidayOvertimeRate     float64 `json:"holiday_overtime_rate"`
+}
+
+type UpdateAttendanceSettingsRequest struct {
+	LateRoundingMode        *string  `json:"late_rounding_mode,omitempty"`
+	LateRoundingMinutes     *int     `json:"late_rounding_minutes,omitempty"`
+	OvertimeRoundingMode    *string  `json:"overtime_rounding_mode,omitempty"`
+	OvertimeRoundingMinutes *int     `json:"overtime_rounding_minutes,omitempty"`
+	AutoCloseMaxWorkMinutes *int     `json:"auto_close_max_work_minutes,omitempty"`
+	OvertimeMinMinutes      *int     `json:"overtime_min_minutes,omitempty"`
+	OvertimeDailyCapMinutes *int     `json:"overtime_daily_cap_minutes,omitempty"`
+	WeekendOvertimeRate     *float64 `json:"weekend_overtime_rate,omitempty"`
+	HolidayOvertimeRate     *float64 `json:"holiday_overtime_rate,omitempty"`
+}
+
+func (r *UpdateAttendanceSettingsRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if r.LateRoundingMode != nil && !RoundingMode(*r.LateRoundingMode).IsValid() {
+		errs = append(errs, validator.ValidationError{Field: "late_rounding_mode", Message: "must be one of: none, up, down, nearest"})
+	}
+	if r.LateRoundingMinutes != nil && (*r.LateRoundingMinutes < 0 || *r.LateRoundingMinutes > 60) {
+		errs = append(errs, validator.ValidationError{Field: "late_rounding_minutes", Message: "must be between 0 and 60"})
+	}
+	if r.OvertimeRoundingMode != nil && !RoundingMode(*r.OvertimeRoundingMode).IsValid() {
+		errs = append(errs, validator.ValidationError{Field: "overtime_rounding_mode", Message: "must be one of: none, up, down, nearest"})
+	}
+	if r.OvertimeRoundingMinutes != nil && (*r.OvertimeRoundingMinutes < 0 || *r.OvertimeRoundingMinutes > 60) {
+		errs = append(errs, validator.ValidationError{Field: "overtime_rounding_minutes", Message: "must be between 0 and 60"})
+	}
+	if r.AutoCloseMaxWorkMinutes != nil && (*r.AutoCloseMaxWorkMinutes < 60 || *r.AutoCloseMaxWorkMinutes > 1440) {
+		errs = append(errs, validator.ValidationError{Field: "auto_close_max_work_minutes", Message: "must be between 60 and 1440"})
+	}
+	if r.OvertimeMinMinutes != nil && (*r.OvertimeMinMinutes < 0 || *r.OvertimeMinMinutes > 240) {
+		errs = append(errs, validator.ValidationError{Field: "overtime_min_minutes", Message: "must be between 0 and 240"})
+	}
+	if r.OvertimeDailyCapMinutes != nil && (*r.OvertimeDailyCapMinutes < 0 || *r.OvertimeDailyCapMinutes > 1440) {
+		errs = append(errs, validator.ValidationError{Field: "overtime_daily_cap_minutes", Message: "must be between 0 and 1440"})
+	}
+	if r.WeekendOvertimeRate != nil && (*r.WeekendOvertimeRate < 1 || *r.WeekendOvertimeRate > 10) {
+		errs = append(errs, validator.ValidationError{Field: "weekend_overtime_rate", Message: "must be between 1 and 10"})
+	}
+	if r.HolidayOvertimeRate != nil && (*r.HolidayOvertimeRate < 1 || *r.HolidayOvertimeRate > 10) {
+		errs = append(errs, validator.ValidationError{Field: "holiday_overtime_rate", Message: "must be between 1 and 10"})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Live board statuses
+const (
+	LiveBoardNotClockedIn = "not_clocked_in"
+	LiveBoardClockedIn    = "clocked_in"
+	LiveBoardOnLeave      = "on_leave"
+	LiveBoardAbsent       = "absent"
+)
+
+type LiveBoardFilter struct {
+	BranchID *string `json:"branch_id,omitempty"`
+}
+
+func (f *LiveBoardFilter) Validate() error {
+	var errs validator.ValidationErrors
+
+	if f.BranchID != nil && !validator.IsValidUUID(*f.BranchID) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "branch_id",
+			Message: "branch_id must be a valid UUID",
+		})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+type LiveBoardEmployee struct {
+	EmployeeID        string  `json:"employee_id"`
+	EmployeeName      string  `json:"employee_name"`
+	EmployeeCode      string  `json:"employee_code"`
+	PositionName      *string `json:"position_name,omitempty"`
+	BranchID          *string `json:"branch_id,omitempty"`
+	BranchName        *string `json:"branch_name,omitempty"`
+	Date              string  `json:"date"`
+	ScheduleName      string  `json:"schedule_name"`
+	ScheduledClockIn  string  `json:"scheduled_clock_in"`
+	ScheduledClockOut string  `json:"scheduled_clock_out"`
+	Status            string  `json:"status"` // not_clocked_in, clocked_in, on_leave, absent
+	AttendanceID      *string `json:"attendance_id,omitempty"`
+	ClockInAt         *string `json:"clock_in_at,omitempty"`  // HH:MM local
+	ClockOutAt        *string `json:"clock_out_at,omitempty"` // HH:MM local
+	LeaveTypeName     *string `json:"leave_type_name,omitempty"`
+}
+
+type LiveBoardSummary struct {
+	Total        int `json:"total"`
+	NotClockedIn int `json:"not_clocked_in"`
+	ClockedIn    int `json:"clocked_in"`
+	OnLeave      int `json:"on_leave"`
+	Absent       int `json:"absent"`
+}
+
+type LiveBoardResponse struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	Summary     LiveBoardSummary    `json:"summary"`
+	Employees   []LiveBoardEmployee `json:"employees"`
+}
+
+// ========================================
+// IMPORT DTOs
+// ========================================
+
+// MaxImportRows caps how many data rows a single attendance import may contain
+const MaxImportRows = 5000
+
+// Import row outcomes
+const (
+	ImportRowCreated = "created"
+	ImportRowSkipped = "skipped" // a record already exists for the employee and date
+	ImportRowFailed  = "failed"
+)
+
+// ImportRowResult reports what happened to one data row of an import file
+type ImportRowResult struct {
+	Row          int     `json:"row"` // 1-based line number in the file, header is row 1
+	EmployeeCode string  `json:"employee_code"`
+	Date         string  `json:"date"`
+	Result       string  `json:"result"` // created, skipped, failed
+	AttendanceID *string `json:"attendance_id,omitempty"`
+	Message      string  `json:"message,omitempty"`
+}
+
+// BulkImportResponse summarizes an attendance import
+type BulkImportResponse struct {
+	TotalRows int               `json:"total_rows"`
+	Created   int               `json:"created"`
+	Skipped   int               `json:"skipped"`
+	Failed    int               `json:"failed"`
+	Rows      []ImportRowResult `json:"rows"`
+}
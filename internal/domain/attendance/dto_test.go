@@ -0,0 +1,41 @@
+package attendance
+
+import (
+	"errors"
+	"mime/multipart"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
+)
+
+func TestClockInRequestProofPhotoValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		fileHeader *multipart.FileHeader
+		want       string
+	}{
+		{"missing photo", nil, "attendance proof photo is required"},
+		{"no extension", &multipart.FileHeader{Filename: "proof", Size: 1 << 10}, "invalid file type: only jpg, jpeg, png allowed"},
+		{"wrong type", &multipart.FileHeader{Filename: "proof.gif", Size: 1 << 10}, "invalid file type: only jpg, jpeg, png allowed"},
+		{"too large", &multipart.FileHeader{Filename: "proof.JPG", Size: 10<<20 + 1}, "attendance proof photo size must not exceed 10MB"},
+		{"valid", &multipart.FileHeader{Filename: "proof.jpeg", Size: 10 << 20}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clockIn := ClockInRequest{EmployeeID: "emp-1", FileHeader: tt.fileHeader}
+			clockOut := ClockOutRequest{EmployeeID: "emp-1", FileHeader: tt.fileHeader}
+
+			for _, err := range []error{clockIn.Validate(), clockOut.Validate()} {
+				var errs validator.ValidationErrors
+				switch {
+				case tt.want == "" && err != nil:
+					t.Errorf("Validate() error = %v, want nil", err)
+				case tt.want == "":
+				case !errors.As(err, &errs) || errs.ToMap()["file"] != tt.want:
+					t.Errorf("Validate() error = %v, want file: %s", err, tt.want)
+				}
+			}
+		})
+	}
+}
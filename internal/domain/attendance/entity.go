@@ -2,36 +2,169 @@ package attendance
 
 import (
 	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
 )
 
 type Attendance struct {
-	ID                 string
-	EmployeeID         string
-	Date               time.Time
-	WorkScheduleTimeID *string
-	ActualLocationType *string
-	ClockIn            *time.Time
-	ClockOut           *time.Time
-	WorkHoursInMinutes *int
-	ClockInLatitude    *float64
-	ClockInLongitude   *float64
-	ClockInProofURL    *string
-	ClockOutLatitude   *float64
-	ClockOutLongitude  *float64
-	ClockOutProofURL   *string
-	Status             string
-	CompanyID          string
-	ApprovedBy         *string
-	ApprovedAt         *time.Time
-	RejectionReason    *string
-	LeaveTypeID        *string
-	LateMinutes        *int
-	EarlyLeaveMinutes  *int
-	OvertimeMinutes    *int
-	CreatedAt          time.Time
-	UpdatedAt          time.Time
+	ID                     string
+	EmployeeID             string
+	Date                   time.Time
+	WorkScheduleTimeID     *string
+	ActualLocationType     *string
+	WorkScheduleLocationID *string // Schedule location clocked in at; nil when none was within radius
+	ClockIn                *time.Time
+	ClockOut               *time.Time
+	WorkHoursInMinutes     *int
+	ClockInLatitude        *float64
+	ClockInLongitude       *float64
+	ClockInProofURL        *string
+	ClockOutLatitude       *float64
+	ClockOutLongitude      *float64
+	ClockOutProofURL       *string
+	Status                 string
+	CompanyID              string
+	ApprovedBy             *string
+	ApprovedAt             *time.Time
+	RejectionReason        *string
+	LeaveTypeID            *string
+	LateMinutes            *int
+	EarlyLeaveMinutes      *int
+	OvertimeMinutes        *int
+	RawLateMinutes         *int // Before company rounding rules were applied
+	RawOvertimeMinutes     *int
+	OvertimeMultiplier     *float64 // Pay multiplier applied to overtime_minutes (weekend/holiday premium)
+	AutoClosed             bool     // Clock-out was estimated because the employee never clocked out
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
 
 	// DTO
 	EmployeeName     *string
 	EmployeePosition *string
 }
+
+// LiveBoardEntry is one scheduled employee's attendance state for their current local day
+type LiveBoardEntry struct {
+	EmployeeID        string
+	EmployeeName      string
+	EmployeeCode      string
+	PositionName      *string
+	BranchID          *string
+	BranchName        *string
+	Date              time.Time // Today in the employee's branch (or company) timezone
+	ScheduleName      string
+	ScheduledClockIn  string // HH:MM
+	ScheduledClockOut string // HH:MM
+	AttendanceID      *string
+	AttendanceStatus  *string
+	ClockIn           *time.Time
+	ClockOut          *time.Time
+	ClockInLocal      *string // HH:MM in the employee's timezone
+	ClockOutLocal     *string
+	LeaveTypeName     *string // Set when an approved leave covers today
+}
+
+// RoundingMode controls how late/overtime minutes are rounded to a step
+type RoundingMode string
+
+const (
+	RoundingModeNone    RoundingMode = "none"
+	RoundingModeUp      RoundingMode = "up"
+	RoundingModeDown    RoundingMode = "down"
+	RoundingModeNearest RoundingMode = "nearest"
+)
+
+// IsValid checks if the rounding mode is supported
+func (m RoundingMode) IsValid() bool {
+	switch m {
+	case RoundingModeNone, RoundingModeUp, RoundingModeDown, RoundingModeNearest:
+		return true
+	}
+	return false
+}
+
+// Round rounds minutes to a multiple of step. A step of 0 or mode none leaves minutes unchanged.
+func (m RoundingMode) Round(minutes, step int) int {
+	if step <= 0 || minutes <= 0 {
+		return minutes
+	}
+
+	switch m {
+	case RoundingModeUp:
+		return (minutes + step - 1) / step * step
+	case RoundingModeDown:
+		return minutes / step * step
+	case RoundingModeNearest:
+		return (minutes + step/2) / step * step
+	default:
+		return minutes
+	}
+}
+
+// AttendanceSettings - Company attendance configuration
+type AttendanceSettings struct {
+	ID                      string
+	CompanyID               string
+	LateRoundingMode        RoundingMode
+	LateRoundingMinutes     int
+	OvertimeRoundingMode    RoundingMode
+	OvertimeRoundingMinutes int
+	AutoCloseMaxWorkMinutes int     // Cap on worked minutes for attendances closed by the auto-close job
+	OvertimeMinMinutes      int     // Overstays shorter than this don't count as overtime
+	OvertimeDailyCapMinutes int     // 0 means no cap
+	WeekendOvertimeRate     float64 // Multiplier for overtime on the company's non-working weekdays
+	HolidayOvertimeRate     float64 // Multiplier for overtime on company holidays
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+}
+
+// DefaultAutoCloseMaxWorkMinutes caps auto-closed attendances at 12 hours unless a company configures otherwise
+const DefaultAutoCloseMaxWorkMinutes = 720
+
+// DefaultAttendanceSettings returns settings without rounding, used until a company saves its own
+func DefaultAttendanceSettings(companyID string) AttendanceSettings {
+	return AttendanceSettings{
+		CompanyID:               companyID,
+		LateRoundingMode:        RoundingModeNone,
+		OvertimeRoundingMode:    RoundingModeNone,
+		AutoCloseMaxWorkMinutes: DefaultAutoCloseMaxWorkMinutes,
+		WeekendOvertimeRate:     1,
+		HolidayOvertimeRate:     1,
+	}
+}
+
+// RoundLate applies the late-in rounding rule
+func (s AttendanceSettings) RoundLate(minutes int) int {
+	return s.LateRoundingMode.Round(minutes, s.LateRoundingMinutes)
+}
+
+// RoundOvertime applies the overtime rounding rule
+func (s AttendanceSettings) RoundOvertime(minutes int) int {
+	return s.OvertimeRoundingMode.Round(minutes, s.OvertimeRoundingMinutes)
+}
+
+// ApplyOvertimeRules turns raw overstay minutes into payable overtime: overstays under the
+// minimum are dropped, the rest is rounded and then capped per day
+func (s AttendanceSettings) ApplyOvertimeRules(rawMinutes int) int {
+	if rawMinutes <= 0 || rawMinutes < s.OvertimeMinMinutes {
+		return 0
+	}
+	minutes := s.RoundOvertime(rawMinutes)
+	if s.OvertimeDailyCapMinutes > 0 && minutes > s.OvertimeDailyCapMinutes {
+		minutes = s.OvertimeDailyCapMinutes
+	}
+	return minutes
+}
+
+// OvertimeMultiplier returns the pay multiplier for overtime worked on date, taking holidays and
+// working weekdays from the company calendar. Holiday takes precedence over weekend; any other
+// day is paid at 1.
+func (s AttendanceSettings) OvertimeMultiplier(date time.Time, calendar company.Calendar) float64 {
+	if calendar.IsHoliday(date) {
+		return s.HolidayOvertimeRate
+	}
+	if !calendar.IsWorkingWeekday(date) {
+		return s.WeekendOvertimeRate
+	}
+	return 1
+}
@@ -0,0 +1,37 @@
+package attendance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+)
+
+func TestOvertimeMultiplier(t *testing.T) {
+	settings := AttendanceSettings{WeekendOvertimeRate: 1.5, HolidayOvertimeRate: 2}
+	calendar := company.NewCalendar(2026, []company.Holiday{
+		{Date: time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC), Name: "Independence Day"}, // Monday
+		{Date: time.Date(2026, 12, 26, 0, 0, 0, 0, time.UTC), Name: "Boxing Day"},      // Saturday
+	})
+
+	tests := []struct {
+		name string
+		date time.Time
+		want float64
+	}{
+		{name: "working day", date: time.Date(2026, 8, 18, 0, 0, 0, 0, time.UTC), want: 1},
+		{name: "saturday", date: time.Date(2026, 8, 15, 0, 0, 0, 0, time.UTC), want: 1.5},
+		{name: "sunday", date: time.Date(2026, 8, 16, 0, 0, 0, 0, time.UTC), want: 1.5},
+		{name: "holiday on a weekday", date: time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC), want: 2},
+		{name: "holiday on a weekend", date: time.Date(2026, 12, 26, 0, 0, 0, 0, time.UTC), want: 2},
+		{name: "holiday date in the employee's timezone", date: time.Date(2026, 8, 17, 0, 0, 0, 0, time.FixedZone("WIB", 7*3600)), want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settings.OvertimeMultiplier(tt.date, calendar); got != tt.want {
+				t.Errorf("OvertimeMultiplier(%s) = %v, want %v", tt.date.Format("Mon 2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
@@ -11,9 +11,17 @@ var (
 	ErrTooEarlyToCheckIn    = errors.New("too early to check in")
 	ErrNotCheckedIn         = errors.New("you have not checked in yet")
 	ErrAlreadyCheckedOut    = errors.New("you have already checked out")
+	ErrOnLeave              = errors.New("you are on approved leave today")
 
 	// General errors
 	ErrAttendanceNotFound         = errors.New("attendance record not found")
 	ErrUnauthorized               = errors.New("unauthorized to access this attendance record")
 	ErrAttendanceAlreadyProcessed = errors.New("attendance has already been approved or rejected")
+
+	// Import errors
+	ErrInvalidImportFile = errors.New("import file must be a CSV with employee_code and date columns")
+	ErrImportTooLarge    = errors.New("import file has too many rows")
+
+	// Settings errors
+	ErrAttendanceSettingsNotFound = errors.New("attendance settings not found")
 )
@@ -28,14 +28,33 @@ type AttendanceRepository interface {
 	GetMyAttendance(ctx context.Context, employeeID string, filter MyAttendanceFilter, companyID string) ([]Attendance, int64, error)
 
 	HasCheckedInToday(ctx context.Context, employeeID string, dateLocal string, companyID string) (bool, error)
+
+	// IsOnApprovedLeave reports whether the employee has a leave attendance record or an
+	// approved leave request covering the date
+	IsOnApprovedLeave(ctx context.Context, employeeID string, dateLocal string, companyID string) (bool, error)
 	GetOpenSession(ctx context.Context, employeeID string) (Attendance, error)
 
-	// GetStaleOpenSessions returns unclosed attendances past grace period
-	GetStaleOpenSessions(ctx context.Context, gracePeriodHours int) ([]Attendance, error)
+	// GetStaleOpenSessions returns attendances dated before today in the employee's timezone
+	// that have a clock-in but no clock-out and whose scheduled end is at or before now
+	GetStaleOpenSessions(ctx context.Context, now time.Time) ([]Attendance, error)
+
+	// AutoClose sets an estimated clock-out on an open attendance, zeroes its overtime and flags it auto_closed
+	AutoClose(ctx context.Context, id string, companyID string, clockOut time.Time, workMinutes int) error
 
 	// BulkCreateAbsences creates multiple absence records efficiently
 	BulkCreateAbsences(ctx context.Context, attendances []Attendance) error
 
 	// Delete soft deletes an attendance record
 	Delete(ctx context.Context, id string, companyID string) error
+
+	// GetLiveBoardEntries returns every active employee scheduled to work on their current local day,
+	// joined with that day's attendance and approved leave, optionally limited to a branch
+	GetLiveBoardEntries(ctx context.Context, companyID string, branchID *string, now time.Time) ([]LiveBoardEntry, error)
+}
+
+// AttendanceSettingsRepository stores company-level attendance configuration
+type AttendanceSettingsRepository interface {
+	// GetSettings returns ErrAttendanceSettingsNotFound when the company has not saved settings yet
+	GetSettings(ctx context.Context, companyID string) (AttendanceSettings, error)
+	UpsertSettings(ctx context.Context, settings AttendanceSettings) (AttendanceSettings, error)
 }
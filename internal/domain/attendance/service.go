@@ -2,6 +2,8 @@ package attendance
 
 import (
 	"context"
+	"io"
+	"time"
 )
 
 // AttendanceService defines business logic for attendance operations
@@ -35,4 +37,22 @@ type AttendanceService interface {
 
 	// DeleteAttendance soft deletes an attendance record
 	DeleteAttendance(ctx context.Context, id string) error
+
+	// BulkImport creates historical attendance records from a CSV file of
+	// employee_code, date, clock_in, clock_out, status rows, reporting the outcome of every row
+	BulkImport(ctx context.Context, companyID string, file io.Reader) (BulkImportResponse, error)
+
+	// GetSettings retrieves the company's attendance settings, or defaults if none are saved
+	GetSettings(ctx context.Context) (AttendanceSettingsResponse, error)
+
+	// UpdateSettings updates the company's attendance settings
+	UpdateSettings(ctx context.Context, req UpdateAttendanceSettingsRequest) (AttendanceSettingsResponse, error)
+
+	// GetLiveBoard returns who is scheduled today and whether they have clocked in, are on leave or are absent
+	GetLiveBoard(ctx context.Context, companyID string, filter LiveBoardFilter) (LiveBoardResponse, error)
+
+	// AutoCloseOpenAttendance closes past attendances that were never clocked out and whose shift
+	// ended by now, using the scheduled end time capped by the company's auto-close limit.
+	// Returns how many were closed.
+	AutoCloseOpenAttendance(ctx context.Context, now time.Time) (int, error)
 }
@@ -0,0 +1,28 @@
+package audit
+
+import "time"
+
+// Action mirrors the audit_action enum
+type Action string
+
+const (
+	ActionCreate    Action = "CREATE"
+	ActionUpdate    Action = "UPDATE"
+	ActionDelete    Action = "DELETE"
+	ActionApprove   Action = "APPROVE"
+	ActionReject    Action = "REJECT"
+	ActionAnonymize Action = "ANONYMIZE"
+)
+
+// Entry is one row of the audit trail
+type Entry struct {
+	ID          string
+	UserID      *string // Actor; nil for system actions
+	Action      Action
+	TableName   string
+	RecordID    string
+	OldValue    map[string]interface{}
+	NewValue    map[string]interface{}
+	Description string
+	CreatedAt   time.Time
+}
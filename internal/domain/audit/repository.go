@@ -0,0 +1,8 @@
+package audit
+
+import "context"
+
+// AuditRepository appends to the audit trail
+type AuditRepository interface {
+	Create(ctx context.Context, entry Entry) error
+}
@@ -307,6 +307,50 @@ func (r *VerifyEmailRequest) Validate() error {
 	return nil
 }
 
+// OAuthIdentity is the identity asserted by an OAuth provider after a successful callback
+type OAuthIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// ConfirmOAuthLinkRequest confirms a pending OAuth link with the existing account's password
+type ConfirmOAuthLinkRequest struct {
+	LinkToken string `json:"link_token"`
+	Password  string `json:"password"`
+}
+
+func (r *ConfirmOAuthLinkRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if validator.IsEmpty(r.LinkToken) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "link_token",
+			Message: "link_token is required",
+		})
+	}
+	if len(r.LinkToken) > 255 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "link_token",
+			Message: "link_token must not exceed 255 characters",
+		})
+	}
+
+	if validator.IsEmpty(r.Password) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "password",
+			Message: "password is required",
+		})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
 type SessionTrackingRequest struct {
 	UserAgent string
 	IPAddress string
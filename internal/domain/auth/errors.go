@@ -19,9 +19,19 @@ var (
 	ErrStateCookieEmpty               = errors.New("state cookie is empty")
 	ErrCodeValueEmpty                 = errors.New("code value is empty")
 	ErrEmailAlreadyExists             = errors.New("account with this email already exists")
-	ErrGoogleAccessDeniedByUser       = errors.New("continue with google access denied by user")
+	ErrOAuthAccessDeniedByUser        = errors.New("oauth access denied by user")
+	ErrOAuthProviderNotSupported      = errors.New("oauth provider is not supported")
+	ErrOAuthEmailNotVerified          = errors.New("oauth provider did not return a verified email")
 	ErrRefreshTokenCookieEmpty        = errors.New("refresh token cookie is empty")
 
+	// OAuth account linking errors
+	ErrOAuthLinkRequired         = errors.New("oauth identity must be confirmed before it is linked to the existing account")
+	ErrOAuthAccountConflict      = errors.New("account is already linked to a different oauth identity")
+	ErrOAuthLinkEmailNotVerified = errors.New("existing account email must be verified before linking an oauth identity")
+	ErrOAuthLinkTokenNotFound    = errors.New("oauth link token not found")
+	ErrOAuthLinkTokenExpired     = errors.New("oauth link token has expired")
+	ErrOAuthLinkTokenUsed        = errors.New("oauth link token has already been used")
+
 	// Password reset errors
 	ErrPasswordResetTokenNotFound = errors.New("password reset token not found")
 	ErrPasswordResetTokenExpired  = errors.New("password reset token has expired")
@@ -32,3 +42,19 @@ var (
 	ErrEmailVerificationTokenExpired  = errors.New("email verification token has expired")
 	ErrEmailAlreadyVerified           = errors.New("email is already verified")
 )
+
+// OAuthLinkRequiredError is returned by an OAuth login that matched an existing password account.
+// The account owner confirms the link with LinkToken and their password.
+type OAuthLinkRequiredError struct {
+	Provider  string
+	Email     string
+	LinkToken string
+}
+
+func (e *OAuthLinkRequiredError) Error() string {
+	return ErrOAuthLinkRequired.Error()
+}
+
+func (e *OAuthLinkRequiredError) Unwrap() error {
+	return ErrOAuthLinkRequired
+}
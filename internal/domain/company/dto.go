@@ -5,27 +5,48 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/utils"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
 )
 
 type CompanyResponse struct {
-	ID        string     `json:"id"`
-	Name      string     `json:"company_name"`
-	Username  string     `json:"company_username"`
-	Address   *string    `json:"company_address,omitempty"`
-	LogoURL   *string    `json:"logo_url,omitempty"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ID       string  `json:"id"`
+	Name     string  `json:"company_name"`
+	Username string  `json:"company_username"`
+	Address  *string `json:"company_address,omitempty"`
+	LogoURL  *string `json:"logo_url,omitempty"`
+	Timezone string  `json:"timezone"`
+	// LeaveYearStartMonth is the month (1-12) leave quotas reset in
+	LeaveYearStartMonth int `json:"leave_year_start_month"`
+	// LeaveApprovalSLAHours is how long leave requests wait for approval before escalation; 0 is off
+	LeaveApprovalSLAHours int        `json:"leave_approval_sla_hours"`
+	LeaveEscalationAction string     `json:"leave_escalation_action"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+	DeletedAt             *time.Time `json:"deleted_at,omitempty"`
 }
 
+// Leave type templates a company can be seeded with
+const (
+	// LeaveTypeTemplateIndonesia is the full set of statutory leave types under Indonesian labor law
+	LeaveTypeTemplateIndonesia = "indonesia"
+	// LeaveTypeTemplateBasic is annual, sick and unpaid leave only
+	LeaveTypeTemplateBasic = "basic"
+)
+
+var LeaveTypeTemplates = []string{LeaveTypeTemplateIndonesia, LeaveTypeTemplateBasic}
+
 type CreateCompanyRequest struct {
-	Name          string                `json:"company_name"`
-	Username      string                `json:"company_username"`
-	Address       *string               `json:"company_address,omitempty"`
-	AttachmentURL *string               `json:"-"`
-	File          multipart.File        `json:"-"`
-	FileHeader    *multipart.FileHeader `json:"-"`
+	Name         string  `json:"company_name"`
+	Username     string  `json:"company_username"`
+	Address      *string `json:"company_address,omitempty"`
+	SignupSource *string `json:"signup_source,omitempty"` // Campaign/referral code selecting trial terms
+	// LeaveTypeTemplate picks the default leave types; indonesia when empty
+	LeaveTypeTemplate     string                `json:"leave_type_template,omitempty"`
+	SkipDefaultLeaveTypes bool                  `json:"skip_default_leave_types,omitempty"`
+	AttachmentURL         *string               `json:"-"`
+	File                  multipart.File        `json:"-"`
+	FileHeader            *multipart.FileHeader `json:"-"`
 }
 
 func (r *CreateCompanyRequest) Validate() error {
@@ -50,6 +71,44 @@ func (r *CreateCompanyRequest) Validate() error {
 			Message: "company_username is required",
 		})
 	}
+	if r.SignupSource != nil && len(*r.SignupSource) > 50 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "signup_source",
+			Message: "signup_source must not exceed 50 characters",
+		})
+	}
+	if r.LeaveTypeTemplate == "" {
+		r.LeaveTypeTemplate = LeaveTypeTemplateIndonesia
+	} else if !validator.IsInSlice(r.LeaveTypeTemplate, LeaveTypeTemplates) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "leave_type_template",
+			Message: "leave_type_template must be one of: " + strings.Join(LeaveTypeTemplates, ", "),
+		})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// SeedLeaveTypesRequest adds a template's leave types to an existing company
+type SeedLeaveTypesRequest struct {
+	Template string `json:"template"`
+}
+
+func (r *SeedLeaveTypesRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if r.Template == "" {
+		r.Template = LeaveTypeTemplateIndonesia
+	} else if !validator.IsInSlice(r.Template, LeaveTypeTemplates) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "template",
+			Message: "template must be one of: " + strings.Join(LeaveTypeTemplates, ", "),
+		})
+	}
 
 	if len(errs) > 0 {
 		return errs
@@ -58,10 +117,24 @@ func (r *CreateCompanyRequest) Validate() error {
 	return nil
 }
 
+// SeedLeaveTypesResponse lists the template's leave types by name. Types whose code or name
+// already exists in the company are skipped, so seeding twice changes nothing.
+type SeedLeaveTypesResponse struct {
+	Template string   `json:"template"`
+	Created  []string `json:"created"`
+	Skipped  []string `json:"skipped"`
+}
+
 type UpdateCompanyRequest struct {
-	Name    *string `json:"company_name,omitempty"`
-	Address *string `json:"company_address,omitempty"`
-	LogoURL *string `json:"logo_url,omitempty"`
+	Name     *string `json:"company_name,omitempty"`
+	Address  *string `json:"company_address,omitempty"`
+	LogoURL  *string `json:"logo_url,omitempty"`
+	Timezone *string `json:"timezone,omitempty"`
+	// LeaveYearStartMonth applies from the next quota allocation; existing quotas keep their year
+	LeaveYearStartMonth *int `json:"leave_year_start_month,omitempty"`
+	// LeaveApprovalSLAHours and LeaveEscalationAction apply to requests already waiting
+	LeaveApprovalSLAHours *int    `json:"leave_approval_sla_hours,omitempty"`
+	LeaveEscalationAction *string `json:"leave_escalation_action,omitempty"`
 }
 
 func (r *UpdateCompanyRequest) Validate() error {
@@ -77,6 +150,34 @@ func (r *UpdateCompanyRequest) Validate() error {
 		}
 	}
 
+	if r.Timezone != nil && !utils.IsValidTimezone(*r.Timezone) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "timezone",
+			Message: "timezone must be a valid IANA timezone (e.g. Asia/Jakarta)",
+		})
+	}
+
+	if r.LeaveYearStartMonth != nil && (*r.LeaveYearStartMonth < 1 || *r.LeaveYearStartMonth > 12) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "leave_year_start_month",
+			Message: "leave_year_start_month must be between 1 and 12",
+		})
+	}
+
+	if r.LeaveApprovalSLAHours != nil && (*r.LeaveApprovalSLAHours < 0 || *r.LeaveApprovalSLAHours > 720) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "leave_approval_sla_hours",
+			Message: "leave_approval_sla_hours must be between 0 and 720",
+		})
+	}
+
+	if r.LeaveEscalationAction != nil && !validator.IsInSlice(*r.LeaveEscalationAction, LeaveEscalationActions) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "leave_escalation_action",
+			Message: "leave_escalation_action must be one of: notify, auto_approve",
+		})
+	}
+
 	if len(errs) > 0 {
 		return errs
 	}
@@ -125,3 +226,93 @@ func (r *UploadCompanyLogoRequest) Validate() error {
 type UploadCompanyLogoResponse struct {
 	LogoURL string `json:"logo_url"`
 }
+
+// Onboarding steps, in the order the setup checklist recommends them
+const (
+	OnboardingStepBranches      = "branches"
+	OnboardingStepPositions     = "positions"
+	OnboardingStepLeaveTypes    = "leave_types"
+	OnboardingStepWorkSchedules = "work_schedules"
+	OnboardingStepEmployees     = "employees"
+)
+
+// OnboardingStep is one item of the company setup checklist
+type OnboardingStep struct {
+	Key       string `json:"key"`
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+	Count     int    `json:"count"`
+}
+
+// OnboardingStatusResponse is the company setup checklist. NextStep is the first incomplete
+// step and is omitted once every step is done.
+type OnboardingStatusResponse struct {
+	Steps          []OnboardingStep `json:"steps"`
+	CompletedSteps int              `json:"completed_steps"`
+	TotalSteps     int              `json:"total_steps"`
+	IsComplete     bool             `json:"is_complete"`
+	NextStep       *string          `json:"next_step,omitempty"`
+}
+
+// CalendarFilter picks the year of a company calendar; Year defaults to the current year
+type CalendarFilter struct {
+	Year *int `json:"year,omitempty"`
+}
+
+func (f *CalendarFilter) Validate() error {
+	var errs validator.ValidationErrors
+
+	if f.Year != nil && (*f.Year < 1900 || *f.Year > 9999) {
+		errs = append(errs, validator.ValidationError{Field: "year", Message: "year must be between 1900 and 9999"})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// CalendarWeekday is a working weekday; DayOfWeek follows ISO numbering, 1 is Monday and 7 is Sunday
+type CalendarWeekday struct {
+	DayOfWeek int    `json:"day_of_week"`
+	DayName   string `json:"day_name"`
+}
+
+type HolidayResponse struct {
+	ID          string `json:"id"`
+	Date        string `json:"date"`
+	Name        string `json:"name"`
+	IsRecurring bool   `json:"is_recurring"`
+}
+
+// CreateHolidayRequest adds a company-wide day off
+type CreateHolidayRequest struct {
+	Date        string `json:"date"` // YYYY-MM-DD
+	Name        string `json:"name"`
+	IsRecurring bool   `json:"is_recurring"` // Repeat every year from date on
+}
+
+func (r *CreateHolidayRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if _, valid := validator.IsValidDate(r.Date); !valid {
+		errs = append(errs, validator.ValidationError{Field: "date", Message: "date format is invalid (use YYYY-MM-DD)"})
+	}
+	if name := strings.TrimSpace(r.Name); name == "" || len(name) > 255 {
+		errs = append(errs, validator.ValidationError{Field: "name", Message: "name is required and must be at most 255 characters"})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// CalendarResponse is a company's holidays and working weekdays for one year.
+// TotalWorkingDays counts the working weekdays of the year that are not holidays.
+type CalendarResponse struct {
+	Year             int               `json:"year"`
+	WorkingWeekdays  []CalendarWeekday `json:"working_weekdays"`
+	Holidays         []HolidayResponse `json:"holidays"`
+	TotalWorkingDays int               `json:"total_working_days"`
+}
@@ -1,14 +1,100 @@
 package company
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type Company struct {
-	ID        string
-	Name      string
-	Username  string
-	Address   *string
-	LogoURL   *string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt time.Time
+	ID       string
+	Name     string
+	Username string
+	Address  *string
+	LogoURL  *string
+	Timezone string
+	// LeaveYearStartMonth is the month (1-12) the leave year starts in; 1 is a calendar leave year
+	LeaveYearStartMonth int
+	// LeaveApprovalSLAHours is how long a leave request may wait for approval before it is escalated; 0 disables escalation
+	LeaveApprovalSLAHours int
+	// LeaveEscalationAction is what escalation does, one of the LeaveEscalation constants
+	LeaveEscalationAction string
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+	DeletedAt             time.Time
+}
+
+// Leave escalation actions
+const (
+	// LeaveEscalationNotify tells the company owners a request is waiting past the SLA
+	LeaveEscalationNotify = "notify"
+	// LeaveEscalationAutoApprove also approves the request on the owners' behalf
+	LeaveEscalationAutoApprove = "auto_approve"
+)
+
+var LeaveEscalationActions = []string{LeaveEscalationNotify, LeaveEscalationAutoApprove}
+
+// OnboardingCounts is how much setup data a company has, used to derive its onboarding progress
+type OnboardingCounts struct {
+	Branches        int
+	Positions       int
+	LeaveTypes      int // Active leave types only
+	WorkSchedules   int
+	ActiveEmployees int // Includes the owner
+}
+
+// DefaultWorkingWeekdays are the weekdays companies work; they are not configurable per company yet
+var DefaultWorkingWeekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// Holiday is a company-wide day off
+type Holiday struct {
+	ID          string
+	CompanyID   string
+	Date        time.Time // For a recurring holiday, the first year it applies
+	Name        string
+	IsRecurring bool // Repeats on the same month and day every year from Date on
+	CreatedAt   time.Time
+}
+
+// Calendar is a company's working-day definition for one year. It is the single source for
+// working-day math, so leave and attendance should ask it rather than check weekdays themselves.
+type Calendar struct {
+	Year            int
+	WorkingWeekdays []time.Weekday
+	Holidays        []Holiday
+}
+
+// NewCalendar returns the calendar for year with the default working weekdays
+func NewCalendar(year int, holidays []Holiday) Calendar {
+	return Calendar{Year: year, WorkingWeekdays: DefaultWorkingWeekdays, Holidays: holidays}
+}
+
+// IsWorkingDay reports whether date falls on a working weekday and is not a holiday
+func (c Calendar) IsWorkingDay(date time.Time) bool {
+	return c.IsWorkingWeekday(date) && !c.IsHoliday(date)
+}
+
+// IsWorkingWeekday reports whether date falls on a working weekday, holiday or not
+func (c Calendar) IsWorkingWeekday(date time.Time) bool {
+	return slices.Contains(c.WorkingWeekdays, date.Weekday())
+}
+
+// IsHoliday reports whether date is one of the company's holidays
+func (c Calendar) IsHoliday(date time.Time) bool {
+	for _, h := range c.Holidays {
+		if h.Date.Year() == date.Year() && h.Date.YearDay() == date.YearDay() {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkingDays counts the working days in the calendar's year
+func (c Calendar) WorkingDays() int {
+	count := 0
+	for d := time.Date(c.Year, time.January, 1, 0, 0, 0, 0, time.UTC); d.Year() == c.Year; d = d.AddDate(0, 0, 1) {
+		if c.IsWorkingDay(d) {
+			count++
+		}
+	}
+	return count
 }
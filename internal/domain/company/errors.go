@@ -9,4 +9,6 @@ var (
 	ErrInvalidCompanyName           = errors.New("company name cannot be empty")
 	ErrUpdatedAtBeforeCreatedAt     = errors.New("updated_at cannot be before created_at")
 	ErrFileSizeExceeds              = errors.New("File size exceeds 5MB")
+	ErrHolidayNotFound              = errors.New("holiday not found")
+	ErrHolidayExists                = errors.New("a holiday already exists on this date")
 )
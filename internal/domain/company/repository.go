@@ -9,4 +9,17 @@ type CompanyRepository interface {
 	ExistsByIDOrUsername(ctx context.Context, id, username *string) (bool, error)
 	Update(ctx context.Context, id string, req UpdateCompanyRequest) error
 	Delete(ctx context.Context, id string) error
+	GetTimezone(ctx context.Context, id string) (string, error)
+	GetLeaveYearStartMonth(ctx context.Context, id string) (int, error)
+	GetOnboardingCounts(ctx context.Context, id string) (OnboardingCounts, error)
+}
+
+type HolidayRepository interface {
+	// GetByYear returns the company's holidays in year, ordered by date. Recurring holidays from
+	// earlier years are included with their date moved into year.
+	GetByYear(ctx context.Context, companyID string, year int) ([]Holiday, error)
+	// Create returns ErrHolidayExists when the company already has a holiday on that date
+	Create(ctx context.Context, holiday Holiday) (Holiday, error)
+	// Delete returns ErrHolidayNotFound when the holiday does not exist in the company
+	Delete(ctx context.Context, companyID string, id string) (Holiday, error)
 }
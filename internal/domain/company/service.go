@@ -11,4 +11,20 @@ type CompanyService interface {
 	Update(ctx context.Context, id string, req UpdateCompanyRequest) error
 	Delete(ctx context.Context, id string) error
 	UploadCompanyLogo(ctx context.Context, req UploadCompanyLogoRequest) (UploadCompanyLogoResponse, error)
+	SeedDefaultLeaveTypes(ctx context.Context, companyID string, req SeedLeaveTypesRequest) (SeedLeaveTypesResponse, error)
+	GetOnboardingStatus(ctx context.Context, companyID string) (OnboardingStatusResponse, error)
+	// GetCalendar returns the company's holidays and working weekdays for year, 0 meaning the current year
+	GetCalendar(ctx context.Context, companyID string, year int) (CalendarResponse, error)
+	// InvalidateCalendar drops the company's cached calendars; call it whenever its holidays change
+	InvalidateCalendar(companyID string)
+	// CreateHoliday adds a company-wide day off and clears the company's cached calendars
+	CreateHoliday(ctx context.Context, companyID string, req CreateHolidayRequest) (HolidayResponse, error)
+	// DeleteHoliday removes a company holiday and clears the company's cached calendars
+	DeleteHoliday(ctx context.Context, companyID string, id string) error
+}
+
+// CalendarProvider returns company calendars. Services doing working-day math depend on it
+// rather than on CompanyService.
+type CalendarProvider interface {
+	Calendar(ctx context.Context, companyID string, year int) (Calendar, error)
 }
@@ -76,3 +76,32 @@ type AttendanceRecordItem struct {
 	Status       string  `json:"status"`
 	CheckIn      *string `json:"check_in,omitempty"` // Format: "HH:MM"
 }
+
+// ========== HEADCOUNT ANALYTICS ==========
+
+// HeadcountAnalyticsResponse represents headcount distribution and 12-month trends
+type HeadcountAnalyticsResponse struct {
+	TotalActive      int64                `json:"total_active"`
+	ByBranch         []HeadcountGroupItem `json:"by_branch"`
+	ByPosition       []HeadcountGroupItem `json:"by_position"`
+	ByGrade          []HeadcountGroupItem `json:"by_grade"`
+	ByEmploymentType []HeadcountGroupItem `json:"by_employment_type"`
+	Monthly          []HeadcountMonthItem `json:"monthly"`       // Oldest month first, 12 entries
+	TurnoverRate     float64              `json:"turnover_rate"` // Percent over the last 12 months
+	PeriodStart      string               `json:"period_start"`  // Format: "YYYY-MM"
+	PeriodEnd        string               `json:"period_end"`    // Format: "YYYY-MM"
+}
+
+// HeadcountGroupItem represents one bar/slice of a headcount breakdown
+type HeadcountGroupItem struct {
+	ID    *string `json:"id,omitempty"`
+	Label string  `json:"label"`
+	Count int64   `json:"count"`
+}
+
+// HeadcountMonthItem represents hires and departures for one month
+type HeadcountMonthItem struct {
+	Month        string `json:"month"` // Format: "YYYY-MM"
+	NewHires     int64  `json:"new_hires"`
+	Resignations int64  `json:"resignations"`
+}
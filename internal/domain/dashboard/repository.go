@@ -44,6 +44,40 @@ type MonthlyAttendanceData struct {
 	Records []AttendanceRecordItem
 }
 
+// HeadcountDimension selects the employee attribute active headcount is grouped by
+type HeadcountDimension string
+
+const (
+	HeadcountByBranch         HeadcountDimension = "branch"
+	HeadcountByPosition       HeadcountDimension = "position"
+	HeadcountByGrade          HeadcountDimension = "grade"
+	HeadcountByEmploymentType HeadcountDimension = "employment_type"
+)
+
+// HeadcountUnassignedLabel labels the group of employees without a value for the dimension
+const HeadcountUnassignedLabel = "Unassigned"
+
+// HeadcountGroupStats is the active headcount of one group (branch, position, grade or employment type)
+type HeadcountGroupStats struct {
+	ID    *string // nil for employment type and the unassigned group
+	Label string
+	Count int64
+}
+
+// HeadcountMonthlyStats combines hires and departures for a month
+type HeadcountMonthlyStats struct {
+	Month        time.Time
+	NewHires     int64
+	Resignations int64 // resigned or terminated
+}
+
+// HeadcountTotals combines the counts needed for the turnover rate
+type HeadcountTotals struct {
+	Active        int64
+	ActiveAtStart int64 // employed at the start of the period
+	Resignations  int64 // resigned or terminated within the period
+}
+
 // DashboardRepository defines the interface for dashboard data access
 type DashboardRepository interface {
 	// GetEmployeeSummary returns total, new (30 days), active, resigned counts in single query
@@ -60,4 +94,13 @@ type DashboardRepository interface {
 
 	// GetMonthlyAttendanceWithRecords returns monthly stats + latest records in single query (using subquery)
 	GetMonthlyAttendanceWithRecords(ctx context.Context, companyID string, year, month int, limit int) (*MonthlyAttendanceData, error)
+
+	// GetHeadcountTotals returns active headcount now and at since, plus departures since then, in single query
+	GetHeadcountTotals(ctx context.Context, companyID string, since time.Time) (*HeadcountTotals, error)
+
+	// GetHeadcountByDimension returns active headcount grouped by branch, position, grade or employment type
+	GetHeadcountByDimension(ctx context.Context, companyID string, dimension HeadcountDimension) ([]HeadcountGroupStats, error)
+
+	// GetHeadcountMonthlyMovements returns hires and departures per month from the month of since onward, one row per month
+	GetHeadcountMonthlyMovements(ctx context.Context, companyID string, since time.Time, months int) ([]HeadcountMonthlyStats, error)
 }
@@ -18,4 +18,7 @@ type DashboardService interface {
 
 	// GetDailyAttendanceStats returns attendance statistics for a specific day
 	GetDailyAttendanceStats(ctx context.Context, date string) (*AttendanceStatsResponse, error)
+
+	// GetHeadcountAnalytics returns headcount breakdowns, monthly hires/resignations and turnover for the last 12 months
+	GetHeadcountAnalytics(ctx context.Context, companyID string) (*HeadcountAnalyticsResponse, error)
 }
@@ -3,6 +3,7 @@ package employee
 import (
 	"mime/multipart"
 	"strings"
+	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
 	"github.com/shopspring/decimal"
@@ -14,30 +15,36 @@ import (
 
 // CreateEmployeeRequest for creating a new employee
 type CreateEmployeeRequest struct {
-	WorkScheduleID        string                `json:"work_schedule_id"`
-	PositionID            string                `json:"position_id"`
-	GradeID               string                `json:"grade_id"`
-	BranchID              string                `json:"branch_id,omitempty"`
-	EmployeeCode          string                `json:"employee_code"`
-	FullName              string                `json:"full_name"`
-	Email                 string                `json:"email"` // Required for invitation
-	Role                  string                `json:"role"`  // "employee" (default) or "manager"
-	NIK                   *string               `json:"nik,omitempty"`
-	Gender                string                `json:"gender"`
-	PhoneNumber           string                `json:"phone_number"`
-	Address               *string               `json:"address,omitempty"`
-	PlaceOfBirth          *string               `json:"place_of_birth,omitempty"`
-	DOB                   *string               `json:"dob,omitempty"`
-	Education             *string               `json:"education,omitempty"`
-	HireDate              string                `json:"hire_date"`
-	EmploymentType        string                `json:"employment_type"`
-	WarningLetter         *string               `json:"warning_letter,omitempty"`
-	BankName              *string               `json:"bank_name,omitempty"`
-	BankAccountHolderName *string               `json:"bank_account_holder_name,omitempty"`
-	BankAccountNumber     *string               `json:"bank_account_number,omitempty"`
-	BaseSalary            *decimal.Decimal      `json:"base_salary,omitempty"`
-	File                  multipart.File        `json:"-"`
-	FileHeader            *multipart.FileHeader `json:"-"`
+	WorkScheduleID        string           `json:"work_schedule_id"`
+	PositionID            string           `json:"position_id"`
+	GradeID               string           `json:"grade_id"`
+	BranchID              string           `json:"branch_id,omitempty"`
+	EmployeeCode          string           `json:"employee_code"`
+	FullName              string           `json:"full_name"`
+	Email                 string           `json:"email"` // Required for invitation
+	Role                  string           `json:"role"`  // "employee" (default) or "manager"
+	NIK                   *string          `json:"nik,omitempty"`
+	Gender                string           `json:"gender"`
+	PhoneNumber           string           `json:"phone_number"`
+	Address               *string          `json:"address,omitempty"`
+	PlaceOfBirth          *string          `json:"place_of_birth,omitempty"`
+	DOB                   *string          `json:"dob,omitempty"`
+	Education             *string          `json:"education,omitempty"`
+	HireDate              string           `json:"hire_date"`
+	EmploymentType        string           `json:"employment_type"`
+	WarningLetter         *string          `json:"warning_letter,omitempty"`
+	BankName              *string          `json:"bank_name,omitempty"`
+	BankAccountHolderName *string          `json:"bank_account_holder_name,omitempty"`
+	BankAccountNumber     *string          `json:"bank_account_number,omitempty"`
+	BaseSalary            *decimal.Decimal `json:"base_salary,omitempty"`
+	OverrideSalaryBand    bool             `json:"override_salary_band,omitempty"` // Owner only: allow base_salary outside the grade band
+
+	EmergencyContactName         *string `json:"emergency_contact_name,omitempty"`
+	EmergencyContactRelationship *string `json:"emergency_contact_relationship,omitempty"`
+	EmergencyContactPhone        *string `json:"emergency_contact_phone,omitempty"`
+
+	File       multipart.File        `json:"-"`
+	FileHeader *multipart.FileHeader `json:"-"`
 }
 
 func (r *CreateEmployeeRequest) Validate() error {
@@ -179,6 +186,8 @@ func (r *CreateEmployeeRequest) Validate() error {
 		}
 	}
 
+	errs = append(errs, validateEmergencyContact(r.EmergencyContactName, r.EmergencyContactRelationship, r.EmergencyContactPhone)...)
+
 	// Validate avatar file if provided
 	if r.FileHeader != nil {
 		filename := r.FileHeader.Filename
@@ -227,6 +236,11 @@ type UpdateEmployeeRequest struct {
 	BankAccountHolderName *string          `json:"bank_account_holder_name,omitempty"`
 	BankAccountNumber     *string          `json:"bank_account_number,omitempty"`
 	BaseSalary            *decimal.Decimal `json:"base_salary,omitempty"`
+	OverrideSalaryBand    bool             `json:"override_salary_band,omitempty"` // Owner only: allow base_salary outside the grade band
+
+	EmergencyContactName         *string `json:"emergency_contact_name,omitempty"`
+	EmergencyContactRelationship *string `json:"emergency_contact_relationship,omitempty"`
+	EmergencyContactPhone        *string `json:"emergency_contact_phone,omitempty"`
 }
 
 func (r *UpdateEmployeeRequest) Validate(role string) error {
@@ -280,7 +294,7 @@ func (r *UpdateEmployeeRequest) Validate(role string) error {
 		if len(restrictedFields) > 0 {
 			errs = append(errs, validator.ValidationError{
 				Field:   "restricted_fields",
-				Message: "employee cannot update the following fields: " + strings.Join(restrictedFields, ", ") + ". Only phone_number, address, place_of_birth, dob, education, and bank details can be updated",
+				Message: "employee cannot update the following fields: " + strings.Join(restrictedFields, ", ") + ". Only phone_number, address, place_of_birth, dob, education, bank details, and emergency contact can be updated",
 			})
 		}
 	}
@@ -367,6 +381,8 @@ func (r *UpdateEmployeeRequest) Validate(role string) error {
 		}
 	}
 
+	errs = append(errs, validateEmergencyContact(r.EmergencyContactName, r.EmergencyContactRelationship, r.EmergencyContactPhone)...)
+
 	if len(errs) > 0 {
 		return errs
 	}
@@ -374,6 +390,36 @@ func (r *UpdateEmployeeRequest) Validate(role string) error {
 	return nil
 }
 
+// validateEmergencyContact checks the emergency contact fields that are set; an empty string clears a field
+func validateEmergencyContact(name, relationship, phone *string) validator.ValidationErrors {
+	var errs validator.ValidationErrors
+
+	if name != nil && len(*name) > 255 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "emergency_contact_name",
+			Message: "emergency_contact_name must not exceed 255 characters",
+		})
+	}
+
+	if relationship != nil && len(*relationship) > 50 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "emergency_contact_relationship",
+			Message: "emergency_contact_relationship must not exceed 50 characters",
+		})
+	}
+
+	if phone != nil && *phone != "" {
+		if len(*phone) < 10 || len(*phone) > 13 {
+			errs = append(errs, validator.ValidationError{
+				Field:   "emergency_contact_phone",
+				Message: "emergency_contact_phone must be between 10 and 13 digits",
+			})
+		}
+	}
+
+	return errs
+}
+
 // EmployeeResponse for returning employee data with joined names
 type EmployeeResponse struct {
 	ID                    string           `json:"id"`
@@ -407,14 +453,31 @@ type EmployeeResponse struct {
 	BankAccountHolderName *string          `json:"bank_account_holder_name,omitempty"`
 	BankAccountNumber     *string          `json:"bank_account_number,omitempty"`
 	BaseSalary            *decimal.Decimal `json:"base_salary,omitempty"`
-	CreatedAt             string           `json:"created_at"`
-	UpdatedAt             string           `json:"updated_at"`
+
+	EmergencyContactName         *string `json:"emergency_contact_name,omitempty"`
+	EmergencyContactRelationship *string `json:"emergency_contact_relationship,omitempty"`
+	EmergencyContactPhone        *string `json:"emergency_contact_phone,omitempty"`
+
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// EmployeeProfileResponse is the full employee profile for display
+type EmployeeProfileResponse struct {
+	EmployeeResponse
+	ManagerID    *string `json:"manager_id,omitempty"`
+	ManagerName  *string `json:"manager_name,omitempty"`
+	TenureMonths int     `json:"tenure_months"`
 }
 
 // EmployeeFilter for filtering employee list
 type EmployeeFilter struct {
 	// Search
-	Search *string `json:"search,omitempty"` // Search by full_name, employee_code, nik
+	Search *string `json:"search,omitempty"` // Search by full_name, employee_code, nik, phone_number
+
+	// Partial matches on a single field, e.g. the last digits of a NIK or phone number
+	NIK         *string `json:"nik,omitempty"`
+	PhoneNumber *string `json:"phone_number,omitempty"`
 
 	// Filters
 	WorkScheduleID   *string `json:"work_schedule_id,omitempty"`
@@ -445,6 +508,20 @@ type EmployeeFilter struct {
 func (f *EmployeeFilter) Validate() error {
 	var errs validator.ValidationErrors
 
+	// Shorter patterns cannot use the trigram indexes and match most of the company anyway
+	if f.NIK != nil && len(strings.TrimSpace(*f.NIK)) < 3 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "nik",
+			Message: "nik must be at least 3 characters",
+		})
+	}
+	if f.PhoneNumber != nil && len(strings.TrimSpace(*f.PhoneNumber)) < 3 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "phone_number",
+			Message: "phone_number must be at least 3 characters",
+		})
+	}
+
 	// Page validation
 	if f.Page < 0 {
 		errs = append(errs, validator.ValidationError{
@@ -665,6 +742,36 @@ func (r *InactivateEmployeeRequest) Validate() error {
 	return nil
 }
 
+// SetBranchScopeRequest limits a manager to a single branch
+// A nil BranchID removes the restriction (company-wide manager)
+type SetBranchScopeRequest struct {
+	EmployeeID string  `json:"-"`
+	BranchID   *string `json:"branch_id"`
+}
+
+func (r *SetBranchScopeRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if r.BranchID != nil && validator.IsEmpty(*r.BranchID) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "branch_id",
+			Message: "branch_id must not be empty; use null to remove the branch scope",
+		})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// BranchScopeResponse is the branch a manager is limited to
+type BranchScopeResponse struct {
+	EmployeeID string  `json:"employee_id"`
+	BranchID   *string `json:"branch_id"`
+}
+
 // UploadAvatarRequest for avatar upload
 type UploadAvatarRequest struct {
 	EmployeeID string                `json:"-"`
@@ -702,3 +809,111 @@ func (r *UploadAvatarRequest) Validate() error {
 
 	return nil
 }
+
+// EmployeeDataExport bundles everything stored about one employee for a data subject access request.
+// Records only carry the employee's own data; who approved, rejected or paid them is left out.
+type EmployeeDataExport struct {
+	ExportedAt    time.Time               `json:"exported_at"`
+	Profile       EmployeeResponse        `json:"profile"`
+	Attendance    []ExportedAttendance    `json:"attendance"`
+	LeaveRequests []ExportedLeaveRequest  `json:"leave_requests"`
+	LeaveQuotas   []ExportedLeaveQuota    `json:"leave_quotas"`
+	Payroll       []ExportedPayrollRecord `json:"payroll"`
+	Documents     []ExportedDocument      `json:"documents"`
+}
+
+// ExportedAttendance is one attendance record in an employee data export
+type ExportedAttendance struct {
+	ID                 string     `json:"id"`
+	Date               string     `json:"date"`
+	Status             string     `json:"status"`
+	ClockIn            *time.Time `json:"clock_in,omitempty"`
+	ClockOut           *time.Time `json:"clock_out,omitempty"`
+	WorkHoursInMinutes *int       `json:"work_hours_in_minutes,omitempty"`
+	LocationType       *string    `json:"location_type,omitempty"`
+	ClockInLatitude    *float64   `json:"clock_in_latitude,omitempty"`
+	ClockInLongitude   *float64   `json:"clock_in_longitude,omitempty"`
+	ClockOutLatitude   *float64   `json:"clock_out_latitude,omitempty"`
+	ClockOutLongitude  *float64   `json:"clock_out_longitude,omitempty"`
+	LateMinutes        *int       `json:"late_minutes,omitempty"`
+	EarlyLeaveMinutes  *int       `json:"early_leave_minutes,omitempty"`
+	OvertimeMinutes    *int       `json:"overtime_minutes,omitempty"`
+	LeaveTypeID        *string    `json:"leave_type_id,omitempty"`
+	RejectionReason    *string    `json:"rejection_reason,omitempty"`
+	AutoClosed         bool       `json:"auto_closed"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// ExportedLeaveRequest is one leave request in an employee data export
+type ExportedLeaveRequest struct {
+	ID                 string     `json:"id"`
+	LeaveTypeID        string     `json:"leave_type_id"`
+	LeaveTypeName      string     `json:"leave_type_name"`
+	StartDate          string     `json:"start_date"`
+	EndDate            string     `json:"end_date"`
+	DurationType       string     `json:"duration_type"`
+	TotalDays          float64    `json:"total_days"`
+	WorkingDays        float64    `json:"working_days"`
+	Reason             string     `json:"reason"`
+	EmergencyLeave     bool       `json:"emergency_leave"`
+	Status             string     `json:"status"`
+	SubmittedAt        time.Time  `json:"submitted_at"`
+	DecidedAt          *time.Time `json:"decided_at,omitempty"`
+	RejectionReason    *string    `json:"rejection_reason,omitempty"`
+	CancelledAt        *time.Time `json:"cancelled_at,omitempty"`
+	CancellationReason *string    `json:"cancellation_reason,omitempty"`
+}
+
+// ExportedLeaveQuota is one leave year's quota in an employee data export
+type ExportedLeaveQuota struct {
+	LeaveTypeID     string  `json:"leave_type_id"`
+	LeaveTypeName   string  `json:"leave_type_name"`
+	Year            int     `json:"year"`
+	OpeningBalance  int     `json:"opening_balance"`
+	EarnedQuota     int     `json:"earned_quota"`
+	RolloverQuota   int     `json:"rollover_quota"`
+	AdjustmentQuota int     `json:"adjustment_quota"`
+	UsedQuota       float64 `json:"used_quota"`
+	PendingQuota    float64 `json:"pending_quota"`
+	AvailableQuota  float64 `json:"available_quota"`
+}
+
+// ExportedPayrollRecord is one payslip in an employee data export
+type ExportedPayrollRecord struct {
+	ID               string                     `json:"id"`
+	PeriodMonth      int                        `json:"period_month"`
+	PeriodYear       int                        `json:"period_year"`
+	BaseSalary       decimal.Decimal            `json:"base_salary"`
+	AllowancesDetail map[string]decimal.Decimal `json:"allowances_detail"`
+	DeductionsDetail map[string]decimal.Decimal `json:"deductions_detail"`
+	TotalAllowances  decimal.Decimal            `json:"total_allowances"`
+	TotalDeductions  decimal.Decimal            `json:"total_deductions"`
+	OvertimeAmount   decimal.Decimal            `json:"overtime_amount"`
+	GrossSalary      decimal.Decimal            `json:"gross_salary"`
+	NetSalary        decimal.Decimal            `json:"net_salary"`
+	TotalWorkDays    int                        `json:"total_work_days"`
+	Status           string                     `json:"status"`
+	PaidAt           *time.Time                 `json:"paid_at,omitempty"`
+}
+
+// Kinds of files listed in an employee data export
+const (
+	DocumentAvatar          = "avatar"
+	DocumentClockInProof    = "clock_in_proof"
+	DocumentClockOutProof   = "clock_out_proof"
+	DocumentLeaveAttachment = "leave_attachment"
+)
+
+// ExportedDocument points to a file stored for the employee; SourceID is the record it belongs to
+type ExportedDocument struct {
+	Type     string `json:"type"`
+	URL      string `json:"url"`
+	SourceID string `json:"source_id,omitempty"`
+}
+
+// AnonymizeEmployeeResponse confirms an employee's personal data was overwritten
+type AnonymizeEmployeeResponse struct {
+	EmployeeID   string    `json:"employee_id"`
+	EmployeeCode string    `json:"employee_code"`
+	AnonymizedAt time.Time `json:"anonymized_at"`
+}
@@ -33,11 +33,19 @@ type Employee struct {
 	BankAccountHolderName *string
 	BankAccountNumber     string
 	BaseSalary            *decimal.Decimal
-	CreatedAt             time.Time
-	UpdatedAt             time.Time
-	DeletedAt             *time.Time
+
+	EmergencyContactName         *string
+	EmergencyContactRelationship *string
+	EmergencyContactPhone        *string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
 }
 
+// AnonymizedName replaces the full name of an anonymized employee
+const AnonymizedName = "Anonymized Employee"
+
 type Gender string
 
 const (
@@ -1,6 +1,9 @@
 package employee
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // EmployeeWithDetails contains employee data with joined related names
 type EmployeeWithDetails struct {
@@ -12,6 +15,14 @@ type EmployeeWithDetails struct {
 	Email            *string
 }
 
+// EmployeeProfile is an employee with all master data names resolved
+type EmployeeProfile struct {
+	EmployeeWithDetails
+	ManagerID    *string // Branch-scoped manager of the employee's branch, if any
+	ManagerName  *string
+	TenureMonths int // Full months from hire_date to today, or to resignation_date if resigned
+}
+
 type EmployeeRepository interface {
 	// Basic CRUD
 	GetByID(ctx context.Context, id string) (Employee, error)
@@ -28,12 +39,22 @@ type EmployeeRepository interface {
 
 	// New operations with company filter and JOINs
 	GetByIDWithDetails(ctx context.Context, id string, companyID string) (EmployeeWithDetails, error)
+	GetProfile(ctx context.Context, id string, companyID string) (EmployeeProfile, error)
 	Search(ctx context.Context, query string, companyID string, limit int) ([]EmployeeWithDetails, error)
 	List(ctx context.Context, filter EmployeeFilter, companyID string) ([]EmployeeWithDetails, int64, error)
 	SoftDelete(ctx context.Context, id string, companyID string) error
 	UpdateAvatar(ctx context.Context, id string, companyID string, avatarURL string) error
 	Inactivate(ctx context.Context, id string, companyID string, resignationDate string) error
+	// Anonymize overwrites the personal data of an inactive employee, unlinks the user account and soft deletes the row.
+	// It returns ErrEmployeeAlreadyAnonymized when the employee was anonymized before.
+	Anonymize(ctx context.Context, id string, companyID string, anonymizedAt time.Time) error
 
 	// Notification-related
 	GetManagersByCompanyID(ctx context.Context, companyID string) ([]Employee, error)
+	GetManagersByBranchID(ctx context.Context, companyID string, branchID string) ([]Employee, error)
+	GetOwnersByCompanyID(ctx context.Context, companyID string) ([]Employee, error)
+
+	// Calendar feed
+	GetCalendarFeedNonce(ctx context.Context, id string) (*string, error)
+	SetCalendarFeedNonce(ctx context.Context, id string, nonce string) error
 }
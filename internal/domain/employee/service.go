@@ -12,6 +12,9 @@ type EmployeeService interface {
 	// GetEmployee retrieves a single employee by ID (with role-based access control)
 	GetEmployee(ctx context.Context, id string) (EmployeeResponse, error)
 
+	// GetProfile retrieves an employee with master data names, manager and tenure (with role-based access control)
+	GetProfile(ctx context.Context, employeeID string) (EmployeeProfileResponse, error)
+
 	// CreateEmployee creates a new employee (manager+ only)
 	CreateEmployee(ctx context.Context, req CreateEmployeeRequest) (EmployeeResponse, error)
 
@@ -29,4 +32,13 @@ type EmployeeService interface {
 
 	// UploadAvatar uploads avatar for an employee
 	UploadAvatar(ctx context.Context, req UploadAvatarRequest) (EmployeeResponse, error)
+
+	// ExportEmployeeData bundles all data stored about an employee (employee themselves, or user.manage)
+	ExportEmployeeData(ctx context.Context, employeeID string) (EmployeeDataExport, error)
+
+	// Anonymize overwrites a resigned or terminated employee's personal data, keeping their records (user.manage only)
+	Anonymize(ctx context.Context, employeeID string) (AnonymizeEmployeeResponse, error)
+
+	// SetBranchScope restricts a manager to a single branch, or lifts the restriction (owner only)
+	SetBranchScope(ctx context.Context, req SetBranchScopeRequest) (BranchScopeResponse, error)
 }
@@ -1,5 +1,7 @@
 package employee_dashboard
 
+import "time"
+
 // ========== COMBINED EMPLOYEE DASHBOARD ==========
 
 // EmployeeDashboardResponse is the combined response for employee dashboard
@@ -47,6 +49,36 @@ type LeaveBreakdownItem struct {
 	Percent       float64 `json:"percent"`
 }
 
+// ========== ATTENDANCE RECAP (My Month) ==========
+
+// AttendanceRecapResponse is an employee's own attendance for a month with a daily list
+type AttendanceRecapResponse struct {
+	Month                string               `json:"month"` // Format: "YYYY-MM"
+	OnTimeCount          int64                `json:"on_time_count"`
+	LateCount            int64                `json:"late_count"`
+	AbsentCount          int64                `json:"absent_count"`
+	LeaveDays            int64                `json:"leave_days"`
+	WorkDays             int64                `json:"work_days"`              // Days counted by payroll
+	TotalLateMinutes     int64                `json:"total_late_minutes"`     // Over payroll-counted days
+	TotalOvertimeMinutes int64                `json:"total_overtime_minutes"` // Over payroll-counted days
+	TotalWorkHours       string               `json:"total_work_hours"`       // Format: "120h 54m"
+	TotalWorkMinutes     int64                `json:"total_work_minutes"`
+	Days                 []AttendanceRecapDay `json:"days"`
+}
+
+// AttendanceRecapDay is one attendance record in the recap
+type AttendanceRecapDay struct {
+	Date            string     `json:"date"`     // Format: "2006-01-02"
+	DayName         string     `json:"day_name"` // "Monday", "Tuesday", etc
+	Status          string     `json:"status"`
+	LeaveTypeName   *string    `json:"leave_type_name,omitempty"`
+	ClockIn         *time.Time `json:"clock_in,omitempty"`
+	ClockOut        *time.Time `json:"clock_out,omitempty"`
+	WorkMinutes     int64      `json:"work_minutes"`
+	LateMinutes     int64      `json:"late_minutes"`
+	OvertimeMinutes int64      `json:"overtime_minutes"`
+}
+
 // ========== LEAVE SUMMARY ==========
 
 // LeaveSummaryResponse represents leave quota summary for a year
@@ -13,6 +13,9 @@ type EmployeeDashboardRepository interface {
 	// GetAttendanceSummary returns attendance distribution for a month
 	GetAttendanceSummary(ctx context.Context, employeeID string, year, month int) (*AttendanceSummaryData, error)
 
+	// GetAttendanceRecap returns every attendance record of an employee in a month, oldest first
+	GetAttendanceRecap(ctx context.Context, employeeID string, year, month int) ([]AttendanceRecapData, error)
+
 	// GetLeaveSummary returns leave quota summary for a year
 	GetLeaveSummary(ctx context.Context, employeeID string, year int) ([]LeaveQuotaData, error)
 
@@ -58,3 +61,15 @@ type DailyWorkHourData struct {
 	Date        time.Time
 	WorkMinutes int64
 }
+
+// AttendanceRecapData contains one attendance record for the monthly recap
+type AttendanceRecapData struct {
+	Date            time.Time
+	Status          string
+	LeaveTypeName   *string
+	ClockIn         *time.Time
+	ClockOut        *time.Time
+	WorkMinutes     int64
+	LateMinutes     int64
+	OvertimeMinutes int64
+}
@@ -15,6 +15,10 @@ type EmployeeDashboardService interface {
 	// month format: "YYYY-MM"
 	GetAttendanceSummary(ctx context.Context, month string) (*AttendanceSummaryResponse, error)
 
+	// GetMyAttendanceRecap returns the month's attendance counts, late and overtime totals and
+	// daily list for the employee of userID; month and year default to the current month when 0
+	GetMyAttendanceRecap(ctx context.Context, userID string, month, year int) (*AttendanceRecapResponse, error)
+
 	// GetLeaveSummary returns leave quota summary for a year
 	GetLeaveSummary(ctx context.Context, year string) (*LeaveSummaryResponse, error)
 
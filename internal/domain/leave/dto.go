@@ -2,6 +2,8 @@ package leave
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"mime/multipart"
 	"strings"
 	"time"
@@ -24,8 +26,9 @@ type CreateLeaveTypeRequest struct {
 	RequiresAttachment          *bool `json:"requires_attachment,omitempty"`
 	AttachmentRequiredAfterDays *int  `json:"attachment_required_after_days,omitempty"`
 
-	HasQuota      *bool   `json:"has_quota,omitempty"`
-	AccrualMethod *string `json:"accrual_method,omitempty"`
+	HasQuota             *bool   `json:"has_quota,omitempty"`
+	AccrualMethod        *string `json:"accrual_method,omitempty"`
+	AllowNegativeBalance *bool   `json:"allow_negative_balance,omitempty"`
 
 	DeductionType *string `json:"deduction_type,omitempty"`
 	AllowHalfDay  *bool   `json:"allow_half_day,omitempty"`
@@ -231,6 +234,14 @@ func (r *CreateLeaveTypeRequest) Validate() error {
 					})
 				}
 
+				// default_quota is the fallback when no rule matches; it may be omitted (0) but never negative
+				if qr.DefaultQuota < 0 {
+					errs = append(errs, validator.ValidationError{
+						Field:   "quota_rules.default_quota",
+						Message: "default_quota must not be negative",
+					})
+				}
+
 				// Valid employment types
 				validEmploymentTypes := []string{"permanent", "probation", "contract", "internship", "freelance"}
 
@@ -290,6 +301,14 @@ func (r *CreateLeaveTypeRequest) Validate() error {
 							})
 						}
 
+						// Combined rules are matched on conditions only; a rule without them would never apply
+						if qr.Type == "combined" && rule.Conditions == nil {
+							errs = append(errs, validator.ValidationError{
+								Field:   "quota_rules.rules[" + validator.Itoa(i) + "].conditions",
+								Message: "conditions are required when type is 'combined'",
+							})
+						}
+
 						// For combined rules, validate conditions
 						if rule.Conditions != nil {
 							if rule.Conditions.MinTenureMonths != nil && *rule.Conditions.MinTenureMonths < 0 {
@@ -320,14 +339,6 @@ func (r *CreateLeaveTypeRequest) Validate() error {
 							}
 						}
 					}
-				} else {
-					// If no rules and type is not fixed, default_quota is mandatory
-					if qr.DefaultQuota < 0 {
-						errs = append(errs, validator.ValidationError{
-							Field:   "quota_rules.default_quota",
-							Message: "default_quota is required when rules are not provided and must not be negative",
-						})
-					}
 				}
 			}
 		}
@@ -351,6 +362,7 @@ type LeaveTypeResponse struct {
 	RequiresApproval     *bool      `json:"requires_approval,omitempty"`
 	HasQuota             *bool      `json:"has_quota,omitempty"`
 	AccrualMethod        *string    `json:"accrual_method,omitempty"`
+	AllowNegativeBalance *bool      `json:"allow_negative_balance,omitempty"`
 	QuotaCalculationType string     `json:"quota_calculation_type"`
 	QuotaRules           QuotaRules `json:"quota_rules"`
 }
@@ -367,6 +379,7 @@ type UpdateLeaveTypeRequest struct {
 	AttachmentRequiredAfterDays *int                   `json:"attachment_required_after_days,omitempty"`
 	HasQuota                    *bool                  `json:"has_quota,omitempty"`
 	AccrualMethod               *string                `json:"accrual_method,omitempty"`
+	AllowNegativeBalance        *bool                  `json:"allow_negative_balance,omitempty"`
 	DeductionType               *string                `json:"deduction_type,omitempty"`
 	AllowHalfDay                *bool                  `json:"allow_half_day,omitempty"`
 	MaxDaysPerRequest           *int                   `json:"max_days_per_request,omitempty"`
@@ -647,6 +660,14 @@ func (r *UpdateLeaveTypeRequest) Validate() error {
 					})
 				}
 
+				// default_quota is the fallback when no rule matches; it may be omitted (0) but never negative
+				if qr.DefaultQuota < 0 {
+					errs = append(errs, validator.ValidationError{
+						Field:   "quota_rules.default_quota",
+						Message: "default_quota must not be negative",
+					})
+				}
+
 				// Valid employment types
 				validEmploymentTypes := []string{"permanent", "probation", "contract", "internship", "freelance"}
 
@@ -706,6 +727,14 @@ func (r *UpdateLeaveTypeRequest) Validate() error {
 							})
 						}
 
+						// Combined rules are matched on conditions only; a rule without them would never apply
+						if qr.Type == "combined" && rule.Conditions == nil {
+							errs = append(errs, validator.ValidationError{
+								Field:   "quota_rules.rules[" + validator.Itoa(i) + "].conditions",
+								Message: "conditions are required when type is 'combined'",
+							})
+						}
+
 						// For combined rules, validate conditions
 						if rule.Conditions != nil {
 							if rule.Conditions.MinTenureMonths != nil && *rule.Conditions.MinTenureMonths < 0 {
@@ -736,14 +765,6 @@ func (r *UpdateLeaveTypeRequest) Validate() error {
 							}
 						}
 					}
-				} else {
-					// If no rules and type is not fixed, default_quota is mandatory
-					if qr.DefaultQuota < 0 {
-						errs = append(errs, validator.ValidationError{
-							Field:   "quota_rules.default_quota",
-							Message: "default_quota is required when rules are not provided and must not be negative",
-						})
-					}
 				}
 			}
 		}
@@ -756,6 +777,14 @@ func (r *UpdateLeaveTypeRequest) Validate() error {
 	return nil
 }
 
+// How StartDate and EndDate of a leave request list filter are matched
+const (
+	// LeaveDateModeWithin keeps requests that start on or after start_date and end on or before end_date
+	LeaveDateModeWithin = "within"
+	// LeaveDateModeOverlaps keeps requests with at least one day between start_date and end_date
+	LeaveDateModeOverlaps = "overlaps"
+)
+
 type LeaveRequestFilter struct {
 	// Search & Filter
 	EmployeeID   *string `json:"employee_id,omitempty"`
@@ -764,6 +793,10 @@ type LeaveRequestFilter struct {
 	Status       *string `json:"status,omitempty"`
 	StartDate    *string `json:"start_date,omitempty"`
 	EndDate      *string `json:"end_date,omitempty"`
+	DateMode     string  `json:"date_mode,omitempty"` // within (default), overlaps
+
+	// BranchID restricts results to one branch; set from the caller's scope, never from input
+	BranchID *string `json:"-"`
 
 	// Pagination
 	Page  int `json:"page"`
@@ -835,6 +868,15 @@ func (f *LeaveRequestFilter) Validate() error {
 		}
 	}
 
+	if f.DateMode == "" {
+		f.DateMode = LeaveDateModeWithin
+	} else if f.DateMode != LeaveDateModeWithin && f.DateMode != LeaveDateModeOverlaps {
+		errs = append(errs, validator.ValidationError{
+			Field:   "date_mode",
+			Message: "date_mode must be one of: within, overlaps",
+		})
+	}
+
 	// Sort validation
 	if f.SortBy != "" {
 		validSortFields := []string{"submitted_at", "employee_name", "start_date", "end_date", "status"}
@@ -867,13 +909,14 @@ func (f *LeaveRequestFilter) Validate() error {
 	return nil
 }
 
-// MyLeaveRequestFilter - Filter for /leave/requests/my (no employee filters)
+// MyLeaveRequestFilter - Filter for /leave/requests/my and /leave/requests/employee/{employeeID} (no employee filters)
 type MyLeaveRequestFilter struct {
 	// Search & Filter (no employee_id/employee_name)
 	LeaveTypeID *string `json:"leave_type_id,omitempty"`
 	Status      *string `json:"status,omitempty"`
 	StartDate   *string `json:"start_date,omitempty"`
 	EndDate     *string `json:"end_date,omitempty"`
+	DateMode    string  `json:"date_mode,omitempty"` // within (default), overlaps
 
 	// Pagination
 	Page  int `json:"page"`
@@ -945,6 +988,15 @@ func (f *MyLeaveRequestFilter) Validate() error {
 		}
 	}
 
+	if f.DateMode == "" {
+		f.DateMode = LeaveDateModeWithin
+	} else if f.DateMode != LeaveDateModeWithin && f.DateMode != LeaveDateModeOverlaps {
+		errs = append(errs, validator.ValidationError{
+			Field:   "date_mode",
+			Message: "date_mode must be one of: within, overlaps",
+		})
+	}
+
 	// Sort validation (no employee_name for my requests)
 	if f.SortBy != "" {
 		validSortFields := []string{"submitted_at", "start_date", "end_date", "status"}
@@ -978,6 +1030,15 @@ func (f *MyLeaveRequestFilter) Validate() error {
 }
 
 // LeaveRequestResponse - Enhanced with employee details
+// LeaveAttachment is an opened leave request attachment; the caller must close Content.
+// FileName is derived from the request, never from the storage key.
+type LeaveAttachment struct {
+	Content     io.ReadCloser
+	FileName    string
+	ContentType string
+	ModTime     time.Time
+}
+
 type LeaveRequestResponse struct {
 	ID              string     `json:"id"`
 	EmployeeID      string     `json:"employee_id"`
@@ -996,6 +1057,9 @@ type LeaveRequestResponse struct {
 	ApprovedBy      *string    `json:"approved_by,omitempty"`
 	ApprovedAt      *time.Time `json:"approved_at,omitempty"`
 	RejectionReason *string    `json:"rejection_reason,omitempty"`
+	RecurrenceID    *string    `json:"recurrence_id,omitempty"`
+	EscalatedAt     *time.Time `json:"escalated_at,omitempty"`
+	Version         int        `json:"version"`
 }
 
 // ListLeaveRequestResponse - Enhanced with pagination metadata
@@ -1008,6 +1072,17 @@ type ListLeaveRequestResponse struct {
 	Requests   []LeaveRequestResponse `json:"requests"`
 }
 
+// CalendarFeedTokenResponse is returned when an employee (re)generates their iCal feed URL
+type CalendarFeedTokenResponse struct {
+	Token   string `json:"token"`
+	FeedURL string `json:"feed_url"`
+}
+
+// PendingApprovalCountResponse is the number of requests awaiting the caller's approval
+type PendingApprovalCountResponse struct {
+	Count int64 `json:"count"`
+}
+
 type CreateLeaveQuotaRequest struct {
 	EmployeeID  string `json:"employee_id"`
 	LeaveTypeID string `json:"leave_type_id"`
@@ -1285,6 +1360,7 @@ type CreateLeaveRequestRequest struct {
 	DurationType  string                `json:"duration_type"` // 'full_day', 'half_day_morning', 'half_day_afternoon'
 	Reason        string                `json:"reason"`
 	AttachmentURL *string               `json:"-"`
+	RecurrenceID  *string               `json:"-"`
 	File          multipart.File        `json:"-"`
 	FileHeader    *multipart.FileHeader `json:"-"`
 }
@@ -1387,6 +1463,11 @@ type UpdateLeaveRequestRequest struct {
 	CancelledBy        *string    `json:"cancelled_by,omitempty"`
 	CancelledAt        *time.Time `json:"cancelled_at,omitempty"`
 	CancellationReason *string    `json:"cancellation_reason,omitempty"`
+	// ExpectedVersion is the version the row was read at; the update fails with ErrConcurrentModification
+	// when the row changed since
+	ExpectedVersion int `json:"-"`
+	// ExpectedStatus makes the update fail with ErrLeaveAlreadyProcessed when the request left that status
+	ExpectedStatus *string `json:"-"`
 }
 
 func (r *UpdateLeaveRequestRequest) Validate() error {
@@ -1561,6 +1642,80 @@ func (r *UpdateLeaveRequestRequest) Validate() error {
 	return nil
 }
 
+// UpdateMyLeaveRequestRequest edits a request its employee submitted while it still waits for approval.
+// Omitted fields keep their current value; a new attachment replaces the old one.
+type UpdateMyLeaveRequestRequest struct {
+	StartDate    *string               `json:"start_date,omitempty"`
+	EndDate      *string               `json:"end_date,omitempty"`
+	DurationType *string               `json:"duration_type,omitempty"`
+	Reason       *string               `json:"reason,omitempty"`
+	Version      *int                  `json:"version" validate:"required"` // version as loaded; a stale value rejects the edit
+	File         multipart.File        `json:"-"`
+	FileHeader   *multipart.FileHeader `json:"-"`
+}
+
+func (r *UpdateMyLeaveRequestRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if r.StartDate != nil {
+		if _, valid := validator.IsValidDate(*r.StartDate); !valid {
+			errs = append(errs, validator.ValidationError{
+				Field:   "start_date",
+				Message: "start date format is invalid (use YYYY-MM-DD)",
+			})
+		}
+	}
+
+	if r.EndDate != nil {
+		if _, valid := validator.IsValidDate(*r.EndDate); !valid {
+			errs = append(errs, validator.ValidationError{
+				Field:   "end_date",
+				Message: "end date format is invalid (use YYYY-MM-DD)",
+			})
+		}
+	}
+
+	validDurationTypes := []string{"full_day", "half_day_morning", "half_day_afternoon"}
+	if r.DurationType != nil && !validator.IsInSlice(*r.DurationType, validDurationTypes) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "duration_type",
+			Message: "duration type must be one of: full_day, half_day_morning, half_day_afternoon",
+		})
+	}
+
+	if r.Reason != nil && len(*r.Reason) < 10 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "reason",
+			Message: "reason must be at least 10 characters",
+		})
+	}
+
+	if r.StartDate == nil && r.EndDate == nil && r.DurationType == nil && r.Reason == nil && r.File == nil {
+		errs = append(errs, validator.ValidationError{
+			Field:   "data",
+			Message: "at least one field must be provided",
+		})
+	}
+
+	if r.Version == nil {
+		errs = append(errs, validator.ValidationError{
+			Field:   "version",
+			Message: "version is required",
+		})
+	} else if *r.Version < 1 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "version",
+			Message: "version must be a positive number",
+		})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
 // ========================================
 // LEAVE QUOTA DTOs
 // ========================================
@@ -1579,6 +1734,42 @@ type LeaveQuotaResponse struct {
 	UsedQuota       float64 `json:"used_quota"`
 	PendingQuota    float64 `json:"pending_quota"`
 	AvailableQuota  float64 `json:"available_quota"`
+	NegativeBalance float64 `json:"negative_balance"` // days taken beyond the quota, 0 when not in debt
+}
+
+// ToResponse maps the quota for responses; unset balances read as 0
+func (q LeaveQuota) ToResponse(leaveTypeName string) LeaveQuotaResponse {
+	available := floatValue(q.AvailableQuota)
+	return LeaveQuotaResponse{
+		ID:              q.ID,
+		EmployeeID:      q.EmployeeID,
+		EmployeeName:    q.EmployeeName,
+		LeaveTypeID:     q.LeaveTypeID,
+		LeaveTypeName:   leaveTypeName,
+		Year:            q.Year,
+		OpeningBalance:  intValue(q.OpeningBalance),
+		EarnedQuota:     intValue(q.EarnedQuota),
+		RolloverQuota:   intValue(q.RolloverQuota),
+		AdjustmentQuota: intValue(q.AdjustmentQuota),
+		UsedQuota:       floatValue(q.UsedQuota),
+		PendingQuota:    floatValue(q.PendingQuota),
+		AvailableQuota:  available,
+		NegativeBalance: max(-available, 0),
+	}
+}
+
+func intValue(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func floatValue(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
 }
 
 type AdjustQuotaRequest struct {
@@ -1693,3 +1884,305 @@ func (r *RejectRequestRequest) Validate() error {
 
 	return nil
 }
+
+// maxRecurringLeaveRangeDays caps how far a single recurring submission may span
+const maxRecurringLeaveRangeDays = 366
+
+// CreateRecurringLeaveRequest submits one single-day request for every date in the
+// range that falls on one of the given weekdays (1=Monday ... 7=Sunday)
+type CreateRecurringLeaveRequest struct {
+	EmployeeID   string `json:"-"`
+	LeaveTypeID  string `json:"leave_type_id"`
+	StartDate    string `json:"start_date"`
+	EndDate      string `json:"end_date"`
+	Weekdays     []int  `json:"weekdays"`
+	DurationType string `json:"duration_type"`
+	Reason       string `json:"reason"`
+}
+
+func (r *CreateRecurringLeaveRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if validator.IsEmpty(r.EmployeeID) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "employee_id",
+			Message: "employee ID is required",
+		})
+	}
+	if validator.IsEmpty(r.LeaveTypeID) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "leave_type_id",
+			Message: "leave type ID is required",
+		})
+	} else if !validator.IsValidUUID(r.LeaveTypeID) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "leave_type_id",
+			Message: "leave type ID must be a valid UUID",
+		})
+	}
+
+	var startDate, endDate time.Time
+	var startValid, endValid bool
+	if validator.IsEmpty(r.StartDate) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "start_date",
+			Message: "start date is required",
+		})
+	} else if startDate, startValid = validator.IsValidDate(r.StartDate); !startValid {
+		errs = append(errs, validator.ValidationError{
+			Field:   "start_date",
+			Message: "start date format is invalid (use YYYY-MM-DD)",
+		})
+	}
+
+	if validator.IsEmpty(r.EndDate) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "end_date",
+			Message: "end date is required",
+		})
+	} else if endDate, endValid = validator.IsValidDate(r.EndDate); !endValid {
+		errs = append(errs, validator.ValidationError{
+			Field:   "end_date",
+			Message: "end date format is invalid (use YYYY-MM-DD)",
+		})
+	}
+
+	if startValid && endValid {
+		if endDate.Before(startDate) {
+			errs = append(errs, validator.ValidationError{
+				Field:   "end_date",
+				Message: "end date must be on or after start date",
+			})
+		} else if endDate.Sub(startDate).Hours()/24 >= maxRecurringLeaveRangeDays {
+			errs = append(errs, validator.ValidationError{
+				Field:   "end_date",
+				Message: fmt.Sprintf("recurring leave cannot span more than %d days", maxRecurringLeaveRangeDays),
+			})
+		}
+	}
+
+	if len(r.Weekdays) == 0 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "weekdays",
+			Message: "at least one weekday is required",
+		})
+	}
+	for _, day := range r.Weekdays {
+		if day < 1 || day > 7 {
+			errs = append(errs, validator.ValidationError{
+				Field:   "weekdays",
+				Message: "weekdays must be between 1 (Monday) and 7 (Sunday)",
+			})
+			break
+		}
+	}
+
+	validDurationTypes := []string{"full_day", "half_day_morning", "half_day_afternoon"}
+	if r.DurationType == "" {
+		r.DurationType = "full_day"
+	} else if !validator.IsInSlice(r.DurationType, validDurationTypes) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "duration_type",
+			Message: "duration type must be one of: full_day, half_day_morning, half_day_afternoon",
+		})
+	}
+
+	if validator.IsEmpty(r.Reason) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "reason",
+			Message: "reason is required",
+		})
+	} else if len(r.Reason) < 10 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "reason",
+			Message: "reason must be at least 10 characters",
+		})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// RecurringLeaveResponse lists the single-day requests created from one recurring submission
+type RecurringLeaveResponse struct {
+	RecurrenceID string                 `json:"recurrence_id"`
+	TotalDays    float64                `json:"total_days"`
+	Requests     []LeaveRequestResponse `json:"requests"`
+}
+
+// Reasons a day of an approved leave got no attendance record
+const (
+	LeaveDateSkippedWeekend          = "weekend"
+	LeaveDateSkippedHoliday          = "holiday"
+	LeaveDateSkippedAttendanceExists = "attendance_exists"
+)
+
+// SkippedLeaveDate is a day of an approved leave that kept its existing attendance
+type SkippedLeaveDate struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason"`
+}
+
+// ApproveLeaveResponse lists which days of an approved request got a leave attendance record
+type ApproveLeaveResponse struct {
+	RequestID    string             `json:"request_id"`
+	Status       LeaveRequestStatus `json:"status"`
+	CreatedDates []string           `json:"created_dates"`
+	SkippedDates []SkippedLeaveDate `json:"skipped_dates"`
+}
+
+// ApproveRecurringLeaveResponse reports the attendance coverage of every request approved in a recurrence group
+type ApproveRecurringLeaveResponse struct {
+	RecurrenceID string                 `json:"recurrence_id"`
+	Requests     []ApproveLeaveResponse `json:"requests"`
+}
+
+// LeaveDayCounts are the day figures stored on a leave request
+type LeaveDayCounts struct {
+	TotalDays   float64 `json:"total_days"`
+	WorkingDays float64 `json:"working_days"`
+}
+
+// RecomputeLeaveRequestResponse compares a request's day counts before and after recalculation.
+// QuotaDelta is the change applied to the pending (waiting approval) or used (approved) quota.
+type RecomputeLeaveRequestResponse struct {
+	RequestID  string             `json:"request_id"`
+	Status     LeaveRequestStatus `json:"status"`
+	Before     LeaveDayCounts     `json:"before"`
+	After      LeaveDayCounts     `json:"after"`
+	QuotaDelta float64            `json:"quota_delta"`
+}
+
+// QuotaPreviewFilter picks the employee and leave year of a quota preview; Year defaults to the current leave year
+type QuotaPreviewFilter struct {
+	EmployeeID string `json:"employee_id"`
+	Year       *int   `json:"year,omitempty"`
+}
+
+func (f *QuotaPreviewFilter) Validate() error {
+	var errs validator.ValidationErrors
+
+	if validator.IsEmpty(f.EmployeeID) {
+		errs = append(errs, validator.ValidationError{Field: "employee_id", Message: "employee_id is required"})
+	}
+	if f.Year != nil && *f.Year <= 0 {
+		errs = append(errs, validator.ValidationError{Field: "year", Message: "year must be a positive integer"})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// QuotaPreviewResponse is the quota an employee would be given under a leave type's current rules.
+// MatchedRule is the entry of quota_rules.rules that decided it; nothing is saved.
+type QuotaPreviewResponse struct {
+	LeaveTypeID      string     `json:"leave_type_id"`
+	LeaveTypeName    string     `json:"leave_type_name"`
+	EmployeeID       string     `json:"employee_id"`
+	EmployeeName     string     `json:"employee_name"`
+	Year             int        `json:"year"`
+	RuleType         string     `json:"rule_type"`
+	TenureMonths     int        `json:"tenure_months"`
+	Eligible         bool       `json:"eligible"`
+	IneligibleReason *string    `json:"ineligible_reason,omitempty"`
+	MatchedRuleIndex *int       `json:"matched_rule_index,omitempty"`
+	MatchedRule      *QuotaRule `json:"matched_rule,omitempty"`
+	UsedDefault      bool       `json:"used_default"`
+	Quota            float64    `json:"quota"` // Yearly entitlement
+	AccrualMethod    *string    `json:"accrual_method,omitempty"`
+	OpeningBalance   int        `json:"opening_balance"` // What a quota created now would start with
+	EarnedQuota      int        `json:"earned_quota"`
+}
+
+// RejectRecurringLeaveRequest rejects every pending request in a recurrence group
+type RejectRecurringLeaveRequest struct {
+	RecurrenceID string `json:"-"`
+	Reason       string `json:"reason"`
+}
+
+func (r *RejectRecurringLeaveRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if validator.IsEmpty(r.RecurrenceID) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "recurrence_id",
+			Message: "recurrence_id is required",
+		})
+	} else if !validator.IsValidUUID(r.RecurrenceID) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "recurrence_id",
+			Message: "recurrence_id must be a valid UUID",
+		})
+	}
+
+	if validator.IsEmpty(r.Reason) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "reason",
+			Message: "reason is required",
+		})
+	} else if len(r.Reason) < 10 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "reason",
+			Message: "reason must be at least 10 characters",
+		})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// LeaveTypeStatsFilter picks the leave year and optionally the branch and position of the
+// employees whose requests are counted; Year defaults to the current leave year
+type LeaveTypeStatsFilter struct {
+	Year       *int    `json:"year,omitempty"`
+	BranchID   *string `json:"branch_id,omitempty"`
+	PositionID *string `json:"position_id,omitempty"`
+}
+
+func (f *LeaveTypeStatsFilter) Validate() error {
+	var errs validator.ValidationErrors
+
+	if f.Year != nil && *f.Year <= 0 {
+		errs = append(errs, validator.ValidationError{Field: "year", Message: "year must be a positive integer"})
+	}
+	if f.BranchID != nil && !validator.IsValidUUID(*f.BranchID) {
+		errs = append(errs, validator.ValidationError{Field: "branch_id", Message: "branch_id must be a valid UUID"})
+	}
+	if f.PositionID != nil && !validator.IsValidUUID(*f.PositionID) {
+		errs = append(errs, validator.ValidationError{Field: "position_id", Message: "position_id must be a valid UUID"})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// LeaveTypeStatItem is one bar of the leave type statistics chart, labelled with the leave type name.
+// RejectionRate is the percentage of decided requests that were rejected.
+type LeaveTypeStatItem struct {
+	LeaveTypeID      string  `json:"leave_type_id"`
+	Label            string  `json:"label"`
+	TotalRequests    int64   `json:"total_requests"`
+	ApprovedRequests int64   `json:"approved_requests"`
+	RejectedRequests int64   `json:"rejected_requests"`
+	ApprovedDays     float64 `json:"approved_days"`
+	RejectionRate    float64 `json:"rejection_rate"`
+	AverageDays      float64 `json:"average_days"`
+}
+
+// LeaveTypeStatsResponse holds one item per leave type of the company, most requested first
+type LeaveTypeStatsResponse struct {
+	Year        int                 `json:"year"`
+	PeriodStart string              `json:"period_start"` // Format: "YYYY-MM-DD"
+	PeriodEnd   string              `json:"period_end"`   // Format: "YYYY-MM-DD"
+	Items       []LeaveTypeStatItem `json:"items"`
+}
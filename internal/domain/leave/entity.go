@@ -23,8 +23,9 @@ type LeaveType struct {
 	AttachmentRequiredAfterDays *int
 
 	// Quota Rules
-	HasQuota      *bool
-	AccrualMethod *string // 'yearly', 'monthly', 'none'
+	HasQuota             *bool
+	AccrualMethod        *string // 'yearly', 'monthly', 'none'
+	AllowNegativeBalance *bool   // reservation/approval may exceed the available quota
 
 	// Deduction Rules
 	DeductionType *string // 'working_days', 'calendar_days'
@@ -87,6 +88,50 @@ type QuotaConditions struct {
 	MaxTenureMonths *int     `json:"max_tenure_months,omitempty"`
 }
 
+// Matches reports whether an employee satisfies every condition that is set.
+// Unset conditions are ignored, so empty conditions match everyone.
+func (c *QuotaConditions) Matches(positionID, gradeID, employmentType string, tenureMonths int) bool {
+	if len(c.PositionIDs) > 0 && !containsString(c.PositionIDs, positionID) {
+		return false
+	}
+	if len(c.GradeIDs) > 0 && (gradeID == "" || !containsString(c.GradeIDs, gradeID)) {
+		return false
+	}
+	if c.EmploymentType != "" && c.EmploymentType != employmentType {
+		return false
+	}
+	if c.MinTenureMonths != nil && tenureMonths < *c.MinTenureMonths {
+		return false
+	}
+	if c.MaxTenureMonths != nil && tenureMonths >= *c.MaxTenureMonths {
+		return false
+	}
+	return true
+}
+
+// MatchCombinedRule returns the index of the first rule whose conditions the employee satisfies.
+// Rules are evaluated in order, so overlapping rules resolve to the earliest one.
+func (qr QuotaRules) MatchCombinedRule(positionID, gradeID, employmentType string, tenureMonths int) (int, bool) {
+	for i, rule := range qr.Rules {
+		if rule.Conditions == nil {
+			continue
+		}
+		if rule.Conditions.Matches(positionID, gradeID, employmentType, tenureMonths) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
 // Value implements driver.Valuer for database storage
 func (qr QuotaRules) Value() (driver.Value, error) {
 	if qr.Type == "" {
@@ -119,6 +164,43 @@ func ConvertMapToQuotaRules(m map[string]interface{}) (QuotaRules, error) {
 	return qr, err
 }
 
+// LeaveYear returns the leave year t falls in when the leave year starts in startMonth.
+// A leave year is named after the calendar year it starts in, so with April as the start
+// month February 2026 belongs to leave year 2025. A startMonth outside 1-12 means January.
+func LeaveYear(t time.Time, startMonth int) int {
+	if startMonth < 1 || startMonth > 12 || int(t.Month()) >= startMonth {
+		return t.Year()
+	}
+	return t.Year() - 1
+}
+
+// LeaveYearStart returns the first day of leave year year in loc
+func LeaveYearStart(year, startMonth int, loc *time.Location) time.Time {
+	if startMonth < 1 || startMonth > 12 {
+		startMonth = 1
+	}
+	return time.Date(year, time.Month(startMonth), 1, 0, 0, 0, 0, loc)
+}
+
+// LeaveTypeStats aggregates one leave type's requests over a period
+type LeaveTypeStats struct {
+	LeaveTypeID      string
+	LeaveTypeName    string
+	TotalRequests    int64
+	ApprovedRequests int64
+	RejectedRequests int64
+	ApprovedDays     float64 // Sum of working days of approved requests
+	AverageDays      float64 // Mean total days over all requests
+}
+
+// StaleApproval is a waiting_approval request that outlived its company's approval SLA
+type StaleApproval struct {
+	Request          LeaveRequest
+	CompanyID        string
+	SLAHours         int
+	EscalationAction string // company.LeaveEscalationNotify or company.LeaveEscalationAutoApprove
+}
+
 // LeaveQuota entity
 type LeaveQuota struct {
 	ID          string
@@ -189,11 +271,19 @@ type LeaveRequest struct {
 	CancelledAt        *time.Time
 	CancellationReason *string
 
+	// RecurrenceID links the single-day requests created from one recurring submission
+	RecurrenceID *string
+
 	SubmittedAt time.Time
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	Version     int // Incremented by every update, for optimistic locking
+
+	// EscalatedAt is set once the request outlived its company's approval SLA
+	EscalatedAt *time.Time
 
 	// Relationships (for responses)
 	LeaveTypeName *string
 	EmployeeName  *string
+	EmployeeCode  *string
 }
@@ -1,6 +1,9 @@
 package leave
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// General
@@ -22,15 +25,27 @@ var (
 	ErrInsufficientQuota = errors.New("insufficient leave quota")
 
 	// Leave Request errors
-	ErrLeaveRequestNotFound  = errors.New("leave request not found")
-	ErrOverlappingLeave      = errors.New("leave dates overlap with existing request")
-	ErrLeaveAlreadyProcessed = errors.New("leave request already processed")
-	ErrBackdateNotAllowed    = errors.New("backdate leave is not allowed")
-	ErrBackdateTooOld        = errors.New("backdate exceeds maximum allowed days")
-	ErrInsufficientNotice    = errors.New("insufficient notice period")
-	ErrTooFarAdvance         = errors.New("leave date is too far in advance")
-	ErrExceedsMaxDays        = errors.New("leave duration exceeds maximum days per request")
-	ErrAttachmentRequired    = errors.New("attachment is required for this leave type")
+	ErrLeaveRequestNotFound    = errors.New("leave request not found")
+	ErrOverlappingLeave        = errors.New("leave dates overlap with existing request")
+	ErrLeaveAlreadyProcessed   = errors.New("leave request already processed")
+	ErrLeaveRequestClosed      = errors.New("rejected or cancelled leave requests cannot be recomputed")
+	ErrLeaveRequestCancelled   = errors.New("cancelled leave requests cannot be reopened")
+	ErrLeaveRequestNotRejected = errors.New("only rejected leave requests can be reopened")
+	ErrLeaveAlreadyStarted     = errors.New("leave has already started")
+	ErrReopenWindowExpired     = errors.New("leave request was rejected too long ago to be reopened")
+	ErrBackdateNotAllowed      = errors.New("backdate leave is not allowed")
+	ErrBackdateTooOld          = errors.New("backdate exceeds maximum allowed days")
+	ErrInsufficientNotice      = errors.New("insufficient notice period")
+	ErrTooFarAdvance           = errors.New("leave date is too far in advance")
+	ErrExceedsMaxDays          = errors.New("leave duration exceeds maximum days per request")
+	ErrAttachmentRequired      = errors.New("attachment is required for this leave type")
+	ErrAttachmentNotFound      = errors.New("leave request has no attachment")
+	ErrConcurrentModification  = errors.New("leave request was changed by someone else")
+	ErrNoRecurringLeaveDates   = errors.New("no working days match the recurrence pattern")
+	ErrRecurrenceNotFound      = errors.New("recurring leave not found")
+
+	// Calendar feed errors
+	ErrInvalidCalendarFeedToken = errors.New("calendar feed token is invalid or has been regenerated")
 
 	// Eligibility errors
 	ErrNotEligible                = errors.New("employee is not eligible for this leave type")
@@ -46,3 +61,51 @@ var (
 	// Leave Adjustment errors
 	ErrNegativeQuota = errors.New("adjustment would result in negative available quota")
 )
+
+// InsufficientQuotaError reports how far a request exceeds the available balance
+type InsufficientQuotaError struct {
+	Available float64
+	Requested float64
+}
+
+func (e *InsufficientQuotaError) Error() string {
+	return fmt.Sprintf("%s: %.1f day(s) available, %.1f requested", ErrInsufficientQuota.Error(), e.Available, e.Requested)
+}
+
+func (e *InsufficientQuotaError) Unwrap() error {
+	return ErrInsufficientQuota
+}
+
+// Shortfall returns the number of days the request is short by
+func (e *InsufficientQuotaError) Shortfall() float64 {
+	return e.Requested - e.Available
+}
+
+// RecurringLeaveDateError reports which expanded date made a recurring submission fail
+type RecurringLeaveDateError struct {
+	Date string
+	Err  error
+}
+
+func (e *RecurringLeaveDateError) Error() string {
+	return fmt.Sprintf("recurring leave on %s: %s", e.Date, e.Err.Error())
+}
+
+func (e *RecurringLeaveDateError) Unwrap() error {
+	return e.Err
+}
+
+// LeaveAttendanceDateError reports which day's attendance record could not be written
+// while approving a leave request. The approval is rolled back.
+type LeaveAttendanceDateError struct {
+	Date string
+	Err  error
+}
+
+func (e *LeaveAttendanceDateError) Error() string {
+	return fmt.Sprintf("leave attendance on %s: %s", e.Date, e.Err.Error())
+}
+
+func (e *LeaveAttendanceDateError) Unwrap() error {
+	return e.Err
+}
@@ -20,13 +20,18 @@ type LeaveQuotaRepository interface {
 	Create(ctx context.Context, quota LeaveQuota) (LeaveQuota, error)
 	GetByID(ctx context.Context, id string) (LeaveQuota, error)
 	GetByEmployeeTypeYear(ctx context.Context, employeeID, leaveTypeID string, year int) (LeaveQuota, error)
+	// GetByEmployeeTypeYearForUpdate retrieves a quota and locks the row until the surrounding transaction ends
+	GetByEmployeeTypeYearForUpdate(ctx context.Context, employeeID, leaveTypeID string, year int) (LeaveQuota, error)
 	GetByEmployeeYear(ctx context.Context, employeeID string, year int) ([]LeaveQuota, error)
 	GetByEmployee(ctx context.Context, employeeID string) ([]LeaveQuota, error)
 	GetByCompanyID(ctx context.Context, companyID string) ([]LeaveQuota, error)
 	GetByCompanyIDAndYear(ctx context.Context, companyID string, year int) ([]LeaveQuota, error)
 	Update(ctx context.Context, quota UpdateLeaveQuotaRequest) error
-	AddPendingQuota(ctx context.Context, quotaID string, amount float64) error
+	// AddPendingQuota reserves days; without allowNegative it fails with ErrInsufficientQuota when the balance would drop below 0
+	AddPendingQuota(ctx context.Context, quotaID string, amount float64, allowNegative bool) error
 	MovePendingToUsed(ctx context.Context, quotaID string, amount float64) error
+	// AddUsedQuota changes used days by amount, which may be negative
+	AddUsedQuota(ctx context.Context, quotaID string, amount float64) error
 	RemovePendingQuota(ctx context.Context, quotaID string, amount float64) error
 	Delete(ctx context.Context, id string) error
 }
@@ -34,10 +39,38 @@ type LeaveQuotaRepository interface {
 type LeaveRequestRepository interface {
 	Create(ctx context.Context, req LeaveRequest) (LeaveRequest, error)
 	GetByID(ctx context.Context, id string) (LeaveRequest, error)
+	// GetByIDInCompany is GetByID limited to requests of the company's employees.
+	// A request of another company returns ErrLeaveRequestNotFound.
+	GetByIDInCompany(ctx context.Context, id string, companyID string) (LeaveRequest, error)
+	// GetByIDForUpdate is GetByID that also locks the request row until the transaction ends
+	GetByIDForUpdate(ctx context.Context, id string) (LeaveRequest, error)
+	// GetByRecurrenceID returns every request created from one recurring submission, ordered by date
+	GetByRecurrenceID(ctx context.Context, recurrenceID string) ([]LeaveRequest, error)
+	// GetApprovedByEmployeeID returns every approved request of an employee, ordered by start date
+	GetApprovedByEmployeeID(ctx context.Context, employeeID string) ([]LeaveRequest, error)
 	GetByEmployeeID(ctx context.Context, employeeID string, filter LeaveRequestFilter) ([]LeaveRequest, int64, error)
 	GetByCompanyID(ctx context.Context, companyID string, filter LeaveRequestFilter) ([]LeaveRequest, int64, error)
+	// StreamByCompanyID calls fn for every request matching the filter, ignoring pagination
+	StreamByCompanyID(ctx context.Context, companyID string, filter LeaveRequestFilter, fn func(LeaveRequest) error) error
 	GetMyRequests(ctx context.Context, employeeID string, companyID string, filter MyLeaveRequestFilter) ([]LeaveRequest, int64, error)
 	Update(ctx context.Context, request UpdateLeaveRequestRequest) error
+	// Reopen moves a rejected request back to waiting_approval, clearing the rejection and resubmitting it at submittedAt.
+	// It returns pgx.ErrNoRows when the request is no longer rejected.
+	Reopen(ctx context.Context, id string, submittedAt time.Time) error
 	CheckOverlapping(ctx context.Context, employeeID string, startDate, endDate time.Time) (bool, error)
+	// CheckOverlappingExcluding is CheckOverlapping ignoring the request being edited
+	CheckOverlappingExcluding(ctx context.Context, employeeID string, startDate, endDate time.Time, excludeID string) (bool, error)
 	GetMyRequest(ctx context.Context, userID string, companyID string) ([]LeaveRequest, int64, error)
+	// CountPendingApprovals counts waiting_approval requests in a company, optionally limited to a branch,
+	// excluding requests submitted by the approver's own user
+	CountPendingApprovals(ctx context.Context, companyID string, approverUserID string, branchID *string) (int64, error)
+	// GetTypeStats aggregates requests starting in [start, end) per leave type of the company,
+	// optionally limited to employees of one branch and position
+	GetTypeStats(ctx context.Context, companyID string, start, end time.Time, branchID, positionID *string) ([]LeaveTypeStats, error)
+	// GetStaleApprovals returns not yet escalated waiting_approval requests submitted more than
+	// their company's approval SLA before now, oldest first. Companies with an SLA of 0 are skipped.
+	GetStaleApprovals(ctx context.Context, now time.Time) ([]StaleApproval, error)
+	// MarkEscalated stamps a waiting_approval request as escalated at the given time.
+	// It returns pgx.ErrNoRows when the request was already escalated or is no longer waiting.
+	MarkEscalated(ctx context.Context, id string, escalatedAt time.Time) error
 }
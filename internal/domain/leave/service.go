@@ -2,6 +2,7 @@ package leave
 
 import (
 	"context"
+	"io"
 )
 
 type LeaveService interface {
@@ -20,14 +21,35 @@ type LeaveService interface {
 	ListLeaveQuota(ctx context.Context, companyID string) ([]LeaveQuotaResponse, error)
 	DeleteLeaveQuota(ctx context.Context, id string) error
 	AdjustLeaveQuota(ctx context.Context, req AdjustQuotaRequest) error
-	GetMyQuota(ctx context.Context, userID string, year int) ([]LeaveQuotaResponse, error)
+	// PreviewQuota evaluates a leave type's quota rules for an employee without creating a quota
+	PreviewQuota(ctx context.Context, leaveTypeID, employeeID string, year int) (QuotaPreviewResponse, error)
+	GetLeaveTypeStats(ctx context.Context, companyID string, filter LeaveTypeStatsFilter) (LeaveTypeStatsResponse, error)
+	// GetMyQuota returns the employee's quotas for the company's current leave year
+	GetMyQuota(ctx context.Context, employeeID string) ([]LeaveQuotaResponse, error)
 	// Request
 	CreateLeaveRequest(ctx context.Context, req CreateLeaveRequestRequest) (LeaveRequestResponse, error)
-	ApproveLeaveRequest(ctx context.Context, requestID string) error
+	ApproveLeaveRequest(ctx context.Context, requestID string) (ApproveLeaveResponse, error)
 	RejectLeaveRequest(ctx context.Context, req RejectRequestRequest) error
+	CreateRecurringLeaveRequest(ctx context.Context, req CreateRecurringLeaveRequest) (RecurringLeaveResponse, error)
+	ApproveRecurringLeave(ctx context.Context, recurrenceID string) (ApproveRecurringLeaveResponse, error)
+	RejectRecurringLeave(ctx context.Context, req RejectRecurringLeaveRequest) error
 	CancelLeaveRequest(ctx context.Context, requestID string) error
+	ReopenRequest(ctx context.Context, requestID string) (LeaveRequestResponse, error)
+	UpdateMyRequest(ctx context.Context, requestID string, req UpdateMyLeaveRequestRequest) (LeaveRequestResponse, error)
+	RecomputeLeaveRequest(ctx context.Context, requestID string) (RecomputeLeaveRequestResponse, error)
 	ListLeaveRequest(ctx context.Context, companyID string, filter LeaveRequestFilter) (ListLeaveRequestResponse, error)
+	ExportLeaveRequests(ctx context.Context, companyID string, filter LeaveRequestFilter, w io.Writer) error
 	ListMyLeaveRequests(ctx context.Context, employeeID string, companyID string, filter MyLeaveRequestFilter) (ListLeaveRequestResponse, error)
+	// ListEmployeeRequests lists one employee of the caller's company for HR; the employee filters are fixed
+	ListEmployeeRequests(ctx context.Context, employeeID string, filter MyLeaveRequestFilter) (ListLeaveRequestResponse, error)
 	GetMyRequest(ctx context.Context, userID string, companyID string) (ListLeaveRequestResponse, error)
 	GetLeaveRequest(ctx context.Context, requestID string) (LeaveRequestResponse, error)
+	// GetLeaveAttachment opens the attachment of a request the caller may view
+	GetLeaveAttachment(ctx context.Context, requestID string) (LeaveAttachment, error)
+	CountPendingApprovals(ctx context.Context, managerUserID string) (int64, error)
+	// EscalateStaleApprovals escalates requests waiting past their company's approval SLA, returning how many
+	EscalateStaleApprovals(ctx context.Context) (int, error)
+	// Calendar feed
+	RotateCalendarFeedNonce(ctx context.Context, employeeID string) (string, error)
+	GetCalendarFeedRequests(ctx context.Context, employeeID string, nonce string) ([]LeaveRequest, error)
 }
@@ -3,9 +3,11 @@ package branch
 import "errors"
 
 var (
-	ErrBranchNotFound     = errors.New("branch not found")
-	ErrBranchNameExists   = errors.New("branch with this name already exists")
-	ErrBranchesNotFound   = errors.New("no branches found")
-	ErrUnauthorizedAccess = errors.New("unauthorized access to branch")
-	ErrInvalidTimezone    = errors.New("invalid timezone")
+	ErrBranchNotFound        = errors.New("branch not found")
+	ErrBranchNameExists      = errors.New("branch with this name already exists")
+	ErrBranchesNotFound      = errors.New("no branches found")
+	ErrUnauthorizedAccess    = errors.New("unauthorized access to branch")
+	ErrInvalidTimezone       = errors.New("invalid timezone")
+	ErrBranchInUse           = errors.New("branch is still referenced by employees, managers or job history")
+	ErrInvalidReassignTarget = errors.New("reassign target must be another existing branch")
 )
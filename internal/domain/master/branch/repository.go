@@ -8,6 +8,8 @@ type BranchRepository interface {
 	GetByCompanyID(ctx context.Context, companyID string) ([]Branch, error)
 	Update(ctx context.Context, req UpdateBranchRequest) error
 	Delete(ctx context.Context, id string, companyID string) error
+	CountReferences(ctx context.Context, id string, companyID string) (int, error)
+	ReassignReferences(ctx context.Context, fromID, toID string, companyID string) error
 	GetTimezone(ctx context.Context, id string, companyID string) (string, error)
 	GetTimezoneByEmployeeID(ctx context.Context, employeeID string, companyID string) (string, error)
 }
@@ -1,9 +1,14 @@
 package grade
 
-import "github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
+import (
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
+	"github.com/shopspring/decimal"
+)
 
 type CreateGradeRequest struct {
-	Name string `json:"name" validate:"required,max=100"`
+	Name      string           `json:"name" validate:"required,max=100"`
+	MinSalary *decimal.Decimal `json:"min_salary,omitempty"`
+	MaxSalary *decimal.Decimal `json:"max_salary,omitempty"`
 }
 
 func (r *CreateGradeRequest) Validate() error {
@@ -23,6 +28,8 @@ func (r *CreateGradeRequest) Validate() error {
 		})
 	}
 
+	errs = append(errs, validateSalaryBand(r.MinSalary, r.MaxSalary)...)
+
 	if len(errs) > 0 {
 		return errs
 	}
@@ -31,9 +38,11 @@ func (r *CreateGradeRequest) Validate() error {
 }
 
 type UpdateGradeRequest struct {
-	ID        string `json:"id" validate:"required"`
-	CompanyID string `json:"-"` // From JWT
-	Name      string `json:"name" validate:"required,max=100"`
+	ID        string           `json:"id" validate:"required"`
+	CompanyID string           `json:"-"` // From JWT
+	Name      string           `json:"name" validate:"required,max=100"`
+	MinSalary *decimal.Decimal `json:"min_salary,omitempty"`
+	MaxSalary *decimal.Decimal `json:"max_salary,omitempty"`
 }
 
 func (r *UpdateGradeRequest) Validate() error {
@@ -61,6 +70,8 @@ func (r *UpdateGradeRequest) Validate() error {
 		})
 	}
 
+	errs = append(errs, validateSalaryBand(r.MinSalary, r.MaxSalary)...)
+
 	if len(errs) > 0 {
 		return errs
 	}
@@ -69,6 +80,34 @@ func (r *UpdateGradeRequest) Validate() error {
 }
 
 type GradeResponse struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	MinSalary *decimal.Decimal `json:"min_salary"`
+	MaxSalary *decimal.Decimal `json:"max_salary"`
+}
+
+// validateSalaryBand checks that both bounds are non-negative and min does not exceed max
+func validateSalaryBand(minSalary, maxSalary *decimal.Decimal) validator.ValidationErrors {
+	var errs validator.ValidationErrors
+
+	if minSalary != nil && minSalary.IsNegative() {
+		errs = append(errs, validator.ValidationError{
+			Field:   "min_salary",
+			Message: "min_salary must not be negative",
+		})
+	}
+	if maxSalary != nil && maxSalary.IsNegative() {
+		errs = append(errs, validator.ValidationError{
+			Field:   "max_salary",
+			Message: "max_salary must not be negative",
+		})
+	}
+	if minSalary != nil && maxSalary != nil && minSalary.GreaterThan(*maxSalary) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "max_salary",
+			Message: "max_salary must be greater than or equal to min_salary",
+		})
+	}
+
+	return errs
 }
@@ -1,7 +1,23 @@
 package grade
 
+import "github.com/shopspring/decimal"
+
 type Grade struct {
 	ID        string
 	CompanyID string
 	Name      string
+	MinSalary *decimal.Decimal
+	MaxSalary *decimal.Decimal
+}
+
+// SalaryInBand reports whether salary falls within the grade's salary band
+// A missing bound is treated as open-ended
+func (g Grade) SalaryInBand(salary decimal.Decimal) bool {
+	if g.MinSalary != nil && salary.LessThan(*g.MinSalary) {
+		return false
+	}
+	if g.MaxSalary != nil && salary.GreaterThan(*g.MaxSalary) {
+		return false
+	}
+	return true
 }
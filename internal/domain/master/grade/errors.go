@@ -3,8 +3,10 @@ package grade
 import "errors"
 
 var (
-	ErrGradeNotFound      = errors.New("grade not found")
-	ErrGradeNameExists    = errors.New("grade with this name already exists")
-	ErrGradesNotFound     = errors.New("no grades found")
-	ErrUnauthorizedAccess = errors.New("unauthorized access to grade")
+	ErrGradeNotFound         = errors.New("grade not found")
+	ErrGradeNameExists       = errors.New("grade with this name already exists")
+	ErrGradesNotFound        = errors.New("no grades found")
+	ErrUnauthorizedAccess    = errors.New("unauthorized access to grade")
+	ErrGradeInUse            = errors.New("grade is still referenced by employees or job history")
+	ErrInvalidReassignTarget = errors.New("reassign target must be another existing grade")
 )
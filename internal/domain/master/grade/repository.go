@@ -8,4 +8,6 @@ type GradeRepository interface {
 	GetByCompanyID(ctx context.Context, companyID string) ([]Grade, error)
 	Update(ctx context.Context, req UpdateGradeRequest) error
 	Delete(ctx context.Context, id string, companyID string) error
+	CountReferences(ctx context.Context, id string, companyID string) (int, error)
+	ReassignReferences(ctx context.Context, fromID, toID string, companyID string) error
 }
@@ -3,8 +3,10 @@ package position
 import "errors"
 
 var (
-	ErrPositionNotFound   = errors.New("position not found")
-	ErrPositionNameExists = errors.New("position with this name already exists")
-	ErrPositionsNotFound  = errors.New("no positions found")
-	ErrUnauthorizedAccess = errors.New("unauthorized access to position")
+	ErrPositionNotFound      = errors.New("position not found")
+	ErrPositionNameExists    = errors.New("position with this name already exists")
+	ErrPositionsNotFound     = errors.New("no positions found")
+	ErrUnauthorizedAccess    = errors.New("unauthorized access to position")
+	ErrPositionInUse         = errors.New("position is still referenced by employees or job history")
+	ErrInvalidReassignTarget = errors.New("reassign target must be another existing position")
 )
@@ -1,7 +1,10 @@
 package notification
 
 import (
+	"slices"
 	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
 )
 
 // ============= Request DTOs =============
@@ -15,6 +18,11 @@ type CreateNotificationRequest struct {
 	Title       string
 	Message     string
 	Data        map[string]interface{}
+
+	// DedupKey coalesces repeated notifications: while the recipient has an unread notification
+	// with the same key younger than DedupWindow, it is updated in place instead of adding another
+	DedupKey    string
+	DedupWindow time.Duration
 }
 
 // MarkAsReadRequest represents a request to mark notifications as read
@@ -29,6 +37,45 @@ type UpdatePreferenceRequest struct {
 	PushEnabled      bool             `json:"push_enabled"`
 }
 
+// MaxTemplateTitleLength matches the notifications.title column
+const MaxTemplateTitleLength = 255
+
+// UpsertTemplateRequest represents a request to customize a notification type for a company
+type UpsertTemplateRequest struct {
+	Type    NotificationType `json:"-"`
+	Title   string           `json:"title"`
+	Message string           `json:"message"`
+}
+
+// Validate checks the type exists and both templates render with the type's placeholders
+func (r *UpsertTemplateRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if !slices.Contains(AllNotificationTypes(), r.Type) {
+		errs = append(errs, validator.ValidationError{Field: "type", Message: "must be a valid notification type"})
+		return errs
+	}
+
+	if validator.IsEmpty(r.Title) {
+		errs = append(errs, validator.ValidationError{Field: "title", Message: "title is required"})
+	} else if len(r.Title) > MaxTemplateTitleLength {
+		errs = append(errs, validator.ValidationError{Field: "title", Message: "title must not exceed 255 characters"})
+	} else if err := validateTemplate(r.Type, r.Title); err != nil {
+		errs = append(errs, validator.ValidationError{Field: "title", Message: "invalid template: " + err.Error()})
+	}
+
+	if validator.IsEmpty(r.Message) {
+		errs = append(errs, validator.ValidationError{Field: "message", Message: "message is required"})
+	} else if err := validateTemplate(r.Type, r.Message); err != nil {
+		errs = append(errs, validator.ValidationError{Field: "message", Message: "invalid template: " + err.Error()})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
 // ListNotificationsRequest represents a request to list notifications
 type ListNotificationsRequest struct {
 	UserID   string
@@ -67,6 +114,17 @@ type PreferenceResponse struct {
 	PushEnabled      bool             `json:"push_enabled"`
 }
 
+// TemplateResponse represents a company's notification template in API responses.
+// Types without a custom template are listed with empty title and message and is_custom false.
+type TemplateResponse struct {
+	Type         NotificationType `json:"type"`
+	Title        string           `json:"title"`
+	Message      string           `json:"message"`
+	IsCustom     bool             `json:"is_custom"`
+	Placeholders []string         `json:"placeholders"`
+	UpdatedAt    *time.Time       `json:"updated_at,omitempty"`
+}
+
 // UnreadCountResponse represents unread count response
 type UnreadCountResponse struct {
 	UnreadCount int `json:"unread_count"`
@@ -8,17 +8,27 @@ import (
 type NotificationType string
 
 const (
-	TypeAttendanceClockIn      NotificationType = "attendance_clock_in"
-	TypeAttendanceClockOut     NotificationType = "attendance_clock_out"
-	TypeAttendanceAutoClosed   NotificationType = "attendance_auto_closed"
-	TypeAttendanceMarkedAbsent NotificationType = "attendance_marked_absent"
-	TypeLeaveRequest           NotificationType = "leave_request"
-	TypeLeaveApproved          NotificationType = "leave_approved"
-	TypeLeaveRejected          NotificationType = "leave_rejected"
-	TypePayrollGenerated       NotificationType = "payroll_generated"
-	TypeScheduleUpdated        NotificationType = "schedule_updated"
-	TypeInvitationSent         NotificationType = "invitation_sent"
-	TypeEmployeeJoined         NotificationType = "employee_joined"
+	TypeAttendanceClockIn         NotificationType = "attendance_clock_in"
+	TypeAttendanceClockOut        NotificationType = "attendance_clock_out"
+	TypeAttendanceAutoClosed      NotificationType = "attendance_auto_closed"
+	TypeAttendanceMarkedAbsent    NotificationType = "attendance_marked_absent"
+	TypeLeaveRequest              NotificationType = "leave_request"
+	TypeLeaveApproved             NotificationType = "leave_approved"
+	TypeLeaveRejected             NotificationType = "leave_rejected"
+	TypePayrollGenerated          NotificationType = "payroll_generated"
+	TypeScheduleUpdated           NotificationType = "schedule_updated"
+	TypeInvitationSent            NotificationType = "invitation_sent"
+	TypeEmployeeJoined            NotificationType = "employee_joined"
+	TypeTrialEnding               NotificationType = "trial_ending"
+	TypeSubscriptionActivated     NotificationType = "subscription_activated"
+	TypeShiftSwapRequested        NotificationType = "shift_swap_requested"
+	TypeShiftSwapApproved         NotificationType = "shift_swap_approved"
+	TypeShiftSwapRejected         NotificationType = "shift_swap_rejected"
+	TypeSubscriptionStatusChanged NotificationType = "subscription_status_changed"
+	TypeInvoiceExpired            NotificationType = "invoice_expired"
+	TypePaymentFailed             NotificationType = "payment_failed"
+	TypeSeatLimitExceeded         NotificationType = "seat_limit_exceeded"
+	TypeLeaveEscalated            NotificationType = "leave_escalated"
 )
 
 // AllNotificationTypes returns all available notification types
@@ -35,6 +45,16 @@ func AllNotificationTypes() []NotificationType {
 		TypeScheduleUpdated,
 		TypeInvitationSent,
 		TypeEmployeeJoined,
+		TypeTrialEnding,
+		TypeSubscriptionActivated,
+		TypeShiftSwapRequested,
+		TypeShiftSwapApproved,
+		TypeShiftSwapRejected,
+		TypeSubscriptionStatusChanged,
+		TypeInvoiceExpired,
+		TypePaymentFailed,
+		TypeSeatLimitExceeded,
+		TypeLeaveEscalated,
 	}
 }
 
@@ -48,6 +68,7 @@ type Notification struct {
 	Title       string
 	Message     string
 	Data        map[string]interface{}
+	DedupKey    *string
 	IsRead      bool
 	ReadAt      *time.Time
 	CreatedAt   time.Time
@@ -63,3 +84,22 @@ type NotificationPreference struct {
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 }
+
+// NotificationTemplate is a company's custom title and message for a notification type
+type NotificationTemplate struct {
+	ID        string
+	CompanyID string
+	Type      NotificationType
+	Title     string
+	Message   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// OutboxEntry is a notification recorded alongside a business change and awaiting delivery
+type OutboxEntry struct {
+	ID        string
+	Request   CreateNotificationRequest
+	Attempts  int
+	CreatedAt time.Time
+}
@@ -9,4 +9,5 @@ var (
 	ErrInvalidNotificationType = errors.New("invalid notification type")
 	ErrPreferenceNotFound      = errors.New("notification preference not found")
 	ErrQueueFull               = errors.New("notification queue is full")
+	ErrTemplateNotFound        = errors.New("notification template not found")
 )
@@ -2,6 +2,7 @@ package notification
 
 import (
 	"context"
+	"time"
 )
 
 // Repository defines the notification repository interface
@@ -16,9 +17,26 @@ type Repository interface {
 	MarkAllAsRead(ctx context.Context, userID string) error
 	Delete(ctx context.Context, id string, userID string) error
 
+	// Coalescing
+	GetLatestUnreadByDedupKey(ctx context.Context, recipientID string, dedupKey string, since time.Time) (*Notification, error)
+	Coalesce(ctx context.Context, n *Notification) error
+
 	// Preferences
 	GetPreferences(ctx context.Context, userID string) ([]*NotificationPreference, error)
 	GetPreference(ctx context.Context, userID string, notifType NotificationType) (*NotificationPreference, error)
 	UpsertPreference(ctx context.Context, pref *NotificationPreference) error
 	IsNotificationEnabled(ctx context.Context, userID string, notifType NotificationType) (bool, error)
+
+	// Templates
+	GetTemplate(ctx context.Context, companyID string, notifType NotificationType) (*NotificationTemplate, error)
+	ListTemplates(ctx context.Context, companyID string) ([]*NotificationTemplate, error)
+	UpsertTemplate(ctx context.Context, tmpl *NotificationTemplate) (*NotificationTemplate, error)
+	DeleteTemplate(ctx context.Context, companyID string, notifType NotificationType) error
+
+	// Outbox
+	EnqueueOutbox(ctx context.Context, req CreateNotificationRequest) error
+	ClaimOutbox(ctx context.Context, limit int, now time.Time, lease time.Duration) ([]*OutboxEntry, error)
+	MarkOutboxSent(ctx context.Context, id string) error
+	MarkOutboxRetry(ctx context.Context, id string, lastErr string, nextAttemptAt time.Time) error
+	MarkOutboxFailed(ctx context.Context, id string, lastErr string) error
 }
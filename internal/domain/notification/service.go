@@ -10,6 +10,11 @@ type Service interface {
 	QueueNotification(ctx context.Context, req CreateNotificationRequest) error
 	QueueBulkNotification(ctx context.Context, reqs []CreateNotificationRequest) error
 
+	// Outbox (durable delivery: Enqueue joins the transaction carried by ctx, DeliverOutbox sends
+	// what was committed and returns how many notifications were delivered)
+	Enqueue(ctx context.Context, req CreateNotificationRequest) error
+	DeliverOutbox(ctx context.Context) (int, error)
+
 	// Direct operations
 	GetNotifications(ctx context.Context, userID string, page, pageSize int, unreadOnly bool) (*NotificationListResponse, error)
 	GetUnreadCount(ctx context.Context, userID string) (int, error)
@@ -21,6 +26,11 @@ type Service interface {
 	GetPreferences(ctx context.Context, userID string) ([]PreferenceResponse, error)
 	UpdatePreference(ctx context.Context, userID string, req UpdatePreferenceRequest) error
 
+	// Company templates
+	ListTemplates(ctx context.Context, companyID string) ([]TemplateResponse, error)
+	UpsertTemplate(ctx context.Context, companyID string, req UpsertTemplateRequest) (TemplateResponse, error)
+	DeleteTemplate(ctx context.Context, companyID string, notifType NotificationType) error
+
 	// SSE subscription
 	Subscribe(ctx context.Context, userID string) (<-chan SSEEvent, func())
 
@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// TemplatePlaceholders lists the data keys each notification type carries,
+// i.e. the placeholders a company template for that type may use
+var TemplatePlaceholders = map[NotificationType][]string{
+	TypeAttendanceClockIn:         {"employee_id", "employee_name", "attendance_id", "clock_in_time"},
+	TypeAttendanceClockOut:        {"employee_id", "employee_name", "attendance_id", "clock_out_time"},
+	TypeAttendanceAutoClosed:      {"employee_name", "attendance_id", "date"},
+	TypeAttendanceMarkedAbsent:    {"count", "date"},
+	TypeLeaveRequest:              {"employee_id", "employee_name", "leave_request_id", "leave_type", "start_date", "end_date", "total_days"},
+	TypeLeaveApproved:             {"employee_name", "leave_request_id", "leave_type", "start_date", "end_date"},
+	TypeLeaveRejected:             {"employee_name", "leave_request_id", "leave_type", "start_date", "end_date", "reason"},
+	TypePayrollGenerated:          {"payroll_id", "period_month", "period_year", "net_salary"},
+	TypeScheduleUpdated:           {"employee_id", "work_schedule_id", "schedule_name", "start_date"},
+	TypeInvitationSent:            {"company_id", "company_name", "position_name", "employee_id"},
+	TypeEmployeeJoined:            {"employee_id", "employee_name", "position_name"},
+	TypeTrialEnding:               {"subscription_id", "trial_ends_at", "days_left", "checkout_url"},
+	TypeSubscriptionActivated:     {"subscription_id", "plan_name", "period_end"},
+	TypeShiftSwapRequested:        {"shift_swap_id", "from_employee_id", "to_employee_id", "date"},
+	TypeShiftSwapApproved:         {"shift_swap_id", "from_employee_id", "to_employee_id", "date", "status"},
+	TypeShiftSwapRejected:         {"shift_swap_id", "from_employee_id", "to_employee_id", "date", "status"},
+	TypeSubscriptionStatusChanged: {"subscription_id", "old_status", "new_status", "period_end"},
+	TypeInvoiceExpired:            {"invoice_id", "invoice_number", "plan_name", "amount", "checkout_url"},
+	TypePaymentFailed:             {"invoice_id", "invoice_number", "plan_name", "amount", "checkout_url"},
+	TypeSeatLimitExceeded:         {"subscription_id", "max_seats", "active_employees", "excess_seats", "subscription_url"},
+	TypeLeaveEscalated:            {"employee_id", "employee_name", "leave_request_id", "leave_type", "start_date", "end_date", "waiting_hours", "auto_approved"},
+}
+
+// placeholderPattern matches the {{name}} shorthand, which is rewritten to {{.name}} before parsing
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([a-z][a-z0-9_]*)\s*\}\}`)
+
+// knownPlaceholders is the union of TemplatePlaceholders, so keywords such as {{end}} are left alone
+var knownPlaceholders = func() map[string]bool {
+	known := make(map[string]bool)
+	for _, keys := range TemplatePlaceholders {
+		for _, key := range keys {
+			known[key] = true
+		}
+	}
+	return known
+}()
+
+// ParseTemplate parses a title or message template. Both {{employee_name}} and the
+// text/template form {{.employee_name}} are accepted. Missing keys fail at execution.
+func ParseTemplate(text string) (*template.Template, error) {
+	text = placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		if !knownPlaceholders[name] {
+			return match
+		}
+		return "{{." + name + "}}"
+	})
+	return template.New("notification").Option("missingkey=error").Parse(text)
+}
+
+// RenderTemplate executes a template against the notification data
+func RenderTemplate(text string, data map[string]interface{}) (string, error) {
+	tmpl, err := ParseTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// validateTemplate checks a template parses and only uses the placeholders of its notification type
+func validateTemplate(notifType NotificationType, text string) error {
+	sample := make(map[string]interface{})
+	for _, key := range TemplatePlaceholders[notifType] {
+		sample[key] = key
+	}
+	_, err := RenderTemplate(text, sample)
+	return err
+}
@@ -49,10 +49,12 @@ func (r *UpdatePayrollSettingsRequest) Validate() error {
 // ========== COMPONENT DTOs ==========
 
 type CreatePayrollComponentRequest struct {
-	Name        string  `json:"name"`
-	Type        string  `json:"type"` // "allowance" or "deduction"
-	Description *string `json:"description,omitempty"`
-	IsTaxable   *bool   `json:"is_taxable,omitempty"`
+	Name         string  `json:"name"`
+	Type         string  `json:"type"` // "allowance" or "deduction"
+	Description  *string `json:"description,omitempty"`
+	IsTaxable    *bool   `json:"is_taxable,omitempty"`
+	DisplayOrder *int    `json:"display_order,omitempty"` // Payslip position within its type, lowest first; 0 when omitted
+	Category     *string `json:"category,omitempty"`
 }
 
 func (r *CreatePayrollComponentRequest) Validate() error {
@@ -64,6 +66,7 @@ func (r *CreatePayrollComponentRequest) Validate() error {
 	if r.Type != "allowance" && r.Type != "deduction" {
 		errs = append(errs, validator.ValidationError{Field: "type", Message: "must be 'allowance' or 'deduction'"})
 	}
+	errs = append(errs, validateComponentOrder(r.DisplayOrder, r.Category)...)
 
 	if len(errs) > 0 {
 		return errs
@@ -72,21 +75,47 @@ func (r *CreatePayrollComponentRequest) Validate() error {
 }
 
 type UpdatePayrollComponentRequest struct {
-	ID          string
-	Name        *string `json:"name,omitempty"`
-	Description *string `json:"description,omitempty"`
-	IsTaxable   *bool   `json:"is_taxable,omitempty"`
-	IsActive    *bool   `json:"is_active,omitempty"`
+	ID           string
+	Name         *string `json:"name,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	IsTaxable    *bool   `json:"is_taxable,omitempty"`
+	IsActive     *bool   `json:"is_active,omitempty"`
+	DisplayOrder *int    `json:"display_order,omitempty"`
+	Category     *string `json:"category,omitempty"` // Empty string clears the category
+}
+
+func (r *UpdatePayrollComponentRequest) Validate() error {
+	errs := validateComponentOrder(r.DisplayOrder, r.Category)
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateComponentOrder(displayOrder *int, category *string) validator.ValidationErrors {
+	var errs validator.ValidationErrors
+
+	if displayOrder != nil && *displayOrder < 0 {
+		errs = append(errs, validator.ValidationError{Field: "display_order", Message: "must be non-negative"})
+	}
+	if category != nil && len(*category) > 50 {
+		errs = append(errs, validator.ValidationError{Field: "category", Message: "must be at most 50 characters"})
+	}
+
+	return errs
 }
 
 type PayrollComponentResponse struct {
-	ID          string  `json:"id"`
-	CompanyID   string  `json:"company_id"`
-	Name        string  `json:"name"`
-	Type        string  `json:"type"`
-	Description *string `json:"description,omitempty"`
-	IsTaxable   bool    `json:"is_taxable"`
-	IsActive    bool    `json:"is_active"`
+	ID           string  `json:"id"`
+	CompanyID    string  `json:"company_id"`
+	Name         string  `json:"name"`
+	Type         string  `json:"type"`
+	Description  *string `json:"description,omitempty"`
+	IsTaxable    bool    `json:"is_taxable"`
+	IsActive     bool    `json:"is_active"`
+	DisplayOrder int     `json:"display_order"`
+	Category     *string `json:"category,omitempty"`
 }
 
 // ========== EMPLOYEE COMPONENT DTOs ==========
@@ -165,6 +194,22 @@ type UpdatePayrollRecordRequest struct {
 	LateDeductionAmount *decimal.Decimal `json:"late_deduction_amount,omitempty"`
 	OvertimeAmount      *decimal.Decimal `json:"overtime_amount,omitempty"`
 	Notes               *string          `json:"notes,omitempty"`
+	Version             *int             `json:"version" validate:"required"` // version as loaded; a stale value rejects the edit
+}
+
+func (r *UpdatePayrollRecordRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if r.Version == nil {
+		errs = append(errs, validator.ValidationError{Field: "version", Message: "version is required"})
+	} else if *r.Version < 1 {
+		errs = append(errs, validator.ValidationError{Field: "version", Message: "version must be a positive number"})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
 type FinalizePayrollRequest struct {
@@ -198,6 +243,8 @@ type PayrollRecordResponse struct {
 	TotalDeductions           decimal.Decimal            `json:"total_deductions"`
 	AllowancesDetail          map[string]decimal.Decimal `json:"allowances_detail,omitempty"`
 	DeductionsDetail          map[string]decimal.Decimal `json:"deductions_detail,omitempty"`
+	Allowances                []PayrollLineResponse      `json:"allowances"` // Payslip order
+	Deductions                []PayrollLineResponse      `json:"deductions"` // Payslip order
 	TotalWorkDays             int                        `json:"total_work_days"`
 	TotalLateMinutes          int                        `json:"total_late_minutes"`
 	LateDeductionAmount       decimal.Decimal            `json:"late_deduction_amount"`
@@ -210,6 +257,34 @@ type PayrollRecordResponse struct {
 	Status                    string                     `json:"status"`
 	PaidAt                    *string                    `json:"paid_at,omitempty"`
 	Notes                     *string                    `json:"notes,omitempty"`
+	Version                   int                        `json:"version"`
+}
+
+// PayrollLineResponse is one component amount on a payslip
+type PayrollLineResponse struct {
+	Name     string          `json:"name"`
+	Category *string         `json:"category,omitempty"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+// PayrollRecordTotals are the attendance driven figures of a payroll record and the totals they feed into
+type PayrollRecordTotals struct {
+	TotalWorkDays             int             `json:"total_work_days"`
+	TotalLateMinutes          int             `json:"total_late_minutes"`
+	LateDeductionAmount       decimal.Decimal `json:"late_deduction_amount"`
+	TotalEarlyLeaveMinutes    int             `json:"total_early_leave_minutes"`
+	EarlyLeaveDeductionAmount decimal.Decimal `json:"early_leave_deduction_amount"`
+	TotalOvertimeMinutes      int             `json:"total_overtime_minutes"`
+	OvertimeAmount            decimal.Decimal `json:"overtime_amount"`
+	GrossSalary               decimal.Decimal `json:"gross_salary"`
+	NetSalary                 decimal.Decimal `json:"net_salary"`
+}
+
+// RecalculatePayrollRecordResponse shows a record's totals before and after its attendance was re-pulled
+type RecalculatePayrollRecordResponse struct {
+	Before PayrollRecordTotals   `json:"before"`
+	After  PayrollRecordTotals   `json:"after"`
+	Record PayrollRecordResponse `json:"record"`
 }
 
 type PayrollFilter struct {
@@ -244,3 +319,75 @@ type PayrollSummaryResponse struct {
 	DraftCount         int             `json:"draft_count"`
 	PaidCount          int             `json:"paid_count"`
 }
+
+// ========== PAYROLL RUN DTOs ==========
+
+// GeneratePayrollResponse is the run a generation belongs to and the records it created
+type GeneratePayrollResponse struct {
+	Run     PayrollRunResponse      `json:"run"`
+	Records []PayrollRecordResponse `json:"records"`
+}
+
+type PayrollRunResponse struct {
+	ID                 string  `json:"id"`
+	PeriodMonth        int     `json:"period_month"`
+	PeriodYear         int     `json:"period_year"`
+	Status             string  `json:"status"`
+	TotalEmployees     int     `json:"total_employees"`
+	ProcessedEmployees int     `json:"processed_employees"`
+	CreatedRecords     int     `json:"created_records"`
+	ProgressPercent    int     `json:"progress_percent"`
+	LastError          *string `json:"last_error,omitempty"`
+	CreatedBy          *string `json:"created_by,omitempty"`
+	StartedAt          string  `json:"started_at"`
+	CompletedAt        *string `json:"completed_at,omitempty"`
+}
+
+type PayrollRunFilter struct {
+	PeriodMonth *int `json:"period_month,omitempty"`
+	PeriodYear  *int `json:"period_year,omitempty"`
+}
+
+// ========== BANK FILE DTOs ==========
+
+// Bank transfer file formats
+const (
+	// BankFileFormatCSV is a generic CSV any bank or finance tool can map
+	BankFileFormatCSV = "csv"
+	// BankFileFormatBCA is a fixed-width file for BCA bulk transfers between BCA accounts
+	BankFileFormatBCA = "bca"
+)
+
+var BankFileFormats = []string{BankFileFormatCSV, BankFileFormatBCA}
+
+// Reasons an employee is left out of a bank file
+const (
+	BankFileSkipMissingBankDetails   = "missing_bank_details"
+	BankFileSkipInvalidAccountNumber = "invalid_account_number"
+	BankFileSkipUnsupportedBank      = "unsupported_bank"
+	BankFileSkipNonPositiveNetSalary = "non_positive_net_salary"
+)
+
+// BankFileExport is a bank transfer file and what was left out of it. Content is only served as the
+// file download; the JSON form summarizes the file.
+type BankFileExport struct {
+	Format      string                    `json:"format"`
+	Filename    string                    `json:"filename"`
+	ContentType string                    `json:"-"`
+	Content     []byte                    `json:"-"`
+	PeriodMonth int                       `json:"period_month"`
+	PeriodYear  int                       `json:"period_year"`
+	Transfers   int                       `json:"transfers"`
+	TotalAmount decimal.Decimal           `json:"total_amount"`
+	Skipped     []BankFileSkippedEmployee `json:"skipped"`
+}
+
+// BankFileSkippedEmployee is a finalized record that could not be put in the bank file
+type BankFileSkippedEmployee struct {
+	RecordID     string          `json:"record_id"`
+	EmployeeID   string          `json:"employee_id"`
+	EmployeeName string          `json:"employee_name"`
+	EmployeeCode string          `json:"employee_code"`
+	NetSalary    decimal.Decimal `json:"net_salary"`
+	Reason       string          `json:"reason"`
+}
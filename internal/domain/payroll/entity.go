@@ -1,6 +1,7 @@
 package payroll
 
 import (
+	"sort"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -37,8 +38,11 @@ type PayrollComponent struct {
 	Description *string
 	IsTaxable   bool
 	IsActive    bool
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// DisplayOrder positions the component among those of its type on payslips, lowest first
+	DisplayOrder int
+	Category     *string // Optional grouping label, e.g. "Statutory"
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 // EmployeePayrollComponent - Component assignment to employee
@@ -53,8 +57,20 @@ type EmployeePayrollComponent struct {
 	UpdatedAt          time.Time
 
 	// Joined fields
-	ComponentName *string
-	ComponentType *ComponentType
+	ComponentName         *string
+	ComponentType         *ComponentType
+	ComponentCategory     *string
+	ComponentDisplayOrder int
+}
+
+// PayrollLine is one component amount on a payroll record, snapshotted when the record is generated
+// so later changes to the component don't reorder or regroup issued payslips
+type PayrollLine struct {
+	Name         string          `json:"name"`
+	Type         ComponentType   `json:"type"`
+	Category     *string         `json:"category,omitempty"`
+	DisplayOrder int             `json:"display_order"`
+	Amount       decimal.Decimal `json:"amount"`
 }
 
 // PayrollStatus enum
@@ -86,12 +102,15 @@ type PayrollRecord struct {
 	OvertimeAmount            decimal.Decimal
 	GrossSalary               decimal.Decimal
 	NetSalary                 decimal.Decimal
+	ComponentLines            []PayrollLine // Empty for records generated before components had an order
+	PayrollRunID              *string
 	Status                    PayrollStatus
 	PaidAt                    *time.Time
 	PaidBy                    *string
 	Notes                     *string
 	CreatedAt                 time.Time
 	UpdatedAt                 time.Time
+	Version                   int // Incremented by every update, for optimistic locking
 
 	// Joined fields
 	EmployeeName *string
@@ -100,6 +119,33 @@ type PayrollRecord struct {
 	BranchName   *string
 }
 
+// PayrollRunStatus enum
+type PayrollRunStatus string
+
+const (
+	PayrollRunStatusRunning   PayrollRunStatus = "running"
+	PayrollRunStatusCompleted PayrollRunStatus = "completed"
+	PayrollRunStatusFailed    PayrollRunStatus = "failed"
+)
+
+// PayrollRun - One payroll generation for a period, resumed until every employee is processed
+type PayrollRun struct {
+	ID                 string
+	CompanyID          string
+	PeriodMonth        int
+	PeriodYear         int
+	Status             PayrollRunStatus
+	TotalEmployees     int
+	ProcessedEmployees int
+	CreatedRecords     int
+	LastError          *string
+	CreatedBy          *string
+	StartedAt          time.Time
+	CompletedAt        *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
 // AttendanceSummary - Aggregate from attendances table
 type AttendanceSummary struct {
 	EmployeeID             string
@@ -107,4 +153,47 @@ type AttendanceSummary struct {
 	TotalLateMinutes       int
 	TotalEarlyLeaveMinutes int
 	TotalOvertimeMinutes   int
+	PayableOvertimeMinutes decimal.Decimal // Overtime minutes weighted by each day's weekend/holiday multiplier
+}
+
+// Lines returns the record's component amounts of one type in payslip order: by display order, then name.
+// Records without component lines fall back to their detail maps, ordered by name.
+func (r PayrollRecord) Lines(componentType ComponentType) []PayrollLine {
+	var lines []PayrollLine
+	if len(r.ComponentLines) > 0 {
+		for _, line := range r.ComponentLines {
+			if line.Type == componentType {
+				lines = append(lines, line)
+			}
+		}
+	} else {
+		detail := r.AllowancesDetail
+		if componentType == ComponentTypeDeduction {
+			detail = r.DeductionsDetail
+		}
+		for name, amount := range detail {
+			lines = append(lines, PayrollLine{Name: name, Type: componentType, Amount: amount})
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		if lines[i].DisplayOrder != lines[j].DisplayOrder {
+			return lines[i].DisplayOrder < lines[j].DisplayOrder
+		}
+		return lines[i].Name < lines[j].Name
+	})
+
+	return lines
+}
+
+// BankTransferLine is a paid payroll record with the bank details needed to transfer its net salary
+type BankTransferLine struct {
+	RecordID          string
+	EmployeeID        string
+	EmployeeName      string
+	EmployeeCode      string
+	BankName          *string
+	AccountHolderName *string
+	AccountNumber     *string
+	NetSalary         decimal.Decimal
 }
@@ -15,4 +15,8 @@ var (
 	ErrEmployeeComponentNotFound  = errors.New("employee component assignment not found")
 	ErrEmployeeNotFound           = errors.New("employee not found")
 	ErrInvalidComponentType       = errors.New("invalid component type")
+	ErrUnsupportedBankFileFormat  = errors.New("unsupported bank file format")
+	ErrPayrollRunNotFound         = errors.New("payroll run not found")
+	ErrPayrollRunInProgress       = errors.New("payroll generation for this period is already running")
+	ErrConcurrentModification     = errors.New("payroll record was changed by someone else")
 )
@@ -29,9 +29,22 @@ type PayrollRepository interface {
 	GetPayrollRecordByEmployeePeriod(ctx context.Context, employeeID string, month, year int, companyID string) (PayrollRecord, error)
 	ListPayrollRecords(ctx context.Context, companyID string, filter PayrollFilter) ([]PayrollRecord, int64, error)
 	UpdatePayrollRecord(ctx context.Context, companyID string, req UpdatePayrollRecordRequest) error
+	UpdatePayrollRecordAttendance(ctx context.Context, record PayrollRecord) error
 	FinalizePayrollRecords(ctx context.Context, ids []string, paidBy string, companyID string) error
 	DeletePayrollRecord(ctx context.Context, id string, companyID string) error
 
+	// Runs
+	GetOpenPayrollRunForUpdate(ctx context.Context, companyID string, month, year int) (PayrollRun, error)
+	CreatePayrollRun(ctx context.Context, run PayrollRun) (PayrollRun, error)
+	RestartPayrollRun(ctx context.Context, id string, companyID string) (PayrollRun, error)
+	UpdatePayrollRunProgress(ctx context.Context, run PayrollRun) error
+	FinishPayrollRun(ctx context.Context, run PayrollRun) error
+	GetPayrollRunByID(ctx context.Context, id string, companyID string) (PayrollRun, error)
+	ListPayrollRuns(ctx context.Context, companyID string, filter PayrollRunFilter) ([]PayrollRun, error)
+
+	// Bank transfers
+	GetBankTransferLines(ctx context.Context, companyID string, month, year int) ([]BankTransferLine, error)
+
 	// Aggregations
 	GetAttendanceSummary(ctx context.Context, companyID string, month, year int, employeeIDs []string) ([]AttendanceSummary, error)
 	GetPayrollSummary(ctx context.Context, companyID string, month, year int) (PayrollSummaryResponse, error)
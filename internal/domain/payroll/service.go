@@ -21,13 +21,21 @@ type PayrollService interface {
 	RemoveEmployeeComponent(ctx context.Context, id string) error
 
 	// Payroll Generation & Management
-	GeneratePayroll(ctx context.Context, req GeneratePayrollRequest) ([]PayrollRecordResponse, error)
+	GeneratePayroll(ctx context.Context, req GeneratePayrollRequest) (GeneratePayrollResponse, error)
 	GetPayrollRecord(ctx context.Context, id string) (PayrollRecordResponse, error)
 	ListPayrollRecords(ctx context.Context, filter PayrollFilter) (ListPayrollRecordResponse, error)
 	UpdatePayrollRecord(ctx context.Context, req UpdatePayrollRecordRequest) (PayrollRecordResponse, error)
+	RecalculateRecord(ctx context.Context, recordID string) (RecalculatePayrollRecordResponse, error)
 	FinalizePayroll(ctx context.Context, req FinalizePayrollRequest) error
 	DeletePayrollRecord(ctx context.Context, id string) error
 
+	// Runs
+	GetPayrollRun(ctx context.Context, id string) (PayrollRunResponse, error)
+	ListPayrollRuns(ctx context.Context, filter PayrollRunFilter) ([]PayrollRunResponse, error)
+
+	// Bank transfer file of a period's finalized records
+	ExportBankFile(ctx context.Context, companyID string, month, year int, format string) (BankFileExport, error)
+
 	// Summary
 	GetPayrollSummary(ctx context.Context, month, year int) (PayrollSummaryResponse, error)
 }
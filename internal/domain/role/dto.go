@@ -0,0 +1,160 @@
+package role
+
+import (
+	"fmt"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
+)
+
+// RoleResponse represents a company role in API responses
+type RoleResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description *string  `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// PermissionCatalogResponse lists all permissions and the defaults of each base role
+type PermissionCatalogResponse struct {
+	Permissions  []string            `json:"permissions"`
+	Assignable   []string            `json:"assignable"`
+	RoleDefaults map[string][]string `json:"role_defaults"`
+}
+
+// CreateRoleRequest represents request to create a company role
+type CreateRoleRequest struct {
+	Name        string   `json:"name"`
+	Description *string  `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+func (r *CreateRoleRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if validator.IsEmpty(r.Name) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "name",
+			Message: "name is required",
+		})
+	} else if len(r.Name) > 100 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "name",
+			Message: "name must not exceed 100 characters",
+		})
+	}
+
+	errs = append(errs, validatePermissions(r.Permissions)...)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// UpdateRoleRequest represents request to update a company role
+type UpdateRoleRequest struct {
+	ID          string    `json:"-"`
+	Name        *string   `json:"name,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Permissions *[]string `json:"permissions,omitempty"`
+}
+
+func (r *UpdateRoleRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if validator.IsEmpty(r.ID) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "id",
+			Message: "id is required",
+		})
+	}
+
+	if r.Name != nil {
+		if validator.IsEmpty(*r.Name) {
+			errs = append(errs, validator.ValidationError{
+				Field:   "name",
+				Message: "name must not be empty",
+			})
+		} else if len(*r.Name) > 100 {
+			errs = append(errs, validator.ValidationError{
+				Field:   "name",
+				Message: "name must not exceed 100 characters",
+			})
+		}
+	}
+
+	if r.Permissions != nil {
+		errs = append(errs, validatePermissions(*r.Permissions)...)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// AssignRoleRequest attaches a company role to an employee's account
+// A nil RoleID detaches the current role
+type AssignRoleRequest struct {
+	EmployeeID string  `json:"-"`
+	RoleID     *string `json:"role_id"`
+}
+
+func (r *AssignRoleRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if validator.IsEmpty(r.EmployeeID) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "employee_id",
+			Message: "employee_id is required",
+		})
+	}
+
+	if r.RoleID != nil && validator.IsEmpty(*r.RoleID) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "role_id",
+			Message: "role_id must not be empty; use null to remove the role",
+		})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// validatePermissions checks that every permission exists and may be granted through a company role
+func validatePermissions(permissions []string) validator.ValidationErrors {
+	var errs validator.ValidationErrors
+
+	if len(permissions) == 0 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "permissions",
+			Message: "at least one permission is required",
+		})
+		return errs
+	}
+
+	for _, p := range permissions {
+		permission := user.Permission(p)
+		if !user.IsValidPermission(permission) {
+			errs = append(errs, validator.ValidationError{
+				Field:   "permissions",
+				Message: fmt.Sprintf("unknown permission '%s'", p),
+			})
+		} else if !user.IsAssignablePermission(permission) {
+			errs = append(errs, validator.ValidationError{
+				Field:   "permissions",
+				Message: fmt.Sprintf("permission '%s' is reserved for the owner", p),
+			})
+		}
+	}
+
+	return errs
+}
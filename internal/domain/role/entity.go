@@ -0,0 +1,19 @@
+package role
+
+import (
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+)
+
+// Role is a company-defined, named set of permissions attached to users on top of their base role
+// (e.g. a "Payroll Admin" employee)
+type Role struct {
+	ID          string
+	CompanyID   string
+	Name        string
+	Description *string
+	Permissions []user.Permission
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
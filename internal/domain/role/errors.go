@@ -0,0 +1,8 @@
+package role
+
+import "errors"
+
+var (
+	ErrRoleNotFound   = errors.New("role not found")
+	ErrRoleNameExists = errors.New("role with this name already exists")
+)
@@ -0,0 +1,20 @@
+package role
+
+import (
+	"context"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+)
+
+type RoleRepository interface {
+	Create(ctx context.Context, role Role) (Role, error)
+	GetByID(ctx context.Context, id string, companyID string) (Role, error)
+	ListByCompanyID(ctx context.Context, companyID string) ([]Role, error)
+	Update(ctx context.Context, role Role) error
+	Delete(ctx context.Context, id string, companyID string) error
+
+	// AssignToUser attaches a role to a user; a nil roleID detaches it
+	AssignToUser(ctx context.Context, userID string, roleID *string) error
+	// GetPermissionsByUserID returns the permissions of the user's company role (nil if none)
+	GetPermissionsByUserID(ctx context.Context, userID string) ([]user.Permission, error)
+}
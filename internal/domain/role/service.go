@@ -0,0 +1,17 @@
+package role
+
+import "context"
+
+type RoleService interface {
+	// ListPermissions lists every permission and the defaults of each base role
+	ListPermissions(ctx context.Context) PermissionCatalogResponse
+
+	ListRoles(ctx context.Context) ([]RoleResponse, error)
+	GetRole(ctx context.Context, id string) (RoleResponse, error)
+	CreateRole(ctx context.Context, req CreateRoleRequest) (RoleResponse, error)
+	UpdateRole(ctx context.Context, req UpdateRoleRequest) (RoleResponse, error)
+	DeleteRole(ctx context.Context, id string) error
+
+	// AssignRole attaches a company role to an employee's account, or detaches it when RoleID is nil
+	AssignRole(ctx context.Context, req AssignRoleRequest) error
+}
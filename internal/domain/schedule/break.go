@@ -0,0 +1,54 @@
+package schedule
+
+import "time"
+
+// ShiftBreak places a schedule's break on the shift that starts on date. On an overnight shift a
+// break earlier in the day than the clock-in falls after midnight, and a break that itself crosses
+// midnight ends on the following day. ok is false when the schedule has no break.
+func ShiftBreak(date, shiftStart time.Time, breakStart, breakEnd *time.Time, loc *time.Location) (start, end time.Time, ok bool) {
+	if breakStart == nil || breakEnd == nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	at := func(clock time.Time) time.Time {
+		return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	}
+
+	start, end = at(*breakStart), at(*breakEnd)
+	if minuteOfDay(*breakStart) < minuteOfDay(shiftStart) {
+		start = start.AddDate(0, 0, 1)
+		end = end.AddDate(0, 0, 1)
+	}
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	return start, end, true
+}
+
+// WorkedMinutes is the time between clock-in and clock-out less the part of the break
+// [breakStart, breakEnd) it covers, so an employee who works through without clocking out
+// for the break is not credited with it
+func WorkedMinutes(clockIn, clockOut, breakStart, breakEnd time.Time) int {
+	worked := clockOut.Sub(clockIn)
+	if worked <= 0 {
+		return 0
+	}
+
+	overlapStart, overlapEnd := breakStart, breakEnd
+	if clockIn.After(overlapStart) {
+		overlapStart = clockIn
+	}
+	if clockOut.Before(overlapEnd) {
+		overlapEnd = clockOut
+	}
+	if overlapEnd.After(overlapStart) {
+		worked -= overlapEnd.Sub(overlapStart)
+	}
+
+	return int(worked.Minutes())
+}
+
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
@@ -1,6 +1,7 @@
 package schedule
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -11,6 +12,8 @@ type CreateWorkScheduleRequest struct {
 	Name               string `json:"name"`
 	Type               string `json:"type"`
 	GracePeriodMinutes *int   `json:"grace_period_minutes"`
+	// EarliestClockInMinutes limits how early employees may clock in; omitted or 0 means no limit
+	EarliestClockInMinutes *int `json:"earliest_clock_in_minutes,omitempty"`
 }
 
 func (r *CreateWorkScheduleRequest) Validate() error {
@@ -46,6 +49,12 @@ func (r *CreateWorkScheduleRequest) Validate() error {
 			Message: "grace_period_minutes must be a non-negative number",
 		})
 	}
+	if r.EarliestClockInMinutes != nil && *r.EarliestClockInMinutes < 0 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "earliest_clock_in_minutes",
+			Message: "earliest_clock_in_minutes must be a non-negative number",
+		})
+	}
 
 	if len(errs) > 0 {
 		return errs
@@ -55,16 +64,17 @@ func (r *CreateWorkScheduleRequest) Validate() error {
 }
 
 type WorkScheduleResponse struct {
-	ID                 string                         `json:"id"`
-	CompanyID          string                         `json:"company_id"`
-	Name               string                         `json:"name"`
-	Type               string                         `json:"type"`
-	GracePeriodMinutes int                            `json:"grace_period_minutes"`
-	Times              []WorkScheduleTimeResponse     `json:"times,omitempty"`
-	Locations          []WorkScheduleLocationResponse `json:"locations,omitempty"`
-	CreatedAt          string                         `json:"created_at"`
-	UpdatedAt          string                         `json:"updated_at"`
-	DeletedAt          *string                        `json:"deleted_at,omitempty"`
+	ID                     string                         `json:"id"`
+	CompanyID              string                         `json:"company_id"`
+	Name                   string                         `json:"name"`
+	Type                   string                         `json:"type"`
+	GracePeriodMinutes     int                            `json:"grace_period_minutes"`
+	EarliestClockInMinutes int                            `json:"earliest_clock_in_minutes"`
+	Times                  []WorkScheduleTimeResponse     `json:"times,omitempty"`
+	Locations              []WorkScheduleLocationResponse `json:"locations,omitempty"`
+	CreatedAt              string                         `json:"created_at"`
+	UpdatedAt              string                         `json:"updated_at"`
+	DeletedAt              *string                        `json:"deleted_at,omitempty"`
 }
 
 // ListWorkScheduleResponse - Enhanced with pagination metadata
@@ -350,6 +360,12 @@ func (r *CreateWorkScheduleTimeRequest) Validate() error {
 					})
 				}
 			}
+
+			if r.IsNextDayCheckout != nil {
+				if err := validateBreakLength(r.ClockInTime, r.ClockOutTime, *r.BreakStartTime, *r.BreakEndTime, *r.IsNextDayCheckout); err != nil {
+					errs = append(errs, *err)
+				}
+			}
 		}
 	}
 
@@ -388,6 +404,64 @@ type WorkScheduleTimeResponse struct {
 	UpdatedAt         string  `json:"updated_at"` // ISO 8601 format
 }
 
+// WeeklyScheduleResponse lays a work schedule's times out over Monday to Sunday
+type WeeklyScheduleResponse struct {
+	WorkScheduleID string              `json:"work_schedule_id"`
+	Name           string              `json:"name"`
+	Type           string              `json:"type"`
+	Days           []WeeklyScheduleDay `json:"days"` // Always seven, Monday first
+}
+
+// WeeklyScheduleDay is one weekday of a weekly schedule view; days without times are off
+type WeeklyScheduleDay struct {
+	DayOfWeek    int                        `json:"day_of_week"` // 1=Monday, ..., 7=Sunday
+	DayName      string                     `json:"day_name"`
+	IsOff        bool                       `json:"is_off"`
+	LocationType *string                    `json:"location_type,omitempty"`
+	Times        []WorkScheduleTimeResponse `json:"times"`
+}
+
+// validateBreakLength checks that a break lasts at least a minute and is shorter than the shift it
+// sits in. Overnight shifts and breaks spanning midnight wrap around to the next day; same-day breaks
+// that are empty or run backwards are left to the ordering checks.
+func validateBreakLength(clockIn, clockOut, breakStart, breakEnd string, isNextDayCheckout bool) *validator.ValidationError {
+	in, validIn := validator.IsValidTime(clockIn)
+	out, validOut := validator.IsValidTime(clockOut)
+	start, validStart := validator.IsValidTime(breakStart)
+	end, validEnd := validator.IsValidTime(breakEnd)
+	if !validIn || !validOut || !validStart || !validEnd {
+		return nil
+	}
+
+	shift := out.Sub(in)
+	breakLength := end.Sub(start)
+	if isNextDayCheckout {
+		if shift <= 0 {
+			shift += 24 * time.Hour
+		}
+		if breakLength < 0 {
+			breakLength += 24 * time.Hour
+		}
+	} else if breakLength <= 0 {
+		return nil
+	}
+
+	if breakLength < time.Minute {
+		return &validator.ValidationError{
+			Field:   "break_times",
+			Message: "break must last at least 1 minute",
+		}
+	}
+	if breakLength >= shift {
+		return &validator.ValidationError{
+			Field:   "break_times",
+			Message: fmt.Sprintf("break (%d minutes) must be shorter than the shift (%d minutes)", int(breakLength.Minutes()), int(shift.Minutes())),
+		}
+	}
+
+	return nil
+}
+
 type CreateWorkScheduleLocationRequest struct {
 	WorkScheduleID string  `json:"work_schedule_id"`
 	LocationName   string  `json:"location_name"`
@@ -507,12 +581,41 @@ type EmployeeScheduleAssignmentResponse struct {
 	UpdatedAt      string `json:"updated_at"` // ISO 8601 format
 }
 
+// ClearScheduleResponse reports how many times or locations were removed from a work schedule
+type ClearScheduleResponse struct {
+	WorkScheduleID string `json:"work_schedule_id"`
+	Deleted        int64  `json:"deleted"`
+}
+
+// CloneWorkScheduleRequest names the copy of an existing work schedule
+type CloneWorkScheduleRequest struct {
+	Name string `json:"name"`
+}
+
+func (r *CloneWorkScheduleRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if validator.IsEmpty(r.Name) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "name",
+			Message: "name is required",
+		})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
 type UpdateWorkScheduleRequest struct {
-	ID                 string  `json:"id"`
-	CompanyID          string  `json:"-"`
-	Name               *string `json:"name,omitempty"`
-	Type               *string `json:"type,omitempty"`
-	GracePeriodMinutes *int    `json:"grace_period_minutes,omitempty"`
+	ID                     string  `json:"id"`
+	CompanyID              string  `json:"-"`
+	Name                   *string `json:"name,omitempty"`
+	Type                   *string `json:"type,omitempty"`
+	GracePeriodMinutes     *int    `json:"grace_period_minutes,omitempty"`
+	EarliestClockInMinutes *int    `json:"earliest_clock_in_minutes,omitempty"` // 0 removes the limit
 }
 
 func (r *UpdateWorkScheduleRequest) Validate() error {
@@ -549,6 +652,12 @@ func (r *UpdateWorkScheduleRequest) Validate() error {
 			Message: "grace_period_minutes must be a non-negative number",
 		})
 	}
+	if r.EarliestClockInMinutes != nil && *r.EarliestClockInMinutes < 0 {
+		errs = append(errs, validator.ValidationError{
+			Field:   "earliest_clock_in_minutes",
+			Message: "earliest_clock_in_minutes must be a non-negative number",
+		})
+	}
 
 	if len(errs) > 0 {
 		return errs
@@ -716,6 +825,12 @@ func (r *UpdateWorkScheduleTimeRequest) Validate() error {
 					})
 				}
 			}
+
+			if r.IsNextDayCheckout != nil {
+				if err := validateBreakLength(r.ClockInTime, r.ClockOutTime, *r.BreakStartTime, *r.BreakEndTime, *r.IsNextDayCheckout); err != nil {
+					errs = append(errs, *err)
+				}
+			}
 		}
 	}
 
@@ -908,19 +1023,25 @@ func (r *AssignScheduleRequest) Validate() error {
 }
 
 type AssignScheduleResponse struct {
-	EmployeeScheduleAssignmentID *string `json:"employee_schedule_assignment_id,omitempty"`
-	WorkScheduleID               string  `json:"work_schedule_id"`
-	EmployeeID                   string  `json:"employee_id"`
-	StartDate                    *string `json:"start_date,omitempty"`
-	EndDate                      *string `json:"end_date,omitempty"`
-	CreatedAt                    *string `json:"created_at,omitempty"`
-	UpdatedAt                    *string `json:"updated_at,omitempty"`
+	EmployeeScheduleAssignmentID *string  `json:"employee_schedule_assignment_id,omitempty"`
+	WorkScheduleID               string   `json:"work_schedule_id"`
+	EmployeeID                   string   `json:"employee_id"`
+	StartDate                    *string  `json:"start_date,omitempty"`
+	EndDate                      *string  `json:"end_date,omitempty"`
+	CreatedAt                    *string  `json:"created_at,omitempty"`
+	UpdatedAt                    *string  `json:"updated_at,omitempty"`
+	Warnings                     []string `json:"warnings,omitempty"`
 }
 
 // ========================================
 // EMPLOYEE SCHEDULE TIMELINE DTOs
 // ========================================
 
+// Supported schedule timeline export formats
+const (
+	ExportFormatCSV = "csv"
+)
+
 type EmployeeScheduleTimelineFilter struct {
 	Page  int `json:"page"`
 	Limit int `json:"limit"`
@@ -1005,6 +1126,7 @@ type ScheduleActions struct {
 
 // ScheduleLocation untuk parsing JSON lokasi
 type ScheduleLocation struct {
+	ID           string  `json:"id"`
 	Name         string  `json:"name"`
 	Latitude     float64 `json:"latitude"`
 	Longitude    float64 `json:"longitude"`
@@ -1013,13 +1135,152 @@ type ScheduleLocation struct {
 
 // Domain Model (Output bersih untuk Service)
 type ActiveSchedule struct {
-	ScheduleID         string
-	ScheduleName       string
-	LocationType       string
-	GracePeriodMinutes int
-	TimeID             string
-	ClockIn            time.Time
-	ClockOut           time.Time
-	IsNextDayCheckout  bool
-	Locations          []ScheduleLocation
+	ScheduleID             string
+	ScheduleName           string
+	LocationType           string
+	GracePeriodMinutes     int
+	EarliestClockInMinutes int
+	TimeID                 string
+	ClockIn                time.Time
+	ClockOut               time.Time
+	BreakStart             *time.Time
+	BreakEnd               *time.Time
+	IsNextDayCheckout      bool
+	Locations              []ScheduleLocation
+}
+
+// CreateShiftSwapRequest asks to swap the caller's schedule with another employee for one date
+type CreateShiftSwapRequest struct {
+	ToEmployeeID string `json:"to_employee_id"`
+	Date         string `json:"date"`
+}
+
+func (r *CreateShiftSwapRequest) Validate() error {
+	var errs validator.ValidationErrors
+
+	if validator.IsEmpty(r.ToEmployeeID) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "to_employee_id",
+			Message: "to_employee_id is required",
+		})
+	}
+	if validator.IsEmpty(r.Date) {
+		errs = append(errs, validator.ValidationError{
+			Field:   "date",
+			Message: "date is required",
+		})
+	} else if _, err := time.Parse("2006-01-02", r.Date); err != nil {
+		errs = append(errs, validator.ValidationError{
+			Field:   "date",
+			Message: "date must be in YYYY-MM-DD format",
+		})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+type RejectShiftSwapRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ShiftSwapFilter struct {
+	Status     *string
+	EmployeeID *string // Matches either side of the swap
+}
+
+type ShiftSwapResponse struct {
+	ID               string  `json:"id"`
+	FromEmployeeID   string  `json:"from_employee_id"`
+	FromEmployeeName *string `json:"from_employee_name,omitempty"`
+	ToEmployeeID     string  `json:"to_employee_id"`
+	ToEmployeeName   *string `json:"to_employee_name,omitempty"`
+	Date             string  `json:"date"`
+	Status           string  `json:"status"`
+	ReviewedBy       *string `json:"reviewed_by,omitempty"`
+	ReviewedAt       *string `json:"reviewed_at,omitempty"`
+	RejectionReason  *string `json:"rejection_reason,omitempty"`
+	CreatedAt        string  `json:"created_at"`
+}
+
+// MaxScheduleRangeDays caps how many days GET /employee-schedules/my resolves at once
+const MaxScheduleRangeDays = 92
+
+// MyScheduleRangeFilter selects the days whose effective schedule an employee wants to see
+type MyScheduleRangeFilter struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+func (f *MyScheduleRangeFilter) Validate() error {
+	var errs validator.ValidationErrors
+
+	start, startValid := validator.IsValidDate(f.StartDate)
+	if !startValid {
+		errs = append(errs, validator.ValidationError{
+			Field:   "start_date",
+			Message: "start_date is required in YYYY-MM-DD format",
+		})
+	}
+	end, endValid := validator.IsValidDate(f.EndDate)
+	if !endValid {
+		errs = append(errs, validator.ValidationError{
+			Field:   "end_date",
+			Message: "end_date is required in YYYY-MM-DD format",
+		})
+	}
+
+	if startValid && endValid {
+		if end.Before(start) {
+			errs = append(errs, validator.ValidationError{
+				Field:   "end_date",
+				Message: "end_date must not be before start_date",
+			})
+		} else if int(end.Sub(start).Hours()/24)+1 > MaxScheduleRangeDays {
+			errs = append(errs, validator.ValidationError{
+				Field:   "end_date",
+				Message: fmt.Sprintf("range must not exceed %d days", MaxScheduleRangeDays),
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Where the schedule of a MySchedulePeriod comes from
+const (
+	ScheduleSourceOverride = "override" // a dated employee schedule assignment
+	ScheduleSourceDefault  = "default"  // the employee's own work schedule
+	ScheduleSourceNone     = "none"     // no schedule applies
+)
+
+// MySchedulePeriod is a run of consecutive days with the same effective schedule.
+// Days the schedule has no working time for are returned with is_working_day false.
+type MySchedulePeriod struct {
+	StartDate         string  `json:"start_date"`
+	EndDate           string  `json:"end_date"`
+	Days              int     `json:"days"`
+	Source            string  `json:"source"`
+	WorkScheduleID    *string `json:"work_schedule_id"`
+	WorkScheduleName  *string `json:"work_schedule_name"`
+	IsWorkingDay      bool    `json:"is_working_day"`
+	ClockInTime       *string `json:"clock_in_time"`
+	ClockOutTime      *string `json:"clock_out_time"`
+	BreakStartTime    *string `json:"break_start_time"`
+	BreakEndTime      *string `json:"break_end_time"`
+	IsNextDayCheckout bool    `json:"is_next_day_checkout"`
+	LocationType      *string `json:"location_type"`
+}
+
+// MyScheduleRangeResponse lists the effective schedule of every day in the requested range
+type MyScheduleRangeResponse struct {
+	StartDate string             `json:"start_date"`
+	EndDate   string             `json:"end_date"`
+	Periods   []MySchedulePeriod `json:"periods"`
 }
@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
+)
+
+func TestWorkScheduleTimeBreakValidation(t *testing.T) {
+	tests := []struct {
+		name           string
+		clockIn        string
+		clockOut       string
+		nextDay        bool
+		breakStart     string
+		breakEnd       string
+		wantBreakError string // substring of the break error message, empty when valid
+	}{
+		{name: "lunch break", clockIn: "08:00", clockOut: "17:00", breakStart: "12:00", breakEnd: "13:00"},
+		{name: "one minute break", clockIn: "08:00", clockOut: "17:00", breakStart: "12:00", breakEnd: "12:01"},
+		{name: "empty break", clockIn: "08:00", clockOut: "17:00", breakStart: "12:00", breakEnd: "12:00", wantBreakError: "break_start_time must be before break_end_time"},
+		{name: "break runs backwards", clockIn: "08:00", clockOut: "17:00", breakStart: "13:00", breakEnd: "12:00", wantBreakError: "break_start_time must be before break_end_time"},
+		{name: "break as long as the shift", clockIn: "08:00", clockOut: "17:00", breakStart: "08:00", breakEnd: "17:00", wantBreakError: "must be shorter than the shift (540 minutes)"},
+		{name: "break after clock out", clockIn: "08:00", clockOut: "17:00", breakStart: "16:30", breakEnd: "17:30", wantBreakError: "break_end_time must be before clock_out_time"},
+		{name: "overnight break before midnight", clockIn: "22:00", clockOut: "06:00", nextDay: true, breakStart: "23:00", breakEnd: "23:30"},
+		{name: "overnight break after midnight", clockIn: "22:00", clockOut: "06:00", nextDay: true, breakStart: "01:00", breakEnd: "02:00"},
+		{name: "overnight break spanning midnight", clockIn: "22:00", clockOut: "06:00", nextDay: true, breakStart: "23:30", breakEnd: "00:30"},
+		{name: "overnight empty break", clockIn: "22:00", clockOut: "06:00", nextDay: true, breakStart: "23:00", breakEnd: "23:00", wantBreakError: "break must last at least 1 minute"},
+		{name: "overnight break as long as the shift", clockIn: "22:00", clockOut: "06:00", nextDay: true, breakStart: "22:00", breakEnd: "06:00", wantBreakError: "must be shorter than the shift (480 minutes)"},
+		{name: "overnight break wrapping past the shift", clockIn: "22:00", clockOut: "06:00", nextDay: true, breakStart: "23:00", breakEnd: "22:30", wantBreakError: "must be shorter than the shift"},
+		{name: "24 hour shift", clockIn: "08:00", clockOut: "08:00", nextDay: true, breakStart: "12:00", breakEnd: "13:00"},
+		{name: "24 hour shift with a 24 hour break", clockIn: "08:00", clockOut: "08:00", nextDay: true, breakStart: "08:00", breakEnd: "08:00", wantBreakError: "break must last at least 1 minute"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day := 1
+			nextDay := tt.nextDay
+			breakStart, breakEnd := tt.breakStart, tt.breakEnd
+
+			requests := map[string]interface{ Validate() error }{
+				"create": &CreateWorkScheduleTimeRequest{
+					WorkScheduleID: "ws-1", DayOfWeek: &day, ClockInTime: tt.clockIn, ClockOutTime: tt.clockOut,
+					IsNextDayCheckout: &nextDay, BreakStartTime: &breakStart, BreakEndTime: &breakEnd, LocationType: "WFO",
+				},
+				"update": &UpdateWorkScheduleTimeRequest{
+					ID: "wst-1", DayOfWeek: &day, ClockInTime: tt.clockIn, ClockOutTime: tt.clockOut,
+					IsNextDayCheckout: &nextDay, BreakStartTime: &breakStart, BreakEndTime: &breakEnd, LocationType: "WFO",
+				},
+			}
+
+			for kind, req := range requests {
+				err := req.Validate()
+				if tt.wantBreakError == "" {
+					if err != nil {
+						t.Errorf("%s Validate() error = %v, want nil", kind, err)
+					}
+					continue
+				}
+
+				var errs validator.ValidationErrors
+				if !errors.As(err, &errs) {
+					t.Fatalf("%s Validate() error = %v, want validation errors", kind, err)
+				}
+				found := false
+				for _, e := range errs {
+					if strings.HasPrefix(e.Field, "break_") && strings.Contains(e.Message, tt.wantBreakError) {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("%s Validate() error = %v, want a break error containing %q", kind, err, tt.wantBreakError)
+				}
+			}
+		})
+	}
+}
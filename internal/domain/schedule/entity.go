@@ -8,9 +8,11 @@ type WorkSchedule struct {
 	Name               string
 	Type               WorkArrangement
 	GracePeriodMinutes int
-	CreatedAt          time.Time
-	UpdatedAt          time.Time
-	DeletedAt          *time.Time
+	// EarliestClockInMinutes is how long before the scheduled clock-in employees may clock in (0 means no limit)
+	EarliestClockInMinutes int
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+	DeletedAt              *time.Time
 
 	Times     []WorkScheduleTime
 	Locations []WorkScheduleLocation
@@ -30,6 +32,10 @@ var WorkArrangementValues = []string{
 	string(WorkArrangementHybrid),
 }
 
+// DefaultWorkingDays are the weekdays (1=Monday, ..., 5=Friday) a schedule is expected to cover.
+// Companies do not configure their working week yet.
+var DefaultWorkingDays = []int{1, 2, 3, 4, 5}
+
 type WorkScheduleTime struct {
 	ID                string
 	WorkScheduleID    string
@@ -64,3 +70,31 @@ type EmployeeScheduleAssignment struct {
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
+
+type ShiftSwapStatus string
+
+const (
+	ShiftSwapStatusPending  ShiftSwapStatus = "pending"
+	ShiftSwapStatusApproved ShiftSwapStatus = "approved"
+	ShiftSwapStatusRejected ShiftSwapStatus = "rejected"
+)
+
+// ShiftSwapRequest is a request by one employee to swap schedules with another for a single date
+type ShiftSwapRequest struct {
+	ID              string
+	CompanyID       string
+	FromEmployeeID  string
+	ToEmployeeID    string
+	SwapDate        time.Time
+	Status          ShiftSwapStatus
+	RequestedBy     *string
+	ReviewedBy      *string
+	ReviewedAt      *time.Time
+	RejectionReason *string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+
+	// DTO
+	FromEmployeeName *string
+	ToEmployeeName   *string
+}
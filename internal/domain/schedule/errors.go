@@ -7,23 +7,39 @@ var (
 	ErrWorkScheduleNotFound       = errors.New("work schedule not found")
 	ErrWorkScheduleNameExists     = errors.New("work schedule with this name already exists")
 	ErrWorkScheduleAlreadyDeleted = errors.New("work schedule not found or already deleted")
+	ErrWorkScheduleInUse          = errors.New("work schedule is assigned to employees")
 
 	// Work Schedule Time Errors
 	ErrWorkScheduleTimeNotFound = errors.New("work schedule time not found")
 	ErrWorkScheduleTimeExists   = errors.New("work schedule time already exists")
 	ErrInvalidLocationType      = errors.New("invalid location type for work schedule")
 	ErrMismatchedLocationType   = errors.New("mismatched location type for work schedule")
+	ErrWorkScheduleTimeInUse    = errors.New("work schedule time is referenced by attendance records")
 
 	// Work Schedule Location Errors
 	ErrWorkScheduleLocationNotFound = errors.New("work schedule location not found")
 	ErrInvalidWorkScheduleType      = errors.New("work schedule type must be 'WFO' 'Hybrid' or match the required location type")
 
+	// Assignment Precondition Errors
+	ErrIncompleteSchedule = errors.New("work schedule has no working times configured")
+
 	// Employee Schedule Assignment Errors
 	ErrEmployeeScheduleAssignmentNotFound = errors.New("employee schedule assignment not found")
 	ErrOverlappingScheduleAssignment      = errors.New("overlapping schedule assignment detected")
+	ErrAssignmentOutsideEmployment        = errors.New("schedule assignment falls outside the employee's employment period")
+
+	// Shift Swap Errors
+	ErrShiftSwapNotFound         = errors.New("shift swap request not found")
+	ErrShiftSwapSameEmployee     = errors.New("cannot swap a shift with yourself")
+	ErrShiftSwapSameSchedule     = errors.New("both employees already have the same schedule on this date")
+	ErrShiftSwapOverlap          = errors.New("shift swap would overlap an existing schedule assignment")
+	ErrShiftSwapDateInPast       = errors.New("shift swap date must not be in the past")
+	ErrShiftSwapAlreadyRequested = errors.New("a pending shift swap already exists for this date")
+	ErrShiftSwapAlreadyProcessed = errors.New("shift swap request has already been approved or rejected")
 
 	// Employee Schedule Timeline Errors
 	ErrEmployeeScheduleTimelineNotFound = errors.New("employee schedule timeline not found")
+	ErrUnsupportedExportFormat          = errors.New("unsupported export format")
 
 	// Validation Errors
 	ErrEmployeeIDRequired = errors.New("employee ID is required")
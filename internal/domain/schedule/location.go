@@ -0,0 +1,26 @@
+package schedule
+
+import "github.com/cmlabs-hris/hris-backend-go/internal/pkg/utils"
+
+// LocationMatch is the schedule location closest to a set of coordinates
+type LocationMatch struct {
+	Location       ScheduleLocation
+	DistanceMeters float64
+	WithinRadius   bool
+}
+
+// NearestLocation picks the location closest to lat/lon by haversine distance and reports
+// whether the coordinates fall inside its radius. ok is false when there are no locations.
+func NearestLocation(locations []ScheduleLocation, lat, lon float64) (match LocationMatch, ok bool) {
+	for i, location := range locations {
+		distance := utils.CalculateHaversineDistance(lat, lon, location.Latitude, location.Longitude)
+		if i == 0 || distance < match.DistanceMeters {
+			match = LocationMatch{
+				Location:       location,
+				DistanceMeters: distance,
+				WithinRadius:   distance <= float64(location.RadiusMeters),
+			}
+		}
+	}
+	return match, len(locations) > 0
+}
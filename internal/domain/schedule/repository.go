@@ -14,6 +14,9 @@ type WorkScheduleRepository interface {
 	SoftDelete(ctx context.Context, id, companyID string) error
 	GetEmployeeScheduleTimeline(ctx context.Context, employeeID, companyID string, page, limit int) ([]EmployeeScheduleTimelineItem, int64, string, error)
 	GetActiveSchedule(ctx context.Context, employeeID string, date time.Time, companyID string) (*ActiveSchedule, error)
+	// CountActiveAssignments counts active employees using the schedule as their default plus
+	// assignments that have not ended yet
+	CountActiveAssignments(ctx context.Context, id, companyID string) (int, error)
 }
 
 type WorkScheduleTimeRepository interface {
@@ -23,6 +26,7 @@ type WorkScheduleTimeRepository interface {
 	GetTimeByScheduleAndDay(ctx context.Context, scheduleID string, dayOfWeek int, companyID string) (WorkScheduleTime, error)
 	Update(ctx context.Context, req UpdateWorkScheduleTimeRequest) error
 	Delete(ctx context.Context, id, companyID string) error
+	BulkDeleteByWorkScheduleID(ctx context.Context, workScheduleID, companyID string) (int64, error)
 }
 
 type WorkScheduleLocationRepository interface {
@@ -31,7 +35,7 @@ type WorkScheduleLocationRepository interface {
 	GetByWorkScheduleID(ctx context.Context, workScheduleID, companyID string) ([]WorkScheduleLocation, error)
 	Update(ctx context.Context, req UpdateWorkScheduleLocationRequest) error
 	Delete(ctx context.Context, id, companyID string) error
-	BulkDeleteByWorkScheduleID(ctx context.Context, workScheduleID, companyID string) error
+	BulkDeleteByWorkScheduleID(ctx context.Context, workScheduleID, companyID string) (int64, error)
 }
 
 type EmployeeScheduleAssignmentRepository interface {
@@ -44,3 +48,11 @@ type EmployeeScheduleAssignmentRepository interface {
 	Delete(ctx context.Context, id string, companyID string) error
 	DeleteFutureAssignments(ctx context.Context, startDate time.Time, employeeID, companyID string) error
 }
+
+type ShiftSwapRepository interface {
+	Create(ctx context.Context, swap ShiftSwapRequest) (ShiftSwapRequest, error)
+	GetByID(ctx context.Context, id string, companyID string) (ShiftSwapRequest, error)
+	List(ctx context.Context, companyID string, filter ShiftSwapFilter) ([]ShiftSwapRequest, error)
+	// Review records the decision on a pending swap; returns ErrShiftSwapAlreadyProcessed if it is no longer pending
+	Review(ctx context.Context, swap ShiftSwapRequest) error
+}
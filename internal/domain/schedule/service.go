@@ -12,18 +12,24 @@ type ScheduleService interface {
 	ListWorkSchedules(ctx context.Context, filter WorkScheduleFilter) (ListWorkScheduleResponse, error)
 	UpdateWorkSchedule(ctx context.Context, req UpdateWorkScheduleRequest) error
 	DeleteWorkSchedule(ctx context.Context, id string) error
+	CloneWorkSchedule(ctx context.Context, sourceID string, newName string) (WorkScheduleResponse, error)
+	GetWeeklyView(ctx context.Context, scheduleID string) (WeeklyScheduleResponse, error)
 
 	// Work Schedule Time
 	CreateWorkScheduleTime(ctx context.Context, req CreateWorkScheduleTimeRequest) (WorkScheduleTimeResponse, error)
 	GetWorkScheduleTime(ctx context.Context, id string) (WorkScheduleTimeResponse, error)
 	UpdateWorkScheduleTime(ctx context.Context, req UpdateWorkScheduleTimeRequest) error
 	DeleteWorkScheduleTime(ctx context.Context, id string) error
+	// ClearScheduleTimes deletes every time of a schedule; force allows it while the schedule is assigned
+	ClearScheduleTimes(ctx context.Context, scheduleID string, force bool) (ClearScheduleResponse, error)
 
 	// Work Schedule Location
 	CreateWorkScheduleLocation(ctx context.Context, req CreateWorkScheduleLocationRequest) (WorkScheduleLocationResponse, error)
 	GetWorkScheduleLocation(ctx context.Context, id string) (WorkScheduleLocationResponse, error)
 	UpdateWorkScheduleLocation(ctx context.Context, req UpdateWorkScheduleLocationRequest) error
 	DeleteWorkScheduleLocation(ctx context.Context, id string) error
+	// ClearScheduleLocations deletes every location of a schedule; force allows it while the schedule is assigned
+	ClearScheduleLocations(ctx context.Context, scheduleID string, force bool) (ClearScheduleResponse, error)
 
 	// Employee Schedule Assignment
 	CreateEmployeeScheduleAssignment(ctx context.Context, req CreateEmployeeScheduleAssignmentRequest) (EmployeeScheduleAssignmentResponse, error)
@@ -38,4 +44,12 @@ type ScheduleService interface {
 
 	// Employee Schedule Timeline
 	GetEmployeeScheduleTimeline(ctx context.Context, employeeID string, filter EmployeeScheduleTimelineFilter) (EmployeeScheduleTimelineResponse, error)
+	ExportEmployeeTimeline(ctx context.Context, employeeID string, format string) ([]byte, error)
+	GetMyScheduleRange(ctx context.Context, userID string, startDate, endDate string) (MyScheduleRangeResponse, error)
+
+	// Shift Swap
+	RequestShiftSwap(ctx context.Context, fromEmployeeID, toEmployeeID string, date time.Time) (ShiftSwapResponse, error)
+	ApproveShiftSwap(ctx context.Context, id string) (ShiftSwapResponse, error)
+	RejectShiftSwap(ctx context.Context, id string, reason string) (ShiftSwapResponse, error)
+	ListShiftSwaps(ctx context.Context, filter ShiftSwapFilter) ([]ShiftSwapResponse, error)
 }
@@ -0,0 +1,82 @@
+package subscription
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Currency is an ISO 4217 currency code used to price plans and bill invoices
+type Currency string
+
+const (
+	CurrencyIDR Currency = "IDR"
+	CurrencyUSD Currency = "USD"
+	CurrencySGD Currency = "SGD"
+	CurrencyMYR Currency = "MYR"
+	CurrencyPHP Currency = "PHP"
+	CurrencyTHB Currency = "THB"
+	CurrencyVND Currency = "VND"
+
+	// DefaultCurrency applies to plans and invoices created before multi-currency support
+	DefaultCurrency = CurrencyIDR
+)
+
+// currencyMinorUnits lists the supported currencies with the number of decimal places
+// the payment provider accepts for them. IDR and VND are billed in whole units.
+var currencyMinorUnits = map[Currency]int32{
+	CurrencyIDR: 0,
+	CurrencyUSD: 2,
+	CurrencySGD: 2,
+	CurrencyMYR: 2,
+	CurrencyPHP: 2,
+	CurrencyTHB: 2,
+	CurrencyVND: 0,
+}
+
+// IsSupported reports whether checkout can bill in this currency
+func (c Currency) IsSupported() bool {
+	_, ok := currencyMinorUnits[c]
+	return ok
+}
+
+// MinorUnits returns the number of decimal places of the currency
+func (c Currency) MinorUnits() int32 {
+	return currencyMinorUnits[c]
+}
+
+// Round rounds an amount to the currency's minor units
+func (c Currency) Round(amount decimal.Decimal) decimal.Decimal {
+	return amount.Round(c.MinorUnits())
+}
+
+// Format renders an amount with thousand separators, e.g. "Rp 1.500.000" or "USD 1,500.00".
+// Rupiah keeps the local convention of dots for thousands; other currencies use commas.
+func (c Currency) Format(amount decimal.Decimal) string {
+	decimals := c.MinorUnits()
+	fixed := amount.Abs().StringFixed(decimals)
+
+	whole, fraction, _ := strings.Cut(fixed, ".")
+
+	prefix, thousandSep, decimalSep := string(c)+" ", ",", "."
+	if c == CurrencyIDR {
+		prefix, thousandSep, decimalSep = "Rp ", ".", ","
+	}
+
+	var out strings.Builder
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			out.WriteString(thousandSep)
+		}
+		out.WriteRune(d)
+	}
+	if fraction != "" {
+		out.WriteString(decimalSep)
+		out.WriteString(fraction)
+	}
+
+	if amount.IsNegative() {
+		return "-" + prefix + out.String()
+	}
+	return prefix + out.String()
+}
@@ -2,6 +2,7 @@ package subscription
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
 	"github.com/shopspring/decimal"
@@ -111,6 +112,74 @@ func (r *ChangeSeatRequest) Validate() error {
 	return nil
 }
 
+// InvoiceFilter narrows and paginates a company's invoice history
+type InvoiceFilter struct {
+	Status       *string `json:"status,omitempty"`
+	BillingCycle *string `json:"billing_cycle,omitempty"`
+	StartDate    *string `json:"start_date,omitempty"` // Issue date from (YYYY-MM-DD)
+	EndDate      *string `json:"end_date,omitempty"`   // Issue date to (YYYY-MM-DD)
+
+	// Pagination
+	Page  int `json:"page"`
+	Limit int `json:"limit"`
+}
+
+func (f *InvoiceFilter) Validate() error {
+	var errs validator.ValidationErrors
+
+	if f.Page < 0 {
+		errs = append(errs, validator.ValidationError{Field: "page", Message: "page must be a positive number"})
+	}
+	if f.Page == 0 {
+		f.Page = 1 // Default page
+	}
+
+	if f.Limit < 0 {
+		errs = append(errs, validator.ValidationError{Field: "limit", Message: "limit must be a positive number"})
+	}
+	if f.Limit == 0 {
+		f.Limit = 20 // Default limit
+	}
+	if f.Limit > 100 {
+		errs = append(errs, validator.ValidationError{Field: "limit", Message: "limit must not exceed 100"})
+	}
+
+	if f.Status != nil {
+		validStatuses := []string{
+			string(InvoiceStatusPending), string(InvoiceStatusPaid),
+			string(InvoiceStatusExpired), string(InvoiceStatusFailed),
+		}
+		if !validator.IsInSlice(*f.Status, validStatuses) {
+			errs = append(errs, validator.ValidationError{Field: "status", Message: "status must be one of: pending, paid, expired, failed"})
+		}
+	}
+
+	if f.BillingCycle != nil && *f.BillingCycle != string(BillingCycleMonthly) && *f.BillingCycle != string(BillingCycleYearly) {
+		errs = append(errs, validator.ValidationError{Field: "billing_cycle", Message: "billing_cycle must be 'monthly' or 'yearly'"})
+	}
+
+	var startDate time.Time
+	var startValid bool
+	if f.StartDate != nil {
+		if startDate, startValid = validator.IsValidDate(*f.StartDate); !startValid {
+			errs = append(errs, validator.ValidationError{Field: "start_date", Message: "start_date must be in YYYY-MM-DD format"})
+		}
+	}
+	if f.EndDate != nil {
+		endDate, endValid := validator.IsValidDate(*f.EndDate)
+		if !endValid {
+			errs = append(errs, validator.ValidationError{Field: "end_date", Message: "end_date must be in YYYY-MM-DD format"})
+		} else if startValid && endDate.Before(startDate) {
+			errs = append(errs, validator.ValidationError{Field: "end_date", Message: "end_date must be on or after start_date"})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
 // ==================== Response DTOs ====================
 
 // PlanResponse represents a plan in API responses
@@ -118,6 +187,7 @@ type PlanResponse struct {
 	ID           string            `json:"id"`
 	Name         string            `json:"name"`
 	PricePerSeat decimal.Decimal   `json:"price_per_seat"`
+	Currency     Currency          `json:"currency"`
 	TierLevel    int               `json:"tier_level"`
 	MaxSeats     *int              `json:"max_seats,omitempty"`
 	Features     []FeatureResponse `json:"features"`
@@ -150,7 +220,9 @@ type SubscriptionResponse struct {
 // InvoiceResponse represents an invoice in API responses
 type InvoiceResponse struct {
 	ID             string          `json:"id"`
+	InvoiceNumber  string          `json:"invoice_number"`
 	Amount         decimal.Decimal `json:"amount"`
+	Currency       Currency        `json:"currency"`
 	Status         InvoiceStatus   `json:"status"`
 	IsProrated     bool            `json:"is_prorated"`
 	PlanName       string          `json:"plan_name"`
@@ -167,6 +239,16 @@ type InvoiceResponse struct {
 	PaymentChannel *string         `json:"payment_channel,omitempty"`
 }
 
+// ListInvoiceResponse is a page of invoices with pagination metadata
+type ListInvoiceResponse struct {
+	TotalCount int64             `json:"total_count"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	TotalPages int               `json:"total_pages"`
+	Showing    string            `json:"showing"`
+	Invoices   []InvoiceResponse `json:"invoices"`
+}
+
 // CheckoutResponse represents the response after creating a checkout invoice
 type CheckoutResponse struct {
 	Invoice    InvoiceResponse `json:"invoice"`
@@ -182,25 +264,56 @@ type ChangeSeatResponse struct {
 	PendingMaxSeats *int             `json:"pending_max_seats,omitempty"`
 }
 
-// ==================== Webhook DTOs ====================
-
-// XenditWebhookPayload represents the webhook payload from Xendit
-type XenditWebhookPayload struct {
-	ID                 string  `json:"id"`
-	ExternalID         string  `json:"external_id"`
-	UserID             string  `json:"user_id"`
-	Status             string  `json:"status"` // PAID, EXPIRED, PENDING
-	MerchantName       string  `json:"merchant_name"`
-	Amount             float64 `json:"amount"`
-	PaidAmount         float64 `json:"paid_amount"`
-	BankCode           string  `json:"bank_code"`
-	PaidAt             string  `json:"paid_at"`
-	PayerEmail         string  `json:"payer_email"`
-	Description        string  `json:"description"`
-	Currency           string  `json:"currency"`
-	PaymentMethod      string  `json:"payment_method"`
-	PaymentChannel     string  `json:"payment_channel"`
-	PaymentDestination string  `json:"payment_destination"`
+// Seat change preview types
+const (
+	SeatChangeUpsell   = "upsell"
+	SeatChangeDownsell = "downsell"
+)
+
+// SeatChangePreviewResponse describes the effect of a seat change before it is confirmed
+type SeatChangePreviewResponse struct {
+	Type           string           `json:"type"` // upsell, downsell
+	CurrentSeats   int              `json:"current_seats"`
+	NewSeats       int              `json:"new_seats"`
+	PricePerSeat   decimal.Decimal  `json:"price_per_seat"`
+	Currency       Currency         `json:"currency"`
+	BillingCycle   BillingCycle     `json:"billing_cycle"`
+	ProratedAmount *decimal.Decimal `json:"prorated_amount,omitempty"` // Upsell: charged now
+	DaysRemaining  *float64         `json:"days_remaining,omitempty"`
+	TotalDays      *float64         `json:"total_days,omitempty"`
+	EffectiveDate  *string          `json:"effective_date,omitempty"` // Downsell: applied at renewal
+	Message        string           `json:"message"`
+}
+
+// SeatUsageResponse breaks the seats in use down by what occupies them.
+// Pending invitations hold a seat until they are accepted or expire.
+type SeatUsageResponse struct {
+	MaxSeats           int  `json:"max_seats"`
+	PendingMaxSeats    *int `json:"pending_max_seats,omitempty"` // Scheduled downsell, applied at renewal
+	ActiveEmployees    int  `json:"active_employees"`
+	InactiveEmployees  int  `json:"inactive_employees"` // Resigned or terminated, no seat
+	PendingInvitations int  `json:"pending_invitations"`
+	SeatsInUse         int  `json:"seats_in_use"` // active_employees + pending_invitations
+	AvailableSeats     int  `json:"available_seats"`
+	CanAddEmployee     bool `json:"can_add_employee"`
+}
+
+// Seat reconciliation actions
+const (
+	SeatActionPendingIncreaseScheduled = "pending_increase_scheduled"
+	SeatActionFlagged                  = "flagged"
+)
+
+// SeatViolation is a company with more active employees than paid seats, as found by ReconcileSeats
+type SeatViolation struct {
+	CompanyID       string             `json:"company_id"`
+	SubscriptionID  string             `json:"subscription_id"`
+	Status          SubscriptionStatus `json:"status"`
+	MaxSeats        int                `json:"max_seats"`
+	PendingMaxSeats *int               `json:"pending_max_seats,omitempty"`
+	ActiveEmployees int                `json:"active_employees"`
+	ExcessSeats     int                `json:"excess_seats"`
+	Action          string             `json:"action"` // pending_increase_scheduled, flagged
 }
 
 // ==================== Helper Functions ====================
@@ -220,6 +333,7 @@ func (p *Plan) ToResponse() PlanResponse {
 		ID:           p.ID,
 		Name:         p.Name,
 		PricePerSeat: p.PricePerSeat,
+		Currency:     p.Currency,
 		TierLevel:    p.TierLevel,
 		MaxSeats:     p.MaxSeats,
 		Features:     features,
@@ -268,17 +382,19 @@ func (s *Subscription) ToResponse(usedSeats int, pendingPlan *Plan) Subscription
 // ToResponse converts an Invoice entity to InvoiceResponse
 func (i *Invoice) ToResponse() InvoiceResponse {
 	resp := InvoiceResponse{
-		ID:           i.ID,
-		Amount:       i.Amount,
-		Status:       i.Status,
-		IsProrated:   i.IsProrated,
-		PlanName:     i.PlanSnapshotName,
-		SeatCount:    i.SeatCountSnapshot,
-		PricePerSeat: i.PricePerSeatSnapshot,
-		BillingCycle: i.BillingCycleSnapshot,
-		PeriodStart:  i.PeriodStart.Format("2006-01-02"),
-		PeriodEnd:    i.PeriodEnd.Format("2006-01-02"),
-		IssueDate:    i.IssueDate.Format("2006-01-02T15:04:05Z07:00"),
+		ID:            i.ID,
+		InvoiceNumber: i.InvoiceNumber,
+		Amount:        i.Amount,
+		Currency:      i.Currency,
+		Status:        i.Status,
+		IsProrated:    i.IsProrated,
+		PlanName:      i.PlanSnapshotName,
+		SeatCount:     i.SeatCountSnapshot,
+		PricePerSeat:  i.PricePerSeatSnapshot,
+		BillingCycle:  i.BillingCycleSnapshot,
+		PeriodStart:   i.PeriodStart.Format("2006-01-02"),
+		PeriodEnd:     i.PeriodEnd.Format("2006-01-02"),
+		IssueDate:     i.IssueDate.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
 	if i.XenditInvoiceURL != nil {
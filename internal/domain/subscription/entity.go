@@ -1,6 +1,7 @@
 package subscription
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -49,6 +50,7 @@ type Plan struct {
 	ID           string          `json:"id"`
 	Name         string          `json:"name"`
 	PricePerSeat decimal.Decimal `json:"price_per_seat"`
+	Currency     Currency        `json:"currency"`
 	TierLevel    int             `json:"tier_level"`
 	MaxSeats     *int            `json:"max_seats,omitempty"` // nil = unlimited
 	IsActive     bool            `json:"is_active"`
@@ -91,10 +93,12 @@ type Invoice struct {
 	ID               string          `json:"id"`
 	CompanyID        string          `json:"company_id"`
 	SubscriptionID   string          `json:"subscription_id"`
+	InvoiceNumber    string          `json:"invoice_number"` // Per-company sequential number, e.g. INV-2024-000123
 	XenditInvoiceID  *string         `json:"xendit_invoice_id,omitempty"`
 	XenditInvoiceURL *string         `json:"xendit_invoice_url,omitempty"`
 	XenditExpiryDate *time.Time      `json:"xendit_expiry_date,omitempty"`
 	Amount           decimal.Decimal `json:"amount"`
+	Currency         Currency        `json:"currency"` // Snapshot of the plan currency at checkout
 	IsProrated       bool            `json:"is_prorated"`
 	// Snapshot data (immutable - values at transaction time)
 	PlanSnapshotName     string          `json:"plan_snapshot_name"`
@@ -115,21 +119,40 @@ type Invoice struct {
 	UpdatedAt      time.Time     `json:"updated_at"`
 }
 
+// FormatInvoiceNumber renders a company's invoice sequence number as INV-<year>-<000123>
+func FormatInvoiceNumber(year int, sequence int64) string {
+	return fmt.Sprintf("INV-%d-%06d", year, sequence)
+}
+
+// BillingContact is a company owner who receives billing notifications
+type BillingContact struct {
+	UserID      string
+	Email       string
+	Name        string
+	CompanyName string
+}
+
 // IsActive checks if subscription is in an active state (active or trial)
 func (s *Subscription) IsActive() bool {
 	return s.Status == StatusActive || s.Status == StatusTrial || s.Status == StatusPastDue
 }
 
-// IsExpired checks if the subscription period has ended
-func (s *Subscription) IsExpired() bool {
-	return time.Now().After(s.CurrentPeriodEnd)
+// GracePeriodDays is how long a subscription stays past_due after its period ends before it expires
+const GracePeriodDays = 7
+
+// IsExpired checks if the subscription period has ended; the period end itself is no longer covered
+func (s *Subscription) IsExpired(now time.Time) bool {
+	return !now.Before(s.CurrentPeriodEnd)
+}
+
+// GraceEndsAt is when an unpaid subscription expires, GracePeriodDays after its period end
+func (s *Subscription) GraceEndsAt() time.Time {
+	return s.CurrentPeriodEnd.AddDate(0, 0, GracePeriodDays)
 }
 
-// IsInGracePeriod checks if subscription is in grace period (7 days after period end)
-func (s *Subscription) IsInGracePeriod() bool {
-	gracePeriodEnd := s.CurrentPeriodEnd.Add(7 * 24 * time.Hour)
-	now := time.Now()
-	return now.After(s.CurrentPeriodEnd) && now.Before(gracePeriodEnd)
+// IsInGracePeriod checks if subscription is in grace period (GracePeriodDays after period end)
+func (s *Subscription) IsInGracePeriod(now time.Time) bool {
+	return s.IsExpired(now) && now.Before(s.GraceEndsAt())
 }
 
 // HasFeature checks if the subscription includes a specific feature
@@ -7,6 +7,7 @@ var (
 	ErrSubscriptionNotFound     = errors.New("subscription not found")
 	ErrSubscriptionExpired      = errors.New("subscription has expired")
 	ErrSubscriptionCancelled    = errors.New("subscription has been cancelled")
+	ErrSubscriptionPastDue      = errors.New("subscription is past due")
 	ErrAlreadySubscribed        = errors.New("company already has an active subscription")
 	ErrInvalidSubscriptionState = errors.New("invalid subscription state for this operation")
 
@@ -19,6 +20,7 @@ var (
 	ErrNotAnUpgrade         = errors.New("target plan is not an upgrade from current plan")
 	ErrNotADowngrade        = errors.New("target plan is not a downgrade from current plan")
 	ErrTrialNotAllowed      = errors.New("trial subscription is not allowed")
+	ErrUnsupportedCurrency  = errors.New("plan currency is not supported for checkout")
 
 	// Seat errors
 	ErrInsufficientSeats              = errors.New("seat count must be greater than or equal to active employees")
@@ -45,5 +47,6 @@ var (
 
 	// Webhook errors
 	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	ErrInvalidWebhookPayload   = errors.New("invalid webhook payload")
 	ErrWebhookProcessingFailed = errors.New("failed to process webhook")
 )
@@ -0,0 +1,24 @@
+package subscription
+
+import (
+	"context"
+	"time"
+)
+
+// StatusChangedEvent describes a subscription moving from one status to another
+type StatusChangedEvent struct {
+	SubscriptionID   string
+	CompanyID        string
+	OldStatus        SubscriptionStatus
+	NewStatus        SubscriptionStatus
+	CurrentPeriodEnd time.Time
+	OccurredAt       time.Time
+}
+
+// EventPublisher delivers subscription lifecycle events to interested systems.
+// The default implementation notifies company owners in-app; an HTTP webhook
+// publisher can be plugged in by implementing this interface.
+type EventPublisher interface {
+	// PublishStatusChanged is called after every committed status transition
+	PublishStatusChanged(ctx context.Context, event StatusChangedEvent) error
+}
@@ -0,0 +1,58 @@
+package subscription
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PaymentProvider abstracts the payment gateway used to bill subscriptions.
+// Xendit is the default implementation; other gateways (e.g. Midtrans, Stripe)
+// can be plugged in by implementing this interface.
+type PaymentProvider interface {
+	// Name returns the provider identifier (e.g. "xendit")
+	Name() string
+
+	// CreateInvoice creates a hosted payment invoice at the provider
+	CreateInvoice(ctx context.Context, req PaymentInvoiceRequest) (PaymentInvoice, error)
+
+	// ExpireInvoice voids a pending invoice at the provider
+	ExpireInvoice(ctx context.Context, providerInvoiceID string) error
+
+	// VerifyCallback authenticates a webhook request and maps its provider-specific
+	// status to an internal InvoiceStatus. It returns ErrInvalidWebhookSignature when
+	// the request cannot be authenticated and ErrInvalidWebhookPayload when the body is malformed.
+	VerifyCallback(header http.Header, body []byte) (PaymentCallback, error)
+}
+
+// PaymentInvoiceRequest is the provider-agnostic request to create an invoice
+type PaymentInvoiceRequest struct {
+	ExternalID         string
+	Amount             decimal.Decimal
+	Currency           string
+	Description        string
+	PayerEmail         string
+	DurationSeconds    int
+	SuccessRedirectURL string
+	FailureRedirectURL string
+}
+
+// PaymentInvoice is the provider's view of a created invoice
+type PaymentInvoice struct {
+	ID         string
+	URL        string
+	ExpiryDate time.Time
+}
+
+// PaymentCallback is a verified payment notification mapped to internal statuses.
+// Status is empty when the provider status has no internal equivalent (e.g. still pending).
+type PaymentCallback struct {
+	ProviderInvoiceID string
+	Status            InvoiceStatus
+	ProviderStatus    string
+	PaymentMethod     string
+	PaymentChannel    string
+	PaidAt            *time.Time
+}
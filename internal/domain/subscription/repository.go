@@ -1,6 +1,9 @@
 package subscription
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // FeatureRepository handles feature data operations
 type FeatureRepository interface {
@@ -34,6 +37,10 @@ type SubscriptionRepository interface {
 	// GetByCompanyID retrieves a subscription by company ID
 	GetByCompanyID(ctx context.Context, companyID string) (Subscription, error)
 
+	// GetByCompanyIDForUpdate retrieves a subscription by company ID and locks the row
+	// Must be called inside a transaction
+	GetByCompanyIDForUpdate(ctx context.Context, companyID string) (Subscription, error)
+
 	// GetByCompanyIDWithFeatures retrieves subscription with plan and features
 	GetByCompanyIDWithFeatures(ctx context.Context, companyID string) (Subscription, error)
 
@@ -61,7 +68,7 @@ type SubscriptionRepository interface {
 	// SetPendingPlan sets pending plan for downgrade
 	SetPendingPlan(ctx context.Context, id string, pendingPlanID *string) error
 
-	// ListExpiring retrieves subscriptions expiring before a given time
+	// ListExpiring retrieves trial and active subscriptions whose period ended at or before a given time
 	ListExpiring(ctx context.Context, before interface{}) ([]Subscription, error)
 
 	// ListByStatus retrieves subscriptions by status
@@ -76,8 +83,27 @@ type SubscriptionRepository interface {
 	// ApplyPendingPlan applies the pending plan to the subscription
 	ApplyPendingPlan(ctx context.Context, id string) error
 
-	// UpdateExpiredToStatus bulk updates subscriptions that have passed their period end
-	UpdateExpiredToStatus(ctx context.Context, cutoffTime interface{}, fromStatuses []SubscriptionStatus, toStatus SubscriptionStatus) (int64, error)
+	// UpdateExpiredToStatus bulk updates subscriptions whose period ended at or before cutoffTime
+	// and returns one event per subscription whose status changed
+	UpdateExpiredToStatus(ctx context.Context, cutoffTime interface{}, fromStatuses []SubscriptionStatus, toStatus SubscriptionStatus) ([]StatusChangedEvent, error)
+
+	// ListTrialsForReminder retrieves trials ending in (now, endsBefore] that have not
+	// yet received a reminder with a lead time of leadDays or less
+	ListTrialsForReminder(ctx context.Context, now, endsBefore time.Time, leadDays int) ([]Subscription, error)
+
+	// MarkTrialReminderSent records the lead time of the reminder just sent for a trial
+	MarkTrialReminderSent(ctx context.Context, id string, leadDays int) error
+
+	// ListSeatViolations retrieves trial, active and past_due subscriptions whose company has
+	// more active employees than max_seats, with the active employee count filled in
+	ListSeatViolations(ctx context.Context) ([]SeatViolation, error)
+
+	// MarkSeatViolationFlagged flags a subscription for admin follow-up.
+	// It returns false when the subscription was already flagged.
+	MarkSeatViolationFlagged(ctx context.Context, id string) (bool, error)
+
+	// ClearResolvedSeatViolations unflags subscriptions that are back within their seat limit
+	ClearResolvedSeatViolations(ctx context.Context) (int64, error)
 }
 
 // InvoiceRepository handles invoice data operations
@@ -91,14 +117,23 @@ type InvoiceRepository interface {
 	// Create creates a new invoice
 	Create(ctx context.Context, invoice Invoice) (Invoice, error)
 
+	// AllocateNumber increments and returns the company's invoice sequence.
+	// The sequence row stays locked until the surrounding transaction ends, so call it inside one.
+	AllocateNumber(ctx context.Context, companyID string) (int64, error)
+
 	// UpdateStatus updates invoice status
 	UpdateStatus(ctx context.Context, id string, status InvoiceStatus) error
 
+	// MarkDunningSent records that the customer was told about an expired or failed invoice.
+	// It returns false when a notice was already sent, so concurrent webhooks notify only once.
+	MarkDunningSent(ctx context.Context, id string) (bool, error)
+
 	// UpdatePayment updates invoice with payment details
 	UpdatePayment(ctx context.Context, id string, status InvoiceStatus, paidAt interface{}, method, channel string) error
 
-	// ListByCompanyID retrieves all invoices for a company
-	ListByCompanyID(ctx context.Context, companyID string) ([]Invoice, error)
+	// ListByCompanyID retrieves a filtered page of a company's invoices, newest issue date first,
+	// along with the total number of matching invoices
+	ListByCompanyID(ctx context.Context, companyID string, filter InvoiceFilter) ([]Invoice, int64, error)
 
 	// ListBySubscriptionID retrieves all invoices for a subscription
 	ListBySubscriptionID(ctx context.Context, subscriptionID string) ([]Invoice, error)
@@ -124,4 +159,22 @@ type InvoiceRepository interface {
 type EmployeeCounter interface {
 	// CountActiveByCompanyID counts active employees for a company
 	CountActiveByCompanyID(ctx context.Context, companyID string) (int, error)
+
+	// CountSeatsInUseByCompanyID counts active employees plus outstanding invitations
+	// for employees that are not yet active, i.e. every seat that is taken or promised
+	CountSeatsInUseByCompanyID(ctx context.Context, companyID string) (int, error)
+
+	// CountInactiveByCompanyID counts resigned and terminated employees, which hold no seat
+	CountInactiveByCompanyID(ctx context.Context, companyID string) (int, error)
+
+	// CountPendingInvitationsByCompanyID counts the outstanding invitations that hold a seat,
+	// i.e. the invitation part of CountSeatsInUseByCompanyID
+	CountPendingInvitationsByCompanyID(ctx context.Context, companyID string) (int, error)
+}
+
+// BillingContactFinder resolves who should receive billing notifications for a company
+// This is implemented by the user repository
+type BillingContactFinder interface {
+	// ListOwnersByCompanyID returns the owners of a company with their contact details
+	ListOwnersByCompanyID(ctx context.Context, companyID string) ([]BillingContact, error)
 }
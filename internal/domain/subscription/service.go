@@ -21,8 +21,8 @@ type SubscriptionService interface {
 	GetMySubscription(ctx context.Context, companyID string) (SubscriptionResponse, error)
 
 	// CreateTrialSubscription creates a trial subscription for a new company
-	// Called during company registration
-	CreateTrialSubscription(ctx context.Context, companyID string) (Subscription, error)
+	// Called during company registration; signupSource selects per-campaign trial terms (may be empty)
+	CreateTrialSubscription(ctx context.Context, companyID string, signupSource string) (Subscription, error)
 
 	// ==================== Checkout & Payment ====================
 
@@ -30,8 +30,8 @@ type SubscriptionService interface {
 	// Validates: seat_count >= active employees
 	Checkout(ctx context.Context, companyID string, req CheckoutRequest) (InvoiceResponse, error)
 
-	// HandleWebhook processes Xendit webhook callback
-	HandleWebhook(ctx context.Context, payload XenditWebhookPayload) error
+	// HandleWebhook processes a verified payment provider callback
+	HandleWebhook(ctx context.Context, callback PaymentCallback) error
 
 	// ==================== Plan Changes ====================
 
@@ -46,23 +46,37 @@ type SubscriptionService interface {
 
 	// ==================== Seat Management ====================
 
+	// PreviewSeatChange returns the prorated charge or scheduled date of a seat change without side effects
+	PreviewSeatChange(ctx context.Context, companyID string, newSeatCount int) (SeatChangePreviewResponse, error)
+
 	// ChangeSeats changes the number of seats (upsell: prorated payment, downsell: scheduled)
 	ChangeSeats(ctx context.Context, companyID string, req ChangeSeatRequest) (ChangeSeatResponse, error)
 
 	// CancelPendingInvoice cancels a pending invoice
 	CancelPendingInvoice(ctx context.Context, companyID string, invoiceID string) error
 
+	// GetSeatUsageBreakdown counts active and inactive employees and pending invitations against MaxSeats
+	GetSeatUsageBreakdown(ctx context.Context, companyID string) (SeatUsageResponse, error)
+
 	// CanAddEmployee checks if more employees can be added to the subscription
 	CanAddEmployee(ctx context.Context, companyID string) (bool, error)
 
+	// EnsureSeatAvailable returns ErrSeatLimitExceeded when no seat is left for a new employee
+	// When called inside a transaction the subscription row stays locked until commit,
+	// so concurrent creates cannot both claim the last seat
+	EnsureSeatAvailable(ctx context.Context, companyID string) error
+
 	// ==================== Invoice Operations ====================
 
-	// GetInvoices retrieves all invoices for the specified company
-	GetInvoices(ctx context.Context, companyID string) ([]InvoiceResponse, error)
+	// GetInvoices retrieves a filtered, paginated list of invoices for the specified company
+	GetInvoices(ctx context.Context, companyID string, filter InvoiceFilter) (ListInvoiceResponse, error)
 
 	// GetInvoiceByID retrieves a specific invoice
 	GetInvoiceByID(ctx context.Context, companyID string, invoiceID string) (InvoiceResponse, error)
 
+	// GenerateInvoicePDF renders a printable PDF of a specific invoice
+	GenerateInvoicePDF(ctx context.Context, companyID string, invoiceID string) ([]byte, error)
+
 	// ==================== Cron Job Operations ====================
 
 	// UpdateExpiredSubscriptions updates subscription statuses based on period end
@@ -77,6 +91,15 @@ type SubscriptionService interface {
 	// Called by cron job
 	ApplyPendingDowngrades(ctx context.Context) error
 
+	// NotifyExpiringTrials reminds company owners that their trial ends soon
+	// Called by cron job, once per configured lead time
+	NotifyExpiringTrials(ctx context.Context) error
+
+	// ReconcileSeats reports companies whose active employee count exceeds their paid seats
+	// and schedules a pending seat increase or flags them for follow-up
+	// Called by cron job
+	ReconcileSeats(ctx context.Context) ([]SeatViolation, error)
+
 	// ==================== Feature Check ====================
 
 	// HasFeature checks if the company's subscription includes a specific feature
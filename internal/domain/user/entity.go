@@ -22,6 +22,7 @@ type User struct {
 	EmailVerified           bool
 	EmailVerificationToken  *string
 	EmailVerificationSentAt *time.Time
+	ManagedBranchID         *string // Branch a manager is limited to; nil means company-wide
 	CreatedAt               time.Time
 	UpdatedAt               time.Time
 
@@ -49,6 +50,11 @@ func (u *User) CanApprove() bool {
 	return u.IsManager()
 }
 
+// IsBranchScoped checks if user is a manager limited to a single branch
+func (u *User) IsBranchScoped() bool {
+	return u.Role == RoleManager && u.ManagedBranchID != nil && *u.ManagedBranchID != ""
+}
+
 // CanManageCompany checks if user can manage company settings
 func (u *User) CanManageCompany() bool {
 	return u.IsOwner()
@@ -13,6 +13,7 @@ const (
 	PermissionLeaveViewAll     Permission = "leave.view_all"
 	PermissionLeaveApprove     Permission = "leave.approve"
 	PermissionLeaveManageTypes Permission = "leave.manage_types"
+	PermissionLeaveAdjustQuota Permission = "leave.adjust_quota"
 
 	// Attendance Management
 	PermissionAttendanceViewOwn Permission = "attendance.view_own"
@@ -28,6 +29,12 @@ const (
 	PermissionCompanyView   Permission = "company.view"
 	PermissionCompanyManage Permission = "company.manage"
 
+	// Payroll
+	PermissionPayrollView      Permission = "payroll.view"
+	PermissionPayrollProcess   Permission = "payroll.process"
+	PermissionPayrollConfigure Permission = "payroll.configure"
+	PermissionPayrollFinalize  Permission = "payroll.finalize"
+
 	// Reports
 	PermissionReportsView Permission = "reports.view"
 
@@ -35,6 +42,58 @@ const (
 	PermissionUserManage Permission = "user.manage"
 )
 
+// AllPermissions lists every permission known to the system
+var AllPermissions = []Permission{
+	PermissionViewOwnProfile,
+	PermissionEditOwnProfile,
+	PermissionLeaveViewOwn,
+	PermissionLeaveCreate,
+	PermissionLeaveViewAll,
+	PermissionLeaveApprove,
+	PermissionLeaveManageTypes,
+	PermissionLeaveAdjustQuota,
+	PermissionAttendanceViewOwn,
+	PermissionAttendanceCreate,
+	PermissionAttendanceViewAll,
+	PermissionAttendanceApprove,
+	PermissionEmployeeViewAll,
+	PermissionEmployeeManage,
+	PermissionCompanyView,
+	PermissionCompanyManage,
+	PermissionPayrollView,
+	PermissionPayrollProcess,
+	PermissionPayrollConfigure,
+	PermissionPayrollFinalize,
+	PermissionReportsView,
+	PermissionUserManage,
+}
+
+// ownerOnlyPermissions can never be granted through a company role
+// so that a custom role cannot be used to escalate to owner
+var ownerOnlyPermissions = map[Permission]struct{}{
+	PermissionCompanyManage: {},
+	PermissionUserManage:    {},
+}
+
+// IsValidPermission checks if a permission is known to the system
+func IsValidPermission(permission Permission) bool {
+	for _, p := range AllPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAssignablePermission checks if a permission may be granted through a company role
+func IsAssignablePermission(permission Permission) bool {
+	if !IsValidPermission(permission) {
+		return false
+	}
+	_, ownerOnly := ownerOnlyPermissions[permission]
+	return !ownerOnly
+}
+
 // RolePermissions maps roles to their permissions
 var RolePermissions = map[Role][]Permission{
 	RoleOwner: {
@@ -46,6 +105,7 @@ var RolePermissions = map[Role][]Permission{
 		PermissionLeaveViewAll,
 		PermissionLeaveApprove,
 		PermissionLeaveManageTypes,
+		PermissionLeaveAdjustQuota,
 		PermissionAttendanceViewOwn,
 		PermissionAttendanceCreate,
 		PermissionAttendanceViewAll,
@@ -54,6 +114,10 @@ var RolePermissions = map[Role][]Permission{
 		PermissionEmployeeManage,
 		PermissionCompanyView,
 		PermissionCompanyManage,
+		PermissionPayrollView,
+		PermissionPayrollProcess,
+		PermissionPayrollConfigure,
+		PermissionPayrollFinalize,
 		PermissionReportsView,
 		PermissionUserManage,
 	},
@@ -65,12 +129,15 @@ var RolePermissions = map[Role][]Permission{
 		PermissionLeaveCreate,
 		PermissionLeaveViewAll,
 		PermissionLeaveApprove,
+		PermissionLeaveAdjustQuota,
 		PermissionAttendanceViewOwn,
 		PermissionAttendanceCreate,
 		PermissionAttendanceViewAll,
 		PermissionAttendanceApprove,
 		PermissionEmployeeViewAll,
 		PermissionCompanyView,
+		PermissionPayrollView,
+		PermissionPayrollProcess,
 		PermissionReportsView,
 	},
 	RoleEmployee: {
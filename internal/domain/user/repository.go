@@ -9,7 +9,7 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id string) (User, error)
 	Create(ctx context.Context, newUser User) (User, error)
 	ExistsByIDOrEmail(ctx context.Context, id, email *string) (bool, error)
-	LinkGoogleAccount(ctx context.Context, GoogleID string, email string) (User, error)
+	LinkOAuthAccount(ctx context.Context, provider string, providerID string, email string) (User, error)
 	LinkPasswordAccount(ctx context.Context, id string, password string) (User, error)
 	UpdateRole(ctx context.Context, req UpdateUserRoleRequest) error
 	Update(ctx context.Context, req UpdateUserRequest) error
@@ -17,4 +17,7 @@ type UserRepository interface {
 	UpdatePassword(ctx context.Context, userID, passwordHash string) error
 	VerifyEmail(ctx context.Context, userID string) error
 	GetByEmailVerificationToken(ctx context.Context, token string) (User, error)
+	UpdateManagedBranch(ctx context.Context, userID string, branchID *string) error
+	// DetachFromCompany removes the user's company, role, branch scope and company role and revokes their refresh tokens
+	DetachFromCompany(ctx context.Context, userID string) error
 }
@@ -3,6 +3,7 @@ package fixtures
 import (
 	"time"
 
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/master/branch"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/master/grade"
@@ -394,6 +395,28 @@ func GetDefaultLeaveTypes(companyID string) []leave.LeaveType {
 	}
 }
 
+// basicLeaveTypeCodes are the leave types of company.LeaveTypeTemplateBasic
+var basicLeaveTypeCodes = map[string]bool{"ANNUAL": true, "SICK": true, "UNPAID": true}
+
+// GetLeaveTypeTemplate returns the leave types of a company.LeaveTypeTemplates entry,
+// or nil for an unknown template
+func GetLeaveTypeTemplate(companyID string, template string) []leave.LeaveType {
+	switch template {
+	case company.LeaveTypeTemplateIndonesia:
+		return GetDefaultLeaveTypes(companyID)
+	case company.LeaveTypeTemplateBasic:
+		var leaveTypes []leave.LeaveType
+		for _, lt := range GetDefaultLeaveTypes(companyID) {
+			if lt.Code != nil && basicLeaveTypeCodes[*lt.Code] {
+				leaveTypes = append(leaveTypes, lt)
+			}
+		}
+		return leaveTypes
+	default:
+		return nil
+	}
+}
+
 // ==========================================
 // DEFAULT WORK SCHEDULE
 // ==========================================
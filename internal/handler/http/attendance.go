@@ -9,6 +9,7 @@ import (
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
 	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
 )
 
 type AttendanceHandler interface {
@@ -22,6 +23,10 @@ type AttendanceHandler interface {
 	Approve(w http.ResponseWriter, r *http.Request)
 	Reject(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
+	GetSettings(w http.ResponseWriter, r *http.Request)
+	UpdateSettings(w http.ResponseWriter, r *http.Request)
+	LiveBoard(w http.ResponseWriter, r *http.Request)
+	Import(w http.ResponseWriter, r *http.Request)
 }
 
 type attendanceHandlerImpl struct {
@@ -39,9 +44,7 @@ func (h *attendanceHandlerImpl) ClockIn(w http.ResponseWriter, r *http.Request)
 	var req attendance.ClockInRequest
 
 	// Parse multipart form (max 10MB)
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
-		slog.Error("Failed to parse multipart form", "error", err)
-		response.BadRequest(w, "Failed to parse form data", nil)
+	if !parseMultipartForm(w, r) {
 		return
 	}
 
@@ -97,9 +100,7 @@ func (h *attendanceHandlerImpl) ClockOut(w http.ResponseWriter, r *http.Request)
 	var req attendance.ClockOutRequest
 
 	// Parse multipart form (max 10MB)
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
-		slog.Error("Failed to parse multipart form", "error", err)
-		response.BadRequest(w, "Failed to parse form data", nil)
+	if !parseMultipartForm(w, r) {
 		return
 	}
 
@@ -309,6 +310,41 @@ func (h *attendanceHandlerImpl) GetStatus(w http.ResponseWriter, r *http.Request
 	response.Success(w, status)
 }
 
+// LiveBoard implements AttendanceHandler.
+func (h *attendanceHandlerImpl) LiveBoard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		response.Forbidden(w, "Company ID not found in token")
+		return
+	}
+
+	filter := attendance.LiveBoardFilter{}
+	if branchID := r.URL.Query().Get("branch_id"); branchID != "" {
+		filter.BranchID = &branchID
+	}
+
+	if err := filter.Validate(); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	board, err := h.attendanceService.GetLiveBoard(ctx, companyID, filter)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, board)
+}
+
 // Update implements AttendanceHandler.
 func (h *attendanceHandlerImpl) Update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -394,3 +430,72 @@ func (h *attendanceHandlerImpl) Delete(w http.ResponseWriter, r *http.Request) {
 
 	response.SuccessWithMessage(w, "Attendance deleted successfully", nil)
 }
+
+// GetSettings implements AttendanceHandler.
+func (h *attendanceHandlerImpl) GetSettings(w http.ResponseWriter, r *http.Request) {
+	result, err := h.attendanceService.GetSettings(r.Context())
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
+// UpdateSettings implements AttendanceHandler.
+func (h *attendanceHandlerImpl) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req attendance.UpdateAttendanceSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body", nil)
+		return
+	}
+
+	result, err := h.attendanceService.UpdateSettings(r.Context(), req)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
+// Import implements AttendanceHandler.
+// POST /api/v1/attendance/import - multipart form with a CSV "file"
+func (h *attendanceHandlerImpl) Import(w http.ResponseWriter, r *http.Request) {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		response.Unauthorized(w, "Failed to extract claims from context")
+		return
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		response.Unauthorized(w, "company_id claim is missing or invalid")
+		return
+	}
+
+	// Parse multipart form (max 10MB)
+	if !parseMultipartForm(w, r) {
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		if err == http.ErrMissingFile {
+			response.BadRequest(w, "Import file is required", nil)
+			return
+		}
+		slog.Error("Failed to get file from form", "error", err)
+		response.BadRequest(w, "Invalid file upload", nil)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.attendanceService.BulkImport(r.Context(), companyID, file)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
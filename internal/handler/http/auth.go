@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -12,14 +13,16 @@ import (
 	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/jwt"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/oauth"
+	"github.com/go-chi/chi/v5"
 )
 
 type AuthHandler interface {
 	Register(w http.ResponseWriter, r *http.Request)
 	Login(w http.ResponseWriter, r *http.Request)
 	LoginWithEmployeeCode(w http.ResponseWriter, r *http.Request)
-	LoginWithGoogle(w http.ResponseWriter, r *http.Request)
-	OAuthCallbackGoogle(w http.ResponseWriter, r *http.Request)
+	LoginWithOAuth(w http.ResponseWriter, r *http.Request)
+	OAuthCallback(w http.ResponseWriter, r *http.Request)
+	ConfirmOAuthLink(w http.ResponseWriter, r *http.Request)
 	Logout(w http.ResponseWriter, r *http.Request)
 	RefreshToken(w http.ResponseWriter, r *http.Request)
 	ForgotPassword(w http.ResponseWriter, r *http.Request)
@@ -28,10 +31,10 @@ type AuthHandler interface {
 }
 
 type AuthHandlerImpl struct {
-	jwtService    jwt.Service
-	authService   auth.AuthService
-	googleService oauth.GoogleService
-	frontendURL   string
+	jwtService  jwt.Service
+	authService auth.AuthService
+	providers   map[string]oauth.Provider
+	frontendURL string
 }
 
 // ForgotPassword implements AuthHandler.
@@ -169,20 +172,26 @@ func (a *AuthHandlerImpl) LoginWithEmployeeCode(w http.ResponseWriter, r *http.R
 	response.Created(w, "User logged in successfully", tokenResponse)
 }
 
-// LoginWithGoogle implements AuthHandler.
-func (a *AuthHandlerImpl) LoginWithGoogle(w http.ResponseWriter, r *http.Request) {
-	state := a.googleService.GenerateState(r.UserAgent())
+// LoginWithOAuth implements AuthHandler.
+func (a *AuthHandlerImpl) LoginWithOAuth(w http.ResponseWriter, r *http.Request) {
+	provider, ok := a.providers[chi.URLParam(r, "provider")]
+	if !ok {
+		response.HandleError(w, auth.ErrOAuthProviderNotSupported)
+		return
+	}
+
+	state := provider.GenerateState(r.UserAgent())
 	cookie := &http.Cookie{
 		Name:     "state",
 		Value:    state,
-		Path:     "/api/v1/auth/oauth/callback/google",
+		Path:     "/api/v1/auth/oauth/callback/" + provider.Name(),
 		Expires:  time.Now().Add(5 * time.Minute),
 		HttpOnly: true,
 		Secure:   false,
 		SameSite: http.SameSiteLaxMode,
 	}
 	http.SetCookie(w, cookie)
-	url := a.googleService.RedirectURL(state)
+	url := provider.RedirectURL(state)
 	http.Redirect(w, r, url, http.StatusTemporaryRedirect)
 }
 
@@ -219,11 +228,17 @@ func (a *AuthHandlerImpl) Logout(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, "User logged out successfully")
 }
 
-// OAuthCallbackGoogle implements AuthHandler.
-func (a *AuthHandlerImpl) OAuthCallbackGoogle(w http.ResponseWriter, r *http.Request) {
+// OAuthCallback implements AuthHandler.
+func (a *AuthHandlerImpl) OAuthCallback(w http.ResponseWriter, r *http.Request) {
+	provider, ok := a.providers[chi.URLParam(r, "provider")]
+	if !ok {
+		response.HandleError(w, auth.ErrOAuthProviderNotSupported)
+		return
+	}
+
 	// Helper function to redirect to frontend with error
 	redirectWithError := func(errorMsg string) {
-		redirectURL := fmt.Sprintf("%s/auth/callback/google?error=%s", a.frontendURL, url.QueryEscape(errorMsg))
+		redirectURL := fmt.Sprintf("%s/auth/callback/%s?error=%s", a.frontendURL, provider.Name(), url.QueryEscape(errorMsg))
 		http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
 	}
 
@@ -235,7 +250,7 @@ func (a *AuthHandlerImpl) OAuthCallbackGoogle(w http.ResponseWriter, r *http.Req
 	}
 	errorValue := r.URL.Query().Get("error")
 	if errorValue == "access_denied" {
-		slog.Error("Google access denied by user", "error", auth.ErrGoogleAccessDeniedByUser)
+		slog.Error("OAuth access denied by user", "provider", provider.Name(), "error", auth.ErrOAuthAccessDeniedByUser)
 		redirectWithError("access_denied")
 		return
 	}
@@ -272,14 +287,14 @@ func (a *AuthHandlerImpl) OAuthCallbackGoogle(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	token, err := a.googleService.VerifyToken(r.Context(), code)
+	token, err := provider.VerifyToken(r.Context(), code)
 	if err != nil {
 		slog.Error("Failed to verify token", "error", err)
 		redirectWithError("token_verification_failed")
 		return
 	}
 
-	userGoogle, err := a.googleService.VerifyUser(r.Context(), token)
+	identity, err := provider.FetchIdentity(r.Context(), token)
 	if err != nil {
 		slog.Error("Failed to verify user", "error", err)
 		redirectWithError("user_verification_failed")
@@ -289,9 +304,40 @@ func (a *AuthHandlerImpl) OAuthCallbackGoogle(w http.ResponseWriter, r *http.Req
 	var sessionTrackReq auth.SessionTrackingRequest
 	sessionTrackReq.IPAddress = r.RemoteAddr
 	sessionTrackReq.UserAgent = r.UserAgent()
-	tokenResponse, err := a.authService.LoginWithGoogle(r.Context(), userGoogle.Email, userGoogle.GoogleID, sessionTrackReq)
+	tokenResponse, err := a.authService.LoginWithOAuth(r.Context(), auth.OAuthIdentity{
+		Provider:      identity.Provider,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+	}, sessionTrackReq)
 	if err != nil {
-		slog.Error("Failed to login with Google", "error", err)
+		var linkRequired *auth.OAuthLinkRequiredError
+		if errors.As(err, &linkRequired) {
+			// Existing password account: the frontend asks for the password and calls /auth/oauth/link
+			slog.Info("OAuth login requires account link confirmation", "provider", provider.Name())
+			redirectURL := fmt.Sprintf("%s/auth/callback/%s?link_required=true&link_token=%s&email=%s",
+				a.frontendURL,
+				provider.Name(),
+				url.QueryEscape(linkRequired.LinkToken),
+				url.QueryEscape(linkRequired.Email),
+			)
+			http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
+			return
+		}
+
+		slog.Error("Failed to login with OAuth", "provider", provider.Name(), "error", err)
+		if errors.Is(err, auth.ErrOAuthEmailNotVerified) {
+			redirectWithError("email_not_verified")
+			return
+		}
+		if errors.Is(err, auth.ErrOAuthAccountConflict) {
+			redirectWithError("account_linked_elsewhere")
+			return
+		}
+		if errors.Is(err, auth.ErrOAuthLinkEmailNotVerified) {
+			redirectWithError("account_email_not_verified")
+			return
+		}
 		redirectWithError("login_failed")
 		return
 	}
@@ -300,17 +346,44 @@ func (a *AuthHandlerImpl) OAuthCallbackGoogle(w http.ResponseWriter, r *http.Req
 	refreshTokenCookie := a.jwtService.RefreshTokenCookie(tokenResponse.RefreshToken, tokenResponse.RefreshTokenExpiresIn)
 	http.SetCookie(w, refreshTokenCookie)
 
-	slog.Info("User logged in successfully via Google OAuth")
+	slog.Info("User logged in successfully via OAuth", "provider", provider.Name())
 
 	// Redirect to frontend with access token
-	redirectURL := fmt.Sprintf("%s/auth/callback/google?access_token=%s&expires_in=%d",
+	redirectURL := fmt.Sprintf("%s/auth/callback/%s?access_token=%s&expires_in=%d",
 		a.frontendURL,
+		provider.Name(),
 		url.QueryEscape(tokenResponse.AccessToken),
 		tokenResponse.AccessTokenExpiresIn,
 	)
 	http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
 }
 
+// ConfirmOAuthLink implements AuthHandler.
+func (a *AuthHandlerImpl) ConfirmOAuthLink(w http.ResponseWriter, r *http.Request) {
+	var confirmReq auth.ConfirmOAuthLinkRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&confirmReq); err != nil {
+		slog.Error("ConfirmOAuthLink decode error", "error", err)
+		response.BadRequest(w, "Invalid request format", nil)
+		return
+	}
+
+	var sessionTrackReq auth.SessionTrackingRequest
+	sessionTrackReq.IPAddress = r.RemoteAddr
+	sessionTrackReq.UserAgent = r.UserAgent()
+	tokenResponse, err := a.authService.ConfirmOAuthLink(r.Context(), confirmReq, sessionTrackReq)
+	if err != nil {
+		slog.Error("ConfirmOAuthLink service error", "error", err)
+		response.HandleError(w, err)
+		return
+	}
+
+	refreshTokenCookie := a.jwtService.RefreshTokenCookie(tokenResponse.RefreshToken, tokenResponse.RefreshTokenExpiresIn)
+	http.SetCookie(w, refreshTokenCookie)
+	slog.Info("OAuth account linked successfully")
+	response.Created(w, "OAuth account linked successfully", tokenResponse)
+}
+
 // RefreshToken implements AuthHandler.
 func (a *AuthHandlerImpl) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	// RefreshToken implements AuthHandler.
@@ -416,11 +489,17 @@ func (a *AuthHandlerImpl) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 	response.SuccessWithMessage(w, "Email has been verified successfully", nil)
 }
 
-func NewAuthHandler(jwtService jwt.Service, authService auth.AuthService, googleService oauth.GoogleService, frontendURL string) AuthHandler {
+// NewAuthHandler creates the auth handler; each provider is served at /auth/login/oauth/{provider}
+func NewAuthHandler(jwtService jwt.Service, authService auth.AuthService, frontendURL string, providers ...oauth.Provider) AuthHandler {
+	registered := make(map[string]oauth.Provider, len(providers))
+	for _, p := range providers {
+		registered[p.Name()] = p
+	}
+
 	return &AuthHandlerImpl{
-		jwtService:    jwtService,
-		authService:   authService,
-		googleService: googleService,
-		frontendURL:   frontendURL,
+		jwtService:  jwtService,
+		authService: authService,
+		providers:   registered,
+		frontendURL: frontendURL,
 	}
 }
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/auth"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
 	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/jwt"
 	"github.com/cmlabs-hris/hris-backend-go/internal/service/file"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth/v5"
 )
 
@@ -21,6 +23,11 @@ type CompanyHandler interface {
 	Update(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
 	UploadCompanyLogo(w http.ResponseWriter, r *http.Request)
+	SeedDefaultLeaveTypes(w http.ResponseWriter, r *http.Request)
+	GetOnboardingStatus(w http.ResponseWriter, r *http.Request)
+	GetCalendar(w http.ResponseWriter, r *http.Request)
+	CreateHoliday(w http.ResponseWriter, r *http.Request)
+	DeleteHoliday(w http.ResponseWriter, r *http.Request)
 }
 
 type CompanyHandlerImpl struct {
@@ -31,9 +38,7 @@ type CompanyHandlerImpl struct {
 
 // UploadCompanyLogo implements CompanyHandler.
 func (c *CompanyHandlerImpl) UploadCompanyLogo(w http.ResponseWriter, r *http.Request) {
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
-		slog.Error("Failed to parse multipart form", "error", err)
-		response.BadRequest(w, "Failed to parse form data", nil)
+	if !parseMultipartForm(w, r) {
 		return
 	}
 
@@ -81,9 +86,7 @@ func (c *CompanyHandlerImpl) UploadCompanyLogo(w http.ResponseWriter, r *http.Re
 // Create implements CompanyHandler.
 func (c *CompanyHandlerImpl) Create(w http.ResponseWriter, r *http.Request) {
 
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
-		slog.Error("Failed to parse multipart form", "error", err)
-		response.BadRequest(w, "Failed to parse form data", nil)
+	if !parseMultipartForm(w, r) {
 		return
 	}
 
@@ -129,6 +132,160 @@ func (c *CompanyHandlerImpl) Create(w http.ResponseWriter, r *http.Request) {
 	response.Created(w, "Company created successfully", company)
 }
 
+// SeedDefaultLeaveTypes implements CompanyHandler.
+func (c *CompanyHandlerImpl) SeedDefaultLeaveTypes(w http.ResponseWriter, r *http.Request) {
+	var req company.SeedLeaveTypesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request format", nil)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		response.HandleError(w, auth.ErrInvalidToken)
+		return
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		response.HandleError(w, auth.ErrInvalidToken)
+		return
+	}
+
+	result, err := c.companyService.SeedDefaultLeaveTypes(r.Context(), companyID, req)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Default leave types seeded successfully", result)
+}
+
+// GetOnboardingStatus implements CompanyHandler.
+func (c *CompanyHandlerImpl) GetOnboardingStatus(w http.ResponseWriter, r *http.Request) {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		response.HandleError(w, auth.ErrInvalidToken)
+		return
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		response.HandleError(w, auth.ErrInvalidToken)
+		return
+	}
+
+	result, err := c.companyService.GetOnboardingStatus(r.Context(), companyID)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
+// GetCalendar implements CompanyHandler.
+func (c *CompanyHandlerImpl) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		response.HandleError(w, auth.ErrInvalidToken)
+		return
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		response.HandleError(w, auth.ErrInvalidToken)
+		return
+	}
+
+	var filter company.CalendarFilter
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil {
+			response.BadRequest(w, "Invalid year", nil)
+			return
+		}
+		filter.Year = &year
+	}
+	if err := filter.Validate(); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	year := 0
+	if filter.Year != nil {
+		year = *filter.Year
+	}
+
+	result, err := c.companyService.GetCalendar(r.Context(), companyID, year)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
+// CreateHoliday implements CompanyHandler.
+func (c *CompanyHandlerImpl) CreateHoliday(w http.ResponseWriter, r *http.Request) {
+	var req company.CreateHolidayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request format", nil)
+		return
+	}
+
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		response.HandleError(w, auth.ErrInvalidToken)
+		return
+	}
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		response.HandleError(w, auth.ErrInvalidToken)
+		return
+	}
+
+	holiday, err := c.companyService.CreateHoliday(r.Context(), companyID, req)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Created(w, "Holiday created successfully", holiday)
+}
+
+// DeleteHoliday implements CompanyHandler.
+func (c *CompanyHandlerImpl) DeleteHoliday(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		response.BadRequest(w, "Holiday ID is required", nil)
+		return
+	}
+
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		response.HandleError(w, auth.ErrInvalidToken)
+		return
+	}
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		response.HandleError(w, auth.ErrInvalidToken)
+		return
+	}
+
+	if err := c.companyService.DeleteHoliday(r.Context(), companyID, id); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Holiday deleted successfully", nil)
+}
+
 // Delete implements CompanyHandler.
 func (c *CompanyHandlerImpl) Delete(w http.ResponseWriter, r *http.Request) {
 	// Get company_id from JWT
@@ -18,6 +18,8 @@ type DashboardHandler interface {
 	GetMonthlyAttendance(w http.ResponseWriter, r *http.Request)
 	// GetDailyAttendanceStats returns attendance stats for a day
 	GetDailyAttendanceStats(w http.ResponseWriter, r *http.Request)
+	// GetHeadcountAnalytics returns headcount breakdowns and 12-month trends
+	GetHeadcountAnalytics(w http.ResponseWriter, r *http.Request)
 }
 
 type dashboardHandlerImpl struct {
@@ -90,3 +92,20 @@ func (h *dashboardHandlerImpl) GetDailyAttendanceStats(w http.ResponseWriter, r
 
 	response.Success(w, result)
 }
+
+// GetHeadcountAnalytics handles GET /dashboard/headcount-analytics
+func (h *dashboardHandlerImpl) GetHeadcountAnalytics(w http.ResponseWriter, r *http.Request) {
+	companyID, ok := getCompanyIDFromContext(r)
+	if !ok {
+		response.Forbidden(w, "no company associated with this user")
+		return
+	}
+
+	result, err := h.dashboardService.GetHeadcountAnalytics(r.Context(), companyID)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -16,14 +17,18 @@ import (
 type EmployeeHandler interface {
 	SearchEmployees(w http.ResponseWriter, r *http.Request)
 	GetEmployee(w http.ResponseWriter, r *http.Request)
+	GetProfile(w http.ResponseWriter, r *http.Request)
+	ExportEmployeeData(w http.ResponseWriter, r *http.Request)
 	CreateEmployee(w http.ResponseWriter, r *http.Request)
 	UpdateEmployee(w http.ResponseWriter, r *http.Request)
 	DeleteEmployee(w http.ResponseWriter, r *http.Request)
 	ListEmployees(w http.ResponseWriter, r *http.Request)
 	InactivateEmployee(w http.ResponseWriter, r *http.Request)
+	AnonymizeEmployee(w http.ResponseWriter, r *http.Request)
 	UploadAvatar(w http.ResponseWriter, r *http.Request)
 	ResendInvitation(w http.ResponseWriter, r *http.Request)
 	RevokeInvitation(w http.ResponseWriter, r *http.Request)
+	SetBranchScope(w http.ResponseWriter, r *http.Request)
 }
 
 type employeeHandlerImpl struct {
@@ -88,6 +93,47 @@ func (h *employeeHandlerImpl) GetEmployee(w http.ResponseWriter, r *http.Request
 	response.Success(w, result)
 }
 
+// GetProfile implements EmployeeHandler
+func (h *employeeHandlerImpl) GetProfile(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		response.BadRequest(w, "Employee ID is required", nil)
+		return
+	}
+
+	result, err := h.employeeService.GetProfile(r.Context(), id)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
+// ExportEmployeeData implements EmployeeHandler - downloads everything stored about the employee as JSON
+func (h *employeeHandlerImpl) ExportEmployeeData(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		response.BadRequest(w, "Employee ID is required", nil)
+		return
+	}
+
+	result, err := h.employeeService.ExportEmployeeData(r.Context(), id)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	response.File(w, "application/json", fmt.Sprintf("employee-data-%s.json", result.Profile.EmployeeCode), data)
+}
+
 // CreateEmployee implements EmployeeHandler
 func (h *employeeHandlerImpl) CreateEmployee(w http.ResponseWriter, r *http.Request) {
 	var req employee.CreateEmployeeRequest
@@ -97,9 +143,7 @@ func (h *employeeHandlerImpl) CreateEmployee(w http.ResponseWriter, r *http.Requ
 	// Check if it's multipart form (with file upload)
 	if len(contentType) >= 19 && contentType[:19] == "multipart/form-data" {
 		// Parse multipart form (max 10MB)
-		if err := r.ParseMultipartForm(10 << 20); err != nil {
-			slog.Error("Failed to parse multipart form", "error", err)
-			response.BadRequest(w, "Failed to parse form data", nil)
+		if !parseMultipartForm(w, r) {
 			return
 		}
 
@@ -198,6 +242,12 @@ func (h *employeeHandlerImpl) ListEmployees(w http.ResponseWriter, r *http.Reque
 	if search := r.URL.Query().Get("search"); search != "" {
 		filter.Search = &search
 	}
+	if nik := r.URL.Query().Get("nik"); nik != "" {
+		filter.NIK = &nik
+	}
+	if phoneNumber := r.URL.Query().Get("phone_number"); phoneNumber != "" {
+		filter.PhoneNumber = &phoneNumber
+	}
 
 	// Filters
 	if workScheduleID := r.URL.Query().Get("work_schedule_id"); workScheduleID != "" {
@@ -312,6 +362,54 @@ func (h *employeeHandlerImpl) InactivateEmployee(w http.ResponseWriter, r *http.
 	response.SuccessWithMessage(w, "Employee inactivated successfully", result)
 }
 
+// AnonymizeEmployee implements EmployeeHandler
+// POST /api/v1/employees/{id}/anonymize - user.manage only
+func (h *employeeHandlerImpl) AnonymizeEmployee(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		response.BadRequest(w, "Employee ID is required", nil)
+		return
+	}
+
+	result, err := h.employeeService.Anonymize(r.Context(), id)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Employee anonymized successfully", result)
+}
+
+// SetBranchScope implements EmployeeHandler
+// PUT /api/v1/employees/{id}/branch-scope - Owner only
+func (h *employeeHandlerImpl) SetBranchScope(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		response.BadRequest(w, "Employee ID is required", nil)
+		return
+	}
+
+	var req employee.SetBranchScopeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request format", nil)
+		return
+	}
+	req.EmployeeID = id
+
+	if err := req.Validate(); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	result, err := h.employeeService.SetBranchScope(r.Context(), req)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Branch scope updated successfully", result)
+}
+
 // UploadAvatar implements EmployeeHandler
 func (h *employeeHandlerImpl) UploadAvatar(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -320,10 +418,8 @@ func (h *employeeHandlerImpl) UploadAvatar(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Parse multipart form (max 5MB)
-	if err := r.ParseMultipartForm(5 << 20); err != nil {
-		slog.Error("Failed to parse multipart form", "error", err)
-		response.BadRequest(w, "Failed to parse form data", nil)
+	// Parse multipart form; the avatar size itself is checked by the request validation
+	if !parseMultipartForm(w, r) {
 		return
 	}
 
@@ -2,6 +2,7 @@ package http
 
 import (
 	"net/http"
+	"strconv"
 
 	empDashboard "github.com/cmlabs-hris/hris-backend-go/internal/domain/employee_dashboard"
 	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
@@ -14,6 +15,8 @@ type EmployeeDashboardHandler interface {
 	GetWorkStats(w http.ResponseWriter, r *http.Request)
 	// GetAttendanceSummary returns attendance summary for a month
 	GetAttendanceSummary(w http.ResponseWriter, r *http.Request)
+	// GetMyAttendanceRecap returns the caller's attendance recap for a month
+	GetMyAttendanceRecap(w http.ResponseWriter, r *http.Request)
 	// GetLeaveSummary returns leave quota summary for a year
 	GetLeaveSummary(w http.ResponseWriter, r *http.Request)
 	// GetWorkHoursChart returns daily work hours for a specific week
@@ -73,6 +76,46 @@ func (h *employeeDashboardHandlerImpl) GetAttendanceSummary(w http.ResponseWrite
 	response.Success(w, result)
 }
 
+// GetMyAttendanceRecap handles GET /my-dashboard/attendance-recap
+// Query params:
+//   - month: 1-12 (default: current month)
+//   - year: YYYY (default: current year)
+func (h *employeeDashboardHandlerImpl) GetMyAttendanceRecap(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r)
+	if userID == "" {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	month, err := parseOptionalIntQuery(r, "month")
+	if err != nil {
+		response.BadRequest(w, "month must be a number", nil)
+		return
+	}
+	year, err := parseOptionalIntQuery(r, "year")
+	if err != nil {
+		response.BadRequest(w, "year must be a number", nil)
+		return
+	}
+
+	result, err := h.service.GetMyAttendanceRecap(r.Context(), userID, month, year)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
+// parseOptionalIntQuery returns 0 when the query parameter is absent
+func parseOptionalIntQuery(r *http.Request, key string) (int, error) {
+	val := r.URL.Query().Get(key)
+	if val == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(val)
+}
+
 // GetLeaveSummary handles GET /my-dashboard/leave-summary
 // Query params:
 //   - year: YYYY (default: current year)
@@ -1,14 +1,21 @@
 package http
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log/slog"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
 	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/ical"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/jwt"
 	"github.com/cmlabs-hris/hris-backend-go/internal/service/file"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth/v5"
@@ -19,6 +26,8 @@ type LeaveHandler interface {
 	UpdateType(w http.ResponseWriter, r *http.Request)
 	ListTypes(w http.ResponseWriter, r *http.Request)
 	DeleteType(w http.ResponseWriter, r *http.Request)
+	PreviewQuota(w http.ResponseWriter, r *http.Request)
+	GetTypeStats(w http.ResponseWriter, r *http.Request)
 
 	SetQuota(w http.ResponseWriter, r *http.Request)
 	ListQuota(w http.ResponseWriter, r *http.Request)
@@ -27,16 +36,30 @@ type LeaveHandler interface {
 	GetQuota(w http.ResponseWriter, r *http.Request)
 
 	ListRequests(w http.ResponseWriter, r *http.Request)
+	ExportRequests(w http.ResponseWriter, r *http.Request)
 	GetMyRequests(w http.ResponseWriter, r *http.Request)
+	ListEmployeeRequests(w http.ResponseWriter, r *http.Request)
 	GetRequest(w http.ResponseWriter, r *http.Request)
+	DownloadAttachment(w http.ResponseWriter, r *http.Request)
 	CreateRequest(w http.ResponseWriter, r *http.Request)
 	ApproveRequest(w http.ResponseWriter, r *http.Request)
 	RejectRequest(w http.ResponseWriter, r *http.Request)
+	CreateRecurringRequest(w http.ResponseWriter, r *http.Request)
+	ApproveRecurringRequest(w http.ResponseWriter, r *http.Request)
+	RecomputeRequest(w http.ResponseWriter, r *http.Request)
+	ReopenRequest(w http.ResponseWriter, r *http.Request)
+	UpdateMyRequest(w http.ResponseWriter, r *http.Request)
+	RejectRecurringRequest(w http.ResponseWriter, r *http.Request)
+	CountPendingApprovals(w http.ResponseWriter, r *http.Request)
+
+	RegenerateCalendarFeedToken(w http.ResponseWriter, r *http.Request)
+	CalendarFeed(w http.ResponseWriter, r *http.Request)
 }
 
 type LeaveHandlerImpl struct {
 	leaveService leave.LeaveService
 	fileService  file.FileService
+	jwtService   jwt.Service
 }
 
 // GetQuota implements LeaveHandler.
@@ -60,6 +83,83 @@ func (l *LeaveHandlerImpl) GetQuota(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, leaveQuota)
 }
 
+// PreviewQuota implements LeaveHandler.
+// GET /api/v1/leave/types/{id}/quota-preview?employee_id=&year= - the quota the type's rules would give, without saving it
+func (l *LeaveHandlerImpl) PreviewQuota(w http.ResponseWriter, r *http.Request) {
+	leaveTypeID := chi.URLParam(r, "id")
+	if leaveTypeID == "" {
+		response.BadRequest(w, "Leave type ID is required", nil)
+		return
+	}
+
+	filter := leave.QuotaPreviewFilter{EmployeeID: r.URL.Query().Get("employee_id")}
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil {
+			response.BadRequest(w, "Invalid year", nil)
+			return
+		}
+		filter.Year = &year
+	}
+	if err := filter.Validate(); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	year := 0
+	if filter.Year != nil {
+		year = *filter.Year
+	}
+
+	preview, err := l.leaveService.PreviewQuota(r.Context(), leaveTypeID, filter.EmployeeID, year)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, preview)
+}
+
+// GetTypeStats implements LeaveHandler.
+func (l *LeaveHandlerImpl) GetTypeStats(w http.ResponseWriter, r *http.Request) {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		response.Unauthorized(w, "Failed to extract claims from context")
+		return
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		response.Unauthorized(w, "company_id claim is missing or invalid")
+		return
+	}
+
+	var filter leave.LeaveTypeStatsFilter
+	query := r.URL.Query()
+	if yearStr := query.Get("year"); yearStr != "" {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil {
+			response.BadRequest(w, "Invalid year", nil)
+			return
+		}
+		filter.Year = &year
+	}
+	if branchID := query.Get("branch_id"); branchID != "" {
+		filter.BranchID = &branchID
+	}
+	if positionID := query.Get("position_id"); positionID != "" {
+		filter.PositionID = &positionID
+	}
+
+	stats, err := l.leaveService.GetLeaveTypeStats(r.Context(), companyID, filter)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, stats)
+}
+
 // AdjustQuota implements LeaveHandler.
 func (l *LeaveHandlerImpl) AdjustQuota(w http.ResponseWriter, r *http.Request) {
 	var req leave.AdjustQuotaRequest
@@ -100,12 +200,13 @@ func (l *LeaveHandlerImpl) ApproveRequest(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if err := l.leaveService.ApproveLeaveRequest(r.Context(), req.RequestID); err != nil {
+	result, err := l.leaveService.ApproveLeaveRequest(r.Context(), req.RequestID)
+	if err != nil {
 		response.HandleError(w, err)
 		return
 	}
 
-	response.SuccessWithMessage(w, "Leave request approved successfully", nil)
+	response.SuccessWithMessage(w, "Leave request approved successfully", result)
 }
 
 // CreateRequest implements LeaveHandler.
@@ -127,9 +228,7 @@ func (l *LeaveHandlerImpl) CreateRequest(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
-		slog.Error("Failed to parse multipart form", "error", err)
-		response.BadRequest(w, "Failed to parse form data", nil)
+	if !parseMultipartForm(w, r) {
 		return
 	}
 
@@ -176,6 +275,169 @@ func (l *LeaveHandlerImpl) CreateRequest(w http.ResponseWriter, r *http.Request)
 	response.Created(w, "Leave request created successfully", leaveRequest)
 }
 
+// UpdateMyRequest implements LeaveHandler.
+// The body is multipart like CreateRequest: a JSON 'data' field and an optional 'attachment'.
+func (l *LeaveHandlerImpl) UpdateMyRequest(w http.ResponseWriter, r *http.Request) {
+	var req leave.UpdateMyLeaveRequestRequest
+
+	requestID := chi.URLParam(r, "id")
+	if requestID == "" {
+		response.BadRequest(w, "Request ID is required", nil)
+		return
+	}
+
+	if !parseMultipartForm(w, r) {
+		return
+	}
+
+	if dataJSON := r.FormValue("data"); dataJSON != "" {
+		if err := json.Unmarshal([]byte(dataJSON), &req); err != nil {
+			slog.Error("Failed to unmarshal JSON data", "error", err)
+			response.BadRequest(w, "Invalid request format", nil)
+			return
+		}
+	}
+
+	file, fileHeader, err := r.FormFile("attachment")
+	if err != nil && err != http.ErrMissingFile {
+		slog.Error("Failed to get file from form", "error", err)
+		response.BadRequest(w, "Invalid file upload", nil)
+		return
+	}
+	req.File = file
+	req.FileHeader = fileHeader
+
+	if err := req.Validate(); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	leaveRequest, err := l.leaveService.UpdateMyRequest(r.Context(), requestID, req)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Leave request updated successfully", leaveRequest)
+}
+
+// CreateRecurringRequest implements LeaveHandler.
+func (l *LeaveHandlerImpl) CreateRecurringRequest(w http.ResponseWriter, r *http.Request) {
+	var req leave.CreateRecurringLeaveRequest
+
+	// Get employee_id from JWT claims
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		slog.Error("Failed to get JWT claims", "error", err)
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	employeeID, ok := claims["employee_id"].(string)
+	if !ok || employeeID == "" {
+		slog.Error("employee_id not found in JWT claims")
+		response.Forbidden(w, "Employee ID not found in token")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Error("CreateRecurringRequest decode error", "error", err)
+		response.BadRequest(w, "Invalid request format", nil)
+		return
+	}
+
+	req.EmployeeID = employeeID
+
+	if err := req.Validate(); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	result, err := l.leaveService.CreateRecurringLeaveRequest(r.Context(), req)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Created(w, "Recurring leave requests created successfully", result)
+}
+
+// ApproveRecurringRequest implements LeaveHandler.
+func (l *LeaveHandlerImpl) ApproveRecurringRequest(w http.ResponseWriter, r *http.Request) {
+	recurrenceID := chi.URLParam(r, "recurrenceID")
+	if recurrenceID == "" {
+		response.BadRequest(w, "Recurrence ID is required", nil)
+		return
+	}
+
+	result, err := l.leaveService.ApproveRecurringLeave(r.Context(), recurrenceID)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Recurring leave approved successfully", result)
+}
+
+// RecomputeRequest implements LeaveHandler.
+func (l *LeaveHandlerImpl) RecomputeRequest(w http.ResponseWriter, r *http.Request) {
+	requestID := chi.URLParam(r, "id")
+	if requestID == "" {
+		response.BadRequest(w, "Request ID is required", nil)
+		return
+	}
+
+	result, err := l.leaveService.RecomputeLeaveRequest(r.Context(), requestID)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Leave request recomputed successfully", result)
+}
+
+// ReopenRequest implements LeaveHandler.
+func (l *LeaveHandlerImpl) ReopenRequest(w http.ResponseWriter, r *http.Request) {
+	requestID := chi.URLParam(r, "id")
+	if requestID == "" {
+		response.BadRequest(w, "Request ID is required", nil)
+		return
+	}
+
+	result, err := l.leaveService.ReopenRequest(r.Context(), requestID)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Leave request reopened successfully", result)
+}
+
+// RejectRecurringRequest implements LeaveHandler.
+func (l *LeaveHandlerImpl) RejectRecurringRequest(w http.ResponseWriter, r *http.Request) {
+	var req leave.RejectRecurringLeaveRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Error("RejectRecurringRequest decode error", "error", err)
+		response.BadRequest(w, "Invalid request format", nil)
+		return
+	}
+
+	req.RecurrenceID = chi.URLParam(r, "recurrenceID")
+
+	if err := req.Validate(); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	if err := l.leaveService.RejectRecurringLeave(r.Context(), req); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Recurring leave rejected successfully", nil)
+}
+
 // CreateType implements LeaveHandler.
 func (l *LeaveHandlerImpl) CreateType(w http.ResponseWriter, r *http.Request) {
 	var req leave.CreateLeaveTypeRequest
@@ -232,7 +494,7 @@ func (l *LeaveHandlerImpl) GetMyQuota(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	leaveQuota, err := l.leaveService.GetMyQuota(r.Context(), employeeID, time.Now().Year())
+	leaveQuota, err := l.leaveService.GetMyQuota(r.Context(), employeeID)
 	if err != nil {
 		response.HandleError(w, err)
 		return
@@ -263,7 +525,20 @@ func (l *LeaveHandlerImpl) GetMyRequests(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Parse query parameters
+	filter := myLeaveRequestFilterFromQuery(r)
+
+	// Call service
+	leaveRequestResponse, err := l.leaveService.ListMyLeaveRequests(ctx, employeeID, companyID, filter)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, leaveRequestResponse)
+}
+
+// myLeaveRequestFilterFromQuery reads the filters of the employee-scoped leave request lists
+func myLeaveRequestFilterFromQuery(r *http.Request) leave.MyLeaveRequestFilter {
 	filter := leave.MyLeaveRequestFilter{}
 
 	// Leave type filter
@@ -284,6 +559,7 @@ func (l *LeaveHandlerImpl) GetMyRequests(w http.ResponseWriter, r *http.Request)
 	if endDate := r.URL.Query().Get("end_date"); endDate != "" {
 		filter.EndDate = &endDate
 	}
+	filter.DateMode = r.URL.Query().Get("date_mode")
 
 	// Pagination
 	if page := r.URL.Query().Get("page"); page != "" {
@@ -307,8 +583,19 @@ func (l *LeaveHandlerImpl) GetMyRequests(w http.ResponseWriter, r *http.Request)
 		filter.SortOrder = sortOrder
 	}
 
-	// Call service
-	leaveRequestResponse, err := l.leaveService.ListMyLeaveRequests(ctx, employeeID, companyID, filter)
+	return filter
+}
+
+// ListEmployeeRequests implements LeaveHandler.
+// GET /api/v1/leave/requests/employee/{employeeID} - one employee's requests for HR, same filters as /my
+func (l *LeaveHandlerImpl) ListEmployeeRequests(w http.ResponseWriter, r *http.Request) {
+	employeeID := chi.URLParam(r, "employeeID")
+	if employeeID == "" {
+		response.BadRequest(w, "Employee ID is required", nil)
+		return
+	}
+
+	leaveRequestResponse, err := l.leaveService.ListEmployeeRequests(r.Context(), employeeID, myLeaveRequestFilterFromQuery(r))
 	if err != nil {
 		response.HandleError(w, err)
 		return
@@ -317,6 +604,53 @@ func (l *LeaveHandlerImpl) GetMyRequests(w http.ResponseWriter, r *http.Request)
 	response.Success(w, leaveRequestResponse)
 }
 
+// inlineAttachmentTypes are shown in the browser; anything else is always downloaded so an
+// uploaded HTML or SVG file can never run in the app's origin
+var inlineAttachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+}
+
+// DownloadAttachment implements LeaveHandler.
+// GET /api/v1/leave/requests/{id}/attachment - streams the attachment, honouring Range requests
+func (l *LeaveHandlerImpl) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	requestID := chi.URLParam(r, "id")
+	if requestID == "" {
+		response.BadRequest(w, "Request ID is required", nil)
+		return
+	}
+
+	attachment, err := l.leaveService.GetLeaveAttachment(r.Context(), requestID)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+	defer attachment.Content.Close()
+
+	// Range requests need to seek; buffer storage backends that only stream
+	content, ok := attachment.Content.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(attachment.Content)
+		if err != nil {
+			response.HandleError(w, fmt.Errorf("failed to read leave attachment: %w", err))
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+
+	disposition := "attachment"
+	if inlineAttachmentTypes[attachment.ContentType] {
+		disposition = "inline"
+	}
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-cache")
+	http.ServeContent(w, r, attachment.FileName, attachment.ModTime, content)
+}
+
 // GetRequests implements LeaveHandler.
 func (l *LeaveHandlerImpl) GetRequest(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -362,6 +696,123 @@ func (l *LeaveHandlerImpl) ListQuota(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, leaveTypeResponse)
 }
 
+// RegenerateCalendarFeedToken implements LeaveHandler.
+// Issues a new iCal feed URL for the caller; any previously issued URL stops working.
+func (l *LeaveHandlerImpl) RegenerateCalendarFeedToken(w http.ResponseWriter, r *http.Request) {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		response.Unauthorized(w, "Unauthorized")
+		return
+	}
+
+	employeeID, ok := claims["employee_id"].(string)
+	if !ok || employeeID == "" {
+		response.Forbidden(w, "Employee ID not found in token")
+		return
+	}
+
+	nonce, err := l.leaveService.RotateCalendarFeedNonce(r.Context(), employeeID)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	token, err := l.jwtService.GenerateCalendarFeedToken(employeeID, nonce)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	response.Success(w, leave.CalendarFeedTokenResponse{
+		Token:   token,
+		FeedURL: scheme + "://" + r.Host + "/api/v1/leave/requests/my.ics?token=" + url.QueryEscape(token),
+	})
+}
+
+// CalendarFeed implements LeaveHandler.
+// Calendar clients cannot send headers, so the feed is authenticated by the token in the query string.
+func (l *LeaveHandlerImpl) CalendarFeed(w http.ResponseWriter, r *http.Request) {
+	tokenStr := r.URL.Query().Get("token")
+	if tokenStr == "" {
+		response.Unauthorized(w, "Missing token")
+		return
+	}
+
+	employeeID, nonce, err := l.jwtService.ValidateCalendarFeedToken(tokenStr)
+	if err != nil {
+		response.Unauthorized(w, "Invalid token")
+		return
+	}
+
+	requests, err := l.leaveService.GetCalendarFeedRequests(r.Context(), employeeID, nonce)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	events := make([]ical.Event, 0, len(requests))
+	for _, req := range requests {
+		stamp := req.UpdatedAt
+		if req.ApprovedAt != nil {
+			stamp = *req.ApprovedAt
+		}
+
+		summary := "Leave"
+		if req.LeaveTypeName != nil {
+			summary = *req.LeaveTypeName
+		}
+		if req.DurationType == leave.LeaveDurationHalfDayMorning || req.DurationType == leave.LeaveDurationHalfDayAfternoon {
+			summary += " (half day)"
+		}
+
+		events = append(events, ical.Event{
+			UID:         req.ID + "@leave.hris",
+			Summary:     summary,
+			Description: req.Reason,
+			Start:       req.StartDate,
+			End:         req.EndDate,
+			Stamp:       stamp,
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="leave.ics"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	w.Write(ical.Encode("My Leave", events))
+}
+
+// CountPendingApprovals implements LeaveHandler.
+func (l *LeaveHandlerImpl) CountPendingApprovals(w http.ResponseWriter, r *http.Request) {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		response.Unauthorized(w, "Failed to extract claims from context")
+		return
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok || userID == "" {
+		response.Unauthorized(w, "user_id claim is missing or invalid")
+		return
+	}
+
+	count, err := l.leaveService.CountPendingApprovals(r.Context(), userID)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, leave.PendingApprovalCountResponse{Count: count})
+}
+
 // ListRequests implements LeaveHandler.
 func (l *LeaveHandlerImpl) ListRequests(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -408,6 +859,7 @@ func (l *LeaveHandlerImpl) ListRequests(w http.ResponseWriter, r *http.Request)
 	if endDate := r.URL.Query().Get("end_date"); endDate != "" {
 		filter.EndDate = &endDate
 	}
+	filter.DateMode = r.URL.Query().Get("date_mode")
 
 	// Pagination
 	page := 1
@@ -449,6 +901,81 @@ func (l *LeaveHandlerImpl) ListRequests(w http.ResponseWriter, r *http.Request)
 	response.Success(w, leaveRequestResponse)
 }
 
+// ExportRequests implements LeaveHandler.
+// GET /api/v1/leave/requests/export - same filters as ListRequests, without pagination
+func (l *LeaveHandlerImpl) ExportRequests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		response.Unauthorized(w, "Failed to extract claims from context")
+		return
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		response.Unauthorized(w, "company_id claim is missing or invalid")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := leave.LeaveRequestFilter{}
+	if employeeName := query.Get("employee_name"); employeeName != "" {
+		filter.EmployeeName = &employeeName
+	}
+	if employeeID := query.Get("employee_id"); employeeID != "" {
+		filter.EmployeeID = &employeeID
+	}
+	if leaveTypeID := query.Get("leave_type_id"); leaveTypeID != "" {
+		filter.LeaveTypeID = &leaveTypeID
+	}
+	if status := query.Get("status"); status != "" {
+		filter.Status = &status
+	}
+	if startDate := query.Get("start_date"); startDate != "" {
+		filter.StartDate = &startDate
+	}
+	if endDate := query.Get("end_date"); endDate != "" {
+		filter.EndDate = &endDate
+	}
+	filter.DateMode = query.Get("date_mode")
+	filter.SortBy = query.Get("sort_by")
+	filter.SortOrder = query.Get("sort_order")
+
+	if err := filter.Validate(); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("leave-requests-%s.csv", time.Now().Format("20060102"))
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Cache-Control", "no-store")
+
+	out := &exportWriter{w: w}
+	if err := l.leaveService.ExportLeaveRequests(ctx, companyID, filter, out); err != nil {
+		if out.written {
+			// The status line is already sent; all we can do is cut the download short
+			slog.Error("leave request export aborted", "company_id", companyID, "error", err)
+			return
+		}
+		w.Header().Del("Content-Disposition")
+		w.Header().Del("Cache-Control")
+		response.HandleError(w, err)
+	}
+}
+
+// exportWriter records whether a streamed download has started, so errors before the
+// first byte can still be sent as a normal JSON error response
+type exportWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	e.written = true
+	return e.w.Write(p)
+}
+
 // ListTypes implements LeaveHandler.
 func (l *LeaveHandlerImpl) ListTypes(w http.ResponseWriter, r *http.Request) {
 	_, claims, err := jwtauth.FromContext(r.Context())
@@ -532,9 +1059,10 @@ func (l *LeaveHandlerImpl) UpdateType(w http.ResponseWriter, r *http.Request) {
 	response.SuccessWithMessage(w, "Leave type updated successfully", nil)
 }
 
-func NewLeaveHandler(leaveService leave.LeaveService, fileService file.FileService) LeaveHandler {
+func NewLeaveHandler(leaveService leave.LeaveService, fileService file.FileService, jwtService jwt.Service) LeaveHandler {
 	return &LeaveHandlerImpl{
 		leaveService: leaveService,
 		fileService:  fileService,
+		jwtService:   jwtService,
 	}
 }
@@ -137,7 +137,10 @@ func (h *masterHandlerImpl) UpdateBranch(w http.ResponseWriter, r *http.Request)
 func (h *masterHandlerImpl) DeleteBranch(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := h.masterService.DeleteBranch(r.Context(), id); err != nil {
+	// Optional: move assigned employees to another branch before deleting
+	reassignTo := r.URL.Query().Get("reassign_to")
+
+	if err := h.masterService.DeleteBranch(r.Context(), id, reassignTo); err != nil {
 		response.HandleError(w, err)
 		return
 	}
@@ -232,7 +235,10 @@ func (h *masterHandlerImpl) UpdateGrade(w http.ResponseWriter, r *http.Request)
 func (h *masterHandlerImpl) DeleteGrade(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := h.masterService.DeleteGrade(r.Context(), id); err != nil {
+	// Optional: move assigned employees to another grade before deleting
+	reassignTo := r.URL.Query().Get("reassign_to")
+
+	if err := h.masterService.DeleteGrade(r.Context(), id, reassignTo); err != nil {
 		response.HandleError(w, err)
 		return
 	}
@@ -327,7 +333,10 @@ func (h *masterHandlerImpl) UpdatePosition(w http.ResponseWriter, r *http.Reques
 func (h *masterHandlerImpl) DeletePosition(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := h.masterService.DeletePosition(r.Context(), id); err != nil {
+	// Optional: move assigned employees to another position before deleting
+	reassignTo := r.URL.Query().Get("reassign_to")
+
+	if err := h.masterService.DeletePosition(r.Context(), id, reassignTo); err != nil {
 		response.HandleError(w, err)
 		return
 	}
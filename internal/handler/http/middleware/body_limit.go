@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
+)
+
+// LimitBodySize caps request bodies at maxBytes. Requests that declare a larger Content-Length
+// are rejected with 413 up front; others are cut off by http.MaxBytesReader, which handlers
+// report as 413 through response.HandleError.
+func LimitBodySize(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				response.PayloadTooLarge(w, fmt.Sprintf("Request body must not exceed %dMB", maxBytes>>20))
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
+)
+
+const testBodyLimit = 1 << 20
+
+// readingHandler reads the whole body and reports any read error the way handlers do
+func readingHandler(reached *bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*reached = true
+		if _, err := io.ReadAll(r.Body); err != nil {
+			response.HandleError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) response.ErrorDetail {
+	t.Helper()
+	var body response.Response
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body.Error == nil {
+		t.Fatalf("decode error response: %v (body %q)", err, rec.Body.String())
+	}
+	return *body.Error
+}
+
+func TestLimitBodySize(t *testing.T) {
+	tests := []struct {
+		name        string
+		size        int
+		chunked     bool
+		wantStatus  int
+		wantReached bool
+	}{
+		{"declared length at the limit", testBodyLimit, false, http.StatusNoContent, true},
+		{"declared length over the limit", testBodyLimit + 1, false, http.StatusRequestEntityTooLarge, false},
+		{"chunked body at the limit", testBodyLimit, true, http.StatusNoContent, true},
+		{"chunked body over the limit", testBodyLimit + 1, true, http.StatusRequestEntityTooLarge, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", tt.size)))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			reached := false
+
+			LimitBodySize(testBodyLimit)(readingHandler(&reached)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if reached != tt.wantReached {
+				t.Errorf("handler reached = %v, want %v", reached, tt.wantReached)
+			}
+			if tt.wantStatus == http.StatusRequestEntityTooLarge {
+				detail := decodeError(t, rec)
+				if detail.Code != "PAYLOAD_TOO_LARGE" || detail.Message != "Request body must not exceed 1MB" {
+					t.Errorf("error = %s %q, want PAYLOAD_TOO_LARGE with the 1MB limit", detail.Code, detail.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestLimitBodySizeReturnsMaxBytesError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", testBodyLimit+1)))
+	req.ContentLength = -1
+	var readErr error
+
+	LimitBodySize(testBodyLimit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	})).ServeHTTP(httptest.NewRecorder(), req)
+
+	var maxBytesErr *http.MaxBytesError
+	if !errors.As(readErr, &maxBytesErr) || maxBytesErr.Limit != testBodyLimit {
+		t.Fatalf("read error = %v, want *http.MaxBytesError with limit %d", readErr, testBodyLimit)
+	}
+}
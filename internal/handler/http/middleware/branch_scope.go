@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/scope"
+	"github.com/go-chi/jwtauth/v5"
+)
+
+// BranchScopeMiddleware resolves the branch a manager is restricted to
+type BranchScopeMiddleware struct {
+	userRepo user.UserRepository
+}
+
+// NewBranchScopeMiddleware creates a new branch scope middleware
+func NewBranchScopeMiddleware(userRepo user.UserRepository) *BranchScopeMiddleware {
+	return &BranchScopeMiddleware{userRepo: userRepo}
+}
+
+// ResolveBranchScope stores a branch-scoped manager's branch in the request context
+// The scope is looked up per request rather than read from the token, so changes apply immediately.
+// Owners, employees and company-wide managers pass through unscoped.
+func (m *BranchScopeMiddleware) ResolveBranchScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, claims, err := jwtauth.FromContext(r.Context())
+		if err != nil {
+			response.Unauthorized(w, "unauthorized")
+			return
+		}
+
+		role, _ := claims["role"].(string)
+		if user.Role(role) != user.RoleManager {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userID, _ := claims["user_id"].(string)
+		if userID == "" {
+			response.Unauthorized(w, "unauthorized")
+			return
+		}
+
+		// Fail closed: a manager whose scope cannot be resolved must not fall back to company-wide access
+		u, err := m.userRepo.GetByID(r.Context(), userID)
+		if err != nil {
+			response.InternalServerError(w, "failed to resolve branch scope")
+			return
+		}
+
+		if !u.IsBranchScoped() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(scope.WithBranchID(r.Context(), *u.ManagedBranchID)))
+	})
+}
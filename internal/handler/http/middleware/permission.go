@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/role"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/scope"
+	"github.com/go-chi/jwtauth/v5"
+)
+
+// PermissionMiddleware resolves a user's effective permissions
+type PermissionMiddleware struct {
+	roleRepo role.RoleRepository
+}
+
+// NewPermissionMiddleware creates a new permission middleware
+func NewPermissionMiddleware(roleRepo role.RoleRepository) *PermissionMiddleware {
+	return &PermissionMiddleware{roleRepo: roleRepo}
+}
+
+// ResolvePermissions stores the union of the base role's defaults and the user's company role
+// in the request context for RequirePermission and service-level checks.
+// Owners already hold every permission and users without a company role keep the defaults,
+// so nothing is stored for them.
+func (m *PermissionMiddleware) ResolvePermissions(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, claims, err := jwtauth.FromContext(r.Context())
+		if err != nil {
+			response.Unauthorized(w, "unauthorized")
+			return
+		}
+
+		roleStr, _ := claims["role"].(string)
+		baseRole := user.Role(roleStr)
+		if baseRole == user.RoleOwner || baseRole == user.RolePending {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userID, _ := claims["user_id"].(string)
+		if userID == "" {
+			response.Unauthorized(w, "unauthorized")
+			return
+		}
+
+		extra, err := m.roleRepo.GetPermissionsByUserID(r.Context(), userID)
+		if err != nil {
+			response.InternalServerError(w, "failed to resolve permissions")
+			return
+		}
+
+		if len(extra) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		permissions := append(append([]user.Permission{}, user.RolePermissions[baseRole]...), extra...)
+		next.ServeHTTP(w, r.WithContext(scope.WithPermissions(r.Context(), permissions)))
+	})
+}
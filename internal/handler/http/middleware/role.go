@@ -6,6 +6,7 @@ import (
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
 	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/scope"
 	"github.com/go-chi/jwtauth/v5"
 )
 
@@ -83,6 +84,8 @@ func RequirePending(next http.Handler) http.Handler {
 }
 
 // RequirePermission checks if user has specific permission
+// Uses the permissions resolved by PermissionMiddleware (base role plus company role),
+// falling back to the base role's defaults.
 func RequirePermission(permission user.Permission) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -92,15 +95,9 @@ func RequirePermission(permission user.Permission) func(http.Handler) http.Handl
 				return
 			}
 
-			roleStr, ok := claims["role"].(string)
-			if !ok {
-				response.Forbidden(w, fmt.Sprintf("Insufficient permissions: required '%s'", permission))
-				return
-			}
-
-			role := user.Role(roleStr)
-			if !user.HasPermission(role, permission) {
-				response.Forbidden(w, fmt.Sprintf("Insufficient permissions: required '%s', but user role is '%s'", permission, role))
+			if !scope.Can(r.Context(), permission) {
+				roleStr, _ := claims["role"].(string)
+				response.Forbidden(w, fmt.Sprintf("Insufficient permissions: required '%s', but user role is '%s'", permission, roleStr))
 				return
 			}
 
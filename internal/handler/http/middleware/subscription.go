@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
@@ -9,16 +12,141 @@ import (
 	"github.com/go-chi/jwtauth/v5"
 )
 
+// statusCacheTTL bounds how long a company's subscription status is reused across requests
+// Kept short so a payment or plan change is picked up within seconds
+const statusCacheTTL = 30 * time.Second
+
+// statusCacheMaxEntries caps the cache; when it is full of fresh entries the oldest one is evicted
+const statusCacheMaxEntries = 10000
+
 // SubscriptionMiddleware provides middleware functions for subscription checks
 type SubscriptionMiddleware struct {
 	subscriptionService subscription.SubscriptionService
+
+	mu          sync.Mutex
+	statusCache map[string]cachedSubscription
+	lastSweep   time.Time // When expired entries were last removed
+	now         func() time.Time
+}
+
+// cachedSubscription is the subscription state of a company at fetchedAt
+type cachedSubscription struct {
+	status    subscription.SubscriptionStatus
+	features  featureSet
+	fetchedAt time.Time
 }
 
 // NewSubscriptionMiddleware creates a new subscription middleware
 func NewSubscriptionMiddleware(subscriptionService subscription.SubscriptionService) *SubscriptionMiddleware {
 	return &SubscriptionMiddleware{
 		subscriptionService: subscriptionService,
+		statusCache:         make(map[string]cachedSubscription),
+		now:                 time.Now,
+	}
+}
+
+// EnforceSubscriptionAccess restricts business routes by subscription status
+// active, trial and cancelled (until period end): full access
+// past_due (grace period): read-only, mutating methods are rejected
+// expired or missing: blocked
+// Billing routes must be mounted outside this middleware so the company can recover.
+func (m *SubscriptionMiddleware) EnforceSubscriptionAccess(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, claims, err := jwtauth.FromContext(r.Context())
+		if err != nil {
+			response.Unauthorized(w, "unauthorized")
+			return
+		}
+
+		// Users without a company (e.g. pending invitees) have no subscription to enforce
+		companyID, ok := claims["company_id"].(string)
+		if !ok || companyID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cached, err := m.loadSubscription(r.Context(), companyID)
+		if err != nil {
+			if errors.Is(err, subscription.ErrSubscriptionNotFound) {
+				response.HandleError(w, subscription.ErrSubscriptionNotFound)
+				return
+			}
+			response.InternalServerError(w, "failed to check subscription status")
+			return
+		}
+
+		switch cached.status {
+		case subscription.StatusActive, subscription.StatusTrial, subscription.StatusCancelled:
+		case subscription.StatusPastDue:
+			if !isReadOnlyMethod(r.Method) {
+				response.HandleError(w, subscription.ErrSubscriptionPastDue)
+				return
+			}
+		default:
+			response.HandleError(w, subscription.ErrSubscriptionExpired)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withFeatureSet(r.Context(), cached.features)))
+	})
+}
+
+// loadSubscription returns the company's subscription state, served from the cache while fresh
+func (m *SubscriptionMiddleware) loadSubscription(ctx context.Context, companyID string) (cachedSubscription, error) {
+	now := m.now()
+
+	m.mu.Lock()
+	cached, ok := m.statusCache[companyID]
+	m.mu.Unlock()
+	if ok && now.Sub(cached.fetchedAt) < statusCacheTTL {
+		return cached, nil
+	}
+
+	sub, err := m.subscriptionService.GetMySubscription(ctx, companyID)
+	if err != nil {
+		return cachedSubscription{}, err
+	}
+
+	cached = cachedSubscription{
+		status:    subscription.SubscriptionStatus(sub.Status),
+		features:  newFeatureSet(sub.Features),
+		fetchedAt: now,
+	}
+
+	m.storeSubscription(companyID, cached)
+
+	return cached, nil
+}
+
+// storeSubscription caches a company's subscription state. Expired entries are swept at most
+// once per TTL, or when the cache is full, so companies that stopped sending requests do not
+// stay in memory; the size stays within statusCacheMaxEntries.
+func (m *SubscriptionMiddleware) storeSubscription(companyID string, cached cachedSubscription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := cached.fetchedAt
+	_, refresh := m.statusCache[companyID]
+	if (!refresh && len(m.statusCache) >= statusCacheMaxEntries) || now.Sub(m.lastSweep) >= statusCacheTTL {
+		for id, entry := range m.statusCache {
+			if now.Sub(entry.fetchedAt) >= statusCacheTTL {
+				delete(m.statusCache, id)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	if !refresh && len(m.statusCache) >= statusCacheMaxEntries {
+		oldestID, oldest := "", now
+		for id, entry := range m.statusCache {
+			if oldestID == "" || entry.fetchedAt.Before(oldest) {
+				oldestID, oldest = id, entry.fetchedAt
+			}
+		}
+		delete(m.statusCache, oldestID)
 	}
+
+	m.statusCache[companyID] = cached
 }
 
 // RequireActiveSubscription checks if the company has an active subscription
@@ -71,24 +199,21 @@ func (m *SubscriptionMiddleware) RequireActiveSubscription(next http.Handler) ht
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		// Cache the feature set so downstream RequireFeature checks skip the database
+		next.ServeHTTP(w, r.WithContext(withFeatureSet(r.Context(), newFeatureSet(sub.Features))))
 	})
 }
 
-// RequireFeature checks if the company's subscription has access to a specific feature
-// This performs a double check: JWT claims + database verification
+// RequireFeature checks if the company's subscription includes a specific feature
+// The subscription's feature set is loaded once per request and cached in the request context,
+// so stacking several RequireFeature calls on a route costs a single database lookup.
+// Responds 402 when there is no usable subscription and 403 when the plan lacks the feature.
 func (m *SubscriptionMiddleware) RequireFeature(featureCode string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			token, _, err := jwtauth.FromContext(r.Context())
-			if err != nil || token == nil {
-				response.Unauthorized(w, "unauthorized")
-				return
-			}
-
-			claims, err := token.AsMap(r.Context())
+			_, claims, err := jwtauth.FromContext(r.Context())
 			if err != nil {
-				response.Unauthorized(w, "invalid token claims")
+				response.Unauthorized(w, "unauthorized")
 				return
 			}
 
@@ -99,30 +224,26 @@ func (m *SubscriptionMiddleware) RequireFeature(featureCode string) func(http.Ha
 				return
 			}
 
-			// Quick check from JWT claims (if features are included)
-			// This is a fast-path optimization
-			if features, ok := claims["features"].([]interface{}); ok {
-				hasFeature := false
-				for _, f := range features {
-					if code, ok := f.(string); ok && code == featureCode {
-						hasFeature = true
-						break
+			features, ok := featureSetFromContext(r.Context())
+			if !ok {
+				sub, err := m.subscriptionService.GetMySubscription(r.Context(), companyID)
+				if err != nil {
+					if errors.Is(err, subscription.ErrSubscriptionNotFound) {
+						response.PaymentRequired(w, "an active subscription is required to use this feature")
+						return
 					}
+					response.InternalServerError(w, "failed to check feature access")
+					return
 				}
-				if hasFeature {
-					next.ServeHTTP(w, r)
+				if !isActiveStatus(subscription.SubscriptionStatus(sub.Status)) {
+					response.PaymentRequired(w, "subscription is not active")
 					return
 				}
+				features = newFeatureSet(sub.Features)
+				r = r.WithContext(withFeatureSet(r.Context(), features))
 			}
 
-			// DB verification: Check feature access in database
-			hasFeature, err := m.subscriptionService.HasFeature(r.Context(), companyID, featureCode)
-			if err != nil {
-				response.InternalServerError(w, "failed to check feature access")
-				return
-			}
-
-			if !hasFeature {
+			if !features.has(featureCode) {
 				response.HandleError(w, subscription.ErrFeatureNotAvailable)
 				return
 			}
@@ -181,7 +302,46 @@ func isActiveStatus(status subscription.SubscriptionStatus) bool {
 	}
 }
 
+// featureSetKey is the request context key for the cached subscription feature set
+type featureSetKey struct{}
+
+// featureSet is the set of feature codes included in a company's subscription
+type featureSet map[string]struct{}
+
+func newFeatureSet(codes []string) featureSet {
+	set := make(featureSet, len(codes))
+	for _, code := range codes {
+		set[code] = struct{}{}
+	}
+	return set
+}
+
+func (s featureSet) has(code string) bool {
+	_, ok := s[code]
+	return ok
+}
+
+func withFeatureSet(ctx context.Context, set featureSet) context.Context {
+	return context.WithValue(ctx, featureSetKey{}, set)
+}
+
+func featureSetFromContext(ctx context.Context) (featureSet, bool) {
+	set, ok := ctx.Value(featureSetKey{}).(featureSet)
+	return set, ok
+}
+
+// isReadOnlyMethod reports whether an HTTP method does not modify data
+func isReadOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
 // Feature codes for easy reference - Must match database feature codes
+// See the "Feature Gating" section of the README for the routes each code protects
 const (
 	FeatureAttendance = "attendance" // Clock in/out, attendance tracking
 	FeatureLeave      = "leave"      // Leave requests, approvals, quota management
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+)
+
+// fakeSubscriptionService answers every company with an active subscription and counts lookups
+type fakeSubscriptionService struct {
+	subscription.SubscriptionService
+	lookups int
+}
+
+func (s *fakeSubscriptionService) GetMySubscription(context.Context, string) (subscription.SubscriptionResponse, error) {
+	s.lookups++
+	return subscription.SubscriptionResponse{Status: subscription.StatusActive}, nil
+}
+
+func TestStatusCacheSweepsExpiredEntries(t *testing.T) {
+	service := &fakeSubscriptionService{}
+	m := NewSubscriptionMiddleware(service)
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 100; i++ {
+		if _, err := m.loadSubscription(ctx, fmt.Sprintf("company-%d", i)); err != nil {
+			t.Fatalf("loadSubscription() error = %v", err)
+		}
+	}
+
+	// Fresh entries are served from the cache
+	now = now.Add(statusCacheTTL - time.Second)
+	if _, _ = m.loadSubscription(ctx, "company-0"); service.lookups != 100 {
+		t.Errorf("lookups = %d, want the fresh entry reused", service.lookups)
+	}
+
+	// Once they expire, the next store drops every company that has not been seen since
+	now = now.Add(time.Second)
+	if _, _ = m.loadSubscription(ctx, "company-new"); len(m.statusCache) != 1 {
+		t.Errorf("cache size = %d, want only the new entry after the sweep", len(m.statusCache))
+	}
+}
+
+func TestStatusCacheIsBounded(t *testing.T) {
+	m := NewSubscriptionMiddleware(&fakeSubscriptionService{})
+	start := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	// All entries stay fresh, so only the size bound can evict
+	for i := 0; i <= statusCacheMaxEntries; i++ {
+		m.storeSubscription(fmt.Sprintf("company-%d", i), cachedSubscription{status: subscription.StatusActive, fetchedAt: start.Add(time.Duration(i) * time.Microsecond)})
+	}
+
+	if len(m.statusCache) != statusCacheMaxEntries {
+		t.Errorf("cache size = %d, want %d", len(m.statusCache), statusCacheMaxEntries)
+	}
+	if _, ok := m.statusCache["company-0"]; ok {
+		t.Error("the oldest entry was kept")
+	}
+	if _, ok := m.statusCache[fmt.Sprintf("company-%d", statusCacheMaxEntries)]; !ok {
+		t.Error("the newest entry was not stored")
+	}
+
+	// Refreshing a cached company never evicts another one
+	m.storeSubscription("company-1", cachedSubscription{status: subscription.StatusPastDue, fetchedAt: start.Add(time.Second)})
+	if len(m.statusCache) != statusCacheMaxEntries || m.statusCache["company-1"].status != subscription.StatusPastDue {
+		t.Errorf("cache size = %d after a refresh, want %d", len(m.statusCache), statusCacheMaxEntries)
+	}
+}
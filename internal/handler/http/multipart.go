@@ -0,0 +1,32 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
+)
+
+// multipartMemoryLimit is how much of a multipart body is held in memory; larger file parts
+// are spooled to temporary files. The body as a whole is capped by middleware.LimitBodySize.
+const multipartMemoryLimit = 2 << 20
+
+// parseMultipartForm parses a multipart request and writes the error response when it fails:
+// 413 when the body exceeds the request size cap, 400 otherwise. It reports whether to continue.
+func parseMultipartForm(w http.ResponseWriter, r *http.Request) bool {
+	err := r.ParseMultipartForm(multipartMemoryLimit)
+	if err == nil {
+		return true
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		response.HandleError(w, err)
+		return false
+	}
+
+	slog.Error("Failed to parse multipart form", "error", err)
+	response.BadRequest(w, "Failed to parse form data", nil)
+	return false
+}
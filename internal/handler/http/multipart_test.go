@@ -0,0 +1,109 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/middleware"
+	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
+)
+
+const testMaxBodyBytes = 4 << 20
+
+// multipartBody builds a form with one text field and one file of fileSize bytes
+func multipartBody(t *testing.T, fileSize int) (*bytes.Buffer, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	if err := mw.WriteField("description", "medical certificate"); err != nil {
+		t.Fatal(err)
+	}
+	part, err := mw.CreateFormFile("attachment", "letter.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(bytes.Repeat([]byte("x"), fileSize)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf, mw.FormDataContentType()
+}
+
+// serveMultipart runs parseMultipartForm behind the body size cap, as the router mounts it
+func serveMultipart(req *http.Request) (*httptest.ResponseRecorder, bool) {
+	rec := httptest.NewRecorder()
+	parsed := false
+	handler := middleware.LimitBodySize(testMaxBodyBytes)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if parsed = parseMultipartForm(w, r); parsed {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	handler.ServeHTTP(rec, req)
+	return rec, parsed
+}
+
+func TestParseMultipartForm(t *testing.T) {
+	tests := []struct {
+		name       string
+		fileSize   int
+		chunked    bool
+		wantStatus int
+		wantCode   string
+	}{
+		{"small form in memory", 1 << 10, false, http.StatusNoContent, ""},
+		{"file spooled past the memory limit", multipartMemoryLimit + 1, false, http.StatusNoContent, ""},
+		{"declared length over the cap", testMaxBodyBytes, false, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE"},
+		{"chunked body over the cap", testMaxBodyBytes, true, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType := multipartBody(t, tt.fileSize)
+			req := httptest.NewRequest(http.MethodPost, "/", body)
+			req.Header.Set("Content-Type", contentType)
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+
+			rec, parsed := serveMultipart(req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantCode == "" {
+				if !parsed || req.MultipartForm == nil || len(req.MultipartForm.File["attachment"]) != 1 {
+					t.Errorf("parsed = %v, want the attachment in the form", parsed)
+				}
+				if req.MultipartForm != nil {
+					req.MultipartForm.RemoveAll()
+				}
+				return
+			}
+			var resp response.Response
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.Error == nil {
+				t.Fatalf("decode error response: %v", err)
+			}
+			if resp.Error.Code != tt.wantCode || resp.Error.Message != "Request body must not exceed 4MB" {
+				t.Errorf("error = %s %q, want %s with the 4MB limit", resp.Error.Code, resp.Error.Message, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestParseMultipartFormRejectsMalformedBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("--missing\r\nnot a part"))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=expected")
+
+	rec, parsed := serveMultipart(req)
+
+	if parsed || rec.Code != http.StatusBadRequest {
+		t.Fatalf("parsed = %v, status = %d, want a 400", parsed, rec.Code)
+	}
+}
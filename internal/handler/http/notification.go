@@ -27,6 +27,11 @@ type NotificationHandler interface {
 	GetPreferences(w http.ResponseWriter, r *http.Request)
 	UpdatePreference(w http.ResponseWriter, r *http.Request)
 
+	// Company templates
+	ListTemplates(w http.ResponseWriter, r *http.Request)
+	UpsertTemplate(w http.ResponseWriter, r *http.Request)
+	DeleteTemplate(w http.ResponseWriter, r *http.Request)
+
 	// SSE
 	GetSSEToken(w http.ResponseWriter, r *http.Request)
 	Stream(w http.ResponseWriter, r *http.Request)
@@ -223,6 +228,64 @@ func (h *notificationHandlerImpl) UpdatePreference(w http.ResponseWriter, r *htt
 	response.SuccessWithMessage(w, "Preference updated", nil)
 }
 
+// ListTemplates lists the company's notification templates for every type
+func (h *notificationHandlerImpl) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	companyID, ok := getCompanyIDFromContext(r)
+	if !ok {
+		response.Forbidden(w, "no company associated with this user")
+		return
+	}
+
+	templates, err := h.notifService.ListTemplates(r.Context(), companyID)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, templates)
+}
+
+// UpsertTemplate saves the company's template for a notification type
+func (h *notificationHandlerImpl) UpsertTemplate(w http.ResponseWriter, r *http.Request) {
+	companyID, ok := getCompanyIDFromContext(r)
+	if !ok {
+		response.Forbidden(w, "no company associated with this user")
+		return
+	}
+
+	var req notification.UpsertTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body", nil)
+		return
+	}
+	req.Type = notification.NotificationType(chi.URLParam(r, "type"))
+
+	tmpl, err := h.notifService.UpsertTemplate(r.Context(), companyID, req)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Template saved", tmpl)
+}
+
+// DeleteTemplate removes the company's template for a notification type
+func (h *notificationHandlerImpl) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	companyID, ok := getCompanyIDFromContext(r)
+	if !ok {
+		response.Forbidden(w, "no company associated with this user")
+		return
+	}
+
+	notifType := notification.NotificationType(chi.URLParam(r, "type"))
+	if err := h.notifService.DeleteTemplate(r.Context(), companyID, notifType); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Template deleted", nil)
+}
+
 // GetSSEToken generates a short-lived token for SSE connections
 func (h *notificationHandlerImpl) GetSSEToken(w http.ResponseWriter, r *http.Request) {
 	userID := getUserIDFromContext(r)
@@ -0,0 +1,220 @@
+package http
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/payroll"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/openapi"
+	"github.com/go-chi/chi/v5"
+)
+
+// apiRoutes documents request and response bodies for the OpenAPI document, keyed by
+// "METHOD /path" below /api/v1. Routes without an entry are still listed from the router.
+var apiRoutes = map[string]openapi.Route{
+	// Leave
+	"GET /leave/types":                          {Summary: "List leave types", Response: []leave.LeaveTypeResponse{}},
+	"POST /leave/types":                         {Summary: "Create a leave type", Request: leave.CreateLeaveTypeRequest{}, Response: leave.LeaveType{}, Status: http.StatusCreated},
+	"PUT /leave/types/{id}":                     {Summary: "Update a leave type", Request: leave.UpdateLeaveTypeRequest{}},
+	"DELETE /leave/types/{id}":                  {Summary: "Delete a leave type"},
+	"GET /leave/types/stats":                    {Summary: "Get request statistics per leave type for a leave year", Query: leave.LeaveTypeStatsFilter{}, Response: leave.LeaveTypeStatsResponse{}},
+	"GET /leave/types/{id}/quota-preview":       {Summary: "Preview the quota a leave type's rules give an employee", Query: leave.QuotaPreviewFilter{}, Response: leave.QuotaPreviewResponse{}},
+	"GET /leave/quota":                          {Summary: "List leave quotas of the company", Response: []leave.LeaveQuotaResponse{}},
+	"GET /leave/quota/my":                       {Summary: "List my leave quotas for this year", Response: []leave.LeaveQuotaResponse{}},
+	"GET /leave/quota/{id}":                     {Summary: "Get a leave quota", Response: leave.LeaveQuota{}},
+	"POST /leave/quota/adjust":                  {Summary: "Adjust a leave quota", Request: leave.AdjustQuotaRequest{}},
+	"GET /leave/requests":                       {Summary: "List leave requests", Query: leave.LeaveRequestFilter{}, Response: leave.ListLeaveRequestResponse{}},
+	"GET /leave/requests/export":                {Summary: "Export leave requests as CSV", Query: leave.LeaveRequestFilter{}, Produces: "text/csv"},
+	"GET /leave/requests/my":                    {Summary: "List my leave requests", Query: leave.MyLeaveRequestFilter{}, Response: leave.ListLeaveRequestResponse{}},
+	"GET /leave/requests/employee/{employeeID}": {Summary: "List one employee's leave requests", Query: leave.MyLeaveRequestFilter{}, Response: leave.ListLeaveRequestResponse{}},
+	"GET /leave/requests/my.ics": {Summary: "Leave calendar feed", Query: struct {
+		Token string `json:"token"`
+	}{}, Produces: "text/calendar"},
+	"POST /leave/requests/my/calendar-token":                {Summary: "Regenerate my calendar feed token", Response: leave.CalendarFeedTokenResponse{}},
+	"GET /leave/requests/pending/count":                     {Summary: "Count leave requests waiting for my approval", Response: leave.PendingApprovalCountResponse{}},
+	"GET /leave/requests/{id}":                              {Summary: "Get a leave request", Response: leave.LeaveRequestResponse{}},
+	"GET /leave/requests/{id}/attachment":                   {Summary: "Download a leave request's attachment (supports Range)", Produces: "application/octet-stream"},
+	"POST /leave/requests":                                  {Summary: "Submit a leave request", Form: map[string]*openapi.Schema{"data": openapi.String("JSON-encoded CreateLeaveRequestRequest"), "attachment": openapi.Binary()}, Response: leave.LeaveRequestResponse{}, Status: http.StatusCreated},
+	"POST /leave/requests/recurring":                        {Summary: "Submit a recurring leave request", Request: leave.CreateRecurringLeaveRequest{}, Response: leave.RecurringLeaveResponse{}, Status: http.StatusCreated},
+	"POST /leave/requests/{id}/approve":                     {Summary: "Approve a leave request", Request: leave.ApproveRequestRequest{}, Response: leave.ApproveLeaveResponse{}},
+	"POST /leave/requests/{id}/reject":                      {Summary: "Reject a leave request", Request: leave.RejectRequestRequest{}},
+	"POST /leave/requests/recurring/{recurrenceID}/approve": {Summary: "Approve every occurrence of a recurring leave", Response: leave.ApproveRecurringLeaveResponse{}},
+	"POST /leave/requests/recurring/{recurrenceID}/reject":  {Summary: "Reject every occurrence of a recurring leave", Request: leave.RejectRecurringLeaveRequest{}},
+	"PUT /leave/requests/{id}":                              {Summary: "Edit one of the caller's leave requests before it is processed", Request: leave.UpdateMyLeaveRequestRequest{}, Response: leave.LeaveRequestResponse{}},
+	"POST /leave/requests/{id}/reopen":                      {Summary: "Reopen one of the caller's rejected leave requests", Response: leave.LeaveRequestResponse{}},
+	"POST /leave/requests/{id}/recompute":                   {Summary: "Recompute the days of a leave request and adjust its quota", Response: leave.RecomputeLeaveRequestResponse{}},
+
+	// Schedule
+	"GET /schedule": {Summary: "List work schedules", Query: struct {
+		Name      *string `json:"name"`
+		Type      *string `json:"type"`
+		All       *bool   `json:"all"`
+		Page      *int    `json:"page"`
+		Limit     *int    `json:"limit"`
+		SortBy    *string `json:"sort_by"`
+		SortOrder *string `json:"sort_order"`
+	}{}, Response: schedule.ListWorkScheduleResponse{}},
+	"POST /schedule":              {Summary: "Create a work schedule", Request: schedule.CreateWorkScheduleRequest{}, Response: schedule.WorkScheduleResponse{}, Status: http.StatusCreated},
+	"GET /schedule/{id}":          {Summary: "Get a work schedule", Response: schedule.WorkScheduleResponse{}},
+	"GET /schedule/{id}/week":     {Summary: "Get a work schedule's times grouped by weekday", Response: schedule.WeeklyScheduleResponse{}},
+	"PUT /schedule/{id}":          {Summary: "Update a work schedule", Request: schedule.UpdateWorkScheduleRequest{}},
+	"DELETE /schedule/{id}":       {Summary: "Delete a work schedule"},
+	"POST /schedule/{id}/clone":   {Summary: "Clone a work schedule", Request: schedule.CloneWorkScheduleRequest{}, Response: schedule.WorkScheduleResponse{}, Status: http.StatusCreated},
+	"GET /schedule/employee/{id}": {Summary: "Get an employee's schedule timeline", Query: schedule.EmployeeScheduleTimelineFilter{}, Response: schedule.EmployeeScheduleTimelineResponse{}},
+	"GET /schedule/employee/{id}/export": {Summary: "Export an employee's schedule timeline", Query: struct {
+		Format *string `json:"format"`
+	}{}, Produces: "text/csv"},
+	"POST /schedule/{scheduleID}/employee/{employeeID}": {Summary: "Assign a schedule to an employee", Request: schedule.AssignScheduleRequest{}, Response: schedule.AssignScheduleResponse{}, Status: http.StatusCreated},
+	"PUT /schedule/{assignID}/employee/{employeeID}":    {Summary: "Update an employee schedule assignment", Request: schedule.UpdateEmployeeScheduleAssignmentRequest{}},
+	"DELETE /schedule/{assignID}/employee/{employeeID}": {Summary: "Delete an employee schedule assignment"},
+	"POST /schedule/times":                              {Summary: "Add a day to a work schedule", Request: schedule.CreateWorkScheduleTimeRequest{}, Response: schedule.WorkScheduleTimeResponse{}, Status: http.StatusCreated},
+	"GET /schedule/times/{id}":                          {Summary: "Get a work schedule time", Response: schedule.WorkScheduleTimeResponse{}},
+	"PUT /schedule/times/{id}":                          {Summary: "Update a work schedule time", Request: schedule.UpdateWorkScheduleTimeRequest{}},
+	"DELETE /schedule/{id}/times":                       {Summary: "Delete every time of a work schedule", Response: schedule.ClearScheduleResponse{}},
+	"DELETE /schedule/{id}/locations":                   {Summary: "Delete every location of a work schedule", Response: schedule.ClearScheduleResponse{}},
+	"DELETE /schedule/times/{id}":                       {Summary: "Delete a work schedule time"},
+	"POST /schedule/locations":                          {Summary: "Add a location to a work schedule", Request: schedule.CreateWorkScheduleLocationRequest{}, Response: schedule.WorkScheduleLocationResponse{}, Status: http.StatusCreated},
+	"GET /schedule/locations/{id}":                      {Summary: "Get a work schedule location", Response: schedule.WorkScheduleLocationResponse{}},
+	"PUT /schedule/locations/{id}":                      {Summary: "Update a work schedule location", Request: schedule.UpdateWorkScheduleLocationRequest{}},
+	"DELETE /schedule/locations/{id}":                   {Summary: "Delete a work schedule location"},
+	"GET /schedule/swaps": {Summary: "List shift swaps", Query: struct {
+		Status     *string `json:"status"`
+		EmployeeID *string `json:"employee_id"`
+	}{}, Response: []schedule.ShiftSwapResponse{}},
+	"POST /schedule/swaps":              {Summary: "Request a shift swap", Request: schedule.CreateShiftSwapRequest{}, Response: schedule.ShiftSwapResponse{}, Status: http.StatusCreated},
+	"POST /schedule/swaps/{id}/approve": {Summary: "Approve a shift swap", Response: schedule.ShiftSwapResponse{}},
+	"POST /schedule/swaps/{id}/reject":  {Summary: "Reject a shift swap", Request: schedule.RejectShiftSwapRequest{}, Response: schedule.ShiftSwapResponse{}},
+	"GET /employee-schedules": {Summary: "List employee schedule assignments", Query: struct {
+		EmployeeID *string `json:"employee_id"`
+	}{}, Response: []schedule.EmployeeScheduleAssignmentResponse{}},
+	"POST /employee-schedules": {Summary: "Create an employee schedule assignment", Request: schedule.CreateEmployeeScheduleAssignmentRequest{}, Response: schedule.EmployeeScheduleAssignmentResponse{}, Status: http.StatusCreated},
+	"GET /employee-schedules/active": {Summary: "Get the schedule active for an employee on a date", Query: struct {
+		EmployeeID *string `json:"employee_id"`
+		Date       *string `json:"date"`
+	}{}, Response: schedule.WorkScheduleResponse{}},
+	"GET /employee-schedules/my":      {Summary: "Get my effective schedule for a date range, grouped into periods", Query: schedule.MyScheduleRangeFilter{}, Response: schedule.MyScheduleRangeResponse{}},
+	"GET /employee-schedules/{id}":    {Summary: "Get an employee schedule assignment", Response: schedule.EmployeeScheduleAssignmentResponse{}},
+	"PUT /employee-schedules/{id}":    {Summary: "Update an employee schedule assignment", Request: schedule.UpdateEmployeeScheduleAssignmentRequest{}},
+	"DELETE /employee-schedules/{id}": {Summary: "Delete an employee schedule assignment"},
+
+	// Attendance settings
+	"GET /attendance/settings": {Summary: "Get attendance settings", Response: attendance.AttendanceSettingsResponse{}},
+	"PUT /attendance/settings": {Summary: "Update attendance settings", Request: attendance.UpdateAttendanceSettingsRequest{}, Response: attendance.AttendanceSettingsResponse{}},
+
+	// Payroll
+	"GET /payroll/settings": {Summary: "Get payroll settings", Response: payroll.PayrollSettingsResponse{}},
+	"PUT /payroll/settings": {Summary: "Update payroll settings", Request: payroll.UpdatePayrollSettingsRequest{}, Response: payroll.PayrollSettingsResponse{}},
+	"GET /payroll/components": {Summary: "List payroll components", Query: struct {
+		ActiveOnly *bool `json:"active_only"`
+	}{}, Response: []payroll.PayrollComponentResponse{}},
+	"POST /payroll/components":                        {Summary: "Create a payroll component", Request: payroll.CreatePayrollComponentRequest{}, Response: payroll.PayrollComponentResponse{}, Status: http.StatusCreated},
+	"GET /payroll/components/{id}":                    {Summary: "Get a payroll component", Response: payroll.PayrollComponentResponse{}},
+	"PUT /payroll/components/{id}":                    {Summary: "Update a payroll component", Request: payroll.UpdatePayrollComponentRequest{}},
+	"DELETE /payroll/components/{id}":                 {Summary: "Delete a payroll component"},
+	"GET /payroll/employees/{employeeId}/components":  {Summary: "List an employee's payroll components", Response: []payroll.EmployeeComponentResponse{}},
+	"POST /payroll/employees/{employeeId}/components": {Summary: "Assign a payroll component to an employee", Request: payroll.AssignComponentRequest{}, Response: payroll.EmployeeComponentResponse{}, Status: http.StatusCreated},
+	"PUT /payroll/employee-components/{id}":           {Summary: "Update an employee's payroll component", Request: payroll.UpdateEmployeeComponentRequest{}},
+	"DELETE /payroll/employee-components/{id}":        {Summary: "Remove a payroll component from an employee"},
+	"POST /payroll/generate":                          {Summary: "Generate draft payroll records for a period", Request: payroll.GeneratePayrollRequest{}, Response: payroll.GeneratePayrollResponse{}, Status: http.StatusCreated},
+	"GET /payroll/runs":                               {Summary: "List payroll generation runs", Query: payroll.PayrollRunFilter{}, Response: []payroll.PayrollRunResponse{}},
+	"GET /payroll/runs/{id}":                          {Summary: "Get a payroll generation run's status and progress", Response: payroll.PayrollRunResponse{}},
+	"GET /payroll/records":                            {Summary: "List payroll records", Query: payroll.PayrollFilter{}, Response: payroll.ListPayrollRecordResponse{}},
+	"GET /payroll/records/{id}":                       {Summary: "Get a payroll record", Response: payroll.PayrollRecordResponse{}},
+	"PUT /payroll/records/{id}":                       {Summary: "Update a draft payroll record", Request: payroll.UpdatePayrollRecordRequest{}, Response: payroll.PayrollRecordResponse{}},
+	"POST /payroll/records/{id}/recalculate":          {Summary: "Recompute an unpaid payroll record from current attendance", Response: payroll.RecalculatePayrollRecordResponse{}},
+	"DELETE /payroll/records/{id}":                    {Summary: "Delete a draft payroll record"},
+	"POST /payroll/finalize":                          {Summary: "Finalize payroll records", Request: payroll.FinalizePayrollRequest{}},
+	"GET /payroll/bank-file": {Summary: "Download the bank transfer file for a period's finalized records", Query: struct {
+		PeriodMonth int     `json:"period_month"`
+		PeriodYear  int     `json:"period_year"`
+		Format      *string `json:"format"`
+	}{}, Produces: "text/csv"},
+	"GET /payroll/bank-file/summary": {Summary: "Summarize a period's bank transfer file and the employees it leaves out", Query: struct {
+		PeriodMonth int     `json:"period_month"`
+		PeriodYear  int     `json:"period_year"`
+		Format      *string `json:"format"`
+	}{}, Response: payroll.BankFileExport{}},
+	"GET /payroll/summary": {Summary: "Payroll summary for a period", Query: struct {
+		PeriodMonth int `json:"period_month"`
+		PeriodYear  int `json:"period_year"`
+	}{}, Response: payroll.PayrollSummaryResponse{}},
+
+	// Company
+	"POST /company/my/leave-types/seed":                {Summary: "Seed default leave types from a template", Request: company.SeedLeaveTypesRequest{}, Response: company.SeedLeaveTypesResponse{}},
+	"GET /company/my/calendar":                         {Summary: "Get the company's holidays and working weekdays for a year", Response: company.CalendarResponse{}},
+	"POST /company/my/holidays":                        {Summary: "Add a company holiday", Request: company.CreateHolidayRequest{}, Response: company.HolidayResponse{}, Status: http.StatusCreated},
+	"DELETE /company/my/holidays/{id}":                 {Summary: "Delete a company holiday"},
+	"GET /company/my/onboarding":                       {Summary: "Get the company setup checklist", Response: company.OnboardingStatusResponse{}},
+	"GET /company/my/notification-templates":           {Summary: "List notification templates", Response: []notification.TemplateResponse{}},
+	"PUT /company/my/notification-templates/{type}":    {Summary: "Customize a notification type", Request: notification.UpsertTemplateRequest{}, Response: notification.TemplateResponse{}},
+	"DELETE /company/my/notification-templates/{type}": {Summary: "Restore a notification type's default text"},
+
+	// Subscription
+	"GET /plans":                          {Summary: "List subscription plans", Response: []subscription.PlanResponse{}},
+	"GET /subscription/my":                {Summary: "Get my company's subscription", Response: subscription.SubscriptionResponse{}},
+	"GET /subscription/seats/usage":       {Summary: "Break the seats in use down by employees and invitations", Response: subscription.SeatUsageResponse{}},
+	"GET /subscription/invoices":          {Summary: "List invoices", Query: subscription.InvoiceFilter{}, Response: subscription.ListInvoiceResponse{}},
+	"GET /subscription/invoices/{id}":     {Summary: "Get an invoice", Response: subscription.InvoiceResponse{}},
+	"GET /subscription/invoices/{id}/pdf": {Summary: "Download an invoice as PDF", Produces: "application/pdf"},
+	"DELETE /subscription/invoices/{id}":  {Summary: "Cancel a pending invoice"},
+	"POST /subscription/checkout":         {Summary: "Start a paid subscription", Request: subscription.CheckoutRequest{}, Response: subscription.InvoiceResponse{}, Status: http.StatusCreated},
+	"POST /subscription/upgrade":          {Summary: "Upgrade the plan", Request: subscription.UpgradeRequest{}, Response: subscription.InvoiceResponse{}},
+	"POST /subscription/downgrade":        {Summary: "Downgrade the plan at the end of the period", Request: subscription.DowngradeRequest{}},
+	"POST /subscription/cancel":           {Summary: "Cancel the subscription", Request: subscription.CancelRequest{}},
+	"POST /subscription/seats":            {Summary: "Change the number of seats", Request: subscription.ChangeSeatRequest{}, Response: subscription.ChangeSeatResponse{}},
+	"GET /subscription/seats/preview": {Summary: "Preview the cost of a seat change", Query: struct {
+		SeatCount int `json:"seat_count"`
+	}{}, Response: subscription.SeatChangePreviewResponse{}},
+}
+
+// openAPIHandler serves an OpenAPI document generated from the router's registered routes.
+// The document is built on the first request, once every route is in place.
+func openAPIHandler(router chi.Routes) http.HandlerFunc {
+	var (
+		once sync.Once
+		body []byte
+		err  error
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			var doc openapi.Document
+			doc, err = buildOpenAPIDocument(router)
+			if err != nil {
+				return
+			}
+			body, err = json.MarshalIndent(doc, "", "  ")
+		})
+		if err != nil {
+			slog.Error("Failed to build OpenAPI document", "error", err)
+			response.InternalServerError(w, "Failed to build OpenAPI document")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}
+}
+
+// buildOpenAPIDocument describes the routes under /api/v1 with the bodies documented in apiRoutes
+func buildOpenAPIDocument(router chi.Routes) (openapi.Document, error) {
+	return openapi.Build(router, openapi.Config{
+		Info: openapi.Info{
+			Title:       "HRIS cmlabs API",
+			Version:     "1.0.0",
+			Description: "Generated from the registered routes and their request/response DTOs.",
+		},
+		BasePath: "/api/v1",
+		Routes:   apiRoutes,
+		Envelope: response.Response{},
+	})
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/middleware"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/jwt"
+	"github.com/go-chi/chi/v5"
+)
+
+// newDocumentedRouter mounts every route the server registers. The handlers have no services,
+// which is enough to walk the routes but not to serve them.
+func newDocumentedRouter(t *testing.T) *chi.Mux {
+	t.Helper()
+	jwtService := jwt.NewJWTService("test-secret", "15m", "24h")
+
+	return NewRouter(
+		jwtService,
+		NewAuthHandler(jwtService, nil, "http://localhost:3000"),
+		NewCompanyHandler(jwtService, nil, nil),
+		NewLeaveHandler(nil, nil, jwtService),
+		NewMasterHandler(nil),
+		NewScheduleHandler(nil),
+		NewAttendanceHandler(nil),
+		NewEmployeeHandler(nil, nil),
+		NewInvitationHandler(nil),
+		NewPayrollHandler(nil),
+		NewDashboardHandler(nil),
+		NewEmployeeDashboardHandler(nil),
+		NewNotificationHandler(nil, jwtService),
+		NewReportHandler(nil),
+		NewSubscriptionHandler(nil, nil),
+		NewRoleHandler(nil),
+		middleware.NewSubscriptionMiddleware(nil),
+		middleware.NewBranchScopeMiddleware(nil),
+		middleware.NewPermissionMiddleware(nil),
+		t.TempDir(),
+		1<<20,
+	)
+}
+
+// Every entry of apiRoutes must name a mounted route, otherwise the document fails to build
+func TestOpenAPIDocumentMatchesRouter(t *testing.T) {
+	router := newDocumentedRouter(t)
+
+	doc, err := buildOpenAPIDocument(router)
+	if err != nil {
+		t.Fatalf("buildOpenAPIDocument() error = %v", err)
+	}
+	op := doc.Paths["/leave/requests/recurring"]["post"]
+	if op == nil {
+		t.Fatal("POST /leave/requests/recurring is missing from the document")
+	}
+	if op.Summary != apiRoutes["POST /leave/requests/recurring"].Summary {
+		t.Errorf("POST /leave/requests/recurring summary = %q, want the documented one", op.Summary)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /openapi.json status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if !json.Valid(rec.Body.Bytes()) {
+		t.Errorf("GET /openapi.json did not return JSON")
+	}
+}
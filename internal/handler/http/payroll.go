@@ -5,9 +5,11 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/auth"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/payroll"
 	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
 )
 
 type PayrollHandler interface {
@@ -33,9 +35,18 @@ type PayrollHandler interface {
 	GetPayrollRecord(w http.ResponseWriter, r *http.Request)
 	ListPayrollRecords(w http.ResponseWriter, r *http.Request)
 	UpdatePayrollRecord(w http.ResponseWriter, r *http.Request)
+	RecalculatePayrollRecord(w http.ResponseWriter, r *http.Request)
 	FinalizePayroll(w http.ResponseWriter, r *http.Request)
 	DeletePayrollRecord(w http.ResponseWriter, r *http.Request)
 
+	// Runs
+	ListPayrollRuns(w http.ResponseWriter, r *http.Request)
+	GetPayrollRun(w http.ResponseWriter, r *http.Request)
+
+	// Bank file
+	ExportBankFile(w http.ResponseWriter, r *http.Request)
+	GetBankFileSummary(w http.ResponseWriter, r *http.Request)
+
 	// Summary
 	GetPayrollSummary(w http.ResponseWriter, r *http.Request)
 }
@@ -344,6 +355,22 @@ func (h *payrollHandlerImpl) UpdatePayrollRecord(w http.ResponseWriter, r *http.
 	response.Success(w, result)
 }
 
+func (h *payrollHandlerImpl) RecalculatePayrollRecord(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		response.BadRequest(w, "Record ID is required", nil)
+		return
+	}
+
+	result, err := h.payrollService.RecalculateRecord(r.Context(), id)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
 func (h *payrollHandlerImpl) FinalizePayroll(w http.ResponseWriter, r *http.Request) {
 	var req payroll.FinalizePayrollRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -377,27 +404,58 @@ func (h *payrollHandlerImpl) DeletePayrollRecord(w http.ResponseWriter, r *http.
 // ========== SUMMARY ==========
 
 func (h *payrollHandlerImpl) GetPayrollSummary(w http.ResponseWriter, r *http.Request) {
-	monthStr := r.URL.Query().Get("period_month")
-	yearStr := r.URL.Query().Get("period_year")
+	month, year, ok := parsePayrollPeriod(w, r)
+	if !ok {
+		return
+	}
 
-	if monthStr == "" || yearStr == "" {
-		response.BadRequest(w, "period_month and period_year are required", nil)
+	result, err := h.payrollService.GetPayrollSummary(r.Context(), month, year)
+	if err != nil {
+		response.HandleError(w, err)
 		return
 	}
 
-	month, err := strconv.Atoi(monthStr)
-	if err != nil || month < 1 || month > 12 {
-		response.BadRequest(w, "Invalid period_month", nil)
+	response.Success(w, result)
+}
+
+// ========== RUNS ==========
+
+func (h *payrollHandlerImpl) ListPayrollRuns(w http.ResponseWriter, r *http.Request) {
+	var filter payroll.PayrollRunFilter
+	if monthStr := r.URL.Query().Get("period_month"); monthStr != "" {
+		month, err := strconv.Atoi(monthStr)
+		if err != nil || month < 1 || month > 12 {
+			response.BadRequest(w, "Invalid period_month", nil)
+			return
+		}
+		filter.PeriodMonth = &month
+	}
+	if yearStr := r.URL.Query().Get("period_year"); yearStr != "" {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil {
+			response.BadRequest(w, "Invalid period_year", nil)
+			return
+		}
+		filter.PeriodYear = &year
+	}
+
+	result, err := h.payrollService.ListPayrollRuns(r.Context(), filter)
+	if err != nil {
+		response.HandleError(w, err)
 		return
 	}
 
-	year, err := strconv.Atoi(yearStr)
-	if err != nil || year < 2020 {
-		response.BadRequest(w, "Invalid period_year", nil)
+	response.Success(w, result)
+}
+
+func (h *payrollHandlerImpl) GetPayrollRun(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		response.BadRequest(w, "Run ID is required", nil)
 		return
 	}
 
-	result, err := h.payrollService.GetPayrollSummary(r.Context(), month, year)
+	result, err := h.payrollService.GetPayrollRun(r.Context(), id)
 	if err != nil {
 		response.HandleError(w, err)
 		return
@@ -405,3 +463,79 @@ func (h *payrollHandlerImpl) GetPayrollSummary(w http.ResponseWriter, r *http.Re
 
 	response.Success(w, result)
 }
+
+// ========== BANK FILE ==========
+
+// ExportBankFile downloads the bank transfer file for a period's finalized records.
+// The number of records left out is sent in X-Skipped-Count; GetBankFileSummary lists them.
+func (h *payrollHandlerImpl) ExportBankFile(w http.ResponseWriter, r *http.Request) {
+	result, ok := h.exportBankFile(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("X-Skipped-Count", strconv.Itoa(len(result.Skipped)))
+	response.File(w, result.ContentType, result.Filename, result.Content)
+}
+
+// GetBankFileSummary reports what the bank file for a period would contain and which employees it leaves out
+func (h *payrollHandlerImpl) GetBankFileSummary(w http.ResponseWriter, r *http.Request) {
+	result, ok := h.exportBankFile(w, r)
+	if !ok {
+		return
+	}
+
+	response.Success(w, result)
+}
+
+func (h *payrollHandlerImpl) exportBankFile(w http.ResponseWriter, r *http.Request) (payroll.BankFileExport, bool) {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		response.HandleError(w, auth.ErrInvalidToken)
+		return payroll.BankFileExport{}, false
+	}
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		response.HandleError(w, auth.ErrInvalidToken)
+		return payroll.BankFileExport{}, false
+	}
+
+	month, year, ok := parsePayrollPeriod(w, r)
+	if !ok {
+		return payroll.BankFileExport{}, false
+	}
+
+	result, err := h.payrollService.ExportBankFile(r.Context(), companyID, month, year, r.URL.Query().Get("format"))
+	if err != nil {
+		response.HandleError(w, err)
+		return payroll.BankFileExport{}, false
+	}
+
+	return result, true
+}
+
+// parsePayrollPeriod reads the required period_month and period_year query parameters,
+// writing a bad request response when they are missing or invalid
+func parsePayrollPeriod(w http.ResponseWriter, r *http.Request) (month, year int, ok bool) {
+	monthStr := r.URL.Query().Get("period_month")
+	yearStr := r.URL.Query().Get("period_year")
+
+	if monthStr == "" || yearStr == "" {
+		response.BadRequest(w, "period_month and period_year are required", nil)
+		return 0, 0, false
+	}
+
+	month, err := strconv.Atoi(monthStr)
+	if err != nil || month < 1 || month > 12 {
+		response.BadRequest(w, "Invalid period_month", nil)
+		return 0, 0, false
+	}
+
+	year, err = strconv.Atoi(yearStr)
+	if err != nil || year < 2020 {
+		response.BadRequest(w, "Invalid period_year", nil)
+		return 0, 0, false
+	}
+
+	return month, year, true
+}
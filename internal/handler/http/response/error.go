@@ -2,8 +2,10 @@ package response
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/auth"
@@ -14,12 +16,26 @@ import (
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/master/branch"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/master/grade"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/master/position"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/payroll"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/report"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/role"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
 )
 
+// apiError is the HTTP shape of a domain error. Code is stable across releases so
+// clients can branch on it instead of parsing Message.
+type apiError struct {
+	Status  int
+	Code    string
+	Message string
+	Details map[string]string
+}
+
 // HandleError maps domain errors to HTTP responses
 func HandleError(w http.ResponseWriter, err error) {
 	// Check if it's a validation error
@@ -29,354 +45,550 @@ func HandleError(w http.ResponseWriter, err error) {
 		return
 	}
 
+	e := mapError(err)
+	if e.Status == http.StatusInternalServerError {
+		// Log the error for debugging purposes
+		log.Printf("Unhandled error: %v", err)
+	}
+	writeError(w, e.Status, &ErrorDetail{Code: e.Code, Message: e.Message, Details: e.Details})
+}
+
+// mapError gives every known domain error its status and code. Codes are the domain
+// package followed by the error name, e.g. leave.ErrInsufficientQuota is
+// LEAVE_INSUFFICIENT_QUOTA.
+func mapError(err error) apiError {
+	var quotaErr *leave.InsufficientQuotaError
+	var recurringErr *leave.RecurringLeaveDateError
+	var attendanceDateErr *leave.LeaveAttendanceDateError
+	var maxBytesErr *http.MaxBytesError
+
 	switch {
+	case errors.As(err, &maxBytesErr):
+		return apiError{http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", fmt.Sprintf("Request body must not exceed %dMB", maxBytesErr.Limit>>20), nil}
+	case database.IsStatementTimeout(err):
+		return apiError{http.StatusServiceUnavailable, "QUERY_TIMEOUT", "The request took too long to process. Try a smaller range or try again later", nil}
+
 	// Security: generic message for registration conflicts
 	// case errors.Is(err, user.ErrUserEmailExists), errors.Is(err, company.ErrCompanyUsernameExists):
 	// 	Conflict(w, "Registration failed: one or more fields already exist")
 
 	// Auth domain errors
 	case errors.Is(err, auth.ErrEmailAlreadyExists):
-		Conflict(w, "Account with this email already exists")
+		return apiError{http.StatusConflict, "AUTH_EMAIL_ALREADY_EXISTS", "Account with this email already exists", nil}
 	case errors.Is(err, auth.ErrInvalidCredentials):
-		Unauthorized(w, err.Error())
+		return apiError{http.StatusUnauthorized, "AUTH_INVALID_CREDENTIALS", err.Error(), nil}
 	case errors.Is(err, auth.ErrInvalidEmployeeCodeCredentials):
-		Unauthorized(w, err.Error())
+		return apiError{http.StatusUnauthorized, "AUTH_INVALID_EMPLOYEE_CODE_CREDENTIALS", err.Error(), nil}
 	case errors.Is(err, auth.ErrTokenExpired):
-		Unauthorized(w, "Token expired")
+		return apiError{http.StatusUnauthorized, "AUTH_TOKEN_EXPIRED", "Token expired", nil}
 	case errors.Is(err, auth.ErrRefreshTokenRevoked):
-		Unauthorized(w, "Refresh token revoked")
+		return apiError{http.StatusUnauthorized, "AUTH_REFRESH_TOKEN_REVOKED", "Refresh token revoked", nil}
 	case errors.Is(err, auth.ErrEmailNotVerified):
-		Forbidden(w, "Email not verified")
+		return apiError{http.StatusForbidden, "AUTH_EMAIL_NOT_VERIFIED", "Email not verified", nil}
 	case errors.Is(err, auth.ErrUserNotFound):
-		NotFound(w, "User not found")
+		return apiError{http.StatusNotFound, "AUTH_USER_NOT_FOUND", "User not found", nil}
 	case errors.Is(err, auth.ErrCompanyNotFound):
-		NotFound(w, "Company not found")
+		return apiError{http.StatusNotFound, "AUTH_COMPANY_NOT_FOUND", "Company not found", nil}
 	case errors.Is(err, auth.ErrAccountLocked):
-		Forbidden(w, "Account is locked")
+		return apiError{http.StatusForbidden, "AUTH_ACCOUNT_LOCKED", "Account is locked", nil}
 	case errors.Is(err, auth.ErrInvalidToken):
-		Unauthorized(w, "Invalid or expired token")
+		return apiError{http.StatusUnauthorized, "AUTH_INVALID_TOKEN", "Invalid or expired token", nil}
 	case errors.Is(err, auth.ErrStateCookieNotFound):
-		Unauthorized(w, "State cookie not found")
+		return apiError{http.StatusUnauthorized, "AUTH_STATE_COOKIE_NOT_FOUND", "State cookie not found", nil}
 	case errors.Is(err, auth.ErrStateMismatch):
-		Unauthorized(w, "State mismatch: value from cookie does not match value from URL parameter")
+		return apiError{http.StatusUnauthorized, "AUTH_STATE_MISMATCH", "State mismatch: value from cookie does not match value from URL parameter", nil}
 	case errors.Is(err, auth.ErrStateParamEmpty):
-		Unauthorized(w, "State param is empty")
+		return apiError{http.StatusUnauthorized, "AUTH_STATE_PARAM_EMPTY", "State param is empty", nil}
 	case errors.Is(err, auth.ErrStateCookieEmpty):
-		Unauthorized(w, "State cookie is empty")
+		return apiError{http.StatusUnauthorized, "AUTH_STATE_COOKIE_EMPTY", "State cookie is empty", nil}
 	case errors.Is(err, auth.ErrCodeValueEmpty):
-		BadRequest(w, "Code value is empty", nil)
-	case errors.Is(err, auth.ErrGoogleAccessDeniedByUser):
-		Unauthorized(w, "Google access denied by user")
+		return apiError{http.StatusBadRequest, "AUTH_CODE_VALUE_EMPTY", "Code value is empty", nil}
+	case errors.Is(err, auth.ErrOAuthAccessDeniedByUser):
+		return apiError{http.StatusUnauthorized, "AUTH_OAUTH_ACCESS_DENIED_BY_USER", "OAuth access denied by user", nil}
+	case errors.Is(err, auth.ErrOAuthProviderNotSupported):
+		return apiError{http.StatusNotFound, "AUTH_OAUTH_PROVIDER_NOT_SUPPORTED", "OAuth provider is not supported", nil}
+	case errors.Is(err, auth.ErrOAuthEmailNotVerified):
+		return apiError{http.StatusUnauthorized, "AUTH_OAUTH_EMAIL_NOT_VERIFIED", "OAuth provider did not return a verified email", nil}
+	case errors.Is(err, auth.ErrOAuthLinkRequired):
+		return apiError{http.StatusConflict, "AUTH_OAUTH_LINK_REQUIRED", "Confirm the OAuth link with your account password", nil}
+	case errors.Is(err, auth.ErrOAuthAccountConflict):
+		return apiError{http.StatusConflict, "AUTH_OAUTH_ACCOUNT_CONFLICT", "Account is already linked to a different OAuth identity", nil}
+	case errors.Is(err, auth.ErrOAuthLinkEmailNotVerified):
+		return apiError{http.StatusForbidden, "AUTH_OAUTH_LINK_EMAIL_NOT_VERIFIED", "Verify your account email before linking an OAuth identity", nil}
+	case errors.Is(err, auth.ErrOAuthLinkTokenNotFound):
+		return apiError{http.StatusNotFound, "AUTH_OAUTH_LINK_TOKEN_NOT_FOUND", "OAuth link token not found", nil}
+	case errors.Is(err, auth.ErrOAuthLinkTokenExpired):
+		return apiError{http.StatusBadRequest, "AUTH_OAUTH_LINK_TOKEN_EXPIRED", "OAuth link token has expired", nil}
+	case errors.Is(err, auth.ErrOAuthLinkTokenUsed):
+		return apiError{http.StatusBadRequest, "AUTH_OAUTH_LINK_TOKEN_USED", "OAuth link token has already been used", nil}
 	case errors.Is(err, auth.ErrRefreshTokenCookieNotFound):
-		Unauthorized(w, "Refresh token cookie not found")
+		return apiError{http.StatusUnauthorized, "AUTH_REFRESH_TOKEN_COOKIE_NOT_FOUND", "Refresh token cookie not found", nil}
 	case errors.Is(err, auth.ErrRefreshTokenCookieEmpty):
-		Unauthorized(w, "Refresh token cookie is empty")
+		return apiError{http.StatusUnauthorized, "AUTH_REFRESH_TOKEN_COOKIE_EMPTY", "Refresh token cookie is empty", nil}
 
 	// Employee domain errors
 	case errors.Is(err, employee.ErrEmployeeNotFound):
-		NotFound(w, "Employee not found")
+		return apiError{http.StatusNotFound, "EMPLOYEE_NOT_FOUND", "Employee not found", nil}
 	case errors.Is(err, employee.ErrEmployeeCodeExists):
-		Conflict(w, "Employee code already exists")
+		return apiError{http.StatusConflict, "EMPLOYEE_CODE_EXISTS", "Employee code already exists", nil}
 	case errors.Is(err, employee.ErrNIKExists):
-		Conflict(w, "NIK already registered")
+		return apiError{http.StatusConflict, "EMPLOYEE_NIK_EXISTS", "NIK already registered", nil}
 	case errors.Is(err, employee.ErrEmailExists):
-		Conflict(w, "Email already registered in this company")
+		return apiError{http.StatusConflict, "EMPLOYEE_EMAIL_EXISTS", "Email already registered in this company", nil}
 	case errors.Is(err, employee.ErrInvalidEmployeeCode):
-		BadRequest(w, "Invalid employee code format", nil)
+		return apiError{http.StatusBadRequest, "EMPLOYEE_INVALID_EMPLOYEE_CODE", "Invalid employee code format", nil}
 	case errors.Is(err, employee.ErrInvalidNIK):
-		BadRequest(w, "NIK must be exactly 16 digits", nil)
+		return apiError{http.StatusBadRequest, "EMPLOYEE_INVALID_NIK", "NIK must be exactly 16 digits", nil}
 	case errors.Is(err, employee.ErrInvalidPhoneNumber):
-		BadRequest(w, "Phone number must be 10-13 digits", nil)
+		return apiError{http.StatusBadRequest, "EMPLOYEE_INVALID_PHONE_NUMBER", "Phone number must be 10-13 digits", nil}
 	case errors.Is(err, employee.ErrInvalidGender):
-		BadRequest(w, "Gender must be Male or Female", nil)
+		return apiError{http.StatusBadRequest, "EMPLOYEE_INVALID_GENDER", "Gender must be Male or Female", nil}
 	case errors.Is(err, employee.ErrMinimumAge):
-		BadRequest(w, "Employee must be at least 17 years old", nil)
+		return apiError{http.StatusBadRequest, "EMPLOYEE_MINIMUM_AGE", "Employee must be at least 17 years old", nil}
 	case errors.Is(err, employee.ErrFutureDateNotAllowed):
-		BadRequest(w, "Date cannot be in the future", nil)
+		return apiError{http.StatusBadRequest, "EMPLOYEE_FUTURE_DATE_NOT_ALLOWED", "Date cannot be in the future", nil}
+	case errors.Is(err, employee.ErrSalaryOutsideGradeBand):
+		return apiError{http.StatusBadRequest, "EMPLOYEE_SALARY_OUTSIDE_GRADE_BAND", "Base salary is outside the grade's salary band", map[string]string{
+			"base_salary": "must be within the grade's min_salary and max_salary, or set override_salary_band as owner",
+		}}
+	case errors.Is(err, employee.ErrEmployeeStillActive):
+		return apiError{http.StatusConflict, "EMPLOYEE_STILL_ACTIVE", "Only resigned or terminated employees can be anonymized", nil}
+	case errors.Is(err, employee.ErrEmployeeAlreadyAnonymized):
+		return apiError{http.StatusConflict, "EMPLOYEE_ALREADY_ANONYMIZED", "Employee is already anonymized", nil}
+	case errors.Is(err, employee.ErrNotManager):
+		return apiError{http.StatusBadRequest, "EMPLOYEE_NOT_MANAGER", "Branch scope can only be set for employees with a manager account", nil}
 
 	// Leave domain errors
+	case errors.As(err, &attendanceDateErr):
+		return apiError{http.StatusInternalServerError, "LEAVE_ATTENDANCE_NOT_RECORDED", "Attendance could not be recorded for " + attendanceDateErr.Date + "; the approval was not saved", map[string]string{
+			"date": attendanceDateErr.Date,
+		}}
+	case errors.As(err, &recurringErr):
+		return apiError{http.StatusBadRequest, "LEAVE_RECURRING_DATE_UNAVAILABLE", "Recurring leave could not be submitted for " + recurringErr.Date, map[string]string{
+			"date":   recurringErr.Date,
+			"reason": recurringErr.Err.Error(),
+		}}
+	case errors.Is(err, leave.ErrNoRecurringLeaveDates):
+		return apiError{http.StatusBadRequest, "LEAVE_NO_RECURRING_LEAVE_DATES", "No working days match the recurrence pattern", nil}
+	case errors.Is(err, leave.ErrRecurrenceNotFound):
+		return apiError{http.StatusNotFound, "LEAVE_RECURRENCE_NOT_FOUND", "Recurring leave not found", nil}
+	case errors.Is(err, leave.ErrInvalidCalendarFeedToken):
+		return apiError{http.StatusUnauthorized, "LEAVE_INVALID_CALENDAR_FEED_TOKEN", "Calendar feed token is invalid or has been regenerated", nil}
 	case errors.Is(err, leave.ErrLeaveRequestNotFound):
-		NotFound(w, "Leave request not found")
+		return apiError{http.StatusNotFound, "LEAVE_REQUEST_NOT_FOUND", "Leave request not found", nil}
+	case errors.Is(err, leave.ErrAttachmentNotFound):
+		return apiError{http.StatusNotFound, "LEAVE_ATTACHMENT_NOT_FOUND", "Leave request has no attachment", nil}
+	case errors.Is(err, leave.ErrConcurrentModification):
+		return apiError{http.StatusConflict, "LEAVE_CONCURRENT_MODIFICATION", "Leave request was changed by someone else, reload it and try again", nil}
+	case errors.As(err, &quotaErr):
+		return apiError{http.StatusBadRequest, "LEAVE_INSUFFICIENT_QUOTA", "Insufficient leave quota", map[string]string{
+			"available_quota": strconv.FormatFloat(quotaErr.Available, 'f', -1, 64),
+			"requested_days":  strconv.FormatFloat(quotaErr.Requested, 'f', -1, 64),
+			"shortfall":       strconv.FormatFloat(quotaErr.Shortfall(), 'f', -1, 64),
+		}}
 	case errors.Is(err, leave.ErrInsufficientQuota):
-		BadRequest(w, "Insufficient leave quota", nil)
+		return apiError{http.StatusBadRequest, "LEAVE_INSUFFICIENT_QUOTA", "Insufficient leave quota", nil}
 	case errors.Is(err, leave.ErrLeaveRequestAlreadyProcessed):
-		Conflict(w, "Leave request already processed")
+		return apiError{http.StatusConflict, "LEAVE_REQUEST_ALREADY_PROCESSED", "Leave request already processed", nil}
 	case errors.Is(err, leave.ErrLeaveTypeNotFound):
-		NotFound(w, "Leave type not found")
+		return apiError{http.StatusNotFound, "LEAVE_TYPE_NOT_FOUND", "Leave type not found", nil}
 	case errors.Is(err, leave.ErrLeaveTypesNotFound):
-		NotFound(w, "Leave types not found")
+		return apiError{http.StatusNotFound, "LEAVE_TYPES_NOT_FOUND", "Leave types not found", nil}
 	case errors.Is(err, leave.ErrLeaveTypeCodeExists):
-		Conflict(w, "Leave type code already exists")
+		return apiError{http.StatusConflict, "LEAVE_TYPE_CODE_EXISTS", "Leave type code already exists", nil}
 	case errors.Is(err, leave.ErrLeaveTypeNameExists):
-		Conflict(w, "Leave type name already exists")
+		return apiError{http.StatusConflict, "LEAVE_TYPE_NAME_EXISTS", "Leave type name already exists", nil}
 	case errors.Is(err, leave.ErrLeaveTypeInactive):
-		BadRequest(w, "Leave type is not active", nil)
+		return apiError{http.StatusBadRequest, "LEAVE_TYPE_INACTIVE", "Leave type is not active", nil}
 	case errors.Is(err, leave.ErrQuotaNotFound):
-		NotFound(w, "Leave quota not found")
+		return apiError{http.StatusNotFound, "LEAVE_QUOTA_NOT_FOUND", "Leave quota not found", nil}
 	case errors.Is(err, leave.ErrOverlappingLeave):
-		Conflict(w, "Leave dates overlap with existing request")
+		return apiError{http.StatusConflict, "LEAVE_OVERLAPPING_LEAVE", "Leave dates overlap with existing request", nil}
+	case errors.Is(err, leave.ErrLeaveRequestClosed):
+		return apiError{http.StatusConflict, "LEAVE_REQUEST_CLOSED", "Rejected or cancelled leave requests cannot be recomputed", nil}
+	case errors.Is(err, leave.ErrLeaveRequestCancelled):
+		return apiError{http.StatusConflict, "LEAVE_REQUEST_CANCELLED", "Cancelled leave requests cannot be reopened", nil}
+	case errors.Is(err, leave.ErrLeaveRequestNotRejected):
+		return apiError{http.StatusConflict, "LEAVE_REQUEST_NOT_REJECTED", "Only rejected leave requests can be reopened", nil}
+	case errors.Is(err, leave.ErrLeaveAlreadyStarted):
+		return apiError{http.StatusBadRequest, "LEAVE_ALREADY_STARTED", "Leave has already started", nil}
+	case errors.Is(err, leave.ErrReopenWindowExpired):
+		return apiError{http.StatusBadRequest, "LEAVE_REOPEN_WINDOW_EXPIRED", "Leave request was rejected too long ago to be reopened", nil}
 	case errors.Is(err, leave.ErrLeaveAlreadyProcessed):
-		Conflict(w, "leave request is not in waiting approval status")
+		return apiError{http.StatusConflict, "LEAVE_ALREADY_PROCESSED", "leave request is not in waiting approval status", nil}
 	case errors.Is(err, leave.ErrBackdateNotAllowed):
-		BadRequest(w, "Backdate leave is not allowed", nil)
+		return apiError{http.StatusBadRequest, "LEAVE_BACKDATE_NOT_ALLOWED", "Backdate leave is not allowed", nil}
 	case errors.Is(err, leave.ErrBackdateTooOld):
-		BadRequest(w, "Backdate exceeds maximum allowed days", nil)
+		return apiError{http.StatusBadRequest, "LEAVE_BACKDATE_TOO_OLD", "Backdate exceeds maximum allowed days", nil}
 	case errors.Is(err, leave.ErrInsufficientNotice):
-		BadRequest(w, "Insufficient notice period", nil)
+		return apiError{http.StatusBadRequest, "LEAVE_INSUFFICIENT_NOTICE", "Insufficient notice period", nil}
 	case errors.Is(err, leave.ErrTooFarAdvance):
-		BadRequest(w, "Leave date is too far in advance", nil)
+		return apiError{http.StatusBadRequest, "LEAVE_TOO_FAR_ADVANCE", "Leave date is too far in advance", nil}
 	case errors.Is(err, leave.ErrExceedsMaxDays):
-		BadRequest(w, "Leave duration exceeds maximum days per request", nil)
+		return apiError{http.StatusBadRequest, "LEAVE_EXCEEDS_MAX_DAYS", "Leave duration exceeds maximum days per request", nil}
 	case errors.Is(err, leave.ErrAttachmentRequired):
-		BadRequest(w, "Attachment is required for this leave type", nil)
+		return apiError{http.StatusBadRequest, "LEAVE_ATTACHMENT_REQUIRED", "Attachment is required for this leave type", nil}
 	case errors.Is(err, leave.ErrNotEligible):
-		Forbidden(w, "Employee is not eligible for this leave type")
+		return apiError{http.StatusForbidden, "LEAVE_NOT_ELIGIBLE", "Employee is not eligible for this leave type", nil}
 	case errors.Is(err, leave.ErrInsufficientTenure):
-		Forbidden(w, "Insufficient tenure for this leave type")
+		return apiError{http.StatusForbidden, "LEAVE_INSUFFICIENT_TENURE", "Insufficient tenure for this leave type", nil}
 	case errors.Is(err, leave.ErrProbationNotEligible):
-		Forbidden(w, "Probation employees are not eligible")
+		return apiError{http.StatusForbidden, "LEAVE_PROBATION_NOT_ELIGIBLE", "Probation employees are not eligible", nil}
 	case errors.Is(err, leave.ErrQuotaNotAvailable):
-		BadRequest(w, "No quota available for this leave type", nil)
+		return apiError{http.StatusBadRequest, "LEAVE_QUOTA_NOT_AVAILABLE", "No quota available for this leave type", nil}
 	case errors.Is(err, leave.ErrPositionNotEligible):
-		Forbidden(w, "Employee position is not eligible for this leave type")
+		return apiError{http.StatusForbidden, "LEAVE_POSITION_NOT_ELIGIBLE", "Employee position is not eligible for this leave type", nil}
 	case errors.Is(err, leave.ErrGradeNotEligible):
-		Forbidden(w, "Employee grade is not eligible for this leave type")
+		return apiError{http.StatusForbidden, "LEAVE_GRADE_NOT_ELIGIBLE", "Employee grade is not eligible for this leave type", nil}
 	case errors.Is(err, leave.ErrEmploymentTypeNotEligible):
-		Forbidden(w, "Employee employment type is not eligible for this leave type")
+		return apiError{http.StatusForbidden, "LEAVE_EMPLOYMENT_TYPE_NOT_ELIGIBLE", "Employee employment type is not eligible for this leave type", nil}
 	case errors.Is(err, leave.ErrCombinedRequirementsNotMet):
-		Forbidden(w, "Employee does not meet combined eligibility requirements")
+		return apiError{http.StatusForbidden, "LEAVE_COMBINED_REQUIREMENTS_NOT_MET", "Employee does not meet combined eligibility requirements", nil}
 	case errors.Is(err, leave.ErrMinimumTenureNotMet):
-		Forbidden(w, "Employee does not meet minimum tenure requirement")
+		return apiError{http.StatusForbidden, "LEAVE_MINIMUM_TENURE_NOT_MET", "Employee does not meet minimum tenure requirement", nil}
 	case errors.Is(err, leave.ErrFileSizeExceeds):
-		BadRequest(w, "File size exceeds 5MB", nil)
+		return apiError{http.StatusBadRequest, "LEAVE_FILE_SIZE_EXCEEDS", "File size exceeds 5MB", nil}
 	case errors.Is(err, leave.ErrFileTypeNotAllowed):
-		BadRequest(w, "File type not allowed. Allowed: pdf, jpg, jpeg, png", nil)
+		return apiError{http.StatusBadRequest, "LEAVE_FILE_TYPE_NOT_ALLOWED", "File type not allowed. Allowed: pdf, jpg, jpeg, png", nil}
 	case errors.Is(err, leave.ErrUnauthorizedAccess):
-		Forbidden(w, "Unauthorized access to leave request")
+		return apiError{http.StatusForbidden, "LEAVE_UNAUTHORIZED_ACCESS", "Unauthorized access to leave request", nil}
 	case errors.Is(err, leave.ErrUnauthorizedAccessQuota):
-		Forbidden(w, "Unauthorized access to leave quota")
+		return apiError{http.StatusForbidden, "LEAVE_UNAUTHORIZED_ACCESS_QUOTA", "Unauthorized access to leave quota", nil}
 	case errors.Is(err, leave.ErrNegativeQuota):
-		BadRequest(w, "Adjustment would result in negative available quota", nil)
+		return apiError{http.StatusBadRequest, "LEAVE_NEGATIVE_QUOTA", "Adjustment would result in negative available quota", nil}
 
 	// User domain errors
 	case errors.Is(err, user.ErrUserNotFound):
-		NotFound(w, "User not found")
+		return apiError{http.StatusNotFound, "USER_NOT_FOUND", "User not found", nil}
 	case errors.Is(err, user.ErrInvalidEmailFormat):
-		BadRequest(w, "Invalid email format", nil)
+		return apiError{http.StatusBadRequest, "USER_INVALID_EMAIL_FORMAT", "Invalid email format", nil}
 	case errors.Is(err, user.ErrInvalidPasswordLength):
-		BadRequest(w, "Password must be at least 8 characters", nil)
+		return apiError{http.StatusBadRequest, "USER_INVALID_PASSWORD_LENGTH", "Password must be at least 8 characters", nil}
 	case errors.Is(err, user.ErrInvalidOAuthProvider):
-		BadRequest(w, "Invalid oauth provider", nil)
+		return apiError{http.StatusBadRequest, "USER_INVALID_OAUTH_PROVIDER", "Invalid oauth provider", nil}
 	case errors.Is(err, user.ErrOAuthProviderIDExists):
-		Conflict(w, "OAuth provider id already registered")
+		return apiError{http.StatusConflict, "USER_OAUTH_PROVIDER_ID_EXISTS", "OAuth provider id already registered", nil}
 	case errors.Is(err, user.ErrEmailNotVerified):
-		Forbidden(w, "Email not verified")
+		return apiError{http.StatusForbidden, "USER_EMAIL_NOT_VERIFIED", "Email not verified", nil}
 	case errors.Is(err, user.ErrEmailVerificationTokenEmpty):
-		BadRequest(w, "Email verification token is empty", nil)
+		return apiError{http.StatusBadRequest, "USER_EMAIL_VERIFICATION_TOKEN_EMPTY", "Email verification token is empty", nil}
 	case errors.Is(err, user.ErrAdminPrivilegeRequired):
-		Forbidden(w, "Admin privilege required")
+		return apiError{http.StatusForbidden, "USER_ADMIN_PRIVILEGE_REQUIRED", "Admin privilege required", nil}
 	case errors.Is(err, user.ErrOwnerAccessRequired):
-		Forbidden(w, "Owner access required")
+		return apiError{http.StatusForbidden, "USER_OWNER_ACCESS_REQUIRED", "Owner access required", nil}
 	case errors.Is(err, user.ErrPendingRoleRequired):
-		Forbidden(w, "Pending role required")
+		return apiError{http.StatusForbidden, "USER_PENDING_ROLE_REQUIRED", "Pending role required", nil}
 	case errors.Is(err, user.ErrManagerAccessRequired):
-		Forbidden(w, "Manager access required")
+		return apiError{http.StatusForbidden, "USER_MANAGER_ACCESS_REQUIRED", "Manager access required", nil}
 	case errors.Is(err, user.ErrPendingRoleAccessRequired):
-		Forbidden(w, "Pending role access required")
+		return apiError{http.StatusForbidden, "USER_PENDING_ROLE_ACCESS_REQUIRED", "Pending role access required", nil}
 	case errors.Is(err, user.ErrInsufficientPermissions):
-		Forbidden(w, "Insufficient permissions")
+		return apiError{http.StatusForbidden, "USER_INSUFFICIENT_PERMISSIONS", "Insufficient permissions", nil}
 	case errors.Is(err, user.ErrCompanyIDRequired):
-		Forbidden(w, "Create a company or join a company to access")
+		return apiError{http.StatusForbidden, "USER_COMPANY_ID_REQUIRED", "Create a company or join a company to access", nil}
 	case errors.Is(err, user.ErrUpdatedAtBeforeCreatedAt):
-		BadRequest(w, "updated_at cannot be before created_at", nil)
+		return apiError{http.StatusBadRequest, "USER_UPDATED_AT_BEFORE_CREATED_AT", "updated_at cannot be before created_at", nil}
+
+	// Role domain errors
+	case errors.Is(err, role.ErrRoleNotFound):
+		return apiError{http.StatusNotFound, "ROLE_NOT_FOUND", "Role not found", nil}
+	case errors.Is(err, role.ErrRoleNameExists):
+		return apiError{http.StatusConflict, "ROLE_NAME_EXISTS", "Role with this name already exists", nil}
 
 	// Company domain errors
 	case errors.Is(err, company.ErrCompanyNotFound):
-		NotFound(w, "Company not found")
+		return apiError{http.StatusNotFound, "COMPANY_NOT_FOUND", "Company not found", nil}
 	case errors.Is(err, company.ErrInvalidCompanyUsernameFormat):
-		BadRequest(w, "Invalid company username format", nil)
+		return apiError{http.StatusBadRequest, "COMPANY_INVALID_COMPANY_USERNAME_FORMAT", "Invalid company username format", nil}
 	case errors.Is(err, company.ErrInvalidCompanyName):
-		BadRequest(w, "Company name cannot be empty", nil)
+		return apiError{http.StatusBadRequest, "COMPANY_INVALID_COMPANY_NAME", "Company name cannot be empty", nil}
 	case errors.Is(err, company.ErrUpdatedAtBeforeCreatedAt):
-		BadRequest(w, "updated_at cannot be before created_at", nil)
+		return apiError{http.StatusBadRequest, "COMPANY_UPDATED_AT_BEFORE_CREATED_AT", "updated_at cannot be before created_at", nil}
 	case errors.Is(err, company.ErrCompanyUsernameExists):
-		Conflict(w, "Company username already exists")
+		return apiError{http.StatusConflict, "COMPANY_USERNAME_EXISTS", "Company username already exists", nil}
 	case errors.Is(err, company.ErrFileSizeExceeds):
-		BadRequest(w, "File size exceeds 5MB", nil)
+		return apiError{http.StatusBadRequest, "COMPANY_FILE_SIZE_EXCEEDS", "File size exceeds 5MB", nil}
+
+	// Company holidays
+	case errors.Is(err, company.ErrHolidayNotFound):
+		return apiError{http.StatusNotFound, "HOLIDAY_NOT_FOUND", "Holiday not found", nil}
+	case errors.Is(err, company.ErrHolidayExists):
+		return apiError{http.StatusConflict, "HOLIDAY_EXISTS", "A holiday already exists on this date", nil}
 
 	// Master data - Branch domain errors
 	case errors.Is(err, branch.ErrBranchNotFound):
-		NotFound(w, "Branch not found")
+		return apiError{http.StatusNotFound, "BRANCH_NOT_FOUND", "Branch not found", nil}
 	case errors.Is(err, branch.ErrBranchNameExists):
-		Conflict(w, "Branch with this name already exists")
+		return apiError{http.StatusConflict, "BRANCH_NAME_EXISTS", "Branch with this name already exists", nil}
+	case errors.Is(err, branch.ErrBranchInUse):
+		return apiError{http.StatusConflict, "BRANCH_IN_USE", "Branch is still referenced by employees, managers or job history. Pass reassign_to to move employees and managers to another branch; a branch in job history cannot be deleted", nil}
+	case errors.Is(err, branch.ErrInvalidReassignTarget):
+		return apiError{http.StatusBadRequest, "BRANCH_INVALID_REASSIGN_TARGET", "reassign_to must be another existing branch", nil}
 	case errors.Is(err, branch.ErrBranchesNotFound):
-		NotFound(w, "Branches not found")
+		return apiError{http.StatusNotFound, "BRANCHES_NOT_FOUND", "Branches not found", nil}
 	case errors.Is(err, branch.ErrUnauthorizedAccess):
-		Forbidden(w, "Unauthorized access to branch")
+		return apiError{http.StatusForbidden, "BRANCH_UNAUTHORIZED_ACCESS", "Unauthorized access to branch", nil}
 	case errors.Is(err, branch.ErrInvalidTimezone):
-		BadRequest(w, "Invalid timezone", nil)
+		return apiError{http.StatusBadRequest, "BRANCH_INVALID_TIMEZONE", "Invalid timezone", nil}
 
 	// Master data - Grade domain errors
 	case errors.Is(err, grade.ErrGradeNotFound):
-		NotFound(w, "Grade not found")
+		return apiError{http.StatusNotFound, "GRADE_NOT_FOUND", "Grade not found", nil}
 	case errors.Is(err, grade.ErrGradeNameExists):
-		Conflict(w, "Grade with this name already exists")
+		return apiError{http.StatusConflict, "GRADE_NAME_EXISTS", "Grade with this name already exists", nil}
+	case errors.Is(err, grade.ErrGradeInUse):
+		return apiError{http.StatusConflict, "GRADE_IN_USE", "Grade is still referenced by employees or job history. Pass reassign_to to move employees to another grade; a grade in job history cannot be deleted", nil}
+	case errors.Is(err, grade.ErrInvalidReassignTarget):
+		return apiError{http.StatusBadRequest, "GRADE_INVALID_REASSIGN_TARGET", "reassign_to must be another existing grade", nil}
 	case errors.Is(err, grade.ErrGradesNotFound):
-		NotFound(w, "Grades not found")
+		return apiError{http.StatusNotFound, "GRADES_NOT_FOUND", "Grades not found", nil}
 	case errors.Is(err, grade.ErrUnauthorizedAccess):
-		Forbidden(w, "Unauthorized access to grade")
+		return apiError{http.StatusForbidden, "GRADE_UNAUTHORIZED_ACCESS", "Unauthorized access to grade", nil}
 
 	// Master data - Position domain errors
 	case errors.Is(err, position.ErrPositionNotFound):
-		NotFound(w, "Position not found")
+		return apiError{http.StatusNotFound, "POSITION_NOT_FOUND", "Position not found", nil}
 	case errors.Is(err, position.ErrPositionNameExists):
-		Conflict(w, "Position with this name already exists")
+		return apiError{http.StatusConflict, "POSITION_NAME_EXISTS", "Position with this name already exists", nil}
+	case errors.Is(err, position.ErrPositionInUse):
+		return apiError{http.StatusConflict, "POSITION_IN_USE", "Position is still referenced by employees or job history. Pass reassign_to to move employees to another position; a position in job history cannot be deleted", nil}
+	case errors.Is(err, position.ErrInvalidReassignTarget):
+		return apiError{http.StatusBadRequest, "POSITION_INVALID_REASSIGN_TARGET", "reassign_to must be another existing position", nil}
 	case errors.Is(err, position.ErrPositionsNotFound):
-		NotFound(w, "Positions not found")
+		return apiError{http.StatusNotFound, "POSITIONS_NOT_FOUND", "Positions not found", nil}
 	case errors.Is(err, position.ErrUnauthorizedAccess):
-		Forbidden(w, "Unauthorized access to position")
+		return apiError{http.StatusForbidden, "POSITION_UNAUTHORIZED_ACCESS", "Unauthorized access to position", nil}
 
 	// Schedule errors
 	// Schedule domain errors
 	case errors.Is(err, schedule.ErrWorkScheduleNotFound):
-		NotFound(w, "Work schedule not found")
+		return apiError{http.StatusNotFound, "SCHEDULE_WORK_SCHEDULE_NOT_FOUND", "Work schedule not found", nil}
 	case errors.Is(err, schedule.ErrWorkScheduleAlreadyDeleted):
-		NotFound(w, "Work schedule not found or already deleted")
+		return apiError{http.StatusNotFound, "SCHEDULE_WORK_SCHEDULE_ALREADY_DELETED", "Work schedule not found or already deleted", nil}
 	case errors.Is(err, schedule.ErrWorkScheduleNameExists):
-		Conflict(w, "Work schedule with this name already exists")
+		return apiError{http.StatusConflict, "SCHEDULE_WORK_SCHEDULE_NAME_EXISTS", "Work schedule with this name already exists", nil}
 	case errors.Is(err, schedule.ErrWorkScheduleTimeNotFound):
-		NotFound(w, "Work schedule time not found")
+		return apiError{http.StatusNotFound, "SCHEDULE_WORK_SCHEDULE_TIME_NOT_FOUND", "Work schedule time not found", nil}
 	case errors.Is(err, schedule.ErrWorkScheduleLocationNotFound):
-		NotFound(w, "Work schedule location not found")
+		return apiError{http.StatusNotFound, "SCHEDULE_WORK_SCHEDULE_LOCATION_NOT_FOUND", "Work schedule location not found", nil}
 	case errors.Is(err, schedule.ErrEmployeeScheduleAssignmentNotFound):
-		NotFound(w, "Employee schedule assignment not found")
+		return apiError{http.StatusNotFound, "SCHEDULE_EMPLOYEE_SCHEDULE_ASSIGNMENT_NOT_FOUND", "Employee schedule assignment not found", nil}
 	case errors.Is(err, schedule.ErrOverlappingScheduleAssignment):
-		Conflict(w, "Overlapping schedule assignment detected")
+		return apiError{http.StatusConflict, "SCHEDULE_OVERLAPPING_SCHEDULE_ASSIGNMENT", "Overlapping schedule assignment detected", nil}
+	case errors.Is(err, schedule.ErrAssignmentOutsideEmployment):
+		return apiError{http.StatusBadRequest, "SCHEDULE_ASSIGNMENT_OUTSIDE_EMPLOYMENT", "Schedule assignment must fall between the employee's hire date and resignation date", nil}
 	case errors.Is(err, schedule.ErrEmployeeIDRequired):
-		BadRequest(w, "Employee ID is required", nil)
+		return apiError{http.StatusBadRequest, "SCHEDULE_EMPLOYEE_ID_REQUIRED", "Employee ID is required", nil}
 	case errors.Is(err, schedule.ErrInvalidDateFormat):
-		BadRequest(w, "Invalid date format. Use YYYY-MM-DD", nil)
+		return apiError{http.StatusBadRequest, "SCHEDULE_INVALID_DATE_FORMAT", "Invalid date format. Use YYYY-MM-DD", nil}
 	case errors.Is(err, schedule.ErrWorkScheduleTimeExists):
-		Conflict(w, "Work schedule time already exists")
+		return apiError{http.StatusConflict, "SCHEDULE_WORK_SCHEDULE_TIME_EXISTS", "This work schedule already has a time for that day of the week", nil}
 	case errors.Is(err, schedule.ErrInvalidWorkScheduleType):
-		BadRequest(w, "Work schedule type must be 'WFO' or 'Hybrid'", nil)
+		return apiError{http.StatusBadRequest, "SCHEDULE_INVALID_WORK_SCHEDULE_TYPE", "Work schedule type must be 'WFO' or 'Hybrid'", nil}
 	case errors.Is(err, schedule.ErrEmployeeScheduleTimelineNotFound):
-		NotFound(w, "Employee schedule timeline not found")
+		return apiError{http.StatusNotFound, "SCHEDULE_EMPLOYEE_SCHEDULE_TIMELINE_NOT_FOUND", "Employee schedule timeline not found", nil}
+	case errors.Is(err, schedule.ErrUnsupportedExportFormat):
+		return apiError{http.StatusBadRequest, "SCHEDULE_UNSUPPORTED_EXPORT_FORMAT", "Unsupported export format, use 'csv'", nil}
 	case errors.Is(err, schedule.ErrMismatchedLocationType):
-		BadRequest(w, "Mismatched location type for work schedule", nil)
+		return apiError{http.StatusBadRequest, "SCHEDULE_MISMATCHED_LOCATION_TYPE", "Mismatched location type for work schedule", nil}
+	case errors.Is(err, schedule.ErrWorkScheduleInUse):
+		return apiError{http.StatusConflict, "SCHEDULE_WORK_SCHEDULE_IN_USE", "Work schedule is assigned to employees; pass force=true to clear it anyway", nil}
+	case errors.Is(err, schedule.ErrWorkScheduleTimeInUse):
+		return apiError{http.StatusConflict, "SCHEDULE_WORK_SCHEDULE_TIME_IN_USE", "Work schedule times are referenced by attendance records and cannot be deleted", nil}
+	case errors.Is(err, schedule.ErrIncompleteSchedule):
+		return apiError{http.StatusBadRequest, "SCHEDULE_INCOMPLETE_SCHEDULE", "Work schedule has no working times configured; add times before assigning it", nil}
+	case errors.Is(err, schedule.ErrShiftSwapNotFound):
+		return apiError{http.StatusNotFound, "SCHEDULE_SHIFT_SWAP_NOT_FOUND", "Shift swap request not found", nil}
+	case errors.Is(err, schedule.ErrShiftSwapSameEmployee):
+		return apiError{http.StatusBadRequest, "SCHEDULE_SHIFT_SWAP_SAME_EMPLOYEE", "Cannot swap a shift with yourself", nil}
+	case errors.Is(err, schedule.ErrShiftSwapSameSchedule):
+		return apiError{http.StatusBadRequest, "SCHEDULE_SHIFT_SWAP_SAME_SCHEDULE", "Both employees already have the same schedule on this date", nil}
+	case errors.Is(err, schedule.ErrShiftSwapDateInPast):
+		return apiError{http.StatusBadRequest, "SCHEDULE_SHIFT_SWAP_DATE_IN_PAST", "Shift swap date must not be in the past", nil}
+	case errors.Is(err, schedule.ErrShiftSwapOverlap):
+		return apiError{http.StatusConflict, "SCHEDULE_SHIFT_SWAP_OVERLAP", "Shift swap would overlap an existing schedule assignment", nil}
+	case errors.Is(err, schedule.ErrShiftSwapAlreadyRequested):
+		return apiError{http.StatusConflict, "SCHEDULE_SHIFT_SWAP_ALREADY_REQUESTED", "A pending shift swap already exists for this date", nil}
+	case errors.Is(err, schedule.ErrShiftSwapAlreadyProcessed):
+		return apiError{http.StatusConflict, "SCHEDULE_SHIFT_SWAP_ALREADY_PROCESSED", "Shift swap request has already been approved or rejected", nil}
 
 	// Attendance domain errors
 	case errors.Is(err, attendance.ErrAlreadyCheckedIn):
-		Conflict(w, "You have already checked in today")
+		return apiError{http.StatusConflict, "ATTENDANCE_ALREADY_CHECKED_IN", "You have already checked in today", nil}
+	case errors.Is(err, attendance.ErrOnLeave):
+		return apiError{http.StatusConflict, "ATTENDANCE_ON_LEAVE", "You are on approved leave today", nil}
 	case errors.Is(err, attendance.ErrNoScheduleFound):
-		NotFound(w, "No schedule found for today")
+		return apiError{http.StatusNotFound, "ATTENDANCE_NO_SCHEDULE_FOUND", "No schedule found for today", nil}
 	case errors.Is(err, attendance.ErrOutsideAllowedRadius):
-		Forbidden(w, "You are outside the allowed radius")
+		return apiError{http.StatusForbidden, "ATTENDANCE_OUTSIDE_ALLOWED_RADIUS", "You are outside the allowed radius", nil}
 	case errors.Is(err, attendance.ErrTooEarlyToCheckIn):
-		BadRequest(w, "Too early to check in", nil)
+		return apiError{http.StatusBadRequest, "ATTENDANCE_TOO_EARLY_TO_CHECK_IN", "Too early to check in", nil}
 	case errors.Is(err, attendance.ErrNotCheckedIn):
-		BadRequest(w, "You have not checked in yet", nil)
+		return apiError{http.StatusBadRequest, "ATTENDANCE_NOT_CHECKED_IN", "You have not checked in yet", nil}
 	case errors.Is(err, attendance.ErrAlreadyCheckedOut):
-		Conflict(w, "You have already checked out")
+		return apiError{http.StatusConflict, "ATTENDANCE_ALREADY_CHECKED_OUT", "You have already checked out", nil}
 	case errors.Is(err, attendance.ErrAttendanceNotFound):
-		NotFound(w, "Attendance record not found")
+		return apiError{http.StatusNotFound, "ATTENDANCE_NOT_FOUND", "Attendance record not found", nil}
 	case errors.Is(err, attendance.ErrUnauthorized):
-		Forbidden(w, "Unauthorized to access this attendance record")
+		return apiError{http.StatusForbidden, "ATTENDANCE_UNAUTHORIZED", "Unauthorized to access this attendance record", nil}
+	case errors.Is(err, attendance.ErrInvalidImportFile):
+		return apiError{http.StatusBadRequest, "ATTENDANCE_INVALID_IMPORT_FILE", "Import file must be a CSV with a header row containing employee_code and date", nil}
+	case errors.Is(err, attendance.ErrImportTooLarge):
+		return apiError{http.StatusBadRequest, "ATTENDANCE_IMPORT_TOO_LARGE", "Import file must not contain more than " + strconv.Itoa(attendance.MaxImportRows) + " rows", nil}
 
 	// Invitation domain errors
 	case errors.Is(err, invitation.ErrInvitationNotFound):
-		NotFound(w, "Invitation not found")
+		return apiError{http.StatusNotFound, "INVITATION_NOT_FOUND", "Invitation not found", nil}
 	case errors.Is(err, invitation.ErrInvitationExpired):
-		BadRequest(w, "Invitation has expired", nil)
+		return apiError{http.StatusBadRequest, "INVITATION_EXPIRED", "Invitation has expired", nil}
 	case errors.Is(err, invitation.ErrInvitationAlreadyUsed):
-		Conflict(w, "Invitation has already been used")
+		return apiError{http.StatusConflict, "INVITATION_ALREADY_USED", "Invitation has already been used", nil}
 	case errors.Is(err, invitation.ErrInvitationRevoked):
-		BadRequest(w, "Invitation has been revoked", nil)
+		return apiError{http.StatusBadRequest, "INVITATION_REVOKED", "Invitation has been revoked", nil}
 	case errors.Is(err, invitation.ErrEmailAlreadyInvited):
-		Conflict(w, "This email already has a pending invitation")
+		return apiError{http.StatusConflict, "INVITATION_EMAIL_ALREADY_INVITED", "This email already has a pending invitation", nil}
 	case errors.Is(err, invitation.ErrEmailMismatch):
-		Forbidden(w, "Your email does not match the invitation")
+		return apiError{http.StatusForbidden, "INVITATION_EMAIL_MISMATCH", "Your email does not match the invitation", nil}
 	case errors.Is(err, invitation.ErrNoPendingInvitation):
-		NotFound(w, "No pending invitation found for this employee")
+		return apiError{http.StatusNotFound, "INVITATION_NO_PENDING_INVITATION", "No pending invitation found for this employee", nil}
 	case errors.Is(err, invitation.ErrEmployeeAlreadyLinked):
-		Conflict(w, "Employee is already linked to a user")
+		return apiError{http.StatusConflict, "INVITATION_EMPLOYEE_ALREADY_LINKED", "Employee is already linked to a user", nil}
 	case errors.Is(err, invitation.ErrUserAlreadyHasCompany):
-		Conflict(w, "User already belongs to a company")
+		return apiError{http.StatusConflict, "INVITATION_USER_ALREADY_HAS_COMPANY", "User already belongs to a company", nil}
 	case errors.Is(err, invitation.ErrCannotRevokeAccepted):
-		BadRequest(w, "Cannot revoke an accepted invitation", nil)
+		return apiError{http.StatusBadRequest, "INVITATION_CANNOT_REVOKE_ACCEPTED", "Cannot revoke an accepted invitation", nil}
 	case errors.Is(err, employee.ErrCannotDeleteSelf):
-		Forbidden(w, "You cannot delete your own employee record")
+		return apiError{http.StatusForbidden, "EMPLOYEE_CANNOT_DELETE_SELF", "You cannot delete your own employee record", nil}
 
 	// Subscription domain errors
 	case errors.Is(err, subscription.ErrSubscriptionNotFound):
-		NotFound(w, "Subscription not found")
+		return apiError{http.StatusNotFound, "SUBSCRIPTION_NOT_FOUND", "Subscription not found", nil}
 	case errors.Is(err, subscription.ErrSubscriptionExpired):
-		Forbidden(w, "Subscription has expired")
+		return apiError{http.StatusForbidden, "SUBSCRIPTION_EXPIRED", "Subscription has expired", nil}
 	case errors.Is(err, subscription.ErrSubscriptionCancelled):
-		Forbidden(w, "Subscription has been cancelled")
+		return apiError{http.StatusForbidden, "SUBSCRIPTION_CANCELLED", "Subscription has been cancelled", nil}
+	case errors.Is(err, subscription.ErrSubscriptionPastDue):
+		return apiError{http.StatusPaymentRequired, "SUBSCRIPTION_PAST_DUE", "Subscription is past due. The account is read-only until the outstanding invoice is paid", nil}
 	case errors.Is(err, subscription.ErrAlreadySubscribed):
-		Conflict(w, "Company already has an active subscription")
+		return apiError{http.StatusConflict, "SUBSCRIPTION_ALREADY_SUBSCRIBED", "Company already has an active subscription", nil}
 	case errors.Is(err, subscription.ErrInvalidSubscriptionState):
-		BadRequest(w, "Invalid subscription state for this operation", nil)
+		return apiError{http.StatusBadRequest, "SUBSCRIPTION_INVALID_SUBSCRIPTION_STATE", "Invalid subscription state for this operation", nil}
 	case errors.Is(err, subscription.ErrTrialNotAllowed):
-		BadRequest(w, subscription.ErrTrialNotAllowed.Error(), nil)
+		return apiError{http.StatusBadRequest, "SUBSCRIPTION_TRIAL_NOT_ALLOWED", subscription.ErrTrialNotAllowed.Error(), nil}
 
 	// Plan errors
 	case errors.Is(err, subscription.ErrPlanNotFound):
-		NotFound(w, "Subscription plan not found")
+		return apiError{http.StatusNotFound, "SUBSCRIPTION_PLAN_NOT_FOUND", "Subscription plan not found", nil}
 	case errors.Is(err, subscription.ErrPlanNotActive):
-		BadRequest(w, "Subscription plan is not active", nil)
+		return apiError{http.StatusBadRequest, "SUBSCRIPTION_PLAN_NOT_ACTIVE", "Subscription plan is not active", nil}
 	case errors.Is(err, subscription.ErrInvalidPlanDowngrade):
-		BadRequest(w, "Cannot downgrade to a higher tier plan", nil)
+		return apiError{http.StatusBadRequest, "SUBSCRIPTION_INVALID_PLAN_DOWNGRADE", "Cannot downgrade to a higher tier plan", nil}
 	case errors.Is(err, subscription.ErrInvalidPlanUpgrade):
-		BadRequest(w, "Cannot upgrade to a lower tier plan", nil)
+		return apiError{http.StatusBadRequest, "SUBSCRIPTION_INVALID_PLAN_UPGRADE", "Cannot upgrade to a lower tier plan", nil}
 	case errors.Is(err, subscription.ErrSamePlan):
-		Conflict(w, "Already subscribed to this plan")
+		return apiError{http.StatusConflict, "SUBSCRIPTION_SAME_PLAN", "Already subscribed to this plan", nil}
 	case errors.Is(err, subscription.ErrNotAnUpgrade):
-		BadRequest(w, "Target plan is not an upgrade from current plan", nil)
+		return apiError{http.StatusBadRequest, "SUBSCRIPTION_NOT_AN_UPGRADE", "Target plan is not an upgrade from current plan", nil}
 	case errors.Is(err, subscription.ErrNotADowngrade):
-		BadRequest(w, "Target plan is not a downgrade from current plan", nil)
+		return apiError{http.StatusBadRequest, "SUBSCRIPTION_NOT_A_DOWNGRADE", "Target plan is not a downgrade from current plan", nil}
+	case errors.Is(err, subscription.ErrUnsupportedCurrency):
+		return apiError{http.StatusBadRequest, "SUBSCRIPTION_UNSUPPORTED_CURRENCY", "Subscription plan currency is not supported for checkout", nil}
 
 	// Seat errors
 	case errors.Is(err, subscription.ErrInsufficientSeats):
-		BadRequest(w, "Seat count must be greater than or equal to active employees", nil)
+		return apiError{http.StatusBadRequest, "SUBSCRIPTION_INSUFFICIENT_SEATS", "Seat count must be greater than or equal to active employees", nil}
 	case errors.Is(err, subscription.ErrMaxSeatsReached):
-		Forbidden(w, "Maximum seats limit reached")
+		return apiError{http.StatusForbidden, "SUBSCRIPTION_MAX_SEATS_REACHED", "Maximum seats limit reached", nil}
 	case errors.Is(err, subscription.ErrExceedsPlanMaxSeats):
-		BadRequest(w, "Requested seats exceed plan maximum", nil)
+		return apiError{http.StatusBadRequest, "SUBSCRIPTION_EXCEEDS_PLAN_MAX_SEATS", "Requested seats exceed plan maximum", nil}
 	case errors.Is(err, subscription.ErrSeatLimitExceeded):
-		Forbidden(w, "Seat limit exceeded for current subscription")
+		return apiError{http.StatusForbidden, "SUBSCRIPTION_SEAT_LIMIT_EXCEEDED", "Seat limit exceeded for current subscription", nil}
 	case errors.Is(err, subscription.ErrSeatsBelowActive):
-		BadRequest(w, "Seat count cannot be less than active employees", nil)
+		return apiError{http.StatusBadRequest, "SUBSCRIPTION_SEATS_BELOW_ACTIVE", "Seat count cannot be less than active employees", nil}
 
 	// Feature errors
 	case errors.Is(err, subscription.ErrFeatureNotFound):
-		NotFound(w, "Feature not found")
+		return apiError{http.StatusNotFound, "SUBSCRIPTION_FEATURE_NOT_FOUND", "Feature not found", nil}
 	case errors.Is(err, subscription.ErrFeatureNotAllowed):
-		Forbidden(w, "Feature not available in current plan")
+		return apiError{http.StatusForbidden, "SUBSCRIPTION_FEATURE_NOT_ALLOWED", "Feature not available in current plan", nil}
 	case errors.Is(err, subscription.ErrFeatureNotAvailable):
-		Forbidden(w, "Feature not available in current subscription")
+		return apiError{http.StatusForbidden, "SUBSCRIPTION_FEATURE_NOT_AVAILABLE", "Feature not available in current subscription", nil}
 
 	// Invoice errors
 	case errors.Is(err, subscription.ErrInvoiceNotFound):
-		NotFound(w, "Invoice not found")
+		return apiError{http.StatusNotFound, "SUBSCRIPTION_INVOICE_NOT_FOUND", "Invoice not found", nil}
 	case errors.Is(err, subscription.ErrInvoiceAlreadyPaid):
-		Conflict(w, "Invoice has already been paid")
+		return apiError{http.StatusConflict, "SUBSCRIPTION_INVOICE_ALREADY_PAID", "Invoice has already been paid", nil}
 	case errors.Is(err, subscription.ErrInvoiceExpired):
-		BadRequest(w, "Invoice has expired", nil)
+		return apiError{http.StatusBadRequest, "SUBSCRIPTION_INVOICE_EXPIRED", "Invoice has expired", nil}
 	case errors.Is(err, subscription.ErrPendingInvoiceExists):
-		Conflict(w, "Pending invoice already exists")
+		return apiError{http.StatusConflict, "SUBSCRIPTION_PENDING_INVOICE_EXISTS", "Pending invoice already exists", nil}
 
 	// Webhook errors
 	case errors.Is(err, subscription.ErrInvalidWebhookSignature):
-		Forbidden(w, "Invalid webhook signature")
+		return apiError{http.StatusForbidden, "SUBSCRIPTION_INVALID_WEBHOOK_SIGNATURE", "Invalid webhook signature", nil}
+	case errors.Is(err, subscription.ErrInvalidWebhookPayload):
+		return apiError{http.StatusBadRequest, "SUBSCRIPTION_INVALID_WEBHOOK_PAYLOAD", "Invalid webhook payload", nil}
 	case errors.Is(err, subscription.ErrWebhookProcessingFailed):
-		InternalServerError(w, "Failed to process webhook")
+		return apiError{http.StatusInternalServerError, "SUBSCRIPTION_WEBHOOK_PROCESSING_FAILED", "Failed to process webhook", nil}
+
+	// Payroll domain errors
+	case errors.Is(err, payroll.ErrPayrollSettingsNotFound):
+		return apiError{http.StatusNotFound, "PAYROLL_SETTINGS_NOT_FOUND", "Payroll settings not found", nil}
+	case errors.Is(err, payroll.ErrPayrollComponentNotFound):
+		return apiError{http.StatusNotFound, "PAYROLL_COMPONENT_NOT_FOUND", "Payroll component not found", nil}
+	case errors.Is(err, payroll.ErrPayrollComponentNameExists):
+		return apiError{http.StatusConflict, "PAYROLL_COMPONENT_NAME_EXISTS", "Payroll component name already exists", nil}
+	case errors.Is(err, payroll.ErrPayrollRecordNotFound):
+		return apiError{http.StatusNotFound, "PAYROLL_RECORD_NOT_FOUND", "Payroll record not found", nil}
+	case errors.Is(err, payroll.ErrPayrollRecordAlreadyExists):
+		return apiError{http.StatusConflict, "PAYROLL_RECORD_ALREADY_EXISTS", "Payroll record already exists for this period", nil}
+	case errors.Is(err, payroll.ErrPayrollRecordAlreadyPaid):
+		return apiError{http.StatusConflict, "PAYROLL_RECORD_ALREADY_PAID", "Payroll record is already paid and cannot be modified", nil}
+	case errors.Is(err, payroll.ErrInvalidPeriod):
+		return apiError{http.StatusBadRequest, "PAYROLL_INVALID_PERIOD", "Invalid payroll period", nil}
+	case errors.Is(err, payroll.ErrEmployeeHasNoBaseSalary):
+		return apiError{http.StatusBadRequest, "PAYROLL_EMPLOYEE_HAS_NO_BASE_SALARY", "Employee has no base salary configured", nil}
+	case errors.Is(err, payroll.ErrCannotDeletePaidRecord):
+		return apiError{http.StatusConflict, "PAYROLL_CANNOT_DELETE_PAID_RECORD", "Paid payroll records cannot be deleted", nil}
+	case errors.Is(err, payroll.ErrEmployeeComponentNotFound):
+		return apiError{http.StatusNotFound, "PAYROLL_EMPLOYEE_COMPONENT_NOT_FOUND", "Employee component assignment not found", nil}
+	case errors.Is(err, payroll.ErrEmployeeNotFound):
+		return apiError{http.StatusNotFound, "PAYROLL_EMPLOYEE_NOT_FOUND", "Employee not found", nil}
+	case errors.Is(err, payroll.ErrInvalidComponentType):
+		return apiError{http.StatusBadRequest, "PAYROLL_INVALID_COMPONENT_TYPE", "Invalid component type", nil}
+	case errors.Is(err, payroll.ErrPayrollRunNotFound):
+		return apiError{http.StatusNotFound, "PAYROLL_RUN_NOT_FOUND", "Payroll run not found", nil}
+	case errors.Is(err, payroll.ErrPayrollRunInProgress):
+		return apiError{http.StatusConflict, "PAYROLL_RUN_IN_PROGRESS", "Payroll generation for this period is already running, try again shortly", nil}
+	case errors.Is(err, payroll.ErrUnsupportedBankFileFormat):
+		return apiError{http.StatusBadRequest, "PAYROLL_UNSUPPORTED_BANK_FILE_FORMAT", "Unsupported bank file format, use csv or bca", nil}
+	case errors.Is(err, payroll.ErrConcurrentModification):
+		return apiError{http.StatusConflict, "PAYROLL_CONCURRENT_MODIFICATION", "Payroll record was changed by someone else, reload it and try again", nil}
+
+	// Notification domain errors
+	case errors.Is(err, notification.ErrNotificationNotFound):
+		return apiError{http.StatusNotFound, "NOTIFICATION_NOT_FOUND", "Notification not found", nil}
+	case errors.Is(err, notification.ErrUnauthorized):
+		return apiError{http.StatusForbidden, "NOTIFICATION_UNAUTHORIZED", "You are not allowed to access this notification", nil}
+	case errors.Is(err, notification.ErrInvalidNotificationType):
+		return apiError{http.StatusBadRequest, "NOTIFICATION_INVALID_TYPE", "Invalid notification type", nil}
+	case errors.Is(err, notification.ErrPreferenceNotFound):
+		return apiError{http.StatusNotFound, "NOTIFICATION_PREFERENCE_NOT_FOUND", "Notification preference not found", nil}
+	case errors.Is(err, notification.ErrTemplateNotFound):
+		return apiError{http.StatusNotFound, "NOTIFICATION_TEMPLATE_NOT_FOUND", "Notification template not found", nil}
+
+	// Report domain errors
+	case errors.Is(err, report.ErrInvalidMonth):
+		return apiError{http.StatusBadRequest, "REPORT_INVALID_MONTH", "Month must be between 1 and 12", nil}
+	case errors.Is(err, report.ErrInvalidYear):
+		return apiError{http.StatusBadRequest, "REPORT_INVALID_YEAR", "Year must be a valid year", nil}
+	case errors.Is(err, report.ErrInvalidDateRange):
+		return apiError{http.StatusBadRequest, "REPORT_INVALID_DATE_RANGE", "End date must be after start date", nil}
+	case errors.Is(err, report.ErrNoDataFound):
+		return apiError{http.StatusNotFound, "REPORT_NO_DATA_FOUND", "No data found for the specified criteria", nil}
 
-	// Default
 	default:
-		// Log the error for debugging purposes
-		log.Printf("Unhandled error: %v", err)
-		InternalServerError(w, "An unexpected error occurred")
+		return apiError{http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "An unexpected error occurred", nil}
 	}
 }
@@ -2,7 +2,10 @@ package response
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 )
 
 type Response struct {
@@ -17,6 +20,13 @@ type ErrorDetail struct {
 	Code    string            `json:"code"`
 	Message string            `json:"message"`
 	Details map[string]string `json:"details,omitempty"`
+	Fields  []FieldError      `json:"fields,omitempty"`
+}
+
+// FieldError is one failed field of a VALIDATION_ERROR, listed in field order
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 type Meta struct {
@@ -41,6 +51,13 @@ func writeJSON(w http.ResponseWriter, statusCode int, payload interface{}) {
 	}
 }
 
+func writeError(w http.ResponseWriter, statusCode int, detail *ErrorDetail) {
+	writeJSON(w, statusCode, Response{
+		Success: false,
+		Error:   detail,
+	})
+}
+
 // Success responses
 func Success(w http.ResponseWriter, data interface{}) {
 	writeJSON(w, http.StatusOK, Response{
@@ -75,73 +92,85 @@ func SuccessWithMeta(w http.ResponseWriter, data interface{}, meta *Meta) {
 
 // Error responses
 func BadRequest(w http.ResponseWriter, message string, details map[string]string) {
-	writeJSON(w, http.StatusBadRequest, Response{
-		Success: false,
-		Error: &ErrorDetail{
-			Code:    "BAD_REQUEST",
-			Message: message,
-			Details: details,
-		},
+	writeError(w, http.StatusBadRequest, &ErrorDetail{
+		Code:    "BAD_REQUEST",
+		Message: message,
+		Details: details,
 	})
 }
 
 func ValidationError(w http.ResponseWriter, details map[string]string) {
-	writeJSON(w, http.StatusUnprocessableEntity, Response{
-		Success: false,
-		Error: &ErrorDetail{
-			Code:    "VALIDATION_ERROR",
-			Message: "Validation failed",
-			Details: details,
-		},
+	writeError(w, http.StatusUnprocessableEntity, &ErrorDetail{
+		Code:    "VALIDATION_ERROR",
+		Message: "Validation failed",
+		Details: details,
+		Fields:  fieldErrors(details),
 	})
 }
 
 func Unauthorized(w http.ResponseWriter, message string) {
-	writeJSON(w, http.StatusUnauthorized, Response{
-		Success: false,
-		Error: &ErrorDetail{
-			Code:    "UNAUTHORIZED",
-			Message: message,
-		},
+	writeError(w, http.StatusUnauthorized, &ErrorDetail{
+		Code:    "UNAUTHORIZED",
+		Message: message,
 	})
 }
 
 func Forbidden(w http.ResponseWriter, message string) {
-	writeJSON(w, http.StatusForbidden, Response{
-		Success: false,
-		Error: &ErrorDetail{
-			Code:    "FORBIDDEN",
-			Message: message,
-		},
+	writeError(w, http.StatusForbidden, &ErrorDetail{
+		Code:    "FORBIDDEN",
+		Message: message,
+	})
+}
+
+func PaymentRequired(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusPaymentRequired, &ErrorDetail{
+		Code:    "PAYMENT_REQUIRED",
+		Message: message,
 	})
 }
 
 func NotFound(w http.ResponseWriter, message string) {
-	writeJSON(w, http.StatusNotFound, Response{
-		Success: false,
-		Error: &ErrorDetail{
-			Code:    "NOT_FOUND",
-			Message: message,
-		},
+	writeError(w, http.StatusNotFound, &ErrorDetail{
+		Code:    "NOT_FOUND",
+		Message: message,
 	})
 }
 
 func InternalServerError(w http.ResponseWriter, message string) {
-	writeJSON(w, http.StatusInternalServerError, Response{
-		Success: false,
-		Error: &ErrorDetail{
-			Code:    "INTERNAL_SERVER_ERROR",
-			Message: message,
-		},
+	writeError(w, http.StatusInternalServerError, &ErrorDetail{
+		Code:    "INTERNAL_SERVER_ERROR",
+		Message: message,
 	})
 }
 
 func Conflict(w http.ResponseWriter, message string) {
-	writeJSON(w, http.StatusConflict, Response{
-		Success: false,
-		Error: &ErrorDetail{
-			Code:    "CONFLICT",
-			Message: message,
-		},
+	writeError(w, http.StatusConflict, &ErrorDetail{
+		Code:    "CONFLICT",
+		Message: message,
+	})
+}
+
+func PayloadTooLarge(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusRequestEntityTooLarge, &ErrorDetail{
+		Code:    "PAYLOAD_TOO_LARGE",
+		Message: message,
 	})
 }
+
+func fieldErrors(details map[string]string) []FieldError {
+	fields := make([]FieldError, 0, len(details))
+	for field, message := range details {
+		fields = append(fields, FieldError{Field: field, Message: message})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+	return fields
+}
+
+// File writes a binary attachment (e.g. PDF or CSV export) with the given content type
+func File(w http.ResponseWriter, contentType, filename string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/role"
+	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// RoleHandler handles company role and permission requests
+type RoleHandler interface {
+	ListPermissions(w http.ResponseWriter, r *http.Request)
+	ListRoles(w http.ResponseWriter, r *http.Request)
+	GetRole(w http.ResponseWriter, r *http.Request)
+	CreateRole(w http.ResponseWriter, r *http.Request)
+	UpdateRole(w http.ResponseWriter, r *http.Request)
+	DeleteRole(w http.ResponseWriter, r *http.Request)
+	AssignRole(w http.ResponseWriter, r *http.Request)
+}
+
+type roleHandlerImpl struct {
+	roleService role.RoleService
+}
+
+func NewRoleHandler(roleService role.RoleService) RoleHandler {
+	return &roleHandlerImpl{
+		roleService: roleService,
+	}
+}
+
+// ListPermissions lists every permission and the base role defaults
+// GET /api/v1/roles/permissions
+func (h *roleHandlerImpl) ListPermissions(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, h.roleService.ListPermissions(r.Context()))
+}
+
+// ListRoles lists the company's roles
+// GET /api/v1/roles
+func (h *roleHandlerImpl) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.roleService.ListRoles(r.Context())
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, roles)
+}
+
+// GetRole retrieves a single company role
+// GET /api/v1/roles/{id}
+func (h *roleHandlerImpl) GetRole(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		response.BadRequest(w, "Role ID is required", nil)
+		return
+	}
+
+	result, err := h.roleService.GetRole(r.Context(), id)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
+// CreateRole creates a company role
+// POST /api/v1/roles
+func (h *roleHandlerImpl) CreateRole(w http.ResponseWriter, r *http.Request) {
+	var req role.CreateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request format", nil)
+		return
+	}
+
+	result, err := h.roleService.CreateRole(r.Context(), req)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Created(w, "Role created successfully", result)
+}
+
+// UpdateRole updates a company role
+// PUT /api/v1/roles/{id}
+func (h *roleHandlerImpl) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		response.BadRequest(w, "Role ID is required", nil)
+		return
+	}
+
+	var req role.UpdateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request format", nil)
+		return
+	}
+	req.ID = id
+
+	result, err := h.roleService.UpdateRole(r.Context(), req)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Role updated successfully", result)
+}
+
+// DeleteRole deletes a company role; its holders keep their base role
+// DELETE /api/v1/roles/{id}
+func (h *roleHandlerImpl) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		response.BadRequest(w, "Role ID is required", nil)
+		return
+	}
+
+	if err := h.roleService.DeleteRole(r.Context(), id); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Role deleted successfully", nil)
+}
+
+// AssignRole attaches a company role to an employee's account (null detaches it)
+// PUT /api/v1/employees/{id}/company-role
+func (h *roleHandlerImpl) AssignRole(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		response.BadRequest(w, "Employee ID is required", nil)
+		return
+	}
+
+	var req role.AssignRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request format", nil)
+		return
+	}
+	req.EmployeeID = id
+
+	if err := h.roleService.AssignRole(r.Context(), req); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Role assigned successfully", nil)
+}
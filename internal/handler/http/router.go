@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
 	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/middleware"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/jwt"
 	"github.com/go-chi/chi/v5"
@@ -15,7 +16,7 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 )
 
-func NewRouter(JWTService jwt.Service, authHandler AuthHandler, companyhandler CompanyHandler, leaveHandler LeaveHandler, masterHandler MasterHandler, scheduleHandler ScheduleHandler, attendanceHandler AttendanceHandler, employeeHandler EmployeeHandler, invitationHandler InvitationHandler, payrollHandler PayrollHandler, dashboardHandler DashboardHandler, employeeDashboardHandler EmployeeDashboardHandler, notificationHandler NotificationHandler, reportHandler ReportHandler, subscriptionHandler SubscriptionHandler, subscriptionMiddleware *middleware.SubscriptionMiddleware, storageBasePath string) *chi.Mux {
+func NewRouter(JWTService jwt.Service, authHandler AuthHandler, companyhandler CompanyHandler, leaveHandler LeaveHandler, masterHandler MasterHandler, scheduleHandler ScheduleHandler, attendanceHandler AttendanceHandler, employeeHandler EmployeeHandler, invitationHandler InvitationHandler, payrollHandler PayrollHandler, dashboardHandler DashboardHandler, employeeDashboardHandler EmployeeDashboardHandler, notificationHandler NotificationHandler, reportHandler ReportHandler, subscriptionHandler SubscriptionHandler, roleHandler RoleHandler, subscriptionMiddleware *middleware.SubscriptionMiddleware, branchScopeMiddleware *middleware.BranchScopeMiddleware, permissionMiddleware *middleware.PermissionMiddleware, storageBasePath string, maxBodyBytes int64) *chi.Mux {
 	r := chi.NewRouter()
 	logFormat := httplog.SchemaECS.Concise(false)
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
@@ -35,6 +36,8 @@ func NewRouter(JWTService jwt.Service, authHandler AuthHandler, companyhandler C
 		MaxAge:           300,
 	}))
 
+	r.Use(middleware.LimitBodySize(maxBodyBytes))
+
 	// r.Use(chiMiddleware.RealIP)
 
 	r.Use(httplog.RequestLogger(logger, &httplog.Options{
@@ -52,10 +55,8 @@ func NewRouter(JWTService jwt.Service, authHandler AuthHandler, companyhandler C
 	fileServer := http.FileServer(http.Dir(storageBasePath))
 	r.Handle("/uploads/*", http.StripPrefix("/uploads/", fileServer))
 
-	// Serve OpenAPI JSON file
-	r.Get("/openapi.json", func(w http.ResponseWriter, req *http.Request) {
-		http.ServeFile(w, req, "api/openapi.json")
-	})
+	// Serve the OpenAPI document generated from the routes below
+	r.Get("/openapi.json", openAPIHandler(r))
 
 	// Serve Swagger UI
 	r.Get("/swagger/*", httpSwagger.Handler(
@@ -73,6 +74,9 @@ func NewRouter(JWTService jwt.Service, authHandler AuthHandler, companyhandler C
 		// Xendit webhook (public, signature verified)
 		r.Post("/webhook/xendit", subscriptionHandler.HandleWebhook)
 
+		// Leave iCal feed (public, authenticated by the feed token in the query string)
+		r.Get("/leave/requests/my.ics", leaveHandler.CalendarFeed)
+
 		r.Route("/auth", func(r chi.Router) {
 			r.Post("/register", authHandler.Register)
 			r.Post("/refresh", authHandler.RefreshToken)
@@ -80,15 +84,16 @@ func NewRouter(JWTService jwt.Service, authHandler AuthHandler, companyhandler C
 			r.Post("/forgot-password", authHandler.ForgotPassword)
 			r.Post("/reset-password", authHandler.ResetPassword)
 			r.Post("/verify-email", authHandler.VerifyEmail)
+			r.Post("/oauth/link", authHandler.ConfirmOAuthLink)
 			r.Route("/oauth/callback", func(r chi.Router) {
-				r.Get("/google", authHandler.OAuthCallbackGoogle)
+				r.Get("/{provider}", authHandler.OAuthCallback)
 			})
 
 			r.Route("/login", func(r chi.Router) {
 				r.Post("/", authHandler.Login)
 				r.Post("/employee-code", authHandler.LoginWithEmployeeCode)
 				r.Route("/oauth", func(r chi.Router) {
-					r.Get("/google", authHandler.LoginWithGoogle)
+					r.Get("/{provider}", authHandler.LoginWithOAuth)
 				})
 			})
 
@@ -100,361 +105,454 @@ func NewRouter(JWTService jwt.Service, authHandler AuthHandler, companyhandler C
 			r.Use(middleware.AuthRequired(JWTService.JWTAuth()))
 			r.Use(middleware.RequireCompany)
 
-			r.Route("/company", func(r chi.Router) {
+			// Subscription Routes - outside the access check so billing stays reachable in any state
+			r.Route("/subscription", func(r chi.Router) {
+				// Authenticated routes - view subscription and invoices
+				r.Get("/my", subscriptionHandler.GetMySubscription)
+				r.Get("/seats/usage", subscriptionHandler.GetSeatUsage)
+				r.Get("/invoices", subscriptionHandler.GetInvoices)
+				r.Get("/invoices/{id}", subscriptionHandler.GetInvoiceByID)
+				r.Get("/invoices/{id}/pdf", subscriptionHandler.DownloadInvoicePDF)
 
-				// Pending only
+				// Owner-only routes - manage subscription
 				r.Group(func(r chi.Router) {
-					r.Use(middleware.RequirePending)
-					r.Post("/", companyhandler.Create)
+					r.Use(middleware.RequireOwner)
+					r.Post("/checkout", subscriptionHandler.Checkout)
+					r.Post("/upgrade", subscriptionHandler.UpgradePlan)
+					r.Post("/downgrade", subscriptionHandler.DowngradePlan)
+					r.Post("/cancel", subscriptionHandler.CancelSubscription)
+					r.Post("/seats", subscriptionHandler.ChangeSeats)
+					r.Get("/seats/preview", subscriptionHandler.PreviewSeatChange)
+					r.Delete("/invoices/{id}", subscriptionHandler.CancelPendingInvoice)
 				})
+			})
 
-				r.Group(func(r chi.Router) {
-					r.Use(subscriptionMiddleware.RequireActiveSubscription)
-					r.Route("/my", func(r chi.Router) {
-						r.Get("/", companyhandler.GetByID)
+			// Notification Routes - not business data, available in any subscription state
+			r.Route("/notifications", func(r chi.Router) {
+				// Get SSE token (requires JWT auth)
+				r.Get("/token", notificationHandler.GetSSEToken)
 
-						// Owner only
-						r.Group(func(r chi.Router) {
-							r.Use(middleware.RequireOwner)
-							r.Put("/", companyhandler.Update)
-							r.Delete("/", companyhandler.Delete)
-							r.Post("/logo", companyhandler.UploadCompanyLogo)
-						})
-					})
-				})
+				// SSE Stream (uses SSE token from query param)
+				r.Get("/stream", notificationHandler.Stream)
+
+				// CRUD operations
+				r.Get("/", notificationHandler.List)
+				r.Get("/unread-count", notificationHandler.UnreadCount)
+				r.Post("/mark-read", notificationHandler.MarkAsRead)
+				r.Post("/mark-all-read", notificationHandler.MarkAllAsRead)
+				r.Delete("/{id}", notificationHandler.Delete)
+
+				// Preferences
+				r.Get("/preferences", notificationHandler.GetPreferences)
+				r.Put("/preferences", notificationHandler.UpdatePreference)
 			})
 
-			r.Route("/leave", func(r chi.Router) {
-				// Leave Types
-				r.Route("/types", func(r chi.Router) {
-					// Read operations - available to all subscriptions
-					r.Get("/", leaveHandler.ListTypes)
+			// Business routes - past_due companies are read-only, expired companies are blocked
+			r.Group(func(r chi.Router) {
+				r.Use(subscriptionMiddleware.EnforceSubscriptionAccess)
+				r.Use(branchScopeMiddleware.ResolveBranchScope)
+				r.Use(permissionMiddleware.ResolvePermissions)
+
+				r.Route("/company", func(r chi.Router) {
 
-					// Write operations - require leave feature
+					// Pending only
 					r.Group(func(r chi.Router) {
-						r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureLeave))
-						r.Use(middleware.RequireOwner)
-						r.Post("/", leaveHandler.CreateType)
-						r.Put("/{id}", leaveHandler.UpdateType)
-						r.Delete("/{id}", leaveHandler.DeleteType)
+						r.Use(middleware.RequirePending)
+						r.Post("/", companyhandler.Create)
 					})
-				})
 
-				// Leave Quota
-				r.Route("/quota", func(r chi.Router) {
-					// Read operations - available to all subscriptions
-					r.Get("/my", leaveHandler.GetMyQuota)
-					r.Get("/{id}", leaveHandler.GetQuota)
-
-					// Manager read + write operations - require leave feature
 					r.Group(func(r chi.Router) {
-						r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureLeave))
-						r.Use(middleware.RequireManager)
-						r.Get("/", leaveHandler.ListQuota)
-						r.Post("/adjust", leaveHandler.AdjustQuota)
+						r.Use(subscriptionMiddleware.RequireActiveSubscription)
+						r.Route("/my", func(r chi.Router) {
+							r.Get("/", companyhandler.GetByID)
+							r.Get("/calendar", companyhandler.GetCalendar)
+							r.With(middleware.RequirePermission(user.PermissionCompanyManage)).Post("/holidays", companyhandler.CreateHoliday)
+							r.With(middleware.RequirePermission(user.PermissionCompanyManage)).Delete("/holidays/{id}", companyhandler.DeleteHoliday)
+
+							// Owner only
+							r.Group(func(r chi.Router) {
+								r.Use(middleware.RequireOwner)
+								r.Put("/", companyhandler.Update)
+								r.Delete("/", companyhandler.Delete)
+								r.Post("/logo", companyhandler.UploadCompanyLogo)
+								r.Post("/leave-types/seed", companyhandler.SeedDefaultLeaveTypes)
+								r.Get("/onboarding", companyhandler.GetOnboardingStatus)
+							})
+
+							// Notification templates
+							r.Route("/notification-templates", func(r chi.Router) {
+								r.Use(middleware.RequirePermission(user.PermissionCompanyManage))
+								r.Get("/", notificationHandler.ListTemplates)
+								r.Put("/{type}", notificationHandler.UpsertTemplate)
+								r.Delete("/{type}", notificationHandler.DeleteTemplate)
+							})
+						})
 					})
 				})
 
-				// Leave Requests
-				r.Route("/requests", func(r chi.Router) {
-					// Read operations - available to all subscriptions
-					r.Get("/{id}", leaveHandler.GetRequest)
-					r.Get("/my", leaveHandler.GetMyRequests)
+				r.Route("/leave", func(r chi.Router) {
+					// Leave Types
+					r.Route("/types", func(r chi.Router) {
+						// Read operations - available to all subscriptions
+						r.Get("/", leaveHandler.ListTypes)
 
-					// Write operations - require leave feature
-					r.Group(func(r chi.Router) {
-						r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureLeave))
-						r.Post("/", leaveHandler.CreateRequest)
+						// Manager read operations - require leave feature
+						r.Group(func(r chi.Router) {
+							r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureLeave))
+							r.With(middleware.RequirePermission(user.PermissionLeaveViewAll)).Get("/{id}/quota-preview", leaveHandler.PreviewQuota)
+							r.With(middleware.RequirePermission(user.PermissionLeaveViewAll)).Get("/stats", leaveHandler.GetTypeStats)
+						})
 
-						// Manager operations
+						// Write operations - require leave feature
 						r.Group(func(r chi.Router) {
-							r.Use(middleware.RequireManager)
-							r.Get("/", leaveHandler.ListRequests)
-							r.Post("/{id}/approve", leaveHandler.ApproveRequest)
-							r.Post("/{id}/reject", leaveHandler.RejectRequest)
+							r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureLeave))
+							r.Use(middleware.RequireOwner)
+							r.Post("/", leaveHandler.CreateType)
+							r.Put("/{id}", leaveHandler.UpdateType)
+							r.Delete("/{id}", leaveHandler.DeleteType)
 						})
 					})
-				})
-			})
 
-			// Master Data Routes
-			r.Route("/master", func(r chi.Router) {
-				// Branch routes
-				r.Route("/branches", func(r chi.Router) {
-					r.Get("/", masterHandler.ListBranches)
-					r.Get("/{id}", masterHandler.GetBranch)
+					// Leave Quota
+					r.Route("/quota", func(r chi.Router) {
+						// Read operations - available to all subscriptions
+						r.Get("/my", leaveHandler.GetMyQuota)
+						r.Get("/{id}", leaveHandler.GetQuota)
 
-					// Owner/Manager only
-					r.Group(func(r chi.Router) {
-						r.Use(middleware.RequireManager)
-						r.Post("/", masterHandler.CreateBranch)
-						r.Put("/{id}", masterHandler.UpdateBranch)
-						r.Delete("/{id}", masterHandler.DeleteBranch)
+						// Manager read + write operations - require leave feature
+						r.Group(func(r chi.Router) {
+							r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureLeave))
+							r.With(middleware.RequirePermission(user.PermissionLeaveViewAll)).Get("/", leaveHandler.ListQuota)
+							r.With(middleware.RequirePermission(user.PermissionLeaveAdjustQuota)).Post("/adjust", leaveHandler.AdjustQuota)
+						})
 					})
-				})
 
-				// Grade routes
-				r.Route("/grades", func(r chi.Router) {
-					r.Get("/", masterHandler.ListGrades)
-					r.Get("/{id}", masterHandler.GetGrade)
+					// Leave Requests
+					r.Route("/requests", func(r chi.Router) {
+						// Read operations - available to all subscriptions
+						r.Get("/{id}", leaveHandler.GetRequest)
+						r.Get("/{id}/attachment", leaveHandler.DownloadAttachment)
+						r.Get("/my", leaveHandler.GetMyRequests)
+						r.Post("/my/calendar-token", leaveHandler.RegenerateCalendarFeedToken)
 
-					// Owner/Manager only
-					r.Group(func(r chi.Router) {
-						r.Use(middleware.RequireManager)
-						r.Post("/", masterHandler.CreateGrade)
-						r.Put("/{id}", masterHandler.UpdateGrade)
-						r.Delete("/{id}", masterHandler.DeleteGrade)
+						// Write operations - require leave feature
+						r.Group(func(r chi.Router) {
+							r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureLeave))
+							r.Post("/", leaveHandler.CreateRequest)
+							r.Post("/recurring", leaveHandler.CreateRecurringRequest)
+							r.Put("/{id}", leaveHandler.UpdateMyRequest)
+							r.Post("/{id}/reopen", leaveHandler.ReopenRequest)
+
+							// Manager operations
+							r.Group(func(r chi.Router) {
+								r.Use(middleware.RequireManager)
+								r.Get("/", leaveHandler.ListRequests)
+								r.Get("/export", leaveHandler.ExportRequests)
+								r.Get("/employee/{employeeID}", leaveHandler.ListEmployeeRequests)
+								r.Get("/pending/count", leaveHandler.CountPendingApprovals)
+								r.Post("/{id}/approve", leaveHandler.ApproveRequest)
+								r.Post("/{id}/reject", leaveHandler.RejectRequest)
+								r.Post("/recurring/{recurrenceID}/approve", leaveHandler.ApproveRecurringRequest)
+								r.Post("/recurring/{recurrenceID}/reject", leaveHandler.RejectRecurringRequest)
+							})
+
+							r.With(middleware.RequireOwner).Post("/{id}/recompute", leaveHandler.RecomputeRequest)
+						})
 					})
 				})
 
-				// Position routes
-				r.Route("/positions", func(r chi.Router) {
-					r.Get("/", masterHandler.ListPositions)
-					r.Get("/{id}", masterHandler.GetPosition)
+				// Master Data Routes
+				r.Route("/master", func(r chi.Router) {
+					// Branch routes
+					r.Route("/branches", func(r chi.Router) {
+						r.Get("/", masterHandler.ListBranches)
+						r.Get("/{id}", masterHandler.GetBranch)
 
-					// Owner/Manager only
-					r.Group(func(r chi.Router) {
-						r.Use(middleware.RequireManager)
-						r.Post("/", masterHandler.CreatePosition)
-						r.Put("/{id}", masterHandler.UpdatePosition)
-						r.Delete("/{id}", masterHandler.DeletePosition)
+						// Owner/Manager only
+						r.Group(func(r chi.Router) {
+							r.Use(middleware.RequireManager)
+							r.Post("/", masterHandler.CreateBranch)
+							r.Put("/{id}", masterHandler.UpdateBranch)
+							r.Delete("/{id}", masterHandler.DeleteBranch)
+						})
 					})
-				})
-			})
 
-			r.Route("/schedule", func(r chi.Router) {
-				// Read operations - available to all subscriptions (schedules are core system data)
-				r.Get("/", scheduleHandler.ListWorkSchedules)
-				r.Get("/{id}", scheduleHandler.GetWorkSchedule)
-				r.Get("/employee/{id}", scheduleHandler.GetEmployeeScheduleTimeline)
+					// Grade routes
+					r.Route("/grades", func(r chi.Router) {
+						r.Get("/", masterHandler.ListGrades)
+						r.Get("/{id}", masterHandler.GetGrade)
 
-				// Write operations - require schedule feature
-				r.Group(func(r chi.Router) {
-					r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureSchedule))
+						// Owner/Manager only
+						r.Group(func(r chi.Router) {
+							r.Use(middleware.RequireManager)
+							r.Post("/", masterHandler.CreateGrade)
+							r.Put("/{id}", masterHandler.UpdateGrade)
+							r.Delete("/{id}", masterHandler.DeleteGrade)
+						})
+					})
 
-					r.Post("/{scheduleID}/employee/{employeeID}", scheduleHandler.AssignSchedule)
-					r.Put("/{assignID}/employee/{employeeID}", scheduleHandler.UpdateEmployeeScheduleAssignment)
-					r.Delete("/{assignID}/employee/{employeeID}", scheduleHandler.DeleteEmployeeScheduleAssignment)
+					// Position routes
+					r.Route("/positions", func(r chi.Router) {
+						r.Get("/", masterHandler.ListPositions)
+						r.Get("/{id}", masterHandler.GetPosition)
 
-					r.Group(func(r chi.Router) {
-						r.Use(middleware.RequireOwner)
-						r.Post("/", scheduleHandler.CreateWorkSchedule)
-						r.Put("/{id}", scheduleHandler.UpdateWorkSchedule)
-						r.Delete("/{id}", scheduleHandler.DeleteWorkSchedule)
+						// Owner/Manager only
+						r.Group(func(r chi.Router) {
+							r.Use(middleware.RequireManager)
+							r.Post("/", masterHandler.CreatePosition)
+							r.Put("/{id}", masterHandler.UpdatePosition)
+							r.Delete("/{id}", masterHandler.DeletePosition)
+						})
 					})
 				})
 
-				// Work Schedule Times
-				r.Route("/times", func(r chi.Router) {
-					r.Get("/{id}", scheduleHandler.GetWorkScheduleTime) // Read - all subscriptions
+				r.Route("/schedule", func(r chi.Router) {
+					// Read operations - available to all subscriptions (schedules are core system data)
+					r.Get("/", scheduleHandler.ListWorkSchedules)
+					r.Get("/{id}", scheduleHandler.GetWorkSchedule)
+					r.Get("/{id}/week", scheduleHandler.GetWeeklyView)
+					r.Get("/employee/{id}", scheduleHandler.GetEmployeeScheduleTimeline)
+					r.With(middleware.RequireManager).Get("/employee/{id}/export", scheduleHandler.ExportEmployeeScheduleTimeline)
+
+					// Write operations - require schedule feature
 					r.Group(func(r chi.Router) {
 						r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureSchedule))
-						r.Use(middleware.RequireOwner)
-						r.Post("/", scheduleHandler.CreateWorkScheduleTime)
-						r.Put("/{id}", scheduleHandler.UpdateWorkScheduleTime)
-						r.Delete("/{id}", scheduleHandler.DeleteWorkScheduleTime)
+
+						r.Post("/{scheduleID}/employee/{employeeID}", scheduleHandler.AssignSchedule)
+						r.Put("/{assignID}/employee/{employeeID}", scheduleHandler.UpdateEmployeeScheduleAssignment)
+						r.Delete("/{assignID}/employee/{employeeID}", scheduleHandler.DeleteEmployeeScheduleAssignment)
+
+						r.Group(func(r chi.Router) {
+							r.Use(middleware.RequireOwner)
+							r.Post("/", scheduleHandler.CreateWorkSchedule)
+							r.Post("/{id}/clone", scheduleHandler.CloneWorkSchedule)
+							r.Put("/{id}", scheduleHandler.UpdateWorkSchedule)
+							r.Delete("/{id}", scheduleHandler.DeleteWorkSchedule)
+							r.Delete("/{id}/times", scheduleHandler.ClearScheduleTimes)
+							r.Delete("/{id}/locations", scheduleHandler.ClearScheduleLocations)
+						})
 					})
-				})
 
-				// Work Schedule Locations
-				r.Route("/locations", func(r chi.Router) {
-					r.Get("/{id}", scheduleHandler.GetWorkScheduleLocation) // Read - all subscriptions
-					r.Group(func(r chi.Router) {
-						r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureSchedule))
-						r.Post("/", scheduleHandler.CreateWorkScheduleLocation)
-						r.Put("/{id}", scheduleHandler.UpdateWorkScheduleLocation)
-						r.Delete("/{id}", scheduleHandler.DeleteWorkScheduleLocation)
+					// Work Schedule Times
+					r.Route("/times", func(r chi.Router) {
+						r.Get("/{id}", scheduleHandler.GetWorkScheduleTime) // Read - all subscriptions
+						r.Group(func(r chi.Router) {
+							r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureSchedule))
+							r.Use(middleware.RequireOwner)
+							r.Post("/", scheduleHandler.CreateWorkScheduleTime)
+							r.Put("/{id}", scheduleHandler.UpdateWorkScheduleTime)
+							r.Delete("/{id}", scheduleHandler.DeleteWorkScheduleTime)
+						})
 					})
-				})
 
-			})
+					// Work Schedule Locations
+					r.Route("/locations", func(r chi.Router) {
+						r.Get("/{id}", scheduleHandler.GetWorkScheduleLocation) // Read - all subscriptions
+						r.Group(func(r chi.Router) {
+							r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureSchedule))
+							r.Post("/", scheduleHandler.CreateWorkScheduleLocation)
+							r.Put("/{id}", scheduleHandler.UpdateWorkScheduleLocation)
+							r.Delete("/{id}", scheduleHandler.DeleteWorkScheduleLocation)
+						})
+					})
 
-			// Employee Schedule Assignments
-			r.Route("/employee-schedules", func(r chi.Router) {
-				// Read operations - available to all subscriptions
-				r.Get("/", scheduleHandler.ListEmployeeScheduleAssignments)
-				r.Get("/active", scheduleHandler.GetActiveScheduleForEmployee)
-				r.Get("/{id}", scheduleHandler.GetEmployeeScheduleAssignment)
+					// Shift Swaps - employees request, managers approve/reject
+					r.Route("/swaps", func(r chi.Router) {
+						r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureSchedule))
+						r.Get("/", scheduleHandler.ListShiftSwaps)
+						r.Post("/", scheduleHandler.RequestShiftSwap)
+						r.Group(func(r chi.Router) {
+							r.Use(middleware.RequireManager)
+							r.Post("/{id}/approve", scheduleHandler.ApproveShiftSwap)
+							r.Post("/{id}/reject", scheduleHandler.RejectShiftSwap)
+						})
+					})
 
-				// Write operations - require schedule feature
-				r.Group(func(r chi.Router) {
-					r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureSchedule))
-					r.Use(middleware.RequireManager)
-					r.Post("/", scheduleHandler.CreateEmployeeScheduleAssignment)
-					r.Put("/{id}", scheduleHandler.UpdateEmployeeScheduleAssignment)
-					r.Delete("/{id}", scheduleHandler.DeleteEmployeeScheduleAssignment)
 				})
-			})
-
-			// Attendance Routes
-			r.Route("/attendance", func(r chi.Router) {
-				// Read operations - available to all subscriptions
-				r.Get("/my", attendanceHandler.GetMyAttendance) // Get my attendance records
-				r.Get("/status", attendanceHandler.GetStatus)   // Get current attendance status
 
-				// Write operations - require attendance feature
-				r.Group(func(r chi.Router) {
-					r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureAttendance))
-					r.Post("/clock-in", attendanceHandler.ClockIn)   // Clock in
-					r.Post("/clock-out", attendanceHandler.ClockOut) // Clock out
+				// Employee Schedule Assignments
+				r.Route("/employee-schedules", func(r chi.Router) {
+					// Read operations - available to all subscriptions
+					r.Get("/", scheduleHandler.ListEmployeeScheduleAssignments)
+					r.Get("/active", scheduleHandler.GetActiveScheduleForEmployee)
+					r.Get("/my", scheduleHandler.GetMyScheduleRange)
+					r.Get("/{id}", scheduleHandler.GetEmployeeScheduleAssignment)
 
-					// Manager operations
+					// Write operations - require schedule feature
 					r.Group(func(r chi.Router) {
+						r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureSchedule))
 						r.Use(middleware.RequireManager)
-						r.Get("/", attendanceHandler.List)                 // All with filters
-						r.Get("/{id}", attendanceHandler.Get)              // Get single attendance
-						r.Put("/{id}", attendanceHandler.Update)           // Update attendance (fix records)
-						r.Delete("/{id}", attendanceHandler.Delete)        // Delete attendance
-						r.Post("/{id}/approve", attendanceHandler.Approve) // Approve attendance
-						r.Post("/{id}/reject", attendanceHandler.Reject)   // Reject attendance
+						r.Post("/", scheduleHandler.CreateEmployeeScheduleAssignment)
+						r.Put("/{id}", scheduleHandler.UpdateEmployeeScheduleAssignment)
+						r.Delete("/{id}", scheduleHandler.DeleteEmployeeScheduleAssignment)
 					})
 				})
-			})
-
-			r.Route("/employees", func(r chi.Router) {
-				r.Get("/{id}", employeeHandler.GetEmployee) // Get single employee
 
-				// Manager+ routes (requires invitation feature for creating employees)
-				r.Group(func(r chi.Router) {
-					r.Use(middleware.RequireManager)
-					r.Get("/", employeeHandler.ListEmployees)         // List employees with filters
-					r.Get("/search", employeeHandler.SearchEmployees) // Autocomplete search
+				// Attendance Routes
+				r.Route("/attendance", func(r chi.Router) {
+					// Read operations - available to all subscriptions
+					r.Get("/my", attendanceHandler.GetMyAttendance) // Get my attendance records
+					r.Get("/status", attendanceHandler.GetStatus)   // Get current attendance status
 
-					// Create employee requires invitation feature + employee slot check
+					// Write operations - require attendance feature
 					r.Group(func(r chi.Router) {
-						r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureInvitation))
-						r.Use(subscriptionMiddleware.RequireCanAddEmployee)
-						r.Post("/", employeeHandler.CreateEmployee) // Create employee (multipart)
-					})
+						r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureAttendance))
+						r.Post("/clock-in", attendanceHandler.ClockIn)   // Clock in
+						r.Post("/clock-out", attendanceHandler.ClockOut) // Clock out
 
-					r.Put("/{id}", employeeHandler.UpdateEmployee)                      // Update employee
-					r.Delete("/{id}", employeeHandler.DeleteEmployee)                   // Soft delete employee
-					r.Post("/{id}/inactivate", employeeHandler.InactivateEmployee)      // Inactivate employee
-					r.Post("/{id}/invitation/resend", employeeHandler.ResendInvitation) // Resend invitation
-					r.Post("/{id}/invitation/revoke", employeeHandler.RevokeInvitation) // Revoke invitation
-				})
-
-				r.Post("/{id}/avatar", employeeHandler.UploadAvatar) // Upload avatar
-			})
+						// Manager operations
+						r.Group(func(r chi.Router) {
+							r.Use(middleware.RequireManager)
+							r.Get("/", attendanceHandler.List)                 // All with filters
+							r.Get("/live-board", attendanceHandler.LiveBoard)  // Who is clocked in today
+							r.Post("/import", attendanceHandler.Import)        // Import historical records from CSV
+							r.Get("/{id}", attendanceHandler.Get)              // Get single attendance
+							r.Put("/{id}", attendanceHandler.Update)           // Update attendance (fix records)
+							r.Delete("/{id}", attendanceHandler.Delete)        // Delete attendance
+							r.Post("/{id}/approve", attendanceHandler.Approve) // Approve attendance
+							r.Post("/{id}/reject", attendanceHandler.Reject)   // Reject attendance
+						})
 
-			// Invitation Routes
-			r.Route("/invitations", func(r chi.Router) {
-				r.Get("/my", invitationHandler.ListMyInvitations)             // List pending invitations for current user
-				r.Post("/{token}/accept", invitationHandler.AcceptInvitation) // Accept invitation
-			})
+						// Company attendance settings (rounding rules)
+						r.With(middleware.RequireManager).Get("/settings", attendanceHandler.GetSettings)
+						r.With(middleware.RequirePermission(user.PermissionCompanyManage)).Put("/settings", attendanceHandler.UpdateSettings)
+					})
+				})
 
-			// Payroll Routes
-			r.Route("/payroll", func(r chi.Router) {
-				r.Use(middleware.RequireManager)
+				r.Route("/employees", func(r chi.Router) {
+					r.Get("/{id}", employeeHandler.GetEmployee)               // Get single employee
+					r.Get("/{id}/profile", employeeHandler.GetProfile)        // Employee with master data names, manager and tenure
+					r.Get("/{id}/export", employeeHandler.ExportEmployeeData) // Personal data export; the employee themselves or user.manage
+					r.Put("/{id}", employeeHandler.UpdateEmployee)            // Manager+, or an employee updating their own personal, bank and emergency contact details
 
-				// Read operations - available to all subscriptions
-				r.Get("/settings", payrollHandler.GetSettings)
-				r.Get("/components", payrollHandler.ListComponents)
-				r.Get("/components/{id}", payrollHandler.GetComponent)
-				r.Get("/employees/{employeeId}/components", payrollHandler.GetEmployeeComponents)
-				r.Get("/records", payrollHandler.ListPayrollRecords)
-				r.Get("/records/{id}", payrollHandler.GetPayrollRecord)
-				r.Get("/summary", payrollHandler.GetPayrollSummary)
+					// Manager+ routes (requires invitation feature for creating employees)
+					r.Group(func(r chi.Router) {
+						r.Use(middleware.RequireManager)
+						r.Get("/", employeeHandler.ListEmployees)         // List employees with filters
+						r.Get("/search", employeeHandler.SearchEmployees) // Autocomplete search
 
-				// Write operations - require payroll feature
-				r.Group(func(r chi.Router) {
-					r.Use(subscriptionMiddleware.RequireFeature(middleware.FeaturePayroll))
+						// Create employee requires invitation feature + employee slot check
+						r.Group(func(r chi.Router) {
+							r.Use(subscriptionMiddleware.RequireFeature(middleware.FeatureInvitation))
+							r.Use(subscriptionMiddleware.RequireCanAddEmployee)
+							r.Post("/", employeeHandler.CreateEmployee) // Create employee (multipart)
+						})
 
-					// Settings (Owner only)
-					r.Group(func(r chi.Router) {
-						r.Use(middleware.RequireOwner)
-						r.Put("/settings", payrollHandler.UpdateSettings)
+						r.Delete("/{id}", employeeHandler.DeleteEmployee)                   // Soft delete employee
+						r.Post("/{id}/inactivate", employeeHandler.InactivateEmployee)      // Inactivate employee
+						r.Post("/{id}/invitation/resend", employeeHandler.ResendInvitation) // Resend invitation
+						r.Post("/{id}/invitation/revoke", employeeHandler.RevokeInvitation) // Revoke invitation
 					})
 
-					// Components (Owner only)
-					r.Group(func(r chi.Router) {
-						r.Use(middleware.RequireOwner)
-						r.Post("/components", payrollHandler.CreateComponent)
-						r.Put("/components/{id}", payrollHandler.UpdateComponent)
-						r.Delete("/components/{id}", payrollHandler.DeleteComponent)
-					})
+					// Owner only - restrict a manager to a single branch
+					r.With(middleware.RequireOwner).Put("/{id}/branch-scope", employeeHandler.SetBranchScope)
 
-					// Employee Components
-					r.Post("/employees/{employeeId}/components", payrollHandler.AssignComponent)
-					r.Put("/employee-components/{id}", payrollHandler.UpdateEmployeeComponent)
-					r.Delete("/employee-components/{id}", payrollHandler.RemoveEmployeeComponent)
+					// Attach a company role (extra permissions) to an employee's account
+					r.With(middleware.RequirePermission(user.PermissionUserManage)).Put("/{id}/company-role", roleHandler.AssignRole)
 
-					// Payroll Records
-					r.Post("/generate", payrollHandler.GeneratePayroll)
-					r.Put("/records/{id}", payrollHandler.UpdatePayrollRecord)
-					r.Group(func(r chi.Router) {
-						r.Use(middleware.RequireOwner)
-						r.Delete("/records/{id}", payrollHandler.DeletePayrollRecord)
-						r.Post("/finalize", payrollHandler.FinalizePayroll)
-					})
-				})
-			})
+					// Overwrite a departed employee's personal data, keeping their history
+					r.With(middleware.RequirePermission(user.PermissionUserManage)).Post("/{id}/anonymize", employeeHandler.AnonymizeEmployee)
 
-			// Dashboard Routes (Manager+)
-			r.Route("/dashboard", func(r chi.Router) {
-				r.Route("/admin", func(r chi.Router) {
-					r.Use(middleware.RequireManager)
-					r.Get("/", dashboardHandler.GetDashboard)
-					r.Get("/employee-current-number", dashboardHandler.GetEmployeeCurrentNumber)
-					r.Get("/employee-status-stats", dashboardHandler.GetEmployeeStatusStats)
-					r.Get("/monthly-attendance", dashboardHandler.GetMonthlyAttendance)
-					r.Get("/daily-attendance-stats", dashboardHandler.GetDailyAttendanceStats)
+					r.Post("/{id}/avatar", employeeHandler.UploadAvatar) // Upload avatar
 				})
-				r.Route("/employee", func(r chi.Router) {
-					r.Get("/", employeeDashboardHandler.GetDashboard)
-					r.Get("/work-stats", employeeDashboardHandler.GetWorkStats)
-					r.Get("/attendance-summary", employeeDashboardHandler.GetAttendanceSummary)
-					r.Get("/leave-summary", employeeDashboardHandler.GetLeaveSummary)
-					r.Get("/work-hours-chart", employeeDashboardHandler.GetWorkHoursChart)
+
+				// Invitation Routes
+				r.Route("/invitations", func(r chi.Router) {
+					r.Get("/my", invitationHandler.ListMyInvitations)             // List pending invitations for current user
+					r.Post("/{token}/accept", invitationHandler.AcceptInvitation) // Accept invitation
 				})
-			})
 
-			// Notification Routes
-			r.Route("/notifications", func(r chi.Router) {
-				// Get SSE token (requires JWT auth)
-				r.Get("/token", notificationHandler.GetSSEToken)
+				// Payroll Routes - permission based so a company role can grant payroll access
+				r.Route("/payroll", func(r chi.Router) {
+					r.Use(middleware.RequirePermission(user.PermissionPayrollView))
 
-				// SSE Stream (uses SSE token from query param)
-				r.Get("/stream", notificationHandler.Stream)
+					// Read operations - available to all subscriptions
+					r.Get("/settings", payrollHandler.GetSettings)
+					r.Get("/components", payrollHandler.ListComponents)
+					r.Get("/components/{id}", payrollHandler.GetComponent)
+					r.Get("/employees/{employeeId}/components", payrollHandler.GetEmployeeComponents)
+					r.Get("/records", payrollHandler.ListPayrollRecords)
+					r.Get("/records/{id}", payrollHandler.GetPayrollRecord)
+					r.Get("/summary", payrollHandler.GetPayrollSummary)
+					r.Get("/runs", payrollHandler.ListPayrollRuns)
+					r.Get("/runs/{id}", payrollHandler.GetPayrollRun)
+
+					// Write operations - require payroll feature
+					r.Group(func(r chi.Router) {
+						r.Use(subscriptionMiddleware.RequireFeature(middleware.FeaturePayroll))
 
-				// CRUD operations
-				r.Get("/", notificationHandler.List)
-				r.Get("/unread-count", notificationHandler.UnreadCount)
-				r.Post("/mark-read", notificationHandler.MarkAsRead)
-				r.Post("/mark-all-read", notificationHandler.MarkAllAsRead)
-				r.Delete("/{id}", notificationHandler.Delete)
+						// Settings and components (Owner by default)
+						r.Group(func(r chi.Router) {
+							r.Use(middleware.RequirePermission(user.PermissionPayrollConfigure))
+							r.Put("/settings", payrollHandler.UpdateSettings)
+							r.Post("/components", payrollHandler.CreateComponent)
+							r.Put("/components/{id}", payrollHandler.UpdateComponent)
+							r.Delete("/components/{id}", payrollHandler.DeleteComponent)
+						})
 
-				// Preferences
-				r.Get("/preferences", notificationHandler.GetPreferences)
-				r.Put("/preferences", notificationHandler.UpdatePreference)
-			})
+						// Employee components and payroll records (Manager+ by default)
+						r.Group(func(r chi.Router) {
+							r.Use(middleware.RequirePermission(user.PermissionPayrollProcess))
+							r.Post("/employees/{employeeId}/components", payrollHandler.AssignComponent)
+							r.Put("/employee-components/{id}", payrollHandler.UpdateEmployeeComponent)
+							r.Delete("/employee-components/{id}", payrollHandler.RemoveEmployeeComponent)
+							r.Post("/generate", payrollHandler.GeneratePayroll)
+							r.Put("/records/{id}", payrollHandler.UpdatePayrollRecord)
+							r.Post("/records/{id}/recalculate", payrollHandler.RecalculatePayrollRecord)
+						})
 
-			// Report Routes (Manager+) - Read-only, available to all subscriptions
-			r.Route("/reports", func(r chi.Router) {
-				r.Use(middleware.RequireManager)
+						// Finalize and delete records (Owner by default)
+						r.Group(func(r chi.Router) {
+							r.Use(middleware.RequirePermission(user.PermissionPayrollFinalize))
+							r.Delete("/records/{id}", payrollHandler.DeletePayrollRecord)
+							r.Post("/finalize", payrollHandler.FinalizePayroll)
+							r.Get("/bank-file", payrollHandler.ExportBankFile)
+							r.Get("/bank-file/summary", payrollHandler.GetBankFileSummary)
+						})
+					})
+				})
 
-				r.Get("/attendance", reportHandler.GetMonthlyAttendanceReport)
-				r.Get("/payroll", reportHandler.GetPayrollSummaryReport)
-				r.Get("/leave-balance", reportHandler.GetLeaveBalanceReport)
-				r.Get("/new-hires", reportHandler.GetNewHireReport)
-			})
+				// Role & Permission Routes (Owner by default)
+				r.Route("/roles", func(r chi.Router) {
+					r.Use(middleware.RequirePermission(user.PermissionUserManage))
 
-			// Subscription Routes
-			r.Route("/subscription", func(r chi.Router) {
-				// Authenticated routes - view subscription and invoices
-				r.Get("/my", subscriptionHandler.GetMySubscription)
-				r.Get("/invoices", subscriptionHandler.GetInvoices)
-				r.Get("/invoices/{id}", subscriptionHandler.GetInvoiceByID)
+					r.Get("/permissions", roleHandler.ListPermissions)
+					r.Get("/", roleHandler.ListRoles)
+					r.Get("/{id}", roleHandler.GetRole)
+					r.Post("/", roleHandler.CreateRole)
+					r.Put("/{id}", roleHandler.UpdateRole)
+					r.Delete("/{id}", roleHandler.DeleteRole)
+				})
 
-				// Owner-only routes - manage subscription
-				r.Group(func(r chi.Router) {
-					r.Use(middleware.RequireOwner)
-					r.Post("/checkout", subscriptionHandler.Checkout)
-					r.Post("/upgrade", subscriptionHandler.UpgradePlan)
-					r.Post("/downgrade", subscriptionHandler.DowngradePlan)
-					r.Post("/cancel", subscriptionHandler.CancelSubscription)
-					r.Post("/seats", subscriptionHandler.ChangeSeats)
-					r.Delete("/invoices/{id}", subscriptionHandler.CancelPendingInvoice)
+				// Dashboard Routes (Manager+)
+				r.Route("/dashboard", func(r chi.Router) {
+					r.Route("/admin", func(r chi.Router) {
+						r.Use(middleware.RequireManager)
+						r.Get("/", dashboardHandler.GetDashboard)
+						r.Get("/employee-current-number", dashboardHandler.GetEmployeeCurrentNumber)
+						r.Get("/employee-status-stats", dashboardHandler.GetEmployeeStatusStats)
+						r.Get("/monthly-attendance", dashboardHandler.GetMonthlyAttendance)
+						r.Get("/daily-attendance-stats", dashboardHandler.GetDailyAttendanceStats)
+						r.Get("/headcount-analytics", dashboardHandler.GetHeadcountAnalytics)
+					})
+					r.Route("/employee", func(r chi.Router) {
+						r.Get("/", employeeDashboardHandler.GetDashboard)
+						r.Get("/work-stats", employeeDashboardHandler.GetWorkStats)
+						r.Get("/attendance-summary", employeeDashboardHandler.GetAttendanceSummary)
+						r.Get("/attendance-recap", employeeDashboardHandler.GetMyAttendanceRecap)
+						r.Get("/leave-summary", employeeDashboardHandler.GetLeaveSummary)
+						r.Get("/work-hours-chart", employeeDashboardHandler.GetWorkHoursChart)
+					})
+				})
+
+				// Report Routes (Manager+) - Read-only, available to all subscriptions
+				r.Route("/reports", func(r chi.Router) {
+					r.Use(middleware.RequireManager)
+
+					r.Get("/attendance", reportHandler.GetMonthlyAttendanceReport)
+					r.Get("/payroll", reportHandler.GetPayrollSummaryReport)
+					r.Get("/leave-balance", reportHandler.GetLeaveBalanceReport)
+					r.Get("/new-hires", reportHandler.GetNewHireReport)
 				})
 			})
 
@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,12 +10,15 @@ import (
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
 	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
 )
 
 type ScheduleHandler interface {
 	// Work Schedule
 	CreateWorkSchedule(w http.ResponseWriter, r *http.Request)
+	CloneWorkSchedule(w http.ResponseWriter, r *http.Request)
 	GetWorkSchedule(w http.ResponseWriter, r *http.Request)
+	GetWeeklyView(w http.ResponseWriter, r *http.Request)
 	ListWorkSchedules(w http.ResponseWriter, r *http.Request)
 	UpdateWorkSchedule(w http.ResponseWriter, r *http.Request)
 	DeleteWorkSchedule(w http.ResponseWriter, r *http.Request)
@@ -24,12 +28,14 @@ type ScheduleHandler interface {
 	GetWorkScheduleTime(w http.ResponseWriter, r *http.Request)
 	UpdateWorkScheduleTime(w http.ResponseWriter, r *http.Request)
 	DeleteWorkScheduleTime(w http.ResponseWriter, r *http.Request)
+	ClearScheduleTimes(w http.ResponseWriter, r *http.Request)
 
 	// Work Schedule Location
 	CreateWorkScheduleLocation(w http.ResponseWriter, r *http.Request)
 	GetWorkScheduleLocation(w http.ResponseWriter, r *http.Request)
 	UpdateWorkScheduleLocation(w http.ResponseWriter, r *http.Request)
 	DeleteWorkScheduleLocation(w http.ResponseWriter, r *http.Request)
+	ClearScheduleLocations(w http.ResponseWriter, r *http.Request)
 
 	// Employee Schedule Assignment
 	CreateEmployeeScheduleAssignment(w http.ResponseWriter, r *http.Request)
@@ -38,10 +44,18 @@ type ScheduleHandler interface {
 	UpdateEmployeeScheduleAssignment(w http.ResponseWriter, r *http.Request)
 	DeleteEmployeeScheduleAssignment(w http.ResponseWriter, r *http.Request)
 	GetActiveScheduleForEmployee(w http.ResponseWriter, r *http.Request)
+	GetMyScheduleRange(w http.ResponseWriter, r *http.Request)
 
 	// Employee Schedule Timeline
 	GetEmployeeScheduleTimeline(w http.ResponseWriter, r *http.Request)
+	ExportEmployeeScheduleTimeline(w http.ResponseWriter, r *http.Request)
 	AssignSchedule(w http.ResponseWriter, r *http.Request)
+
+	// Shift Swap
+	RequestShiftSwap(w http.ResponseWriter, r *http.Request)
+	ListShiftSwaps(w http.ResponseWriter, r *http.Request)
+	ApproveShiftSwap(w http.ResponseWriter, r *http.Request)
+	RejectShiftSwap(w http.ResponseWriter, r *http.Request)
 }
 
 type scheduleHandlerImpl struct {
@@ -94,6 +108,26 @@ func (h *scheduleHandlerImpl) CreateWorkSchedule(w http.ResponseWriter, r *http.
 	response.Created(w, "Work schedule created successfully", result)
 }
 
+// CloneWorkSchedule copies a work schedule with its times and locations under a new name
+// POST /api/v1/schedule/{id}/clone
+func (h *scheduleHandlerImpl) CloneWorkSchedule(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req schedule.CloneWorkScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	result, err := h.scheduleService.CloneWorkSchedule(r.Context(), id, req.Name)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Created(w, "Work schedule cloned successfully", result)
+}
+
 func (h *scheduleHandlerImpl) GetWorkSchedule(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
@@ -106,6 +140,18 @@ func (h *scheduleHandlerImpl) GetWorkSchedule(w http.ResponseWriter, r *http.Req
 	response.Success(w, result)
 }
 
+func (h *scheduleHandlerImpl) GetWeeklyView(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	result, err := h.scheduleService.GetWeeklyView(r.Context(), id)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
 func (h *scheduleHandlerImpl) ListWorkSchedules(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -254,6 +300,20 @@ func (h *scheduleHandlerImpl) DeleteWorkScheduleTime(w http.ResponseWriter, r *h
 	response.SuccessWithMessage(w, "Work schedule time deleted successfully", nil)
 }
 
+// ClearScheduleTimes deletes all times of a schedule; force=true clears a schedule that is still assigned
+func (h *scheduleHandlerImpl) ClearScheduleTimes(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	force := r.URL.Query().Get("force") == "true"
+
+	result, err := h.scheduleService.ClearScheduleTimes(r.Context(), id, force)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Work schedule times cleared successfully", result)
+}
+
 // ==================== WORK SCHEDULE LOCATION HANDLERS ====================
 
 func (h *scheduleHandlerImpl) CreateWorkScheduleLocation(w http.ResponseWriter, r *http.Request) {
@@ -315,6 +375,20 @@ func (h *scheduleHandlerImpl) DeleteWorkScheduleLocation(w http.ResponseWriter,
 	response.SuccessWithMessage(w, "Work schedule location deleted successfully", nil)
 }
 
+// ClearScheduleLocations deletes all locations of a schedule; force=true clears a schedule that is still assigned
+func (h *scheduleHandlerImpl) ClearScheduleLocations(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	force := r.URL.Query().Get("force") == "true"
+
+	result, err := h.scheduleService.ClearScheduleLocations(r.Context(), id, force)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Work schedule locations cleared successfully", result)
+}
+
 // ==================== EMPLOYEE SCHEDULE ASSIGNMENT HANDLERS ====================
 
 func (h *scheduleHandlerImpl) CreateEmployeeScheduleAssignment(w http.ResponseWriter, r *http.Request) {
@@ -424,6 +498,31 @@ func (h *scheduleHandlerImpl) GetActiveScheduleForEmployee(w http.ResponseWriter
 	response.Success(w, result)
 }
 
+// GetMyScheduleRange returns the caller's effective schedule for each day of a range
+// GET /api/v1/employee-schedules/my?start_date=YYYY-MM-DD&end_date=YYYY-MM-DD
+func (h *scheduleHandlerImpl) GetMyScheduleRange(w http.ResponseWriter, r *http.Request) {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		response.Unauthorized(w, "Failed to extract claims from context")
+		return
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok || userID == "" {
+		response.Unauthorized(w, "user_id claim is missing or invalid")
+		return
+	}
+
+	query := r.URL.Query()
+	result, err := h.scheduleService.GetMyScheduleRange(r.Context(), userID, query.Get("start_date"), query.Get("end_date"))
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
 // ==================== EMPLOYEE SCHEDULE TIMELINE HANDLERS ====================
 
 func (h *scheduleHandlerImpl) GetEmployeeScheduleTimeline(w http.ResponseWriter, r *http.Request) {
@@ -461,3 +560,108 @@ func (h *scheduleHandlerImpl) GetEmployeeScheduleTimeline(w http.ResponseWriter,
 
 	response.Success(w, result)
 }
+
+// ExportEmployeeScheduleTimeline downloads an employee's full schedule history
+// GET /api/v1/schedule/employee/{id}/export?format=csv
+func (h *scheduleHandlerImpl) ExportEmployeeScheduleTimeline(w http.ResponseWriter, r *http.Request) {
+	employeeID := chi.URLParam(r, "id")
+	format := r.URL.Query().Get("format")
+
+	data, err := h.scheduleService.ExportEmployeeTimeline(r.Context(), employeeID, format)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.File(w, "text/csv", fmt.Sprintf("schedule-timeline-%s.csv", employeeID), data)
+}
+
+// ==================== SHIFT SWAP HANDLERS ====================
+
+// RequestShiftSwap asks to swap the caller's schedule with another employee for one date
+// POST /api/v1/schedule/swaps
+func (h *scheduleHandlerImpl) RequestShiftSwap(w http.ResponseWriter, r *http.Request) {
+	_, claims, _ := jwtauth.FromContext(r.Context())
+	employeeID, ok := claims["employee_id"].(string)
+	if !ok || employeeID == "" {
+		response.Forbidden(w, "Only employees can request a shift swap")
+		return
+	}
+
+	var req schedule.CreateShiftSwapRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	date, _ := time.Parse("2006-01-02", req.Date)
+
+	result, err := h.scheduleService.RequestShiftSwap(r.Context(), employeeID, req.ToEmployeeID, date)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Created(w, "Shift swap requested successfully", result)
+}
+
+// ListShiftSwaps lists shift swap requests, optionally filtered by status and employee
+// GET /api/v1/schedule/swaps
+func (h *scheduleHandlerImpl) ListShiftSwaps(w http.ResponseWriter, r *http.Request) {
+	var filter schedule.ShiftSwapFilter
+	if status := r.URL.Query().Get("status"); status != "" {
+		filter.Status = &status
+	}
+	if employeeID := r.URL.Query().Get("employee_id"); employeeID != "" {
+		filter.EmployeeID = &employeeID
+	}
+
+	result, err := h.scheduleService.ListShiftSwaps(r.Context(), filter)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
+// ApproveShiftSwap approves a pending swap and applies it to both employees' schedules
+// POST /api/v1/schedule/swaps/{id}/approve
+func (h *scheduleHandlerImpl) ApproveShiftSwap(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	result, err := h.scheduleService.ApproveShiftSwap(r.Context(), id)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Shift swap approved successfully", result)
+}
+
+// RejectShiftSwap rejects a pending swap
+// POST /api/v1/schedule/swaps/{id}/reject
+func (h *scheduleHandlerImpl) RejectShiftSwap(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req schedule.RejectShiftSwapRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.HandleError(w, err)
+			return
+		}
+	}
+
+	result, err := h.scheduleService.RejectShiftSwap(r.Context(), id, req.Reason)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.SuccessWithMessage(w, "Shift swap rejected successfully", result)
+}
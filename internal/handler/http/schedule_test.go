@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/testutil"
+	"github.com/go-chi/chi/v5"
+)
+
+// Clearing a schedule is owner-only, and the router is the one place that enforces it
+func TestClearScheduleRoutesRequireOwner(t *testing.T) {
+	router := newDocumentedRouter(t)
+
+	chains := map[string][]func(http.Handler) http.Handler{}
+	err := chi.Walk(router, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		chains[method+" "+route] = middlewares
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chi.Walk() error = %v", err)
+	}
+
+	for _, route := range []string{"DELETE /api/v1/schedule/{id}/times", "DELETE /api/v1/schedule/{id}/locations"} {
+		t.Run(route, func(t *testing.T) {
+			middlewares, ok := chains[route]
+			if !ok {
+				t.Fatalf("%s is not registered", route)
+			}
+			var owner func(http.Handler) http.Handler
+			for _, mw := range middlewares {
+				if strings.HasSuffix(runtime.FuncForPC(reflect.ValueOf(mw).Pointer()).Name(), ".RequireOwner") {
+					owner = mw
+				}
+			}
+			if owner == nil {
+				t.Fatalf("%s is not behind RequireOwner", route)
+			}
+
+			served := false
+			handler := owner(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { served = true }))
+			ctx := testutil.ClaimsContext(t, map[string]any{"company_id": "company-1", "role": string(user.RoleEmployee)})
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/?force=true", nil).WithContext(ctx))
+
+			if served || rec.Code != http.StatusForbidden {
+				t.Errorf("employee got status %d (handler reached: %t), want %d", rec.Code, served, http.StatusForbidden)
+			}
+		})
+	}
+}
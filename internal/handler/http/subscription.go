@@ -2,12 +2,14 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
 	"github.com/cmlabs-hris/hris-backend-go/internal/handler/http/response"
-	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/xendit"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth/v5"
 )
@@ -20,8 +22,10 @@ type SubscriptionHandler interface {
 
 	// Authenticated endpoints
 	GetMySubscription(w http.ResponseWriter, r *http.Request)
+	GetSeatUsage(w http.ResponseWriter, r *http.Request)
 	GetInvoices(w http.ResponseWriter, r *http.Request)
 	GetInvoiceByID(w http.ResponseWriter, r *http.Request)
+	DownloadInvoicePDF(w http.ResponseWriter, r *http.Request)
 
 	// Owner-only endpoints
 	Checkout(w http.ResponseWriter, r *http.Request)
@@ -29,22 +33,23 @@ type SubscriptionHandler interface {
 	DowngradePlan(w http.ResponseWriter, r *http.Request)
 	CancelSubscription(w http.ResponseWriter, r *http.Request)
 	ChangeSeats(w http.ResponseWriter, r *http.Request)
+	PreviewSeatChange(w http.ResponseWriter, r *http.Request)
 	CancelPendingInvoice(w http.ResponseWriter, r *http.Request)
 }
 
 type subscriptionHandlerImpl struct {
 	subscriptionService subscription.SubscriptionService
-	webhookVerifier     *xendit.WebhookVerifier
+	paymentProvider     subscription.PaymentProvider
 }
 
 // NewSubscriptionHandler creates a new subscription handler
 func NewSubscriptionHandler(
 	subscriptionService subscription.SubscriptionService,
-	webhookVerifier *xendit.WebhookVerifier,
+	paymentProvider subscription.PaymentProvider,
 ) SubscriptionHandler {
 	return &subscriptionHandlerImpl{
 		subscriptionService: subscriptionService,
-		webhookVerifier:     webhookVerifier,
+		paymentProvider:     paymentProvider,
 	}
 }
 
@@ -78,8 +83,26 @@ func (h *subscriptionHandlerImpl) GetMySubscription(w http.ResponseWriter, r *ht
 	response.Success(w, sub)
 }
 
-// GetInvoices retrieves all invoices for the current company
-// GET /api/v1/subscription/invoices - Authenticated
+// GetSeatUsage breaks the seats in use down into active employees and pending invitations
+// GET /api/v1/subscription/seats/usage - Authenticated
+func (h *subscriptionHandlerImpl) GetSeatUsage(w http.ResponseWriter, r *http.Request) {
+	companyID, ok := getCompanyIDFromContext(r)
+	if !ok {
+		response.Forbidden(w, "no company associated with this user")
+		return
+	}
+
+	usage, err := h.subscriptionService.GetSeatUsageBreakdown(r.Context(), companyID)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, usage)
+}
+
+// GetInvoices retrieves a filtered, paginated list of invoices for the current company
+// GET /api/v1/subscription/invoices?status=&billing_cycle=&start_date=&end_date=&page=&limit= - Authenticated
 func (h *subscriptionHandlerImpl) GetInvoices(w http.ResponseWriter, r *http.Request) {
 	companyID, ok := getCompanyIDFromContext(r)
 	if !ok {
@@ -87,7 +110,38 @@ func (h *subscriptionHandlerImpl) GetInvoices(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	invoices, err := h.subscriptionService.GetInvoices(r.Context(), companyID)
+	// Parse query parameters
+	filter := subscription.InvoiceFilter{}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		filter.Status = &status
+	}
+
+	if billingCycle := r.URL.Query().Get("billing_cycle"); billingCycle != "" {
+		filter.BillingCycle = &billingCycle
+	}
+
+	// Issue date range
+	if startDate := r.URL.Query().Get("start_date"); startDate != "" {
+		filter.StartDate = &startDate
+	}
+	if endDate := r.URL.Query().Get("end_date"); endDate != "" {
+		filter.EndDate = &endDate
+	}
+
+	// Pagination
+	if page := r.URL.Query().Get("page"); page != "" {
+		if p, err := strconv.Atoi(page); err == nil {
+			filter.Page = p
+		}
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil {
+			filter.Limit = l
+		}
+	}
+
+	invoices, err := h.subscriptionService.GetInvoices(r.Context(), companyID, filter)
 	if err != nil {
 		response.HandleError(w, err)
 		return
@@ -120,6 +174,30 @@ func (h *subscriptionHandlerImpl) GetInvoiceByID(w http.ResponseWriter, r *http.
 	response.Success(w, invoice)
 }
 
+// DownloadInvoicePDF returns a printable PDF of a specific invoice
+// GET /api/v1/subscription/invoices/{id}/pdf - Authenticated
+func (h *subscriptionHandlerImpl) DownloadInvoicePDF(w http.ResponseWriter, r *http.Request) {
+	companyID, ok := getCompanyIDFromContext(r)
+	if !ok {
+		response.Forbidden(w, "no company associated with this user")
+		return
+	}
+
+	invoiceID := chi.URLParam(r, "id")
+	if invoiceID == "" {
+		response.BadRequest(w, "invoice ID is required", nil)
+		return
+	}
+
+	pdf, err := h.subscriptionService.GenerateInvoicePDF(r.Context(), companyID, invoiceID)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.File(w, "application/pdf", fmt.Sprintf("invoice-%s.pdf", invoiceID), pdf)
+}
+
 // Checkout creates a new subscription invoice
 // POST /api/v1/subscription/checkout - Owner only
 func (h *subscriptionHandlerImpl) Checkout(w http.ResponseWriter, r *http.Request) {
@@ -264,6 +342,30 @@ func (h *subscriptionHandlerImpl) ChangeSeats(w http.ResponseWriter, r *http.Req
 	response.Success(w, result)
 }
 
+// PreviewSeatChange shows the charge or schedule of a seat change before it is confirmed
+// GET /api/v1/subscription/seats/preview?seat_count=N - Owner only
+func (h *subscriptionHandlerImpl) PreviewSeatChange(w http.ResponseWriter, r *http.Request) {
+	companyID, ok := getCompanyIDFromContext(r)
+	if !ok {
+		response.Forbidden(w, "no company associated with this user")
+		return
+	}
+
+	seatCount, err := strconv.Atoi(r.URL.Query().Get("seat_count"))
+	if err != nil || seatCount < 1 {
+		response.BadRequest(w, "seat_count must be a positive integer", nil)
+		return
+	}
+
+	result, err := h.subscriptionService.PreviewSeatChange(r.Context(), companyID, seatCount)
+	if err != nil {
+		response.HandleError(w, err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
 // CancelPendingInvoice cancels a pending invoice
 // DELETE /api/v1/subscription/invoices/{id} - Requires owner role
 func (h *subscriptionHandlerImpl) CancelPendingInvoice(w http.ResponseWriter, r *http.Request) {
@@ -290,8 +392,8 @@ func (h *subscriptionHandlerImpl) CancelPendingInvoice(w http.ResponseWriter, r
 	})
 }
 
-// HandleWebhook processes Xendit webhook callbacks
-// POST /api/v1/webhook/xendit - Public (signature verified)
+// HandleWebhook processes payment provider webhook callbacks
+// POST /api/v1/webhook/xendit - Public (signature verified by the provider)
 func (h *subscriptionHandlerImpl) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 	// Read the raw body for signature verification
 	body, err := io.ReadAll(r.Body)
@@ -300,28 +402,19 @@ func (h *subscriptionHandlerImpl) HandleWebhook(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// Get the callback token from header
-	callbackToken := r.Header.Get("X-Callback-Token")
-	if callbackToken == "" {
-		response.Unauthorized(w, "missing callback token")
-		return
-	}
-
-	// Verify the webhook signature
-	if !h.webhookVerifier.VerifySignature(callbackToken) {
-		response.Unauthorized(w, "invalid callback token")
-		return
-	}
-
-	// Parse the webhook payload
-	var payload subscription.XenditWebhookPayload
-	if err := json.Unmarshal(body, &payload); err != nil {
-		response.BadRequest(w, "invalid webhook payload", nil)
+	// Verify the callback and map the provider status to an internal status
+	callback, err := h.paymentProvider.VerifyCallback(r.Header, body)
+	if err != nil {
+		if errors.Is(err, subscription.ErrInvalidWebhookSignature) {
+			response.Unauthorized(w, "invalid callback token")
+			return
+		}
+		response.HandleError(w, err)
 		return
 	}
 
 	// Process the webhook
-	if err := h.subscriptionService.HandleWebhook(r.Context(), payload); err != nil {
+	if err := h.subscriptionService.HandleWebhook(r.Context(), callback); err != nil {
 		response.HandleError(w, err)
 		return
 	}
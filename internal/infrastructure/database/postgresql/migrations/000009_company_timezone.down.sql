@@ -0,0 +1,5 @@
+-- =========================
+-- Rollback Company Timezone
+-- =========================
+
+ALTER TABLE companies DROP COLUMN IF EXISTS timezone;
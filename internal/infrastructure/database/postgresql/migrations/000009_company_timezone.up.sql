@@ -0,0 +1,10 @@
+-- =========================
+-- Add Timezone to Companies
+-- =========================
+
+-- Company-wide timezone used to resolve "today" and calendar dates
+-- for leave, schedule and attendance when no branch timezone applies
+ALTER TABLE companies
+ADD COLUMN timezone VARCHAR(50) NOT NULL DEFAULT 'Asia/Jakarta';
+
+COMMENT ON COLUMN companies.timezone IS 'IANA timezone name (e.g. Asia/Jakarta) used for company date calculations';
@@ -0,0 +1,20 @@
+-- ==========================================
+-- Rollback Trial Reminders and Notifications
+-- ==========================================
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS valid_notification_type;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_type CHECK (type IN (
+    'attendance_clock_in',
+    'attendance_clock_out',
+    'attendance_auto_closed',
+    'attendance_marked_absent',
+    'leave_request',
+    'leave_approved',
+    'leave_rejected',
+    'payroll_generated',
+    'schedule_updated',
+    'invitation_sent',
+    'employee_joined'
+));
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS trial_reminder_days;
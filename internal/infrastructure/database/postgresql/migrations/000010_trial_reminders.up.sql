@@ -0,0 +1,28 @@
+-- =================================
+-- Trial Reminders and Notifications
+-- =================================
+
+-- Smallest reminder lead time (in days) already sent for the current trial,
+-- so the hourly sweep does not repeat a reminder
+ALTER TABLE subscriptions
+ADD COLUMN trial_reminder_days INT;
+
+COMMENT ON COLUMN subscriptions.trial_reminder_days IS 'Lead time in days of the last trial-ending reminder sent (NULL = none yet)';
+
+-- Allow subscription notification types
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS valid_notification_type;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_type CHECK (type IN (
+    'attendance_clock_in',
+    'attendance_clock_out',
+    'attendance_auto_closed',
+    'attendance_marked_absent',
+    'leave_request',
+    'leave_approved',
+    'leave_rejected',
+    'payroll_generated',
+    'schedule_updated',
+    'invitation_sent',
+    'employee_joined',
+    'trial_ending',
+    'subscription_activated'
+));
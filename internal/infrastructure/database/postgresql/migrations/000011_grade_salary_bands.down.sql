@@ -0,0 +1,8 @@
+-- ================================
+-- Rollback Salary Bands on Grades
+-- ================================
+
+ALTER TABLE grades
+DROP CONSTRAINT IF EXISTS chk_grade_salary_band,
+DROP COLUMN IF EXISTS min_salary,
+DROP COLUMN IF EXISTS max_salary;
@@ -0,0 +1,16 @@
+-- ===========================
+-- Add Salary Bands to Grades
+-- ===========================
+
+-- Optional compensation range per grade; employee base_salary is validated against it
+ALTER TABLE grades
+ADD COLUMN min_salary DECIMAL(15,2),
+ADD COLUMN max_salary DECIMAL(15,2),
+ADD CONSTRAINT chk_grade_salary_band CHECK (
+    (min_salary IS NULL OR min_salary >= 0)
+    AND (max_salary IS NULL OR max_salary >= 0)
+    AND (min_salary IS NULL OR max_salary IS NULL OR min_salary <= max_salary)
+);
+
+COMMENT ON COLUMN grades.min_salary IS 'Minimum base salary for this grade (NULL = no lower bound)';
+COMMENT ON COLUMN grades.max_salary IS 'Maximum base salary for this grade (NULL = no upper bound)';
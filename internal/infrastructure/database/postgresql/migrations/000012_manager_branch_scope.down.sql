@@ -0,0 +1,8 @@
+-- ===================================
+-- Rollback Branch-Scoped Managers
+-- ===================================
+
+DROP INDEX IF EXISTS idx_users_managed_branch;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS managed_branch_id;
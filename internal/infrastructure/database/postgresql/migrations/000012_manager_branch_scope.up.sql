@@ -0,0 +1,12 @@
+-- ===========================
+-- Branch-Scoped Managers
+-- ===========================
+
+-- Optional branch a manager is limited to; NULL keeps company-wide access.
+-- No ON DELETE action: a branch that still scopes a manager counts as in use.
+ALTER TABLE users
+ADD COLUMN managed_branch_id UUID REFERENCES branches(id);
+
+CREATE INDEX idx_users_managed_branch ON users(managed_branch_id) WHERE managed_branch_id IS NOT NULL;
+
+COMMENT ON COLUMN users.managed_branch_id IS 'Branch a manager is restricted to (NULL = company-wide)';
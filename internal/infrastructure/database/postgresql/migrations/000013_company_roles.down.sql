@@ -0,0 +1,10 @@
+-- ===========================
+-- Rollback Company Roles
+-- ===========================
+
+DROP INDEX IF EXISTS idx_users_company_role;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS company_role_id;
+
+DROP TABLE IF EXISTS company_roles;
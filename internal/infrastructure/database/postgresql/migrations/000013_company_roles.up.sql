@@ -0,0 +1,27 @@
+-- ===========================
+-- Company Roles (Custom Permission Sets)
+-- ===========================
+
+-- Named permission sets a company can attach to users on top of their base role
+-- (e.g. a "Payroll Admin" employee). Base roles keep their default permissions.
+CREATE TABLE company_roles (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT uk_company_role_name UNIQUE (company_id, name),
+    CONSTRAINT chk_updated_at_not_before_created_at CHECK (updated_at >= created_at)
+);
+
+CREATE INDEX idx_company_roles_company ON company_roles(company_id);
+
+-- Deleting a company role drops its extra permissions; users keep their base role
+ALTER TABLE users
+ADD COLUMN company_role_id UUID REFERENCES company_roles(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_users_company_role ON users(company_role_id) WHERE company_role_id IS NOT NULL;
+
+COMMENT ON COLUMN users.company_role_id IS 'Optional company role granting permissions beyond the base role';
@@ -0,0 +1,5 @@
+-- =========================
+-- OAuth Link Tokens Migration Down
+-- =========================
+
+DROP TABLE IF EXISTS oauth_link_tokens;
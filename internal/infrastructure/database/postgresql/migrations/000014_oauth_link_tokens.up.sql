@@ -0,0 +1,23 @@
+-- =========================
+-- OAuth Link Tokens Migration
+-- =========================
+
+-- Table: oauth_link_tokens
+-- Pending links between an OAuth identity and an existing password account.
+-- A link is only applied after the account owner confirms it with their password.
+CREATE TABLE oauth_link_tokens (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token UUID UNIQUE NOT NULL DEFAULT uuidv7(),
+    provider VARCHAR(50) NOT NULL,
+    provider_subject VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMPTZ, -- NULL until the link is confirmed
+
+    -- Track request source
+    ip_address VARCHAR(45)
+);
+
+CREATE INDEX idx_oauth_link_tokens_user_id ON oauth_link_tokens(user_id);
+CREATE INDEX idx_oauth_link_tokens_expires_at ON oauth_link_tokens(expires_at);
@@ -0,0 +1,5 @@
+-- =========================
+-- Leave Negative Balance Migration Down
+-- =========================
+
+ALTER TABLE leave_types DROP COLUMN IF EXISTS allow_negative_balance;
@@ -0,0 +1,9 @@
+-- =========================
+-- Leave Negative Balance Migration
+-- =========================
+
+-- Leave types flagged with allow_negative_balance may be reserved and approved
+-- beyond the available quota. The debt stays on the quota row as a negative
+-- available_quota and is carried into the next year's used_quota.
+ALTER TABLE leave_types
+    ADD COLUMN allow_negative_balance BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,9 @@
+-- =========================
+-- Attendance Rounding Migration Down
+-- =========================
+
+ALTER TABLE attendances
+    DROP COLUMN IF EXISTS raw_overtime_minutes,
+    DROP COLUMN IF EXISTS raw_late_minutes;
+
+DROP TABLE IF EXISTS attendance_settings;
@@ -0,0 +1,33 @@
+-- =========================
+-- Attendance Rounding Migration
+-- =========================
+
+-- Table: attendance_settings
+-- Company-wide attendance configuration. Companies without a row use no rounding.
+CREATE TABLE attendance_settings (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE UNIQUE,
+
+    -- Rounding Settings (mode: none, up, down, nearest; minutes: step size)
+    late_rounding_mode VARCHAR(10) NOT NULL DEFAULT 'none',
+    late_rounding_minutes INTEGER NOT NULL DEFAULT 0,
+    overtime_rounding_mode VARCHAR(10) NOT NULL DEFAULT 'none',
+    overtime_rounding_minutes INTEGER NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_attendance_rounding_mode CHECK (
+        late_rounding_mode IN ('none', 'up', 'down', 'nearest') AND
+        overtime_rounding_mode IN ('none', 'up', 'down', 'nearest')
+    ),
+    CONSTRAINT chk_attendance_rounding_minutes CHECK (
+        late_rounding_minutes BETWEEN 0 AND 60 AND
+        overtime_rounding_minutes BETWEEN 0 AND 60
+    )
+);
+
+-- Raw values before rounding, so audits can compare them with late_minutes/overtime_minutes
+ALTER TABLE attendances
+    ADD COLUMN raw_late_minutes INTEGER,
+    ADD COLUMN raw_overtime_minutes INTEGER;
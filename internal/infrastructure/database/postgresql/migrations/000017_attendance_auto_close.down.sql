@@ -0,0 +1,9 @@
+-- =========================
+-- Attendance Auto-Close Migration Down
+-- =========================
+
+ALTER TABLE attendance_settings
+    DROP CONSTRAINT IF EXISTS chk_attendance_auto_close_max,
+    DROP COLUMN IF EXISTS auto_close_max_work_minutes;
+
+ALTER TABLE attendances DROP COLUMN IF EXISTS auto_closed;
@@ -0,0 +1,15 @@
+-- =========================
+-- Attendance Auto-Close Migration
+-- =========================
+
+-- Attendances closed by the auto-close job get an estimated clock-out at the scheduled
+-- end time, capped at auto_close_max_work_minutes, and zero overtime.
+ALTER TABLE attendances
+    ADD COLUMN auto_closed BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE attendance_settings
+    ADD COLUMN auto_close_max_work_minutes INTEGER NOT NULL DEFAULT 720,
+    ADD CONSTRAINT chk_attendance_auto_close_max CHECK (auto_close_max_work_minutes BETWEEN 60 AND 1440);
+
+-- Records closed by the previous job flagged themselves through the status column
+UPDATE attendances SET auto_closed = true WHERE status = 'auto_closed';
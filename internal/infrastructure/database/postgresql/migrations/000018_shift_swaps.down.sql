@@ -0,0 +1,24 @@
+-- =========================
+-- Shift Swap Requests Migration Down
+-- =========================
+
+DELETE FROM notifications WHERE type IN ('shift_swap_requested', 'shift_swap_approved', 'shift_swap_rejected');
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS valid_notification_type;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_type CHECK (type IN (
+    'attendance_clock_in',
+    'attendance_clock_out',
+    'attendance_auto_closed',
+    'attendance_marked_absent',
+    'leave_request',
+    'leave_approved',
+    'leave_rejected',
+    'payroll_generated',
+    'schedule_updated',
+    'invitation_sent',
+    'employee_joined',
+    'trial_ending',
+    'subscription_activated'
+));
+
+DROP TABLE IF EXISTS shift_swap_requests;
@@ -0,0 +1,49 @@
+-- =========================
+-- Shift Swap Requests Migration
+-- =========================
+
+-- Table: shift_swap_requests
+-- An employee asks to swap their schedule with a colleague for a single date.
+-- Once a manager approves, each employee gets a single-day schedule assignment
+-- with the other's schedule.
+CREATE TABLE shift_swap_requests (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    from_employee_id UUID NOT NULL REFERENCES employees(id),
+    to_employee_id UUID NOT NULL REFERENCES employees(id),
+    swap_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    requested_by UUID REFERENCES users(id),
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMPTZ,
+    rejection_reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_shift_swap_status CHECK (status IN ('pending', 'approved', 'rejected')),
+    CONSTRAINT chk_shift_swap_different_employees CHECK (from_employee_id <> to_employee_id)
+);
+
+-- One pending swap per requester and date
+CREATE UNIQUE INDEX uk_shift_swap_pending ON shift_swap_requests(from_employee_id, swap_date) WHERE status = 'pending';
+CREATE INDEX idx_shift_swap_requests_company_status ON shift_swap_requests(company_id, status);
+
+-- Allow shift swap notification types
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS valid_notification_type;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_type CHECK (type IN (
+    'attendance_clock_in',
+    'attendance_clock_out',
+    'attendance_auto_closed',
+    'attendance_marked_absent',
+    'leave_request',
+    'leave_approved',
+    'leave_rejected',
+    'payroll_generated',
+    'schedule_updated',
+    'invitation_sent',
+    'employee_joined',
+    'trial_ending',
+    'subscription_activated',
+    'shift_swap_requested',
+    'shift_swap_approved',
+    'shift_swap_rejected'
+));
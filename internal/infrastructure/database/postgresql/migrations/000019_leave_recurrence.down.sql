@@ -0,0 +1,7 @@
+-- =========================
+-- Recurring Leave Migration Down
+-- =========================
+
+DROP INDEX IF EXISTS idx_leave_requests_recurrence_id;
+
+ALTER TABLE leave_requests DROP COLUMN IF EXISTS recurrence_id;
@@ -0,0 +1,10 @@
+-- =========================
+-- Recurring Leave Migration
+-- =========================
+
+-- A recurring leave submission (e.g. every Friday for a month) is stored as
+-- individual single-day requests that share a recurrence_id, so the group can
+-- be approved or rejected together.
+ALTER TABLE leave_requests ADD COLUMN recurrence_id UUID;
+
+CREATE INDEX idx_leave_requests_recurrence_id ON leave_requests(recurrence_id) WHERE recurrence_id IS NOT NULL;
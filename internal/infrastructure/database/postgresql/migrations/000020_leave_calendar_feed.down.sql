@@ -0,0 +1,5 @@
+-- =========================
+-- Leave Calendar Feed Migration Down
+-- =========================
+
+ALTER TABLE employees DROP COLUMN IF EXISTS calendar_feed_nonce;
@@ -0,0 +1,7 @@
+-- =========================
+-- Leave Calendar Feed Migration
+-- =========================
+
+-- The iCal feed token is a signed token carrying this nonce. Rotating the
+-- nonce invalidates every feed URL issued before it.
+ALTER TABLE employees ADD COLUMN calendar_feed_nonce VARCHAR(64);
@@ -0,0 +1,9 @@
+-- =========================
+-- Invoice Numbering Migration Down
+-- =========================
+
+DROP INDEX IF EXISTS idx_invoices_company_invoice_number;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS invoice_number;
+
+DROP TABLE IF EXISTS invoice_sequences;
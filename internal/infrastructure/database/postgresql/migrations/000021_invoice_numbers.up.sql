@@ -0,0 +1,37 @@
+-- =========================
+-- Invoice Numbering Migration
+-- =========================
+
+-- Table: invoice_sequences
+-- One counter row per company. Allocating a number increments the row inside the
+-- invoice-creating transaction, so the row lock serialises concurrent checkouts and
+-- a rolled-back checkout leaves no gap.
+CREATE TABLE invoice_sequences (
+    company_id UUID PRIMARY KEY REFERENCES companies(id) ON DELETE CASCADE,
+    last_number BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE invoices ADD COLUMN invoice_number VARCHAR(32);
+
+-- Backfill existing invoices in creation order
+WITH numbered AS (
+    SELECT id,
+           company_id,
+           issue_date,
+           ROW_NUMBER() OVER (PARTITION BY company_id ORDER BY created_at, id) AS seq
+    FROM invoices
+)
+UPDATE invoices i
+SET invoice_number = 'INV-' || EXTRACT(YEAR FROM n.issue_date)::int || '-' || LPAD(n.seq::text, 6, '0')
+FROM numbered n
+WHERE n.id = i.id;
+
+INSERT INTO invoice_sequences (company_id, last_number)
+SELECT company_id, COUNT(*)
+FROM invoices
+GROUP BY company_id;
+
+ALTER TABLE invoices ALTER COLUMN invoice_number SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_invoices_company_invoice_number ON invoices(company_id, invoice_number);
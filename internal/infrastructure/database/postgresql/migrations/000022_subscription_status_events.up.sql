@@ -0,0 +1,25 @@
+-- =========================
+-- Subscription Status Events Migration
+-- =========================
+
+-- Allow subscription status change notification type
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS valid_notification_type;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_type CHECK (type IN (
+    'attendance_clock_in',
+    'attendance_clock_out',
+    'attendance_auto_closed',
+    'attendance_marked_absent',
+    'leave_request',
+    'leave_approved',
+    'leave_rejected',
+    'payroll_generated',
+    'schedule_updated',
+    'invitation_sent',
+    'employee_joined',
+    'trial_ending',
+    'subscription_activated',
+    'shift_swap_requested',
+    'shift_swap_approved',
+    'shift_swap_rejected',
+    'subscription_status_changed'
+));
@@ -0,0 +1,6 @@
+-- =========================
+-- Subscription Currency Migration Down
+-- =========================
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS currency;
+ALTER TABLE subscription_plans DROP COLUMN IF EXISTS currency;
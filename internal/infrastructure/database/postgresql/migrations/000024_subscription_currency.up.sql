@@ -0,0 +1,9 @@
+-- =========================
+-- Subscription Currency Migration
+-- =========================
+
+-- ISO 4217 code the plan is priced in; existing plans stay in Rupiah
+ALTER TABLE subscription_plans ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'IDR';
+
+-- Currency snapshot taken at checkout so later plan changes never alter issued invoices
+ALTER TABLE invoices ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'IDR';
@@ -0,0 +1,5 @@
+-- =========================
+-- Attendance Schedule Location Migration Down
+-- =========================
+
+ALTER TABLE attendances DROP COLUMN IF EXISTS work_schedule_location_id;
@@ -0,0 +1,8 @@
+-- =========================
+-- Attendance Schedule Location Migration
+-- =========================
+
+-- Office the employee clocked in at; for schedules with several locations this is the nearest one.
+-- NULL for WFA schedules and for clock-ins outside every location's radius.
+ALTER TABLE attendances
+    ADD COLUMN work_schedule_location_id UUID REFERENCES work_schedule_locations(id) ON DELETE SET NULL;
@@ -0,0 +1,14 @@
+-- =========================
+-- Attendance Overtime Rules Migration Down
+-- =========================
+
+ALTER TABLE attendances DROP COLUMN IF EXISTS overtime_multiplier;
+
+ALTER TABLE attendance_settings
+    DROP CONSTRAINT IF EXISTS chk_attendance_overtime_rates,
+    DROP CONSTRAINT IF EXISTS chk_attendance_overtime_cap,
+    DROP CONSTRAINT IF EXISTS chk_attendance_overtime_min,
+    DROP COLUMN IF EXISTS holiday_overtime_rate,
+    DROP COLUMN IF EXISTS weekend_overtime_rate,
+    DROP COLUMN IF EXISTS overtime_daily_cap_minutes,
+    DROP COLUMN IF EXISTS overtime_min_minutes;
@@ -0,0 +1,21 @@
+-- =========================
+-- Attendance Overtime Rules Migration
+-- =========================
+
+-- Overstays shorter than overtime_min_minutes don't count, the rest is capped at
+-- overtime_daily_cap_minutes per day (0 = no cap). Weekend and holiday overtime is
+-- paid at its own rate.
+ALTER TABLE attendance_settings
+    ADD COLUMN overtime_min_minutes INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN overtime_daily_cap_minutes INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN weekend_overtime_rate NUMERIC(4,2) NOT NULL DEFAULT 1,
+    ADD COLUMN holiday_overtime_rate NUMERIC(4,2) NOT NULL DEFAULT 1,
+    ADD CONSTRAINT chk_attendance_overtime_min CHECK (overtime_min_minutes BETWEEN 0 AND 240),
+    ADD CONSTRAINT chk_attendance_overtime_cap CHECK (overtime_daily_cap_minutes BETWEEN 0 AND 1440),
+    ADD CONSTRAINT chk_attendance_overtime_rates CHECK (
+        weekend_overtime_rate BETWEEN 1 AND 10 AND holiday_overtime_rate BETWEEN 1 AND 10
+    );
+
+-- The multiplier applied to overtime_minutes, kept per record so payroll pays what was in force
+ALTER TABLE attendances
+    ADD COLUMN overtime_multiplier NUMERIC(4,2) NOT NULL DEFAULT 1;
@@ -0,0 +1,30 @@
+-- =========================
+-- Seat Reconciliation Migration Down
+-- =========================
+
+DELETE FROM notifications WHERE type = 'seat_limit_exceeded';
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS valid_notification_type;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_type CHECK (type IN (
+    'attendance_clock_in',
+    'attendance_clock_out',
+    'attendance_auto_closed',
+    'attendance_marked_absent',
+    'leave_request',
+    'leave_approved',
+    'leave_rejected',
+    'payroll_generated',
+    'schedule_updated',
+    'invitation_sent',
+    'employee_joined',
+    'trial_ending',
+    'subscription_activated',
+    'shift_swap_requested',
+    'shift_swap_approved',
+    'shift_swap_rejected',
+    'subscription_status_changed',
+    'invoice_expired',
+    'payment_failed'
+));
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS seat_violation_flagged_at;
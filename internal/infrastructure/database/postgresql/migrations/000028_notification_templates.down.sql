@@ -0,0 +1,5 @@
+-- =========================
+-- Notification Templates Migration Down
+-- =========================
+
+DROP TABLE IF EXISTS notification_templates;
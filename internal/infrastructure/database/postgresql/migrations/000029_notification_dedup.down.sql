@@ -0,0 +1,7 @@
+-- =========================
+-- Notification Deduplication Migration Down
+-- =========================
+
+DROP INDEX IF EXISTS idx_notifications_dedup;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS dedup_key;
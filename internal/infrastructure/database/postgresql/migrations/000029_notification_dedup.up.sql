@@ -0,0 +1,9 @@
+-- =========================
+-- Notification Deduplication Migration
+-- =========================
+
+-- Repeated notifications with the same key are folded into the recipient's latest unread one
+ALTER TABLE notifications ADD COLUMN dedup_key VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_dedup ON notifications(recipient_id, dedup_key, created_at DESC)
+    WHERE dedup_key IS NOT NULL AND is_read = FALSE;
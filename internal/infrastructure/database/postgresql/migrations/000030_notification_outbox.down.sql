@@ -0,0 +1,7 @@
+-- =========================
+-- Notification Outbox Migration Down
+-- =========================
+
+DROP INDEX IF EXISTS idx_notification_outbox_pending;
+
+DROP TABLE IF EXISTS notification_outbox;
@@ -0,0 +1,7 @@
+-- =========================
+-- Employee Emergency Contact Migration Down
+-- =========================
+
+ALTER TABLE employees DROP COLUMN IF EXISTS emergency_contact_phone;
+ALTER TABLE employees DROP COLUMN IF EXISTS emergency_contact_relationship;
+ALTER TABLE employees DROP COLUMN IF EXISTS emergency_contact_name;
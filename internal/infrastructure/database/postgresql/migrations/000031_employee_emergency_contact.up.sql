@@ -0,0 +1,7 @@
+-- =========================
+-- Employee Emergency Contact Migration
+-- =========================
+
+ALTER TABLE employees ADD COLUMN emergency_contact_name VARCHAR(255);
+ALTER TABLE employees ADD COLUMN emergency_contact_relationship VARCHAR(50);
+ALTER TABLE employees ADD COLUMN emergency_contact_phone VARCHAR(20);
@@ -0,0 +1,7 @@
+-- =========================
+-- Employee Search Indexes Migration Down
+-- =========================
+
+DROP INDEX IF EXISTS idx_employees_phone_number_trgm;
+DROP INDEX IF EXISTS idx_employees_nik_trgm;
+DROP INDEX IF EXISTS idx_employees_employee_code_trgm;
@@ -0,0 +1,8 @@
+-- =========================
+-- Employee Search Indexes Migration
+-- =========================
+
+-- Query: List with search or partial nik / phone_number (ILIKE '%...%'), alongside idx_employees_full_name_trgm
+CREATE INDEX IF NOT EXISTS idx_employees_employee_code_trgm ON employees USING gin(employee_code gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_employees_nik_trgm ON employees USING gin(nik gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_employees_phone_number_trgm ON employees USING gin(phone_number gin_trgm_ops);
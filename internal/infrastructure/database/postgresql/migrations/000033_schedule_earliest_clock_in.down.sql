@@ -0,0 +1,7 @@
+-- =========================
+-- Schedule Earliest Clock-In Migration Down
+-- =========================
+
+ALTER TABLE work_schedules DROP CONSTRAINT IF EXISTS chk_earliest_clock_in_minutes;
+
+ALTER TABLE work_schedules DROP COLUMN IF EXISTS earliest_clock_in_minutes;
@@ -0,0 +1,11 @@
+-- =========================
+-- Schedule Earliest Clock-In Migration
+-- =========================
+
+-- How many minutes before the scheduled clock-in employees may clock in (0 = no limit)
+ALTER TABLE work_schedules ADD COLUMN earliest_clock_in_minutes INT NOT NULL DEFAULT 0;
+
+ALTER TABLE work_schedules ADD CONSTRAINT chk_earliest_clock_in_minutes CHECK (earliest_clock_in_minutes >= 0);
+
+-- Existing schedules keep the one-hour limit that clock-in used to apply to every schedule
+UPDATE work_schedules SET earliest_clock_in_minutes = 60;
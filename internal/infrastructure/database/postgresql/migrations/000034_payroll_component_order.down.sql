@@ -0,0 +1,10 @@
+-- =========================
+-- Payroll Component Order Migration Down
+-- =========================
+
+ALTER TABLE payroll_records DROP COLUMN IF EXISTS component_lines;
+
+ALTER TABLE payroll_components DROP CONSTRAINT IF EXISTS chk_payroll_component_display_order;
+
+ALTER TABLE payroll_components DROP COLUMN IF EXISTS category;
+ALTER TABLE payroll_components DROP COLUMN IF EXISTS display_order;
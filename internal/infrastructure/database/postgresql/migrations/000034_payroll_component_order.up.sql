@@ -0,0 +1,12 @@
+-- =========================
+-- Payroll Component Order Migration
+-- =========================
+
+-- Position of a component within its type on payslips (lower first) and an optional grouping label
+ALTER TABLE payroll_components ADD COLUMN display_order INT NOT NULL DEFAULT 0;
+ALTER TABLE payroll_components ADD COLUMN category VARCHAR(50);
+
+ALTER TABLE payroll_components ADD CONSTRAINT chk_payroll_component_display_order CHECK (display_order >= 0);
+
+-- Snapshot of the components a record was generated from, with their order and category at that time
+ALTER TABLE payroll_records ADD COLUMN component_lines JSONB;
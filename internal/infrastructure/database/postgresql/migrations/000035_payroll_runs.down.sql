@@ -0,0 +1,7 @@
+-- =========================
+-- Payroll Runs Migration Down
+-- =========================
+
+ALTER TABLE payroll_records DROP COLUMN IF EXISTS payroll_run_id;
+
+DROP TABLE IF EXISTS payroll_runs;
@@ -0,0 +1,33 @@
+-- =========================
+-- Payroll Runs Migration
+-- =========================
+
+-- Table: payroll_runs
+-- One payroll generation for a period. A run that fails or is interrupted stays open and is
+-- resumed by the next generation for the same period.
+CREATE TABLE payroll_runs (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    period_month SMALLINT NOT NULL,
+    period_year SMALLINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    total_employees INT NOT NULL DEFAULT 0,
+    processed_employees INT NOT NULL DEFAULT 0,
+    created_records INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_by UUID REFERENCES users(id),
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT chk_payroll_run_status CHECK (status IN ('running', 'completed', 'failed')),
+    CONSTRAINT chk_payroll_run_period_month CHECK (period_month BETWEEN 1 AND 12)
+);
+
+-- At most one open run per company and period
+CREATE UNIQUE INDEX uk_payroll_runs_open_period ON payroll_runs(company_id, period_month, period_year) WHERE status <> 'completed';
+CREATE INDEX idx_payroll_runs_company_period ON payroll_runs(company_id, period_year, period_month);
+
+-- The run that generated each record
+ALTER TABLE payroll_records ADD COLUMN payroll_run_id UUID REFERENCES payroll_runs(id) ON DELETE SET NULL;
@@ -0,0 +1,5 @@
+-- =========================
+-- Rollback Company Leave Year Start Month
+-- =========================
+
+ALTER TABLE companies DROP COLUMN IF EXISTS leave_year_start_month;
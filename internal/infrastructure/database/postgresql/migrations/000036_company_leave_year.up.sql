@@ -0,0 +1,12 @@
+-- =========================
+-- Add Leave Year Start Month to Companies
+-- =========================
+
+-- Month the company's leave year starts in. Quotas are keyed by the calendar
+-- year the leave year starts in, so with April a quota for 2025 covers
+-- April 2025 through March 2026
+ALTER TABLE companies
+ADD COLUMN leave_year_start_month SMALLINT NOT NULL DEFAULT 1
+    CHECK (leave_year_start_month BETWEEN 1 AND 12);
+
+COMMENT ON COLUMN companies.leave_year_start_month IS 'Month (1-12) the leave year starts in; 1 is a calendar leave year';
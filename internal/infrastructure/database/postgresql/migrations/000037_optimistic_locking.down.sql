@@ -0,0 +1,7 @@
+-- =========================
+-- Rollback Row Versions
+-- =========================
+
+ALTER TABLE payroll_records DROP COLUMN IF EXISTS version;
+
+ALTER TABLE leave_requests DROP COLUMN IF EXISTS version;
@@ -0,0 +1,11 @@
+-- =========================
+-- Add Row Versions for Optimistic Locking
+-- =========================
+
+-- Incremented by every update. Editors send the version they loaded, and an update
+-- whose version no longer matches is rejected instead of overwriting a newer change
+ALTER TABLE leave_requests
+ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE payroll_records
+ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
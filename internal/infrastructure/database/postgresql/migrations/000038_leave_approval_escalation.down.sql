@@ -0,0 +1,34 @@
+-- =========================
+-- Rollback Leave Approval Escalation
+-- =========================
+
+DELETE FROM notifications WHERE type = 'leave_escalated';
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS valid_notification_type;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_type CHECK (type IN (
+    'attendance_clock_in',
+    'attendance_clock_out',
+    'attendance_auto_closed',
+    'attendance_marked_absent',
+    'leave_request',
+    'leave_approved',
+    'leave_rejected',
+    'payroll_generated',
+    'schedule_updated',
+    'invitation_sent',
+    'employee_joined',
+    'trial_ending',
+    'subscription_activated',
+    'shift_swap_requested',
+    'shift_swap_approved',
+    'shift_swap_rejected',
+    'subscription_status_changed',
+    'invoice_expired',
+    'payment_failed',
+    'seat_limit_exceeded'
+));
+
+DROP INDEX IF EXISTS idx_leave_requests_pending_escalation;
+ALTER TABLE leave_requests DROP COLUMN IF EXISTS escalated_at;
+ALTER TABLE companies DROP COLUMN IF EXISTS leave_escalation_action;
+ALTER TABLE companies DROP COLUMN IF EXISTS leave_approval_sla_hours;
@@ -0,0 +1,45 @@
+-- =========================
+-- Leave Approval Escalation
+-- =========================
+
+-- Hours a leave request may wait for approval before it is escalated; 0 turns escalation off
+ALTER TABLE companies
+ADD COLUMN leave_approval_sla_hours INTEGER NOT NULL DEFAULT 0
+    CHECK (leave_approval_sla_hours >= 0);
+
+-- What happens to a request past the SLA: owners are notified, or it is also approved on their behalf
+ALTER TABLE companies
+ADD COLUMN leave_escalation_action VARCHAR(20) NOT NULL DEFAULT 'notify'
+    CHECK (leave_escalation_action IN ('notify', 'auto_approve'));
+
+-- Set once a request has been escalated, so it is escalated only once
+ALTER TABLE leave_requests ADD COLUMN escalated_at TIMESTAMPTZ;
+
+CREATE INDEX idx_leave_requests_pending_escalation ON leave_requests(submitted_at)
+    WHERE status = 'waiting_approval' AND escalated_at IS NULL;
+
+-- Allow escalation notifications
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS valid_notification_type;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_type CHECK (type IN (
+    'attendance_clock_in',
+    'attendance_clock_out',
+    'attendance_auto_closed',
+    'attendance_marked_absent',
+    'leave_request',
+    'leave_approved',
+    'leave_rejected',
+    'payroll_generated',
+    'schedule_updated',
+    'invitation_sent',
+    'employee_joined',
+    'trial_ending',
+    'subscription_activated',
+    'shift_swap_requested',
+    'shift_swap_approved',
+    'shift_swap_rejected',
+    'subscription_status_changed',
+    'invoice_expired',
+    'payment_failed',
+    'seat_limit_exceeded',
+    'leave_escalated'
+));
@@ -0,0 +1,7 @@
+-- =========================
+-- Rollback Employee Anonymization
+-- =========================
+
+ALTER TABLE employees DROP COLUMN IF EXISTS anonymized_at;
+
+-- PostgreSQL cannot drop an enum value; 'ANONYMIZE' stays in audit_action
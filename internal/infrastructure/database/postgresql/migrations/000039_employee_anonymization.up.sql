@@ -0,0 +1,9 @@
+-- =========================
+-- Employee Anonymization
+-- =========================
+
+-- Set once a departed employee's personal data has been overwritten; the row itself is kept
+-- so payroll and attendance history stay linked to it
+ALTER TABLE employees ADD COLUMN anonymized_at TIMESTAMPTZ;
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'ANONYMIZE';
@@ -4,7 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"strings"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
@@ -13,35 +14,36 @@ import (
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/utils"
 )
 
 type AttendanceJobs struct {
-	attendanceRepo   attendance.AttendanceRepository
-	employeeRepo     employee.EmployeeRepository
-	scheduleRepo     schedule.WorkScheduleRepository
-	scheduleTimeRepo schedule.WorkScheduleTimeRepository
-	branchRepo       branch.BranchRepository
-	notificationSvc  notification.Service
-	db               *database.DB
+	attendanceSvc   attendance.AttendanceService
+	attendanceRepo  attendance.AttendanceRepository
+	employeeRepo    employee.EmployeeRepository
+	scheduleRepo    schedule.WorkScheduleRepository
+	branchRepo      branch.BranchRepository
+	notificationSvc notification.Service
+	db              *database.DB
 }
 
 func NewAttendanceJobs(
+	attendanceSvc attendance.AttendanceService,
 	attendanceRepo attendance.AttendanceRepository,
 	employeeRepo employee.EmployeeRepository,
 	scheduleRepo schedule.WorkScheduleRepository,
-	scheduleTimeRepo schedule.WorkScheduleTimeRepository,
 	branchRepo branch.BranchRepository,
 	notificationSvc notification.Service,
 	db *database.DB,
 ) *AttendanceJobs {
 	return &AttendanceJobs{
-		attendanceRepo:   attendanceRepo,
-		employeeRepo:     employeeRepo,
-		scheduleRepo:     scheduleRepo,
-		scheduleTimeRepo: scheduleTimeRepo,
-		branchRepo:       branchRepo,
-		notificationSvc:  notificationSvc,
-		db:               db,
+		attendanceSvc:   attendanceSvc,
+		attendanceRepo:  attendanceRepo,
+		employeeRepo:    employeeRepo,
+		scheduleRepo:    scheduleRepo,
+		branchRepo:      branchRepo,
+		notificationSvc: notificationSvc,
+		db:              db,
 	}
 }
 
@@ -50,141 +52,15 @@ func (j *AttendanceJobs) RegisterJobs(scheduler *Scheduler) {
 	scheduler.AddJob("mark_absent_employees", 1*time.Hour, j.MarkAbsentEmployees)
 }
 
+// AutoCloseStaleAttendances closes past attendances that were never clocked out. It runs every
+// hour so each company's day is closed soon after it ends locally, and a missed run is caught up
+// by the next one.
 func (j *AttendanceJobs) AutoCloseStaleAttendances(ctx context.Context) error {
-	// Only run at midnight (00:00-00:59 UTC)
-	if time.Now().UTC().Hour() != 0 {
-		return nil
-	}
-
 	slog.Info("Cron: Starting auto-close stale attendances job")
 
-	staleSessions, err := j.attendanceRepo.GetStaleOpenSessions(ctx, 2)
+	closedCount, err := j.attendanceSvc.AutoCloseOpenAttendance(ctx, time.Now())
 	if err != nil {
-		return fmt.Errorf("failed to get stale sessions: %w", err)
-	}
-
-	if len(staleSessions) == 0 {
-		slog.Info("Cron: No stale attendances found")
-		return nil
-	}
-
-	closedCount := 0
-	for _, session := range staleSessions {
-		// Parse schedule metadata from rejection_reason field
-		scheduleData := *session.RejectionReason
-		parts := strings.Split(scheduleData, "|")
-		var clockOutTimeStr, timezone string
-		var isNextDay bool
-
-		for _, part := range parts {
-			kv := strings.Split(part, "=")
-			if len(kv) == 2 {
-				switch kv[0] {
-				case "schedule_clock_out":
-					clockOutTimeStr = kv[1]
-				case "next_day":
-					isNextDay = kv[1] == "true"
-				case "timezone":
-					timezone = kv[1]
-				}
-			}
-		}
-
-		// Load location
-		loc, err := time.LoadLocation(timezone)
-		if err != nil {
-			loc = time.UTC
-		}
-
-		// Parse scheduled clock out time
-		clockOutTime, _ := time.Parse("15:04:05", clockOutTimeStr)
-
-		// Construct scheduled clock out timestamp
-		scheduledOut := time.Date(
-			session.Date.Year(), session.Date.Month(), session.Date.Day(),
-			clockOutTime.Hour(), clockOutTime.Minute(), clockOutTime.Second(), 0,
-			loc,
-		)
-
-		if isNextDay {
-			scheduledOut = scheduledOut.Add(24 * time.Hour)
-		}
-
-		scheduledOutUTC := scheduledOut.UTC()
-
-		// Calculate work hours
-		workDuration := scheduledOutUTC.Sub(*session.ClockIn)
-		workHoursMins := int(workDuration.Minutes())
-
-		// Update attendance
-		session.ClockOut = &scheduledOutUTC
-		session.WorkHoursInMinutes = &workHoursMins
-		session.Status = "auto_closed"
-		reason := "Auto-closed: No clock-out detected within 2 hours of scheduled end time. Please contact your manager if this is incorrect."
-		session.RejectionReason = &reason
-
-		// Calculate overtime if applicable
-		if session.WorkScheduleTimeID != nil {
-			schedTime, err := j.scheduleTimeRepo.GetByID(ctx, *session.WorkScheduleTimeID, session.CompanyID)
-			if err == nil {
-				expectedDuration := schedTime.ClockOutTime.Sub(schedTime.ClockInTime)
-				expectedMins := int(expectedDuration.Minutes())
-
-				if workHoursMins > expectedMins {
-					overtime := workHoursMins - expectedMins
-					session.OvertimeMinutes = &overtime
-				}
-			}
-		}
-
-		if err := j.attendanceRepo.Update(ctx, session); err != nil {
-			slog.Error("Cron: Failed to auto-close attendance",
-				"attendance_id", session.ID,
-				"employee_id", session.EmployeeID,
-				"error", err)
-			continue
-		}
-
-		// Notify employee
-		if j.notificationSvc != nil {
-			emp, _ := j.employeeRepo.GetByID(ctx, session.EmployeeID)
-			if emp.UserID != nil {
-				_ = j.notificationSvc.QueueNotification(ctx, notification.CreateNotificationRequest{
-					CompanyID:   session.CompanyID,
-					RecipientID: *emp.UserID,
-					Type:        notification.TypeAttendanceAutoClosed,
-					Title:       "Attendance Auto-Closed",
-					Message:     fmt.Sprintf("Your attendance for %s was automatically closed", session.Date.Format("2006-01-02")),
-					Data: map[string]interface{}{
-						"attendance_id": session.ID,
-						"date":          session.Date.Format("2006-01-02"),
-						"reason":        reason,
-					},
-				})
-			}
-
-			// Notify managers
-			managers, _ := j.employeeRepo.GetManagersByCompanyID(ctx, session.CompanyID)
-			for _, manager := range managers {
-				if manager.UserID != nil {
-					_ = j.notificationSvc.QueueNotification(ctx, notification.CreateNotificationRequest{
-						CompanyID:   session.CompanyID,
-						RecipientID: *manager.UserID,
-						SenderID:    emp.UserID,
-						Type:        notification.TypeAttendanceAutoClosed,
-						Title:       "Employee Attendance Auto-Closed",
-						Message:     fmt.Sprintf("%s's attendance for %s was auto-closed", emp.FullName, session.Date.Format("2006-01-02")),
-						Data: map[string]interface{}{
-							"employee_id":   session.EmployeeID,
-							"attendance_id": session.ID,
-							"date":          session.Date.Format("2006-01-02"),
-						},
-					})
-				}
-			}
-		}
-
-		closedCount++
+		return fmt.Errorf("failed to auto-close open attendances: %w", err)
 	}
 
 	slog.Info("Cron: Auto-closed stale attendances", "count", closedCount)
@@ -229,6 +105,8 @@ func (j *AttendanceJobs) MarkAbsentEmployees(ctx context.Context) error {
 		}
 
 		var absences []attendance.Attendance
+		// Branches can sit in different timezones, so one run can mark absences on more than one local date
+		absentByDate := make(map[string]int)
 
 		for _, emp := range employees {
 			// Get timezone for employee
@@ -237,7 +115,7 @@ func (j *AttendanceJobs) MarkAbsentEmployees(ctx context.Context) error {
 				continue
 			}
 
-			loc, _ := time.LoadLocation(timezone)
+			loc := utils.LoadLocation(timezone)
 
 			// Calculate yesterday in employee's timezone
 			nowLocal := time.Now().In(loc)
@@ -263,7 +141,7 @@ func (j *AttendanceJobs) MarkAbsentEmployees(ctx context.Context) error {
 			absenceRecord := attendance.Attendance{
 				EmployeeID:         emp.ID,
 				CompanyID:          companyID,
-				Date:               yesterdayLocal.Truncate(24 * time.Hour),
+				Date:               utils.StartOfDay(yesterdayLocal, loc),
 				WorkScheduleTimeID: &activeSchedule.TimeID,
 				Status:             "absent",
 				WorkHoursInMinutes: &zero,
@@ -272,6 +150,7 @@ func (j *AttendanceJobs) MarkAbsentEmployees(ctx context.Context) error {
 			}
 
 			absences = append(absences, absenceRecord)
+			absentByDate[yesterdayStr]++
 		}
 
 		// Bulk insert absences
@@ -283,22 +162,25 @@ func (j *AttendanceJobs) MarkAbsentEmployees(ctx context.Context) error {
 
 			totalAbsent += len(absences)
 
-			// Notify managers about absences
+			// Notify managers about absences, once per local date they were recorded on
 			if j.notificationSvc != nil {
 				managers, _ := j.employeeRepo.GetManagersByCompanyID(ctx, companyID)
-				for _, manager := range managers {
-					if manager.UserID != nil {
-						_ = j.notificationSvc.QueueNotification(ctx, notification.CreateNotificationRequest{
-							CompanyID:   companyID,
-							RecipientID: *manager.UserID,
-							Type:        notification.TypeAttendanceMarkedAbsent,
-							Title:       "Employees Marked Absent",
-							Message:     fmt.Sprintf("%d employees were marked absent for yesterday", len(absences)),
-							Data: map[string]interface{}{
-								"count": len(absences),
-								"date":  time.Now().AddDate(0, 0, -1).Format("2006-01-02"),
-							},
-						})
+				for _, date := range slices.Sorted(maps.Keys(absentByDate)) {
+					count := absentByDate[date]
+					for _, manager := range managers {
+						if manager.UserID != nil {
+							_ = j.notificationSvc.QueueNotification(ctx, notification.CreateNotificationRequest{
+								CompanyID:   companyID,
+								RecipientID: *manager.UserID,
+								Type:        notification.TypeAttendanceMarkedAbsent,
+								Title:       "Employees Marked Absent",
+								Message:     fmt.Sprintf("%d employees were marked absent for %s", count, date),
+								Data: map[string]interface{}{
+									"count": count,
+									"date":  date,
+								},
+							})
+						}
 					}
 				}
 			}
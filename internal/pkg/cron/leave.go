@@ -0,0 +1,99 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+)
+
+// TenureQuotaRecalculator re-evaluates tenure-based leave quotas for a company
+type TenureQuotaRecalculator interface {
+	RecalculateTenureQuotas(ctx context.Context, companyID string, asOf time.Time) (int, error)
+}
+
+// LeaveApprovalEscalator escalates leave requests waiting past their company's approval SLA
+type LeaveApprovalEscalator interface {
+	EscalateStaleApprovals(ctx context.Context) (int, error)
+}
+
+// LeaveJobs contains leave-related cron jobs
+type LeaveJobs struct {
+	quotaRecalculator TenureQuotaRecalculator
+	escalator         LeaveApprovalEscalator
+	db                *database.DB
+}
+
+// NewLeaveJobs creates leave cron jobs
+func NewLeaveJobs(quotaRecalculator TenureQuotaRecalculator, escalator LeaveApprovalEscalator, db *database.DB) *LeaveJobs {
+	return &LeaveJobs{
+		quotaRecalculator: quotaRecalculator,
+		escalator:         escalator,
+		db:                db,
+	}
+}
+
+// RegisterJobs registers all leave-related cron jobs
+func (j *LeaveJobs) RegisterJobs(scheduler *Scheduler) {
+	// Upgrade tenure-based quotas once a day (check every hour)
+	scheduler.AddJob("recalculate_tenure_quotas", 1*time.Hour, j.RecalculateTenureQuotas)
+	// SLAs are set in hours, so stale approvals are escalated every hour
+	scheduler.AddJob("escalate_stale_leave_approvals", 1*time.Hour, j.EscalateStaleApprovals)
+}
+
+// EscalateStaleApprovals escalates leave requests that outlived their company's approval SLA
+func (j *LeaveJobs) EscalateStaleApprovals(ctx context.Context) error {
+	escalated, err := j.escalator.EscalateStaleApprovals(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to escalate stale leave approvals: %w", err)
+	}
+
+	if escalated > 0 {
+		slog.Info("Cron: Escalated stale leave approvals", "escalated", escalated)
+	}
+	return nil
+}
+
+// RecalculateTenureQuotas gives employees who passed a tenure threshold the quota of their new tier
+func (j *LeaveJobs) RecalculateTenureQuotas(ctx context.Context) error {
+	// Only run at 01:00-01:59 UTC, after the midnight attendance jobs
+	if time.Now().UTC().Hour() != 1 {
+		return nil
+	}
+
+	slog.Info("Cron: Starting tenure quota recalculation job")
+
+	rows, err := j.db.Pool.Query(ctx, `
+		SELECT DISTINCT company_id FROM employees
+		WHERE employment_status = 'active' AND deleted_at IS NULL
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to get companies: %w", err)
+	}
+	defer rows.Close()
+
+	var companyIDs []string
+	for rows.Next() {
+		var companyID string
+		if err := rows.Scan(&companyID); err != nil {
+			continue
+		}
+		companyIDs = append(companyIDs, companyID)
+	}
+
+	asOf := time.Now().UTC()
+	totalAdjusted := 0
+	for _, companyID := range companyIDs {
+		adjusted, err := j.quotaRecalculator.RecalculateTenureQuotas(ctx, companyID, asOf)
+		if err != nil {
+			slog.Error("Cron: Failed to recalculate tenure quotas", "company_id", companyID, "error", err)
+			continue
+		}
+		totalAdjusted += adjusted
+	}
+
+	slog.Info("Cron: Tenure quota recalculation completed", "adjusted", totalAdjusted)
+	return nil
+}
@@ -0,0 +1,39 @@
+package cron
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// OutboxDeliverer delivers notifications recorded in the outbox
+type OutboxDeliverer interface {
+	DeliverOutbox(ctx context.Context) (int, error)
+}
+
+// NotificationJobs contains notification-related cron jobs
+type NotificationJobs struct {
+	outbox OutboxDeliverer
+}
+
+// NewNotificationJobs creates notification cron jobs
+func NewNotificationJobs(outbox OutboxDeliverer) *NotificationJobs {
+	return &NotificationJobs{outbox: outbox}
+}
+
+// RegisterJobs registers all notification-related cron jobs
+func (j *NotificationJobs) RegisterJobs(scheduler *Scheduler) {
+	scheduler.AddJob("deliver_notification_outbox", 15*time.Second, j.DeliverOutbox)
+}
+
+// DeliverOutbox sends committed outbox notifications and retries earlier failures that are due
+func (j *NotificationJobs) DeliverOutbox(ctx context.Context) error {
+	delivered, err := j.outbox.DeliverOutbox(ctx)
+	if err != nil {
+		return err
+	}
+	if delivered > 0 {
+		slog.Info("Cron: Delivered outbox notifications", "count", delivered)
+	}
+	return nil
+}
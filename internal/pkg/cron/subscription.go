@@ -41,6 +41,20 @@ func (j *SubscriptionJobs) RegisterJobs(scheduler *Scheduler) {
 		1*time.Hour,
 		j.ApplyPendingDowngrades,
 	)
+
+	// Send trial ending reminders (check every hour)
+	scheduler.AddJob(
+		"notify_expiring_trials",
+		1*time.Hour,
+		j.NotifyExpiringTrials,
+	)
+
+	// Reconcile active employees against paid seats every 6 hours
+	scheduler.AddJob(
+		"reconcile_seats",
+		6*time.Hour,
+		j.ReconcileSeats,
+	)
 }
 
 // UpdateExpiredSubscriptions updates subscription statuses
@@ -59,3 +73,14 @@ func (j *SubscriptionJobs) CleanupStaleInvoices(ctx context.Context) error {
 func (j *SubscriptionJobs) ApplyPendingDowngrades(ctx context.Context) error {
 	return j.subscriptionService.ApplyPendingDowngrades(ctx)
 }
+
+// NotifyExpiringTrials reminds owners before their trial ends
+func (j *SubscriptionJobs) NotifyExpiringTrials(ctx context.Context) error {
+	return j.subscriptionService.NotifyExpiringTrials(ctx)
+}
+
+// ReconcileSeats reports companies with more active employees than paid seats
+func (j *SubscriptionJobs) ReconcileSeats(ctx context.Context) error {
+	_, err := j.subscriptionService.ReconcileSeats(ctx)
+	return err
+}
@@ -0,0 +1,241 @@
+// Package dbtest runs a fake PostgreSQL server in-process so code that opens transactions
+// through database.DB can be tested without a database. It speaks the wire protocol to a real
+// pgx pool, answers transaction control itself and hands every other statement to a Handler.
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Handler answers one statement. Arguments arrive inlined in sql because the pool uses the
+// simple protocol. Returning the zero Result answers with an empty result.
+type Handler func(sql string) Result
+
+// Result is the server's answer to a statement
+type Result struct {
+	Columns []string
+	Rows    [][]any         // string, int, int64, float64, bool, time.Time or nil
+	Tag     string          // Command tag such as "UPDATE 1"; derived from the statement when empty
+	Err     *pgconn.PgError // Sent instead of the result; inside a transaction it aborts it
+}
+
+// Server records the statements it received
+type Server struct {
+	handler Handler
+
+	mu         sync.Mutex
+	statements []string
+}
+
+// Statements returns every statement received so far, transaction control included, in order
+func (s *Server) Statements() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.statements...)
+}
+
+// NewDB returns a database.DB whose connections are served by a fake server calling handler,
+// which may be nil when only transaction control is expected. The pool is closed with the test.
+func NewDB(t testing.TB, handler Handler) (*database.DB, *Server) {
+	t.Helper()
+
+	server := &Server{handler: handler}
+
+	config, err := pgxpool.ParseConfig("postgres://test@dbtest/test?sslmode=disable")
+	if err != nil {
+		t.Fatalf("dbtest: parse config: %v", err)
+	}
+	config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+	config.ConnConfig.LookupFunc = func(_ context.Context, host string) ([]string, error) {
+		return []string{host}, nil
+	}
+	config.ConnConfig.DialFunc = func(context.Context, string, string) (net.Conn, error) {
+		client, backend := net.Pipe()
+		go server.serve(backend)
+		return client, nil
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		t.Fatalf("dbtest: open pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return &database.DB{Pool: pool}, server
+}
+
+// serve speaks the backend side of one connection until the client terminates it
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	backend := pgproto3.NewBackend(conn, conn)
+
+	if _, err := backend.ReceiveStartupMessage(); err != nil {
+		return
+	}
+	backend.Send(&pgproto3.AuthenticationOk{})
+	// pgx refuses simple protocol queries without these two
+	backend.Send(&pgproto3.ParameterStatus{Name: "standard_conforming_strings", Value: "on"})
+	backend.Send(&pgproto3.ParameterStatus{Name: "client_encoding", Value: "UTF8"})
+	backend.Send(&pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 1})
+	backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+	if err := backend.Flush(); err != nil {
+		return
+	}
+
+	txStatus := byte('I')
+	for {
+		msg, err := backend.Receive()
+		if err != nil {
+			return
+		}
+		query, ok := msg.(*pgproto3.Query)
+		if !ok {
+			// Terminate, or a message the fake does not support
+			return
+		}
+
+		txStatus = s.answer(backend, query.String, txStatus)
+		backend.Send(&pgproto3.ReadyForQuery{TxStatus: txStatus})
+		if err := backend.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// answer sends the result of one statement and returns the transaction status that follows it.
+// Like PostgreSQL, a failed transaction rejects everything until it is rolled back, and its
+// COMMIT rolls back instead.
+func (s *Server) answer(backend *pgproto3.Backend, sql string, txStatus byte) byte {
+	s.mu.Lock()
+	s.statements = append(s.statements, sql)
+	s.mu.Unlock()
+
+	keyword := strings.ToUpper(strings.Fields(sql + " ")[0])
+	rollbackTo := keyword == "ROLLBACK" && strings.Contains(strings.ToUpper(sql), " TO ")
+
+	if txStatus == 'E' && keyword != "ROLLBACK" && keyword != "COMMIT" {
+		sendError(backend, &pgconn.PgError{Code: "25P02", Message: "current transaction is aborted, commands ignored until end of transaction block"})
+		return 'E'
+	}
+
+	switch {
+	case keyword == "BEGIN":
+		backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("BEGIN")})
+		return 'T'
+	case keyword == "COMMIT" && txStatus == 'E':
+		backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("ROLLBACK")})
+		return 'I'
+	case rollbackTo:
+		backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("ROLLBACK")})
+		return 'T'
+	case keyword == "ROLLBACK":
+		backend.Send(&pgproto3.CommandComplete{CommandTag: []byte("ROLLBACK")})
+		return 'I'
+	case keyword == "SAVEPOINT" || keyword == "RELEASE":
+		backend.Send(&pgproto3.CommandComplete{CommandTag: []byte(keyword)})
+		return txStatus
+	}
+
+	var result Result
+	if s.handler != nil {
+		result = s.handler(sql)
+	}
+
+	if result.Err != nil {
+		sendError(backend, result.Err)
+		if txStatus == 'T' {
+			return 'E'
+		}
+		return txStatus
+	}
+
+	if result.Columns != nil {
+		fields := make([]pgproto3.FieldDescription, len(result.Columns))
+		for i, name := range result.Columns {
+			fields[i] = pgproto3.FieldDescription{Name: []byte(name), DataTypeOID: columnOID(result.Rows, i), DataTypeSize: -1, TypeModifier: -1}
+		}
+		backend.Send(&pgproto3.RowDescription{Fields: fields})
+		for _, row := range result.Rows {
+			values := make([][]byte, len(row))
+			for i, v := range row {
+				values[i] = encodeText(v)
+			}
+			backend.Send(&pgproto3.DataRow{Values: values})
+		}
+	}
+
+	tag := result.Tag
+	if tag == "" {
+		tag = defaultTag(keyword, len(result.Rows))
+	}
+	backend.Send(&pgproto3.CommandComplete{CommandTag: []byte(tag)})
+
+	if keyword == "COMMIT" {
+		return 'I'
+	}
+	return txStatus
+}
+
+func sendError(backend *pgproto3.Backend, pgErr *pgconn.PgError) {
+	backend.Send(&pgproto3.ErrorResponse{Severity: "ERROR", Code: pgErr.Code, Message: pgErr.Message, ConstraintName: pgErr.ConstraintName})
+}
+
+func defaultTag(keyword string, rows int) string {
+	switch keyword {
+	case "SELECT", "WITH", "UPDATE", "DELETE":
+		return fmt.Sprintf("%s %d", keyword, rows)
+	case "INSERT":
+		return fmt.Sprintf("INSERT 0 %d", rows)
+	}
+	return keyword
+}
+
+// columnOID picks the column type from its first non-nil value
+func columnOID(rows [][]any, col int) uint32 {
+	for _, row := range rows {
+		switch row[col].(type) {
+		case nil:
+			continue
+		case int, int64:
+			return pgtype.Int8OID
+		case float64:
+			return pgtype.Float8OID
+		case bool:
+			return pgtype.BoolOID
+		case time.Time:
+			return pgtype.TimestamptzOID
+		default:
+			return pgtype.TextOID
+		}
+	}
+	return pgtype.TextOID
+}
+
+func encodeText(v any) []byte {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case bool:
+		if v {
+			return []byte("t")
+		}
+		return []byte("f")
+	case time.Time:
+		return []byte(v.Format("2006-01-02 15:04:05.999999Z07:00"))
+	default:
+		return []byte(fmt.Sprint(v))
+	}
+}
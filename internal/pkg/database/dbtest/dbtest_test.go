@@ -0,0 +1,64 @@
+package dbtest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestNewDBServesQueriesAndTransactions(t *testing.T) {
+	createdAt := time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC)
+	db, server := NewDB(t, func(sql string) Result {
+		switch {
+		case strings.HasPrefix(sql, "SELECT"):
+			return Result{Columns: []string{"id", "seats", "active", "created_at", "note"}, Rows: [][]any{{"sub-1", 5, true, createdAt, nil}}}
+		case strings.HasPrefix(sql, "UPDATE"):
+			return Result{Err: &pgconn.PgError{Code: "40001", Message: "could not serialize access"}}
+		}
+		return Result{}
+	})
+	ctx := context.Background()
+
+	var (
+		id      string
+		seats   int
+		active  bool
+		created time.Time
+		note    *string
+	)
+	if err := db.QueryRow(ctx, "SELECT id, seats, active, created_at, note FROM subscriptions WHERE id = $1", "sub-1").Scan(&id, &seats, &active, &created, &note); err != nil {
+		t.Fatalf("QueryRow() error = %v", err)
+	}
+	if id != "sub-1" || seats != 5 || !active || !created.Equal(createdAt) || note != nil {
+		t.Errorf("row = %q %d %v %v %v", id, seats, active, created, note)
+	}
+
+	// A failed statement aborts the transaction, so its commit rolls back
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx() error = %v", err)
+	}
+	var pgErr *pgconn.PgError
+	if _, err := tx.Exec(ctx, "UPDATE subscriptions SET seats = $1", 6); !errors.As(err, &pgErr) || pgErr.Code != "40001" {
+		t.Fatalf("Exec() error = %v, want 40001", err)
+	}
+	if _, err := tx.Exec(ctx, "SELECT 1"); !errors.As(err, &pgErr) || pgErr.Code != "25P02" {
+		t.Errorf("Exec() after the failure error = %v, want 25P02", err)
+	}
+	if err := tx.Commit(ctx); !errors.Is(err, pgx.ErrTxCommitRollback) {
+		t.Errorf("Commit() error = %v, want %v", err, pgx.ErrTxCommitRollback)
+	}
+
+	statements := server.Statements()
+	if got := statements[len(statements)-1]; !strings.EqualFold(got, "commit") {
+		t.Errorf("last statement = %q, want commit", got)
+	}
+	if !strings.Contains(statements[0], "'sub-1'") {
+		t.Errorf("first statement = %q, want the argument inlined", statements[0])
+	}
+}
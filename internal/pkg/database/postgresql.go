@@ -2,6 +2,10 @@ package database
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -10,15 +14,45 @@ import (
 
 type DB struct {
 	*pgxpool.Pool
+	// TxRetry bounds how often a retryable transaction is re-run after a serialization failure
+	TxRetry TxRetryPolicy
+	// ReportStatementTimeout bounds heavy aggregate queries such as dashboards and payroll summaries
+	ReportStatementTimeout time.Duration
 }
 
-func NewPostgreSQLDB(dsn string) (*DB, error) {
+// ErrStatementTimeout reports a query cancelled because it ran longer than its statement timeout
+var ErrStatementTimeout = errors.New("query exceeded its statement timeout")
+
+// IsStatementTimeout reports whether err is a query cancelled by statement_timeout.
+// Cancellations caused by the caller's context (query_canceled "due to user request") are not timeouts.
+func IsStatementTimeout(err error) bool {
+	if errors.Is(err, ErrStatementTimeout) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "57014" && strings.Contains(pgErr.Message, "statement timeout")
+}
+
+// TxRetryPolicy configures retries of transactions aborted by serialization failures or deadlocks
+type TxRetryPolicy struct {
+	MaxRetries int           // Retries after the first attempt (0 = no retry)
+	BaseDelay  time.Duration // Backoff before the first retry, doubled on each one
+	MaxDelay   time.Duration // Upper bound of a single backoff
+}
+
+// NewPostgreSQLDB connects the pool. statementTimeout is set as every connection's
+// statement_timeout, so no query can hold a connection longer (0 = no limit).
+func NewPostgreSQLDB(dsn string, statementTimeout time.Duration) (*DB, error) {
 	config, err := pgxpool.ParseConfig(dsn)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if statementTimeout > 0 {
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(statementTimeout.Milliseconds(), 10)
+	}
+
 	// Connection pool settings
 	config.MaxConns = 25
 	config.MinConns = 5
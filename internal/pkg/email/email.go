@@ -21,6 +21,10 @@ const maxRetries = 3
 type EmailService interface {
 	SendInvitation(to, employeeName, inviterName, companyName string, positionName *string, invitationLink, expiresAt string) error
 	SendPasswordReset(to, resetLink, expiresAt string) error
+	SendTrialReminder(to, recipientName, companyName string, daysLeft int, trialEndsAt, checkoutLink string) error
+	SendSubscriptionActivated(to, recipientName, companyName, planName, periodEnd string) error
+	SendInvoiceExpired(to, recipientName, companyName, invoiceNumber, planName, amount, checkoutLink string) error
+	SendPaymentFailed(to, recipientName, companyName, invoiceNumber, planName, amount, checkoutLink string) error
 }
 
 type emailServiceImpl struct {
@@ -92,6 +96,103 @@ func (s *emailServiceImpl) SendPasswordReset(to, resetLink, expiresAt string) er
 	return s.sendHTML(to, "Reset Password", body.String())
 }
 
+type trialReminderEmailData struct {
+	RecipientName string
+	CompanyName   string
+	DaysLeft      int
+	TrialEndsAt   string
+	CheckoutLink  string
+}
+
+// SendTrialReminder reminds a company owner that the trial is about to end
+func (s *emailServiceImpl) SendTrialReminder(to, recipientName, companyName string, daysLeft int, trialEndsAt, checkoutLink string) error {
+	data := trialReminderEmailData{
+		RecipientName: recipientName,
+		CompanyName:   companyName,
+		DaysLeft:      daysLeft,
+		TrialEndsAt:   trialEndsAt,
+		CheckoutLink:  checkoutLink,
+	}
+
+	var body bytes.Buffer
+	if err := s.templates.ExecuteTemplate(&body, "trial_reminder.html", data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return s.sendHTML(to, fmt.Sprintf("Masa Trial %s Berakhir dalam %d Hari", companyName, daysLeft), body.String())
+}
+
+type subscriptionActivatedEmailData struct {
+	RecipientName string
+	CompanyName   string
+	PlanName      string
+	PeriodEnd     string
+}
+
+// SendSubscriptionActivated confirms that a trial has been converted to a paid subscription
+func (s *emailServiceImpl) SendSubscriptionActivated(to, recipientName, companyName, planName, periodEnd string) error {
+	data := subscriptionActivatedEmailData{
+		RecipientName: recipientName,
+		CompanyName:   companyName,
+		PlanName:      planName,
+		PeriodEnd:     periodEnd,
+	}
+
+	var body bytes.Buffer
+	if err := s.templates.ExecuteTemplate(&body, "subscription_activated.html", data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return s.sendHTML(to, fmt.Sprintf("Langganan %s Telah Aktif", companyName), body.String())
+}
+
+type dunningEmailData struct {
+	RecipientName string
+	CompanyName   string
+	InvoiceNumber string
+	PlanName      string
+	Amount        string
+	CheckoutLink  string
+}
+
+// SendInvoiceExpired tells a company owner that an unpaid invoice expired and a new checkout is needed
+func (s *emailServiceImpl) SendInvoiceExpired(to, recipientName, companyName, invoiceNumber, planName, amount, checkoutLink string) error {
+	data := dunningEmailData{
+		RecipientName: recipientName,
+		CompanyName:   companyName,
+		InvoiceNumber: invoiceNumber,
+		PlanName:      planName,
+		Amount:        amount,
+		CheckoutLink:  checkoutLink,
+	}
+
+	var body bytes.Buffer
+	if err := s.templates.ExecuteTemplate(&body, "invoice_expired.html", data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return s.sendHTML(to, fmt.Sprintf("Tagihan %s Telah Kedaluwarsa", invoiceNumber), body.String())
+}
+
+// SendPaymentFailed tells a company owner that a payment attempt failed and asks them to retry
+func (s *emailServiceImpl) SendPaymentFailed(to, recipientName, companyName, invoiceNumber, planName, amount, checkoutLink string) error {
+	data := dunningEmailData{
+		RecipientName: recipientName,
+		CompanyName:   companyName,
+		InvoiceNumber: invoiceNumber,
+		PlanName:      planName,
+		Amount:        amount,
+		CheckoutLink:  checkoutLink,
+	}
+
+	var body bytes.Buffer
+	if err := s.templates.ExecuteTemplate(&body, "payment_failed.html", data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return s.sendHTML(to, fmt.Sprintf("Pembayaran Tagihan %s Gagal", invoiceNumber), body.String())
+}
+
 func (s *emailServiceImpl) sendHTML(to, subject, htmlBody string) error {
 	// Skip sending if SMTP is not configured
 	if s.cfg.Host == "" {
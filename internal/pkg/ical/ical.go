@@ -0,0 +1,79 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+// Event is an all-day calendar event. End is inclusive; the exclusive DTEND required by
+// RFC 5545 for date values is derived when encoding.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time
+}
+
+// Encode renders events as a VCALENDAR document
+func Encode(calendarName string, events []Event) []byte {
+	var buf bytes.Buffer
+
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:-//cmlabs//HRIS//EN")
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	writeLine(&buf, "X-WR-CALNAME:"+escapeText(calendarName))
+
+	for _, e := range events {
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+e.UID)
+		writeLine(&buf, "DTSTAMP:"+e.Stamp.UTC().Format("20060102T150405Z"))
+		writeLine(&buf, "DTSTART;VALUE=DATE:"+e.Start.Format("20060102"))
+		writeLine(&buf, "DTEND;VALUE=DATE:"+e.End.AddDate(0, 0, 1).Format("20060102"))
+		writeLine(&buf, "SUMMARY:"+escapeText(e.Summary))
+		if e.Description != "" {
+			writeLine(&buf, "DESCRIPTION:"+escapeText(e.Description))
+		}
+		writeLine(&buf, "TRANSP:OPAQUE")
+		writeLine(&buf, "END:VEVENT")
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+
+	return buf.Bytes()
+}
+
+// writeLine writes a CRLF-terminated content line, folding it so no line exceeds 75 octets.
+// Continuation lines start with a space, which counts towards their 75.
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		// Never split a multi-byte UTF-8 sequence
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
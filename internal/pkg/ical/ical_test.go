@@ -0,0 +1,46 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEncodeFoldsLinesWithinLimit(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		summary string
+	}{
+		{"long ASCII", strings.Repeat("Annual leave ", 40)},
+		{"long two-byte", strings.Repeat("Cuti tahunan é ", 40)},
+		{"long three-byte", strings.Repeat("年次休暇", 60)},
+		{"long four-byte", strings.Repeat("🌴", 80)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := string(Encode("Leave", []Event{{UID: "leave-1", Summary: tt.summary, Start: day, End: day, Stamp: day}}))
+
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			var unfolded strings.Builder
+			for i, line := range lines {
+				if len(line) > 75 {
+					t.Errorf("line %d is %d octets, want at most 75: %q", i, len(line), line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 sequence: %q", i, line)
+				}
+				if strings.HasPrefix(line, " ") {
+					unfolded.WriteString(line[1:])
+				} else {
+					unfolded.WriteString("\r\n" + line)
+				}
+			}
+			if !strings.Contains(unfolded.String(), "\r\nSUMMARY:"+escapeText(tt.summary)+"\r\n") {
+				t.Errorf("unfolded output lost the summary:\n%s", unfolded.String())
+			}
+		})
+	}
+}
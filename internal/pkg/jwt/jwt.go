@@ -21,6 +21,8 @@ type Service interface {
 	GenerateRefreshToken(userID string) (token string, expiresAt int64, err error)
 	GenerateSSEToken(userID string) (token string, expiresIn int, err error)
 	ValidateSSEToken(tokenString string) (userID string, err error)
+	GenerateCalendarFeedToken(employeeID string, nonce string) (token string, err error)
+	ValidateCalendarFeedToken(tokenString string) (employeeID string, nonce string, err error)
 	JWTAuth() *jwtauth.JWTAuth
 	RefreshTokenCookie(token string, expiresAt int64) *http.Cookie
 	RevokeToken(token string)
@@ -172,3 +174,52 @@ func (j *JWTService) ValidateSSEToken(tokenString string) (userID string, err er
 
 	return userID, nil
 }
+
+// GenerateCalendarFeedToken generates a non-expiring token for the leave iCal feed.
+// The token is revoked by rotating the employee's nonce.
+func (j *JWTService) GenerateCalendarFeedToken(employeeID string, nonce string) (token string, err error) {
+	_, tokenString, err := j.tokenAuth.Encode(map[string]interface{}{
+		"employee_id": employeeID,
+		"nonce":       nonce,
+		"type":        "calendar",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+// ValidateCalendarFeedToken validates a calendar feed token and returns the employee ID and nonce
+func (j *JWTService) ValidateCalendarFeedToken(tokenString string) (employeeID string, nonce string, err error) {
+	token, err := j.tokenAuth.Decode(tokenString)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Check token type
+	tokenType, ok := token.Get("type")
+	if !ok || tokenType != "calendar" {
+		return "", "", jwt.ErrInvalidJWT()
+	}
+
+	employeeIDVal, ok := token.Get("employee_id")
+	if !ok {
+		return "", "", jwt.ErrInvalidJWT()
+	}
+	employeeID, ok = employeeIDVal.(string)
+	if !ok || employeeID == "" {
+		return "", "", jwt.ErrInvalidJWT()
+	}
+
+	nonceVal, ok := token.Get("nonce")
+	if !ok {
+		return "", "", jwt.ErrInvalidJWT()
+	}
+	nonce, ok = nonceVal.(string)
+	if !ok || nonce == "" {
+		return "", "", jwt.ErrInvalidJWT()
+	}
+
+	return employeeID, nonce, nil
+}
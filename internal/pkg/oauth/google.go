@@ -2,22 +2,14 @@ package oauth
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/base64"
 	"encoding/json"
-	"fmt"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
 
 type GoogleService interface {
-	// GenerateState generates a random state string for OAuth2 flows.
-	GenerateState(userAgent string) string
-	// RedirectURL generates the OAuth2 redirect URL with a state.
-	RedirectURL(state string) string
-	// VerifyToken exchanges the code for an OAuth2 token.
-	VerifyToken(ctx context.Context, code string) (*oauth2.Token, error)
+	Provider
 	// VerifyUser fetches and verifies the Google user information.
 	VerifyUser(ctx context.Context, token *oauth2.Token) (GoogleInformation, error)
 }
@@ -43,15 +35,14 @@ type GoogleInformation struct {
 	VerifiedEmail bool   `json:"verified_email"`
 }
 
+// Name implements Provider.
+func (g *GoogleServiceImpl) Name() string {
+	return ProviderGoogle
+}
+
 // GenerateState generates a random state string for OAuth2 flows.
 func (g *GoogleServiceImpl) GenerateState(userAgent string) string {
-	b := make([]byte, 32)
-	_, err := rand.Read(b)
-	if err != nil {
-		return ""
-	}
-	state := fmt.Sprintf("%s.%s", base64.URLEncoding.EncodeToString(b), userAgent)
-	return base64.URLEncoding.EncodeToString([]byte(state))
+	return generateState(userAgent)
 }
 
 func (g *GoogleServiceImpl) RedirectURL(state string) string {
@@ -83,3 +74,18 @@ func (g *GoogleServiceImpl) VerifyUser(ctx context.Context, token *oauth2.Token)
 
 	return req, nil
 }
+
+// FetchIdentity implements Provider.
+func (g *GoogleServiceImpl) FetchIdentity(ctx context.Context, token *oauth2.Token) (Identity, error) {
+	info, err := g.VerifyUser(ctx, token)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	return Identity{
+		Provider:      ProviderGoogle,
+		Subject:       info.GoogleID,
+		Email:         info.Email,
+		EmailVerified: info.VerifiedEmail,
+	}, nil
+}
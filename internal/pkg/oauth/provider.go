@@ -0,0 +1,49 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"golang.org/x/oauth2"
+)
+
+// Provider names
+const (
+	ProviderGoogle    = "google"
+	ProviderMicrosoft = "microsoft"
+)
+
+// Provider is an OAuth2 login provider
+type Provider interface {
+	// Name returns the provider name used in routes and stored on users (e.g. "google").
+	Name() string
+	// GenerateState generates a random state string for OAuth2 flows.
+	GenerateState(userAgent string) string
+	// RedirectURL generates the OAuth2 redirect URL with a state.
+	RedirectURL(state string) string
+	// VerifyToken exchanges the code for an OAuth2 token.
+	VerifyToken(ctx context.Context, code string) (*oauth2.Token, error)
+	// FetchIdentity fetches the external identity behind the token.
+	FetchIdentity(ctx context.Context, token *oauth2.Token) (Identity, error)
+}
+
+// Identity is a user identity asserted by an OAuth2 provider
+type Identity struct {
+	Provider      string
+	Subject       string // Stable provider user ID
+	Email         string
+	EmailVerified bool
+}
+
+// generateState returns a random, URL-safe state bound to the user agent
+func generateState(userAgent string) string {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return ""
+	}
+	state := fmt.Sprintf("%s.%s", base64.URLEncoding.EncodeToString(b), userAgent)
+	return base64.URLEncoding.EncodeToString([]byte(state))
+}
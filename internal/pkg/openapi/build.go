@@ -0,0 +1,231 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Route documents the bodies of one operation. Routes registered on the router but
+// missing from Config.Routes are still listed, with a generic envelope as response.
+type Route struct {
+	Summary  string
+	Query    any                // Struct whose json fields are the query parameters
+	Request  any                // JSON request body
+	Form     map[string]*Schema // multipart/form-data fields, for uploads
+	Response any                // Value of the envelope's data field
+	Status   int                // Success status, 200 when zero
+	Produces string             // Content type of a non-JSON response, e.g. text/csv
+}
+
+type Config struct {
+	Info     Info
+	BasePath string           // Only routes under this prefix are documented; it becomes the server URL
+	Routes   map[string]Route // Keyed by "METHOD /path", path relative to BasePath
+	Envelope any              // Wrapper of every JSON response; Route.Response goes in its data field
+}
+
+var pathParam = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// Build walks the router and describes every route under cfg.BasePath. It fails when
+// cfg.Routes documents a route the router does not register.
+func Build(router chi.Routes, cfg Config) (Document, error) {
+	reflector := NewReflector()
+	envelope := reflector.Schema(cfg.Envelope)
+
+	doc := Document{
+		OpenAPI: "3.0.3",
+		Info:    cfg.Info,
+		Servers: []Server{{URL: cfg.BasePath}},
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	tags := make(map[string]bool)
+	operationIDs := make(map[string]bool)
+	registered := make(map[string]bool)
+
+	err := chi.Walk(router, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if !strings.HasPrefix(route, cfg.BasePath+"/") || strings.Contains(route, "*") {
+			return nil
+		}
+
+		p := strings.TrimSuffix(strings.TrimPrefix(route, cfg.BasePath), "/")
+		p = pathParam.ReplaceAllString(p, "{$1}")
+		if p == "" {
+			p = "/"
+		}
+
+		op := &Operation{
+			OperationID: operationID(handler, operationIDs),
+			Tags:        []string{tagFor(p)},
+			Responses:   make(map[string]Response),
+		}
+		tags[op.Tags[0]] = true
+
+		for _, match := range pathParam.FindAllStringSubmatch(p, -1) {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"},
+			})
+		}
+
+		var notes []string
+		for _, mw := range middlewares {
+			name := funcName(mw)
+			switch {
+			case strings.Contains(name, ".AuthRequired"):
+				op.Security = []map[string][]string{{"bearerAuth": {}}}
+			case strings.HasSuffix(name, ".RequireOwner"):
+				notes = append(notes, "Owner only.")
+			case strings.HasSuffix(name, ".RequireManager"):
+				notes = append(notes, "Owner or manager only.")
+			case strings.Contains(name, ".RequirePermission"):
+				notes = append(notes, "Requires a role permission.")
+			case strings.Contains(name, ".RequireFeature"):
+				notes = append(notes, "Requires a subscription feature.")
+			}
+		}
+		op.Description = strings.Join(notes, " ")
+
+		registered[method+" "+p] = true
+		spec := cfg.Routes[method+" "+p]
+		op.Summary = spec.Summary
+
+		if spec.Query != nil {
+			query := reflector.Object(spec.Query)
+			names := make([]string, 0, len(query.Properties))
+			for name := range query.Properties {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				schema := query.Properties[name]
+				schema.Nullable = false
+				op.Parameters = append(op.Parameters, Parameter{Name: name, In: "query", Schema: schema})
+			}
+		}
+
+		switch {
+		case spec.Form != nil:
+			op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+				"multipart/form-data": {Schema: &Schema{Type: "object", Properties: spec.Form}},
+			}}
+		case spec.Request != nil:
+			op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+				"application/json": {Schema: reflector.Schema(spec.Request)},
+			}}
+		}
+
+		status := spec.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := Response{Description: http.StatusText(status)}
+		switch {
+		case spec.Produces != "":
+			success.Content = map[string]MediaType{spec.Produces: {Schema: Binary()}}
+		case spec.Response != nil:
+			success.Content = map[string]MediaType{"application/json": {Schema: &Schema{AllOf: []*Schema{
+				envelope,
+				{Type: "object", Properties: map[string]*Schema{"data": reflector.Schema(spec.Response)}},
+			}}}}
+		default:
+			success.Content = map[string]MediaType{"application/json": {Schema: envelope}}
+		}
+		op.Responses[strconv.Itoa(status)] = success
+		op.Responses["default"] = Response{
+			Description: "Error",
+			Content:     map[string]MediaType{"application/json": {Schema: envelope}},
+		}
+
+		item, ok := doc.Paths[p]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[p] = item
+		}
+		item[strings.ToLower(method)] = op
+		return nil
+	})
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to walk routes: %w", err)
+	}
+
+	// A documented route that is not mounted would silently drop its bodies from the document
+	var stale []string
+	for key := range cfg.Routes {
+		if !registered[key] {
+			stale = append(stale, key)
+		}
+	}
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		return Document{}, fmt.Errorf("documented routes are not registered: %s", strings.Join(stale, ", "))
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+
+	doc.Components.Schemas = reflector.Schemas()
+	return doc, nil
+}
+
+// operationID derives a unique id from the handler method, e.g. LeaveHandlerImpl.CreateType
+// becomes leaveCreateType
+func operationID(handler http.Handler, seen map[string]bool) string {
+	name := funcName(handler)
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.TrimSuffix(name, "-fm")
+
+	parts := strings.Split(name, ".")
+	method := parts[len(parts)-1]
+	receiver := ""
+	if len(parts) >= 3 {
+		receiver = strings.Trim(parts[len(parts)-2], "(*)")
+		receiver = strings.TrimSuffix(receiver, "Impl")
+		receiver = strings.TrimSuffix(receiver, "Handler")
+	}
+
+	id := method
+	if receiver != "" {
+		id = strings.ToLower(receiver[:1]) + receiver[1:] + method
+	}
+	for base, n := id, 2; seen[id]; n++ {
+		id = base + strconv.Itoa(n)
+	}
+	seen[id] = true
+	return id
+}
+
+// tagFor groups operations by their first path segment, e.g. /employee-schedules -> Employee Schedules
+func tagFor(p string) string {
+	segment := strings.Split(strings.TrimPrefix(p, "/"), "/")[0]
+	words := strings.Split(segment, "-")
+	for i, w := range words {
+		words[i] = exportName(w)
+	}
+	return strings.Join(words, " ")
+}
+
+func funcName(fn any) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return v.Type().String()
+	}
+	if f := runtime.FuncForPC(v.Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
+}
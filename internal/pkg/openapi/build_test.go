@@ -0,0 +1,47 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestBuildRejectsUnregisteredRoutes(t *testing.T) {
+	noop := func(http.ResponseWriter, *http.Request) {}
+	router := chi.NewRouter()
+	router.Route("/api/v1", func(r chi.Router) {
+		r.Get("/items", noop)
+		r.Post("/items/{id:[0-9]+}", noop)
+	})
+
+	tests := []struct {
+		name    string
+		routes  map[string]Route
+		wantErr string
+	}{
+		{name: "all registered", routes: map[string]Route{"GET /items": {Summary: "List"}, "POST /items/{id}": {Summary: "Update"}}},
+		{name: "undocumented routes are fine", routes: map[string]Route{}},
+		{name: "wrong method", routes: map[string]Route{"PUT /items/{id}": {}}, wantErr: "PUT /items/{id}"},
+		{name: "renamed path", routes: map[string]Route{"GET /items": {}, "GET /item": {}, "DELETE /things": {}}, wantErr: "DELETE /things, GET /item"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Build(router, Config{BasePath: "/api/v1", Routes: tt.routes, Envelope: struct{}{}})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Build() error = %v", err)
+				}
+				if len(doc.Paths) != 2 {
+					t.Errorf("Build() documented %d paths, want 2", len(doc.Paths))
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Build() error = %v, want it to name %q", err, tt.wantErr)
+			}
+		})
+	}
+}
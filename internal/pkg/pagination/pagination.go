@@ -0,0 +1,58 @@
+// Package pagination holds the page arithmetic shared by the list endpoints.
+package pagination
+
+import "fmt"
+
+// DefaultLimit is the page size used when a request does not set one
+const DefaultLimit = 20
+
+// Paginate normalizes a 1-based page and a page size and returns the OFFSET and LIMIT to query with.
+// A page below 1 means the first page and a limit below 1 means DefaultLimit.
+func Paginate(page, limit int) (offset, size int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultLimit
+	}
+	return (page - 1) * limit, limit
+}
+
+// Metadata is the paging summary of a list response
+type Metadata struct {
+	TotalPages int
+	Showing    string
+}
+
+// Meta returns the total pages and showing text of a page of total results
+func Meta(page, limit int, total int64) Metadata {
+	_, limit = Paginate(page, limit)
+	return Metadata{
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+		Showing:    Showing(page, limit, total),
+	}
+}
+
+// Showing returns the "X-Y of Z results" text of a list response.
+// An empty result set is "0 results". A page past the last one is "0 of Z results"
+// instead of a range that starts after the end.
+func Showing(page, limit int, total int64) string {
+	if total <= 0 {
+		return "0 results"
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	start := int64(page-1)*int64(limit) + 1
+	if limit <= 0 || start > total {
+		return fmt.Sprintf("0 of %d results", total)
+	}
+
+	end := start + int64(limit) - 1
+	if end > total {
+		end = total
+	}
+
+	return fmt.Sprintf("%d-%d of %d results", start, end, total)
+}
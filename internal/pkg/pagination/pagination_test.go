@@ -0,0 +1,75 @@
+package pagination
+
+import "testing"
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name        string
+		page, limit int
+		wantOffset  int
+		wantSize    int
+	}{
+		{name: "first page", page: 1, limit: 10, wantOffset: 0, wantSize: 10},
+		{name: "third page", page: 3, limit: 10, wantOffset: 20, wantSize: 10},
+		{name: "page 0 is the first page", page: 0, limit: 10, wantOffset: 0, wantSize: 10},
+		{name: "negative page is the first page", page: -2, limit: 10, wantOffset: 0, wantSize: 10},
+		{name: "limit 0 uses the default", page: 2, limit: 0, wantOffset: DefaultLimit, wantSize: DefaultLimit},
+		{name: "negative limit uses the default", page: 1, limit: -5, wantOffset: 0, wantSize: DefaultLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, size := Paginate(tt.page, tt.limit)
+			if offset != tt.wantOffset || size != tt.wantSize {
+				t.Errorf("Paginate(%d, %d) = %d, %d; want %d, %d", tt.page, tt.limit, offset, size, tt.wantOffset, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestMeta(t *testing.T) {
+	tests := []struct {
+		name        string
+		page, limit int
+		total       int64
+		want        Metadata
+	}{
+		{name: "no results", page: 1, limit: 10, total: 0, want: Metadata{TotalPages: 0, Showing: "0 results"}},
+		{name: "exact multiple", page: 3, limit: 10, total: 30, want: Metadata{TotalPages: 3, Showing: "21-30 of 30 results"}},
+		{name: "partial last page", page: 3, limit: 10, total: 25, want: Metadata{TotalPages: 3, Showing: "21-25 of 25 results"}},
+		{name: "fewer than a page", page: 1, limit: 10, total: 4, want: Metadata{TotalPages: 1, Showing: "1-4 of 4 results"}},
+		{name: "past the end", page: 4, limit: 10, total: 25, want: Metadata{TotalPages: 3, Showing: "0 of 25 results"}},
+		{name: "page 0 shows the first page", page: 0, limit: 10, total: 25, want: Metadata{TotalPages: 3, Showing: "1-10 of 25 results"}},
+		{name: "limit 0 uses the default", page: 1, limit: 0, total: 45, want: Metadata{TotalPages: 3, Showing: "1-20 of 45 results"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Meta(tt.page, tt.limit, tt.total); got != tt.want {
+				t.Errorf("Meta(%d, %d, %d) = %+v, want %+v", tt.page, tt.limit, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShowing(t *testing.T) {
+	tests := []struct {
+		name        string
+		page, limit int
+		total       int64
+		want        string
+	}{
+		{name: "negative total", page: 1, limit: 10, total: -1, want: "0 results"},
+		{name: "last item alone on its page", page: 3, limit: 10, total: 21, want: "21-21 of 21 results"},
+		{name: "limit 0", page: 1, limit: 0, total: 5, want: "0 of 5 results"},
+		{name: "large page does not overflow", page: 1 << 30, limit: 100, total: 5, want: "0 of 5 results"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Showing(tt.page, tt.limit, tt.total); got != tt.want {
+				t.Errorf("Showing(%d, %d, %d) = %q, want %q", tt.page, tt.limit, tt.total, got, tt.want)
+			}
+		})
+	}
+}
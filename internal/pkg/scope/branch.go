@@ -0,0 +1,25 @@
+package scope
+
+import "context"
+
+type branchKey struct{}
+
+// WithBranchID returns a copy of ctx restricted to the given branch
+func WithBranchID(ctx context.Context, branchID string) context.Context {
+	return context.WithValue(ctx, branchKey{}, branchID)
+}
+
+// BranchIDFromContext returns the branch the caller is restricted to, if any.
+// Callers without a branch scope (owners, company-wide managers) get ok == false.
+func BranchIDFromContext(ctx context.Context) (branchID string, ok bool) {
+	branchID, ok = ctx.Value(branchKey{}).(string)
+	return branchID, ok && branchID != ""
+}
+
+// BranchFilter returns the caller's branch scope as an optional filter value
+func BranchFilter(ctx context.Context) *string {
+	if branchID, ok := BranchIDFromContext(ctx); ok {
+		return &branchID
+	}
+	return nil
+}
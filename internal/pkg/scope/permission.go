@@ -0,0 +1,45 @@
+package scope
+
+import (
+	"context"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/go-chi/jwtauth/v5"
+)
+
+type permissionsKey struct{}
+
+// WithPermissions returns a copy of ctx carrying the caller's effective permissions
+func WithPermissions(ctx context.Context, permissions []user.Permission) context.Context {
+	set := make(map[user.Permission]struct{}, len(permissions))
+	for _, p := range permissions {
+		set[p] = struct{}{}
+	}
+	return context.WithValue(ctx, permissionsKey{}, set)
+}
+
+// Can reports whether the caller holds the given permission.
+// Permissions resolved for the request (base role plus company role) take precedence;
+// without them the base role's default permissions from the JWT are used.
+func Can(ctx context.Context, permission user.Permission) bool {
+	if set, ok := ctx.Value(permissionsKey{}).(map[user.Permission]struct{}); ok {
+		_, has := set[permission]
+		return has
+	}
+
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return false
+	}
+
+	role, _ := claims["role"].(string)
+	return user.HasPermission(user.Role(role), permission)
+}
+
+// Require returns user.ErrInsufficientPermissions unless the caller holds the permission
+func Require(ctx context.Context, permission user.Permission) error {
+	if !Can(ctx, permission) {
+		return user.ErrInsufficientPermissions
+	}
+	return nil
+}
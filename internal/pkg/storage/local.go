@@ -102,9 +102,9 @@ func (s *LocalStorage) Delete(ctx context.Context, path string) error {
 	return nil
 }
 
+// GetURL returns the static URL of the file under baseURL. Local files are served publicly,
+// so expiry is ignored and the URL never expires.
 func (s *LocalStorage) GetURL(ctx context.Context, path string, expiry time.Duration) (string, error) {
-	// For local storage, return static URL
-	// In production with auth, you might generate signed tokens
 	cleanPath := filepath.Clean(path)
 	return fmt.Sprintf("%s/%s", s.baseURL, cleanPath), nil
 }
@@ -16,7 +16,8 @@ type FileStorage interface {
 	// Delete removes a file
 	Delete(ctx context.Context, path string) error
 
-	// GetURL generates a presigned/public URL
+	// GetURL generates a presigned/public URL. Backends that sign URLs (MinIO/S3) must make the
+	// URL stop working after expiry; backends serving public URLs may ignore it.
 	GetURL(ctx context.Context, path string, expiry time.Duration) (string, error)
 
 	// Exists checks if file exists
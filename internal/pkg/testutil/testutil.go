@@ -0,0 +1,22 @@
+// Package testutil holds helpers shared by the service tests.
+package testutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+)
+
+// ClaimsContext returns a context carrying a JWT with the given claims, as the auth middleware does
+func ClaimsContext(t testing.TB, claims map[string]any) context.Context {
+	t.Helper()
+	token := jwt.New()
+	for k, v := range claims {
+		if err := token.Set(k, v); err != nil {
+			t.Fatalf("set claim %s: %v", k, err)
+		}
+	}
+	return jwtauth.NewContext(context.Background(), token, nil)
+}
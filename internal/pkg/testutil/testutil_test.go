@@ -0,0 +1,19 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/go-chi/jwtauth/v5"
+)
+
+func TestClaimsContext(t *testing.T) {
+	ctx := ClaimsContext(t, map[string]any{"company_id": "company-1", "role": "owner"})
+
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		t.Fatalf("FromContext() error = %v", err)
+	}
+	if claims["company_id"] != "company-1" || claims["role"] != "owner" {
+		t.Errorf("claims = %v, want company_id company-1 and role owner", claims)
+	}
+}
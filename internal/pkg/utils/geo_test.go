@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateHaversineDistance(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64 // meters
+		tolerance              float64
+	}{
+		{name: "same point", lat1: -6.1754, lon1: 106.8272, lat2: -6.1754, lon2: 106.8272, want: 0, tolerance: 0},
+		{name: "one degree along the equator", lat1: 0, lon1: 0, lat2: 0, lon2: 1, want: 111194.93, tolerance: 0.01},
+		{name: "one degree along a meridian", lat1: 0, lon1: 0, lat2: 1, lon2: 0, want: 111194.93, tolerance: 0.01},
+		{name: "pole to pole", lat1: 90, lon1: 0, lat2: -90, lon2: 0, want: math.Pi * 6371000, tolerance: 0.01},
+		{name: "across the antimeridian", lat1: 0, lon1: 179.5, lat2: 0, lon2: -179.5, want: 111194.93, tolerance: 0.01},
+		{name: "paris to london", lat1: 48.8566, lon1: 2.3522, lat2: 51.5074, lon2: -0.1278, want: 343556, tolerance: 1},
+		{name: "jakarta to bandung", lat1: -6.1754, lon1: 106.8272, lat2: -6.9175, lon2: 107.6191, want: 120258, tolerance: 1},
+		{name: "within an office radius", lat1: -6.1754, lon1: 106.8272, lat2: -6.1754, lon2: 106.8282, want: 110.55, tolerance: 0.01},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateHaversineDistance(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+			if math.Abs(got-tt.want) > tt.tolerance {
+				t.Errorf("CalculateHaversineDistance() = %.2f, want %.2f ± %.2f", got, tt.want, tt.tolerance)
+			}
+			if back := CalculateHaversineDistance(tt.lat2, tt.lon2, tt.lat1, tt.lon1); math.Abs(back-got) > 1e-6 {
+				t.Errorf("distance is not symmetric: %.6f there, %.6f back", got, back)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package utils
+
+import "time"
+
+// DefaultTimezone is the zone used when a company or branch has no valid timezone configured.
+const DefaultTimezone = "Asia/Jakarta"
+
+// LoadLocation resolves an IANA timezone name, falling back to DefaultTimezone and then UTC.
+func LoadLocation(name string) *time.Location {
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	if loc, err := time.LoadLocation(DefaultTimezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// IsValidTimezone reports whether name is a loadable IANA timezone.
+func IsValidTimezone(name string) bool {
+	if name == "" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// StartOfDay returns midnight of t's calendar day as observed in loc.
+// Unlike t.Truncate(24*time.Hour), the result respects the local day boundary.
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+// ParseDateInLocation parses a YYYY-MM-DD string as midnight in loc.
+func ParseDateInLocation(value string, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", value, loc)
+}
+
+// DaysBetween returns the number of calendar days from a to b, ignoring the time of day.
+// DST transitions do not affect the result.
+func DaysBetween(a, b time.Time) int {
+	aDate := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	bDate := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(bDate.Sub(aDate).Hours() / 24)
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestStartOfDay(t *testing.T) {
+	jakarta := mustLoad(t, "Asia/Jakarta")
+	newYork := mustLoad(t, "America/New_York")
+
+	tests := []struct {
+		name string
+		t    time.Time
+		loc  *time.Location
+		want time.Time
+	}{
+		{"UTC evening is the next day in Jakarta", time.Date(2026, 6, 1, 17, 30, 0, 0, time.UTC), jakarta, time.Date(2026, 6, 2, 0, 0, 0, 0, jakarta)},
+		{"UTC afternoon is the same day in Jakarta", time.Date(2026, 6, 1, 16, 30, 0, 0, time.UTC), jakarta, time.Date(2026, 6, 1, 0, 0, 0, 0, jakarta)},
+		{"local midnight is its own start", time.Date(2026, 6, 2, 0, 0, 0, 0, jakarta), jakarta, time.Date(2026, 6, 2, 0, 0, 0, 0, jakarta)},
+		{"a nanosecond before local midnight", time.Date(2026, 6, 1, 23, 59, 59, 999999999, jakarta), jakarta, time.Date(2026, 6, 1, 0, 0, 0, 0, jakarta)},
+		{"UTC early morning is the previous day in New York", time.Date(2026, 6, 2, 3, 0, 0, 0, time.UTC), newYork, time.Date(2026, 6, 1, 0, 0, 0, 0, newYork)},
+		{"DST start day in New York", time.Date(2026, 3, 8, 12, 0, 0, 0, newYork), newYork, time.Date(2026, 3, 8, 0, 0, 0, 0, newYork)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StartOfDay(tt.t, tt.loc)
+			if !got.Equal(tt.want) || got.Location() != tt.loc {
+				t.Errorf("StartOfDay() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDateInLocation(t *testing.T) {
+	jakarta := mustLoad(t, "Asia/Jakarta")
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"midnight in the zone", "2026-06-02", time.Date(2026, 6, 1, 17, 0, 0, 0, time.UTC), false},
+		{"year boundary", "2027-01-01", time.Date(2026, 12, 31, 17, 0, 0, 0, time.UTC), false},
+		{"invalid day", "2026-02-30", time.Time{}, true},
+		{"wrong layout", "02/06/2026", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDateInLocation(tt.value, jakarta)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDateInLocation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (!got.Equal(tt.want) || got.Location() != jakarta) {
+				t.Errorf("ParseDateInLocation() = %s, want %s in Asia/Jakarta", got, tt.want.In(jakarta))
+			}
+		})
+	}
+}
+
+func TestDaysBetween(t *testing.T) {
+	jakarta := mustLoad(t, "Asia/Jakarta")
+	newYork := mustLoad(t, "America/New_York")
+
+	tests := []struct {
+		name string
+		a, b time.Time
+		want int
+	}{
+		{"same day", time.Date(2026, 6, 1, 0, 0, 0, 0, jakarta), time.Date(2026, 6, 1, 23, 59, 0, 0, jakarta), 0},
+		{"across local midnight", time.Date(2026, 6, 1, 23, 30, 0, 0, jakarta), time.Date(2026, 6, 2, 0, 30, 0, 0, jakarta), 1},
+		{"backwards", time.Date(2026, 6, 10, 8, 0, 0, 0, jakarta), time.Date(2026, 6, 3, 8, 0, 0, 0, jakarta), -7},
+		{"leap day", time.Date(2028, 2, 28, 0, 0, 0, 0, jakarta), time.Date(2028, 3, 1, 0, 0, 0, 0, jakarta), 2},
+		{"over the DST change", time.Date(2026, 3, 7, 0, 0, 0, 0, newYork), time.Date(2026, 3, 9, 0, 0, 0, 0, newYork), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DaysBetween(tt.a, tt.b); got != tt.want {
+				t.Errorf("DaysBetween() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how a delivery is retried on transient failures.
+type RetryPolicy struct {
+	MaxRetries     int           // Additional attempts after the first call (0 disables retries)
+	BaseDelay      time.Duration // Delay before the first retry, doubled on every attempt
+	MaxDelay       time.Duration // Upper bound for a single backoff delay
+	RequestTimeout time.Duration // Timeout applied to each individual attempt
+}
+
+// DefaultRetryPolicy is used when the caller does not provide values.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:     4,
+	BaseDelay:      500 * time.Millisecond,
+	MaxDelay:       10 * time.Second,
+	RequestTimeout: 10 * time.Second,
+}
+
+// Backoff returns the delay before retry number attempt (0-based), capped at MaxDelay.
+// The cap is checked before shifting, so a large attempt cannot overflow into a short delay.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+	if attempt >= 63 || p.BaseDelay > p.MaxDelay>>attempt {
+		return p.MaxDelay
+	}
+	return p.BaseDelay << attempt
+}
+
+// withDefaults fills unset values from DefaultRetryPolicy
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxRetries < 0 {
+		p.MaxRetries = 0
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = DefaultRetryPolicy.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultRetryPolicy.MaxDelay
+	}
+	if p.RequestTimeout <= 0 {
+		p.RequestTimeout = DefaultRetryPolicy.RequestTimeout
+	}
+	return p
+}
+
+// isRetryableStatus reports whether a response status may succeed on a later attempt.
+// 5xx, 408 and 429 are retried; other 4xx mean the receiver rejected the payload.
+func isRetryableStatus(status int) bool {
+	return status >= http.StatusInternalServerError ||
+		status == http.StatusRequestTimeout ||
+		status == http.StatusTooManyRequests
+}
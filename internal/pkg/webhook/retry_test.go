@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 500 * time.Millisecond, MaxDelay: 10 * time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: -1, want: 500 * time.Millisecond},
+		{attempt: 0, want: 500 * time.Millisecond},
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 4, want: 8 * time.Second},
+		{attempt: 5, want: 10 * time.Second}, // 16s capped
+		{attempt: 40, want: 10 * time.Second},
+		{attempt: 63, want: 10 * time.Second},
+		{attempt: 64, want: 10 * time.Second},
+		{attempt: 1000, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := p.Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestBackoffShiftOverflow(t *testing.T) {
+	// 2^34+1 ns shifted by 30 wraps to 2^30 ns, about a second, unless the cap is checked first
+	p := RetryPolicy{BaseDelay: time.Duration(1<<34 + 1), MaxDelay: time.Hour}
+
+	if got := p.Backoff(30); got != time.Hour {
+		t.Errorf("Backoff(30) = %v, want the %v cap", got, time.Hour)
+	}
+	for attempt := 0; attempt < 100; attempt++ {
+		if got := p.Backoff(attempt); got <= 0 || got > p.MaxDelay {
+			t.Fatalf("Backoff(%d) = %v, want within (0, %v]", attempt, got, p.MaxDelay)
+		}
+	}
+}
+
+// receiver answers with the given statuses in order and records each request's headers
+type receiver struct {
+	statuses []int
+
+	mu      sync.Mutex
+	headers []http.Header
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := r.statuses[min(len(r.headers), len(r.statuses)-1)]
+	r.headers = append(r.headers, req.Header.Clone())
+	w.WriteHeader(status)
+}
+
+func TestSend(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantAttempts int
+		wantStatus   int
+		wantErr      bool
+	}{
+		{name: "delivered first time", statuses: []int{http.StatusOK}, wantAttempts: 1, wantStatus: http.StatusOK},
+		{name: "retries a server error", statuses: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusNoContent}, wantAttempts: 3, wantStatus: http.StatusNoContent},
+		{name: "retries rate limiting", statuses: []int{http.StatusTooManyRequests, http.StatusOK}, wantAttempts: 2, wantStatus: http.StatusOK},
+		{name: "stops on a client error", statuses: []int{http.StatusBadRequest, http.StatusOK}, wantAttempts: 1, wantStatus: http.StatusBadRequest, wantErr: true},
+		{name: "stops on a client error after a retry", statuses: []int{http.StatusInternalServerError, http.StatusGone, http.StatusOK}, wantAttempts: 2, wantStatus: http.StatusGone, wantErr: true},
+		{name: "gives up after max retries", statuses: []int{http.StatusInternalServerError}, wantAttempts: 4, wantStatus: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recv := &receiver{statuses: tt.statuses}
+			server := httptest.NewServer(recv)
+			defer server.Close()
+
+			sender := NewSender(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+			payload := []byte(`{"event":"payroll.finalized"}`)
+
+			result, err := sender.Send(context.Background(), Delivery{URL: server.URL, Secret: "whsec_test", Event: "payroll.finalized", Payload: payload})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var statusErr *StatusError
+			if tt.wantErr && (!errors.As(err, &statusErr) || statusErr.StatusCode != tt.wantStatus) {
+				t.Errorf("Send() error = %v, want a StatusError with %d", err, tt.wantStatus)
+			}
+			if result.Attempts != tt.wantAttempts || result.StatusCode != tt.wantStatus {
+				t.Errorf("Send() = %+v, want %d attempts ending in %d", result, tt.wantAttempts, tt.wantStatus)
+			}
+
+			if len(recv.headers) != tt.wantAttempts {
+				t.Fatalf("receiver got %d requests, want %d", len(recv.headers), tt.wantAttempts)
+			}
+			for i, h := range recv.headers {
+				if got := h.Get(DeliveryHeader); got != result.DeliveryID {
+					t.Errorf("attempt %d %s = %q, want %q", i+1, DeliveryHeader, got, result.DeliveryID)
+				}
+				if got := h.Get(EventHeader); got != "payroll.finalized" {
+					t.Errorf("attempt %d %s = %q", i+1, EventHeader, got)
+				}
+				if err := Verify("whsec_test", h.Get(SignatureHeader), payload, time.Minute, time.Now()); err != nil {
+					t.Errorf("attempt %d signature: %v", i+1, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSendWithoutSecret(t *testing.T) {
+	sender := NewSender(RetryPolicy{})
+	if _, err := sender.Send(context.Background(), Delivery{URL: "http://127.0.0.1:0"}); !errors.Is(err, ErrMissingSecret) {
+		t.Errorf("Send() error = %v, want %v", err, ErrMissingSecret)
+	}
+}
+
+func TestSendDoesNotRetryAnInvalidURL(t *testing.T) {
+	sender := NewSender(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	result, err := sender.Send(context.Background(), Delivery{URL: "http://[::1", Secret: "whsec_test", Payload: []byte(`{}`)})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Send() error = %v, want %v", err, ErrInvalidRequest)
+	}
+	if result.Attempts != 1 {
+		t.Errorf("Send() attempts = %d, want 1", result.Attempts)
+	}
+}
+
+func TestSendRetriesATransportError(t *testing.T) {
+	server := httptest.NewServer(&receiver{})
+	url := server.URL
+	server.Close() // Nothing listens any more, so every attempt fails to connect
+
+	sender := NewSender(RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	result, err := sender.Send(context.Background(), Delivery{URL: url, Secret: "whsec_test", Payload: []byte(`{}`)})
+	if err == nil || errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Send() error = %v, want a delivery failure", err)
+	}
+	if result.Attempts != 3 || result.StatusCode != 0 {
+		t.Errorf("Send() = %+v, want 3 attempts without a response", result)
+	}
+}
+
+func TestSendStopsWhenTheContextIsCancelled(t *testing.T) {
+	recv := &receiver{statuses: []int{http.StatusServiceUnavailable}}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sender := NewSender(RetryPolicy{MaxRetries: 5, BaseDelay: time.Hour, MaxDelay: time.Hour})
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	result, err := sender.Send(ctx, Delivery{URL: server.URL, Secret: "whsec_test", Payload: []byte(`{}`)})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Send() error = %v, want %v", err, context.Canceled)
+	}
+	if result.Attempts != 1 {
+		t.Errorf("Send() attempts = %d, want 1", result.Attempts)
+	}
+}
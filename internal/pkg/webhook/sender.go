@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Delivery is one outbound webhook call
+type Delivery struct {
+	URL     string
+	Secret  string // The receiving company's signing secret
+	Event   string // e.g. "payroll.finalized"
+	Payload []byte // JSON body, sent as is
+}
+
+// Result describes how a delivery went
+type Result struct {
+	DeliveryID string
+	Attempts   int
+	StatusCode int // Status of the last attempt; 0 when no response was received
+}
+
+// ErrInvalidRequest is returned when the request cannot be built, e.g. from a malformed URL.
+// No attempt can succeed, so it is never retried.
+var ErrInvalidRequest = errors.New("invalid webhook request")
+
+// StatusError is returned when the receiver answered with a non-2xx status
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook receiver responded with status %d", e.StatusCode)
+}
+
+// Sender signs and posts webhooks, retrying transient failures with exponential backoff
+type Sender struct {
+	client *http.Client
+	retry  RetryPolicy
+	now    func() time.Time
+}
+
+// NewSender creates a Sender; unset policy values fall back to DefaultRetryPolicy
+func NewSender(policy RetryPolicy) *Sender {
+	return &Sender{
+		client: &http.Client{},
+		retry:  policy.withDefaults(),
+		now:    time.Now,
+	}
+}
+
+// Send posts d.Payload to d.URL, signed with d.Secret. Every attempt is signed with its own
+// timestamp and carries the same DeliveryHeader. The error of the last attempt is returned
+// when all attempts fail, the request cannot be built or the receiver rejects the payload with
+// a non-retryable status.
+func (s *Sender) Send(ctx context.Context, d Delivery) (Result, error) {
+	result := Result{DeliveryID: uuid.NewString()}
+	if d.Secret == "" {
+		return result, ErrMissingSecret
+	}
+
+	for attempt := 0; ; attempt++ {
+		result.Attempts++
+		status, err := s.post(ctx, d, result.DeliveryID)
+		result.StatusCode = status
+		if err == nil {
+			return result, nil
+		}
+
+		// Without a response only a transport failure is worth another attempt
+		retryable := isRetryableStatus(status) || (status == 0 && !errors.Is(err, ErrInvalidRequest))
+		if attempt >= s.retry.MaxRetries || !retryable || ctx.Err() != nil {
+			return result, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, errors.Join(err, ctx.Err())
+		case <-time.After(s.retry.Backoff(attempt)):
+		}
+	}
+}
+
+// post makes one signed attempt and returns the response status, 0 when none was received
+func (s *Sender) post(ctx context.Context, d Delivery, deliveryID string) (int, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, s.retry.RequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(attemptCtx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(d.Secret, s.now(), d.Payload))
+	req.Header.Set(DeliveryHeader, deliveryID)
+	if d.Event != "" {
+		req.Header.Set(EventHeader, d.Event)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drain so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, &StatusError{StatusCode: resp.StatusCode}
+	}
+	return resp.StatusCode, nil
+}
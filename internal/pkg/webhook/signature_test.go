@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignIsDeterministic(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	payload := []byte(`{"event":"payroll.finalized"}`)
+
+	first := Sign("whsec_test", ts, payload)
+	if second := Sign("whsec_test", ts, payload); first != second {
+		t.Errorf("Sign() = %q then %q for the same inputs", first, second)
+	}
+	if !strings.HasPrefix(first, "t=1772352000,v1=") {
+		t.Errorf("Sign() = %q, want the unix timestamp first", first)
+	}
+	if other := Sign("whsec_other", ts, payload); other == first {
+		t.Error("a different secret gives the same signature")
+	}
+	if later := Sign("whsec_test", ts.Add(time.Second), payload); later == first {
+		t.Error("a different timestamp gives the same signature")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	payload := []byte(`{"event":"payroll.finalized"}`)
+	header := Sign("whsec_test", ts, payload)
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  string
+		payload []byte
+		now     time.Time
+		wantErr error
+	}{
+		{name: "valid", secret: "whsec_test", header: header, payload: payload, now: ts.Add(time.Minute)},
+		{name: "tampered payload", secret: "whsec_test", header: header, payload: []byte(`{"event":"payroll.reopened"}`), now: ts, wantErr: ErrInvalidSignature},
+		{name: "wrong secret", secret: "whsec_other", header: header, payload: payload, now: ts, wantErr: ErrInvalidSignature},
+		{name: "replayed with a new timestamp", secret: "whsec_test", header: strings.Replace(header, "t=1772352000", "t=1772352060", 1), payload: payload, now: ts, wantErr: ErrInvalidSignature},
+		{name: "expired timestamp", secret: "whsec_test", header: header, payload: payload, now: ts.Add(5*time.Minute + time.Second), wantErr: ErrSignatureExpired},
+		{name: "timestamp from the future", secret: "whsec_test", header: header, payload: payload, now: ts.Add(-6 * time.Minute), wantErr: ErrSignatureExpired},
+		{name: "at the tolerance", secret: "whsec_test", header: header, payload: payload, now: ts.Add(5 * time.Minute)},
+		{name: "missing signature", secret: "whsec_test", header: "t=1772352000", payload: payload, now: ts, wantErr: ErrMalformedSignature},
+		{name: "bad timestamp", secret: "whsec_test", header: "t=soon,v1=abc", payload: payload, now: ts, wantErr: ErrMalformedSignature},
+		{name: "no secret", secret: "", header: header, payload: payload, now: ts, wantErr: ErrMissingSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.header, tt.payload, 5*time.Minute, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyWithoutToleranceSkipsTheTimestamp(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	payload := []byte(`{}`)
+
+	if err := Verify("whsec_test", Sign("whsec_test", ts, payload), payload, 0, ts.AddDate(1, 0, 0)); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	b, _ := GenerateSecret()
+	if !strings.HasPrefix(a, secretPrefix) || len(a) != len(secretPrefix)+64 || a == b {
+		t.Errorf("GenerateSecret() = %q, %q", a, b)
+	}
+}
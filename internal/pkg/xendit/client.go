@@ -1,18 +1,28 @@
 package xendit
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/config"
 	xenditSDK "github.com/xendit/xendit-go/v7"
 	"github.com/xendit/xendit-go/v7/invoice"
 )
 
+// InvoiceClient is the subset of the Xendit invoice API the subscription service depends on.
+// It lets checkout and webhook flows be exercised with a fake instead of real HTTP calls.
+type InvoiceClient interface {
+	CreateInvoice(ctx context.Context, req CreateInvoiceRequest) (*InvoiceResponse, error)
+	ExpireInvoice(ctx context.Context, invoiceID string) (*InvoiceResponse, error)
+}
+
 // Client wraps the official Xendit SDK
 type Client struct {
 	sdk         *xenditSDK.APIClient
 	invoiceAPI  invoice.InvoiceApi
 	environment string
+	retry       RetryPolicy
 }
 
 // NewClient creates a new Xendit client using the official SDK
@@ -23,7 +33,27 @@ func NewClient(cfg config.XenditConfig) *Client {
 		sdk:         sdk,
 		invoiceAPI:  sdk.InvoiceApi,
 		environment: cfg.Environment,
+		retry:       retryPolicyFromConfig(cfg),
+	}
+}
+
+// retryPolicyFromConfig builds a RetryPolicy, using DefaultRetryPolicy for unset values.
+// MaxRetries is a pointer so an explicit 0 can disable retries while nil keeps the default.
+func retryPolicyFromConfig(cfg config.XenditConfig) RetryPolicy {
+	policy := DefaultRetryPolicy
+	if cfg.MaxRetries != nil && *cfg.MaxRetries >= 0 {
+		policy.MaxRetries = *cfg.MaxRetries
+	}
+	if cfg.RetryBaseDelay > 0 {
+		policy.BaseDelay = time.Duration(cfg.RetryBaseDelay) * time.Millisecond
+	}
+	if cfg.RetryMaxDelay > 0 {
+		policy.MaxDelay = time.Duration(cfg.RetryMaxDelay) * time.Millisecond
+	}
+	if cfg.RequestTimeout > 0 {
+		policy.RequestTimeout = time.Duration(cfg.RequestTimeout) * time.Second
 	}
+	return policy
 }
 
 // APIError represents a Xendit API error
@@ -2,10 +2,13 @@ package xendit
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/shopspring/decimal"
+	"github.com/xendit/xendit-go/v7/common"
 	"github.com/xendit/xendit-go/v7/invoice"
 )
 
@@ -61,10 +64,9 @@ type InvoiceResponse struct {
 	PaymentDestination string    `json:"payment_destination,omitempty"`
 }
 
-// CreateInvoice creates a new invoice using the official Xendit SDK
-func (c *Client) CreateInvoice(req CreateInvoiceRequest) (*InvoiceResponse, error) {
-	ctx := context.Background()
-
+// CreateInvoice creates a new invoice using the official Xendit SDK.
+// Cancelling ctx aborts the current attempt and stops further retries.
+func (c *Client) CreateInvoice(ctx context.Context, req CreateInvoiceRequest) (*InvoiceResponse, error) {
 	currency := req.Currency
 	if currency == "" {
 		currency = "IDR"
@@ -122,23 +124,62 @@ func (c *Client) CreateInvoice(req CreateInvoiceRequest) (*InvoiceResponse, erro
 		sdkReq.SetMetadata(metadata)
 	}
 
-	// Execute API call
-	resp, _, err := c.invoiceAPI.CreateInvoice(ctx).
-		CreateInvoiceRequest(sdkReq).
-		Execute()
+	// Creating is not idempotent: an attempt that timed out or got a 5xx may still have created
+	// the invoice, so before creating it again it is looked up by external ID
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, c.retry.RequestTimeout)
+		resp, httpResp, sdkErr := c.invoiceAPI.CreateInvoice(attemptCtx).
+			CreateInvoiceRequest(sdkReq).
+			Execute()
+		cancel()
+
+		if sdkErr == nil {
+			return toInvoiceResponse(resp), nil
+		}
+		if attempt >= c.retry.MaxRetries || !isRetryable(ctx, httpResp, sdkErr) {
+			return nil, fmt.Errorf("failed to create invoice: %w", sdkErr)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to create invoice: %w", errors.Join(sdkErr, ctx.Err()))
+		case <-time.After(c.retry.backoff(attempt)):
+		}
+
+		existing, err := c.findInvoiceByExternalID(ctx, req.ExternalID)
+		if err != nil {
+			// Without knowing whether the first attempt went through, creating again could bill twice
+			return nil, fmt.Errorf("failed to create invoice: %w (checking for an invoice from the failed attempt: %v)", sdkErr, err)
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	}
+}
 
+// findInvoiceByExternalID returns the newest invoice created with externalID, or nil when there is none
+func (c *Client) findInvoiceByExternalID(ctx context.Context, externalID string) (*InvoiceResponse, error) {
+	invoices, err := withRetry(ctx, c.retry, func(ctx context.Context) ([]invoice.Invoice, *http.Response, *common.XenditSdkError) {
+		return c.invoiceAPI.GetInvoices(ctx).ExternalId(externalID).Execute()
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create invoice: %w", err)
+		return nil, err
 	}
 
-	return toInvoiceResponse(resp), nil
+	var newest *invoice.Invoice
+	for i := range invoices {
+		if newest == nil || invoices[i].GetCreated().After(newest.GetCreated()) {
+			newest = &invoices[i]
+		}
+	}
+	return toInvoiceResponse(newest), nil
 }
 
 // GetInvoice retrieves an invoice by ID using the official Xendit SDK
-func (c *Client) GetInvoice(invoiceID string) (*InvoiceResponse, error) {
-	ctx := context.Background()
-
-	resp, _, err := c.invoiceAPI.GetInvoiceById(ctx, invoiceID).Execute()
+func (c *Client) GetInvoice(ctx context.Context, invoiceID string) (*InvoiceResponse, error) {
+	resp, err := withRetry(ctx, c.retry, func(ctx context.Context) (*invoice.Invoice, *http.Response, *common.XenditSdkError) {
+		return c.invoiceAPI.GetInvoiceById(ctx, invoiceID).Execute()
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get invoice: %w", err)
 	}
@@ -147,10 +188,10 @@ func (c *Client) GetInvoice(invoiceID string) (*InvoiceResponse, error) {
 }
 
 // ExpireInvoice expires an invoice using the official Xendit SDK
-func (c *Client) ExpireInvoice(invoiceID string) (*InvoiceResponse, error) {
-	ctx := context.Background()
-
-	resp, _, err := c.invoiceAPI.ExpireInvoice(ctx, invoiceID).Execute()
+func (c *Client) ExpireInvoice(ctx context.Context, invoiceID string) (*InvoiceResponse, error) {
+	resp, err := withRetry(ctx, c.retry, func(ctx context.Context) (*invoice.Invoice, *http.Response, *common.XenditSdkError) {
+		return c.invoiceAPI.ExpireInvoice(ctx, invoiceID).Execute()
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to expire invoice: %w", err)
 	}
@@ -201,4 +242,5 @@ const (
 	InvoiceStatusPaid    = "PAID"
 	InvoiceStatusSettled = "SETTLED"
 	InvoiceStatusExpired = "EXPIRED"
+	InvoiceStatusFailed  = "FAILED"
 )
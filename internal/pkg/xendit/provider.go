@@ -0,0 +1,112 @@
+package xendit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+)
+
+// ProviderName identifies Xendit as a payment provider
+const ProviderName = "xendit"
+
+// Provider adapts the Xendit invoice API to subscription.PaymentProvider
+type Provider struct {
+	client   InvoiceClient
+	verifier *WebhookVerifier
+}
+
+// NewProvider creates a Xendit-backed payment provider
+func NewProvider(client InvoiceClient, verifier *WebhookVerifier) *Provider {
+	return &Provider{
+		client:   client,
+		verifier: verifier,
+	}
+}
+
+// Name implements subscription.PaymentProvider.
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// CreateInvoice implements subscription.PaymentProvider.
+func (p *Provider) CreateInvoice(ctx context.Context, req subscription.PaymentInvoiceRequest) (subscription.PaymentInvoice, error) {
+	resp, err := p.client.CreateInvoice(ctx, CreateInvoiceRequest{
+		ExternalID:         req.ExternalID,
+		Amount:             req.Amount,
+		PayerEmail:         req.PayerEmail,
+		Description:        req.Description,
+		Currency:           req.Currency,
+		InvoiceDuration:    req.DurationSeconds,
+		SuccessRedirectURL: req.SuccessRedirectURL,
+		FailureRedirectURL: req.FailureRedirectURL,
+	})
+	if err != nil {
+		return subscription.PaymentInvoice{}, err
+	}
+	if resp == nil {
+		return subscription.PaymentInvoice{}, fmt.Errorf("xendit returned an empty invoice response")
+	}
+
+	return subscription.PaymentInvoice{
+		ID:         resp.ID,
+		URL:        resp.InvoiceURL,
+		ExpiryDate: resp.ExpiryDate,
+	}, nil
+}
+
+// ExpireInvoice implements subscription.PaymentProvider.
+func (p *Provider) ExpireInvoice(ctx context.Context, providerInvoiceID string) error {
+	_, err := p.client.ExpireInvoice(ctx, providerInvoiceID)
+	return err
+}
+
+// VerifyCallback implements subscription.PaymentProvider.
+// Xendit authenticates callbacks with the X-Callback-Token header.
+func (p *Provider) VerifyCallback(header http.Header, body []byte) (subscription.PaymentCallback, error) {
+	callbackToken := header.Get("X-Callback-Token")
+	if callbackToken == "" || !p.verifier.VerifySignature(callbackToken) {
+		return subscription.PaymentCallback{}, subscription.ErrInvalidWebhookSignature
+	}
+
+	var payload InvoiceWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return subscription.PaymentCallback{}, fmt.Errorf("%w: %v", subscription.ErrInvalidWebhookPayload, err)
+	}
+	if payload.ID == "" {
+		return subscription.PaymentCallback{}, fmt.Errorf("%w: missing invoice id", subscription.ErrInvalidWebhookPayload)
+	}
+
+	callback := subscription.PaymentCallback{
+		ProviderInvoiceID: payload.ID,
+		Status:            mapInvoiceStatus(payload.Status),
+		ProviderStatus:    payload.Status,
+		PaymentMethod:     payload.PaymentMethod,
+		PaymentChannel:    payload.PaymentChannel,
+	}
+	if payload.PaidAt != "" {
+		if paidAt, err := time.Parse(time.RFC3339, payload.PaidAt); err == nil {
+			callback.PaidAt = &paidAt
+		}
+	}
+
+	return callback, nil
+}
+
+// mapInvoiceStatus converts a Xendit invoice status to the internal InvoiceStatus.
+// Unknown or in-progress statuses map to an empty status.
+func mapInvoiceStatus(status string) subscription.InvoiceStatus {
+	switch status {
+	case InvoiceStatusPaid, InvoiceStatusSettled:
+		return subscription.InvoiceStatusPaid
+	case InvoiceStatusExpired:
+		return subscription.InvoiceStatusExpired
+	case InvoiceStatusFailed:
+		return subscription.InvoiceStatusFailed
+	default:
+		return ""
+	}
+}
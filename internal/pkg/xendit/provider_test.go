@@ -0,0 +1,48 @@
+package xendit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+	"github.com/shopspring/decimal"
+)
+
+type ctxKey struct{}
+
+// recordingClient remembers the context of every call
+type recordingClient struct {
+	contexts []context.Context
+}
+
+func (c *recordingClient) CreateInvoice(ctx context.Context, req CreateInvoiceRequest) (*InvoiceResponse, error) {
+	c.contexts = append(c.contexts, ctx)
+	return &InvoiceResponse{ID: "inv-1", ExternalID: req.ExternalID}, nil
+}
+
+func (c *recordingClient) ExpireInvoice(ctx context.Context, invoiceID string) (*InvoiceResponse, error) {
+	c.contexts = append(c.contexts, ctx)
+	return &InvoiceResponse{ID: invoiceID, Status: "EXPIRED"}, nil
+}
+
+func TestProviderPassesContextToClient(t *testing.T) {
+	client := &recordingClient{}
+	provider := NewProvider(client, NewWebhookVerifier("token"))
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request-1")
+
+	if _, err := provider.CreateInvoice(ctx, subscription.PaymentInvoiceRequest{ExternalID: "sub-1", Amount: decimal.NewFromInt(100000)}); err != nil {
+		t.Fatalf("CreateInvoice() error = %v", err)
+	}
+	if err := provider.ExpireInvoice(ctx, "inv-1"); err != nil {
+		t.Fatalf("ExpireInvoice() error = %v", err)
+	}
+
+	if len(client.contexts) != 2 {
+		t.Fatalf("client called %d times, want 2", len(client.contexts))
+	}
+	for i, got := range client.contexts {
+		if got.Value(ctxKey{}) != "request-1" {
+			t.Errorf("call %d did not get the caller's context", i)
+		}
+	}
+}
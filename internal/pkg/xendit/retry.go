@@ -0,0 +1,78 @@
+package xendit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/xendit/xendit-go/v7/common"
+)
+
+// RetryPolicy controls how Xendit API calls are retried on transient failures.
+type RetryPolicy struct {
+	MaxRetries     int           // Additional attempts after the first call (0 disables retries)
+	BaseDelay      time.Duration // Delay before the first retry, doubled on every attempt
+	MaxDelay       time.Duration // Upper bound for a single backoff delay
+	RequestTimeout time.Duration // Timeout applied to each individual attempt
+}
+
+// DefaultRetryPolicy is used when the configuration does not provide values.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:     3,
+	BaseDelay:      200 * time.Millisecond,
+	MaxDelay:       2 * time.Second,
+	RequestTimeout: 15 * time.Second,
+}
+
+// backoff returns the delay before retry number attempt (0-based), capped at MaxDelay.
+// The cap is checked before shifting so a large attempt cannot overflow the delay.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+	if attempt >= 63 || p.BaseDelay > p.MaxDelay>>attempt {
+		return p.MaxDelay
+	}
+	return p.BaseDelay << attempt
+}
+
+// isRetryable reports whether a failed call may succeed on a later attempt.
+// Network errors and timeouts (no HTTP response) and 5xx responses are retried;
+// 4xx responses are client errors and are returned immediately.
+func isRetryable(ctx context.Context, httpResp *http.Response, sdkErr *common.XenditSdkError) bool {
+	if sdkErr == nil {
+		return false
+	}
+	if httpResp == nil {
+		// The parent context being done is not transient, only the per-attempt timeout is
+		return ctx.Err() == nil
+	}
+	return httpResp.StatusCode >= http.StatusInternalServerError
+}
+
+// withRetry runs call with a per-attempt timeout, retrying transient failures with
+// exponential backoff. The last SDK error is returned when all attempts fail.
+func withRetry[T any](ctx context.Context, policy RetryPolicy, call func(ctx context.Context) (T, *http.Response, *common.XenditSdkError)) (T, error) {
+	var zero T
+
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, policy.RequestTimeout)
+		result, httpResp, sdkErr := call(attemptCtx)
+		cancel()
+
+		if sdkErr == nil {
+			return result, nil
+		}
+
+		if attempt >= policy.MaxRetries || !isRetryable(ctx, httpResp, sdkErr) {
+			return zero, sdkErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return zero, errors.Join(sdkErr, ctx.Err())
+		case <-time.After(policy.backoff(attempt)):
+		}
+	}
+}
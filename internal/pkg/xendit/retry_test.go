@@ -0,0 +1,276 @@
+package xendit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/config"
+	"github.com/shopspring/decimal"
+	xenditSDK "github.com/xendit/xendit-go/v7"
+)
+
+// testRetryPolicy retries quickly so tests do not sleep
+var testRetryPolicy = RetryPolicy{
+	MaxRetries:     3,
+	BaseDelay:      time.Millisecond,
+	MaxDelay:       4 * time.Millisecond,
+	RequestTimeout: time.Second,
+}
+
+// flakyTransport answers each request with the next scripted reply for its method and path
+type flakyTransport struct {
+	mu      sync.Mutex
+	replies map[string][]reply // "POST /v2/invoices/" -> replies in order
+	calls   map[string]int
+}
+
+type reply struct {
+	status int
+	body   string
+	err    error // returned instead of a response, like a timeout or dropped connection
+}
+
+func newFlakyTransport(replies map[string][]reply) *flakyTransport {
+	return &flakyTransport{replies: replies, calls: map[string]int{}}
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := req.Method + " " + req.URL.Path
+	n := t.calls[key]
+	t.calls[key]++
+
+	replies := t.replies[key]
+	if n >= len(replies) {
+		return nil, errors.New("unexpected request " + key)
+	}
+	r := replies[n]
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &http.Response{
+		StatusCode: r.status,
+		Status:     http.StatusText(r.status),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(r.body)),
+		Request:    req,
+	}, nil
+}
+
+func newTestClient(transport http.RoundTripper) *Client {
+	sdk := xenditSDK.NewClient("test-key")
+	sdk.GetConfig().(*xenditSDK.Configuration).HTTPClient = &http.Client{Transport: transport}
+	return &Client{sdk: sdk, invoiceAPI: sdk.InvoiceApi, retry: testRetryPolicy}
+}
+
+const (
+	createPath = "POST /v2/invoices/"
+	listPath   = "GET /v2/invoices"
+)
+
+func invoiceJSON(id string) string {
+	return `{"id":"` + id + `","external_id":"sub-1","user_id":"u","status":"PENDING","merchant_name":"HRIS",` +
+		`"merchant_profile_picture_url":"","amount":100000,"invoice_url":"https://checkout.example/` + id + `",` +
+		`"expiry_date":"2026-03-02T00:00:00Z","created":"2026-03-01T00:00:00Z","updated":"2026-03-01T00:00:00Z",` +
+		`"currency":"IDR","available_banks":[],"available_retail_outlets":[],"available_ewallets":[],` +
+		`"available_qr_codes":[],"available_direct_debits":[],"available_paylaters":[],"should_send_email":false}`
+}
+
+var testInvoiceRequest = CreateInvoiceRequest{ExternalID: "sub-1", Amount: decimal.NewFromInt(100000)}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 200 * time.Millisecond, MaxDelay: 2 * time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 200 * time.Millisecond},
+		{1, 400 * time.Millisecond},
+		{2, 800 * time.Millisecond},
+		{3, 1600 * time.Millisecond},
+		{4, 2 * time.Second},  // capped
+		{70, 2 * time.Second}, // the shift overflows
+	}
+	for _, tt := range tests {
+		if got := policy.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyBackoffShiftOverflow(t *testing.T) {
+	// 2^34+1 ns shifted by 30 wraps to 2^30 ns, about a second, unless the cap is checked first
+	policy := RetryPolicy{BaseDelay: time.Duration(1<<34 + 1), MaxDelay: time.Hour}
+
+	if got := policy.backoff(30); got != time.Hour {
+		t.Errorf("backoff(30) = %v, want the %v cap", got, time.Hour)
+	}
+	for attempt := 0; attempt < 100; attempt++ {
+		if got := policy.backoff(attempt); got <= 0 || got > policy.MaxDelay {
+			t.Fatalf("backoff(%d) = %v, want within (0, %v]", attempt, got, policy.MaxDelay)
+		}
+	}
+}
+
+func TestRetryPolicyFromConfig(t *testing.T) {
+	zero, five := 0, 5
+
+	tests := []struct {
+		name string
+		cfg  config.XenditConfig
+		want int
+	}{
+		{name: "unset keeps the default", cfg: config.XenditConfig{}, want: DefaultRetryPolicy.MaxRetries},
+		{name: "explicit zero disables retries", cfg: config.XenditConfig{MaxRetries: &zero}, want: 0},
+		{name: "configured value", cfg: config.XenditConfig{MaxRetries: &five}, want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryPolicyFromConfig(tt.cfg); got.MaxRetries != tt.want {
+				t.Errorf("MaxRetries = %d, want %d", got.MaxRetries, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateInvoiceRetriesServerErrors(t *testing.T) {
+	transport := newFlakyTransport(map[string][]reply{
+		createPath: {{status: 503, body: `{"error_code":"SERVER_ERROR"}`}, {status: 200, body: invoiceJSON("inv-1")}},
+		listPath:   {{status: 200, body: `[]`}},
+	})
+
+	resp, err := newTestClient(transport).CreateInvoice(context.Background(), testInvoiceRequest)
+	if err != nil {
+		t.Fatalf("CreateInvoice() error = %v", err)
+	}
+	if resp.ID != "inv-1" {
+		t.Errorf("CreateInvoice() ID = %q, want inv-1", resp.ID)
+	}
+	if transport.calls[createPath] != 2 || transport.calls[listPath] != 1 {
+		t.Errorf("calls = %v, want 2 creates after 1 lookup", transport.calls)
+	}
+}
+
+func TestCreateInvoiceReusesInvoiceFromTimedOutAttempt(t *testing.T) {
+	// Xendit created the invoice but the response never arrived
+	transport := newFlakyTransport(map[string][]reply{
+		createPath: {{err: errors.New("read: connection reset by peer")}},
+		listPath:   {{status: 200, body: `[` + invoiceJSON("inv-1") + `]`}},
+	})
+
+	resp, err := newTestClient(transport).CreateInvoice(context.Background(), testInvoiceRequest)
+	if err != nil {
+		t.Fatalf("CreateInvoice() error = %v", err)
+	}
+	if resp.ID != "inv-1" {
+		t.Errorf("CreateInvoice() ID = %q, want the existing inv-1", resp.ID)
+	}
+	if transport.calls[createPath] != 1 {
+		t.Errorf("creates = %d, want 1: a second create would be a duplicate invoice", transport.calls[createPath])
+	}
+}
+
+func TestCreateInvoiceDoesNotRecreateWhenLookupFails(t *testing.T) {
+	failing := reply{status: 503, body: `{}`}
+	transport := newFlakyTransport(map[string][]reply{
+		createPath: {{err: errors.New("timeout")}},
+		listPath:   {failing, failing, failing, failing},
+	})
+
+	if _, err := newTestClient(transport).CreateInvoice(context.Background(), testInvoiceRequest); err == nil {
+		t.Fatal("CreateInvoice() error = nil, want the create error")
+	}
+	if transport.calls[createPath] != 1 {
+		t.Errorf("creates = %d, want 1", transport.calls[createPath])
+	}
+}
+
+func TestCreateInvoiceDoesNotRetryClientErrors(t *testing.T) {
+	transport := newFlakyTransport(map[string][]reply{
+		createPath: {{status: 400, body: `{"error_code":"API_VALIDATION_ERROR"}`}},
+	})
+
+	if _, err := newTestClient(transport).CreateInvoice(context.Background(), testInvoiceRequest); err == nil {
+		t.Fatal("CreateInvoice() error = nil, want the 400")
+	}
+	if transport.calls[createPath] != 1 || transport.calls[listPath] != 0 {
+		t.Errorf("calls = %v, want a single create", transport.calls)
+	}
+}
+
+func TestExpireInvoiceRetriesUntilMaxRetries(t *testing.T) {
+	failing := reply{status: 502, body: `{}`}
+	path := "POST /invoices/inv-1/expire!"
+
+	transport := newFlakyTransport(map[string][]reply{path: {failing, failing, failing, failing, failing}})
+	if _, err := newTestClient(transport).ExpireInvoice(context.Background(), "inv-1"); err == nil {
+		t.Fatal("ExpireInvoice() error = nil, want the last 502")
+	}
+	if got, want := transport.calls[path], testRetryPolicy.MaxRetries+1; got != want {
+		t.Errorf("attempts = %d, want %d", got, want)
+	}
+
+	transport = newFlakyTransport(map[string][]reply{path: {failing, {status: 200, body: invoiceJSON("inv-1")}}})
+	if _, err := newTestClient(transport).ExpireInvoice(context.Background(), "inv-1"); err != nil {
+		t.Fatalf("ExpireInvoice() error = %v", err)
+	}
+	if transport.calls[path] != 2 {
+		t.Errorf("attempts = %d, want 2", transport.calls[path])
+	}
+}
+
+// cancelAfter cancels the caller's context once the wrapped transport has answered
+type cancelAfter struct {
+	next   http.RoundTripper
+	cancel context.CancelFunc
+}
+
+func (c cancelAfter) RoundTrip(req *http.Request) (*http.Response, error) {
+	defer c.cancel()
+	return c.next.RoundTrip(req)
+}
+
+func TestCancelledContextStopsRetries(t *testing.T) {
+	failing := reply{status: 503, body: `{}`}
+	expirePath := "POST /invoices/inv-1/expire!"
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context, c *Client) error
+		path string
+	}{
+		{name: "create", path: createPath, call: func(ctx context.Context, c *Client) error {
+			_, err := c.CreateInvoice(ctx, testInvoiceRequest)
+			return err
+		}},
+		{name: "expire", path: expirePath, call: func(ctx context.Context, c *Client) error {
+			_, err := c.ExpireInvoice(ctx, "inv-1")
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			transport := newFlakyTransport(map[string][]reply{tt.path: {failing, failing, failing, failing}})
+
+			err := tt.call(ctx, newTestClient(cancelAfter{next: transport, cancel: cancel}))
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("error = %v, want context.Canceled", err)
+			}
+			if transport.calls[tt.path] != 1 || transport.calls[listPath] != 0 {
+				t.Errorf("calls = %v, want a single attempt", transport.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Package xendittest provides an in-memory xendit.InvoiceClient so payment flows can be tested
+// through the real xendit.Provider without calling the Xendit API.
+package xendittest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/xendit"
+)
+
+// Client keeps the invoices it created in memory. CreateErr and ExpireErr, when set, are
+// returned instead of doing the call.
+type Client struct {
+	CreateErr error
+	ExpireErr error
+
+	mu       sync.Mutex
+	now      func() time.Time
+	invoices map[string]*xendit.InvoiceResponse
+	created  []xendit.CreateInvoiceRequest
+	expired  []string
+}
+
+var _ xendit.InvoiceClient = (*Client)(nil)
+
+// NewClient returns an empty client whose invoices are created at now
+func NewClient(now func() time.Time) *Client {
+	return &Client{now: now, invoices: make(map[string]*xendit.InvoiceResponse)}
+}
+
+// CreateInvoice implements xendit.InvoiceClient.
+func (c *Client) CreateInvoice(ctx context.Context, req xendit.CreateInvoiceRequest) (*xendit.InvoiceResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.created = append(c.created, req)
+	if c.CreateErr != nil {
+		return nil, c.CreateErr
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = "IDR"
+	}
+	amount, _ := req.Amount.Float64()
+	created := c.now()
+	id := fmt.Sprintf("xnd-inv-%d", len(c.created))
+
+	inv := &xendit.InvoiceResponse{
+		ID:          id,
+		ExternalID:  req.ExternalID,
+		Status:      xendit.InvoiceStatusPending,
+		Amount:      amount,
+		PayerEmail:  req.PayerEmail,
+		Description: req.Description,
+		InvoiceURL:  "https://checkout.xendit.test/" + id,
+		ExpiryDate:  created.Add(time.Duration(req.InvoiceDuration) * time.Second),
+		Currency:    currency,
+		Created:     created,
+		Updated:     created,
+	}
+	c.invoices[id] = inv
+
+	resp := *inv
+	return &resp, nil
+}
+
+// ExpireInvoice implements xendit.InvoiceClient.
+func (c *Client) ExpireInvoice(ctx context.Context, invoiceID string) (*xendit.InvoiceResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expired = append(c.expired, invoiceID)
+	if c.ExpireErr != nil {
+		return nil, c.ExpireErr
+	}
+
+	inv, ok := c.invoices[invoiceID]
+	if !ok {
+		return nil, fmt.Errorf("invoice %s not found", invoiceID)
+	}
+	inv.Status = xendit.InvoiceStatusExpired
+	inv.Updated = c.now()
+
+	resp := *inv
+	return &resp, nil
+}
+
+// Created returns every create request received, including failed ones
+func (c *Client) Created() []xendit.CreateInvoiceRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]xendit.CreateInvoiceRequest(nil), c.created...)
+}
+
+// Expired returns the IDs of every expire request received, including failed ones
+func (c *Client) Expired() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.expired...)
+}
+
+// Invoice returns a copy of a created invoice
+func (c *Client) Invoice(invoiceID string) (xendit.InvoiceResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	inv, ok := c.invoices[invoiceID]
+	if !ok {
+		return xendit.InvoiceResponse{}, false
+	}
+	return *inv, true
+}
+
+// Pay marks an invoice paid and returns the body of the callback Xendit would send for it
+func (c *Client) Pay(invoiceID, method, channel string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	inv, ok := c.invoices[invoiceID]
+	if !ok {
+		return nil, fmt.Errorf("invoice %s not found", invoiceID)
+	}
+	if inv.Status != xendit.InvoiceStatusPending {
+		return nil, fmt.Errorf("invoice %s is %s", invoiceID, inv.Status)
+	}
+
+	paidAt := c.now()
+	inv.Status = xendit.InvoiceStatusPaid
+	inv.PaymentMethod = method
+	inv.PaymentChannel = channel
+	inv.Updated = paidAt
+
+	return json.Marshal(xendit.InvoiceWebhookPayload{
+		ID:             inv.ID,
+		ExternalID:     inv.ExternalID,
+		Status:         inv.Status,
+		Amount:         inv.Amount,
+		PaidAmount:     inv.Amount,
+		PaidAt:         paidAt.Format(time.RFC3339),
+		PayerEmail:     inv.PayerEmail,
+		Description:    inv.Description,
+		Created:        inv.Created.Format(time.RFC3339),
+		Updated:        paidAt.Format(time.RFC3339),
+		Currency:       inv.Currency,
+		PaymentMethod:  method,
+		PaymentChannel: channel,
+	})
+}
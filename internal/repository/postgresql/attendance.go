@@ -20,12 +20,13 @@ func (a *attendanceRepository) GetOpenSession(ctx context.Context, employeeID st
 	q := GetQuerier(ctx, a.db)
 
 	query := `
-		SELECT id, employee_id, company_id, date, work_schedule_time_id, actual_location_type,
+		SELECT id, employee_id, company_id, date, work_schedule_time_id, actual_location_type, work_schedule_location_id,
 			   clock_in, clock_out, work_hours_in_minutes,
 			   clock_in_latitude, clock_in_longitude, clock_in_proof_url,
 			   clock_out_latitude, clock_out_longitude, clock_out_proof_url,
 			   status, approved_by, approved_at, rejection_reason,
 			   leave_type_id, late_minutes, early_leave_minutes, overtime_minutes,
+			   raw_late_minutes, raw_overtime_minutes, overtime_multiplier, auto_closed,
 			   created_at, updated_at
 		FROM attendances
 		WHERE employee_id = $1
@@ -36,12 +37,13 @@ func (a *attendanceRepository) GetOpenSession(ctx context.Context, employeeID st
 
 	var att attendance.Attendance
 	err := q.QueryRow(ctx, query, employeeID).Scan(
-		&att.ID, &att.EmployeeID, &att.CompanyID, &att.Date, &att.WorkScheduleTimeID, &att.ActualLocationType,
+		&att.ID, &att.EmployeeID, &att.CompanyID, &att.Date, &att.WorkScheduleTimeID, &att.ActualLocationType, &att.WorkScheduleLocationID,
 		&att.ClockIn, &att.ClockOut, &att.WorkHoursInMinutes,
 		&att.ClockInLatitude, &att.ClockInLongitude, &att.ClockInProofURL,
 		&att.ClockOutLatitude, &att.ClockOutLongitude, &att.ClockOutProofURL,
 		&att.Status, &att.ApprovedBy, &att.ApprovedAt, &att.RejectionReason,
 		&att.LeaveTypeID, &att.LateMinutes, &att.EarlyLeaveMinutes, &att.OvertimeMinutes,
+		&att.RawLateMinutes, &att.RawOvertimeMinutes, &att.OvertimeMultiplier, &att.AutoClosed,
 		&att.CreatedAt, &att.UpdatedAt,
 	)
 
@@ -61,12 +63,14 @@ func (a *attendanceRepository) Create(ctx context.Context, newAttendance attenda
 
 	query := `
 		INSERT INTO attendances (
-			employee_id, company_id, date, work_schedule_time_id, actual_location_type,
+			employee_id, company_id, date, work_schedule_time_id, actual_location_type, work_schedule_location_id,
 			clock_in, clock_in_latitude, clock_in_longitude, clock_in_proof_url,
 			status, late_minutes, early_leave_minutes, overtime_minutes, leave_type_id,
-			approved_by, approved_at
+			approved_by, approved_at, raw_late_minutes, raw_overtime_minutes,
+			clock_out, work_hours_in_minutes, overtime_multiplier
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21,
+			COALESCE($22::numeric, 1)
 		) RETURNING id, created_at, updated_at
 	`
 
@@ -76,6 +80,7 @@ func (a *attendanceRepository) Create(ctx context.Context, newAttendance attenda
 		newAttendance.Date,
 		newAttendance.WorkScheduleTimeID,
 		newAttendance.ActualLocationType,
+		newAttendance.WorkScheduleLocationID,
 		newAttendance.ClockIn,
 		newAttendance.ClockInLatitude,
 		newAttendance.ClockInLongitude,
@@ -87,6 +92,11 @@ func (a *attendanceRepository) Create(ctx context.Context, newAttendance attenda
 		newAttendance.LeaveTypeID,
 		newAttendance.ApprovedBy,
 		newAttendance.ApprovedAt,
+		newAttendance.RawLateMinutes,
+		newAttendance.RawOvertimeMinutes,
+		newAttendance.ClockOut,
+		newAttendance.WorkHoursInMinutes,
+		newAttendance.OvertimeMultiplier,
 	).Scan(&newAttendance.ID, &newAttendance.CreatedAt, &newAttendance.UpdatedAt)
 
 	if err != nil {
@@ -101,12 +111,13 @@ func (a *attendanceRepository) GetByEmployeeAndDate(ctx context.Context, employe
 	q := GetQuerier(ctx, a.db)
 
 	query := `
-		SELECT id, employee_id, company_id, date, work_schedule_time_id, actual_location_type,
+		SELECT id, employee_id, company_id, date, work_schedule_time_id, actual_location_type, work_schedule_location_id,
 			   clock_in, clock_out, work_hours_in_minutes,
 			   clock_in_latitude, clock_in_longitude, clock_in_proof_url,
 			   clock_out_latitude, clock_out_longitude, clock_out_proof_url,
 			   status, approved_by, approved_at, rejection_reason,
 			   leave_type_id, late_minutes, early_leave_minutes, overtime_minutes,
+			   raw_late_minutes, raw_overtime_minutes, overtime_multiplier, auto_closed,
 			   created_at, updated_at
 		FROM attendances
 		WHERE employee_id = $1
@@ -117,12 +128,13 @@ func (a *attendanceRepository) GetByEmployeeAndDate(ctx context.Context, employe
 
 	var att attendance.Attendance
 	err := q.QueryRow(ctx, query, employeeID, date, companyID).Scan(
-		&att.ID, &att.EmployeeID, &att.CompanyID, &att.Date, &att.WorkScheduleTimeID, &att.ActualLocationType,
+		&att.ID, &att.EmployeeID, &att.CompanyID, &att.Date, &att.WorkScheduleTimeID, &att.ActualLocationType, &att.WorkScheduleLocationID,
 		&att.ClockIn, &att.ClockOut, &att.WorkHoursInMinutes,
 		&att.ClockInLatitude, &att.ClockInLongitude, &att.ClockInProofURL,
 		&att.ClockOutLatitude, &att.ClockOutLongitude, &att.ClockOutProofURL,
 		&att.Status, &att.ApprovedBy, &att.ApprovedAt, &att.RejectionReason,
 		&att.LeaveTypeID, &att.LateMinutes, &att.EarlyLeaveMinutes, &att.OvertimeMinutes,
+		&att.RawLateMinutes, &att.RawOvertimeMinutes, &att.OvertimeMultiplier, &att.AutoClosed,
 		&att.CreatedAt, &att.UpdatedAt,
 	)
 
@@ -142,12 +154,13 @@ func (a *attendanceRepository) GetByID(ctx context.Context, id string, companyID
 
 	query := `
 		SELECT 
-			a.id, a.employee_id, a.company_id, a.date, a.work_schedule_time_id, a.actual_location_type,
+			a.id, a.employee_id, a.company_id, a.date, a.work_schedule_time_id, a.actual_location_type, a.work_schedule_location_id,
 			a.clock_in, a.clock_out, a.work_hours_in_minutes,
 			a.clock_in_latitude, a.clock_in_longitude, a.clock_in_proof_url,
 			a.clock_out_latitude, a.clock_out_longitude, a.clock_out_proof_url,
 			a.status, a.approved_by, a.approved_at, a.rejection_reason,
 			a.leave_type_id, a.late_minutes, a.early_leave_minutes, a.overtime_minutes,
+			a.raw_late_minutes, a.raw_overtime_minutes, a.overtime_multiplier, a.auto_closed,
 			a.created_at, a.updated_at,
 			e.full_name AS employee_name,
 			p.name AS employee_position
@@ -159,12 +172,13 @@ func (a *attendanceRepository) GetByID(ctx context.Context, id string, companyID
 
 	var att attendance.Attendance
 	err := q.QueryRow(ctx, query, id, companyID).Scan(
-		&att.ID, &att.EmployeeID, &att.CompanyID, &att.Date, &att.WorkScheduleTimeID, &att.ActualLocationType,
+		&att.ID, &att.EmployeeID, &att.CompanyID, &att.Date, &att.WorkScheduleTimeID, &att.ActualLocationType, &att.WorkScheduleLocationID,
 		&att.ClockIn, &att.ClockOut, &att.WorkHoursInMinutes,
 		&att.ClockInLatitude, &att.ClockInLongitude, &att.ClockInProofURL,
 		&att.ClockOutLatitude, &att.ClockOutLongitude, &att.ClockOutProofURL,
 		&att.Status, &att.ApprovedBy, &att.ApprovedAt, &att.RejectionReason,
 		&att.LeaveTypeID, &att.LateMinutes, &att.EarlyLeaveMinutes, &att.OvertimeMinutes,
+		&att.RawLateMinutes, &att.RawOvertimeMinutes, &att.OvertimeMultiplier, &att.AutoClosed,
 		&att.CreatedAt, &att.UpdatedAt,
 		&att.EmployeeName, &att.EmployeePosition,
 	)
@@ -243,12 +257,13 @@ func (a *attendanceRepository) GetMyAttendance(ctx context.Context, employeeID s
 	// Build query with pagination
 	selectQuery := fmt.Sprintf(`
 		SELECT 
-			a.id, a.employee_id, a.company_id, a.date, a.work_schedule_time_id, a.actual_location_type,
+			a.id, a.employee_id, a.company_id, a.date, a.work_schedule_time_id, a.actual_location_type, a.work_schedule_location_id,
 			a.clock_in, a.clock_out, a.work_hours_in_minutes,
 			a.clock_in_latitude, a.clock_in_longitude, a.clock_in_proof_url,
 			a.clock_out_latitude, a.clock_out_longitude, a.clock_out_proof_url,
 			a.status, a.approved_by, a.approved_at, a.rejection_reason,
 			a.leave_type_id, a.late_minutes, a.early_leave_minutes, a.overtime_minutes,
+			a.raw_late_minutes, a.raw_overtime_minutes, a.overtime_multiplier, a.auto_closed,
 			a.created_at, a.updated_at,
 			e.full_name AS employee_name,
 			p.name AS employee_position
@@ -277,12 +292,13 @@ func (a *attendanceRepository) GetMyAttendance(ctx context.Context, employeeID s
 	for rows.Next() {
 		var att attendance.Attendance
 		err := rows.Scan(
-			&att.ID, &att.EmployeeID, &att.CompanyID, &att.Date, &att.WorkScheduleTimeID, &att.ActualLocationType,
+			&att.ID, &att.EmployeeID, &att.CompanyID, &att.Date, &att.WorkScheduleTimeID, &att.ActualLocationType, &att.WorkScheduleLocationID,
 			&att.ClockIn, &att.ClockOut, &att.WorkHoursInMinutes,
 			&att.ClockInLatitude, &att.ClockInLongitude, &att.ClockInProofURL,
 			&att.ClockOutLatitude, &att.ClockOutLongitude, &att.ClockOutProofURL,
 			&att.Status, &att.ApprovedBy, &att.ApprovedAt, &att.RejectionReason,
 			&att.LeaveTypeID, &att.LateMinutes, &att.EarlyLeaveMinutes, &att.OvertimeMinutes,
+			&att.RawLateMinutes, &att.RawOvertimeMinutes, &att.OvertimeMultiplier, &att.AutoClosed,
 			&att.CreatedAt, &att.UpdatedAt,
 			&att.EmployeeName, &att.EmployeePosition,
 		)
@@ -321,6 +337,37 @@ func (a *attendanceRepository) HasCheckedInToday(ctx context.Context, employeeID
 	return hasCheckedIn, nil
 }
 
+// IsOnApprovedLeave implements attendance.AttendanceRepository.
+func (a *attendanceRepository) IsOnApprovedLeave(ctx context.Context, employeeID string, dateLocal string, companyID string) (bool, error) {
+	q := GetQuerier(ctx, a.db)
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM attendances
+			WHERE employee_id = $1
+			  AND date = $2
+			  AND company_id = $3
+			  AND leave_type_id IS NOT NULL
+		) OR EXISTS (
+			SELECT 1
+			FROM leave_requests lr
+			JOIN employees e ON e.id = lr.employee_id
+			WHERE lr.employee_id = $1
+			  AND e.company_id = $3
+			  AND lr.status = 'approved'
+			  AND $2::date BETWEEN lr.start_date AND lr.end_date
+		)
+	`
+
+	var onLeave bool
+	if err := q.QueryRow(ctx, query, employeeID, dateLocal, companyID).Scan(&onLeave); err != nil {
+		return false, fmt.Errorf("failed to check approved leave: %w", err)
+	}
+
+	return onLeave, nil
+}
+
 // List implements attendance.AttendanceRepository.
 func (a *attendanceRepository) List(ctx context.Context, filter attendance.AttendanceFilter, companyID string) ([]attendance.Attendance, int64, error) {
 	q := GetQuerier(ctx, a.db)
@@ -370,6 +417,13 @@ func (a *attendanceRepository) List(ctx context.Context, filter attendance.Atten
 		argIdx++
 	}
 
+	// Branch filter (branch-scoped managers)
+	if filter.BranchID != nil && *filter.BranchID != "" {
+		baseWhere += fmt.Sprintf(" AND e.branch_id = $%d", argIdx)
+		args = append(args, *filter.BranchID)
+		argIdx++
+	}
+
 	// Count total (need to join employees for name filter)
 	countQuery := `
 		SELECT COUNT(*) 
@@ -401,12 +455,13 @@ func (a *attendanceRepository) List(ctx context.Context, filter attendance.Atten
 	// Build query with pagination
 	selectQuery := fmt.Sprintf(`
 		SELECT 
-			a.id, a.employee_id, a.company_id, a.date, a.work_schedule_time_id, a.actual_location_type,
+			a.id, a.employee_id, a.company_id, a.date, a.work_schedule_time_id, a.actual_location_type, a.work_schedule_location_id,
 			a.clock_in, a.clock_out, a.work_hours_in_minutes,
 			a.clock_in_latitude, a.clock_in_longitude, a.clock_in_proof_url,
 			a.clock_out_latitude, a.clock_out_longitude, a.clock_out_proof_url,
 			a.status, a.approved_by, a.approved_at, a.rejection_reason,
 			a.leave_type_id, a.late_minutes, a.early_leave_minutes, a.overtime_minutes,
+			a.raw_late_minutes, a.raw_overtime_minutes, a.overtime_multiplier, a.auto_closed,
 			a.created_at, a.updated_at,
 			e.full_name AS employee_name,
 			p.name AS employee_position
@@ -435,12 +490,13 @@ func (a *attendanceRepository) List(ctx context.Context, filter attendance.Atten
 	for rows.Next() {
 		var att attendance.Attendance
 		err := rows.Scan(
-			&att.ID, &att.EmployeeID, &att.CompanyID, &att.Date, &att.WorkScheduleTimeID, &att.ActualLocationType,
+			&att.ID, &att.EmployeeID, &att.CompanyID, &att.Date, &att.WorkScheduleTimeID, &att.ActualLocationType, &att.WorkScheduleLocationID,
 			&att.ClockIn, &att.ClockOut, &att.WorkHoursInMinutes,
 			&att.ClockInLatitude, &att.ClockInLongitude, &att.ClockInProofURL,
 			&att.ClockOutLatitude, &att.ClockOutLongitude, &att.ClockOutProofURL,
 			&att.Status, &att.ApprovedBy, &att.ApprovedAt, &att.RejectionReason,
 			&att.LeaveTypeID, &att.LateMinutes, &att.EarlyLeaveMinutes, &att.OvertimeMinutes,
+			&att.RawLateMinutes, &att.RawOvertimeMinutes, &att.OvertimeMultiplier, &att.AutoClosed,
 			&att.CreatedAt, &att.UpdatedAt,
 			&att.EmployeeName, &att.EmployeePosition,
 		)
@@ -541,6 +597,21 @@ func (a *attendanceRepository) Update(ctx context.Context, att attendance.Attend
 		args = append(args, att.OvertimeMinutes)
 		argIdx++
 	}
+	if att.RawLateMinutes != nil {
+		updates = append(updates, fmt.Sprintf("raw_late_minutes = $%d", argIdx))
+		args = append(args, att.RawLateMinutes)
+		argIdx++
+	}
+	if att.RawOvertimeMinutes != nil {
+		updates = append(updates, fmt.Sprintf("raw_overtime_minutes = $%d", argIdx))
+		args = append(args, att.RawOvertimeMinutes)
+		argIdx++
+	}
+	if att.OvertimeMultiplier != nil {
+		updates = append(updates, fmt.Sprintf("overtime_multiplier = $%d", argIdx))
+		args = append(args, att.OvertimeMultiplier)
+		argIdx++
+	}
 
 	if len(updates) == 0 {
 		return fmt.Errorf("no updatable fields provided for attendance update")
@@ -589,78 +660,90 @@ func (a *attendanceRepository) Delete(ctx context.Context, id string, companyID
 }
 
 // GetStaleOpenSessions implements attendance.AttendanceRepository.
-func (a *attendanceRepository) GetStaleOpenSessions(ctx context.Context, gracePeriodHours int) ([]attendance.Attendance, error) {
+func (a *attendanceRepository) GetStaleOpenSessions(ctx context.Context, now time.Time) ([]attendance.Attendance, error) {
 	q := GetQuerier(ctx, a.db)
 
-	// This query finds attendances where clock_out is NULL and grace period has passed
-	// Handles next-day checkout correctly
+	// "Today" and the scheduled end are resolved in the employee's timezone, the branch's or
+	// else the company's, so overnight shifts and sessions missed by an earlier run are included
 	query := `
-		SELECT 
-			a.id, a.employee_id, a.company_id, a.date, a.work_schedule_time_id, 
-			a.actual_location_type, a.clock_in, a.clock_out, a.work_hours_in_minutes,
-			a.clock_in_latitude, a.clock_in_longitude, a.clock_in_proof_url,
-			a.clock_out_latitude, a.clock_out_longitude, a.clock_out_proof_url,
-			a.status, a.approved_by, a.approved_at, a.rejection_reason,
-			a.leave_type_id, a.late_minutes, a.early_leave_minutes, a.overtime_minutes,
-			a.created_at, a.updated_at,
-			wst.clock_out_time, wst.is_next_day_checkout,
-			b.timezone
+		SELECT a.id, a.employee_id, a.company_id, a.date, a.work_schedule_time_id, a.actual_location_type, a.work_schedule_location_id,
+			   a.clock_in, a.clock_out, a.work_hours_in_minutes,
+			   a.clock_in_latitude, a.clock_in_longitude, a.clock_in_proof_url,
+			   a.clock_out_latitude, a.clock_out_longitude, a.clock_out_proof_url,
+			   a.status, a.approved_by, a.approved_at, a.rejection_reason,
+			   a.leave_type_id, a.late_minutes, a.early_leave_minutes, a.overtime_minutes,
+			   a.raw_late_minutes, a.raw_overtime_minutes, a.overtime_multiplier, a.auto_closed,
+			   a.created_at, a.updated_at
 		FROM attendances a
-		JOIN employees e ON a.employee_id = e.id
-		JOIN branches b ON e.branch_id = b.id
-		JOIN work_schedule_times wst ON a.work_schedule_time_id = wst.id
-		WHERE a.clock_out IS NULL
-		  AND a.status IN ('waiting_approval', 'on_time', 'late')
-		  AND (
-			  -- Calculate expected clock out considering next-day checkout
-			  CASE 
-				  WHEN wst.is_next_day_checkout = true THEN
-					  (a.date + INTERVAL '1 day' + wst.clock_out_time::time)
-				  ELSE
-					  (a.date + wst.clock_out_time::time)
-			  END + INTERVAL '` + fmt.Sprintf("%d", gracePeriodHours) + ` hours'
-		  ) < NOW()
-		ORDER BY a.date ASC, a.clock_in ASC
+		JOIN employees e ON e.id = a.employee_id
+		JOIN companies c ON c.id = e.company_id
+		LEFT JOIN branches b ON b.id = e.branch_id
+		JOIN work_schedule_times wst ON wst.id = a.work_schedule_time_id
+		WHERE a.clock_in IS NOT NULL
+		  AND a.clock_out IS NULL
+		  AND a.date < ($1::timestamptz AT TIME ZONE COALESCE(b.timezone, c.timezone))::date
+		  AND (a.date + wst.clock_out_time
+			   + CASE WHEN wst.is_next_day_checkout THEN INTERVAL '1 day' ELSE INTERVAL '0' END)
+			  AT TIME ZONE COALESCE(b.timezone, c.timezone) <= $1::timestamptz
+		ORDER BY a.company_id, a.clock_in ASC
 	`
 
-	rows, err := q.Query(ctx, query)
+	rows, err := q.Query(ctx, query, now)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query stale sessions: %w", err)
+		return nil, fmt.Errorf("failed to query open sessions: %w", err)
 	}
 	defer rows.Close()
 
 	var attendances []attendance.Attendance
 	for rows.Next() {
 		var att attendance.Attendance
-		var clockOutTime time.Time
-		var isNextDay bool
-		var timezone string
-
 		err := rows.Scan(
-			&att.ID, &att.EmployeeID, &att.CompanyID, &att.Date, &att.WorkScheduleTimeID,
-			&att.ActualLocationType, &att.ClockIn, &att.ClockOut, &att.WorkHoursInMinutes,
+			&att.ID, &att.EmployeeID, &att.CompanyID, &att.Date, &att.WorkScheduleTimeID, &att.ActualLocationType, &att.WorkScheduleLocationID,
+			&att.ClockIn, &att.ClockOut, &att.WorkHoursInMinutes,
 			&att.ClockInLatitude, &att.ClockInLongitude, &att.ClockInProofURL,
 			&att.ClockOutLatitude, &att.ClockOutLongitude, &att.ClockOutProofURL,
 			&att.Status, &att.ApprovedBy, &att.ApprovedAt, &att.RejectionReason,
 			&att.LeaveTypeID, &att.LateMinutes, &att.EarlyLeaveMinutes, &att.OvertimeMinutes,
+			&att.RawLateMinutes, &att.RawOvertimeMinutes, &att.OvertimeMultiplier, &att.AutoClosed,
 			&att.CreatedAt, &att.UpdatedAt,
-			&clockOutTime, &isNextDay, &timezone,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
-
-		// Store schedule data for cron processing (using rejection_reason temporarily as metadata)
-		scheduleData := fmt.Sprintf("schedule_clock_out=%s|next_day=%t|timezone=%s",
-			clockOutTime.Format("15:04:05"), isNextDay, timezone)
-		att.RejectionReason = &scheduleData
-
 		attendances = append(attendances, att)
 	}
 
 	return attendances, rows.Err()
 }
 
+// AutoClose implements attendance.AttendanceRepository.
+func (a *attendanceRepository) AutoClose(ctx context.Context, id string, companyID string, clockOut time.Time, workMinutes int) error {
+	q := GetQuerier(ctx, a.db)
+
+	// clock_out IS NULL guards against an employee clocking out while the job runs
+	query := `
+		UPDATE attendances
+		SET clock_out = $1,
+			work_hours_in_minutes = $2,
+			overtime_minutes = 0,
+			raw_overtime_minutes = 0,
+			auto_closed = true,
+			updated_at = NOW()
+		WHERE id = $3 AND company_id = $4 AND clock_out IS NULL
+	`
+
+	commandTag, err := q.Exec(ctx, query, clockOut, workMinutes, id, companyID)
+	if err != nil {
+		return fmt.Errorf("failed to auto-close attendance: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return attendance.ErrAlreadyCheckedOut
+	}
+
+	return nil
+}
+
 // BulkCreateAbsences implements attendance.AttendanceRepository.
 func (a *attendanceRepository) BulkCreateAbsences(ctx context.Context, attendances []attendance.Attendance) error {
 	if len(attendances) == 0 {
@@ -710,3 +793,103 @@ func (a *attendanceRepository) BulkCreateAbsences(ctx context.Context, attendanc
 func NewAttendanceRepository(db *database.DB) attendance.AttendanceRepository {
 	return &attendanceRepository{db: db}
 }
+
+// GetLiveBoardEntries implements attendance.AttendanceRepository.
+func (a *attendanceRepository) GetLiveBoardEntries(ctx context.Context, companyID string, branchID *string, now time.Time) ([]attendance.LiveBoardEntry, error) {
+	q := GetQuerier(ctx, a.db)
+
+	// "Today" is resolved per employee from their branch timezone (company timezone as fallback),
+	// then the schedule for that day is picked like GetActiveSchedule: override assignment first,
+	// employee default second.
+	query := `
+		WITH roster AS (
+			SELECT
+				e.id,
+				e.full_name,
+				e.employee_code,
+				e.work_schedule_id,
+				e.branch_id,
+				b.name AS branch_name,
+				p.name AS position_name,
+				COALESCE(b.timezone, c.timezone) AS tz,
+				($2::timestamptz AT TIME ZONE COALESCE(b.timezone, c.timezone))::date AS local_date
+			FROM employees e
+			JOIN companies c ON c.id = e.company_id
+			LEFT JOIN branches b ON b.id = e.branch_id
+			LEFT JOIN positions p ON p.id = e.position_id
+			WHERE e.company_id = $1
+			  AND e.employment_status = 'active'
+			  AND e.deleted_at IS NULL
+			  AND ($3::uuid IS NULL OR e.branch_id = $3::uuid)
+		),
+		scheduled AS (
+			SELECT
+				r.*,
+				COALESCE(
+					(
+						SELECT esa.work_schedule_id
+						FROM employee_schedule_assignments esa
+						WHERE esa.employee_id = r.id
+						  AND r.local_date BETWEEN esa.start_date AND esa.end_date
+						ORDER BY esa.start_date DESC
+						LIMIT 1
+					),
+					r.work_schedule_id
+				) AS schedule_id
+			FROM roster r
+		)
+		SELECT
+			s.id, s.full_name, s.employee_code, s.position_name, s.branch_id, s.branch_name, s.local_date,
+			ws.name, to_char(wst.clock_in_time, 'HH24:MI'), to_char(wst.clock_out_time, 'HH24:MI'),
+			att.id, att.status, att.clock_in, att.clock_out,
+			to_char(att.clock_in AT TIME ZONE s.tz, 'HH24:MI'),
+			to_char(att.clock_out AT TIME ZONE s.tz, 'HH24:MI'),
+			lv.leave_type_name
+		FROM scheduled s
+		JOIN work_schedules ws ON ws.id = s.schedule_id AND ws.deleted_at IS NULL
+		JOIN work_schedule_times wst ON wst.work_schedule_id = ws.id
+			AND wst.day_of_week = EXTRACT(ISODOW FROM s.local_date)::int
+		LEFT JOIN attendances att ON att.employee_id = s.id
+			AND att.date = s.local_date
+			AND att.company_id = $1
+		LEFT JOIN LATERAL (
+			SELECT lt.name AS leave_type_name
+			FROM leave_requests lr
+			JOIN leave_types lt ON lt.id = lr.leave_type_id
+			WHERE lr.employee_id = s.id
+			  AND lr.status = 'approved'
+			  AND s.local_date BETWEEN lr.start_date AND lr.end_date
+			LIMIT 1
+		) lv ON true
+		ORDER BY s.full_name ASC, s.id ASC
+	`
+
+	rows, err := q.Query(ctx, query, companyID, now, branchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance live board: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []attendance.LiveBoardEntry{}
+	for rows.Next() {
+		var e attendance.LiveBoardEntry
+		err := rows.Scan(
+			&e.EmployeeID, &e.EmployeeName, &e.EmployeeCode, &e.PositionName, &e.BranchID, &e.BranchName, &e.Date,
+			&e.ScheduleName, &e.ScheduledClockIn, &e.ScheduledClockOut,
+			&e.AttendanceID, &e.AttendanceStatus, &e.ClockIn, &e.ClockOut,
+			&e.ClockInLocal,
+			&e.ClockOutLocal,
+			&e.LeaveTypeName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan live board entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
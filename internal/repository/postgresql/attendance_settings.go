@@ -0,0 +1,94 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+type attendanceSettingsRepository struct {
+	db *database.DB
+}
+
+// NewAttendanceSettingsRepository creates a new attendance settings repository
+func NewAttendanceSettingsRepository(db *database.DB) attendance.AttendanceSettingsRepository {
+	return &attendanceSettingsRepository{db: db}
+}
+
+// GetSettings implements attendance.AttendanceSettingsRepository.
+func (r *attendanceSettingsRepository) GetSettings(ctx context.Context, companyID string) (attendance.AttendanceSettings, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT id, company_id, late_rounding_mode, late_rounding_minutes,
+			   overtime_rounding_mode, overtime_rounding_minutes, auto_close_max_work_minutes,
+			   overtime_min_minutes, overtime_daily_cap_minutes, weekend_overtime_rate, holiday_overtime_rate,
+			   created_at, updated_at
+		FROM attendance_settings
+		WHERE company_id = $1
+	`
+
+	var s attendance.AttendanceSettings
+	err := q.QueryRow(ctx, query, companyID).Scan(
+		&s.ID, &s.CompanyID, &s.LateRoundingMode, &s.LateRoundingMinutes,
+		&s.OvertimeRoundingMode, &s.OvertimeRoundingMinutes, &s.AutoCloseMaxWorkMinutes,
+		&s.OvertimeMinMinutes, &s.OvertimeDailyCapMinutes, &s.WeekendOvertimeRate, &s.HolidayOvertimeRate,
+		&s.CreatedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return attendance.AttendanceSettings{}, attendance.ErrAttendanceSettingsNotFound
+		}
+		return attendance.AttendanceSettings{}, fmt.Errorf("failed to get attendance settings: %w", err)
+	}
+
+	return s, nil
+}
+
+// UpsertSettings implements attendance.AttendanceSettingsRepository.
+func (r *attendanceSettingsRepository) UpsertSettings(ctx context.Context, settings attendance.AttendanceSettings) (attendance.AttendanceSettings, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		INSERT INTO attendance_settings (
+			company_id, late_rounding_mode, late_rounding_minutes,
+			overtime_rounding_mode, overtime_rounding_minutes, auto_close_max_work_minutes,
+			overtime_min_minutes, overtime_daily_cap_minutes, weekend_overtime_rate, holiday_overtime_rate
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (company_id) DO UPDATE SET
+			late_rounding_mode = EXCLUDED.late_rounding_mode,
+			late_rounding_minutes = EXCLUDED.late_rounding_minutes,
+			overtime_rounding_mode = EXCLUDED.overtime_rounding_mode,
+			overtime_rounding_minutes = EXCLUDED.overtime_rounding_minutes,
+			auto_close_max_work_minutes = EXCLUDED.auto_close_max_work_minutes,
+			overtime_min_minutes = EXCLUDED.overtime_min_minutes,
+			overtime_daily_cap_minutes = EXCLUDED.overtime_daily_cap_minutes,
+			weekend_overtime_rate = EXCLUDED.weekend_overtime_rate,
+			holiday_overtime_rate = EXCLUDED.holiday_overtime_rate,
+			updated_at = NOW()
+		RETURNING id, company_id, late_rounding_mode, late_rounding_minutes,
+			overtime_rounding_mode, overtime_rounding_minutes, auto_close_max_work_minutes,
+			overtime_min_minutes, overtime_daily_cap_minutes, weekend_overtime_rate, holiday_overtime_rate,
+			created_at, updated_at
+	`
+
+	var s attendance.AttendanceSettings
+	err := q.QueryRow(ctx, query,
+		settings.CompanyID, settings.LateRoundingMode, settings.LateRoundingMinutes,
+		settings.OvertimeRoundingMode, settings.OvertimeRoundingMinutes, settings.AutoCloseMaxWorkMinutes,
+		settings.OvertimeMinMinutes, settings.OvertimeDailyCapMinutes, settings.WeekendOvertimeRate, settings.HolidayOvertimeRate,
+	).Scan(
+		&s.ID, &s.CompanyID, &s.LateRoundingMode, &s.LateRoundingMinutes,
+		&s.OvertimeRoundingMode, &s.OvertimeRoundingMinutes, &s.AutoCloseMaxWorkMinutes,
+		&s.OvertimeMinMinutes, &s.OvertimeDailyCapMinutes, &s.WeekendOvertimeRate, &s.HolidayOvertimeRate,
+		&s.CreatedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		return attendance.AttendanceSettings{}, fmt.Errorf("failed to upsert attendance settings: %w", err)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,91 @@
+package postgresql
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// attendanceRow returns the columns GetStaleOpenSessions selects, in order
+func attendanceRow(id string, date, clockIn time.Time) []any {
+	return []any{
+		id, "emp-1", "company-1", date, "wst-1", "wfo", nil,
+		clockIn, nil, nil,
+		-6.2, 106.8, nil,
+		nil, nil, nil,
+		"waiting_approval", nil, nil, nil,
+		nil, 0, nil, nil,
+		0, nil, nil, false,
+		clockIn, clockIn,
+	}
+}
+
+func TestAttendanceRepositoryGetStaleOpenSessions(t *testing.T) {
+	now := time.Date(2026, 3, 4, 2, 0, 0, 0, time.UTC)
+	missed := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	overnight := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	tx := newFakeTx(fakeResult{rows: [][]any{
+		attendanceRow("att-1", missed, missed.Add(time.Hour)),
+		attendanceRow("att-2", overnight, overnight.Add(15*time.Hour)),
+	}})
+	repo := &attendanceRepository{}
+
+	sessions, err := repo.GetStaleOpenSessions(tx.ctx(), now)
+	if err != nil {
+		t.Fatalf("GetStaleOpenSessions() error = %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != "att-1" || sessions[1].ID != "att-2" {
+		t.Fatalf("GetStaleOpenSessions() = %+v, want att-1 and att-2", sessions)
+	}
+	if s := sessions[0]; *s.WorkScheduleTimeID != "wst-1" || s.ClockOut != nil || !s.ClockIn.Equal(missed.Add(time.Hour)) {
+		t.Errorf("first session = %+v", s)
+	}
+
+	call := tx.calls[0]
+	if len(call.args) != 1 || call.args[0] != now {
+		t.Errorf("query args = %v, want [%v]", call.args, now)
+	}
+	sql := compactSQL(call.sql)
+	for _, want := range []string{
+		// before today in the employee's timezone, not just yesterday in UTC
+		"a.date < ($1::timestamptz AT TIME ZONE COALESCE(b.timezone, c.timezone))::date",
+		// the shift, overnight ones included, has ended
+		"INTERVAL '1 day'",
+		"AT TIME ZONE COALESCE(b.timezone, c.timezone) <= $1::timestamptz",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("query is missing %q:\n%s", want, sql)
+		}
+	}
+}
+
+func TestAttendanceRepositoryIsOnApprovedLeave(t *testing.T) {
+	tx := newFakeTx(fakeResult{rows: [][]any{{true}}})
+	repo := &attendanceRepository{}
+
+	onLeave, err := repo.IsOnApprovedLeave(tx.ctx(), "emp-1", "2026-06-01", "company-1")
+	if err != nil {
+		t.Fatalf("IsOnApprovedLeave() error = %v", err)
+	}
+	if !onLeave {
+		t.Errorf("IsOnApprovedLeave() = false, want true")
+	}
+
+	call := tx.calls[0]
+	if len(call.args) != 3 || call.args[0] != "emp-1" || call.args[1] != "2026-06-01" || call.args[2] != "company-1" {
+		t.Errorf("query args = %v, want [emp-1 2026-06-01 company-1]", call.args)
+	}
+	sql := compactSQL(call.sql)
+	for _, want := range []string{
+		// the leave attendance record written on approval
+		"leave_type_id IS NOT NULL",
+		// or an approved request covering the day, its first and last day included
+		"lr.status = 'approved'",
+		"$2::date BETWEEN lr.start_date AND lr.end_date",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("query is missing %q:\n%s", want, sql)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/audit"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+)
+
+type auditRepositoryImpl struct {
+	db *database.DB
+}
+
+// NewAuditRepository creates a new instance of AuditRepository.
+func NewAuditRepository(db *database.DB) audit.AuditRepository {
+	return &auditRepositoryImpl{db: db}
+}
+
+// Create implements audit.AuditRepository.
+func (r *auditRepositoryImpl) Create(ctx context.Context, entry audit.Entry) error {
+	q := GetQuerier(ctx, r.db)
+
+	var oldValue, newValue []byte
+	var err error
+	if entry.OldValue != nil {
+		if oldValue, err = json.Marshal(entry.OldValue); err != nil {
+			return fmt.Errorf("failed to marshal audit old value: %w", err)
+		}
+	}
+	if entry.NewValue != nil {
+		if newValue, err = json.Marshal(entry.NewValue); err != nil {
+			return fmt.Errorf("failed to marshal audit new value: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO audit_trails (user_id, action, table_name, record_id, old_value, new_value, description)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if _, err := q.Exec(ctx, query, entry.UserID, string(entry.Action), entry.TableName, entry.RecordID, oldValue, newValue, entry.Description); err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+
+	return nil
+}
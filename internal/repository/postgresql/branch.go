@@ -21,10 +21,12 @@ func NewBranchRepository(db *database.DB) branch.BranchRepository {
 func (r *branchRepositoryImpl) GetTimezoneByEmployeeID(ctx context.Context, employeeID string, companyID string) (string, error) {
 	q := GetQuerier(ctx, r.db)
 
+	// Employees without a branch fall back to the company timezone
 	query := `
-		SELECT b.timezone
-		FROM branches b
-		JOIN employees e ON b.id = e.branch_id
+		SELECT COALESCE(b.timezone, c.timezone)
+		FROM employees e
+		JOIN companies c ON c.id = e.company_id
+		LEFT JOIN branches b ON b.id = e.branch_id
 		WHERE e.id = $1 AND e.company_id = $2
 	`
 
@@ -32,7 +34,7 @@ func (r *branchRepositoryImpl) GetTimezoneByEmployeeID(ctx context.Context, empl
 	err := q.QueryRow(ctx, query, employeeID, companyID).Scan(&timezone)
 
 	if err == pgx.ErrNoRows {
-		return "", fmt.Errorf("employee or branch not found: %w", err)
+		return "", fmt.Errorf("employee not found: %w", err)
 	}
 
 	if err != nil {
@@ -222,3 +224,56 @@ func (r *branchRepositoryImpl) Delete(ctx context.Context, id string, companyID
 
 	return nil
 }
+
+// CountReferences implements branch.BranchRepository.
+// Counts employees, job history rows and branch-scoped managers that still point to the branch.
+func (r *branchRepositoryImpl) CountReferences(ctx context.Context, id string, companyID string) (int, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM employees WHERE branch_id = $1 AND company_id = $2)
+			+
+			(SELECT COUNT(*)
+			 FROM employee_job_history h
+			 JOIN employees e ON e.id = h.employee_id
+			 WHERE h.branch_id = $1 AND e.company_id = $2)
+			+
+			(SELECT COUNT(*) FROM users WHERE managed_branch_id = $1 AND company_id = $2)
+	`
+
+	var count int
+	if err := q.QueryRow(ctx, query, id, companyID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count branch references: %w", err)
+	}
+
+	return count, nil
+}
+
+// ReassignReferences implements branch.BranchRepository.
+// Moves employees and branch-scoped managers (users.managed_branch_id), the only manager reference
+// to a branch, from one branch to another. Job history keeps recording the branch employees
+// actually worked at, so a branch that appears in it stays in use.
+func (r *branchRepositoryImpl) ReassignReferences(ctx context.Context, fromID, toID string, companyID string) error {
+	q := GetQuerier(ctx, r.db)
+
+	employeesQuery := `
+		UPDATE employees
+		SET branch_id = $2, updated_at = NOW()
+		WHERE branch_id = $1 AND company_id = $3
+	`
+	if _, err := q.Exec(ctx, employeesQuery, fromID, toID, companyID); err != nil {
+		return fmt.Errorf("failed to reassign employees: %w", err)
+	}
+
+	managersQuery := `
+		UPDATE users
+		SET managed_branch_id = $2, updated_at = NOW()
+		WHERE managed_branch_id = $1 AND company_id = $3
+	`
+	if _, err := q.Exec(ctx, managersQuery, fromID, toID, companyID); err != nil {
+		return fmt.Errorf("failed to reassign branch-scoped managers: %w", err)
+	}
+
+	return nil
+}
@@ -66,6 +66,18 @@ func (c *companyRepositoryImpl) Update(ctx context.Context, id string, req compa
 			updates["logo_url"] = *req.LogoURL
 		}
 	}
+	if req.Timezone != nil && *req.Timezone != "" {
+		updates["timezone"] = *req.Timezone
+	}
+	if req.LeaveYearStartMonth != nil {
+		updates["leave_year_start_month"] = *req.LeaveYearStartMonth
+	}
+	if req.LeaveApprovalSLAHours != nil {
+		updates["leave_approval_sla_hours"] = *req.LeaveApprovalSLAHours
+	}
+	if req.LeaveEscalationAction != nil {
+		updates["leave_escalation_action"] = *req.LeaveEscalationAction
+	}
 
 	if len(updates) == 0 {
 		return fmt.Errorf("no updatable fields provided for company update")
@@ -130,7 +142,7 @@ func (c *companyRepositoryImpl) Create(ctx context.Context, newCompany company.C
 	query := `
 		INSERT INTO companies (name, username, address, logo_url)
 		VALUES ($1, $2, $3, $4)
-		RETURNING id, name, username, address, logo_url, created_at, updated_at
+		RETURNING id, name, username, address, logo_url, timezone, leave_year_start_month, leave_approval_sla_hours, leave_escalation_action, created_at, updated_at
 	`
 
 	var created company.Company
@@ -150,7 +162,7 @@ func (c *companyRepositoryImpl) Create(ctx context.Context, newCompany company.C
 	}
 
 	err := q.QueryRow(ctx, query, newCompany.Name, newCompany.Username, addr, logo).
-		Scan(&created.ID, &created.Name, &created.Username, &created.Address, &created.LogoURL, &created.CreatedAt, &created.UpdatedAt)
+		Scan(&created.ID, &created.Name, &created.Username, &created.Address, &created.LogoURL, &created.Timezone, &created.LeaveYearStartMonth, &created.LeaveApprovalSLAHours, &created.LeaveEscalationAction, &created.CreatedAt, &created.UpdatedAt)
 	if err != nil {
 		return company.Company{}, err
 	}
@@ -162,14 +174,14 @@ func (c *companyRepositoryImpl) GetByID(ctx context.Context, id string) (company
 	q := GetQuerier(ctx, c.db)
 
 	query := `
-		SELECT id, name, username, address, logo_url, created_at, updated_at, deleted_at
+		SELECT id, name, username, address, logo_url, timezone, leave_year_start_month, leave_approval_sla_hours, leave_escalation_action, created_at, updated_at, deleted_at
 		FROM companies
 		WHERE id = $1
 	`
 
 	var found company.Company
 	err := q.QueryRow(ctx, query, id).
-		Scan(&found.ID, &found.Name, &found.Username, &found.Address, &found.LogoURL, &found.CreatedAt, &found.UpdatedAt, &found.DeletedAt)
+		Scan(&found.ID, &found.Name, &found.Username, &found.Address, &found.LogoURL, &found.Timezone, &found.LeaveYearStartMonth, &found.LeaveApprovalSLAHours, &found.LeaveEscalationAction, &found.CreatedAt, &found.UpdatedAt, &found.DeletedAt)
 	if err != nil {
 		return company.Company{}, err
 	}
@@ -196,3 +208,66 @@ func (c *companyRepositoryImpl) GetByUsername(ctx context.Context, username stri
 
 	return found, nil
 }
+
+// GetOnboardingCounts implements company.CompanyRepository.
+func (c *companyRepositoryImpl) GetOnboardingCounts(ctx context.Context, id string) (company.OnboardingCounts, error) {
+	q := GetQuerier(ctx, c.db)
+
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM branches WHERE company_id = $1),
+			(SELECT COUNT(*) FROM positions WHERE company_id = $1),
+			(SELECT COUNT(*) FROM leave_types WHERE company_id = $1 AND is_active = true),
+			(SELECT COUNT(*) FROM work_schedules WHERE company_id = $1 AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM employees WHERE company_id = $1 AND deleted_at IS NULL AND employment_status = 'active')
+	`
+
+	var counts company.OnboardingCounts
+	if err := q.QueryRow(ctx, query, id).Scan(
+		&counts.Branches,
+		&counts.Positions,
+		&counts.LeaveTypes,
+		&counts.WorkSchedules,
+		&counts.ActiveEmployees,
+	); err != nil {
+		return company.OnboardingCounts{}, fmt.Errorf("failed to get company onboarding counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetTimezone implements company.CompanyRepository.
+func (c *companyRepositoryImpl) GetTimezone(ctx context.Context, id string) (string, error) {
+	q := GetQuerier(ctx, c.db)
+
+	query := `
+		SELECT timezone
+		FROM companies
+		WHERE id = $1
+	`
+
+	var timezone string
+	if err := q.QueryRow(ctx, query, id).Scan(&timezone); err != nil {
+		return "", fmt.Errorf("failed to get company timezone: %w", err)
+	}
+
+	return timezone, nil
+}
+
+// GetLeaveYearStartMonth implements company.CompanyRepository.
+func (c *companyRepositoryImpl) GetLeaveYearStartMonth(ctx context.Context, id string) (int, error) {
+	q := GetQuerier(ctx, c.db)
+
+	query := `
+		SELECT leave_year_start_month
+		FROM companies
+		WHERE id = $1
+	`
+
+	var month int
+	if err := q.QueryRow(ctx, query, id).Scan(&month); err != nil {
+		return 0, fmt.Errorf("failed to get company leave year start month: %w", err)
+	}
+
+	return month, nil
+}
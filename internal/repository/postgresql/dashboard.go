@@ -190,3 +190,142 @@ func (r *dashboardRepositoryImpl) GetMonthlyAttendanceWithRecords(ctx context.Co
 
 	return &data, nil
 }
+
+// GetHeadcountTotals returns active headcount now and at since, plus departures since then, in single query
+func (r *dashboardRepositoryImpl) GetHeadcountTotals(ctx context.Context, companyID string, since time.Time) (*dashboard.HeadcountTotals, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT 
+			COALESCE(SUM(CASE WHEN employment_status = 'active' THEN 1 ELSE 0 END), 0) as active_count,
+			COALESCE(SUM(CASE WHEN hire_date <= $2 AND (resignation_date IS NULL OR resignation_date > $2) THEN 1 ELSE 0 END), 0) as active_at_start,
+			COALESCE(SUM(CASE WHEN employment_status IN ('resigned', 'terminated') AND resignation_date >= $2 THEN 1 ELSE 0 END), 0) as resign_count
+		FROM employees 
+		WHERE company_id = $1 AND deleted_at IS NULL
+	`
+
+	var totals dashboard.HeadcountTotals
+	err := q.QueryRow(ctx, query, companyID, since).Scan(
+		&totals.Active, &totals.ActiveAtStart, &totals.Resignations,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get headcount totals: %w", err)
+	}
+	return &totals, nil
+}
+
+// GetHeadcountByDimension returns active headcount grouped by the given dimension, largest group first.
+// Employees without a value, or whose branch, position or grade is gone, count under HeadcountUnassignedLabel.
+func (r *dashboardRepositoryImpl) GetHeadcountByDimension(ctx context.Context, companyID string, dimension dashboard.HeadcountDimension) ([]dashboard.HeadcountGroupStats, error) {
+	q := GetQuerier(ctx, r.db)
+
+	var query string
+	switch dimension {
+	case dashboard.HeadcountByBranch:
+		query = `
+			SELECT b.id::text, COALESCE(b.name, $2), COUNT(*)
+			FROM employees e
+			LEFT JOIN branches b ON e.branch_id = b.id
+			WHERE e.company_id = $1 AND e.deleted_at IS NULL AND e.employment_status = 'active'
+			GROUP BY b.id, b.name
+			ORDER BY COUNT(*) DESC, b.name NULLS LAST
+		`
+	case dashboard.HeadcountByPosition:
+		query = `
+			SELECT p.id::text, COALESCE(p.name, $2), COUNT(*)
+			FROM employees e
+			LEFT JOIN positions p ON e.position_id = p.id
+			WHERE e.company_id = $1 AND e.deleted_at IS NULL AND e.employment_status = 'active'
+			GROUP BY p.id, p.name
+			ORDER BY COUNT(*) DESC, p.name NULLS LAST
+		`
+	case dashboard.HeadcountByGrade:
+		query = `
+			SELECT g.id::text, COALESCE(g.name, $2), COUNT(*)
+			FROM employees e
+			LEFT JOIN grades g ON e.grade_id = g.id
+			WHERE e.company_id = $1 AND e.deleted_at IS NULL AND e.employment_status = 'active'
+			GROUP BY g.id, g.name
+			ORDER BY COUNT(*) DESC, g.name NULLS LAST
+		`
+	case dashboard.HeadcountByEmploymentType:
+		query = `
+			SELECT NULL::text, COALESCE(e.employment_type::text, $2), COUNT(*)
+			FROM employees e
+			WHERE e.company_id = $1 AND e.deleted_at IS NULL AND e.employment_status = 'active'
+			GROUP BY e.employment_type
+			ORDER BY COUNT(*) DESC, e.employment_type NULLS LAST
+		`
+	default:
+		return nil, fmt.Errorf("unknown headcount dimension: %s", dimension)
+	}
+
+	rows, err := q.Query(ctx, query, companyID, dashboard.HeadcountUnassignedLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get headcount by %s: %w", dimension, err)
+	}
+	defer rows.Close()
+
+	groups := []dashboard.HeadcountGroupStats{}
+	for rows.Next() {
+		var group dashboard.HeadcountGroupStats
+		if err := rows.Scan(&group.ID, &group.Label, &group.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan headcount by %s: %w", dimension, err)
+		}
+		groups = append(groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// GetHeadcountMonthlyMovements returns hires and departures per month, including months without any
+func (r *dashboardRepositoryImpl) GetHeadcountMonthlyMovements(ctx context.Context, companyID string, since time.Time, months int) ([]dashboard.HeadcountMonthlyStats, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT 
+			m.month::date,
+			COUNT(e.id) FILTER (WHERE e.hire_date >= m.month AND e.hire_date < m.month + INTERVAL '1 month') as new_hires,
+			COUNT(e.id) FILTER (
+				WHERE e.employment_status IN ('resigned', 'terminated')
+				AND e.resignation_date >= m.month AND e.resignation_date < m.month + INTERVAL '1 month'
+			) as resignations
+		FROM generate_series(
+			date_trunc('month', $2::timestamp),
+			date_trunc('month', $2::timestamp) + make_interval(months => $3::int - 1),
+			INTERVAL '1 month'
+		) AS m(month)
+		LEFT JOIN employees e ON e.company_id = $1 AND e.deleted_at IS NULL
+			AND (
+				(e.hire_date >= m.month AND e.hire_date < m.month + INTERVAL '1 month')
+				OR (e.resignation_date >= m.month AND e.resignation_date < m.month + INTERVAL '1 month')
+			)
+		GROUP BY m.month
+		ORDER BY m.month
+	`
+
+	rows, err := q.Query(ctx, query, companyID, since, months)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get headcount monthly movements: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]dashboard.HeadcountMonthlyStats, 0, months)
+	for rows.Next() {
+		var month dashboard.HeadcountMonthlyStats
+		if err := rows.Scan(&month.Month, &month.NewHires, &month.Resignations); err != nil {
+			return nil, fmt.Errorf("failed to scan headcount monthly movements: %w", err)
+		}
+		stats = append(stats, month)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
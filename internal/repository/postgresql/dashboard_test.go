@@ -0,0 +1,58 @@
+package postgresql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/dashboard"
+)
+
+func TestDashboardRepositoryGetHeadcountByDimensionKeepsUnassigned(t *testing.T) {
+	dimensions := []dashboard.HeadcountDimension{
+		dashboard.HeadcountByBranch,
+		dashboard.HeadcountByPosition,
+		dashboard.HeadcountByGrade,
+		dashboard.HeadcountByEmploymentType,
+	}
+
+	for _, dimension := range dimensions {
+		t.Run(string(dimension), func(t *testing.T) {
+			tx := newFakeTx(fakeResult{rows: [][]any{
+				{"group-1", "Jakarta", int64(7)},
+				{nil, dashboard.HeadcountUnassignedLabel, int64(2)},
+			}})
+			repo := &dashboardRepositoryImpl{}
+
+			groups, err := repo.GetHeadcountByDimension(tx.ctx(), "company-1", dimension)
+			if err != nil {
+				t.Fatalf("GetHeadcountByDimension() error = %v", err)
+			}
+			if len(groups) != 2 || groups[1].ID != nil || groups[1].Label != dashboard.HeadcountUnassignedLabel || groups[1].Count != 2 {
+				t.Errorf("GetHeadcountByDimension() = %+v, want the unassigned group last", groups)
+			}
+
+			call := tx.calls[0]
+			fields := strings.Fields(call.sql)
+			for i, field := range fields {
+				if field == "JOIN" && fields[i-1] != "LEFT" {
+					t.Errorf("query uses an inner join, dropping employees without a %s:\n%s", dimension, call.sql)
+				}
+			}
+			if len(call.args) != 2 || call.args[1] != dashboard.HeadcountUnassignedLabel {
+				t.Errorf("args = %v, want the unassigned label", call.args)
+			}
+		})
+	}
+}
+
+func TestDashboardRepositoryGetHeadcountByDimensionUnknown(t *testing.T) {
+	tx := newFakeTx()
+	repo := &dashboardRepositoryImpl{}
+
+	if _, err := repo.GetHeadcountByDimension(tx.ctx(), "company-1", "department"); err == nil {
+		t.Error("GetHeadcountByDimension() error = nil for an unknown dimension")
+	}
+	if len(tx.calls) != 0 {
+		t.Errorf("queries = %d, want none", len(tx.calls))
+	}
+}
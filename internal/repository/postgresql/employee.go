@@ -52,9 +52,10 @@ func (e *employeeRepositoryImpl) GetActiveByCompanyID(ctx context.Context, compa
 
 	query := `
 		SELECT id, user_id, company_id, work_schedule_id, position_id, grade_id, branch_id, employee_code,
-			full_name, nik, gender, phone_number, address, place_of_birth, dob, avatar_url, education,
+			full_name, COALESCE(nik, '') AS nik, gender, COALESCE(phone_number, '') AS phone_number, address, place_of_birth, dob, avatar_url, education,
 			hire_date, resignation_date, employment_type, employment_status, warning_letter,
-			bank_name, bank_account_holder_name, bank_account_number, base_salary, created_at, updated_at, deleted_at
+			COALESCE(bank_name, '') AS bank_name, bank_account_holder_name, COALESCE(bank_account_number, '') AS bank_account_number, base_salary,
+			emergency_contact_name, emergency_contact_relationship, emergency_contact_phone, created_at, updated_at, deleted_at
 		FROM employees
 		WHERE company_id = $1 AND employment_status = $2 AND deleted_at IS NULL
 	`
@@ -75,7 +76,8 @@ func (e *employeeRepositoryImpl) GetActiveByCompanyID(ctx context.Context, compa
 			&emp.AvatarURL, &emp.Education, &emp.HireDate, &emp.ResignationDate,
 			&emp.EmploymentType, &emp.EmploymentStatus, &emp.WarningLetter,
 			&emp.BankName, &emp.BankAccountHolderName, &emp.BankAccountNumber,
-			&emp.BaseSalary, &emp.CreatedAt, &emp.UpdatedAt, &emp.DeletedAt,
+			&emp.BaseSalary,
+			&emp.EmergencyContactName, &emp.EmergencyContactRelationship, &emp.EmergencyContactPhone, &emp.CreatedAt, &emp.UpdatedAt, &emp.DeletedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -99,17 +101,20 @@ func (e *employeeRepositoryImpl) Create(ctx context.Context, newEmployee employe
 			user_id, company_id, work_schedule_id, position_id, grade_id, branch_id, employee_code,
 			full_name, nik, gender, phone_number, address, place_of_birth, dob, avatar_url, education,
 			hire_date, resignation_date, employment_type, employment_status, warning_letter,
-			bank_name, bank_account_holder_name, bank_account_number, base_salary
+			bank_name, bank_account_holder_name, bank_account_number, base_salary,
+			emergency_contact_name, emergency_contact_relationship, emergency_contact_phone
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7,
 			$8, $9, $10, $11, $12, $13, $14, $15, $16,
 			$17, $18, $19, $20, $21,
-			$22, $23, $24, $25
+			$22, $23, $24, $25,
+			$26, $27, $28
 		)
 		RETURNING id, user_id, company_id, work_schedule_id, position_id, grade_id, branch_id, employee_code,
-			full_name, nik, gender, phone_number, address, place_of_birth, dob, avatar_url, education,
+			full_name, COALESCE(nik, '') AS nik, gender, COALESCE(phone_number, '') AS phone_number, address, place_of_birth, dob, avatar_url, education,
 			hire_date, resignation_date, employment_type, employment_status, warning_letter,
-			bank_name, bank_account_holder_name, bank_account_number, base_salary, created_at, updated_at, deleted_at
+			COALESCE(bank_name, '') AS bank_name, bank_account_holder_name, COALESCE(bank_account_number, '') AS bank_account_number, base_salary,
+			emergency_contact_name, emergency_contact_relationship, emergency_contact_phone, created_at, updated_at, deleted_at
 	`
 
 	var created employee.Employee
@@ -120,6 +125,7 @@ func (e *employeeRepositoryImpl) Create(ctx context.Context, newEmployee employe
 		newEmployee.AvatarURL, newEmployee.Education, newEmployee.HireDate, newEmployee.ResignationDate,
 		newEmployee.EmploymentType, newEmployee.EmploymentStatus, newEmployee.WarningLetter,
 		newEmployee.BankName, newEmployee.BankAccountHolderName, newEmployee.BankAccountNumber, newEmployee.BaseSalary,
+		newEmployee.EmergencyContactName, newEmployee.EmergencyContactRelationship, newEmployee.EmergencyContactPhone,
 	).Scan(
 		&created.ID, &created.UserID, &created.CompanyID, &created.WorkScheduleID, &created.PositionID,
 		&created.GradeID, &created.BranchID, &created.EmployeeCode, &created.FullName, &created.NIK,
@@ -127,7 +133,8 @@ func (e *employeeRepositoryImpl) Create(ctx context.Context, newEmployee employe
 		&created.AvatarURL, &created.Education, &created.HireDate, &created.ResignationDate,
 		&created.EmploymentType, &created.EmploymentStatus, &created.WarningLetter,
 		&created.BankName, &created.BankAccountHolderName, &created.BankAccountNumber,
-		&created.BaseSalary, &created.CreatedAt, &created.UpdatedAt, &created.DeletedAt,
+		&created.BaseSalary,
+		&created.EmergencyContactName, &created.EmergencyContactRelationship, &created.EmergencyContactPhone, &created.CreatedAt, &created.UpdatedAt, &created.DeletedAt,
 	)
 	if err != nil {
 		return employee.Employee{}, err
@@ -171,9 +178,10 @@ func (e *employeeRepositoryImpl) GetByEmployeeCode(ctx context.Context, companyI
 
 	query := `
 		SELECT id, user_id, company_id, work_schedule_id, position_id, grade_id, branch_id, employee_code,
-			full_name, nik, gender, phone_number, address, place_of_birth, dob, avatar_url, education,
+			full_name, COALESCE(nik, '') AS nik, gender, COALESCE(phone_number, '') AS phone_number, address, place_of_birth, dob, avatar_url, education,
 			hire_date, resignation_date, employment_type, employment_status, warning_letter,
-			bank_name, bank_account_holder_name, bank_account_number, base_salary, created_at, updated_at, deleted_at
+			COALESCE(bank_name, '') AS bank_name, bank_account_holder_name, COALESCE(bank_account_number, '') AS bank_account_number, base_salary,
+			emergency_contact_name, emergency_contact_relationship, emergency_contact_phone, created_at, updated_at, deleted_at
 		FROM employees
 		WHERE employee_code = $1 AND company_id = $2 AND deleted_at IS NULL
 	`
@@ -187,7 +195,8 @@ func (e *employeeRepositoryImpl) GetByEmployeeCode(ctx context.Context, companyI
 			&found.AvatarURL, &found.Education, &found.HireDate, &found.ResignationDate,
 			&found.EmploymentType, &found.EmploymentStatus, &found.WarningLetter,
 			&found.BankName, &found.BankAccountHolderName, &found.BankAccountNumber,
-			&found.BaseSalary, &found.CreatedAt, &found.UpdatedAt, &found.DeletedAt,
+			&found.BaseSalary,
+			&found.EmergencyContactName, &found.EmergencyContactRelationship, &found.EmergencyContactPhone, &found.CreatedAt, &found.UpdatedAt, &found.DeletedAt,
 		)
 	if err != nil {
 		return employee.Employee{}, err
@@ -202,9 +211,10 @@ func (e *employeeRepositoryImpl) GetByID(ctx context.Context, id string) (employ
 
 	query := `
 		SELECT id, user_id, company_id, work_schedule_id, position_id, grade_id, branch_id, employee_code,
-			full_name, nik, gender, phone_number, address, place_of_birth, dob, avatar_url, education,
+			full_name, COALESCE(nik, '') AS nik, gender, COALESCE(phone_number, '') AS phone_number, address, place_of_birth, dob, avatar_url, education,
 			hire_date, resignation_date, employment_type, employment_status, warning_letter,
-			bank_name, bank_account_holder_name, bank_account_number, base_salary, created_at, updated_at, deleted_at
+			COALESCE(bank_name, '') AS bank_name, bank_account_holder_name, COALESCE(bank_account_number, '') AS bank_account_number, base_salary,
+			emergency_contact_name, emergency_contact_relationship, emergency_contact_phone, created_at, updated_at, deleted_at
 		FROM employees
 		WHERE id = $1
 	`
@@ -218,7 +228,8 @@ func (e *employeeRepositoryImpl) GetByID(ctx context.Context, id string) (employ
 			&found.AvatarURL, &found.Education, &found.HireDate, &found.ResignationDate,
 			&found.EmploymentType, &found.EmploymentStatus, &found.WarningLetter,
 			&found.BankName, &found.BankAccountHolderName, &found.BankAccountNumber,
-			&found.BaseSalary, &found.CreatedAt, &found.UpdatedAt, &found.DeletedAt,
+			&found.BaseSalary,
+			&found.EmergencyContactName, &found.EmergencyContactRelationship, &found.EmergencyContactPhone, &found.CreatedAt, &found.UpdatedAt, &found.DeletedAt,
 		)
 	if err != nil {
 		return employee.Employee{}, err
@@ -233,9 +244,10 @@ func (e *employeeRepositoryImpl) GetByUserID(ctx context.Context, userID string)
 
 	query := `
 		SELECT id, user_id, company_id, work_schedule_id, position_id, grade_id, branch_id, employee_code,
-			full_name, nik, gender, phone_number, address, place_of_birth, dob, avatar_url, education,
+			full_name, COALESCE(nik, '') AS nik, gender, COALESCE(phone_number, '') AS phone_number, address, place_of_birth, dob, avatar_url, education,
 			hire_date, resignation_date, employment_type, employment_status, warning_letter,
-			bank_name, bank_account_holder_name, bank_account_number, base_salary, created_at, updated_at, deleted_at
+			COALESCE(bank_name, '') AS bank_name, bank_account_holder_name, COALESCE(bank_account_number, '') AS bank_account_number, base_salary,
+			emergency_contact_name, emergency_contact_relationship, emergency_contact_phone, created_at, updated_at, deleted_at
 		FROM employees
 		WHERE user_id = $1
 	`
@@ -249,7 +261,8 @@ func (e *employeeRepositoryImpl) GetByUserID(ctx context.Context, userID string)
 			&found.AvatarURL, &found.Education, &found.HireDate, &found.ResignationDate,
 			&found.EmploymentType, &found.EmploymentStatus, &found.WarningLetter,
 			&found.BankName, &found.BankAccountHolderName, &found.BankAccountNumber,
-			&found.BaseSalary, &found.CreatedAt, &found.UpdatedAt, &found.DeletedAt,
+			&found.BaseSalary,
+			&found.EmergencyContactName, &found.EmergencyContactRelationship, &found.EmergencyContactPhone, &found.CreatedAt, &found.UpdatedAt, &found.DeletedAt,
 		)
 	if err != nil {
 		return employee.Employee{}, err
@@ -382,6 +395,27 @@ func (e *employeeRepositoryImpl) Update(ctx context.Context, id string, companyI
 	if req.BaseSalary != nil {
 		updates["base_salary"] = *req.BaseSalary
 	}
+	if req.EmergencyContactName != nil {
+		if *req.EmergencyContactName == "" {
+			updates["emergency_contact_name"] = nil
+		} else {
+			updates["emergency_contact_name"] = *req.EmergencyContactName
+		}
+	}
+	if req.EmergencyContactRelationship != nil {
+		if *req.EmergencyContactRelationship == "" {
+			updates["emergency_contact_relationship"] = nil
+		} else {
+			updates["emergency_contact_relationship"] = *req.EmergencyContactRelationship
+		}
+	}
+	if req.EmergencyContactPhone != nil {
+		if *req.EmergencyContactPhone == "" {
+			updates["emergency_contact_phone"] = nil
+		} else {
+			updates["emergency_contact_phone"] = *req.EmergencyContactPhone
+		}
+	}
 
 	if len(updates) == 0 {
 		return nil // No updates provided
@@ -417,10 +451,11 @@ func (e *employeeRepositoryImpl) GetByIDWithDetails(ctx context.Context, id stri
 	query := `
 		SELECT 
 			e.id, e.user_id, e.company_id, e.work_schedule_id, e.position_id, e.grade_id, e.branch_id, 
-			e.employee_code, e.full_name, e.nik, e.gender, e.phone_number, e.address, e.place_of_birth, 
+			e.employee_code, e.full_name, COALESCE(e.nik, '') AS nik, e.gender, COALESCE(e.phone_number, '') AS phone_number, e.address, e.place_of_birth, 
 			e.dob, e.avatar_url, e.education, e.hire_date, e.resignation_date, e.employment_type, 
-			e.employment_status, e.warning_letter, e.bank_name, e.bank_account_holder_name, 
-			e.bank_account_number, e.base_salary, e.created_at, e.updated_at, e.deleted_at,
+			e.employment_status, e.warning_letter, COALESCE(e.bank_name, '') AS bank_name, e.bank_account_holder_name, 
+			COALESCE(e.bank_account_number, '') AS bank_account_number, e.base_salary,
+			e.emergency_contact_name, e.emergency_contact_relationship, e.emergency_contact_phone, e.created_at, e.updated_at, e.deleted_at,
 			ws.name AS work_schedule_name,
 			p.name AS position_name,
 			g.name AS grade_name,
@@ -443,7 +478,8 @@ func (e *employeeRepositoryImpl) GetByIDWithDetails(ctx context.Context, id stri
 		&emp.AvatarURL, &emp.Education, &emp.HireDate, &emp.ResignationDate,
 		&emp.EmploymentType, &emp.EmploymentStatus, &emp.WarningLetter,
 		&emp.BankName, &emp.BankAccountHolderName, &emp.BankAccountNumber,
-		&emp.BaseSalary, &emp.CreatedAt, &emp.UpdatedAt, &emp.DeletedAt,
+		&emp.BaseSalary,
+		&emp.EmergencyContactName, &emp.EmergencyContactRelationship, &emp.EmergencyContactPhone, &emp.CreatedAt, &emp.UpdatedAt, &emp.DeletedAt,
 		&emp.WorkScheduleName, &emp.PositionName, &emp.GradeName, &emp.BranchName,
 		&emp.Email,
 	)
@@ -457,6 +493,74 @@ func (e *employeeRepositoryImpl) GetByIDWithDetails(ctx context.Context, id stri
 	return emp, nil
 }
 
+// GetProfile implements employee.EmployeeRepository.
+func (e *employeeRepositoryImpl) GetProfile(ctx context.Context, id string, companyID string) (employee.EmployeeProfile, error) {
+	q := GetQuerier(ctx, e.db)
+
+	query := `
+		SELECT
+			e.id, e.user_id, e.company_id, e.work_schedule_id, e.position_id, e.grade_id, e.branch_id,
+			e.employee_code, e.full_name, COALESCE(e.nik, '') AS nik, e.gender, COALESCE(e.phone_number, '') AS phone_number, e.address, e.place_of_birth,
+			e.dob, e.avatar_url, e.education, e.hire_date, e.resignation_date, e.employment_type,
+			e.employment_status, e.warning_letter, COALESCE(e.bank_name, '') AS bank_name, e.bank_account_holder_name,
+			COALESCE(e.bank_account_number, '') AS bank_account_number, e.base_salary,
+			e.emergency_contact_name, e.emergency_contact_relationship, e.emergency_contact_phone, e.created_at, e.updated_at, e.deleted_at,
+			ws.name AS work_schedule_name,
+			p.name AS position_name,
+			g.name AS grade_name,
+			b.name AS branch_name,
+			u.email,
+			mgr.id AS manager_id,
+			mgr.full_name AS manager_name,
+			GREATEST(0, (
+				EXTRACT(YEAR FROM age(COALESCE(e.resignation_date, CURRENT_DATE), e.hire_date)) * 12 +
+				EXTRACT(MONTH FROM age(COALESCE(e.resignation_date, CURRENT_DATE), e.hire_date))
+			))::int AS tenure_months
+		FROM employees e
+		LEFT JOIN work_schedules ws ON e.work_schedule_id = ws.id
+		LEFT JOIN positions p ON e.position_id = p.id
+		LEFT JOIN grades g ON e.grade_id = g.id
+		LEFT JOIN branches b ON e.branch_id = b.id
+		LEFT JOIN users u ON e.user_id = u.id
+		LEFT JOIN LATERAL (
+			SELECT m.id, m.full_name
+			FROM employees m
+			JOIN users mu ON m.user_id = mu.id
+			WHERE m.company_id = e.company_id
+			  AND m.id <> e.id
+			  AND m.deleted_at IS NULL
+			  AND mu.role = 'manager'
+			  AND mu.managed_branch_id = e.branch_id
+			ORDER BY m.full_name
+			LIMIT 1
+		) mgr ON true
+		WHERE e.id = $1 AND e.company_id = $2 AND e.deleted_at IS NULL
+	`
+
+	var emp employee.EmployeeProfile
+	err := q.QueryRow(ctx, query, id, companyID).Scan(
+		&emp.ID, &emp.UserID, &emp.CompanyID, &emp.WorkScheduleID, &emp.PositionID,
+		&emp.GradeID, &emp.BranchID, &emp.EmployeeCode, &emp.FullName, &emp.NIK,
+		&emp.Gender, &emp.PhoneNumber, &emp.Address, &emp.PlaceOfBirth, &emp.DOB,
+		&emp.AvatarURL, &emp.Education, &emp.HireDate, &emp.ResignationDate,
+		&emp.EmploymentType, &emp.EmploymentStatus, &emp.WarningLetter,
+		&emp.BankName, &emp.BankAccountHolderName, &emp.BankAccountNumber,
+		&emp.BaseSalary,
+		&emp.EmergencyContactName, &emp.EmergencyContactRelationship, &emp.EmergencyContactPhone, &emp.CreatedAt, &emp.UpdatedAt, &emp.DeletedAt,
+		&emp.WorkScheduleName, &emp.PositionName, &emp.GradeName, &emp.BranchName,
+		&emp.Email,
+		&emp.ManagerID, &emp.ManagerName, &emp.TenureMonths,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return employee.EmployeeProfile{}, employee.ErrEmployeeNotFound
+		}
+		return employee.EmployeeProfile{}, fmt.Errorf("failed to get employee profile: %w", err)
+	}
+
+	return emp, nil
+}
+
 // Search implements employee.EmployeeRepository.
 func (e *employeeRepositoryImpl) Search(ctx context.Context, queryStr string, companyID string, limit int) ([]employee.EmployeeWithDetails, error) {
 	q := GetQuerier(ctx, e.db)
@@ -464,10 +568,11 @@ func (e *employeeRepositoryImpl) Search(ctx context.Context, queryStr string, co
 	query := `
 		SELECT 
 			e.id, e.user_id, e.company_id, e.work_schedule_id, e.position_id, e.grade_id, e.branch_id, 
-			e.employee_code, e.full_name, e.nik, e.gender, e.phone_number, e.address, e.place_of_birth, 
+			e.employee_code, e.full_name, COALESCE(e.nik, '') AS nik, e.gender, COALESCE(e.phone_number, '') AS phone_number, e.address, e.place_of_birth, 
 			e.dob, e.avatar_url, e.education, e.hire_date, e.resignation_date, e.employment_type, 
-			e.employment_status, e.warning_letter, e.bank_name, e.bank_account_holder_name, 
-			e.bank_account_number, e.base_salary, e.created_at, e.updated_at, e.deleted_at,
+			e.employment_status, e.warning_letter, COALESCE(e.bank_name, '') AS bank_name, e.bank_account_holder_name, 
+			COALESCE(e.bank_account_number, '') AS bank_account_number, e.base_salary,
+			e.emergency_contact_name, e.emergency_contact_relationship, e.emergency_contact_phone, e.created_at, e.updated_at, e.deleted_at,
 			ws.name AS work_schedule_name,
 			p.name AS position_name,
 			g.name AS grade_name,
@@ -504,7 +609,8 @@ func (e *employeeRepositoryImpl) Search(ctx context.Context, queryStr string, co
 			&emp.AvatarURL, &emp.Education, &emp.HireDate, &emp.ResignationDate,
 			&emp.EmploymentType, &emp.EmploymentStatus, &emp.WarningLetter,
 			&emp.BankName, &emp.BankAccountHolderName, &emp.BankAccountNumber,
-			&emp.BaseSalary, &emp.CreatedAt, &emp.UpdatedAt, &emp.DeletedAt,
+			&emp.BaseSalary,
+			&emp.EmergencyContactName, &emp.EmergencyContactRelationship, &emp.EmergencyContactPhone, &emp.CreatedAt, &emp.UpdatedAt, &emp.DeletedAt,
 			&emp.WorkScheduleName, &emp.PositionName, &emp.GradeName, &emp.BranchName,
 		)
 		if err != nil {
@@ -530,10 +636,20 @@ func (e *employeeRepositoryImpl) List(ctx context.Context, filter employee.Emplo
 	argIdx := 2
 
 	if filter.Search != nil && *filter.Search != "" {
-		conditions = append(conditions, fmt.Sprintf("(e.full_name ILIKE $%d OR e.employee_code ILIKE $%d OR e.nik ILIKE $%d)", argIdx, argIdx, argIdx))
+		conditions = append(conditions, fmt.Sprintf("(e.full_name ILIKE $%d OR e.employee_code ILIKE $%d OR e.nik ILIKE $%d OR e.phone_number ILIKE $%d)", argIdx, argIdx, argIdx, argIdx))
 		args = append(args, "%"+*filter.Search+"%")
 		argIdx++
 	}
+	if filter.NIK != nil && *filter.NIK != "" {
+		conditions = append(conditions, fmt.Sprintf("e.nik ILIKE $%d", argIdx))
+		args = append(args, "%"+strings.TrimSpace(*filter.NIK)+"%")
+		argIdx++
+	}
+	if filter.PhoneNumber != nil && *filter.PhoneNumber != "" {
+		conditions = append(conditions, fmt.Sprintf("e.phone_number ILIKE $%d", argIdx))
+		args = append(args, "%"+strings.TrimSpace(*filter.PhoneNumber)+"%")
+		argIdx++
+	}
 	if filter.WorkScheduleID != nil && *filter.WorkScheduleID != "" {
 		conditions = append(conditions, fmt.Sprintf("e.work_schedule_id = $%d", argIdx))
 		args = append(args, *filter.WorkScheduleID)
@@ -633,10 +749,11 @@ func (e *employeeRepositoryImpl) List(ctx context.Context, filter employee.Emplo
 	query := fmt.Sprintf(`
 		SELECT 
 			e.id, e.user_id, e.company_id, e.work_schedule_id, e.position_id, e.grade_id, e.branch_id, 
-			e.employee_code, e.full_name, e.nik, e.gender, e.phone_number, e.address, e.place_of_birth, 
+			e.employee_code, e.full_name, COALESCE(e.nik, '') AS nik, e.gender, COALESCE(e.phone_number, '') AS phone_number, e.address, e.place_of_birth, 
 			e.dob, e.avatar_url, e.education, e.hire_date, e.resignation_date, e.employment_type, 
-			e.employment_status, e.warning_letter, e.bank_name, e.bank_account_holder_name, 
-			e.bank_account_number, e.base_salary, e.created_at, e.updated_at, e.deleted_at,
+			e.employment_status, e.warning_letter, COALESCE(e.bank_name, '') AS bank_name, e.bank_account_holder_name, 
+			COALESCE(e.bank_account_number, '') AS bank_account_number, e.base_salary,
+			e.emergency_contact_name, e.emergency_contact_relationship, e.emergency_contact_phone, e.created_at, e.updated_at, e.deleted_at,
 			ws.name AS work_schedule_name,
 			p.name AS position_name,
 			g.name AS grade_name,
@@ -671,7 +788,8 @@ func (e *employeeRepositoryImpl) List(ctx context.Context, filter employee.Emplo
 			&emp.AvatarURL, &emp.Education, &emp.HireDate, &emp.ResignationDate,
 			&emp.EmploymentType, &emp.EmploymentStatus, &emp.WarningLetter,
 			&emp.BankName, &emp.BankAccountHolderName, &emp.BankAccountNumber,
-			&emp.BaseSalary, &emp.CreatedAt, &emp.UpdatedAt, &emp.DeletedAt,
+			&emp.BaseSalary,
+			&emp.EmergencyContactName, &emp.EmergencyContactRelationship, &emp.EmergencyContactPhone, &emp.CreatedAt, &emp.UpdatedAt, &emp.DeletedAt,
 			&emp.WorkScheduleName, &emp.PositionName, &emp.GradeName, &emp.BranchName,
 			&emp.Email,
 		)
@@ -734,6 +852,49 @@ func (e *employeeRepositoryImpl) UpdateAvatar(ctx context.Context, id string, co
 	return nil
 }
 
+// GetCalendarFeedNonce implements employee.EmployeeRepository.
+func (e *employeeRepositoryImpl) GetCalendarFeedNonce(ctx context.Context, id string) (*string, error) {
+	q := GetQuerier(ctx, e.db)
+
+	query := `
+		SELECT calendar_feed_nonce
+		FROM employees
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	var nonce *string
+	err := q.QueryRow(ctx, query, id).Scan(&nonce)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, employee.ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("failed to get calendar feed nonce: %w", err)
+	}
+
+	return nonce, nil
+}
+
+// SetCalendarFeedNonce implements employee.EmployeeRepository.
+func (e *employeeRepositoryImpl) SetCalendarFeedNonce(ctx context.Context, id string, nonce string) error {
+	q := GetQuerier(ctx, e.db)
+
+	query := `
+		UPDATE employees
+		SET calendar_feed_nonce = $1, updated_at = NOW()
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+
+	tag, err := q.Exec(ctx, query, nonce, id)
+	if err != nil {
+		return fmt.Errorf("failed to set calendar feed nonce: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return employee.ErrEmployeeNotFound
+	}
+
+	return nil
+}
+
 // Inactivate implements employee.EmployeeRepository.
 func (e *employeeRepositoryImpl) Inactivate(ctx context.Context, id string, companyID string, resignationDate string) error {
 	q := GetQuerier(ctx, e.db)
@@ -759,6 +920,35 @@ func (e *employeeRepositoryImpl) Inactivate(ctx context.Context, id string, comp
 	return nil
 }
 
+// Anonymize implements employee.EmployeeRepository.
+// Employment, position and salary data are kept so payroll and attendance history stay meaningful.
+func (e *employeeRepositoryImpl) Anonymize(ctx context.Context, id string, companyID string, anonymizedAt time.Time) error {
+	q := GetQuerier(ctx, e.db)
+
+	query := `
+		UPDATE employees
+		SET full_name = $1, nik = NULL, phone_number = NULL, address = NULL, place_of_birth = NULL, dob = NULL,
+			avatar_url = NULL, education = NULL,
+			bank_name = NULL, bank_account_holder_name = NULL, bank_account_number = NULL,
+			emergency_contact_name = NULL, emergency_contact_relationship = NULL, emergency_contact_phone = NULL,
+			user_id = NULL, calendar_feed_nonce = NULL,
+			anonymized_at = $2, deleted_at = COALESCE(deleted_at, $2), updated_at = $2
+		WHERE id = $3 AND company_id = $4 AND anonymized_at IS NULL
+		RETURNING id
+	`
+
+	var anonymizedID string
+	err := q.QueryRow(ctx, query, employee.AnonymizedName, anonymizedAt, id, companyID).Scan(&anonymizedID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return employee.ErrEmployeeAlreadyAnonymized
+		}
+		return fmt.Errorf("failed to anonymize employee: %w", err)
+	}
+
+	return nil
+}
+
 // LinkUser implements employee.EmployeeRepository.
 func (e *employeeRepositoryImpl) LinkUser(ctx context.Context, employeeID, userID, companyID string) error {
 	q := GetQuerier(ctx, e.db)
@@ -784,15 +974,7 @@ func (e *employeeRepositoryImpl) LinkUser(ctx context.Context, employeeID, userI
 
 // GetManagersByCompanyID retrieves all managers and owners for a company (for notifications)
 func (e *employeeRepositoryImpl) GetManagersByCompanyID(ctx context.Context, companyID string) ([]employee.Employee, error) {
-	q := GetQuerier(ctx, e.db)
-
-	query := `
-		SELECT e.id, e.user_id, e.company_id, e.work_schedule_id, e.position_id, e.grade_id, e.branch_id, e.employee_code,
-			e.full_name, e.nik, e.gender, e.phone_number, e.address, e.place_of_birth, e.dob, e.avatar_url, e.education,
-			e.hire_date, e.resignation_date, e.employment_type, e.employment_status, e.warning_letter,
-			e.bank_name, e.bank_account_holder_name, e.bank_account_number, e.base_salary, e.created_at, e.updated_at, e.deleted_at
-		FROM employees e
-		INNER JOIN users u ON e.user_id = u.id
+	query := managersBaseQuery + `
 		WHERE e.company_id = $1 
 			AND e.employment_status = $2 
 			AND e.deleted_at IS NULL
@@ -800,7 +982,50 @@ func (e *employeeRepositoryImpl) GetManagersByCompanyID(ctx context.Context, com
 			AND u.role IN ('owner', 'manager')
 	`
 
-	rows, err := q.Query(ctx, query, companyID, employee.EmploymentStatusActive)
+	return e.queryManagers(ctx, query, companyID, employee.EmploymentStatusActive)
+}
+
+// GetManagersByBranchID returns the managers restricted to a branch, i.e. the employee's team managers
+func (e *employeeRepositoryImpl) GetManagersByBranchID(ctx context.Context, companyID string, branchID string) ([]employee.Employee, error) {
+	query := managersBaseQuery + `
+		WHERE e.company_id = $1
+			AND e.employment_status = $2
+			AND e.deleted_at IS NULL
+			AND e.user_id IS NOT NULL
+			AND u.role = 'manager'
+			AND u.managed_branch_id = $3
+	`
+
+	return e.queryManagers(ctx, query, companyID, employee.EmploymentStatusActive, branchID)
+}
+
+// GetOwnersByCompanyID returns the company's owners, the approvers above every branch manager
+func (e *employeeRepositoryImpl) GetOwnersByCompanyID(ctx context.Context, companyID string) ([]employee.Employee, error) {
+	query := managersBaseQuery + `
+		WHERE e.company_id = $1
+			AND e.employment_status = $2
+			AND e.deleted_at IS NULL
+			AND e.user_id IS NOT NULL
+			AND u.role = 'owner'
+		ORDER BY e.created_at ASC
+	`
+
+	return e.queryManagers(ctx, query, companyID, employee.EmploymentStatusActive)
+}
+
+const managersBaseQuery = `
+		SELECT e.id, e.user_id, e.company_id, e.work_schedule_id, e.position_id, e.grade_id, e.branch_id, e.employee_code,
+			e.full_name, COALESCE(e.nik, '') AS nik, e.gender, COALESCE(e.phone_number, '') AS phone_number, e.address, e.place_of_birth, e.dob, e.avatar_url, e.education,
+			e.hire_date, e.resignation_date, e.employment_type, e.employment_status, e.warning_letter,
+			COALESCE(e.bank_name, '') AS bank_name, e.bank_account_holder_name, COALESCE(e.bank_account_number, '') AS bank_account_number, e.base_salary,
+			e.emergency_contact_name, e.emergency_contact_relationship, e.emergency_contact_phone, e.created_at, e.updated_at, e.deleted_at
+		FROM employees e
+		INNER JOIN users u ON e.user_id = u.id`
+
+func (e *employeeRepositoryImpl) queryManagers(ctx context.Context, query string, args ...interface{}) ([]employee.Employee, error) {
+	q := GetQuerier(ctx, e.db)
+
+	rows, err := q.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get managers: %w", err)
 	}
@@ -816,7 +1041,8 @@ func (e *employeeRepositoryImpl) GetManagersByCompanyID(ctx context.Context, com
 			&emp.AvatarURL, &emp.Education, &emp.HireDate, &emp.ResignationDate,
 			&emp.EmploymentType, &emp.EmploymentStatus, &emp.WarningLetter,
 			&emp.BankName, &emp.BankAccountHolderName, &emp.BankAccountNumber,
-			&emp.BaseSalary, &emp.CreatedAt, &emp.UpdatedAt, &emp.DeletedAt,
+			&emp.BaseSalary,
+			&emp.EmergencyContactName, &emp.EmergencyContactRelationship, &emp.EmergencyContactPhone, &emp.CreatedAt, &emp.UpdatedAt, &emp.DeletedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan manager: %w", err)
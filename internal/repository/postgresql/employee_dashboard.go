@@ -106,6 +106,55 @@ func (r *employeeDashboardRepositoryImpl) GetAttendanceSummary(ctx context.Conte
 	return &data, nil
 }
 
+// GetAttendanceRecap returns an employee's attendance records for a month (single query)
+func (r *employeeDashboardRepositoryImpl) GetAttendanceRecap(ctx context.Context, employeeID string, year, month int) ([]empDashboard.AttendanceRecapData, error) {
+	q := GetQuerier(ctx, r.db)
+
+	startOfMonth := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	endOfMonth := startOfMonth.AddDate(0, 1, 0)
+
+	query := `
+		SELECT 
+			a.date,
+			a.status,
+			lt.name as leave_type_name,
+			a.clock_in,
+			a.clock_out,
+			COALESCE(a.work_hours_in_minutes, 0) as work_minutes,
+			COALESCE(a.late_minutes, 0) as late_minutes,
+			COALESCE(a.overtime_minutes, 0) as overtime_minutes
+		FROM attendances a
+		LEFT JOIN leave_types lt ON a.leave_type_id = lt.id
+		WHERE a.employee_id = $1
+		AND a.date >= $2 AND a.date < $3
+		ORDER BY a.date ASC, a.clock_in ASC NULLS LAST
+	`
+
+	rows, err := q.Query(ctx, query, employeeID, startOfMonth, endOfMonth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance recap: %w", err)
+	}
+	defer rows.Close()
+
+	var result []empDashboard.AttendanceRecapData
+	for rows.Next() {
+		var item empDashboard.AttendanceRecapData
+		if err := rows.Scan(
+			&item.Date, &item.Status, &item.LeaveTypeName, &item.ClockIn, &item.ClockOut,
+			&item.WorkMinutes, &item.LateMinutes, &item.OvertimeMinutes,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan attendance recap: %w", err)
+		}
+		result = append(result, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // GetLeaveSummary returns leave quota summary for a year (single query)
 func (r *employeeDashboardRepositoryImpl) GetLeaveSummary(ctx context.Context, employeeID string, year int) ([]empDashboard.LeaveQuotaData, error) {
 	q := GetQuerier(ctx, r.db)
@@ -103,7 +103,7 @@ func (e *employeeScheduleAssignmentRepository) GetActiveSchedule(ctx context.Con
 		JOIN work_schedules ws ON esa.work_schedule_id = ws.id
 		WHERE esa.employee_id = $1
 		  AND $2 BETWEEN esa.start_date AND COALESCE(esa.end_date, '9999-12-31'::date)
-		ORDER BY esa.start_date DESC
+		ORDER BY esa.start_date DESC, esa.id DESC
 		LIMIT 1
 	`
 
@@ -146,7 +146,7 @@ func (e *employeeScheduleAssignmentRepository) GetByEmployeeID(ctx context.Conte
 		SELECT id, employee_id, work_schedule_id, start_date, end_date, created_at, updated_at
 		FROM employee_schedule_assignments
 		WHERE employee_id = $1
-		ORDER BY start_date DESC
+		ORDER BY start_date DESC, id DESC
 	`
 
 	rows, err := q.Query(ctx, query, employeeID)
@@ -210,7 +210,7 @@ func (e *employeeScheduleAssignmentRepository) GetScheduleAssignments(ctx contex
 			  (end_date BETWEEN $2 AND $3) OR
 			  (start_date <= $2 AND COALESCE(end_date, '9999-12-31'::date) >= $3)
 		  )
-		ORDER BY start_date
+		ORDER BY start_date, id
 	`
 
 	rows, err := q.Query(ctx, query, employeeID, startDate, endDate)
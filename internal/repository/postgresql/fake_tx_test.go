@@ -0,0 +1,225 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakeResult is what fakeTx answers to one query: the rows it returns or the error it fails with
+type fakeResult struct {
+	rows [][]any
+	tag  string
+	err  error
+}
+
+// fakeCall records one statement sent to fakeTx
+type fakeCall struct {
+	sql  string
+	args []any
+}
+
+// fakeTx is a pgx.Tx that answers queries with queued results, in call order.
+// Scans fail like pgx does when the number of targets differs from the SELECT list,
+// so repository tests catch column/target mismatches without a database.
+// Methods other than Exec, Query and QueryRow are not implemented and panic.
+type fakeTx struct {
+	pgx.Tx
+
+	results []fakeResult
+	calls   []fakeCall
+}
+
+func newFakeTx(results ...fakeResult) *fakeTx {
+	return &fakeTx{results: results}
+}
+
+// ctx returns a context that makes repositories run their queries on tx
+func (tx *fakeTx) ctx() context.Context {
+	return ContextWithTx(context.Background(), tx)
+}
+
+func (tx *fakeTx) next(sql string, args []any) fakeResult {
+	tx.calls = append(tx.calls, fakeCall{sql: sql, args: args})
+	if len(tx.results) == 0 {
+		return fakeResult{err: fmt.Errorf("fakeTx: unexpected query %d: %s", len(tx.calls), compactSQL(sql))}
+	}
+	res := tx.results[0]
+	tx.results = tx.results[1:]
+	return res
+}
+
+func (tx *fakeTx) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	res := tx.next(sql, args)
+	return pgconn.NewCommandTag(res.tag), res.err
+}
+
+func (tx *fakeTx) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	res := tx.next(sql, args)
+	if res.err != nil {
+		return nil, res.err
+	}
+	return &fakeRows{sql: sql, rows: res.rows, index: -1}, nil
+}
+
+func (tx *fakeTx) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	res := tx.next(sql, args)
+	return &fakeRow{rows: &fakeRows{sql: sql, rows: res.rows, index: -1}, err: res.err}
+}
+
+type fakeRows struct {
+	pgx.Rows
+
+	sql   string
+	rows  [][]any
+	index int
+	err   error
+}
+
+func (r *fakeRows) Next() bool {
+	if r.err != nil || r.index+1 >= len(r.rows) {
+		return false
+	}
+	r.index++
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	if err := scanValues(r.sql, r.rows[r.index], dest); err != nil {
+		r.err = err
+		return err
+	}
+	return nil
+}
+
+func (r *fakeRows) Err() error { return r.err }
+
+func (r *fakeRows) Close() {}
+
+func (r *fakeRows) CommandTag() pgconn.CommandTag {
+	return pgconn.NewCommandTag(fmt.Sprintf("SELECT %d", len(r.rows)))
+}
+
+type fakeRow struct {
+	rows *fakeRows
+	err  error
+}
+
+func (r *fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	if !r.rows.Next() {
+		return pgx.ErrNoRows
+	}
+	return r.rows.Scan(dest...)
+}
+
+// scanValues copies values into dest after checking both match the statement's SELECT list
+func scanValues(sql string, values []any, dest []any) error {
+	if columns := selectColumnCount(sql); columns > 0 && columns != len(dest) {
+		// Same wording as pgx, which fails the real query the same way
+		return fmt.Errorf("number of field descriptions must equal number of destinations, got %d and %d", columns, len(dest))
+	}
+	if len(values) != len(dest) {
+		return fmt.Errorf("fakeTx: row has %d values for %d destinations", len(values), len(dest))
+	}
+	for i, d := range dest {
+		if err := assign(d, values[i]); err != nil {
+			return fmt.Errorf("fakeTx: column %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// assign stores value in the pointer dest, converting between named types and allocating
+// pointer targets the way pgx does for nullable columns
+func assign(dest, value any) error {
+	target := reflect.ValueOf(dest)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("destination %T is not a non-nil pointer", dest)
+	}
+	target = target.Elem()
+
+	if value == nil {
+		target.SetZero()
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	if target.Kind() == reflect.Pointer && !v.Type().AssignableTo(target.Type()) {
+		elem := reflect.New(target.Type().Elem())
+		if err := assign(elem.Interface(), value); err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+	}
+
+	switch {
+	case v.Type().AssignableTo(target.Type()):
+		target.Set(v)
+	case v.Type().ConvertibleTo(target.Type()) && v.Kind() == target.Kind():
+		target.Set(v.Convert(target.Type()))
+	default:
+		return fmt.Errorf("cannot scan %T into %s", value, target.Type())
+	}
+	return nil
+}
+
+// selectColumnCount returns how many expressions the outermost SELECT list of sql has,
+// or 0 when sql is not a query that has one
+func selectColumnCount(sql string) int {
+	upper := strings.ToUpper(sql)
+
+	depth := 0
+	start := -1
+	columns := 0
+	for i := 0; i < len(upper); i++ {
+		switch c := upper[i]; {
+		case c == '\'':
+			// Skip string literals, which may contain commas or keywords
+			for i++; i < len(upper) && upper[i] != '\''; i++ {
+			}
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth != 0:
+		case start < 0 && isKeywordAt(upper, i, "SELECT"):
+			start = i + len("SELECT")
+			columns = 1
+			i = start - 1
+		case start >= 0 && c == ',':
+			columns++
+		case start >= 0 && isKeywordAt(upper, i, "FROM"):
+			return columns
+		}
+	}
+	return columns
+}
+
+// isKeywordAt reports whether keyword appears at position i of s as a whole word
+func isKeywordAt(s string, i int, keyword string) bool {
+	if !strings.HasPrefix(s[i:], keyword) {
+		return false
+	}
+	if i > 0 && isIdentRune(rune(s[i-1])) {
+		return false
+	}
+	end := i + len(keyword)
+	return end >= len(s) || !isIdentRune(rune(s[end]))
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func compactSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
@@ -22,16 +22,18 @@ func (r *gradeRepositoryImpl) Create(ctx context.Context, g grade.Grade) (grade.
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		INSERT INTO grades (id, company_id, name)
-		VALUES (uuidv7(), $1, $2)
-		RETURNING id, company_id, name
+		INSERT INTO grades (id, company_id, name, min_salary, max_salary)
+		VALUES (uuidv7(), $1, $2, $3, $4)
+		RETURNING id, company_id, name, min_salary, max_salary
 	`
 
 	var result grade.Grade
-	err := q.QueryRow(ctx, query, g.CompanyID, g.Name).Scan(
+	err := q.QueryRow(ctx, query, g.CompanyID, g.Name, g.MinSalary, g.MaxSalary).Scan(
 		&result.ID,
 		&result.CompanyID,
 		&result.Name,
+		&result.MinSalary,
+		&result.MaxSalary,
 	)
 
 	if err != nil {
@@ -46,7 +48,7 @@ func (r *gradeRepositoryImpl) GetByID(ctx context.Context, id string, companyID
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		SELECT id, company_id, name
+		SELECT id, company_id, name, min_salary, max_salary
 		FROM grades
 		WHERE id = $1 AND company_id = $2
 	`
@@ -56,6 +58,8 @@ func (r *gradeRepositoryImpl) GetByID(ctx context.Context, id string, companyID
 		&result.ID,
 		&result.CompanyID,
 		&result.Name,
+		&result.MinSalary,
+		&result.MaxSalary,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -74,7 +78,7 @@ func (r *gradeRepositoryImpl) GetByCompanyID(ctx context.Context, companyID stri
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		SELECT id, company_id, name
+		SELECT id, company_id, name, min_salary, max_salary
 		FROM grades
 		WHERE company_id = $1
 		ORDER BY name ASC
@@ -93,6 +97,8 @@ func (r *gradeRepositoryImpl) GetByCompanyID(ctx context.Context, companyID stri
 			&g.ID,
 			&g.CompanyID,
 			&g.Name,
+			&g.MinSalary,
+			&g.MaxSalary,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan grade: %w", err)
@@ -113,11 +119,11 @@ func (r *gradeRepositoryImpl) Update(ctx context.Context, req grade.UpdateGradeR
 
 	query := `
 		UPDATE grades 
-		SET name = $1
-		WHERE id = $2 AND company_id = $3
+		SET name = $1, min_salary = $2, max_salary = $3
+		WHERE id = $4 AND company_id = $5
 	`
 
-	commandTag, err := q.Exec(ctx, query, req.Name, req.ID, req.CompanyID)
+	commandTag, err := q.Exec(ctx, query, req.Name, req.MinSalary, req.MaxSalary, req.ID, req.CompanyID)
 	if err != nil {
 		return fmt.Errorf("failed to update grade: %w", err)
 	}
@@ -146,3 +152,44 @@ func (r *gradeRepositoryImpl) Delete(ctx context.Context, id string, companyID s
 
 	return nil
 }
+
+// CountReferences implements grade.GradeRepository.
+// Counts employees and job history rows that still point to the grade.
+func (r *gradeRepositoryImpl) CountReferences(ctx context.Context, id string, companyID string) (int, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM employees WHERE grade_id = $1 AND company_id = $2)
+			+
+			(SELECT COUNT(*)
+			 FROM employee_job_history h
+			 JOIN employees e ON e.id = h.employee_id
+			 WHERE h.grade_id = $1 AND e.company_id = $2)
+	`
+
+	var count int
+	if err := q.QueryRow(ctx, query, id, companyID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count grade references: %w", err)
+	}
+
+	return count, nil
+}
+
+// ReassignReferences implements grade.GradeRepository.
+// Moves employees from one grade to another; no manager setting refers to a grade. Job history
+// keeps recording the grade employees actually held, so a grade that appears in it stays in use.
+func (r *gradeRepositoryImpl) ReassignReferences(ctx context.Context, fromID, toID string, companyID string) error {
+	q := GetQuerier(ctx, r.db)
+
+	employeesQuery := `
+		UPDATE employees
+		SET grade_id = $2, updated_at = NOW()
+		WHERE grade_id = $1 AND company_id = $3
+	`
+	if _, err := q.Exec(ctx, employeesQuery, fromID, toID, companyID); err != nil {
+		return fmt.Errorf("failed to reassign employees: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type holidayRepositoryImpl struct {
+	db *database.DB
+}
+
+func NewHolidayRepository(db *database.DB) company.HolidayRepository {
+	return &holidayRepositoryImpl{db: db}
+}
+
+// GetByYear implements company.HolidayRepository.
+func (r *holidayRepositoryImpl) GetByYear(ctx context.Context, companyID string, year int) ([]company.Holiday, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT id, company_id, date, name, COALESCE(is_recurring, false), created_at
+		FROM public_holidays
+		WHERE company_id = $1 AND date < $3 AND (date >= $2 OR is_recurring)
+	`
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	rows, err := q.Query(ctx, query, companyID, start, start.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holidays: %w", err)
+	}
+	defer rows.Close()
+
+	var holidays []company.Holiday
+	for rows.Next() {
+		var h company.Holiday
+		if err := rows.Scan(&h.ID, &h.CompanyID, &h.Date, &h.Name, &h.IsRecurring, &h.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan holiday: %w", err)
+		}
+		if h.Date.Year() != year {
+			// A recurring holiday from an earlier year; 29 February only recurs in leap years
+			date := time.Date(year, h.Date.Month(), h.Date.Day(), 0, 0, 0, 0, time.UTC)
+			if date.Month() != h.Date.Month() {
+				continue
+			}
+			h.Date = date
+		}
+		holidays = append(holidays, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(holidays, func(a, b company.Holiday) int { return a.Date.Compare(b.Date) })
+	return holidays, nil
+}
+
+// Create implements company.HolidayRepository.
+func (r *holidayRepositoryImpl) Create(ctx context.Context, holiday company.Holiday) (company.Holiday, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		INSERT INTO public_holidays (company_id, date, name, is_recurring)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := q.QueryRow(ctx, query, holiday.CompanyID, holiday.Date, holiday.Name, holiday.IsRecurring).Scan(&holiday.ID, &holiday.CreatedAt)
+	if err != nil {
+		// UNIQUE(company_id, date) from the initial schema
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "public_holidays_company_id_date_key" {
+			return company.Holiday{}, company.ErrHolidayExists
+		}
+		return company.Holiday{}, fmt.Errorf("failed to create holiday: %w", err)
+	}
+
+	return holiday, nil
+}
+
+// Delete implements company.HolidayRepository.
+func (r *holidayRepositoryImpl) Delete(ctx context.Context, companyID string, id string) (company.Holiday, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		DELETE FROM public_holidays
+		WHERE id = $1 AND company_id = $2
+		RETURNING id, company_id, date, name, COALESCE(is_recurring, false), created_at
+	`
+
+	var h company.Holiday
+	err := q.QueryRow(ctx, query, id, companyID).Scan(&h.ID, &h.CompanyID, &h.Date, &h.Name, &h.IsRecurring, &h.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return company.Holiday{}, company.ErrHolidayNotFound
+		}
+		return company.Holiday{}, fmt.Errorf("failed to delete holiday: %w", err)
+	}
+
+	return h, nil
+}
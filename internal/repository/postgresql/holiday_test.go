@@ -0,0 +1,67 @@
+package postgresql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestHolidayRepositoryGetByYear(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	created := date(2024, 1, 1)
+	tx := newFakeTx(fakeResult{rows: [][]any{
+		{"holiday-1", "company-1", date(2026, 8, 18), "Company Anniversary", false, created},
+		{"holiday-2", "company-1", date(2024, 8, 17), "Independence Day", true, created},
+		{"holiday-3", "company-1", date(2024, 2, 29), "Leap Day", true, created},
+		{"holiday-4", "company-1", date(2024, 1, 1), "New Year", true, created},
+	}})
+	repo := &holidayRepositoryImpl{}
+
+	holidays, err := repo.GetByYear(tx.ctx(), "company-1", 2026)
+	if err != nil {
+		t.Fatalf("GetByYear() error = %v", err)
+	}
+
+	// Recurring holidays move into 2026 and sort among the others; 29 February is skipped
+	want := []struct {
+		id   string
+		date time.Time
+	}{
+		{"holiday-4", date(2026, 1, 1)},
+		{"holiday-2", date(2026, 8, 17)},
+		{"holiday-1", date(2026, 8, 18)},
+	}
+	if len(holidays) != len(want) {
+		t.Fatalf("GetByYear() = %+v, want %d holidays", holidays, len(want))
+	}
+	for i, w := range want {
+		if holidays[i].ID != w.id || !holidays[i].Date.Equal(w.date) {
+			t.Errorf("holiday %d = %s on %s, want %s on %s", i, holidays[i].ID, holidays[i].Date.Format("2006-01-02"), w.id, w.date.Format("2006-01-02"))
+		}
+	}
+
+	call := tx.calls[0]
+	if !strings.Contains(call.sql, "FROM public_holidays") {
+		t.Errorf("query reads %q, want public_holidays", compactSQL(call.sql))
+	}
+	from, to := call.args[1].(time.Time), call.args[2].(time.Time)
+	if !from.Equal(date(2026, 1, 1)) || !to.Equal(date(2027, 1, 1)) {
+		t.Errorf("range = %v to %v, want the whole of 2026", from, to)
+	}
+}
+
+func TestHolidayRepositoryCreateDuplicateDate(t *testing.T) {
+	tx := newFakeTx(fakeResult{err: &pgconn.PgError{Code: "23505", ConstraintName: "public_holidays_company_id_date_key"}})
+	repo := &holidayRepositoryImpl{}
+
+	_, err := repo.Create(tx.ctx(), company.Holiday{CompanyID: "company-1", Date: time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC), Name: "Independence Day"})
+	if !errors.Is(err, company.ErrHolidayExists) {
+		t.Errorf("Create() error = %v, want %v", err, company.ErrHolidayExists)
+	}
+}
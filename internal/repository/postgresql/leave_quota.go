@@ -30,7 +30,7 @@ func (r *leaveQuotaRepositoryImpl) GetByCompanyID(ctx context.Context, companyID
 		JOIN employees e ON lq.employee_id = e.id
 		JOIN leave_types lt ON lq.leave_type_id = lt.id
 		WHERE e.company_id = $1
-		ORDER BY lq.year DESC, lt.name
+		ORDER BY lq.year DESC, lt.name, lq.id
 	`
 
 	rows, err := q.Query(ctx, query, companyID)
@@ -68,7 +68,7 @@ func (r *leaveQuotaRepositoryImpl) GetByCompanyIDAndYear(ctx context.Context, co
 			   created_at, updated_at
 		FROM leave_quotas
 		WHERE company_id = $1 AND year = $2
-		ORDER BY leave_type_id
+		ORDER BY leave_type_id, id
 	`
 
 	rows, err := q.Query(ctx, query, companyID, year)
@@ -105,7 +105,7 @@ func (r *leaveQuotaRepositoryImpl) GetByEmployee(ctx context.Context, employeeID
 			   created_at, updated_at
 		FROM leave_quotas
 		WHERE employee_id = $1
-		ORDER BY year DESC, leave_type_id
+		ORDER BY year DESC, leave_type_id, id
 	`
 
 	rows, err := q.Query(ctx, query, employeeID)
@@ -237,6 +237,34 @@ func (r *leaveQuotaRepositoryImpl) GetByEmployeeTypeYear(ctx context.Context, em
 
 }
 
+func (r *leaveQuotaRepositoryImpl) GetByEmployeeTypeYearForUpdate(ctx context.Context, employeeID, leaveTypeID string, year int) (leave.LeaveQuota, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+        SELECT id, employee_id, leave_type_id, year,
+               opening_balance, earned_quota, rollover_quota, adjustment_quota,
+               used_quota, pending_quota, available_quota, rollover_expiry_date,
+               created_at, updated_at
+        FROM leave_quotas
+        WHERE employee_id = $1 AND leave_type_id = $2 AND year = $3
+        FOR UPDATE
+    `
+
+	var quota leave.LeaveQuota
+
+	err := q.QueryRow(ctx, query, employeeID, leaveTypeID, year).Scan(
+		&quota.ID, &quota.EmployeeID, &quota.LeaveTypeID, &quota.Year,
+		&quota.OpeningBalance, &quota.EarnedQuota, &quota.RolloverQuota, &quota.AdjustmentQuota,
+		&quota.UsedQuota, &quota.PendingQuota, &quota.AvailableQuota, &quota.RolloverExpiryDate,
+		&quota.CreatedAt, &quota.UpdatedAt)
+
+	if err != nil {
+		return leave.LeaveQuota{}, err
+	}
+
+	return quota, nil
+}
+
 func (r *leaveQuotaRepositoryImpl) Update(ctx context.Context, req leave.UpdateLeaveQuotaRequest) error {
 	q := GetQuerier(ctx, r.db)
 
@@ -299,7 +327,7 @@ func (r *leaveQuotaRepositoryImpl) Update(ctx context.Context, req leave.UpdateL
 	return nil
 }
 
-func (r *leaveQuotaRepositoryImpl) AddPendingQuota(ctx context.Context, quotaID string, amount float64) error {
+func (r *leaveQuotaRepositoryImpl) AddPendingQuota(ctx context.Context, quotaID string, amount float64, allowNegative bool) error {
 	q := GetQuerier(ctx, r.db)
 
 	query := `
@@ -307,10 +335,10 @@ func (r *leaveQuotaRepositoryImpl) AddPendingQuota(ctx context.Context, quotaID
     SET pending_quota = pending_quota + $1,
         updated_at = NOW()
     WHERE id = $2
-    AND (opening_balance + earned_quota + rollover_quota + adjustment_quota - used_quota - pending_quota - $1) >= 0
+    AND ($3 OR (opening_balance + earned_quota + rollover_quota + adjustment_quota - used_quota - pending_quota - $1) >= 0)
 `
 
-	result, err := q.Exec(ctx, query, amount, quotaID)
+	result, err := q.Exec(ctx, query, amount, quotaID, allowNegative)
 	if err != nil {
 		return err
 	}
@@ -350,7 +378,7 @@ func (r *leaveQuotaRepositoryImpl) GetByEmployeeYear(ctx context.Context, employ
     FROM leave_quotas lq
     JOIN leave_types lt ON lq.leave_type_id = lt.id
     WHERE lq.employee_id = $1 AND lq.year = $2
-    ORDER BY lt.name
+    ORDER BY lt.name, lq.id
 `
 
 	rows, err := q.Query(ctx, query, employeeID, year)
@@ -400,6 +428,21 @@ func (r *leaveQuotaRepositoryImpl) MovePendingToUsed(ctx context.Context, quotaI
 
 }
 
+// AddUsedQuota implements leave.LeaveQuotaRepository.
+func (r *leaveQuotaRepositoryImpl) AddUsedQuota(ctx context.Context, quotaID string, amount float64) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+    UPDATE leave_quotas
+    SET used_quota = used_quota + $1,
+        updated_at = NOW()
+    WHERE id = $2
+`
+
+	_, err := q.Exec(ctx, query, amount, quotaID)
+	return err
+}
+
 // DecrementQuota implements leave.LeaveQuotaRepository.
 func (r *leaveQuotaRepositoryImpl) DecrementQuota(ctx context.Context, quotaID string, days int) error {
 	q := GetQuerier(ctx, r.db)
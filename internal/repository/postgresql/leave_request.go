@@ -2,12 +2,14 @@ package postgresql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/pagination"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -22,11 +24,11 @@ func (r *leaveRequestRepositoryImpl) GetMyRequest(ctx context.Context, userID st
 	query := `
 		SELECT lr.id, lr.employee_id, lr.leave_type_id, lr.start_date, lr.end_date, lr.duration_type, lr.total_days, lr.working_days, 
 			   lr.reason, lr.attachment_url, lr.emergency_leave, lr.is_backdate, lr.status, lr.approved_by, lr.approved_at, 
-			   lr.rejection_reason, lr.cancelled_by, lr.cancelled_at, lr.cancellation_reason, lr.submitted_at, lr.created_at, lr.updated_at
+			   lr.rejection_reason, lr.cancelled_by, lr.cancelled_at, lr.cancellation_reason, lr.submitted_at, lr.created_at, lr.updated_at, lr.version, lr.escalated_at
 		FROM leave_requests lr
 		INNER JOIN employees e ON lr.employee_id = e.id
 		WHERE e.id = $1 AND e.company_id = $2
-		ORDER BY lr.submitted_at DESC
+		ORDER BY lr.submitted_at DESC, lr.id DESC
 	`
 
 	rows, err := q.Query(ctx, query, userID, companyID)
@@ -61,6 +63,8 @@ func (r *leaveRequestRepositoryImpl) GetMyRequest(ctx context.Context, userID st
 			&lr.SubmittedAt,
 			&lr.CreatedAt,
 			&lr.UpdatedAt,
+			&lr.Version,
+			&lr.EscalatedAt,
 		)
 		if err != nil {
 			return nil, 0, err
@@ -113,32 +117,47 @@ func (r *leaveRequestRepositoryImpl) Create(ctx context.Context, request leave.L
 			id, employee_id, leave_type_id,
 			start_date, end_date, duration_type, total_days, working_days,
 			reason, attachment_url, emergency_leave, is_backdate,
-			status, submitted_at,
+			status, recurrence_id, submitted_at,
 			created_at, updated_at
 		) VALUES (
 			uuidv7(), $1, $2,
 			$3, $4, $5, $6, $7,
 			$8, $9, $10, $11,
-			$12, NOW(),
+			$12, $13, NOW(),
 			NOW(), NOW()
-		) RETURNING id, submitted_at, created_at, updated_at
+		) RETURNING id, submitted_at, created_at, updated_at, version
 	`
 
 	err := q.QueryRow(ctx, query,
 		request.EmployeeID, request.LeaveTypeID,
 		request.StartDate, request.EndDate, request.DurationType, request.TotalDays, request.WorkingDays,
 		request.Reason, request.AttachmentURL, request.EmergencyLeave, request.IsBackdate,
-		request.Status,
-	).Scan(&request.ID, &request.SubmittedAt, &request.CreatedAt, &request.UpdatedAt)
+		request.Status, request.RecurrenceID,
+	).Scan(&request.ID, &request.SubmittedAt, &request.CreatedAt, &request.UpdatedAt, &request.Version)
 
 	if err != nil {
-		return leave.LeaveRequest{}, nil
+		return leave.LeaveRequest{}, fmt.Errorf("failed to create leave request: %w", err)
 	}
 
 	return request, nil
 }
 
 func (r *leaveRequestRepositoryImpl) GetByID(ctx context.Context, id string) (leave.LeaveRequest, error) {
+	return r.getOne(ctx, "lr.id = $1", id)
+}
+
+// GetByIDInCompany implements leave.LeaveRequestRepository.
+func (r *leaveRequestRepositoryImpl) GetByIDInCompany(ctx context.Context, id string, companyID string) (leave.LeaveRequest, error) {
+	return r.getOne(ctx, "lr.id = $1 AND e.company_id = $2", id, companyID)
+}
+
+// GetByIDForUpdate implements leave.LeaveRequestRepository.
+func (r *leaveRequestRepositoryImpl) GetByIDForUpdate(ctx context.Context, id string) (leave.LeaveRequest, error) {
+	return r.getOne(ctx, "lr.id = $1 FOR UPDATE OF lr", id)
+}
+
+// getOne returns the single leave request matching where, with its leave type and employee names
+func (r *leaveRequestRepositoryImpl) getOne(ctx context.Context, where string, args ...any) (leave.LeaveRequest, error) {
 	q := GetQuerier(ctx, r.db)
 
 	query := `
@@ -148,25 +167,25 @@ func (r *leaveRequestRepositoryImpl) GetByID(ctx context.Context, id string) (le
 			   lr.status,
 			   lr.approved_by, lr.approved_at, lr.rejection_reason,
 			   lr.cancelled_by, lr.cancelled_at, lr.cancellation_reason,
-			   lr.submitted_at, lr.created_at, lr.updated_at,
+			   lr.recurrence_id, lr.submitted_at, lr.created_at, lr.updated_at, lr.version, lr.escalated_at,
 			   lt.name as leave_type_name,
 			   e.full_name as employee_name
 		FROM leave_requests lr
 		JOIN leave_types lt ON lr.leave_type_id = lt.id
-		JOIN employees e ON lr.employee_id = e.id WHERE lr.id = $1
-	`
+		JOIN employees e ON lr.employee_id = e.id
+		WHERE ` + where
 
 	var req leave.LeaveRequest
 	var leaveTypeName, employeeName string
 
-	err := q.QueryRow(ctx, query, id).Scan(
+	err := q.QueryRow(ctx, query, args...).Scan(
 		&req.ID, &req.EmployeeID, &req.LeaveTypeID,
 		&req.StartDate, &req.EndDate, &req.DurationType, &req.TotalDays, &req.WorkingDays,
 		&req.Reason, &req.AttachmentURL, &req.EmergencyLeave, &req.IsBackdate,
 		&req.Status,
 		&req.ApprovedBy, &req.ApprovedAt, &req.RejectionReason,
 		&req.CancelledBy, &req.CancelledAt, &req.CancellationReason,
-		&req.SubmittedAt, &req.CreatedAt, &req.UpdatedAt,
+		&req.RecurrenceID, &req.SubmittedAt, &req.CreatedAt, &req.UpdatedAt, &req.Version, &req.EscalatedAt,
 		&leaveTypeName, &employeeName,
 	)
 
@@ -183,6 +202,111 @@ func (r *leaveRequestRepositoryImpl) GetByID(ctx context.Context, id string) (le
 	return req, nil
 }
 
+// GetByRecurrenceID implements leave.LeaveRequestRepository.
+func (r *leaveRequestRepositoryImpl) GetByRecurrenceID(ctx context.Context, recurrenceID string) ([]leave.LeaveRequest, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT lr.id, lr.employee_id, lr.leave_type_id,
+			   lr.start_date, lr.end_date, lr.duration_type, lr.total_days, lr.working_days,
+			   lr.reason, lr.attachment_url, lr.emergency_leave, lr.is_backdate,
+			   lr.status,
+			   lr.approved_by, lr.approved_at, lr.rejection_reason,
+			   lr.cancelled_by, lr.cancelled_at, lr.cancellation_reason,
+			   lr.recurrence_id, lr.submitted_at, lr.created_at, lr.updated_at, lr.version, lr.escalated_at,
+			   lt.name as leave_type_name,
+			   e.full_name as employee_name
+		FROM leave_requests lr
+		JOIN leave_types lt ON lr.leave_type_id = lt.id
+		JOIN employees e ON lr.employee_id = e.id
+		WHERE lr.recurrence_id = $1
+		ORDER BY lr.start_date ASC, lr.id
+	`
+
+	rows, err := q.Query(ctx, query, recurrenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leave requests by recurrence: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []leave.LeaveRequest
+	for rows.Next() {
+		var req leave.LeaveRequest
+		var leaveTypeName, employeeName string
+
+		err := rows.Scan(
+			&req.ID, &req.EmployeeID, &req.LeaveTypeID,
+			&req.StartDate, &req.EndDate, &req.DurationType, &req.TotalDays, &req.WorkingDays,
+			&req.Reason, &req.AttachmentURL, &req.EmergencyLeave, &req.IsBackdate,
+			&req.Status,
+			&req.ApprovedBy, &req.ApprovedAt, &req.RejectionReason,
+			&req.CancelledBy, &req.CancelledAt, &req.CancellationReason,
+			&req.RecurrenceID, &req.SubmittedAt, &req.CreatedAt, &req.UpdatedAt, &req.Version, &req.EscalatedAt,
+			&leaveTypeName, &employeeName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan leave request: %w", err)
+		}
+
+		req.LeaveTypeName = &leaveTypeName
+		req.EmployeeName = &employeeName
+		requests = append(requests, req)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// GetApprovedByEmployeeID implements leave.LeaveRequestRepository.
+func (r *leaveRequestRepositoryImpl) GetApprovedByEmployeeID(ctx context.Context, employeeID string) ([]leave.LeaveRequest, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT lr.id, lr.employee_id, lr.leave_type_id,
+			   lr.start_date, lr.end_date, lr.duration_type, lr.total_days, lr.working_days,
+			   lr.reason, lr.status, lr.approved_at, lr.recurrence_id, lr.updated_at, lr.version, lr.escalated_at,
+			   lt.name as leave_type_name
+		FROM leave_requests lr
+		JOIN leave_types lt ON lr.leave_type_id = lt.id
+		WHERE lr.employee_id = $1 AND lr.status = 'approved'
+		ORDER BY lr.start_date ASC, lr.id
+	`
+
+	rows, err := q.Query(ctx, query, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approved leave requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []leave.LeaveRequest
+	for rows.Next() {
+		var req leave.LeaveRequest
+		var leaveTypeName string
+
+		err := rows.Scan(
+			&req.ID, &req.EmployeeID, &req.LeaveTypeID,
+			&req.StartDate, &req.EndDate, &req.DurationType, &req.TotalDays, &req.WorkingDays,
+			&req.Reason, &req.Status, &req.ApprovedAt, &req.RecurrenceID, &req.UpdatedAt, &req.Version, &req.EscalatedAt,
+			&leaveTypeName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan leave request: %w", err)
+		}
+
+		req.LeaveTypeName = &leaveTypeName
+		requests = append(requests, req)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
 func (r *leaveRequestRepositoryImpl) GetByEmployeeID(ctx context.Context, employeeID string, filter leave.LeaveRequestFilter) ([]leave.LeaveRequest, int64, error) {
 	q := GetQuerier(ctx, r.db)
 
@@ -203,17 +327,12 @@ func (r *leaveRequestRepositoryImpl) GetByEmployeeID(ctx context.Context, employ
 		argIndex++
 	}
 
-	if filter.StartDate != nil {
-		whereClause += fmt.Sprintf(" AND lr.start_date >= $%d", argIndex)
-		args = append(args, *filter.StartDate)
-		argIndex++
-	}
-
-	if filter.EndDate != nil {
-		whereClause += fmt.Sprintf(" AND lr.end_date <= $%d", argIndex)
-		args = append(args, *filter.EndDate)
-		argIndex++
+	dateClauses, dateArgs := leaveDateRangeClauses(filter.DateMode, filter.StartDate, filter.EndDate, argIndex)
+	for _, clause := range dateClauses {
+		whereClause += " AND " + clause
 	}
+	args = append(args, dateArgs...)
+	argIndex += len(dateArgs)
 
 	// Count total
 	countQuery := fmt.Sprintf(`
@@ -234,7 +353,7 @@ func (r *leaveRequestRepositoryImpl) GetByEmployeeID(ctx context.Context, employ
 		filter.Limit = 10
 	}
 
-	offset := (filter.Page - 1) * filter.Limit
+	offset, limit := pagination.Paginate(filter.Page, filter.Limit)
 
 	query := fmt.Sprintf(`
 		SELECT lr.id, lr.employee_id, lr.leave_type_id,
@@ -243,18 +362,18 @@ func (r *leaveRequestRepositoryImpl) GetByEmployeeID(ctx context.Context, employ
 			   lr.status,
 			   lr.approved_by, lr.approved_at, lr.rejection_reason,
 			   lr.cancelled_by, lr.cancelled_at, lr.cancellation_reason,
-			   lr.submitted_at, lr.created_at, lr.updated_at,
+			   lr.submitted_at, lr.created_at, lr.updated_at, lr.version, lr.escalated_at,
 			   lt.name as leave_type_name,
 			   e.full_name as employee_name
 		FROM leave_requests lr
 		JOIN leave_types lt ON lr.leave_type_id = lt.id
 		JOIN employees e ON lr.employee_id = e.id
 		%s
-		ORDER BY lr.submitted_at DESC
+		ORDER BY lr.submitted_at DESC, lr.id DESC
 		LIMIT $%d OFFSET $%d
 	`, whereClause, argIndex, argIndex+1)
 
-	args = append(args, filter.Limit, offset)
+	args = append(args, limit, offset)
 
 	rows, err := q.Query(ctx, query, args...)
 	if err != nil {
@@ -275,7 +394,7 @@ func (r *leaveRequestRepositoryImpl) GetByEmployeeID(ctx context.Context, employ
 			&req.Status,
 			&req.ApprovedBy, &req.ApprovedAt, &req.RejectionReason,
 			&req.CancelledBy, &req.CancelledAt, &req.CancellationReason,
-			&req.SubmittedAt, &req.CreatedAt, &req.UpdatedAt,
+			&req.SubmittedAt, &req.CreatedAt, &req.UpdatedAt, &req.Version, &req.EscalatedAt,
 			&leaveTypeName, &employeeName,
 		)
 
@@ -399,17 +518,191 @@ func (r *leaveRequestRepositoryImpl) Update(ctx context.Context, request leave.U
 	args = append(args, time.Now())
 	argIdx++
 
-	args = append(args, request.ID)
+	updates = append(updates, "version = version + 1")
 
-	sql := "UPDATE leave_requests SET " + strings.Join(updates, ", ") + fmt.Sprintf(" WHERE id = $%d RETURNING id", argIdx)
+	args = append(args, request.ID, request.ExpectedVersion)
+	where := fmt.Sprintf(" WHERE id = $%d AND version = $%d", argIdx, argIdx+1)
+	argIdx++
+	if request.ExpectedStatus != nil {
+		argIdx++
+		args = append(args, *request.ExpectedStatus)
+		where += fmt.Sprintf(" AND status = $%d", argIdx)
+	}
+
+	sql := "UPDATE leave_requests SET " + strings.Join(updates, ", ") + where + " RETURNING id"
 
 	var updatedID string
 	if err := q.QueryRow(ctx, sql, args...).Scan(&updatedID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return r.updateConflict(ctx, request)
+		}
 		return fmt.Errorf("failed to update leave request with id %s: %w", request.ID, err)
 	}
 	return nil
 }
 
+// updateConflict explains why a guarded Update matched no row: the request is gone, it left the
+// expected status, or another change bumped its version
+func (r *leaveRequestRepositoryImpl) updateConflict(ctx context.Context, request leave.UpdateLeaveRequestRequest) error {
+	q := GetQuerier(ctx, r.db)
+
+	var status string
+	if err := q.QueryRow(ctx, `SELECT status FROM leave_requests WHERE id = $1`, request.ID).Scan(&status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return leave.ErrLeaveRequestNotFound
+		}
+		return fmt.Errorf("failed to check leave request with id %s: %w", request.ID, err)
+	}
+	if request.ExpectedStatus != nil && status != *request.ExpectedStatus {
+		return leave.ErrLeaveAlreadyProcessed
+	}
+	return leave.ErrConcurrentModification
+}
+
+// Reopen implements leave.LeaveRequestRepository.
+func (r *leaveRequestRepositoryImpl) Reopen(ctx context.Context, id string, submittedAt time.Time) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+        UPDATE leave_requests
+        SET status = 'waiting_approval', approved_by = NULL, approved_at = NULL, rejection_reason = NULL,
+            submitted_at = $2, updated_at = $2, escalated_at = NULL, version = version + 1
+        WHERE id = $1 AND status = 'rejected'
+        RETURNING id
+    `
+
+	var reopenedID string
+	return q.QueryRow(ctx, query, id, submittedAt).Scan(&reopenedID)
+}
+
+// GetStaleApprovals implements leave.LeaveRequestRepository.
+func (r *leaveRequestRepositoryImpl) GetStaleApprovals(ctx context.Context, now time.Time) ([]leave.StaleApproval, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+        SELECT lr.id, lr.employee_id, lr.leave_type_id, lr.start_date, lr.end_date, lr.duration_type,
+               lr.total_days, lr.working_days, lr.reason, lr.status, lr.submitted_at, lr.version,
+               lt.name, e.full_name, e.company_id, c.leave_approval_sla_hours, c.leave_escalation_action
+        FROM leave_requests lr
+        INNER JOIN employees e ON lr.employee_id = e.id
+        INNER JOIN companies c ON e.company_id = c.id
+        INNER JOIN leave_types lt ON lr.leave_type_id = lt.id
+        WHERE lr.status = 'waiting_approval'
+        AND lr.escalated_at IS NULL
+        AND c.leave_approval_sla_hours > 0
+        AND lr.submitted_at <= $1::timestamptz - make_interval(hours => c.leave_approval_sla_hours)
+        ORDER BY lr.submitted_at ASC, lr.id ASC
+    `
+
+	rows, err := q.Query(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stale leave approvals: %w", err)
+	}
+	defer rows.Close()
+
+	var stale []leave.StaleApproval
+	for rows.Next() {
+		var s leave.StaleApproval
+		req := &s.Request
+		if err := rows.Scan(
+			&req.ID, &req.EmployeeID, &req.LeaveTypeID, &req.StartDate, &req.EndDate, &req.DurationType,
+			&req.TotalDays, &req.WorkingDays, &req.Reason, &req.Status, &req.SubmittedAt, &req.Version,
+			&req.LeaveTypeName, &req.EmployeeName, &s.CompanyID, &s.SLAHours, &s.EscalationAction,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan stale leave approval: %w", err)
+		}
+		stale = append(stale, s)
+	}
+
+	return stale, rows.Err()
+}
+
+// MarkEscalated implements leave.LeaveRequestRepository.
+func (r *leaveRequestRepositoryImpl) MarkEscalated(ctx context.Context, id string, escalatedAt time.Time) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+        UPDATE leave_requests
+        SET escalated_at = $2
+        WHERE id = $1 AND status = 'waiting_approval' AND escalated_at IS NULL
+        RETURNING id
+    `
+
+	var escalatedID string
+	return q.QueryRow(ctx, query, id, escalatedAt).Scan(&escalatedID)
+}
+
+// CountPendingApprovals implements leave.LeaveRequestRepository.
+func (r *leaveRequestRepositoryImpl) CountPendingApprovals(ctx context.Context, companyID string, approverUserID string, branchID *string) (int64, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+        SELECT COUNT(*)
+        FROM leave_requests lr
+        INNER JOIN employees e ON lr.employee_id = e.id
+        WHERE e.company_id = $1
+        AND lr.status = 'waiting_approval'
+        AND e.user_id IS DISTINCT FROM $2
+        AND ($3::uuid IS NULL OR e.branch_id = $3)
+    `
+
+	var count int64
+	if err := q.QueryRow(ctx, query, companyID, approverUserID, branchID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count pending leave approvals: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetTypeStats implements leave.LeaveRequestRepository.
+// Every leave type of the company gets a row, including those without requests in the period.
+func (r *leaveRequestRepositoryImpl) GetTypeStats(ctx context.Context, companyID string, start, end time.Time, branchID, positionID *string) ([]leave.LeaveTypeStats, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+        SELECT
+            lt.id,
+            lt.name,
+            COUNT(lr.id),
+            COUNT(lr.id) FILTER (WHERE lr.status = 'approved'),
+            COUNT(lr.id) FILTER (WHERE lr.status = 'rejected'),
+            COALESCE(SUM(lr.working_days) FILTER (WHERE lr.status = 'approved'), 0)::float8,
+            COALESCE(AVG(lr.total_days), 0)::float8
+        FROM leave_types lt
+        LEFT JOIN (
+            leave_requests lr
+            INNER JOIN employees e ON e.id = lr.employee_id
+        ) ON lr.leave_type_id = lt.id
+            AND lr.start_date >= $2 AND lr.start_date < $3
+            AND ($4::uuid IS NULL OR e.branch_id = $4)
+            AND ($5::uuid IS NULL OR e.position_id = $5)
+        WHERE lt.company_id = $1
+        GROUP BY lt.id, lt.name
+        ORDER BY COUNT(lr.id) DESC, lt.name ASC
+    `
+
+	rows, err := q.Query(ctx, query, companyID, start, end, branchID, positionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leave type stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []leave.LeaveTypeStats
+	for rows.Next() {
+		var s leave.LeaveTypeStats
+		if err := rows.Scan(
+			&s.LeaveTypeID, &s.LeaveTypeName,
+			&s.TotalRequests, &s.ApprovedRequests, &s.RejectedRequests,
+			&s.ApprovedDays, &s.AverageDays,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan leave type stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
 func (r *leaveRequestRepositoryImpl) CheckOverlapping(
 	ctx context.Context,
 	employeeID string,
@@ -437,16 +730,59 @@ func (r *leaveRequestRepositoryImpl) CheckOverlapping(
 	return exists, err
 }
 
-// before
-
-func (r *leaveRequestRepositoryImpl) GetByCompanyID(
+// CheckOverlappingExcluding implements leave.LeaveRequestRepository.
+func (r *leaveRequestRepositoryImpl) CheckOverlappingExcluding(
 	ctx context.Context,
-	companyID string,
-	filter leave.LeaveRequestFilter,
-) ([]leave.LeaveRequest, int64, error) {
+	employeeID string,
+	startDate, endDate time.Time,
+	excludeID string,
+) (bool, error) {
 	q := GetQuerier(ctx, r.db)
 
-	// Base query with JOINs
+	query := `
+        SELECT EXISTS (
+            SELECT 1
+            FROM leave_requests
+            WHERE employee_id = $1
+            AND id <> $4
+            AND status IN ('waiting_approval', 'approved')
+            AND start_date <= $3
+            AND end_date >= $2
+        )
+    `
+
+	var exists bool
+	err := q.QueryRow(ctx, query, employeeID, startDate, endDate, excludeID).Scan(&exists)
+
+	return exists, err
+}
+
+// leaveDateRangeClauses builds the date conditions of a list filter, numbering placeholders from argIdx.
+// The default mode keeps requests lying inside the range; LeaveDateModeOverlaps keeps any request
+// with a day in it, so filtering on June also returns a leave from May 28 to June 3.
+func leaveDateRangeClauses(mode string, startDate, endDate *string, argIdx int) ([]string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+
+	startColumn, endColumn := "lr.start_date", "lr.end_date"
+	if mode == leave.LeaveDateModeOverlaps {
+		startColumn, endColumn = "lr.end_date", "lr.start_date"
+	}
+
+	if startDate != nil && *startDate != "" {
+		clauses = append(clauses, fmt.Sprintf("%s >= $%d", startColumn, argIdx+len(args)))
+		args = append(args, *startDate)
+	}
+	if endDate != nil && *endDate != "" {
+		clauses = append(clauses, fmt.Sprintf("%s <= $%d", endColumn, argIdx+len(args)))
+		args = append(args, *endDate)
+	}
+
+	return clauses, args
+}
+
+// companyLeaveRequestsBaseQuery builds the FROM/JOIN/WHERE part shared by the admin list and export
+func companyLeaveRequestsBaseQuery(companyID string, filter leave.LeaveRequestFilter) (string, []interface{}) {
 	baseQuery := `
         FROM leave_requests lr
         INNER JOIN employees e ON lr.employee_id = e.id
@@ -489,15 +825,15 @@ func (r *leaveRequestRepositoryImpl) GetByCompanyID(
 	}
 
 	// Filter by date range
-	if filter.StartDate != nil && *filter.StartDate != "" {
-		whereClauses = append(whereClauses, fmt.Sprintf("lr.start_date >= $%d", argIdx))
-		args = append(args, *filter.StartDate)
-		argIdx++
-	}
-
-	if filter.EndDate != nil && *filter.EndDate != "" {
-		whereClauses = append(whereClauses, fmt.Sprintf("lr.end_date <= $%d", argIdx))
-		args = append(args, *filter.EndDate)
+	dateClauses, dateArgs := leaveDateRangeClauses(filter.DateMode, filter.StartDate, filter.EndDate, argIdx)
+	whereClauses = append(whereClauses, dateClauses...)
+	args = append(args, dateArgs...)
+	argIdx += len(dateArgs)
+
+	// Restrict to a branch (branch-scoped managers)
+	if filter.BranchID != nil && *filter.BranchID != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("e.branch_id = $%d", argIdx))
+		args = append(args, *filter.BranchID)
 		argIdx++
 	}
 
@@ -506,29 +842,11 @@ func (r *leaveRequestRepositoryImpl) GetByCompanyID(
 		baseQuery += " AND " + strings.Join(whereClauses, " AND ")
 	}
 
-	// COUNT query for total records
-	countQuery := "SELECT COUNT(*) " + baseQuery
-	var total int64
-	err := q.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count leave requests: %w", err)
-	}
-
-	// Main SELECT query
-	selectQuery := `
-        SELECT 
-            lr.id, lr.employee_id, lr.leave_type_id,
-            lr.start_date, lr.end_date, lr.duration_type, lr.total_days, lr.working_days,
-            lr.reason, lr.attachment_url, lr.emergency_leave, lr.is_backdate,
-            lr.status,
-            lr.approved_by, lr.approved_at, lr.rejection_reason,
-            lr.cancelled_by, lr.cancelled_at, lr.cancellation_reason,
-            lr.submitted_at, lr.created_at, lr.updated_at,
-            lt.name as leave_type_name,
-            e.full_name as employee_name
-    ` + baseQuery
+	return baseQuery, args
+}
 
-	// ORDER BY clause
+// companyLeaveRequestsOrderBy maps the filter's sort options to an ORDER BY expression
+func companyLeaveRequestsOrderBy(filter leave.LeaveRequestFilter) string {
 	orderBy := "lr.submitted_at DESC" // Default
 
 	switch filter.SortBy {
@@ -550,14 +868,46 @@ func (r *leaveRequestRepositoryImpl) GetByCompanyID(
 		orderBy += " DESC"
 	}
 
-	selectQuery += " ORDER BY " + orderBy
+	// Break ties so pages never overlap or skip rows
+	return orderBy + ", lr.id"
+}
 
-	// PAGINATION
-	limit := filter.Limit
-	if limit == 0 {
-		limit = 20
+func (r *leaveRequestRepositoryImpl) GetByCompanyID(
+	ctx context.Context,
+	companyID string,
+	filter leave.LeaveRequestFilter,
+) ([]leave.LeaveRequest, int64, error) {
+	q := GetQuerier(ctx, r.db)
+
+	baseQuery, args := companyLeaveRequestsBaseQuery(companyID, filter)
+	argIdx := len(args) + 1
+
+	// COUNT query for total records
+	countQuery := "SELECT COUNT(*) " + baseQuery
+	var total int64
+	err := q.QueryRow(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count leave requests: %w", err)
 	}
-	offset := (filter.Page - 1) * limit
+
+	// Main SELECT query
+	selectQuery := `
+        SELECT 
+            lr.id, lr.employee_id, lr.leave_type_id,
+            lr.start_date, lr.end_date, lr.duration_type, lr.total_days, lr.working_days,
+            lr.reason, lr.attachment_url, lr.emergency_leave, lr.is_backdate,
+            lr.status,
+            lr.approved_by, lr.approved_at, lr.rejection_reason,
+            lr.cancelled_by, lr.cancelled_at, lr.cancellation_reason,
+            lr.submitted_at, lr.created_at, lr.updated_at, lr.version, lr.escalated_at,
+            lt.name as leave_type_name,
+            e.full_name as employee_name
+    ` + baseQuery
+
+	selectQuery += " ORDER BY " + companyLeaveRequestsOrderBy(filter)
+
+	// PAGINATION
+	offset, limit := pagination.Paginate(filter.Page, filter.Limit)
 
 	selectQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
 	args = append(args, limit, offset)
@@ -582,7 +932,7 @@ func (r *leaveRequestRepositoryImpl) GetByCompanyID(
 			&req.Status,
 			&req.ApprovedBy, &req.ApprovedAt, &req.RejectionReason,
 			&req.CancelledBy, &req.CancelledAt, &req.CancellationReason,
-			&req.SubmittedAt, &req.CreatedAt, &req.UpdatedAt,
+			&req.SubmittedAt, &req.CreatedAt, &req.UpdatedAt, &req.Version, &req.EscalatedAt,
 			&leaveTypeName, &employeeName,
 		)
 
@@ -603,6 +953,67 @@ func (r *leaveRequestRepositoryImpl) GetByCompanyID(
 	return requests, total, nil
 }
 
+// StreamByCompanyID walks every request matching the filter, ignoring pagination, without loading them all
+func (r *leaveRequestRepositoryImpl) StreamByCompanyID(
+	ctx context.Context,
+	companyID string,
+	filter leave.LeaveRequestFilter,
+	fn func(leave.LeaveRequest) error,
+) error {
+	q := GetQuerier(ctx, r.db)
+
+	baseQuery, args := companyLeaveRequestsBaseQuery(companyID, filter)
+
+	selectQuery := `
+        SELECT 
+            lr.id, lr.employee_id, lr.leave_type_id,
+            lr.start_date, lr.end_date, lr.duration_type, lr.total_days, lr.working_days,
+            lr.reason, lr.status,
+            lr.approved_by, lr.approved_at, lr.rejection_reason,
+            lr.submitted_at, lr.created_at, lr.updated_at, lr.version, lr.escalated_at,
+            lt.name as leave_type_name,
+            e.full_name as employee_name,
+            e.employee_code
+    ` + baseQuery + " ORDER BY " + companyLeaveRequestsOrderBy(filter)
+
+	rows, err := q.Query(ctx, selectQuery, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query leave requests: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var req leave.LeaveRequest
+		var leaveTypeName, employeeName, employeeCode string
+
+		err := rows.Scan(
+			&req.ID, &req.EmployeeID, &req.LeaveTypeID,
+			&req.StartDate, &req.EndDate, &req.DurationType, &req.TotalDays, &req.WorkingDays,
+			&req.Reason, &req.Status,
+			&req.ApprovedBy, &req.ApprovedAt, &req.RejectionReason,
+			&req.SubmittedAt, &req.CreatedAt, &req.UpdatedAt, &req.Version, &req.EscalatedAt,
+			&leaveTypeName, &employeeName, &employeeCode,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan leave request: %w", err)
+		}
+
+		req.LeaveTypeName = &leaveTypeName
+		req.EmployeeName = &employeeName
+		req.EmployeeCode = &employeeCode
+
+		if err := fn(req); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return nil
+}
+
 // GetMyRequests implements leave.LeaveRequestRepository with filtering for authenticated user
 func (r *leaveRequestRepositoryImpl) GetMyRequests(ctx context.Context, employeeID string, companyID string, filter leave.MyLeaveRequestFilter) ([]leave.LeaveRequest, int64, error) {
 	q := GetQuerier(ctx, r.db)
@@ -627,17 +1038,10 @@ func (r *leaveRequestRepositoryImpl) GetMyRequests(ctx context.Context, employee
 	}
 
 	// Date filters
-	if filter.StartDate != nil && *filter.StartDate != "" {
-		paramCount++
-		whereClauses = append(whereClauses, fmt.Sprintf("lr.start_date >= $%d", paramCount))
-		args = append(args, *filter.StartDate)
-	}
-
-	if filter.EndDate != nil && *filter.EndDate != "" {
-		paramCount++
-		whereClauses = append(whereClauses, fmt.Sprintf("lr.end_date <= $%d", paramCount))
-		args = append(args, *filter.EndDate)
-	}
+	dateClauses, dateArgs := leaveDateRangeClauses(filter.DateMode, filter.StartDate, filter.EndDate, paramCount+1)
+	whereClauses = append(whereClauses, dateClauses...)
+	args = append(args, dateArgs...)
+	paramCount += len(dateArgs)
 
 	whereClause := strings.Join(whereClauses, " AND ")
 
@@ -653,7 +1057,7 @@ func (r *leaveRequestRepositoryImpl) GetMyRequests(ctx context.Context, employee
 	default:
 		orderBy = "lr.submitted_at"
 	}
-	orderBy += " " + strings.ToUpper(filter.SortOrder)
+	orderBy += " " + strings.ToUpper(filter.SortOrder) + ", lr.id"
 
 	// Count query
 	countQuery := fmt.Sprintf(`
@@ -670,14 +1074,14 @@ func (r *leaveRequestRepositoryImpl) GetMyRequests(ctx context.Context, employee
 	}
 
 	// Main query with JOINs
-	offset := (filter.Page - 1) * filter.Limit
+	offset, limit := pagination.Paginate(filter.Page, filter.Limit)
 	query := fmt.Sprintf(`
 		SELECT 
 			lr.id, lr.employee_id, lr.leave_type_id, lr.start_date, lr.end_date,
 			lr.duration_type, lr.total_days, lr.working_days, lr.reason, lr.attachment_url,
 			lr.emergency_leave, lr.is_backdate, lr.status, lr.approved_by, lr.approved_at,
 			lr.rejection_reason, lr.cancelled_by, lr.cancelled_at, lr.cancellation_reason,
-			lr.submitted_at, lr.created_at, lr.updated_at,
+			lr.submitted_at, lr.created_at, lr.updated_at, lr.version, lr.escalated_at,
 			lt.name as leave_type_name,
 			e.full_name as employee_name
 		FROM leave_requests lr
@@ -688,7 +1092,7 @@ func (r *leaveRequestRepositoryImpl) GetMyRequests(ctx context.Context, employee
 		LIMIT $%d OFFSET $%d
 	`, whereClause, orderBy, paramCount+1, paramCount+2)
 
-	args = append(args, filter.Limit, offset)
+	args = append(args, limit, offset)
 
 	rows, err := q.Query(ctx, query, args...)
 	if err != nil {
@@ -706,7 +1110,7 @@ func (r *leaveRequestRepositoryImpl) GetMyRequests(ctx context.Context, employee
 			&req.DurationType, &req.TotalDays, &req.WorkingDays, &req.Reason, &req.AttachmentURL,
 			&req.EmergencyLeave, &req.IsBackdate, &req.Status, &req.ApprovedBy, &req.ApprovedAt,
 			&req.RejectionReason, &req.CancelledBy, &req.CancelledAt, &req.CancellationReason,
-			&req.SubmittedAt, &req.CreatedAt, &req.UpdatedAt,
+			&req.SubmittedAt, &req.CreatedAt, &req.UpdatedAt, &req.Version, &req.EscalatedAt,
 			&leaveTypeName, &employeeName,
 		)
 		if err != nil {
@@ -732,7 +1136,7 @@ func (r *leaveRequestRepositoryImpl) UpdateStatus(ctx context.Context, id, statu
 	if approvedBy != "" {
 		query = `
 			UPDATE leave_requests
-			SET status = $1, approved_by = $2, approved_at = $3
+			SET status = $1, approved_by = $2, approved_at = $3, version = version + 1
 			WHERE id = $4
 			RETURNING id
 		`
@@ -740,7 +1144,7 @@ func (r *leaveRequestRepositoryImpl) UpdateStatus(ctx context.Context, id, statu
 	} else {
 		query = `
 			UPDATE leave_requests
-			SET status = $1
+			SET status = $1, version = version + 1
 			WHERE id = $2
 			RETURNING id
 		`
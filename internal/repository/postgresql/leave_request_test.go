@@ -0,0 +1,151 @@
+package postgresql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+)
+
+// leaveRequestRow returns the columns GetMyRequest selects, in order
+func leaveRequestRow(id string, submittedAt time.Time, escalatedAt *time.Time) []any {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	return []any{
+		id, "emp-1", "type-1", start, start.AddDate(0, 0, 1), "full_day", 2.0, 2.0,
+		"family event", nil, false, false, "waiting_approval", nil, nil,
+		nil, nil, nil, nil, submittedAt, submittedAt, submittedAt, 1, escalatedAt,
+	}
+}
+
+func TestLeaveRequestRepositoryGetMyRequestScansRows(t *testing.T) {
+	submitted := time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC)
+	escalated := submitted.Add(48 * time.Hour)
+
+	tx := newFakeTx(
+		fakeResult{rows: [][]any{
+			leaveRequestRow("req-2", submitted.Add(time.Hour), &escalated),
+			leaveRequestRow("req-1", submitted, nil),
+		}},
+		fakeResult{rows: [][]any{{int64(2)}}},
+	)
+	repo := &leaveRequestRepositoryImpl{}
+
+	requests, total, err := repo.GetMyRequest(tx.ctx(), "emp-1", "company-1")
+	if err != nil {
+		t.Fatalf("GetMyRequest() error = %v", err)
+	}
+	if total != 2 || len(requests) != 2 {
+		t.Fatalf("GetMyRequest() = %d requests, total %d; want 2, 2", len(requests), total)
+	}
+
+	got := requests[0]
+	if got.ID != "req-2" || got.Status != leave.LeaveRequestStatus("waiting_approval") || got.Version != 1 {
+		t.Errorf("first request = %+v", got)
+	}
+	if got.EscalatedAt == nil || !got.EscalatedAt.Equal(escalated) {
+		t.Errorf("first request EscalatedAt = %v, want %v", got.EscalatedAt, escalated)
+	}
+	if requests[1].EscalatedAt != nil {
+		t.Errorf("second request EscalatedAt = %v, want nil", requests[1].EscalatedAt)
+	}
+
+	if args := tx.calls[0].args; len(args) != 2 || args[0] != "emp-1" || args[1] != "company-1" {
+		t.Errorf("query args = %v, want [emp-1 company-1]", args)
+	}
+}
+
+func TestLeaveRequestRepositoryGetByIDInCompany(t *testing.T) {
+	submitted := time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC)
+	row := leaveRequestRow("req-1", submitted, nil)
+	// GetByID also selects recurrence_id before submitted_at, and the leave type and employee names
+	row = append(row[:19:19], append([]any{nil}, row[19:]...)...)
+	row = append(row, "Annual Leave", "Dewi Lestari")
+
+	tx := newFakeTx(fakeResult{rows: [][]any{row}}, fakeResult{})
+	repo := &leaveRequestRepositoryImpl{}
+
+	req, err := repo.GetByIDInCompany(tx.ctx(), "req-1", "company-1")
+	if err != nil {
+		t.Fatalf("GetByIDInCompany() error = %v", err)
+	}
+	if req.ID != "req-1" || req.EmployeeName == nil || *req.EmployeeName != "Dewi Lestari" {
+		t.Errorf("GetByIDInCompany() = %+v", req)
+	}
+	if call := tx.calls[0]; !strings.Contains(compactSQL(call.sql), "e.company_id = $2") || len(call.args) != 2 || call.args[1] != "company-1" {
+		t.Errorf("query = %s %v, want it filtered by company", compactSQL(call.sql), call.args)
+	}
+
+	// No row: the request is missing or belongs to another company
+	if _, err := repo.GetByIDInCompany(tx.ctx(), "req-1", "company-2"); !errors.Is(err, leave.ErrLeaveRequestNotFound) {
+		t.Errorf("GetByIDInCompany() of another company error = %v, want %v", err, leave.ErrLeaveRequestNotFound)
+	}
+}
+
+func TestLeaveRequestRepositoryGetByIDForUpdateLocksTheRequest(t *testing.T) {
+	tx := newFakeTx(fakeResult{})
+	repo := &leaveRequestRepositoryImpl{}
+
+	if _, err := repo.GetByIDForUpdate(tx.ctx(), "req-1"); !errors.Is(err, leave.ErrLeaveRequestNotFound) {
+		t.Fatalf("GetByIDForUpdate() error = %v, want %v", err, leave.ErrLeaveRequestNotFound)
+	}
+	if sql := compactSQL(tx.calls[0].sql); !strings.HasSuffix(sql, "WHERE lr.id = $1 FOR UPDATE OF lr") {
+		t.Errorf("query = %s, want it to lock the leave request row", sql)
+	}
+}
+
+func TestLeaveRequestRepositoryUpdateGuardsStatus(t *testing.T) {
+	reason := "moving house, need the extra day"
+	pending := "waiting_approval"
+	repo := &leaveRequestRepositoryImpl{}
+
+	tx := newFakeTx(fakeResult{rows: [][]any{{"req-1"}}}, fakeResult{}, fakeResult{rows: [][]any{{"approved"}}})
+	update := leave.UpdateLeaveRequestRequest{ID: "req-1", Reason: &reason, ExpectedVersion: 2, ExpectedStatus: &pending}
+
+	if err := repo.Update(tx.ctx(), update); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	call := tx.calls[0]
+	if sql := compactSQL(call.sql); !strings.Contains(sql, "WHERE id = $3 AND version = $4 AND status = $5") || call.args[3] != 2 || call.args[4] != pending {
+		t.Errorf("query = %s %v, want it guarded by the version and status", sql, call.args)
+	}
+
+	// No row matched: the request was approved, rejected or cancelled meanwhile
+	if err := repo.Update(tx.ctx(), update); !errors.Is(err, leave.ErrLeaveAlreadyProcessed) {
+		t.Errorf("Update() of a processed request error = %v, want %v", err, leave.ErrLeaveAlreadyProcessed)
+	}
+}
+
+func TestLeaveRequestRepositoryUpdateAlwaysChecksVersion(t *testing.T) {
+	totalDays := 2.0
+	tests := []struct {
+		name    string
+		current [][]any // the row read back after the update matched nothing
+		wantErr error
+	}{
+		{"updated", nil, nil},
+		{"changed since it was read", [][]any{{"approved"}}, leave.ErrConcurrentModification},
+		{"deleted since it was read", [][]any{}, leave.ErrLeaveRequestNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newFakeTx(fakeResult{rows: [][]any{{"req-1"}}})
+			if tt.wantErr != nil {
+				tx = newFakeTx(fakeResult{}, fakeResult{rows: tt.current})
+			}
+			repo := &leaveRequestRepositoryImpl{}
+
+			// Without ExpectedStatus only the version guards the update
+			err := repo.Update(tx.ctx(), leave.UpdateLeaveRequestRequest{ID: "req-1", TotalDays: &totalDays, ExpectedVersion: 3})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			call := tx.calls[0]
+			if sql := compactSQL(call.sql); !strings.HasSuffix(sql, "WHERE id = $3 AND version = $4 RETURNING id") || call.args[3] != 3 {
+				t.Errorf("query = %s %v, want it guarded by the version", sql, call.args)
+			}
+		})
+	}
+}
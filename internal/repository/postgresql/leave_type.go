@@ -22,7 +22,7 @@ func (l *leaveTypeRepositoryImpl) GetByName(ctx context.Context, companyID strin
 	query := `
 		SELECT id, company_id, name, code, description, color,
 			   is_active, requires_approval, requires_attachment, attachment_required_after_days,
-			   has_quota, accrual_method,
+			   has_quota, accrual_method, allow_negative_balance,
 			   deduction_type, allow_half_day,
 			   max_days_per_request, min_notice_days, max_advance_days, allow_backdate, backdate_max_days,
 			   allow_rollover, max_rollover_days, rollover_expiry_month,
@@ -37,7 +37,7 @@ func (l *leaveTypeRepositoryImpl) GetByName(ctx context.Context, companyID strin
 	err := q.QueryRow(ctx, query, companyID, name).Scan(
 		&lt.ID, &lt.CompanyID, &lt.Name, &lt.Code, &lt.Description, &lt.Color,
 		&lt.IsActive, &lt.RequiresApproval, &lt.RequiresAttachment, &lt.AttachmentRequiredAfterDays,
-		&lt.HasQuota, &lt.AccrualMethod,
+		&lt.HasQuota, &lt.AccrualMethod, &lt.AllowNegativeBalance,
 		&lt.DeductionType, &lt.AllowHalfDay,
 		&lt.MaxDaysPerRequest, &lt.MinNoticeDays, &lt.MaxAdvanceDays, &lt.AllowBackdate, &lt.BackdateMaxDays,
 		&lt.AllowRollover, &lt.MaxRolloverDays, &lt.RolloverExpiryMonth,
@@ -65,7 +65,41 @@ func NewLeaveTypeRepository(db *database.DB) leave.LeaveTypeRepository {
 
 // GetByCode implements leave.LeaveTypeRepository.
 func (l *leaveTypeRepositoryImpl) GetByCode(ctx context.Context, companyID string, code string) (leave.LeaveType, error) {
-	panic("unimplemented")
+	q := GetQuerier(ctx, l.db)
+	query := `
+		SELECT id, company_id, name, code, description, color,
+			   is_active, requires_approval, requires_attachment, attachment_required_after_days,
+			   has_quota, accrual_method, allow_negative_balance,
+			   deduction_type, allow_half_day,
+			   max_days_per_request, min_notice_days, max_advance_days, allow_backdate, backdate_max_days,
+			   allow_rollover, max_rollover_days, rollover_expiry_month,
+			   quota_calculation_type, quota_rules,
+			   created_at, updated_at
+		FROM leave_types
+		WHERE company_id = $1 AND code = $2
+	`
+	var lt leave.LeaveType
+	var quotaRulesJSON []byte
+
+	err := q.QueryRow(ctx, query, companyID, code).Scan(
+		&lt.ID, &lt.CompanyID, &lt.Name, &lt.Code, &lt.Description, &lt.Color,
+		&lt.IsActive, &lt.RequiresApproval, &lt.RequiresAttachment, &lt.AttachmentRequiredAfterDays,
+		&lt.HasQuota, &lt.AccrualMethod, &lt.AllowNegativeBalance,
+		&lt.DeductionType, &lt.AllowHalfDay,
+		&lt.MaxDaysPerRequest, &lt.MinNoticeDays, &lt.MaxAdvanceDays, &lt.AllowBackdate, &lt.BackdateMaxDays,
+		&lt.AllowRollover, &lt.MaxRolloverDays, &lt.RolloverExpiryMonth,
+		&lt.QuotaCalculationType, &quotaRulesJSON,
+		&lt.CreatedAt, &lt.UpdatedAt,
+	)
+	if err != nil {
+		return leave.LeaveType{}, err
+	}
+
+	if quotaRulesJSON != nil {
+		json.Unmarshal(quotaRulesJSON, &lt.QuotaRules)
+	}
+
+	return lt, nil
 }
 
 // Delete implements leave.LeaveTypeRepository.
@@ -94,17 +128,17 @@ func (l *leaveTypeRepositoryImpl) Create(ctx context.Context, leaveType leave.Le
 	query := `
 		INSERT INTO leave_types (
 			id, company_id, name,
-			quota_calculation_type, quota_rules,
+			quota_calculation_type, quota_rules, allow_negative_balance,
 			created_at, updated_at
 		) VALUES (
-			uuidv7(), $1, $2, $3, $4,
+			uuidv7(), $1, $2, $3, $4, COALESCE($5, false),
 			NOW(), NOW()
 		) RETURNING id, created_at, updated_at
 	`
 
 	err := q.QueryRow(ctx, query,
 		leaveType.CompanyID, leaveType.Name,
-		leaveType.QuotaCalculationType, quotaRulesJSON,
+		leaveType.QuotaCalculationType, quotaRulesJSON, leaveType.AllowNegativeBalance,
 	).Scan(&leaveType.ID, &leaveType.CreatedAt, &leaveType.UpdatedAt)
 
 	if err != nil {
@@ -120,7 +154,7 @@ func (l *leaveTypeRepositoryImpl) GetByCompanyID(ctx context.Context, companyID
 	query := `
 		SELECT id, company_id, name, code, description, color,
 			   is_active, requires_approval, requires_attachment, attachment_required_after_days,
-			   has_quota, accrual_method,
+			   has_quota, accrual_method, allow_negative_balance,
 			   deduction_type, allow_half_day,
 			   max_days_per_request, min_notice_days, max_advance_days, allow_backdate, backdate_max_days,
 			   allow_rollover, max_rollover_days, rollover_expiry_month,
@@ -128,7 +162,7 @@ func (l *leaveTypeRepositoryImpl) GetByCompanyID(ctx context.Context, companyID
 			   created_at, updated_at
 		FROM leave_types
 		WHERE company_id = $1
-		ORDER BY name
+		ORDER BY name, id
 	`
 	rows, err := q.Query(ctx, query, companyID)
 	if err != nil {
@@ -144,7 +178,7 @@ func (l *leaveTypeRepositoryImpl) GetByCompanyID(ctx context.Context, companyID
 		if err := rows.Scan(
 			&lt.ID, &lt.CompanyID, &lt.Name, &lt.Code, &lt.Description, &lt.Color,
 			&lt.IsActive, &lt.RequiresApproval, &lt.RequiresAttachment, &lt.AttachmentRequiredAfterDays,
-			&lt.HasQuota, &lt.AccrualMethod,
+			&lt.HasQuota, &lt.AccrualMethod, &lt.AllowNegativeBalance,
 			&lt.DeductionType, &lt.AllowHalfDay,
 			&lt.MaxDaysPerRequest, &lt.MinNoticeDays, &lt.MaxAdvanceDays, &lt.AllowBackdate, &lt.BackdateMaxDays,
 			&lt.AllowRollover, &lt.MaxRolloverDays, &lt.RolloverExpiryMonth,
@@ -172,7 +206,7 @@ func (l *leaveTypeRepositoryImpl) GetByID(ctx context.Context, id string) (leave
 	query := `
 		SELECT id, company_id, name, code, description, color,
 			   is_active, requires_approval, requires_attachment, attachment_required_after_days,
-			   has_quota, accrual_method,
+			   has_quota, accrual_method, allow_negative_balance,
 			   deduction_type, allow_half_day,
 			   max_days_per_request, min_notice_days, max_advance_days, allow_backdate, backdate_max_days,
 			   allow_rollover, max_rollover_days, rollover_expiry_month,
@@ -188,7 +222,7 @@ func (l *leaveTypeRepositoryImpl) GetByID(ctx context.Context, id string) (leave
 	err := q.QueryRow(ctx, query, id).Scan(
 		&lt.ID, &lt.CompanyID, &lt.Name, &lt.Code, &lt.Description, &lt.Color,
 		&lt.IsActive, &lt.RequiresApproval, &lt.RequiresAttachment, &lt.AttachmentRequiredAfterDays,
-		&lt.HasQuota, &lt.AccrualMethod,
+		&lt.HasQuota, &lt.AccrualMethod, &lt.AllowNegativeBalance,
 		&lt.DeductionType, &lt.AllowHalfDay,
 		&lt.MaxDaysPerRequest, &lt.MinNoticeDays, &lt.MaxAdvanceDays, &lt.AllowBackdate, &lt.BackdateMaxDays,
 		&lt.AllowRollover, &lt.MaxRolloverDays, &lt.RolloverExpiryMonth,
@@ -216,7 +250,7 @@ func (l *leaveTypeRepositoryImpl) GetActiveByCompanyID(ctx context.Context, comp
 	query := `
 		SELECT id, company_id, name, code, description, color,
 			   is_active, requires_approval, requires_attachment, attachment_required_after_days,
-			   has_quota, accrual_method,
+			   has_quota, accrual_method, allow_negative_balance,
 			   deduction_type, allow_half_day,
 			   max_days_per_request, min_notice_days, max_advance_days, allow_backdate, backdate_max_days,
 			   allow_rollover, max_rollover_days, rollover_expiry_month,
@@ -224,7 +258,7 @@ func (l *leaveTypeRepositoryImpl) GetActiveByCompanyID(ctx context.Context, comp
 			   created_at, updated_at
 		FROM leave_types
 		WHERE company_id = $1 AND is_active = true
-		ORDER BY name
+		ORDER BY name, id
 	`
 
 	rows, err := q.Query(ctx, query, companyID)
@@ -242,7 +276,7 @@ func (l *leaveTypeRepositoryImpl) GetActiveByCompanyID(ctx context.Context, comp
 		err := rows.Scan(
 			&lt.ID, &lt.CompanyID, &lt.Name, &lt.Code, &lt.Description, &lt.Color,
 			&lt.IsActive, &lt.RequiresApproval, &lt.RequiresAttachment, &lt.AttachmentRequiredAfterDays,
-			&lt.HasQuota, &lt.AccrualMethod,
+			&lt.HasQuota, &lt.AccrualMethod, &lt.AllowNegativeBalance,
 			&lt.DeductionType, &lt.AllowHalfDay,
 			&lt.MaxDaysPerRequest, &lt.MinNoticeDays, &lt.MaxAdvanceDays, &lt.AllowBackdate, &lt.BackdateMaxDays,
 			&lt.AllowRollover, &lt.MaxRolloverDays, &lt.RolloverExpiryMonth,
@@ -327,6 +361,11 @@ func (l *leaveTypeRepositoryImpl) Update(ctx context.Context, leaveType leave.Up
 		args = append(args, *leaveType.AccrualMethod)
 		argIdx++
 	}
+	if leaveType.AllowNegativeBalance != nil {
+		updates = append(updates, fmt.Sprintf("allow_negative_balance = $%d", argIdx))
+		args = append(args, *leaveType.AllowNegativeBalance)
+		argIdx++
+	}
 
 	// Deduction Rules
 	if leaveType.DeductionType != nil {
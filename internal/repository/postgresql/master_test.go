@@ -0,0 +1,62 @@
+package postgresql
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestReassignReferencesLeavesJobHistory(t *testing.T) {
+	tests := []struct {
+		name     string
+		reassign func(ctx context.Context) error
+		want     []string // tables updated, in order
+	}{
+		{
+			name: "branch",
+			reassign: func(ctx context.Context) error {
+				return (&branchRepositoryImpl{}).ReassignReferences(ctx, "from", "to", "company-1")
+			},
+			want: []string{"UPDATE employees SET branch_id", "UPDATE users SET managed_branch_id"},
+		},
+		{
+			name: "grade",
+			reassign: func(ctx context.Context) error {
+				return (&gradeRepositoryImpl{}).ReassignReferences(ctx, "from", "to", "company-1")
+			},
+			want: []string{"UPDATE employees SET grade_id"},
+		},
+		{
+			name: "position",
+			reassign: func(ctx context.Context) error {
+				return (&positionRepositoryImpl{}).ReassignReferences(ctx, "from", "to", "company-1")
+			},
+			want: []string{"UPDATE employees SET position_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newFakeTx(fakeResult{tag: "UPDATE 2"}, fakeResult{tag: "UPDATE 1"})
+
+			if err := tt.reassign(tx.ctx()); err != nil {
+				t.Fatalf("ReassignReferences() error = %v", err)
+			}
+			if len(tx.calls) != len(tt.want) {
+				t.Fatalf("statements = %d, want %d", len(tx.calls), len(tt.want))
+			}
+			for i, call := range tx.calls {
+				sql := compactSQL(call.sql)
+				if !strings.HasPrefix(sql, tt.want[i]) {
+					t.Errorf("statement %d = %s, want %s", i, sql, tt.want[i])
+				}
+				if strings.Contains(sql, "employee_job_history") {
+					t.Errorf("statement %d rewrites job history: %s", i, sql)
+				}
+				if len(call.args) != 3 || call.args[0] != "from" || call.args[1] != "to" || call.args[2] != "company-1" {
+					t.Errorf("statement %d args = %v", i, call.args)
+				}
+			}
+		})
+	}
+}
@@ -31,8 +31,8 @@ func (r *notificationRepository) Create(ctx context.Context, n *notification.Not
 	}
 
 	query := `
-		INSERT INTO notifications (id, company_id, recipient_id, sender_id, type, title, message, data, is_read, created_at)
-		VALUES (uuidv7(), $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO notifications (id, company_id, recipient_id, sender_id, type, title, message, data, is_read, created_at, dedup_key)
+		VALUES (uuidv7(), $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
 	_, err = q.Exec(ctx, query,
@@ -46,6 +46,7 @@ func (r *notificationRepository) Create(ctx context.Context, n *notification.Not
 		dataJSON,
 		n.IsRead,
 		n.CreatedAt,
+		n.DedupKey,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create notification: %w", err)
@@ -108,6 +109,80 @@ func (r *notificationRepository) CreateBatch(ctx context.Context, notifications
 	return nil
 }
 
+// GetLatestUnreadByDedupKey retrieves the recipient's newest unread notification with a dedup key
+// created at or after since
+func (r *notificationRepository) GetLatestUnreadByDedupKey(ctx context.Context, recipientID string, dedupKey string, since time.Time) (*notification.Notification, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT id, company_id, recipient_id, sender_id, type, title, message, data, is_read, read_at, created_at
+		FROM notifications
+		WHERE recipient_id = $1 AND dedup_key = $2 AND is_read = false AND created_at >= $3
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var n notification.Notification
+	var dataJSON []byte
+	var notifType string
+
+	err := q.QueryRow(ctx, query, recipientID, dedupKey, since).Scan(
+		&n.ID,
+		&n.CompanyID,
+		&n.RecipientID,
+		&n.SenderID,
+		&notifType,
+		&n.Title,
+		&n.Message,
+		&dataJSON,
+		&n.IsRead,
+		&n.ReadAt,
+		&n.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notification.ErrNotificationNotFound
+		}
+		return nil, fmt.Errorf("failed to get notification by dedup key: %w", err)
+	}
+
+	n.Type = notification.NotificationType(notifType)
+	n.DedupKey = &dedupKey
+	if dataJSON != nil {
+		if err := json.Unmarshal(dataJSON, &n.Data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal notification data: %w", err)
+		}
+	}
+
+	return &n, nil
+}
+
+// Coalesce overwrites an unread notification with newer content and moves it to the top of the inbox
+func (r *notificationRepository) Coalesce(ctx context.Context, n *notification.Notification) error {
+	q := GetQuerier(ctx, r.db)
+
+	dataJSON, err := json.Marshal(n.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification data: %w", err)
+	}
+
+	query := `
+		UPDATE notifications
+		SET sender_id = $2, title = $3, message = $4, data = $5, created_at = $6
+		WHERE id = $1 AND is_read = false
+	`
+	result, err := q.Exec(ctx, query, n.ID, n.SenderID, n.Title, n.Message, dataJSON, n.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to coalesce notification: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return notification.ErrNotificationNotFound
+	}
+
+	return nil
+}
+
 // GetByID retrieves a notification by ID
 func (r *notificationRepository) GetByID(ctx context.Context, id string) (*notification.Notification, error) {
 	q := GetQuerier(ctx, r.db)
@@ -431,3 +506,214 @@ func (r *notificationRepository) IsNotificationEnabled(ctx context.Context, user
 
 	return enabled, nil
 }
+
+// ============= Templates =============
+
+// GetTemplate retrieves a company's template for a notification type
+func (r *notificationRepository) GetTemplate(ctx context.Context, companyID string, notifType notification.NotificationType) (*notification.NotificationTemplate, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT id, company_id, notification_type, title, message, created_at, updated_at
+		FROM notification_templates
+		WHERE company_id = $1 AND notification_type = $2
+	`
+
+	var t notification.NotificationTemplate
+	var nt string
+
+	err := q.QueryRow(ctx, query, companyID, string(notifType)).Scan(
+		&t.ID,
+		&t.CompanyID,
+		&nt,
+		&t.Title,
+		&t.Message,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notification.ErrTemplateNotFound
+		}
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	t.Type = notification.NotificationType(nt)
+	return &t, nil
+}
+
+// ListTemplates retrieves all custom templates of a company
+func (r *notificationRepository) ListTemplates(ctx context.Context, companyID string) ([]*notification.NotificationTemplate, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT id, company_id, notification_type, title, message, created_at, updated_at
+		FROM notification_templates
+		WHERE company_id = $1
+	`
+
+	rows, err := q.Query(ctx, query, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*notification.NotificationTemplate
+	for rows.Next() {
+		var t notification.NotificationTemplate
+		var nt string
+
+		if err := rows.Scan(
+			&t.ID,
+			&t.CompanyID,
+			&nt,
+			&t.Title,
+			&t.Message,
+			&t.CreatedAt,
+			&t.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan template: %w", err)
+		}
+
+		t.Type = notification.NotificationType(nt)
+		templates = append(templates, &t)
+	}
+
+	return templates, rows.Err()
+}
+
+// UpsertTemplate creates or replaces a company's template for a notification type
+func (r *notificationRepository) UpsertTemplate(ctx context.Context, tmpl *notification.NotificationTemplate) (*notification.NotificationTemplate, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		INSERT INTO notification_templates (company_id, notification_type, title, message)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (company_id, notification_type)
+		DO UPDATE SET title = $3, message = $4, updated_at = NOW()
+		RETURNING id, created_at, updated_at
+	`
+
+	saved := *tmpl
+	err := q.QueryRow(ctx, query,
+		tmpl.CompanyID,
+		string(tmpl.Type),
+		tmpl.Title,
+		tmpl.Message,
+	).Scan(&saved.ID, &saved.CreatedAt, &saved.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert template: %w", err)
+	}
+
+	return &saved, nil
+}
+
+// DeleteTemplate removes a company's template so the default text is used again
+func (r *notificationRepository) DeleteTemplate(ctx context.Context, companyID string, notifType notification.NotificationType) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `DELETE FROM notification_templates WHERE company_id = $1 AND notification_type = $2`
+	result, err := q.Exec(ctx, query, companyID, string(notifType))
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return notification.ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+// EnqueueOutbox records a notification for later delivery, inside the transaction carried by ctx if any
+func (r *notificationRepository) EnqueueOutbox(ctx context.Context, req notification.CreateNotificationRequest) error {
+	q := GetQuerier(ctx, r.db)
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+
+	query := `INSERT INTO notification_outbox (payload) VALUES ($1)`
+	if _, err := q.Exec(ctx, query, payload); err != nil {
+		return fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimOutbox picks up to limit pending entries that are due and pushes their next attempt out by
+// lease, so concurrent workers skip them and an entry abandoned mid-delivery is retried after it
+func (r *notificationRepository) ClaimOutbox(ctx context.Context, limit int, now time.Time, lease time.Duration) ([]*notification.OutboxEntry, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		UPDATE notification_outbox
+		SET attempts = attempts + 1, next_attempt_at = $3
+		WHERE id IN (
+			SELECT id FROM notification_outbox
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, payload, attempts, created_at
+	`
+
+	rows, err := q.Query(ctx, query, now, limit, now.Add(lease))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*notification.OutboxEntry
+	for rows.Next() {
+		var e notification.OutboxEntry
+		var payload []byte
+		if err := rows.Scan(&e.ID, &payload, &e.Attempts, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox entry: %w", err)
+		}
+		if err := json.Unmarshal(payload, &e.Request); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal outbox payload %s: %w", e.ID, err)
+		}
+		entries = append(entries, &e)
+	}
+
+	return entries, rows.Err()
+}
+
+// MarkOutboxSent marks an outbox entry as delivered
+func (r *notificationRepository) MarkOutboxSent(ctx context.Context, id string) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `UPDATE notification_outbox SET status = 'sent', sent_at = NOW(), last_error = NULL WHERE id = $1`
+	if _, err := q.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to mark outbox entry sent: %w", err)
+	}
+
+	return nil
+}
+
+// MarkOutboxRetry records a failed delivery and schedules the next attempt
+func (r *notificationRepository) MarkOutboxRetry(ctx context.Context, id string, lastErr string, nextAttemptAt time.Time) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `UPDATE notification_outbox SET last_error = $2, next_attempt_at = $3 WHERE id = $1`
+	if _, err := q.Exec(ctx, query, id, lastErr, nextAttemptAt); err != nil {
+		return fmt.Errorf("failed to reschedule outbox entry: %w", err)
+	}
+
+	return nil
+}
+
+// MarkOutboxFailed gives up on an outbox entry, keeping it with its last error for inspection
+func (r *notificationRepository) MarkOutboxFailed(ctx context.Context, id string, lastErr string) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `UPDATE notification_outbox SET status = 'failed', last_error = $2 WHERE id = $1`
+	if _, err := q.Exec(ctx, query, id, lastErr); err != nil {
+		return fmt.Errorf("failed to mark outbox entry failed: %w", err)
+	}
+
+	return nil
+}
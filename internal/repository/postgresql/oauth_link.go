@@ -0,0 +1,106 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/auth"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// OAuthLinkToken is a pending link between an OAuth identity and an existing account
+type OAuthLinkToken struct {
+	UserID          string
+	Provider        string
+	ProviderSubject string
+	ExpiresAt       time.Time
+	UsedAt          *time.Time
+}
+
+type OAuthLinkRepository interface {
+	CreateOAuthLinkToken(ctx context.Context, userID string, provider string, providerSubject string, expiresAt time.Time, ipAddress string) (token string, err error)
+	GetOAuthLinkToken(ctx context.Context, token string) (OAuthLinkToken, error)
+	MarkOAuthLinkTokenUsed(ctx context.Context, token string) error
+	InvalidateAllUserLinkTokens(ctx context.Context, userID string) error
+}
+
+type oauthLinkRepositoryImpl struct {
+	db *database.DB
+}
+
+// NewOAuthLinkRepository creates a new instance of OAuthLinkRepository.
+func NewOAuthLinkRepository(db *database.DB) OAuthLinkRepository {
+	return &oauthLinkRepositoryImpl{db: db}
+}
+
+// CreateOAuthLinkToken creates a pending link token for a user.
+// Token is generated by database using UUIDv7.
+func (r *oauthLinkRepositoryImpl) CreateOAuthLinkToken(ctx context.Context, userID string, provider string, providerSubject string, expiresAt time.Time, ipAddress string) (string, error) {
+	q := GetQuerier(ctx, r.db)
+	query := `
+		INSERT INTO oauth_link_tokens (user_id, provider, provider_subject, expires_at, ip_address)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING token
+	`
+	var token string
+	err := q.QueryRow(ctx, query, userID, provider, providerSubject, expiresAt, ipAddress).Scan(&token)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// GetOAuthLinkToken retrieves a pending link token's details.
+func (r *oauthLinkRepositoryImpl) GetOAuthLinkToken(ctx context.Context, token string) (OAuthLinkToken, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT user_id, provider, provider_subject, expires_at, used_at
+		FROM oauth_link_tokens
+		WHERE token = $1
+	`
+
+	var found OAuthLinkToken
+	err := q.QueryRow(ctx, query, token).Scan(
+		&found.UserID,
+		&found.Provider,
+		&found.ProviderSubject,
+		&found.ExpiresAt,
+		&found.UsedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return OAuthLinkToken{}, auth.ErrOAuthLinkTokenNotFound
+		}
+		return OAuthLinkToken{}, err
+	}
+
+	return found, nil
+}
+
+// MarkOAuthLinkTokenUsed marks a link token as used.
+func (r *oauthLinkRepositoryImpl) MarkOAuthLinkTokenUsed(ctx context.Context, token string) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		UPDATE oauth_link_tokens
+		SET used_at = NOW()
+		WHERE token = $1 AND used_at IS NULL
+	`
+	_, err := q.Exec(ctx, query, token)
+	return err
+}
+
+// InvalidateAllUserLinkTokens invalidates all pending link tokens for a user.
+func (r *oauthLinkRepositoryImpl) InvalidateAllUserLinkTokens(ctx context.Context, userID string) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		UPDATE oauth_link_tokens
+		SET used_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL
+	`
+	_, err := q.Exec(ctx, query, userID)
+	return err
+}
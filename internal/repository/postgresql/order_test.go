@@ -0,0 +1,110 @@
+package postgresql
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+)
+
+// orderByClause returns the last ORDER BY clause of sql, without any LIMIT
+func orderByClause(sql string) string {
+	sql = compactSQL(sql)
+	i := strings.LastIndex(sql, "ORDER BY ")
+	if i < 0 {
+		return ""
+	}
+	clause := sql[i+len("ORDER BY "):]
+	if j := strings.Index(clause, " LIMIT"); j >= 0 {
+		clause = clause[:j]
+	}
+	return clause
+}
+
+func TestListQueriesBreakTiesOnID(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		run  func(ctx context.Context) error
+		want string
+	}{
+		{name: "leave types", want: "name, id", run: func(ctx context.Context) error {
+			_, err := (&leaveTypeRepositoryImpl{}).GetByCompanyID(ctx, "company-1")
+			return err
+		}},
+		{name: "active leave types", want: "name, id", run: func(ctx context.Context) error {
+			_, err := (&leaveTypeRepositoryImpl{}).GetActiveByCompanyID(ctx, "company-1")
+			return err
+		}},
+		{name: "company quotas for a year", want: "leave_type_id, id", run: func(ctx context.Context) error {
+			_, err := (&leaveQuotaRepositoryImpl{}).GetByCompanyIDAndYear(ctx, "company-1", 2026)
+			return err
+		}},
+		{name: "employee quotas", want: "year DESC, leave_type_id, id", run: func(ctx context.Context) error {
+			_, err := (&leaveQuotaRepositoryImpl{}).GetByEmployee(ctx, "emp-1")
+			return err
+		}},
+		{name: "employee quotas for a year", want: "lt.name, lq.id", run: func(ctx context.Context) error {
+			_, err := (&leaveQuotaRepositoryImpl{}).GetByEmployeeYear(ctx, "emp-1", 2026)
+			return err
+		}},
+		{name: "schedule assignments", want: "start_date DESC, id DESC", run: func(ctx context.Context) error {
+			_, err := (&employeeScheduleAssignmentRepository{}).GetByEmployeeID(ctx, "emp-1")
+			return err
+		}},
+		{name: "schedule assignments in a range", want: "start_date, id", run: func(ctx context.Context) error {
+			_, err := (&employeeScheduleAssignmentRepository{}).GetScheduleAssignments(ctx, "emp-1", day, day.AddDate(0, 0, 6))
+			return err
+		}},
+		{name: "recurring leave requests", want: "lr.start_date ASC, lr.id", run: func(ctx context.Context) error {
+			_, err := (&leaveRequestRepositoryImpl{}).GetByRecurrenceID(ctx, "rec-1")
+			return err
+		}},
+		{name: "approved leave requests", want: "lr.start_date ASC, lr.id", run: func(ctx context.Context) error {
+			_, err := (&leaveRequestRepositoryImpl{}).GetApprovedByEmployeeID(ctx, "emp-1")
+			return err
+		}},
+		{name: "payroll components", want: "type, display_order, name, id", run: func(ctx context.Context) error {
+			_, err := (&payrollRepository{}).GetComponentsByCompanyID(ctx, "company-1", true)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newFakeTx(fakeResult{})
+			if err := tt.run(tx.ctx()); err != nil {
+				t.Fatalf("query error = %v", err)
+			}
+			if got := orderByClause(tx.calls[0].sql); got != tt.want {
+				t.Errorf("ORDER BY %s, want ORDER BY %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompanyLeaveRequestsOrderBy(t *testing.T) {
+	tests := []struct {
+		sortBy, sortOrder string
+		want              string
+	}{
+		{sortBy: "", sortOrder: "", want: "lr.submitted_at DESC, lr.id"},
+		{sortBy: "employee_name", sortOrder: "asc", want: "e.full_name ASC, lr.id"},
+		{sortBy: "start_date", sortOrder: "ASC", want: "lr.start_date ASC, lr.id"},
+		{sortBy: "end_date", sortOrder: "desc", want: "lr.end_date DESC, lr.id"},
+		{sortBy: "status", sortOrder: "asc", want: "lr.status ASC, lr.id"},
+		{sortBy: "id; DROP TABLE leave_requests", sortOrder: "asc", want: "lr.submitted_at ASC, lr.id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy+" "+tt.sortOrder, func(t *testing.T) {
+			got := companyLeaveRequestsOrderBy(leave.LeaveRequestFilter{SortBy: tt.sortBy, SortOrder: tt.sortOrder})
+			if got != tt.want {
+				t.Errorf("companyLeaveRequestsOrderBy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/payroll"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/pagination"
 	"github.com/jackc/pgx/v5"
 	"github.com/shopspring/decimal"
 )
@@ -99,16 +100,17 @@ func (r *payrollRepository) CreateComponent(ctx context.Context, component payro
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		INSERT INTO payroll_components (company_id, name, type, description, is_taxable, is_active)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, company_id, name, type, description, is_taxable, is_active, created_at, updated_at
+		INSERT INTO payroll_components (company_id, name, type, description, is_taxable, is_active, display_order, category)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, company_id, name, type, description, is_taxable, is_active, display_order, category, created_at, updated_at
 	`
 
 	var c payroll.PayrollComponent
 	err := q.QueryRow(ctx, query,
 		component.CompanyID, component.Name, component.Type, component.Description, component.IsTaxable, component.IsActive,
+		component.DisplayOrder, component.Category,
 	).Scan(
-		&c.ID, &c.CompanyID, &c.Name, &c.Type, &c.Description, &c.IsTaxable, &c.IsActive, &c.CreatedAt, &c.UpdatedAt,
+		&c.ID, &c.CompanyID, &c.Name, &c.Type, &c.Description, &c.IsTaxable, &c.IsActive, &c.DisplayOrder, &c.Category, &c.CreatedAt, &c.UpdatedAt,
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "uk_payroll_component_name") {
@@ -124,14 +126,14 @@ func (r *payrollRepository) GetComponentByID(ctx context.Context, id string, com
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		SELECT id, company_id, name, type, description, is_taxable, is_active, created_at, updated_at
+		SELECT id, company_id, name, type, description, is_taxable, is_active, display_order, category, created_at, updated_at
 		FROM payroll_components
 		WHERE id = $1 AND company_id = $2
 	`
 
 	var c payroll.PayrollComponent
 	err := q.QueryRow(ctx, query, id, companyID).Scan(
-		&c.ID, &c.CompanyID, &c.Name, &c.Type, &c.Description, &c.IsTaxable, &c.IsActive, &c.CreatedAt, &c.UpdatedAt,
+		&c.ID, &c.CompanyID, &c.Name, &c.Type, &c.Description, &c.IsTaxable, &c.IsActive, &c.DisplayOrder, &c.Category, &c.CreatedAt, &c.UpdatedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -147,14 +149,14 @@ func (r *payrollRepository) GetComponentsByCompanyID(ctx context.Context, compan
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		SELECT id, company_id, name, type, description, is_taxable, is_active, created_at, updated_at
+		SELECT id, company_id, name, type, description, is_taxable, is_active, display_order, category, created_at, updated_at
 		FROM payroll_components
 		WHERE company_id = $1
 	`
 	if activeOnly {
 		query += " AND is_active = true"
 	}
-	query += " ORDER BY type, name"
+	query += " ORDER BY type, display_order, name, id"
 
 	rows, err := q.Query(ctx, query, companyID)
 	if err != nil {
@@ -166,7 +168,7 @@ func (r *payrollRepository) GetComponentsByCompanyID(ctx context.Context, compan
 	for rows.Next() {
 		var c payroll.PayrollComponent
 		if err := rows.Scan(
-			&c.ID, &c.CompanyID, &c.Name, &c.Type, &c.Description, &c.IsTaxable, &c.IsActive, &c.CreatedAt, &c.UpdatedAt,
+			&c.ID, &c.CompanyID, &c.Name, &c.Type, &c.Description, &c.IsTaxable, &c.IsActive, &c.DisplayOrder, &c.Category, &c.CreatedAt, &c.UpdatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan payroll component: %w", err)
 		}
@@ -203,6 +205,20 @@ func (r *payrollRepository) UpdateComponent(ctx context.Context, companyID strin
 		args = append(args, *req.IsActive)
 		argIdx++
 	}
+	if req.DisplayOrder != nil {
+		setParts = append(setParts, fmt.Sprintf("display_order = $%d", argIdx))
+		args = append(args, *req.DisplayOrder)
+		argIdx++
+	}
+	if req.Category != nil {
+		setParts = append(setParts, fmt.Sprintf("category = $%d", argIdx))
+		if *req.Category == "" {
+			args = append(args, nil)
+		} else {
+			args = append(args, *req.Category)
+		}
+		argIdx++
+	}
 
 	query := fmt.Sprintf(`
 		UPDATE payroll_components
@@ -287,7 +303,8 @@ func (r *payrollRepository) GetEmployeeComponents(ctx context.Context, employeeI
 	query := `
 		SELECT epc.id, epc.employee_id, epc.payroll_component_id, epc.amount, 
 			   epc.effective_date, epc.end_date, epc.created_at, epc.updated_at,
-			   pc.name as component_name, pc.type as component_type
+			   pc.name as component_name, pc.type as component_type, pc.category as component_category,
+			   pc.display_order as component_display_order
 		FROM employee_payroll_components epc
 		JOIN payroll_components pc ON epc.payroll_component_id = pc.id
 		JOIN employees e ON epc.employee_id = e.id
@@ -296,7 +313,7 @@ func (r *payrollRepository) GetEmployeeComponents(ctx context.Context, employeeI
 	if activeOnly {
 		query += ` AND epc.effective_date <= CURRENT_DATE AND (epc.end_date IS NULL OR epc.end_date >= CURRENT_DATE)`
 	}
-	query += " ORDER BY pc.type, pc.name"
+	query += " ORDER BY pc.type, pc.display_order, pc.name, epc.id"
 
 	rows, err := q.Query(ctx, query, employeeID, companyID)
 	if err != nil {
@@ -310,7 +327,7 @@ func (r *payrollRepository) GetEmployeeComponents(ctx context.Context, employeeI
 		if err := rows.Scan(
 			&a.ID, &a.EmployeeID, &a.PayrollComponentID, &a.Amount,
 			&a.EffectiveDate, &a.EndDate, &a.CreatedAt, &a.UpdatedAt,
-			&a.ComponentName, &a.ComponentType,
+			&a.ComponentName, &a.ComponentType, &a.ComponentCategory, &a.ComponentDisplayOrder,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan employee component: %w", err)
 		}
@@ -326,7 +343,8 @@ func (r *payrollRepository) GetEmployeeComponentByID(ctx context.Context, id str
 	query := `
 		SELECT epc.id, epc.employee_id, epc.payroll_component_id, epc.amount, 
 			   epc.effective_date, epc.end_date, epc.created_at, epc.updated_at,
-			   pc.name as component_name, pc.type as component_type
+			   pc.name as component_name, pc.type as component_type, pc.category as component_category,
+			   pc.display_order as component_display_order
 		FROM employee_payroll_components epc
 		JOIN payroll_components pc ON epc.payroll_component_id = pc.id
 		JOIN employees e ON epc.employee_id = e.id
@@ -337,7 +355,7 @@ func (r *payrollRepository) GetEmployeeComponentByID(ctx context.Context, id str
 	err := q.QueryRow(ctx, query, id, companyID).Scan(
 		&a.ID, &a.EmployeeID, &a.PayrollComponentID, &a.Amount,
 		&a.EffectiveDate, &a.EndDate, &a.CreatedAt, &a.UpdatedAt,
-		&a.ComponentName, &a.ComponentType,
+		&a.ComponentName, &a.ComponentType, &a.ComponentCategory, &a.ComponentDisplayOrder,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -427,38 +445,40 @@ func (r *payrollRepository) CreatePayrollRecord(ctx context.Context, record payr
 
 	allowancesJSON, _ := json.Marshal(record.AllowancesDetail)
 	deductionsJSON, _ := json.Marshal(record.DeductionsDetail)
+	linesJSON, _ := json.Marshal(record.ComponentLines)
 
 	query := `
 		INSERT INTO payroll_records (
 			employee_id, company_id, period_month, period_year, base_salary,
-			total_allowances, total_deductions, allowances_detail, deductions_detail,
+			total_allowances, total_deductions, allowances_detail, deductions_detail, component_lines,
 			total_work_days, total_late_minutes, late_deduction_amount,
 			total_early_leave_minutes, early_leave_deduction_amount,
-			total_overtime_minutes, overtime_amount, gross_salary, net_salary, status, notes
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+			total_overtime_minutes, overtime_amount, gross_salary, net_salary, status, notes, payroll_run_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
 		RETURNING id, employee_id, company_id, period_month, period_year, base_salary,
-			total_allowances, total_deductions, allowances_detail, deductions_detail,
+			total_allowances, total_deductions, allowances_detail, deductions_detail, component_lines,
 			total_work_days, total_late_minutes, late_deduction_amount,
 			total_early_leave_minutes, early_leave_deduction_amount,
 			total_overtime_minutes, overtime_amount, gross_salary, net_salary,
-			status, paid_at, paid_by, notes, created_at, updated_at
+			status, paid_at, paid_by, notes, payroll_run_id, created_at, updated_at, version
 	`
 
 	var rec payroll.PayrollRecord
-	var allowancesBytes, deductionsBytes []byte
+	var allowancesBytes, deductionsBytes, linesBytes []byte
 	err := q.QueryRow(ctx, query,
 		record.EmployeeID, record.CompanyID, record.PeriodMonth, record.PeriodYear, record.BaseSalary,
-		record.TotalAllowances, record.TotalDeductions, allowancesJSON, deductionsJSON,
+		record.TotalAllowances, record.TotalDeductions, allowancesJSON, deductionsJSON, linesJSON,
 		record.TotalWorkDays, record.TotalLateMinutes, record.LateDeductionAmount,
 		record.TotalEarlyLeaveMinutes, record.EarlyLeaveDeductionAmount,
 		record.TotalOvertimeMinutes, record.OvertimeAmount, record.GrossSalary, record.NetSalary, record.Status, record.Notes,
+		record.PayrollRunID,
 	).Scan(
 		&rec.ID, &rec.EmployeeID, &rec.CompanyID, &rec.PeriodMonth, &rec.PeriodYear, &rec.BaseSalary,
-		&rec.TotalAllowances, &rec.TotalDeductions, &allowancesBytes, &deductionsBytes,
+		&rec.TotalAllowances, &rec.TotalDeductions, &allowancesBytes, &deductionsBytes, &linesBytes,
 		&rec.TotalWorkDays, &rec.TotalLateMinutes, &rec.LateDeductionAmount,
 		&rec.TotalEarlyLeaveMinutes, &rec.EarlyLeaveDeductionAmount,
 		&rec.TotalOvertimeMinutes, &rec.OvertimeAmount, &rec.GrossSalary, &rec.NetSalary,
-		&rec.Status, &rec.PaidAt, &rec.PaidBy, &rec.Notes, &rec.CreatedAt, &rec.UpdatedAt,
+		&rec.Status, &rec.PaidAt, &rec.PaidBy, &rec.Notes, &rec.PayrollRunID, &rec.CreatedAt, &rec.UpdatedAt, &rec.Version,
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "uk_employee_period") {
@@ -469,6 +489,7 @@ func (r *payrollRepository) CreatePayrollRecord(ctx context.Context, record payr
 
 	_ = json.Unmarshal(allowancesBytes, &rec.AllowancesDetail)
 	_ = json.Unmarshal(deductionsBytes, &rec.DeductionsDetail)
+	_ = json.Unmarshal(linesBytes, &rec.ComponentLines)
 
 	return rec, nil
 }
@@ -478,11 +499,11 @@ func (r *payrollRepository) GetPayrollRecordByID(ctx context.Context, id string,
 
 	query := `
 		SELECT pr.id, pr.employee_id, pr.company_id, pr.period_month, pr.period_year, pr.base_salary,
-			   pr.total_allowances, pr.total_deductions, pr.allowances_detail, pr.deductions_detail,
+			   pr.total_allowances, pr.total_deductions, pr.allowances_detail, pr.deductions_detail, pr.component_lines,
 			   pr.total_work_days, pr.total_late_minutes, pr.late_deduction_amount,
 			   pr.total_early_leave_minutes, pr.early_leave_deduction_amount,
 			   pr.total_overtime_minutes, pr.overtime_amount, pr.gross_salary, pr.net_salary,
-			   pr.status, pr.paid_at, pr.paid_by, pr.notes, pr.created_at, pr.updated_at,
+			   pr.status, pr.paid_at, pr.paid_by, pr.notes, pr.created_at, pr.updated_at, pr.version,
 			   e.full_name as employee_name, e.employee_code, p.name as position_name, b.name as branch_name
 		FROM payroll_records pr
 		JOIN employees e ON pr.employee_id = e.id
@@ -492,14 +513,14 @@ func (r *payrollRepository) GetPayrollRecordByID(ctx context.Context, id string,
 	`
 
 	var rec payroll.PayrollRecord
-	var allowancesBytes, deductionsBytes []byte
+	var allowancesBytes, deductionsBytes, linesBytes []byte
 	err := q.QueryRow(ctx, query, id, companyID).Scan(
 		&rec.ID, &rec.EmployeeID, &rec.CompanyID, &rec.PeriodMonth, &rec.PeriodYear, &rec.BaseSalary,
-		&rec.TotalAllowances, &rec.TotalDeductions, &allowancesBytes, &deductionsBytes,
+		&rec.TotalAllowances, &rec.TotalDeductions, &allowancesBytes, &deductionsBytes, &linesBytes,
 		&rec.TotalWorkDays, &rec.TotalLateMinutes, &rec.LateDeductionAmount,
 		&rec.TotalEarlyLeaveMinutes, &rec.EarlyLeaveDeductionAmount,
 		&rec.TotalOvertimeMinutes, &rec.OvertimeAmount, &rec.GrossSalary, &rec.NetSalary,
-		&rec.Status, &rec.PaidAt, &rec.PaidBy, &rec.Notes, &rec.CreatedAt, &rec.UpdatedAt,
+		&rec.Status, &rec.PaidAt, &rec.PaidBy, &rec.Notes, &rec.CreatedAt, &rec.UpdatedAt, &rec.Version,
 		&rec.EmployeeName, &rec.EmployeeCode, &rec.PositionName, &rec.BranchName,
 	)
 	if err != nil {
@@ -511,6 +532,7 @@ func (r *payrollRepository) GetPayrollRecordByID(ctx context.Context, id string,
 
 	_ = json.Unmarshal(allowancesBytes, &rec.AllowancesDetail)
 	_ = json.Unmarshal(deductionsBytes, &rec.DeductionsDetail)
+	_ = json.Unmarshal(linesBytes, &rec.ComponentLines)
 
 	return rec, nil
 }
@@ -520,25 +542,25 @@ func (r *payrollRepository) GetPayrollRecordByEmployeePeriod(ctx context.Context
 
 	query := `
 		SELECT pr.id, pr.employee_id, pr.company_id, pr.period_month, pr.period_year, pr.base_salary,
-			   pr.total_allowances, pr.total_deductions, pr.allowances_detail, pr.deductions_detail,
+			   pr.total_allowances, pr.total_deductions, pr.allowances_detail, pr.deductions_detail, pr.component_lines,
 			   pr.total_work_days, pr.total_late_minutes, pr.late_deduction_amount,
 			   pr.total_early_leave_minutes, pr.early_leave_deduction_amount,
 			   pr.total_overtime_minutes, pr.overtime_amount, pr.gross_salary, pr.net_salary,
-			   pr.status, pr.paid_at, pr.paid_by, pr.notes, pr.created_at, pr.updated_at
+			   pr.status, pr.paid_at, pr.paid_by, pr.notes, pr.created_at, pr.updated_at, pr.version
 		FROM payroll_records pr
 		JOIN employees e ON pr.employee_id = e.id
 		WHERE pr.employee_id = $1 AND pr.period_month = $2 AND pr.period_year = $3 AND e.company_id = $4
 	`
 
 	var rec payroll.PayrollRecord
-	var allowancesBytes, deductionsBytes []byte
+	var allowancesBytes, deductionsBytes, linesBytes []byte
 	err := q.QueryRow(ctx, query, employeeID, month, year, companyID).Scan(
 		&rec.ID, &rec.EmployeeID, &rec.CompanyID, &rec.PeriodMonth, &rec.PeriodYear, &rec.BaseSalary,
-		&rec.TotalAllowances, &rec.TotalDeductions, &allowancesBytes, &deductionsBytes,
+		&rec.TotalAllowances, &rec.TotalDeductions, &allowancesBytes, &deductionsBytes, &linesBytes,
 		&rec.TotalWorkDays, &rec.TotalLateMinutes, &rec.LateDeductionAmount,
 		&rec.TotalEarlyLeaveMinutes, &rec.EarlyLeaveDeductionAmount,
 		&rec.TotalOvertimeMinutes, &rec.OvertimeAmount, &rec.GrossSalary, &rec.NetSalary,
-		&rec.Status, &rec.PaidAt, &rec.PaidBy, &rec.Notes, &rec.CreatedAt, &rec.UpdatedAt,
+		&rec.Status, &rec.PaidAt, &rec.PaidBy, &rec.Notes, &rec.CreatedAt, &rec.UpdatedAt, &rec.Version,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -549,6 +571,7 @@ func (r *payrollRepository) GetPayrollRecordByEmployeePeriod(ctx context.Context
 
 	_ = json.Unmarshal(allowancesBytes, &rec.AllowancesDetail)
 	_ = json.Unmarshal(deductionsBytes, &rec.DeductionsDetail)
+	_ = json.Unmarshal(linesBytes, &rec.ComponentLines)
 
 	return rec, nil
 }
@@ -613,28 +636,22 @@ func (r *payrollRepository) ListPayrollRecords(ctx context.Context, companyID st
 	}
 
 	// Pagination
-	if filter.Limit <= 0 {
-		filter.Limit = 20
-	}
-	if filter.Page <= 0 {
-		filter.Page = 1
-	}
-	offset := (filter.Page - 1) * filter.Limit
+	offset, limit := pagination.Paginate(filter.Page, filter.Limit)
 
 	selectQuery := fmt.Sprintf(`
 		SELECT pr.id, pr.employee_id, pr.company_id, pr.period_month, pr.period_year, pr.base_salary,
-			   pr.total_allowances, pr.total_deductions, pr.allowances_detail, pr.deductions_detail,
+			   pr.total_allowances, pr.total_deductions, pr.allowances_detail, pr.deductions_detail, pr.component_lines,
 			   pr.total_work_days, pr.total_late_minutes, pr.late_deduction_amount,
 			   pr.total_early_leave_minutes, pr.early_leave_deduction_amount,
 			   pr.total_overtime_minutes, pr.overtime_amount, pr.gross_salary, pr.net_salary,
-			   pr.status, pr.paid_at, pr.paid_by, pr.notes, pr.created_at, pr.updated_at,
+			   pr.status, pr.paid_at, pr.paid_by, pr.notes, pr.created_at, pr.updated_at, pr.version,
 			   e.full_name as employee_name, e.employee_code, p.name as position_name, b.name as branch_name
 		%s
-		ORDER BY %s %s
+		ORDER BY %s %s, pr.id
 		LIMIT $%d OFFSET $%d
 	`, baseQuery, sortColumn, sortOrder, argIdx, argIdx+1)
 
-	args = append(args, filter.Limit, offset)
+	args = append(args, limit, offset)
 
 	rows, err := q.Query(ctx, selectQuery, args...)
 	if err != nil {
@@ -645,20 +662,21 @@ func (r *payrollRepository) ListPayrollRecords(ctx context.Context, companyID st
 	var records []payroll.PayrollRecord
 	for rows.Next() {
 		var rec payroll.PayrollRecord
-		var allowancesBytes, deductionsBytes []byte
+		var allowancesBytes, deductionsBytes, linesBytes []byte
 		if err := rows.Scan(
 			&rec.ID, &rec.EmployeeID, &rec.CompanyID, &rec.PeriodMonth, &rec.PeriodYear, &rec.BaseSalary,
-			&rec.TotalAllowances, &rec.TotalDeductions, &allowancesBytes, &deductionsBytes,
+			&rec.TotalAllowances, &rec.TotalDeductions, &allowancesBytes, &deductionsBytes, &linesBytes,
 			&rec.TotalWorkDays, &rec.TotalLateMinutes, &rec.LateDeductionAmount,
 			&rec.TotalEarlyLeaveMinutes, &rec.EarlyLeaveDeductionAmount,
 			&rec.TotalOvertimeMinutes, &rec.OvertimeAmount, &rec.GrossSalary, &rec.NetSalary,
-			&rec.Status, &rec.PaidAt, &rec.PaidBy, &rec.Notes, &rec.CreatedAt, &rec.UpdatedAt,
+			&rec.Status, &rec.PaidAt, &rec.PaidBy, &rec.Notes, &rec.CreatedAt, &rec.UpdatedAt, &rec.Version,
 			&rec.EmployeeName, &rec.EmployeeCode, &rec.PositionName, &rec.BranchName,
 		); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan payroll record: %w", err)
 		}
 		_ = json.Unmarshal(allowancesBytes, &rec.AllowancesDetail)
 		_ = json.Unmarshal(deductionsBytes, &rec.DeductionsDetail)
+		_ = json.Unmarshal(linesBytes, &rec.ComponentLines)
 		records = append(records, rec)
 	}
 
@@ -681,7 +699,7 @@ func (r *payrollRepository) UpdatePayrollRecord(ctx context.Context, companyID s
 		return payroll.ErrPayrollRecordAlreadyPaid
 	}
 
-	setParts := []string{"updated_at = NOW()"}
+	setParts := []string{"updated_at = NOW()", "version = version + 1"}
 	args := []interface{}{req.ID, companyID}
 	argIdx := 3
 
@@ -723,18 +741,23 @@ func (r *payrollRepository) UpdatePayrollRecord(ctx context.Context, companyID s
 			- COALESCE(total_deductions, 0) - COALESCE(late_deduction_amount, 0) - COALESCE(early_leave_deduction_amount, 0)
 	`)
 
+	// A missing version binds NULL, which matches no row
+	where := fmt.Sprintf("id = $1 AND company_id = $2 AND version = $%d", argIdx)
+	args = append(args, req.Version)
+
 	query := fmt.Sprintf(`
 		UPDATE payroll_records
 		SET %s
-		WHERE id = $1 AND company_id = $2
+		WHERE %s
 		RETURNING id
-	`, strings.Join(setParts, ", "))
+	`, strings.Join(setParts, ", "), where)
 
 	var updatedID string
 	err = q.QueryRow(ctx, query, args...).Scan(&updatedID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return payroll.ErrPayrollRecordNotFound
+			// The record exists, as checked above, so it changed since it was read
+			return payroll.ErrConcurrentModification
 		}
 		return fmt.Errorf("failed to update payroll record: %w", err)
 	}
@@ -742,12 +765,50 @@ func (r *payrollRepository) UpdatePayrollRecord(ctx context.Context, companyID s
 	return nil
 }
 
+// UpdatePayrollRecordAttendance overwrites a record's attendance figures and salary totals.
+// Paid records are left untouched and reported as ErrPayrollRecordAlreadyPaid.
+func (r *payrollRepository) UpdatePayrollRecordAttendance(ctx context.Context, record payroll.PayrollRecord) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		UPDATE payroll_records
+		SET total_work_days = $3,
+			total_late_minutes = $4,
+			late_deduction_amount = $5,
+			total_early_leave_minutes = $6,
+			early_leave_deduction_amount = $7,
+			total_overtime_minutes = $8,
+			overtime_amount = $9,
+			gross_salary = $10,
+			net_salary = $11,
+			updated_at = NOW(),
+			version = version + 1
+		WHERE id = $1 AND company_id = $2 AND status != $12
+	`
+
+	result, err := q.Exec(ctx, query,
+		record.ID, record.CompanyID,
+		record.TotalWorkDays, record.TotalLateMinutes, record.LateDeductionAmount,
+		record.TotalEarlyLeaveMinutes, record.EarlyLeaveDeductionAmount,
+		record.TotalOvertimeMinutes, record.OvertimeAmount,
+		record.GrossSalary, record.NetSalary, payroll.PayrollStatusPaid,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update payroll record attendance: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return payroll.ErrPayrollRecordAlreadyPaid
+	}
+
+	return nil
+}
+
 func (r *payrollRepository) FinalizePayrollRecords(ctx context.Context, ids []string, paidBy string, companyID string) error {
 	q := GetQuerier(ctx, r.db)
 
 	query := `
 		UPDATE payroll_records
-		SET status = 'paid', paid_at = NOW(), paid_by = $1, updated_at = NOW()
+		SET status = 'paid', paid_at = NOW(), paid_by = $1, updated_at = NOW(), version = version + 1
 		WHERE id = ANY($2) AND company_id = $3 AND status = 'draft'
 	`
 
@@ -791,6 +852,205 @@ func (r *payrollRepository) DeletePayrollRecord(ctx context.Context, id string,
 
 // ========== AGGREGATIONS ==========
 
+// ========== PAYROLL RUNS ==========
+
+const payrollRunColumns = `id, company_id, period_month, period_year, status, total_employees, processed_employees,
+	created_records, last_error, created_by, started_at, completed_at, created_at, updated_at`
+
+func scanPayrollRun(row pgx.Row) (payroll.PayrollRun, error) {
+	var run payroll.PayrollRun
+	err := row.Scan(
+		&run.ID, &run.CompanyID, &run.PeriodMonth, &run.PeriodYear, &run.Status, &run.TotalEmployees, &run.ProcessedEmployees,
+		&run.CreatedRecords, &run.LastError, &run.CreatedBy, &run.StartedAt, &run.CompletedAt, &run.CreatedAt, &run.UpdatedAt,
+	)
+	return run, err
+}
+
+// GetOpenPayrollRunForUpdate locks the period's unfinished run. Must be called inside a transaction.
+func (r *payrollRepository) GetOpenPayrollRunForUpdate(ctx context.Context, companyID string, month, year int) (payroll.PayrollRun, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `SELECT ` + payrollRunColumns + `
+		FROM payroll_runs
+		WHERE company_id = $1 AND period_month = $2 AND period_year = $3 AND status <> $4
+		FOR UPDATE`
+
+	run, err := scanPayrollRun(q.QueryRow(ctx, query, companyID, month, year, payroll.PayrollRunStatusCompleted))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return payroll.PayrollRun{}, payroll.ErrPayrollRunNotFound
+		}
+		return payroll.PayrollRun{}, fmt.Errorf("failed to get open payroll run: %w", err)
+	}
+
+	return run, nil
+}
+
+func (r *payrollRepository) CreatePayrollRun(ctx context.Context, run payroll.PayrollRun) (payroll.PayrollRun, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		INSERT INTO payroll_runs (company_id, period_month, period_year, status, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + payrollRunColumns
+
+	created, err := scanPayrollRun(q.QueryRow(ctx, query, run.CompanyID, run.PeriodMonth, run.PeriodYear, payroll.PayrollRunStatusRunning, run.CreatedBy))
+	if err != nil {
+		if strings.Contains(err.Error(), "uk_payroll_runs_open_period") {
+			return payroll.PayrollRun{}, payroll.ErrPayrollRunInProgress
+		}
+		return payroll.PayrollRun{}, fmt.Errorf("failed to create payroll run: %w", err)
+	}
+
+	return created, nil
+}
+
+// RestartPayrollRun puts a failed or abandoned run back to running, keeping its counters
+func (r *payrollRepository) RestartPayrollRun(ctx context.Context, id string, companyID string) (payroll.PayrollRun, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		UPDATE payroll_runs
+		SET status = $3, last_error = NULL, updated_at = NOW()
+		WHERE id = $1 AND company_id = $2
+		RETURNING ` + payrollRunColumns
+
+	run, err := scanPayrollRun(q.QueryRow(ctx, query, id, companyID, payroll.PayrollRunStatusRunning))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return payroll.PayrollRun{}, payroll.ErrPayrollRunNotFound
+		}
+		return payroll.PayrollRun{}, fmt.Errorf("failed to restart payroll run: %w", err)
+	}
+
+	return run, nil
+}
+
+// UpdatePayrollRunProgress saves the counters. It also refreshes updated_at, which tells a later
+// generation that the run is still alive.
+func (r *payrollRepository) UpdatePayrollRunProgress(ctx context.Context, run payroll.PayrollRun) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		UPDATE payroll_runs
+		SET total_employees = $3, processed_employees = $4, created_records = $5, updated_at = NOW()
+		WHERE id = $1 AND company_id = $2
+	`
+
+	if _, err := q.Exec(ctx, query, run.ID, run.CompanyID, run.TotalEmployees, run.ProcessedEmployees, run.CreatedRecords); err != nil {
+		return fmt.Errorf("failed to update payroll run progress: %w", err)
+	}
+
+	return nil
+}
+
+// FinishPayrollRun saves the final counters with the completed or failed status
+func (r *payrollRepository) FinishPayrollRun(ctx context.Context, run payroll.PayrollRun) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		UPDATE payroll_runs
+		SET status = $3, total_employees = $4, processed_employees = $5, created_records = $6, last_error = $7,
+			completed_at = CASE WHEN $3 = 'completed' THEN NOW() ELSE NULL END,
+			updated_at = NOW()
+		WHERE id = $1 AND company_id = $2
+	`
+
+	if _, err := q.Exec(ctx, query,
+		run.ID, run.CompanyID, string(run.Status), run.TotalEmployees, run.ProcessedEmployees, run.CreatedRecords, run.LastError,
+	); err != nil {
+		return fmt.Errorf("failed to finish payroll run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *payrollRepository) GetPayrollRunByID(ctx context.Context, id string, companyID string) (payroll.PayrollRun, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `SELECT ` + payrollRunColumns + ` FROM payroll_runs WHERE id = $1 AND company_id = $2`
+
+	run, err := scanPayrollRun(q.QueryRow(ctx, query, id, companyID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return payroll.PayrollRun{}, payroll.ErrPayrollRunNotFound
+		}
+		return payroll.PayrollRun{}, fmt.Errorf("failed to get payroll run: %w", err)
+	}
+
+	return run, nil
+}
+
+func (r *payrollRepository) ListPayrollRuns(ctx context.Context, companyID string, filter payroll.PayrollRunFilter) ([]payroll.PayrollRun, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `SELECT ` + payrollRunColumns + ` FROM payroll_runs WHERE company_id = $1`
+	args := []interface{}{companyID}
+	argIdx := 2
+
+	if filter.PeriodMonth != nil {
+		query += fmt.Sprintf(" AND period_month = $%d", argIdx)
+		args = append(args, *filter.PeriodMonth)
+		argIdx++
+	}
+	if filter.PeriodYear != nil {
+		query += fmt.Sprintf(" AND period_year = $%d", argIdx)
+		args = append(args, *filter.PeriodYear)
+		argIdx++
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT 100"
+
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payroll runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []payroll.PayrollRun
+	for rows.Next() {
+		run, err := scanPayrollRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payroll run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+func (r *payrollRepository) GetBankTransferLines(ctx context.Context, companyID string, month, year int) ([]payroll.BankTransferLine, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT pr.id, pr.employee_id, e.full_name, e.employee_code,
+			   e.bank_name, e.bank_account_holder_name, e.bank_account_number, pr.net_salary
+		FROM payroll_records pr
+		JOIN employees e ON pr.employee_id = e.id
+		WHERE pr.company_id = $1 AND pr.period_month = $2 AND pr.period_year = $3 AND pr.status = $4
+		ORDER BY e.employee_code, pr.id
+	`
+
+	rows, err := q.Query(ctx, query, companyID, month, year, payroll.PayrollStatusPaid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank transfer lines: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []payroll.BankTransferLine
+	for rows.Next() {
+		var l payroll.BankTransferLine
+		if err := rows.Scan(
+			&l.RecordID, &l.EmployeeID, &l.EmployeeName, &l.EmployeeCode,
+			&l.BankName, &l.AccountHolderName, &l.AccountNumber, &l.NetSalary,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan bank transfer line: %w", err)
+		}
+		lines = append(lines, l)
+	}
+
+	return lines, rows.Err()
+}
+
 func (r *payrollRepository) GetAttendanceSummary(ctx context.Context, companyID string, month, year int, employeeIDs []string) ([]payroll.AttendanceSummary, error) {
 	q := GetQuerier(ctx, r.db)
 
@@ -802,7 +1062,8 @@ func (r *payrollRepository) GetAttendanceSummary(ctx context.Context, companyID
 			COUNT(*) as total_work_days,
 			COALESCE(SUM(late_minutes), 0) as total_late_minutes,
 			COALESCE(SUM(early_leave_minutes), 0) as total_early_leave_minutes,
-			COALESCE(SUM(overtime_minutes), 0) as total_overtime_minutes
+			COALESCE(SUM(overtime_minutes), 0) as total_overtime_minutes,
+			COALESCE(SUM(overtime_minutes * overtime_multiplier), 0) as payable_overtime_minutes
 		FROM attendances
 		WHERE company_id = $1 
 			AND EXTRACT(MONTH FROM date) = $2
@@ -830,7 +1091,7 @@ func (r *payrollRepository) GetAttendanceSummary(ctx context.Context, companyID
 		var s payroll.AttendanceSummary
 		if err := rows.Scan(
 			&s.EmployeeID, &s.TotalWorkDays, &s.TotalLateMinutes,
-			&s.TotalEarlyLeaveMinutes, &s.TotalOvertimeMinutes,
+			&s.TotalEarlyLeaveMinutes, &s.TotalOvertimeMinutes, &s.PayableOvertimeMinutes,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan attendance summary: %w", err)
 		}
@@ -0,0 +1,50 @@
+package postgresql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/payroll"
+)
+
+func TestPayrollRepositoryUpdateRecordAlwaysChecksVersion(t *testing.T) {
+	notes := "corrected allowance"
+	loaded := 4
+
+	tests := []struct {
+		name    string
+		version *int
+		updated bool
+		wantErr error
+	}{
+		{"current version", &loaded, true, nil},
+		{"stale version", &loaded, false, payroll.ErrConcurrentModification},
+		// Binds NULL, which matches no row, so the edit cannot bypass the check
+		{"no version", nil, false, payroll.ErrConcurrentModification},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := fakeResult{}
+			if tt.updated {
+				result.rows = [][]any{{"rec-1"}}
+			}
+			tx := newFakeTx(fakeResult{rows: [][]any{{"draft"}}}, result)
+			repo := &payrollRepository{}
+
+			err := repo.UpdatePayrollRecord(tx.ctx(), "company-1", payroll.UpdatePayrollRecordRequest{ID: "rec-1", Notes: &notes, Version: tt.version})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdatePayrollRecord() error = %v, want %v", err, tt.wantErr)
+			}
+
+			call := tx.calls[1]
+			if sql := compactSQL(call.sql); !strings.Contains(sql, "WHERE id = $1 AND company_id = $2 AND version = $4") {
+				t.Errorf("query = %s, want it guarded by the version", sql)
+			}
+			if got := call.args[3]; got != any(tt.version) {
+				t.Errorf("version arg = %v, want %v", got, tt.version)
+			}
+		})
+	}
+}
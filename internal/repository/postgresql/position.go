@@ -146,3 +146,44 @@ func (r *positionRepositoryImpl) Delete(ctx context.Context, id string, companyI
 
 	return nil
 }
+
+// CountReferences implements position.PositionRepository.
+// Counts employees and job history rows that still point to the position.
+func (r *positionRepositoryImpl) CountReferences(ctx context.Context, id string, companyID string) (int, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM employees WHERE position_id = $1 AND company_id = $2)
+			+
+			(SELECT COUNT(*)
+			 FROM employee_job_history h
+			 JOIN employees e ON e.id = h.employee_id
+			 WHERE h.position_id = $1 AND e.company_id = $2)
+	`
+
+	var count int
+	if err := q.QueryRow(ctx, query, id, companyID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count position references: %w", err)
+	}
+
+	return count, nil
+}
+
+// ReassignReferences implements position.PositionRepository.
+// Moves employees from one position to another; no manager setting refers to a position. Job history
+// keeps recording the position employees actually held, so a position that appears in it stays in use.
+func (r *positionRepositoryImpl) ReassignReferences(ctx context.Context, fromID, toID string, companyID string) error {
+	q := GetQuerier(ctx, r.db)
+
+	employeesQuery := `
+		UPDATE employees
+		SET position_id = $2, updated_at = NOW()
+		WHERE position_id = $1 AND company_id = $3
+	`
+	if _, err := q.Exec(ctx, employeesQuery, fromID, toID, companyID); err != nil {
+		return fmt.Errorf("failed to reassign employees: %w", err)
+	}
+
+	return nil
+}
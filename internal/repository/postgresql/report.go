@@ -10,9 +10,9 @@ import (
 )
 
 type ReportRepository interface {
-	GetMonthlyAttendanceReport(ctx context.Context, companyID string, month, year int) ([]report.MonthlyAttendanceEmployee, error)
+	GetMonthlyAttendanceReport(ctx context.Context, companyID string, branchID *string, month, year int) ([]report.MonthlyAttendanceEmployee, error)
 	GetPayrollSummaryReport(ctx context.Context, companyID string, month, year int) ([]report.PayrollSummaryRow, error)
-	GetLeaveBalanceReport(ctx context.Context, companyID string, year int) ([]report.LeaveBalanceRow, error)
+	GetLeaveBalanceReport(ctx context.Context, companyID string, branchID *string, year int) ([]report.LeaveBalanceRow, error)
 	GetNewHireReport(ctx context.Context, companyID, startDate, endDate string) ([]report.NewHireRow, error)
 }
 
@@ -24,8 +24,9 @@ func NewReportRepository(db *database.DB) ReportRepository {
 	return &reportRepositoryImpl{db: db}
 }
 
-// GetMonthlyAttendanceReport retrieves attendance data for all employees in a company for a specific month
-func (r *reportRepositoryImpl) GetMonthlyAttendanceReport(ctx context.Context, companyID string, month, year int) ([]report.MonthlyAttendanceEmployee, error) {
+// GetMonthlyAttendanceReport retrieves attendance data for all employees in a company for a specific month,
+// optionally restricted to one branch
+func (r *reportRepositoryImpl) GetMonthlyAttendanceReport(ctx context.Context, companyID string, branchID *string, month, year int) ([]report.MonthlyAttendanceEmployee, error) {
 	q := GetQuerier(ctx, r.db)
 
 	// Calculate period start and end dates
@@ -58,6 +59,7 @@ func (r *reportRepositoryImpl) GetMonthlyAttendanceReport(ctx context.Context, c
 			WHERE e.company_id = $1 
 				AND e.deleted_at IS NULL
 				AND e.employment_status = 'active'
+				AND ($4::uuid IS NULL OR e.branch_id = $4)
 		),
 		employee_summary AS (
 			SELECT 
@@ -89,7 +91,7 @@ func (r *reportRepositoryImpl) GetMonthlyAttendanceReport(ctx context.Context, c
 		ORDER BY es.employee_name ASC
 	`
 
-	rows, err := q.Query(ctx, query, companyID, periodStart, periodEnd)
+	rows, err := q.Query(ctx, query, companyID, periodStart, periodEnd, branchID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query attendance summary: %w", err)
 	}
@@ -276,8 +278,9 @@ func (r *reportRepositoryImpl) GetPayrollSummaryReport(ctx context.Context, comp
 	return result, nil
 }
 
-// GetLeaveBalanceReport retrieves leave balance for all employees in a company for a specific year
-func (r *reportRepositoryImpl) GetLeaveBalanceReport(ctx context.Context, companyID string, year int) ([]report.LeaveBalanceRow, error) {
+// GetLeaveBalanceReport retrieves leave balance for all employees in a company for a specific year,
+// optionally restricted to one branch
+func (r *reportRepositoryImpl) GetLeaveBalanceReport(ctx context.Context, companyID string, branchID *string, year int) ([]report.LeaveBalanceRow, error) {
 	q := GetQuerier(ctx, r.db)
 
 	query := `
@@ -301,10 +304,11 @@ func (r *reportRepositoryImpl) GetLeaveBalanceReport(ctx context.Context, compan
 			e.company_id = $1
 			AND e.employment_status = 'active'
 			AND e.deleted_at IS NULL
+			AND ($3::uuid IS NULL OR e.branch_id = $3)
 		ORDER BY e.full_name ASC, lt.name ASC
 	`
 
-	rows, err := q.Query(ctx, query, companyID, year)
+	rows, err := q.Query(ctx, query, companyID, year, branchID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query leave balance: %w", err)
 	}
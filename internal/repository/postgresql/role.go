@@ -0,0 +1,232 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/role"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type roleRepositoryImpl struct {
+	db *database.DB
+}
+
+func NewRoleRepository(db *database.DB) role.RoleRepository {
+	return &roleRepositoryImpl{db: db}
+}
+
+// Create implements role.RoleRepository.
+func (r *roleRepositoryImpl) Create(ctx context.Context, newRole role.Role) (role.Role, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		INSERT INTO company_roles (company_id, name, description, permissions)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, company_id, name, description, permissions, created_at, updated_at
+	`
+
+	created, err := scanRole(q.QueryRow(ctx, query,
+		newRole.CompanyID, newRole.Name, newRole.Description, permissionsToStrings(newRole.Permissions),
+	))
+	if err != nil {
+		if isRoleNameViolation(err) {
+			return role.Role{}, role.ErrRoleNameExists
+		}
+		return role.Role{}, fmt.Errorf("failed to create role: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetByID implements role.RoleRepository.
+func (r *roleRepositoryImpl) GetByID(ctx context.Context, id string, companyID string) (role.Role, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT id, company_id, name, description, permissions, created_at, updated_at
+		FROM company_roles
+		WHERE id = $1 AND company_id = $2
+	`
+
+	found, err := scanRole(q.QueryRow(ctx, query, id, companyID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return role.Role{}, role.ErrRoleNotFound
+		}
+		return role.Role{}, fmt.Errorf("failed to get role: %w", err)
+	}
+
+	return found, nil
+}
+
+// ListByCompanyID implements role.RoleRepository.
+func (r *roleRepositoryImpl) ListByCompanyID(ctx context.Context, companyID string) ([]role.Role, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT id, company_id, name, description, permissions, created_at, updated_at
+		FROM company_roles
+		WHERE company_id = $1
+		ORDER BY name ASC, id ASC
+	`
+
+	rows, err := q.Query(ctx, query, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := []role.Role{}
+	for rows.Next() {
+		found, err := scanRole(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan role: %w", err)
+		}
+		roles = append(roles, found)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return roles, nil
+}
+
+// Update implements role.RoleRepository.
+func (r *roleRepositoryImpl) Update(ctx context.Context, updated role.Role) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		UPDATE company_roles
+		SET name = $1, description = $2, permissions = $3, updated_at = NOW()
+		WHERE id = $4 AND company_id = $5
+	`
+
+	commandTag, err := q.Exec(ctx, query,
+		updated.Name, updated.Description, permissionsToStrings(updated.Permissions), updated.ID, updated.CompanyID,
+	)
+	if err != nil {
+		if isRoleNameViolation(err) {
+			return role.ErrRoleNameExists
+		}
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return role.ErrRoleNotFound
+	}
+
+	return nil
+}
+
+// Delete implements role.RoleRepository.
+// Users holding the role fall back to their base role (ON DELETE SET NULL).
+func (r *roleRepositoryImpl) Delete(ctx context.Context, id string, companyID string) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `DELETE FROM company_roles WHERE id = $1 AND company_id = $2`
+
+	commandTag, err := q.Exec(ctx, query, id, companyID)
+	if err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return role.ErrRoleNotFound
+	}
+
+	return nil
+}
+
+// AssignToUser implements role.RoleRepository.
+func (r *roleRepositoryImpl) AssignToUser(ctx context.Context, userID string, roleID *string) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		UPDATE users
+		SET company_role_id = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	commandTag, err := q.Exec(ctx, query, roleID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to assign role: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return user.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// GetPermissionsByUserID implements role.RoleRepository.
+func (r *roleRepositoryImpl) GetPermissionsByUserID(ctx context.Context, userID string) ([]user.Permission, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT cr.permissions
+		FROM users u
+		JOIN company_roles cr ON cr.id = u.company_role_id AND cr.company_id = u.company_id
+		WHERE u.id = $1
+	`
+
+	var permissions []string
+	err := q.QueryRow(ctx, query, userID).Scan(&permissions)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get role permissions: %w", err)
+	}
+
+	return stringsToPermissions(permissions), nil
+}
+
+func scanRole(row pgx.Row) (role.Role, error) {
+	var (
+		found       role.Role
+		permissions []string
+	)
+	err := row.Scan(
+		&found.ID,
+		&found.CompanyID,
+		&found.Name,
+		&found.Description,
+		&permissions,
+		&found.CreatedAt,
+		&found.UpdatedAt,
+	)
+	if err != nil {
+		return role.Role{}, err
+	}
+
+	found.Permissions = stringsToPermissions(permissions)
+	return found, nil
+}
+
+func isRoleNameViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "uk_company_role_name"
+}
+
+func permissionsToStrings(permissions []user.Permission) []string {
+	result := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		result = append(result, string(p))
+	}
+	return result
+}
+
+func stringsToPermissions(values []string) []user.Permission {
+	result := make([]user.Permission, 0, len(values))
+	for _, v := range values {
+		result = append(result, user.Permission(v))
+	}
+	return result
+}
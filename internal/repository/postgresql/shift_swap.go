@@ -0,0 +1,149 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type shiftSwapRepository struct {
+	db *database.DB
+}
+
+// NewShiftSwapRepository creates a new shift swap repository
+func NewShiftSwapRepository(db *database.DB) schedule.ShiftSwapRepository {
+	return &shiftSwapRepository{db: db}
+}
+
+const shiftSwapSelect = `
+	SELECT ssr.id, ssr.company_id, ssr.from_employee_id, ssr.to_employee_id, ssr.swap_date,
+		   ssr.status, ssr.requested_by, ssr.reviewed_by, ssr.reviewed_at, ssr.rejection_reason,
+		   ssr.created_at, ssr.updated_at,
+		   fe.full_name, te.full_name
+	FROM shift_swap_requests ssr
+	LEFT JOIN employees fe ON fe.id = ssr.from_employee_id
+	LEFT JOIN employees te ON te.id = ssr.to_employee_id
+`
+
+func scanShiftSwap(row pgx.Row) (schedule.ShiftSwapRequest, error) {
+	var s schedule.ShiftSwapRequest
+	err := row.Scan(
+		&s.ID, &s.CompanyID, &s.FromEmployeeID, &s.ToEmployeeID, &s.SwapDate,
+		&s.Status, &s.RequestedBy, &s.ReviewedBy, &s.ReviewedAt, &s.RejectionReason,
+		&s.CreatedAt, &s.UpdatedAt,
+		&s.FromEmployeeName, &s.ToEmployeeName,
+	)
+	return s, err
+}
+
+// Create implements schedule.ShiftSwapRepository.
+func (r *shiftSwapRepository) Create(ctx context.Context, swap schedule.ShiftSwapRequest) (schedule.ShiftSwapRequest, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		INSERT INTO shift_swap_requests (
+			company_id, from_employee_id, to_employee_id, swap_date, requested_by
+		) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, status, created_at, updated_at
+	`
+
+	err := q.QueryRow(ctx, query,
+		swap.CompanyID, swap.FromEmployeeID, swap.ToEmployeeID, swap.SwapDate, swap.RequestedBy,
+	).Scan(&swap.ID, &swap.Status, &swap.CreatedAt, &swap.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return schedule.ShiftSwapRequest{}, schedule.ErrShiftSwapAlreadyRequested
+		}
+		return schedule.ShiftSwapRequest{}, fmt.Errorf("failed to create shift swap request: %w", err)
+	}
+
+	return swap, nil
+}
+
+// GetByID implements schedule.ShiftSwapRepository.
+func (r *shiftSwapRepository) GetByID(ctx context.Context, id string, companyID string) (schedule.ShiftSwapRequest, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := shiftSwapSelect + ` WHERE ssr.id = $1 AND ssr.company_id = $2`
+
+	s, err := scanShiftSwap(q.QueryRow(ctx, query, id, companyID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return schedule.ShiftSwapRequest{}, schedule.ErrShiftSwapNotFound
+		}
+		return schedule.ShiftSwapRequest{}, fmt.Errorf("failed to get shift swap request: %w", err)
+	}
+
+	return s, nil
+}
+
+// List implements schedule.ShiftSwapRepository.
+func (r *shiftSwapRepository) List(ctx context.Context, companyID string, filter schedule.ShiftSwapFilter) ([]schedule.ShiftSwapRequest, error) {
+	q := GetQuerier(ctx, r.db)
+
+	whereClauses := []string{"ssr.company_id = $1"}
+	args := []interface{}{companyID}
+	argIdx := 2
+
+	if filter.Status != nil && *filter.Status != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("ssr.status = $%d", argIdx))
+		args = append(args, *filter.Status)
+		argIdx++
+	}
+	if filter.EmployeeID != nil && *filter.EmployeeID != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("(ssr.from_employee_id = $%d OR ssr.to_employee_id = $%d)", argIdx, argIdx))
+		args = append(args, *filter.EmployeeID)
+		argIdx++
+	}
+
+	query := shiftSwapSelect + " WHERE " + strings.Join(whereClauses, " AND ") + " ORDER BY ssr.swap_date DESC, ssr.created_at DESC"
+
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shift swap requests: %w", err)
+	}
+	defer rows.Close()
+
+	swaps := []schedule.ShiftSwapRequest{}
+	for rows.Next() {
+		s, err := scanShiftSwap(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan shift swap request: %w", err)
+		}
+		swaps = append(swaps, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return swaps, nil
+}
+
+// Review implements schedule.ShiftSwapRepository.
+func (r *shiftSwapRepository) Review(ctx context.Context, swap schedule.ShiftSwapRequest) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		UPDATE shift_swap_requests
+		SET status = $3, reviewed_by = $4, reviewed_at = NOW(), rejection_reason = $5, updated_at = NOW()
+		WHERE id = $1 AND company_id = $2 AND status = 'pending'
+	`
+
+	tag, err := q.Exec(ctx, query, swap.ID, swap.CompanyID, swap.Status, swap.ReviewedBy, swap.RejectionReason)
+	if err != nil {
+		return fmt.Errorf("failed to review shift swap request: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return schedule.ErrShiftSwapAlreadyProcessed
+	}
+
+	return nil
+}
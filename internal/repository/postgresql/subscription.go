@@ -2,12 +2,14 @@ package postgresql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/shopspring/decimal"
 )
 
@@ -84,14 +86,14 @@ func (r *planRepository) GetByID(ctx context.Context, id string) (subscription.P
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		SELECT id, name, price_per_seat, tier_level, max_seats, is_active, created_at, updated_at
+		SELECT id, name, price_per_seat, currency, tier_level, max_seats, is_active, created_at, updated_at
 		FROM subscription_plans
 		WHERE id = $1
 	`
 
 	var p subscription.Plan
 	err := q.QueryRow(ctx, query, id).Scan(
-		&p.ID, &p.Name, &p.PricePerSeat, &p.TierLevel, &p.MaxSeats, &p.IsActive, &p.CreatedAt, &p.UpdatedAt,
+		&p.ID, &p.Name, &p.PricePerSeat, &p.Currency, &p.TierLevel, &p.MaxSeats, &p.IsActive, &p.CreatedAt, &p.UpdatedAt,
 	)
 	if err != nil {
 		return subscription.Plan{}, err
@@ -111,14 +113,14 @@ func (r *planRepository) GetByName(ctx context.Context, name string) (subscripti
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		SELECT id, name, price_per_seat, tier_level, max_seats, is_active, created_at, updated_at
+		SELECT id, name, price_per_seat, currency, tier_level, max_seats, is_active, created_at, updated_at
 		FROM subscription_plans
 		WHERE name = $1
 	`
 
 	var p subscription.Plan
 	err := q.QueryRow(ctx, query, name).Scan(
-		&p.ID, &p.Name, &p.PricePerSeat, &p.TierLevel, &p.MaxSeats, &p.IsActive, &p.CreatedAt, &p.UpdatedAt,
+		&p.ID, &p.Name, &p.PricePerSeat, &p.Currency, &p.TierLevel, &p.MaxSeats, &p.IsActive, &p.CreatedAt, &p.UpdatedAt,
 	)
 	if err != nil {
 		return subscription.Plan{}, err
@@ -138,7 +140,7 @@ func (r *planRepository) ListActive(ctx context.Context) ([]subscription.Plan, e
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		SELECT id, name, price_per_seat, tier_level, max_seats, is_active, created_at, updated_at
+		SELECT id, name, price_per_seat, currency, tier_level, max_seats, is_active, created_at, updated_at
 		FROM subscription_plans
 		WHERE is_active = true
 		ORDER BY tier_level
@@ -154,7 +156,7 @@ func (r *planRepository) ListActive(ctx context.Context) ([]subscription.Plan, e
 	for rows.Next() {
 		var p subscription.Plan
 		if err := rows.Scan(
-			&p.ID, &p.Name, &p.PricePerSeat, &p.TierLevel, &p.MaxSeats, &p.IsActive, &p.CreatedAt, &p.UpdatedAt,
+			&p.ID, &p.Name, &p.PricePerSeat, &p.Currency, &p.TierLevel, &p.MaxSeats, &p.IsActive, &p.CreatedAt, &p.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -249,6 +251,26 @@ func (r *subscriptionRepository) GetByCompanyID(ctx context.Context, companyID s
 	return s, err
 }
 
+func (r *subscriptionRepository) GetByCompanyIDForUpdate(ctx context.Context, companyID string) (subscription.Subscription, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT id, company_id, plan_id, status, max_seats, pending_max_seats, current_period_start, current_period_end,
+			   trial_ends_at, pending_plan_id, billing_cycle, auto_renew, created_at, updated_at
+		FROM subscriptions
+		WHERE company_id = $1
+		FOR UPDATE
+	`
+
+	var s subscription.Subscription
+	err := q.QueryRow(ctx, query, companyID).Scan(
+		&s.ID, &s.CompanyID, &s.PlanID, &s.Status, &s.MaxSeats, &s.PendingMaxSeats,
+		&s.CurrentPeriodStart, &s.CurrentPeriodEnd, &s.TrialEndsAt,
+		&s.PendingPlanID, &s.BillingCycle, &s.AutoRenew, &s.CreatedAt, &s.UpdatedAt,
+	)
+	return s, err
+}
+
 func (r *subscriptionRepository) GetByCompanyIDWithFeatures(ctx context.Context, companyID string) (subscription.Subscription, error) {
 	// Get subscription
 	s, err := r.GetByCompanyID(ctx, companyID)
@@ -260,13 +282,13 @@ func (r *subscriptionRepository) GetByCompanyIDWithFeatures(ctx context.Context,
 
 	// Get plan
 	planQuery := `
-		SELECT id, name, price_per_seat, tier_level, max_seats, is_active, created_at, updated_at
+		SELECT id, name, price_per_seat, currency, tier_level, max_seats, is_active, created_at, updated_at
 		FROM subscription_plans
 		WHERE id = $1
 	`
 	var plan subscription.Plan
 	err = q.QueryRow(ctx, planQuery, s.PlanID).Scan(
-		&plan.ID, &plan.Name, &plan.PricePerSeat, &plan.TierLevel, &plan.MaxSeats, &plan.IsActive, &plan.CreatedAt, &plan.UpdatedAt,
+		&plan.ID, &plan.Name, &plan.PricePerSeat, &plan.Currency, &plan.TierLevel, &plan.MaxSeats, &plan.IsActive, &plan.CreatedAt, &plan.UpdatedAt,
 	)
 	if err != nil {
 		return subscription.Subscription{}, err
@@ -307,9 +329,10 @@ func (r *subscriptionRepository) GetByCompanyIDWithFeatures(ctx context.Context,
 	return s, nil
 }
 
+// Create inserts a subscription, returning subscription.ErrAlreadySubscribed when the company has one.
+// The insert runs under a savepoint, so after that error the caller's transaction can still read
+// the existing subscription.
 func (r *subscriptionRepository) Create(ctx context.Context, s subscription.Subscription) (subscription.Subscription, error) {
-	q := GetQuerier(ctx, r.db)
-
 	query := `
 		INSERT INTO subscriptions (company_id, plan_id, status, max_seats, current_period_start, current_period_end,
 								   trial_ends_at, pending_plan_id, billing_cycle, auto_renew)
@@ -317,12 +340,22 @@ func (r *subscriptionRepository) Create(ctx context.Context, s subscription.Subs
 		RETURNING id, created_at, updated_at
 	`
 
-	err := q.QueryRow(ctx, query,
-		s.CompanyID, s.PlanID, string(s.Status), s.MaxSeats, s.CurrentPeriodStart, s.CurrentPeriodEnd,
-		s.TrialEndsAt, s.PendingPlanID, string(s.BillingCycle), s.AutoRenew,
-	).Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+	err := withSavepoint(ctx, r.db, func(q database.Querier) error {
+		return q.QueryRow(ctx, query,
+			s.CompanyID, s.PlanID, string(s.Status), s.MaxSeats, s.CurrentPeriodStart, s.CurrentPeriodEnd,
+			s.TrialEndsAt, s.PendingPlanID, string(s.BillingCycle), s.AutoRenew,
+		).Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+	})
+	if err != nil {
+		// One subscription per company is enforced by uk_company_subscription
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "uk_company_subscription" {
+			return subscription.Subscription{}, subscription.ErrAlreadySubscribed
+		}
+		return subscription.Subscription{}, err
+	}
 
-	return s, err
+	return s, nil
 }
 
 func (r *subscriptionRepository) Update(ctx context.Context, s subscription.Subscription) error {
@@ -377,7 +410,7 @@ func (r *subscriptionRepository) ListExpiring(ctx context.Context, before interf
 		SELECT id, company_id, plan_id, status, max_seats, pending_max_seats, current_period_start, current_period_end,
 			   trial_ends_at, pending_plan_id, billing_cycle, auto_renew, created_at, updated_at
 		FROM subscriptions
-		WHERE current_period_end < $1 AND status IN ('trial', 'active')
+		WHERE current_period_end <= $1 AND status IN ('trial', 'active')
 		ORDER BY current_period_end
 	`
 
@@ -434,7 +467,7 @@ func (r *subscriptionRepository) ListByStatus(ctx context.Context, status subscr
 	return subs, nil
 }
 
-func (r *subscriptionRepository) UpdateExpiredToStatus(ctx context.Context, cutoffTime interface{}, fromStatuses []subscription.SubscriptionStatus, toStatus subscription.SubscriptionStatus) (int64, error) {
+func (r *subscriptionRepository) UpdateExpiredToStatus(ctx context.Context, cutoffTime interface{}, fromStatuses []subscription.SubscriptionStatus, toStatus subscription.SubscriptionStatus) ([]subscription.StatusChangedEvent, error) {
 	q := GetQuerier(ctx, r.db)
 
 	// Convert []SubscriptionStatus to []string for pgx encoding
@@ -443,17 +476,30 @@ func (r *subscriptionRepository) UpdateExpiredToStatus(ctx context.Context, cuto
 		fromStatusStrings[i] = string(s)
 	}
 
+	// The self-join reads the pre-update row so the previous status can be returned
 	query := `
-		UPDATE subscriptions
+		UPDATE subscriptions s
 		SET status = $1, updated_at = NOW()
-		WHERE current_period_end < $2 AND status = ANY($3::subscription_status[])
+		FROM subscriptions prev
+		WHERE prev.id = s.id AND s.current_period_end <= $2 AND s.status = ANY($3::subscription_status[])
+		RETURNING s.id, s.company_id, prev.status, s.status, s.current_period_end, s.updated_at
 	`
 
-	tag, err := q.Exec(ctx, query, string(toStatus), cutoffTime, fromStatusStrings)
+	rows, err := q.Query(ctx, query, string(toStatus), cutoffTime, fromStatusStrings)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return tag.RowsAffected(), nil
+	defer rows.Close()
+
+	var events []subscription.StatusChangedEvent
+	for rows.Next() {
+		var e subscription.StatusChangedEvent
+		if err := rows.Scan(&e.SubscriptionID, &e.CompanyID, &e.OldStatus, &e.NewStatus, &e.CurrentPeriodEnd, &e.OccurredAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
 }
 
 func (r *subscriptionRepository) UpdateMaxSeats(ctx context.Context, id string, maxSeats int) error {
@@ -578,8 +624,8 @@ func (r *invoiceRepository) GetByID(ctx context.Context, id string) (subscriptio
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		SELECT id, company_id, subscription_id, xendit_invoice_id, xendit_invoice_url, xendit_expiry_date,
-			   amount, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
+		SELECT id, company_id, subscription_id, invoice_number, xendit_invoice_id, xendit_invoice_url, xendit_expiry_date,
+			   amount, currency, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
 			   period_start, period_end, status, issue_date, paid_at, payment_method, payment_channel,
 			   description, notes, created_at, updated_at
 		FROM invoices
@@ -588,8 +634,8 @@ func (r *invoiceRepository) GetByID(ctx context.Context, id string) (subscriptio
 
 	var inv subscription.Invoice
 	err := q.QueryRow(ctx, query, id).Scan(
-		&inv.ID, &inv.CompanyID, &inv.SubscriptionID, &inv.XenditInvoiceID, &inv.XenditInvoiceURL, &inv.XenditExpiryDate,
-		&inv.Amount, &inv.IsProrated, &inv.PlanSnapshotName, &inv.PricePerSeatSnapshot, &inv.SeatCountSnapshot, &inv.BillingCycleSnapshot,
+		&inv.ID, &inv.CompanyID, &inv.SubscriptionID, &inv.InvoiceNumber, &inv.XenditInvoiceID, &inv.XenditInvoiceURL, &inv.XenditExpiryDate,
+		&inv.Amount, &inv.Currency, &inv.IsProrated, &inv.PlanSnapshotName, &inv.PricePerSeatSnapshot, &inv.SeatCountSnapshot, &inv.BillingCycleSnapshot,
 		&inv.PeriodStart, &inv.PeriodEnd, &inv.Status, &inv.IssueDate, &inv.PaidAt, &inv.PaymentMethod, &inv.PaymentChannel,
 		&inv.Description, &inv.Notes, &inv.CreatedAt, &inv.UpdatedAt,
 	)
@@ -600,8 +646,8 @@ func (r *invoiceRepository) GetByXenditID(ctx context.Context, xenditID string)
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		SELECT id, company_id, subscription_id, xendit_invoice_id, xendit_invoice_url, xendit_expiry_date,
-			   amount, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
+		SELECT id, company_id, subscription_id, invoice_number, xendit_invoice_id, xendit_invoice_url, xendit_expiry_date,
+			   amount, currency, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
 			   period_start, period_end, status, issue_date, paid_at, payment_method, payment_channel,
 			   description, notes, created_at, updated_at
 		FROM invoices
@@ -610,8 +656,8 @@ func (r *invoiceRepository) GetByXenditID(ctx context.Context, xenditID string)
 
 	var inv subscription.Invoice
 	err := q.QueryRow(ctx, query, xenditID).Scan(
-		&inv.ID, &inv.CompanyID, &inv.SubscriptionID, &inv.XenditInvoiceID, &inv.XenditInvoiceURL, &inv.XenditExpiryDate,
-		&inv.Amount, &inv.IsProrated, &inv.PlanSnapshotName, &inv.PricePerSeatSnapshot, &inv.SeatCountSnapshot, &inv.BillingCycleSnapshot,
+		&inv.ID, &inv.CompanyID, &inv.SubscriptionID, &inv.InvoiceNumber, &inv.XenditInvoiceID, &inv.XenditInvoiceURL, &inv.XenditExpiryDate,
+		&inv.Amount, &inv.Currency, &inv.IsProrated, &inv.PlanSnapshotName, &inv.PricePerSeatSnapshot, &inv.SeatCountSnapshot, &inv.BillingCycleSnapshot,
 		&inv.PeriodStart, &inv.PeriodEnd, &inv.Status, &inv.IssueDate, &inv.PaidAt, &inv.PaymentMethod, &inv.PaymentChannel,
 		&inv.Description, &inv.Notes, &inv.CreatedAt, &inv.UpdatedAt,
 	)
@@ -623,21 +669,38 @@ func (r *invoiceRepository) Create(ctx context.Context, inv subscription.Invoice
 
 	query := `
 		INSERT INTO invoices (company_id, subscription_id, xendit_invoice_id, xendit_invoice_url, xendit_expiry_date,
-						  amount, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
-						  period_start, period_end, status, description, notes)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11::billing_cycle_enum, $12, $13, $14::invoice_status, $15, $16)
+						  amount, currency, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
+						  period_start, period_end, status, description, notes, invoice_number)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12::billing_cycle_enum, $13, $14, $15::invoice_status, $16, $17, $18)
 		RETURNING id, issue_date, created_at, updated_at
 	`
 
 	err := q.QueryRow(ctx, query,
 		inv.CompanyID, inv.SubscriptionID, inv.XenditInvoiceID, inv.XenditInvoiceURL, inv.XenditExpiryDate,
-		inv.Amount, inv.IsProrated, inv.PlanSnapshotName, inv.PricePerSeatSnapshot, inv.SeatCountSnapshot, string(inv.BillingCycleSnapshot),
-		inv.PeriodStart, inv.PeriodEnd, string(inv.Status), inv.Description, inv.Notes,
+		inv.Amount, string(inv.Currency), inv.IsProrated, inv.PlanSnapshotName, inv.PricePerSeatSnapshot, inv.SeatCountSnapshot, string(inv.BillingCycleSnapshot),
+		inv.PeriodStart, inv.PeriodEnd, string(inv.Status), inv.Description, inv.Notes, inv.InvoiceNumber,
 	).Scan(&inv.ID, &inv.IssueDate, &inv.CreatedAt, &inv.UpdatedAt)
 
 	return inv, err
 }
 
+func (r *invoiceRepository) AllocateNumber(ctx context.Context, companyID string) (int64, error) {
+	q := GetQuerier(ctx, r.db)
+
+	// The upsert row-locks the company's counter until commit, serialising concurrent allocations
+	query := `
+		INSERT INTO invoice_sequences (company_id, last_number, updated_at)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (company_id) DO UPDATE
+		SET last_number = invoice_sequences.last_number + 1, updated_at = NOW()
+		RETURNING last_number
+	`
+
+	var number int64
+	err := q.QueryRow(ctx, query, companyID).Scan(&number)
+	return number, err
+}
+
 func (r *invoiceRepository) UpdateStatus(ctx context.Context, id string, status subscription.InvoiceStatus) error {
 	q := GetQuerier(ctx, r.db)
 
@@ -646,6 +709,17 @@ func (r *invoiceRepository) UpdateStatus(ctx context.Context, id string, status
 	return err
 }
 
+func (r *invoiceRepository) MarkDunningSent(ctx context.Context, id string) (bool, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `UPDATE invoices SET dunning_sent_at = NOW() WHERE id = $1 AND dunning_sent_at IS NULL`
+	tag, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
 func (r *invoiceRepository) UpdatePayment(ctx context.Context, id string, status subscription.InvoiceStatus, paidAt interface{}, method, channel string) error {
 	q := GetQuerier(ctx, r.db)
 
@@ -658,28 +732,82 @@ func (r *invoiceRepository) UpdatePayment(ctx context.Context, id string, status
 	return err
 }
 
-func (r *invoiceRepository) ListByCompanyID(ctx context.Context, companyID string) ([]subscription.Invoice, error) {
+func (r *invoiceRepository) ListByCompanyID(ctx context.Context, companyID string, filter subscription.InvoiceFilter) ([]subscription.Invoice, int64, error) {
 	q := GetQuerier(ctx, r.db)
 
-	query := `
-		SELECT id, company_id, subscription_id, xendit_invoice_id, xendit_invoice_url, xendit_expiry_date,
-			   amount, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
+	// Build WHERE clause
+	whereClause := "WHERE company_id = $1"
+	args := []interface{}{companyID}
+	argIndex := 2
+
+	if filter.Status != nil {
+		whereClause += fmt.Sprintf(" AND status = $%d", argIndex)
+		args = append(args, *filter.Status)
+		argIndex++
+	}
+
+	if filter.BillingCycle != nil {
+		whereClause += fmt.Sprintf(" AND billing_cycle_snapshot = $%d", argIndex)
+		args = append(args, *filter.BillingCycle)
+		argIndex++
+	}
+
+	if filter.StartDate != nil {
+		whereClause += fmt.Sprintf(" AND issue_date >= $%d::date", argIndex)
+		args = append(args, *filter.StartDate)
+		argIndex++
+	}
+
+	if filter.EndDate != nil {
+		// End date is inclusive, so compare against the start of the following day
+		whereClause += fmt.Sprintf(" AND issue_date < $%d::date + 1", argIndex)
+		args = append(args, *filter.EndDate)
+		argIndex++
+	}
+
+	// Count total
+	countQuery := "SELECT COUNT(*) FROM invoices " + whereClause
+
+	var total int64
+	if err := q.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+
+	query := fmt.Sprintf(`
+		SELECT id, company_id, subscription_id, invoice_number, xendit_invoice_id, xendit_invoice_url, xendit_expiry_date,
+			   amount, currency, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
 			   period_start, period_end, status, issue_date, paid_at, payment_method, payment_channel,
 			   description, notes, created_at, updated_at
 		FROM invoices
-		WHERE company_id = $1
-		ORDER BY issue_date DESC
-	`
+		%s
+		ORDER BY issue_date DESC, invoice_number DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argIndex, argIndex+1)
 
-	return r.scanInvoices(ctx, q, query, companyID)
+	args = append(args, filter.Limit, offset)
+
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	invoices, err := r.parseInvoiceRows(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return invoices, total, nil
 }
 
 func (r *invoiceRepository) ListBySubscriptionID(ctx context.Context, subscriptionID string) ([]subscription.Invoice, error) {
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		SELECT id, company_id, subscription_id, xendit_invoice_id, xendit_invoice_url, xendit_expiry_date,
-			   amount, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
+		SELECT id, company_id, subscription_id, invoice_number, xendit_invoice_id, xendit_invoice_url, xendit_expiry_date,
+			   amount, currency, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
 			   period_start, period_end, status, issue_date, paid_at, payment_method, payment_channel,
 			   description, notes, created_at, updated_at
 		FROM invoices
@@ -694,8 +822,8 @@ func (r *invoiceRepository) ListPending(ctx context.Context) ([]subscription.Inv
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		SELECT id, company_id, subscription_id, xendit_invoice_id, xendit_invoice_url, xendit_expiry_date,
-			   amount, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
+		SELECT id, company_id, subscription_id, invoice_number, xendit_invoice_id, xendit_invoice_url, xendit_expiry_date,
+			   amount, currency, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
 			   period_start, period_end, status, issue_date, paid_at, payment_method, payment_channel,
 			   description, notes, created_at, updated_at
 		FROM invoices
@@ -710,8 +838,8 @@ func (r *invoiceRepository) ListPendingOlderThan(ctx context.Context, olderThan
 	q := GetQuerier(ctx, r.db)
 
 	query := `
-		SELECT id, company_id, subscription_id, xendit_invoice_id, xendit_invoice_url, xendit_expiry_date,
-			   amount, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
+		SELECT id, company_id, subscription_id, invoice_number, xendit_invoice_id, xendit_invoice_url, xendit_expiry_date,
+			   amount, currency, is_prorated, plan_snapshot_name, price_per_seat_snapshot, seat_count_snapshot, billing_cycle_snapshot,
 			   period_start, period_end, status, issue_date, paid_at, payment_method, payment_channel,
 			   description, notes, created_at, updated_at
 		FROM invoices
@@ -782,8 +910,8 @@ func (r *invoiceRepository) parseInvoiceRows(rows pgx.Rows) ([]subscription.Invo
 	for rows.Next() {
 		var inv subscription.Invoice
 		if err := rows.Scan(
-			&inv.ID, &inv.CompanyID, &inv.SubscriptionID, &inv.XenditInvoiceID, &inv.XenditInvoiceURL, &inv.XenditExpiryDate,
-			&inv.Amount, &inv.IsProrated, &inv.PlanSnapshotName, &inv.PricePerSeatSnapshot, &inv.SeatCountSnapshot, &inv.BillingCycleSnapshot,
+			&inv.ID, &inv.CompanyID, &inv.SubscriptionID, &inv.InvoiceNumber, &inv.XenditInvoiceID, &inv.XenditInvoiceURL, &inv.XenditExpiryDate,
+			&inv.Amount, &inv.Currency, &inv.IsProrated, &inv.PlanSnapshotName, &inv.PricePerSeatSnapshot, &inv.SeatCountSnapshot, &inv.BillingCycleSnapshot,
 			&inv.PeriodStart, &inv.PeriodEnd, &inv.Status, &inv.IssueDate, &inv.PaidAt, &inv.PaymentMethod, &inv.PaymentChannel,
 			&inv.Description, &inv.Notes, &inv.CreatedAt, &inv.UpdatedAt,
 		); err != nil {
@@ -794,6 +922,114 @@ func (r *invoiceRepository) parseInvoiceRows(rows pgx.Rows) ([]subscription.Invo
 	return invoices, nil
 }
 
+func (r *subscriptionRepository) ListTrialsForReminder(ctx context.Context, now, endsBefore time.Time, leadDays int) ([]subscription.Subscription, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT id, company_id, plan_id, status, max_seats, pending_max_seats, current_period_start, current_period_end,
+			   trial_ends_at, pending_plan_id, billing_cycle, auto_renew, created_at, updated_at
+		FROM subscriptions
+		WHERE status = 'trial'
+		  AND COALESCE(trial_ends_at, current_period_end) > $1
+		  AND COALESCE(trial_ends_at, current_period_end) <= $2
+		  AND (trial_reminder_days IS NULL OR trial_reminder_days > $3)
+		ORDER BY COALESCE(trial_ends_at, current_period_end)
+	`
+
+	rows, err := q.Query(ctx, query, now, endsBefore, leadDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []subscription.Subscription
+	for rows.Next() {
+		var s subscription.Subscription
+		if err := rows.Scan(
+			&s.ID, &s.CompanyID, &s.PlanID, &s.Status, &s.MaxSeats, &s.PendingMaxSeats,
+			&s.CurrentPeriodStart, &s.CurrentPeriodEnd, &s.TrialEndsAt,
+			&s.PendingPlanID, &s.BillingCycle, &s.AutoRenew, &s.CreatedAt, &s.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	return subs, nil
+}
+
+func (r *subscriptionRepository) MarkTrialReminderSent(ctx context.Context, id string, leadDays int) error {
+	q := GetQuerier(ctx, r.db)
+	query := `UPDATE subscriptions SET trial_reminder_days = $2, updated_at = NOW() WHERE id = $1`
+	_, err := q.Exec(ctx, query, id, leadDays)
+	return err
+}
+
+func (r *subscriptionRepository) ListSeatViolations(ctx context.Context) ([]subscription.SeatViolation, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT s.id, s.company_id, s.status, s.max_seats, s.pending_max_seats, e.active_count
+		FROM subscriptions s
+		JOIN (
+			SELECT company_id, COUNT(*) AS active_count
+			FROM employees
+			WHERE employment_status = 'active' AND deleted_at IS NULL
+			GROUP BY company_id
+		) e ON e.company_id = s.company_id
+		WHERE s.status IN ('trial', 'active', 'past_due')
+		  AND e.active_count > s.max_seats
+		ORDER BY e.active_count - s.max_seats DESC, s.company_id
+	`
+
+	rows, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var violations []subscription.SeatViolation
+	for rows.Next() {
+		var v subscription.SeatViolation
+		if err := rows.Scan(&v.SubscriptionID, &v.CompanyID, &v.Status, &v.MaxSeats, &v.PendingMaxSeats, &v.ActiveEmployees); err != nil {
+			return nil, err
+		}
+		v.ExcessSeats = v.ActiveEmployees - v.MaxSeats
+		violations = append(violations, v)
+	}
+	return violations, rows.Err()
+}
+
+func (r *subscriptionRepository) MarkSeatViolationFlagged(ctx context.Context, id string) (bool, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `UPDATE subscriptions SET seat_violation_flagged_at = NOW() WHERE id = $1 AND seat_violation_flagged_at IS NULL`
+	tag, err := q.Exec(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *subscriptionRepository) ClearResolvedSeatViolations(ctx context.Context) (int64, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		UPDATE subscriptions s
+		SET seat_violation_flagged_at = NULL
+		WHERE s.seat_violation_flagged_at IS NOT NULL
+		  AND (
+			SELECT COUNT(*)
+			FROM employees e
+			WHERE e.company_id = s.company_id AND e.employment_status = 'active' AND e.deleted_at IS NULL
+		  ) <= s.max_seats
+	`
+	tag, err := q.Exec(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
 // ==================== Employee Counter (for seat validation) ====================
 
 type employeeCounter struct {
@@ -818,6 +1054,97 @@ func (r *employeeCounter) CountActiveByCompanyID(ctx context.Context, companyID
 	return count, err
 }
 
+func (r *employeeCounter) CountSeatsInUseByCompanyID(ctx context.Context, companyID string) (int, error) {
+	q := GetQuerier(ctx, r.db)
+
+	// Invitations for active employees are already covered by the employee count
+	query := `
+		SELECT
+			(SELECT COUNT(*)
+			 FROM employees
+			 WHERE company_id = $1 AND employment_status = 'active' AND deleted_at IS NULL)
+			+
+			(SELECT COUNT(*)
+			 FROM employee_invitations i
+			 JOIN employees e ON e.id = i.employee_id
+			 WHERE i.company_id = $1 AND i.status = 'pending' AND i.expires_at > NOW()
+			   AND e.deleted_at IS NULL AND e.employment_status <> 'active')
+	`
+
+	var count int
+	err := q.QueryRow(ctx, query, companyID).Scan(&count)
+	return count, err
+}
+
+func (r *employeeCounter) CountInactiveByCompanyID(ctx context.Context, companyID string) (int, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT COUNT(*)
+		FROM employees
+		WHERE company_id = $1 AND employment_status <> 'active' AND deleted_at IS NULL
+	`
+
+	var count int
+	err := q.QueryRow(ctx, query, companyID).Scan(&count)
+	return count, err
+}
+
+func (r *employeeCounter) CountPendingInvitationsByCompanyID(ctx context.Context, companyID string) (int, error) {
+	q := GetQuerier(ctx, r.db)
+
+	// Same filter as CountSeatsInUseByCompanyID, so the breakdown adds up to the seats in use
+	query := `
+		SELECT COUNT(*)
+		FROM employee_invitations i
+		JOIN employees e ON e.id = i.employee_id
+		WHERE i.company_id = $1 AND i.status = 'pending' AND i.expires_at > NOW()
+		  AND e.deleted_at IS NULL AND e.employment_status <> 'active'
+	`
+
+	var count int
+	err := q.QueryRow(ctx, query, companyID).Scan(&count)
+	return count, err
+}
+
+// ==================== Billing Contacts ====================
+
+type billingContactFinder struct {
+	db *database.DB
+}
+
+func NewBillingContactFinder(db *database.DB) subscription.BillingContactFinder {
+	return &billingContactFinder{db: db}
+}
+
+func (r *billingContactFinder) ListOwnersByCompanyID(ctx context.Context, companyID string) ([]subscription.BillingContact, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		SELECT u.id, u.email, COALESCE(e.full_name, u.email), c.name
+		FROM users u
+		JOIN companies c ON c.id = u.company_id
+		LEFT JOIN employees e ON e.user_id = u.id AND e.deleted_at IS NULL
+		WHERE u.company_id = $1 AND u.role = 'owner'
+	`
+
+	rows, err := q.Query(ctx, query, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contacts []subscription.BillingContact
+	for rows.Next() {
+		var c subscription.BillingContact
+		if err := rows.Scan(&c.UserID, &c.Email, &c.Name, &c.CompanyName); err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, rows.Err()
+}
+
 // ==================== Helper Functions ====================
 
 // CalculatePeriodEnd calculates the end date based on billing cycle
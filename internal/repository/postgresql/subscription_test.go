@@ -0,0 +1,113 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database/dbtest"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestSubscriptionRepositoryCreateConflictInTransaction(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	trialEnd := start.AddDate(0, 0, 14)
+	db, server := dbtest.NewDB(t, func(sql string) dbtest.Result {
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(sql), "INSERT INTO subscriptions"):
+			return dbtest.Result{Err: &pgconn.PgError{Code: "23505", ConstraintName: "uk_company_subscription", Message: "duplicate key value violates unique constraint"}}
+		case strings.Contains(sql, "FROM subscriptions"):
+			return dbtest.Result{
+				Columns: []string{"id", "company_id", "plan_id", "status", "max_seats", "pending_max_seats", "current_period_start", "current_period_end", "trial_ends_at", "pending_plan_id", "billing_cycle", "auto_renew", "created_at", "updated_at"},
+				Rows:    [][]any{{"sub-1", "company-1", "plan-trial", "trial", 10, nil, start, trialEnd, trialEnd, nil, "monthly", false, start, start}},
+			}
+		}
+		return dbtest.Result{}
+	})
+	repo := &subscriptionRepository{db: db}
+	ctx := context.Background()
+
+	// The company signup creates the trial inside its own transaction
+	var existing subscription.Subscription
+	err := WithTransaction(ctx, db, func(tx pgx.Tx) error {
+		txCtx := ContextWithTx(ctx, tx)
+		_, err := repo.Create(txCtx, subscription.Subscription{
+			CompanyID: "company-1", PlanID: "plan-trial", Status: subscription.StatusTrial, MaxSeats: 10,
+			CurrentPeriodStart: start, CurrentPeriodEnd: trialEnd, TrialEndsAt: &trialEnd, BillingCycle: subscription.BillingCycleMonthly,
+		})
+		if !errors.Is(err, subscription.ErrAlreadySubscribed) {
+			t.Fatalf("Create() error = %v, want %v", err, subscription.ErrAlreadySubscribed)
+		}
+
+		existing, err = repo.GetByCompanyID(txCtx, "company-1")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("transaction error = %v, want the existing subscription read after the conflict", err)
+	}
+	if existing.ID != "sub-1" {
+		t.Errorf("GetByCompanyID() = %+v", existing)
+	}
+
+	// Only the insert is undone; the read and the commit run in the same transaction
+	statements := server.Statements()
+	want := []string{"begin", "savepoint", "insert into subscriptions", "rollback to savepoint", "select id, company_id", "commit"}
+	if len(statements) != len(want) {
+		t.Fatalf("statements = %q, want %d", statements, len(want))
+	}
+	for i, prefix := range want {
+		if got := strings.ToLower(strings.Join(strings.Fields(statements[i]), " ")); !strings.HasPrefix(got, prefix) {
+			t.Errorf("statement %d = %q, want %q...", i, got, prefix)
+		}
+	}
+}
+
+func TestSubscriptionRepositoryCreateConflictOutsideTransaction(t *testing.T) {
+	db, server := dbtest.NewDB(t, func(sql string) dbtest.Result {
+		return dbtest.Result{Err: &pgconn.PgError{Code: "23505", ConstraintName: "uk_company_subscription"}}
+	})
+	repo := &subscriptionRepository{db: db}
+
+	_, err := repo.Create(context.Background(), subscription.Subscription{CompanyID: "company-1"})
+	if !errors.Is(err, subscription.ErrAlreadySubscribed) {
+		t.Errorf("Create() error = %v, want %v", err, subscription.ErrAlreadySubscribed)
+	}
+	if statements := server.Statements(); len(statements) != 1 {
+		t.Errorf("statements = %q, want only the insert", statements)
+	}
+}
+
+func TestSubscriptionRepositoryPeriodEndIsInclusive(t *testing.T) {
+	cutoff := time.Date(2026, 3, 17, 9, 30, 0, 0, time.UTC)
+	tx := newFakeTx(fakeResult{}, fakeResult{})
+	repo := &subscriptionRepository{}
+
+	if _, err := repo.ListExpiring(tx.ctx(), cutoff); err != nil {
+		t.Fatalf("ListExpiring() error = %v", err)
+	}
+	if _, err := repo.UpdateExpiredToStatus(tx.ctx(), cutoff, []subscription.SubscriptionStatus{subscription.StatusTrial}, subscription.StatusExpired); err != nil {
+		t.Fatalf("UpdateExpiredToStatus() error = %v", err)
+	}
+
+	// A period ending exactly at the cutoff has ended
+	wants := []struct {
+		condition string
+		arg       int
+	}{
+		{"current_period_end <= $1", 0},
+		{"current_period_end <= $2", 1},
+	}
+	for i, want := range wants {
+		call := tx.calls[i]
+		if !strings.Contains(compactSQL(call.sql), want.condition) {
+			t.Errorf("statement %d = %q, want %q", i, compactSQL(call.sql), want.condition)
+		}
+		if got, ok := call.args[want.arg].(time.Time); !ok || !got.Equal(cutoff) {
+			t.Errorf("statement %d cutoff = %v, want %s", i, call.args[want.arg], cutoff)
+		}
+	}
+}
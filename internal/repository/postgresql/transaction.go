@@ -2,12 +2,25 @@ package postgresql
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// txContextKey is the context key of the transaction repositories should run on
+type txContextKey struct{}
+
+// ContextWithTx returns a copy of ctx that makes repositories run their queries on tx
+func ContextWithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
 // WithTransaction executes fn inside a database transaction
 func WithTransaction(ctx context.Context, db *database.DB, fn func(tx pgx.Tx) error) error {
 	tx, err := db.BeginTx(ctx)
@@ -38,10 +51,112 @@ func WithTransaction(ctx context.Context, db *database.DB, fn func(tx pgx.Tx) er
 	return nil
 }
 
+// WithTransactionRetry is WithTransaction that re-runs fn in a fresh transaction when Postgres
+// aborts it with a serialization failure or deadlock, following db.TxRetry.
+// fn may run more than once, so it must only touch the database: uploads, provider calls and
+// other side effects belong outside it, and values it assigns are overwritten by the last attempt.
+func WithTransactionRetry(ctx context.Context, db *database.DB, fn func(tx pgx.Tx) error) error {
+	policy := db.TxRetry
+	delay := policy.BaseDelay
+
+	for attempt := 0; ; attempt++ {
+		err := WithTransaction(ctx, db, fn)
+		if err == nil || attempt >= policy.MaxRetries || !IsRetryableTxError(err) {
+			return err
+		}
+
+		// Full jitter keeps concurrent retries of the same conflict from colliding again
+		wait := delay
+		if wait > 0 {
+			wait = time.Duration(rand.Int63n(int64(wait)) + 1)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+		if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+}
+
+// IsRetryableTxError reports whether err comes from a transaction Postgres aborted because of a
+// conflict with a concurrent one (serialization_failure or deadlock_detected)
+func IsRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
+// WithStatementTimeout runs fn with statement_timeout lowered or raised to timeout for its queries,
+// overriding the connection default. Outside a transaction fn gets its own one, so several calls
+// can run in parallel; inside one the setting lasts until that transaction ends.
+// A query hitting the limit fails with an error matching database.ErrStatementTimeout.
+func WithStatementTimeout[T any](ctx context.Context, db *database.DB, timeout time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	run := func(ctx context.Context, tx pgx.Tx) error {
+		// SET LOCAL takes no parameters; set_config with is_local = true is its parameterized form
+		if _, err := tx.Exec(ctx, "SELECT set_config('statement_timeout', $1, true)", strconv.FormatInt(timeout.Milliseconds(), 10)); err != nil {
+			return fmt.Errorf("set statement timeout: %w", err)
+		}
+		var err error
+		result, err = fn(ctx)
+		return err
+	}
+
+	var err error
+	if tx, ok := ctx.Value(txContextKey{}).(pgx.Tx); ok {
+		err = run(ctx, tx)
+	} else {
+		err = WithTransaction(ctx, db, func(tx pgx.Tx) error {
+			return run(ContextWithTx(ctx, tx), tx)
+		})
+	}
+	if err != nil {
+		if database.IsStatementTimeout(err) && !errors.Is(err, database.ErrStatementTimeout) {
+			err = fmt.Errorf("%w: %w", database.ErrStatementTimeout, err)
+		}
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
+
+// withSavepoint runs fn under a savepoint when ctx carries a transaction, so an error the caller
+// recovers from (e.g. a unique violation) only undoes fn instead of aborting the whole transaction.
+// Outside a transaction fn runs on the pool, where a failed statement affects nothing else.
+func withSavepoint(ctx context.Context, db *database.DB, fn func(q database.Querier) error) error {
+	tx, ok := ctx.Value(txContextKey{}).(pgx.Tx)
+	if !ok {
+		return fn(db.Pool)
+	}
+
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("create savepoint: %w", err)
+	}
+	if err := fn(savepoint); err != nil {
+		if rbErr := savepoint.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("rollback to savepoint error: %v (original error: %w)", rbErr, err)
+		}
+		return err
+	}
+	return savepoint.Commit(ctx)
+}
+
 // GetQuerier returns either transaction or pool
 // Used in repositories to support both transactional and non-transactional operations
 func GetQuerier(ctx context.Context, db *database.DB) database.Querier {
-	if tx, ok := ctx.Value("tx").(pgx.Tx); ok {
+	if tx, ok := ctx.Value(txContextKey{}).(pgx.Tx); ok {
 		return tx
 	}
 	return db.Pool
@@ -0,0 +1,46 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database/dbtest"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestGetQuerier(t *testing.T) {
+	db, _ := dbtest.NewDB(t, nil)
+	tx := newFakeTx()
+
+	t.Run("transaction when set", func(t *testing.T) {
+		if q := GetQuerier(ContextWithTx(context.Background(), tx), db); q != tx {
+			t.Errorf("GetQuerier() = %T, want the transaction", q)
+		}
+	})
+
+	t.Run("pool otherwise", func(t *testing.T) {
+		if q := GetQuerier(context.Background(), db); q != db.Pool {
+			t.Errorf("GetQuerier() = %T, want the pool", q)
+		}
+	})
+
+	t.Run("untyped key is ignored", func(t *testing.T) {
+		// Services used to store the transaction under the string "tx"
+		ctx := context.WithValue(context.Background(), "tx", pgx.Tx(tx))
+		if q := GetQuerier(ctx, db); q != db.Pool {
+			t.Errorf("GetQuerier() = %T, want the pool", q)
+		}
+	})
+
+	t.Run("transaction opened by WithTransaction", func(t *testing.T) {
+		err := WithTransaction(context.Background(), db, func(opened pgx.Tx) error {
+			if q := GetQuerier(ContextWithTx(context.Background(), opened), db); q != opened {
+				t.Errorf("GetQuerier() = %T, want the open transaction", q)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -120,8 +120,8 @@ func (r *userRepositoryImpl) LinkPasswordAccount(ctx context.Context, id string,
 	return updated, nil
 }
 
-// LinkGoogleAccount implements user.UserRepository.
-func (r *userRepositoryImpl) LinkGoogleAccount(ctx context.Context, GoogleID string, email string) (user.User, error) {
+// LinkOAuthAccount implements user.UserRepository.
+func (r *userRepositoryImpl) LinkOAuthAccount(ctx context.Context, provider string, providerID string, email string) (user.User, error) {
 	q := GetQuerier(ctx, r.db)
 
 	updateQuery := `
@@ -134,7 +134,7 @@ func (r *userRepositoryImpl) LinkGoogleAccount(ctx context.Context, GoogleID str
 	`
 
 	var updated user.User
-	err := q.QueryRow(ctx, updateQuery, "google", GoogleID, email).Scan(
+	err := q.QueryRow(ctx, updateQuery, provider, providerID, email).Scan(
 		&updated.ID,
 		&updated.CompanyID,
 		&updated.Email,
@@ -239,7 +239,7 @@ func (r *userRepositoryImpl) GetByID(ctx context.Context, id string) (user.User,
 	query := `
 		SELECT u.id, u.company_id, u.email, u.password_hash, u.role, u.oauth_provider, u.oauth_provider_id,
 			   u.email_verified, u.email_verification_token, u.email_verification_sent_at,
-			   u.created_at, u.updated_at, e.id AS employee_id
+			   u.managed_branch_id, u.created_at, u.updated_at, e.id AS employee_id
 		FROM users u
 		LEFT JOIN employees e ON u.id = e.user_id
 		WHERE u.id = $1
@@ -257,6 +257,7 @@ func (r *userRepositoryImpl) GetByID(ctx context.Context, id string) (user.User,
 		&found.EmailVerified,
 		&found.EmailVerificationToken,
 		&found.EmailVerificationSentAt,
+		&found.ManagedBranchID,
 		&found.CreatedAt,
 		&found.UpdatedAt,
 		&found.EmployeeID,
@@ -327,6 +328,29 @@ func (r *userRepositoryImpl) UpdateCompanyAndRole(ctx context.Context, userID, c
 	return nil
 }
 
+// UpdateManagedBranch implements user.UserRepository.
+func (r *userRepositoryImpl) UpdateManagedBranch(ctx context.Context, userID string, branchID *string) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		UPDATE users
+		SET managed_branch_id = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING id
+	`
+
+	var updatedID string
+	err := q.QueryRow(ctx, query, branchID, userID).Scan(&updatedID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return user.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to update managed branch: %w", err)
+	}
+
+	return nil
+}
+
 // UpdatePassword implements user.UserRepository.
 func (r *userRepositoryImpl) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
 	q := GetQuerier(ctx, r.db)
@@ -420,3 +444,30 @@ func (r *userRepositoryImpl) GetByEmailVerificationToken(ctx context.Context, to
 
 	return u, nil
 }
+
+// DetachFromCompany implements user.UserRepository.
+func (r *userRepositoryImpl) DetachFromCompany(ctx context.Context, userID string) error {
+	q := GetQuerier(ctx, r.db)
+
+	query := `
+		UPDATE users
+		SET company_id = NULL, role = $1, managed_branch_id = NULL, company_role_id = NULL, updated_at = NOW()
+		WHERE id = $2
+		RETURNING id
+	`
+
+	var updatedID string
+	err := q.QueryRow(ctx, query, user.RolePending, userID).Scan(&updatedID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return user.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to detach user from company: %w", err)
+	}
+
+	if _, err := q.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`, userID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return nil
+}
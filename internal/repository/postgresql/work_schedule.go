@@ -9,6 +9,7 @@ import (
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/pagination"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -45,18 +46,22 @@ SELECT
     ws.id AS schedule_id,
     ws.name AS schedule_name,
     ws.grace_period_minutes,
+    ws.earliest_clock_in_minutes,
     ws.type AS location_type, -- 'WFO', 'WFA', 'Hybrid'
     
     -- Detail Waktu (Spesifik Hari Ini)
     wst.id AS time_id,
     wst.clock_in_time,
     wst.clock_out_time,
+    wst.break_start_time,
+    wst.break_end_time,
     wst.is_next_day_checkout,
     
     -- Detail Lokasi (Digabung jadi satu JSON Array)
     COALESCE(
         (
             SELECT json_agg(json_build_object(
+                'id', wsl.id,
                 'name', wsl.location_name,
                 'latitude', wsl.latitude,
                 'longitude', wsl.longitude,
@@ -82,16 +87,19 @@ WHERE
 
 	// ActiveScheduleDTO menampung hasil raw dari database
 	type activeScheduleDTO struct {
-		ScheduleID         string `db:"schedule_id"`
-		ScheduleName       string `db:"schedule_name"`
-		LocationType       string `db:"location_type"`
-		GracePeriodMinutes int    `db:"grace_period_minutes"`
+		ScheduleID             string `db:"schedule_id"`
+		ScheduleName           string `db:"schedule_name"`
+		LocationType           string `db:"location_type"`
+		GracePeriodMinutes     int    `db:"grace_period_minutes"`
+		EarliestClockInMinutes int    `db:"earliest_clock_in_minutes"`
 
 		// Detail Waktu
-		TimeID            string    `db:"time_id"`
-		ClockInTime       time.Time `db:"clock_in_time"`
-		ClockOutTime      time.Time `db:"clock_out_time"`
-		IsNextDayCheckout bool      `db:"is_next_day_checkout"`
+		TimeID            string     `db:"time_id"`
+		ClockInTime       time.Time  `db:"clock_in_time"`
+		ClockOutTime      time.Time  `db:"clock_out_time"`
+		BreakStartTime    *time.Time `db:"break_start_time"`
+		BreakEndTime      *time.Time `db:"break_end_time"`
+		IsNextDayCheckout bool       `db:"is_next_day_checkout"`
 
 		// Lokasi (Raw JSON)
 		AllowedLocationsJSON []byte `db:"allowed_locations"`
@@ -103,10 +111,13 @@ WHERE
 		&dto.ScheduleID,
 		&dto.ScheduleName,
 		&dto.GracePeriodMinutes,
+		&dto.EarliestClockInMinutes,
 		&dto.LocationType,
 		&dto.TimeID,
 		&dto.ClockInTime,
 		&dto.ClockOutTime,
+		&dto.BreakStartTime,
+		&dto.BreakEndTime,
 		&dto.IsNextDayCheckout,
 		&dto.AllowedLocationsJSON,
 	)
@@ -125,15 +136,18 @@ WHERE
 
 	// Map DTO to domain model
 	return &schedule.ActiveSchedule{
-		ScheduleID:         dto.ScheduleID,
-		ScheduleName:       dto.ScheduleName,
-		LocationType:       dto.LocationType,
-		GracePeriodMinutes: dto.GracePeriodMinutes,
-		TimeID:             dto.TimeID,
-		ClockIn:            dto.ClockInTime,
-		ClockOut:           dto.ClockOutTime,
-		IsNextDayCheckout:  dto.IsNextDayCheckout,
-		Locations:          locations,
+		ScheduleID:             dto.ScheduleID,
+		ScheduleName:           dto.ScheduleName,
+		LocationType:           dto.LocationType,
+		GracePeriodMinutes:     dto.GracePeriodMinutes,
+		EarliestClockInMinutes: dto.EarliestClockInMinutes,
+		TimeID:                 dto.TimeID,
+		ClockIn:                dto.ClockInTime,
+		ClockOut:               dto.ClockOutTime,
+		BreakStart:             dto.BreakStartTime,
+		BreakEnd:               dto.BreakEndTime,
+		IsNextDayCheckout:      dto.IsNextDayCheckout,
+		Locations:              locations,
 	}, nil
 }
 
@@ -143,15 +157,15 @@ func (w *workScheduleRepositoryImpl) Create(ctx context.Context, workSchedule sc
 
 	query := `
 		INSERT INTO work_schedules (
-			id, company_id, name, type, grace_period_minutes, created_at, updated_at
+			id, company_id, name, type, grace_period_minutes, earliest_clock_in_minutes, created_at, updated_at
 		) VALUES (
-			uuidv7(), $1, $2, $3, $4, NOW(), NOW()
-		) RETURNING id, grace_period_minutes, created_at, updated_at
+			uuidv7(), $1, $2, $3, $4, $5, NOW(), NOW()
+		) RETURNING id, grace_period_minutes, earliest_clock_in_minutes, created_at, updated_at
 	`
 
 	err := q.QueryRow(ctx, query,
-		workSchedule.CompanyID, workSchedule.Name, workSchedule.Type, workSchedule.GracePeriodMinutes,
-	).Scan(&workSchedule.ID, &workSchedule.GracePeriodMinutes, &workSchedule.CreatedAt, &workSchedule.UpdatedAt)
+		workSchedule.CompanyID, workSchedule.Name, workSchedule.Type, workSchedule.GracePeriodMinutes, workSchedule.EarliestClockInMinutes,
+	).Scan(&workSchedule.ID, &workSchedule.GracePeriodMinutes, &workSchedule.EarliestClockInMinutes, &workSchedule.CreatedAt, &workSchedule.UpdatedAt)
 
 	if err != nil {
 		return schedule.WorkSchedule{}, err
@@ -236,11 +250,12 @@ func (w *workScheduleRepositoryImpl) GetByCompanyID(ctx context.Context, company
 					ws.name,
 					ws.type,
 					ws.grace_period_minutes,
+					ws.earliest_clock_in_minutes,
 					ws.created_at,
 					ws.updated_at
 				FROM work_schedules ws
 				WHERE %s
-				ORDER BY %s %s
+				ORDER BY %s %s, ws.id
 			)
 			SELECT 
 				ps.id,
@@ -248,6 +263,7 @@ func (w *workScheduleRepositoryImpl) GetByCompanyID(ctx context.Context, company
 				ps.name,
 				ps.type,
 				ps.grace_period_minutes,
+				ps.earliest_clock_in_minutes,
 				ps.created_at,
 				ps.updated_at,
 				wst.id AS time_id,
@@ -267,15 +283,11 @@ func (w *workScheduleRepositoryImpl) GetByCompanyID(ctx context.Context, company
 			FROM all_schedules ps
 			LEFT JOIN work_schedule_times wst ON wst.work_schedule_id = ps.id
 			LEFT JOIN work_schedule_locations wsl ON wsl.work_schedule_id = ps.id
-			ORDER BY %s %s, wst.day_of_week ASC
+			ORDER BY %s %s, ps.id, wst.day_of_week ASC, wsl.id
 		`, baseWhere, orderByField, sortOrder, outerOrderByField, sortOrder)
 	} else {
 		// Pagination - apply to base table first, then join
-		limit := filter.Limit
-		if limit == 0 {
-			limit = 20
-		}
-		offset := (filter.Page - 1) * limit
+		offset, limit := pagination.Paginate(filter.Page, filter.Limit)
 
 		// Main SELECT with subquery to paginate work_schedules first, then LEFT JOIN times and locations
 		// This ensures we get all times/locations for the paginated schedules
@@ -287,11 +299,12 @@ func (w *workScheduleRepositoryImpl) GetByCompanyID(ctx context.Context, company
 					ws.name,
 					ws.type,
 					ws.grace_period_minutes,
+					ws.earliest_clock_in_minutes,
 					ws.created_at,
 					ws.updated_at
 				FROM work_schedules ws
 				WHERE %s
-				ORDER BY %s %s
+				ORDER BY %s %s, ws.id
 				LIMIT $%d OFFSET $%d
 			)
 			SELECT 
@@ -300,6 +313,7 @@ func (w *workScheduleRepositoryImpl) GetByCompanyID(ctx context.Context, company
 				ps.name,
 				ps.type,
 				ps.grace_period_minutes,
+				ps.earliest_clock_in_minutes,
 				ps.created_at,
 				ps.updated_at,
 				wst.id AS time_id,
@@ -319,7 +333,7 @@ func (w *workScheduleRepositoryImpl) GetByCompanyID(ctx context.Context, company
 			FROM paginated_schedules ps
 			LEFT JOIN work_schedule_times wst ON wst.work_schedule_id = ps.id
 			LEFT JOIN work_schedule_locations wsl ON wsl.work_schedule_id = ps.id
-			ORDER BY %s %s, wst.day_of_week ASC
+			ORDER BY %s %s, ps.id, wst.day_of_week ASC, wsl.id
 		`, baseWhere, orderByField, sortOrder, argIdx, argIdx+1, outerOrderByField, sortOrder)
 
 		args = append(args, limit, offset)
@@ -339,14 +353,14 @@ func (w *workScheduleRepositoryImpl) GetByCompanyID(ctx context.Context, company
 func (w *workScheduleRepositoryImpl) GetByID(ctx context.Context, id string, companyID string) (schedule.WorkSchedule, error) {
 	q := GetQuerier(ctx, w.db)
 	query := `
-		SELECT id, company_id, name, type, grace_period_minutes, created_at, updated_at
+		SELECT id, company_id, name, type, grace_period_minutes, earliest_clock_in_minutes, created_at, updated_at
 		FROM work_schedules
 		WHERE id = $1 AND company_id = $2 AND deleted_at IS NULL
 	`
 
 	var ws schedule.WorkSchedule
 	err := q.QueryRow(ctx, query, id, companyID).Scan(
-		&ws.ID, &ws.CompanyID, &ws.Name, &ws.Type, &ws.GracePeriodMinutes, &ws.CreatedAt, &ws.UpdatedAt,
+		&ws.ID, &ws.CompanyID, &ws.Name, &ws.Type, &ws.GracePeriodMinutes, &ws.EarliestClockInMinutes, &ws.CreatedAt, &ws.UpdatedAt,
 	)
 
 	if err != nil {
@@ -377,6 +391,11 @@ func (w *workScheduleRepositoryImpl) Update(ctx context.Context, req schedule.Up
 		args = append(args, *req.Type)
 		argIdx++
 	}
+	if req.EarliestClockInMinutes != nil {
+		updates = append(updates, fmt.Sprintf("earliest_clock_in_minutes = $%d", argIdx))
+		args = append(args, *req.EarliestClockInMinutes)
+		argIdx++
+	}
 
 	if len(updates) == 0 {
 		return schedule.WorkSchedule{}, fmt.Errorf("no updatable fields provided for work schedule update")
@@ -393,11 +412,11 @@ func (w *workScheduleRepositoryImpl) Update(ctx context.Context, req schedule.Up
 	args = append(args, req.CompanyID)
 
 	query := "UPDATE work_schedules SET " + strings.Join(updates, ", ") +
-		fmt.Sprintf(" WHERE id = $%d AND company_id = $%d RETURNING id, company_id, name, type, grace_period_minutes, created_at, updated_at", idIdx, argIdx)
+		fmt.Sprintf(" WHERE id = $%d AND company_id = $%d RETURNING id, company_id, name, type, grace_period_minutes, earliest_clock_in_minutes, created_at, updated_at", idIdx, argIdx)
 
 	var ws schedule.WorkSchedule
 	err := q.QueryRow(ctx, query, args...).Scan(
-		&ws.ID, &ws.CompanyID, &ws.Name, &ws.Type, &ws.GracePeriodMinutes, &ws.CreatedAt, &ws.UpdatedAt,
+		&ws.ID, &ws.CompanyID, &ws.Name, &ws.Type, &ws.GracePeriodMinutes, &ws.EarliestClockInMinutes, &ws.CreatedAt, &ws.UpdatedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -418,7 +437,7 @@ func (r *workScheduleRepositoryImpl) mapRowsToWorkSchedules(rows pgx.Rows, total
 
 		err := rows.Scan(
 			&raw.WorkScheduleID, &raw.CompanyID, &raw.Name, &raw.Type,
-			&raw.GracePeriodMinutes, &raw.CreatedAt, &raw.UpdatedAt,
+			&raw.GracePeriodMinutes, &raw.EarliestClockInMinutes, &raw.CreatedAt, &raw.UpdatedAt,
 			&raw.TimeID, &raw.DayOfWeek, &raw.ClockInTime, &raw.ClockOutTime,
 			&raw.BreakStartTime, &raw.BreakEndTime, &raw.LocationType,
 			&raw.TimeCreatedAt, &raw.TimeUpdatedAt,
@@ -432,15 +451,16 @@ func (r *workScheduleRepositoryImpl) mapRowsToWorkSchedules(rows pgx.Rows, total
 		ws, exists := schedulesMap[raw.WorkScheduleID]
 		if !exists {
 			ws = &schedule.WorkSchedule{
-				ID:                 raw.WorkScheduleID,
-				CompanyID:          raw.CompanyID,
-				Name:               raw.Name,
-				Type:               schedule.WorkArrangement(raw.Type),
-				GracePeriodMinutes: raw.GracePeriodMinutes,
-				CreatedAt:          raw.CreatedAt,
-				UpdatedAt:          raw.UpdatedAt,
-				Times:              []schedule.WorkScheduleTime{},
-				Locations:          []schedule.WorkScheduleLocation{},
+				ID:                     raw.WorkScheduleID,
+				CompanyID:              raw.CompanyID,
+				Name:                   raw.Name,
+				Type:                   schedule.WorkArrangement(raw.Type),
+				GracePeriodMinutes:     raw.GracePeriodMinutes,
+				EarliestClockInMinutes: raw.EarliestClockInMinutes,
+				CreatedAt:              raw.CreatedAt,
+				UpdatedAt:              raw.UpdatedAt,
+				Times:                  []schedule.WorkScheduleTime{},
+				Locations:              []schedule.WorkScheduleLocation{},
 			}
 			schedulesMap[raw.WorkScheduleID] = ws
 		}
@@ -532,8 +552,7 @@ func (w *workScheduleRepositoryImpl) SoftDelete(ctx context.Context, id, company
 func (w *workScheduleRepositoryImpl) GetEmployeeScheduleTimeline(ctx context.Context, employeeID string, companyID string, page int, limit int) ([]schedule.EmployeeScheduleTimelineItem, int64, string, error) {
 	q := GetQuerier(ctx, w.db)
 
-	// Calculate offset
-	offset := (page - 1) * limit
+	offset, limit := pagination.Paginate(page, limit)
 
 	// CTE to get employee info and validate company_id
 	// Then UNION ALL to combine default schedule and override schedules
@@ -673,6 +692,32 @@ func (w *workScheduleRepositoryImpl) GetEmployeeScheduleTimeline(ctx context.Con
 	return items, total, employeeName, nil
 }
 
+// CountActiveAssignments implements schedule.WorkScheduleRepository.
+func (w *workScheduleRepositoryImpl) CountActiveAssignments(ctx context.Context, id, companyID string) (int, error) {
+	q := GetQuerier(ctx, w.db)
+
+	query := `
+		SELECT
+			(SELECT COUNT(*)
+			 FROM employees
+			 WHERE work_schedule_id = $1 AND company_id = $2
+			   AND employment_status = 'active' AND deleted_at IS NULL)
+			+
+			(SELECT COUNT(*)
+			 FROM employee_schedule_assignments esa
+			 JOIN employees e ON e.id = esa.employee_id
+			 WHERE esa.work_schedule_id = $1 AND e.company_id = $2
+			   AND esa.end_date >= CURRENT_DATE)
+	`
+
+	var count int
+	if err := q.QueryRow(ctx, query, id, companyID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count work schedule assignments: %w", err)
+	}
+
+	return count, nil
+}
+
 func NewWorkScheduleRepository(db *database.DB) schedule.WorkScheduleRepository {
 	return &workScheduleRepositoryImpl{db: db}
 }
@@ -680,13 +725,14 @@ func NewWorkScheduleRepository(db *database.DB) schedule.WorkScheduleRepository
 // Internal DTO for query result (tidak expose ke domain)
 type workScheduleWithRelations struct {
 	// Work Schedule fields
-	WorkScheduleID     string
-	CompanyID          string
-	Name               string
-	Type               string
-	GracePeriodMinutes int
-	CreatedAt          time.Time
-	UpdatedAt          time.Time
+	WorkScheduleID         string
+	CompanyID              string
+	Name                   string
+	Type                   string
+	GracePeriodMinutes     int
+	EarliestClockInMinutes int
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
 
 	// Work Schedule Time fields (nullable karena LEFT JOIN)
 	TimeID         *string
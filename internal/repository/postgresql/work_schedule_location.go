@@ -16,7 +16,7 @@ type workScheduleLocationRepository struct {
 }
 
 // BulkDeleteByWorkScheduleID implements schedule.WorkScheduleLocationRepository.
-func (w *workScheduleLocationRepository) BulkDeleteByWorkScheduleID(ctx context.Context, workScheduleID string, companyID string) error {
+func (w *workScheduleLocationRepository) BulkDeleteByWorkScheduleID(ctx context.Context, workScheduleID string, companyID string) (int64, error) {
 	q := GetQuerier(ctx, w.db)
 
 	query := `DELETE FROM work_schedule_locations 
@@ -25,12 +25,12 @@ func (w *workScheduleLocationRepository) BulkDeleteByWorkScheduleID(ctx context.
 			WHERE id = $1 AND company_id = $2
 		)`
 
-	_, err := q.Exec(ctx, query, workScheduleID, companyID)
+	commandTag, err := q.Exec(ctx, query, workScheduleID, companyID)
 	if err != nil {
-		return fmt.Errorf("failed to bulk delete work schedule locations: %w", err)
+		return 0, fmt.Errorf("failed to bulk delete work schedule locations: %w", err)
 	}
 
-	return nil
+	return commandTag.RowsAffected(), nil
 }
 
 // Create implements schedule.WorkScheduleLocationRepository.
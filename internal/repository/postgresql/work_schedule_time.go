@@ -230,6 +230,24 @@ func (r *workScheduleTimeRepositoryImpl) Delete(ctx context.Context, id, company
 	return nil
 }
 
+// BulkDeleteByWorkScheduleID implements schedule.WorkScheduleTimeRepository.
+func (r *workScheduleTimeRepositoryImpl) BulkDeleteByWorkScheduleID(ctx context.Context, workScheduleID string, companyID string) (int64, error) {
+	q := GetQuerier(ctx, r.db)
+
+	query := `DELETE FROM work_schedule_times 
+		WHERE work_schedule_id = $1 AND EXISTS (
+			SELECT 1 FROM work_schedules 
+			WHERE id = $1 AND company_id = $2
+		)`
+
+	commandTag, err := q.Exec(ctx, query, workScheduleID, companyID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bulk delete work schedule times: %w", err)
+	}
+
+	return commandTag.RowsAffected(), nil
+}
+
 func NewWorkScheduleTimeRepository(db *database.DB) schedule.WorkScheduleTimeRepository {
 	return &workScheduleTimeRepositoryImpl{db: db}
 }
@@ -0,0 +1,63 @@
+package attendance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+)
+
+func TestAutoCloseOpenAttendance(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	at := func(d, hour int) time.Time { return time.Date(2026, 3, d, hour, 0, 0, 0, jakarta) }
+	// 09:00 in Jakarta on March 4
+	now := at(4, 9).UTC()
+
+	repo := &fakeAttendanceRepo{stale: []attendance.Attendance{
+		// Left open three days ago, when the job did not run
+		{ID: "missed", EmployeeID: "emp-1", CompanyID: "company-1", Date: day(1), WorkScheduleTimeID: stringPtr("day"), ClockIn: timePtr(at(1, 8).UTC())},
+		// Overnight shift that ended at 06:00 today
+		{ID: "overnight", EmployeeID: "emp-2", CompanyID: "company-1", Date: day(3), WorkScheduleTimeID: stringPtr("night"), ClockIn: timePtr(at(3, 22).UTC())},
+		// Overnight shift that ends at 10:00 today, still running
+		{ID: "running", EmployeeID: "emp-3", CompanyID: "company-1", Date: day(3), WorkScheduleTimeID: stringPtr("long-night"), ClockIn: timePtr(at(3, 22).UTC())},
+	}}
+	s := &AttendanceServiceImpl{
+		AttendanceRepository: repo,
+		WorkScheduleTimeRepository: &fakeScheduleTimeRepo{times: map[string]schedule.WorkScheduleTime{
+			"day":        {ID: "day", ClockInTime: wallClock(8, 0), BreakStartTime: timePtr(wallClock(12, 0)), BreakEndTime: timePtr(wallClock(13, 0)), ClockOutTime: wallClock(17, 0)},
+			"night":      {ID: "night", ClockInTime: wallClock(22, 0), ClockOutTime: wallClock(6, 0), IsNextDayCheckout: true},
+			"long-night": {ID: "long-night", ClockInTime: wallClock(22, 0), ClockOutTime: wallClock(10, 0), IsNextDayCheckout: true},
+		}},
+		BranchRepository: &fakeBranchRepo{timezone: "Asia/Jakarta"},
+		settingsRepo:     &fakeSettingsRepo{},
+	}
+
+	closed, err := s.AutoCloseOpenAttendance(context.Background(), now)
+	if err != nil {
+		t.Fatalf("AutoCloseOpenAttendance() error = %v", err)
+	}
+	if closed != 2 {
+		t.Errorf("closed = %d, want 2", closed)
+	}
+
+	want := map[string]autoClosed{
+		"missed":    {clockOut: at(1, 17).UTC(), workMinutes: 8 * 60}, // nine hours less the hour break
+		"overnight": {clockOut: at(4, 6).UTC(), workMinutes: 8 * 60},
+	}
+	for id, w := range want {
+		got, ok := repo.closed[id]
+		if !ok {
+			t.Errorf("%s was not closed", id)
+			continue
+		}
+		if !got.clockOut.Equal(w.clockOut) || got.workMinutes != w.workMinutes {
+			t.Errorf("%s closed at %v with %d minutes, want %v with %d", id, got.clockOut, got.workMinutes, w.clockOut, w.workMinutes)
+		}
+	}
+	if _, ok := repo.closed["running"]; ok {
+		t.Error("running shift was closed before its scheduled end")
+	}
+}
@@ -0,0 +1,104 @@
+package attendance
+
+import (
+	"errors"
+	"mime/multipart"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/testutil"
+)
+
+func TestClockInOnApprovedLeave(t *testing.T) {
+	annual := "annual"
+	proof := &multipart.FileHeader{Filename: "proof.jpg", Size: 1 << 10}
+	tests := []struct {
+		name        string
+		role        user.Role
+		onLeave     bool
+		override    bool
+		wantErr     error
+		wantDeleted []string
+	}{
+		// Without an active schedule a clock-in that passes the leave check ends in ErrNoScheduleFound
+		{"working day", user.RoleEmployee, false, false, attendance.ErrNoScheduleFound, nil},
+		{"approved leave day", user.RoleEmployee, true, false, attendance.ErrOnLeave, nil},
+		{"override without approve permission", user.RoleEmployee, true, true, user.ErrInsufficientPermissions, nil},
+		{"override by an approver", user.RoleManager, true, true, attendance.ErrNoScheduleFound, []string{"att-leave"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAttendanceRepo{
+				records: map[string]attendance.Attendance{},
+				onLeave: map[string]bool{"emp-1": tt.onLeave},
+			}
+			if tt.onLeave {
+				repo.records["emp-1"] = attendance.Attendance{ID: "att-leave", EmployeeID: "emp-1", LeaveTypeID: &annual}
+			}
+			a := &AttendanceServiceImpl{
+				AttendanceRepository:   repo,
+				BranchRepository:       &fakeBranchRepo{timezone: "Asia/Jakarta"},
+				WorkScheduleRepository: &fakeScheduleRepo{},
+				clock:                  clock.NewFake(time.Date(2026, 6, 1, 2, 0, 0, 0, time.UTC)),
+			}
+			ctx := testutil.ClaimsContext(t, map[string]any{"company_id": "company-1", "employee_id": "emp-1", "role": string(tt.role)})
+
+			_, err := a.ClockIn(ctx, attendance.ClockInRequest{EmployeeID: "emp-1", OverrideLeave: tt.override, FileHeader: proof})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ClockIn() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(repo.deleted, tt.wantDeleted) {
+				t.Errorf("deleted attendances = %v, want %v", repo.deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+// Jakarta is UTC+7, so half an hour either side of local midnight is the same UTC day
+func TestClockInUsesBranchLocalDate(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		now      time.Time
+		wantDate string
+	}{
+		{"23:30 local", time.Date(2026, 6, 1, 23, 30, 0, 0, jakarta), "2026-06-01"},
+		{"00:30 local", time.Date(2026, 6, 2, 0, 30, 0, 0, jakarta), "2026-06-02"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.now.UTC().Format("2006-01-02"); got != "2026-06-01" {
+				t.Fatalf("UTC date = %s, want both cases on 2026-06-01", got)
+			}
+			repo := &fakeAttendanceRepo{records: map[string]attendance.Attendance{}}
+			schedules := &fakeScheduleRepo{}
+			a := &AttendanceServiceImpl{
+				AttendanceRepository:   repo,
+				BranchRepository:       &fakeBranchRepo{timezone: "Asia/Jakarta"},
+				WorkScheduleRepository: schedules,
+				clock:                  clock.NewFake(tt.now.UTC()),
+			}
+			ctx := testutil.ClaimsContext(t, map[string]any{"company_id": "company-1", "employee_id": "emp-1", "role": string(user.RoleEmployee)})
+			proof := &multipart.FileHeader{Filename: "proof.jpg", Size: 1 << 10}
+
+			if _, err := a.ClockIn(ctx, attendance.ClockInRequest{EmployeeID: "emp-1", FileHeader: proof}); !errors.Is(err, attendance.ErrNoScheduleFound) {
+				t.Fatalf("ClockIn() error = %v, want %v", err, attendance.ErrNoScheduleFound)
+			}
+			if !slices.Equal(repo.checkedDates, []string{tt.wantDate}) {
+				t.Errorf("checked-in lookup dates = %v, want [%s]", repo.checkedDates, tt.wantDate)
+			}
+			if len(schedules.lookups) != 1 || schedules.lookups[0].Format("2006-01-02") != tt.wantDate || schedules.lookups[0].Location().String() != "Asia/Jakarta" {
+				t.Errorf("schedule lookups = %v, want one on %s in Asia/Jakarta", schedules.lookups, tt.wantDate)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package attendance
+
+import (
+	"context"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/master/branch"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/jackc/pgx/v5"
+)
+
+// autoClosed records the clock-out and worked minutes an attendance was auto-closed with
+type autoClosed struct {
+	clockOut    time.Time
+	workMinutes int
+}
+
+type fakeAttendanceRepo struct {
+	attendance.AttendanceRepository
+	stale  []attendance.Attendance
+	closed map[string]autoClosed
+
+	// records holds the day's attendance per employee; onLeave marks employees on approved leave
+	records map[string]attendance.Attendance
+	onLeave map[string]bool
+	deleted []string
+	// checkedDates lists the local dates HasCheckedInToday was asked about
+	checkedDates []string
+}
+
+func (r *fakeAttendanceRepo) GetStaleOpenSessions(context.Context, time.Time) ([]attendance.Attendance, error) {
+	return r.stale, nil
+}
+
+func (r *fakeAttendanceRepo) AutoClose(_ context.Context, id, _ string, clockOut time.Time, workMinutes int) error {
+	if r.closed == nil {
+		r.closed = map[string]autoClosed{}
+	}
+	r.closed[id] = autoClosed{clockOut: clockOut, workMinutes: workMinutes}
+	return nil
+}
+
+func (r *fakeAttendanceRepo) IsOnApprovedLeave(_ context.Context, employeeID, _, _ string) (bool, error) {
+	return r.onLeave[employeeID], nil
+}
+
+func (r *fakeAttendanceRepo) GetByEmployeeAndDate(_ context.Context, employeeID string, _ time.Time, _ string) (*attendance.Attendance, error) {
+	record, ok := r.records[employeeID]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return &record, nil
+}
+
+func (r *fakeAttendanceRepo) Delete(_ context.Context, id, _ string) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func (r *fakeAttendanceRepo) HasCheckedInToday(_ context.Context, employeeID, dateLocal, _ string) (bool, error) {
+	r.checkedDates = append(r.checkedDates, dateLocal)
+	record, ok := r.records[employeeID]
+	return ok && record.ClockIn != nil, nil
+}
+
+// fakeScheduleRepo has no active schedule for anyone, which ends a clock-in right after the leave check
+type fakeScheduleRepo struct {
+	schedule.WorkScheduleRepository
+	lookups []time.Time
+}
+
+func (r *fakeScheduleRepo) GetActiveSchedule(_ context.Context, _ string, date time.Time, _ string) (*schedule.ActiveSchedule, error) {
+	r.lookups = append(r.lookups, date)
+	return nil, pgx.ErrNoRows
+}
+
+type fakeScheduleTimeRepo struct {
+	schedule.WorkScheduleTimeRepository
+	times map[string]schedule.WorkScheduleTime
+}
+
+func (r *fakeScheduleTimeRepo) GetByID(_ context.Context, id, _ string) (schedule.WorkScheduleTime, error) {
+	st, ok := r.times[id]
+	if !ok {
+		return schedule.WorkScheduleTime{}, pgx.ErrNoRows
+	}
+	return st, nil
+}
+
+// fakeBranchRepo resolves every employee to the same timezone
+type fakeBranchRepo struct {
+	branch.BranchRepository
+	timezone string
+}
+
+func (r *fakeBranchRepo) GetTimezoneByEmployeeID(context.Context, string, string) (string, error) {
+	return r.timezone, nil
+}
+
+type fakeSettingsRepo struct {
+	attendance.AttendanceSettingsRepository
+	settings map[string]attendance.AttendanceSettings
+	err      error
+}
+
+func (r *fakeSettingsRepo) GetSettings(_ context.Context, companyID string) (attendance.AttendanceSettings, error) {
+	if r.err != nil {
+		return attendance.AttendanceSettings{}, r.err
+	}
+	settings, ok := r.settings[companyID]
+	if !ok {
+		return attendance.AttendanceSettings{}, attendance.ErrAttendanceSettingsNotFound
+	}
+	return settings, nil
+}
+
+// wallClock returns a wall-clock time for schedule fields, which only carry hour and minute
+func wallClock(hour, minute int) time.Time {
+	return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
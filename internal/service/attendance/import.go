@@ -0,0 +1,392 @@
+package attendance
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/scope"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/utils"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// importStatuses are the final statuses an imported record may carry.
+// Rows without a status get on_time/late from the clock-in, or absent without one.
+var importStatuses = []string{"on_time", "late", "absent", "on_leave", "holiday"}
+
+// importNoClockStatuses are statuses for days the employee did not work
+var importNoClockStatuses = []string{"absent", "on_leave", "holiday"}
+
+// importEmployee caches an employee lookup and its timezone across rows
+type importEmployee struct {
+	employee *employee.Employee // nil when the code is unknown
+	loc      *time.Location
+}
+
+// importContext is shared by every row of one import
+type importContext struct {
+	companyID  string
+	approverID *string
+	settings   attendance.AttendanceSettings
+	employees  map[string]importEmployee
+	seen       map[string]bool // employee_id|date already handled earlier in the file
+}
+
+// BulkImport implements attendance.AttendanceService.
+// Every row is validated and stored on its own, so one bad row never blocks the rest of the file.
+// Imported records are historical data and are stored as already approved by the importing user.
+func (a *AttendanceServiceImpl) BulkImport(ctx context.Context, companyID string, file io.Reader) (attendance.BulkImportResponse, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return attendance.BulkImportResponse{}, attendance.ErrInvalidImportFile
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	if _, ok := columns["employee_code"]; !ok {
+		return attendance.BulkImportResponse{}, attendance.ErrInvalidImportFile
+	}
+	if _, ok := columns["date"]; !ok {
+		return attendance.BulkImportResponse{}, attendance.ErrInvalidImportFile
+	}
+
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return attendance.BulkImportResponse{}, fmt.Errorf("%w: %v", attendance.ErrInvalidImportFile, err)
+		}
+		if len(records) == attendance.MaxImportRows {
+			return attendance.BulkImportResponse{}, attendance.ErrImportTooLarge
+		}
+		records = append(records, record)
+	}
+
+	settings, err := a.settingsFor(ctx, companyID)
+	if err != nil {
+		return attendance.BulkImportResponse{}, err
+	}
+
+	ic := &importContext{
+		companyID: companyID,
+		settings:  settings,
+		employees: make(map[string]importEmployee),
+		seen:      make(map[string]bool),
+	}
+	if _, claims, err := jwtauth.FromContext(ctx); err == nil {
+		if userID, ok := claims["user_id"].(string); ok && userID != "" {
+			ic.approverID = &userID
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	result := attendance.BulkImportResponse{
+		TotalRows: len(records),
+		Rows:      make([]attendance.ImportRowResult, 0, len(records)),
+	}
+
+	for i, record := range records {
+		row := a.importRow(ctx, ic, i+2,
+			field(record, "employee_code"),
+			field(record, "date"),
+			field(record, "clock_in"),
+			field(record, "clock_out"),
+			strings.ToLower(field(record, "status")),
+		)
+
+		switch row.Result {
+		case attendance.ImportRowCreated:
+			result.Created++
+		case attendance.ImportRowSkipped:
+			result.Skipped++
+		default:
+			result.Failed++
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result, nil
+}
+
+// importRow validates and stores a single row
+func (a *AttendanceServiceImpl) importRow(ctx context.Context, ic *importContext, rowNumber int, employeeCode, dateStr, clockInStr, clockOutStr, status string) attendance.ImportRowResult {
+	row := attendance.ImportRowResult{
+		Row:          rowNumber,
+		EmployeeCode: employeeCode,
+		Date:         dateStr,
+		Result:       attendance.ImportRowFailed,
+	}
+
+	if employeeCode == "" {
+		row.Message = "employee_code is required"
+		return row
+	}
+	parsedDate, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		row.Message = "date must be in YYYY-MM-DD format"
+		return row
+	}
+	if status != "" && !validator.IsInSlice(status, importStatuses) {
+		row.Message = "status must be one of: " + strings.Join(importStatuses, ", ")
+		return row
+	}
+
+	emp, err := a.importEmployee(ctx, ic, employeeCode)
+	if err != nil {
+		slog.Error("attendance import: failed to get employee", "employee_code", employeeCode, "error", err)
+		row.Message = "failed to look up employee"
+		return row
+	}
+	if emp.employee == nil {
+		row.Message = "employee not found"
+		return row
+	}
+	if branchID, scoped := scope.BranchIDFromContext(ctx); scoped && emp.employee.BranchID != branchID {
+		row.Message = "employee is outside your branch"
+		return row
+	}
+
+	loc := emp.loc
+	date := time.Date(parsedDate.Year(), parsedDate.Month(), parsedDate.Day(), 0, 0, 0, 0, loc)
+	if date.After(utils.StartOfDay(a.clock.Now(), loc)) {
+		row.Message = "date must not be in the future"
+		return row
+	}
+	hireDate := emp.employee.HireDate
+	if date.Before(time.Date(hireDate.Year(), hireDate.Month(), hireDate.Day(), 0, 0, 0, 0, loc)) {
+		row.Message = "date is before the employee's hire date"
+		return row
+	}
+
+	// Clock times are wall-clock times on the row's date in the employee's timezone
+	var clockIn, clockOut *time.Time
+	if clockInStr != "" {
+		t, ok := parseImportClock(date, clockInStr, loc)
+		if !ok {
+			row.Message = "clock_in must be in HH:MM or HH:MM:SS format"
+			return row
+		}
+		clockIn = &t
+	}
+	if clockOutStr != "" {
+		t, ok := parseImportClock(date, clockOutStr, loc)
+		if !ok {
+			row.Message = "clock_out must be in HH:MM or HH:MM:SS format"
+			return row
+		}
+		// A clock-out before the clock-in belongs to an overnight shift
+		if clockIn != nil && t.Before(*clockIn) {
+			t = t.AddDate(0, 0, 1)
+		}
+		clockOut = &t
+	}
+
+	if status == "" && clockIn == nil && clockOut == nil {
+		status = "absent"
+	}
+	if validator.IsInSlice(status, importNoClockStatuses) {
+		if clockIn != nil || clockOut != nil {
+			row.Message = fmt.Sprintf("%s rows must not have clock_in or clock_out", status)
+			return row
+		}
+	} else {
+		if clockIn == nil {
+			row.Message = "clock_in is required"
+			return row
+		}
+	}
+
+	key := emp.employee.ID + "|" + dateStr
+	if ic.seen[key] {
+		row.Result = attendance.ImportRowSkipped
+		row.Message = "duplicate of an earlier row in the file"
+		return row
+	}
+	ic.seen[key] = true
+
+	existing, err := a.AttendanceRepository.GetByEmployeeAndDate(ctx, emp.employee.ID, date, ic.companyID)
+	if err != nil {
+		slog.Error("attendance import: failed to check existing attendance", "employee_id", emp.employee.ID, "date", dateStr, "error", err)
+		row.Message = "failed to check existing attendance"
+		return row
+	}
+	if existing != nil {
+		row.Result = attendance.ImportRowSkipped
+		row.AttendanceID = &existing.ID
+		row.Message = "attendance already exists for this date"
+		return row
+	}
+
+	now := a.clock.Now()
+	record := attendance.Attendance{
+		EmployeeID: emp.employee.ID,
+		CompanyID:  ic.companyID,
+		Date:       date,
+		ApprovedBy: ic.approverID,
+		ApprovedAt: &now,
+	}
+
+	if clockIn == nil {
+		record.Status = status
+	} else {
+		if err := a.applyImportSchedule(ctx, ic, &record, *clockIn, clockOut, loc); err != nil {
+			slog.Error("attendance import: failed to apply schedule", "employee_id", emp.employee.ID, "date", dateStr, "error", err)
+			row.Message = "failed to load company calendar"
+			return row
+		}
+		if status != "" {
+			record.Status = status
+		}
+	}
+
+	created, err := a.AttendanceRepository.Create(ctx, record)
+	if err != nil {
+		slog.Error("attendance import: failed to create attendance", "employee_id", emp.employee.ID, "date", dateStr, "error", err)
+		row.Message = "failed to create attendance"
+		return row
+	}
+
+	row.Result = attendance.ImportRowCreated
+	row.AttendanceID = &created.ID
+	return row
+}
+
+// applyImportSchedule fills clock times, the derived status and late/early/overtime minutes,
+// using the employee's schedule for that day when there is one. It fails only when the company
+// calendar needed for the overtime rate cannot be read.
+func (a *AttendanceServiceImpl) applyImportSchedule(ctx context.Context, ic *importContext, record *attendance.Attendance, clockIn time.Time, clockOut *time.Time, loc *time.Location) error {
+	clockInUTC := clockIn.UTC()
+	record.ClockIn = &clockInUTC
+	record.Status = "on_time"
+
+	if clockOut != nil {
+		clockOutUTC := clockOut.UTC()
+		workMinutes := int(clockOut.Sub(clockIn).Minutes())
+		record.ClockOut = &clockOutUTC
+		record.WorkHoursInMinutes = &workMinutes
+	}
+
+	activeSchedule, err := a.WorkScheduleRepository.GetActiveSchedule(ctx, record.EmployeeID, record.Date, ic.companyID)
+	if err != nil || activeSchedule == nil {
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("attendance import: failed to get schedule", "employee_id", record.EmployeeID, "error", err)
+		}
+		return nil
+	}
+
+	record.WorkScheduleTimeID = &activeSchedule.TimeID
+	record.ActualLocationType = &activeSchedule.LocationType
+
+	scheduledIn := time.Date(
+		record.Date.Year(), record.Date.Month(), record.Date.Day(),
+		activeSchedule.ClockIn.Hour(), activeSchedule.ClockIn.Minute(), 0, 0,
+		loc,
+	)
+	graceLimit := scheduledIn.Add(time.Duration(activeSchedule.GracePeriodMinutes) * time.Minute)
+
+	rawLateMinutes := 0
+	if clockIn.After(graceLimit) {
+		record.Status = "late"
+		rawLateMinutes = int(math.Floor(clockIn.Sub(scheduledIn).Minutes()))
+	}
+	lateMinutes := ic.settings.RoundLate(rawLateMinutes)
+	record.LateMinutes = &lateMinutes
+	record.RawLateMinutes = &rawLateMinutes
+
+	if clockOut == nil {
+		return nil
+	}
+
+	// Deduct the scheduled break from the worked time set above
+	if breakStart, breakEnd, ok := schedule.ShiftBreak(record.Date, activeSchedule.ClockIn, activeSchedule.BreakStart, activeSchedule.BreakEnd, loc); ok {
+		workMinutes := schedule.WorkedMinutes(clockIn, *clockOut, breakStart, breakEnd)
+		record.WorkHoursInMinutes = &workMinutes
+	}
+
+	scheduledOut := time.Date(
+		record.Date.Year(), record.Date.Month(), record.Date.Day(),
+		activeSchedule.ClockOut.Hour(), activeSchedule.ClockOut.Minute(), 0, 0,
+		loc,
+	)
+	if activeSchedule.IsNextDayCheckout {
+		scheduledOut = scheduledOut.AddDate(0, 0, 1)
+	}
+
+	earlyLeaveMinutes, rawOvertimeMinutes := 0, 0
+	if clockOut.Before(scheduledOut) {
+		earlyLeaveMinutes = int(scheduledOut.Sub(*clockOut).Minutes())
+	} else {
+		rawOvertimeMinutes = int(clockOut.Sub(scheduledOut).Minutes())
+	}
+	overtimeMinutes := ic.settings.ApplyOvertimeRules(rawOvertimeMinutes)
+	overtimeMultiplier, err := a.overtimeMultiplier(ctx, ic.companyID, ic.settings, record.Date)
+	if err != nil {
+		return err
+	}
+	record.EarlyLeaveMinutes = &earlyLeaveMinutes
+	record.OvertimeMinutes = &overtimeMinutes
+	record.RawOvertimeMinutes = &rawOvertimeMinutes
+	record.OvertimeMultiplier = &overtimeMultiplier
+	return nil
+}
+
+// importEmployee looks an employee up by code once per import
+func (a *AttendanceServiceImpl) importEmployee(ctx context.Context, ic *importContext, employeeCode string) (importEmployee, error) {
+	if cached, ok := ic.employees[employeeCode]; ok {
+		return cached, nil
+	}
+
+	found, err := a.EmployeeRepository.GetByEmployeeCode(ctx, ic.companyID, employeeCode)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ic.employees[employeeCode] = importEmployee{}
+			return importEmployee{}, nil
+		}
+		return importEmployee{}, err
+	}
+
+	cached := importEmployee{
+		employee: &found,
+		loc:      a.employeeLocation(ctx, found.ID, ic.companyID),
+	}
+	ic.employees[employeeCode] = cached
+	return cached, nil
+}
+
+// parseImportClock combines a HH:MM[:SS] value with the row's date in loc
+func parseImportClock(date time.Time, value string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc), true
+		}
+	}
+	return time.Time{}, false
+}
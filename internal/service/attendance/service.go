@@ -4,15 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/master/branch"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/pagination"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/scope"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/utils"
 	"github.com/cmlabs-hris/hris-backend-go/internal/service/file"
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/jackc/pgx/v5"
@@ -25,8 +32,11 @@ type AttendanceServiceImpl struct {
 	schedule.WorkScheduleRepository
 	schedule.WorkScheduleTimeRepository
 	branch.BranchRepository
+	settingsRepo        attendance.AttendanceSettingsRepository
+	calendars           company.CalendarProvider
 	fileService         file.FileService
 	notificationService notification.Service
+	clock               clock.Clock
 }
 
 // timePtrToString safely converts a *time.Time to a string.
@@ -43,7 +53,7 @@ func (a *AttendanceServiceImpl) ClockIn(ctx context.Context, req attendance.Cloc
 	if err := req.Validate(); err != nil {
 		return attendance.AttendanceResponse{}, err
 	}
-	nowUTC := time.Now().UTC()
+	nowUTC := a.clock.Now().UTC()
 
 	_, claims, err := jwtauth.FromContext(ctx)
 	if err != nil {
@@ -68,13 +78,36 @@ func (a *AttendanceServiceImpl) ClockIn(ctx context.Context, req attendance.Cloc
 		return attendance.AttendanceResponse{}, fmt.Errorf("failed to get timezone by employee ID: %w", err)
 	}
 
-	loc, err := time.LoadLocation(timezoneStr)
+	loc := utils.LoadLocation(timezoneStr)
+	nowLocal := nowUTC.In(loc)
+	dateLocal := nowLocal.Format("2006-01-02")
+	todayLocal := utils.StartOfDay(nowLocal, loc)
+
+	// Approved leave blocks clock-in; an approver can override it when the leave
+	// no longer applies, replacing the day's leave record with the clock-in
+	onLeave, err := a.AttendanceRepository.IsOnApprovedLeave(ctx, employeeID, dateLocal, companyID)
 	if err != nil {
-		loc = time.UTC
+		return attendance.AttendanceResponse{}, fmt.Errorf("failed to check approved leave: %w", err)
 	}
 
-	nowLocal := nowUTC.In(loc)
-	dateLocal := nowLocal.Format("2006-01-02")
+	if onLeave {
+		if !req.OverrideLeave {
+			return attendance.AttendanceResponse{}, attendance.ErrOnLeave
+		}
+		if err := scope.Require(ctx, user.PermissionAttendanceApprove); err != nil {
+			return attendance.AttendanceResponse{}, err
+		}
+
+		leaveRecord, err := a.AttendanceRepository.GetByEmployeeAndDate(ctx, employeeID, todayLocal, companyID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return attendance.AttendanceResponse{}, fmt.Errorf("failed to get leave attendance record: %w", err)
+		}
+		if leaveRecord != nil && leaveRecord.LeaveTypeID != nil {
+			if err := a.AttendanceRepository.Delete(ctx, leaveRecord.ID, companyID); err != nil {
+				return attendance.AttendanceResponse{}, fmt.Errorf("failed to remove leave attendance record: %w", err)
+			}
+		}
+	}
 
 	hasChekedIn, err := a.AttendanceRepository.HasCheckedInToday(ctx, employeeID, dateLocal, companyID)
 	if err != nil {
@@ -98,27 +131,16 @@ func (a *AttendanceServiceImpl) ClockIn(ctx context.Context, req attendance.Cloc
 		return attendance.AttendanceResponse{}, attendance.ErrNoScheduleFound
 	}
 
-	// if activeSchedule.LocationType != "WFA" {
-	// 	isValidLocation := false
-
-	// 	for _, office := range activeSchedule.Locations {
-	// 		// Hitung jarak user ke kantor (dalam Meter)
-	// 		distanceMeters := utils.CalculateHaversineDistance(
-	// 			req.Latitude, req.Longitude,
-	// 			office.Latitude, office.Longitude,
-	// 		)
-
-	// 		// Cek apakah masuk radius
-	// 		if distanceMeters <= float64(office.RadiusMeters) {
-	// 			isValidLocation = true
-	// 			break // Keluar loop jika sudah ketemu satu yang valid
-	// 		}
-	// 	}
-
-	// 	if !isValidLocation {
-	// 		return attendance.AttendanceResponse{}, attendance.ErrOutsideAllowedRadius
-	// 	}
-	// }
+	// Resolve the office the employee clocked in at; a schedule with several
+	// locations clocks against whichever one is nearest
+	var scheduleLocationID *string
+	if activeSchedule.LocationType != "WFA" {
+		match, found := schedule.NearestLocation(activeSchedule.Locations, req.Latitude, req.Longitude)
+		// Radius is not enforced yet; clock-ins outside every location are kept without one
+		if found && match.WithinRadius {
+			scheduleLocationID = &match.Location.ID
+		}
+	}
 
 	scheduledInTime := time.Date(
 		nowLocal.Year(), nowLocal.Month(), nowLocal.Day(),
@@ -143,14 +165,23 @@ func (a *AttendanceServiceImpl) ClockIn(ctx context.Context, req attendance.Cloc
 	}
 	status = "waiting_approval"
 
-	// Validasi Early Check-In (Opsional: Cegah absen jam 2 pagi untuk shift jam 8)
-	// Misal: Max checkin 3 jam sebelum jadwal
-	earliestAllowed := scheduledInTime.Add(-1 * time.Hour)
-	if nowLocal.Before(earliestAllowed) {
-		return attendance.AttendanceResponse{}, attendance.ErrTooEarlyToCheckIn
+	// Apply the company's rounding rule, keeping the raw value for audits
+	rawLateMinutes := lateMinutes
+	settings, err := a.settingsFor(ctx, companyID)
+	if err != nil {
+		return attendance.AttendanceResponse{}, err
+	}
+	lateMinutes = settings.RoundLate(rawLateMinutes)
+
+	// Validasi Early Check-In: batas per jadwal, 0 berarti tanpa batas
+	if activeSchedule.EarliestClockInMinutes > 0 {
+		earliestAllowed := scheduledInTime.Add(-time.Duration(activeSchedule.EarliestClockInMinutes) * time.Minute)
+		if nowLocal.Before(earliestAllowed) {
+			return attendance.AttendanceResponse{}, attendance.ErrTooEarlyToCheckIn
+		}
 	}
 
-	ProofPhotoURL, err := a.fileService.UploadAttendanceProof(ctx, employeeID, nowLocal.Truncate(24*time.Hour), req.File, req.FileHeader.Filename, "CLOCK_IN")
+	ProofPhotoURL, err := a.fileService.UploadAttendanceProof(ctx, employeeID, todayLocal, req.File, req.FileHeader.Filename, "CLOCK_IN")
 	if err != nil {
 		return attendance.AttendanceResponse{}, fmt.Errorf("failed to upload attendance proof: %w", err)
 	}
@@ -161,12 +192,15 @@ func (a *AttendanceServiceImpl) ClockIn(ctx context.Context, req attendance.Cloc
 		CompanyID:  companyID,
 
 		// PENTING: Date adalah representasi "Hari Kerja", bukan timestamp
-		Date: nowLocal.Truncate(24 * time.Hour), // time.Time (trunc to day)
+		Date: todayLocal, // Tengah malam di zona waktu karyawan
 
 		// Referensi ke Rule Jadwal
 		WorkScheduleTimeID: &activeSchedule.TimeID,
 		ActualLocationType: &activeSchedule.LocationType, // WFO/WFA/Hybrid
 
+		// Lokasi kantor terdekat (nil jika di luar radius)
+		WorkScheduleLocationID: scheduleLocationID,
+
 		// Waktu Absolut (Simpan UTC!)
 		ClockIn: &nowUTC,
 
@@ -178,6 +212,7 @@ func (a *AttendanceServiceImpl) ClockIn(ctx context.Context, req attendance.Cloc
 		// Hasil Kalkulasi
 		Status:            status,
 		LateMinutes:       &lateMinutes,
+		RawLateMinutes:    &rawLateMinutes,
 		EarlyLeaveMinutes: nil, // Diisi saat checkout
 		OvertimeMinutes:   nil, // Diisi saat checkout
 	}
@@ -191,23 +226,24 @@ func (a *AttendanceServiceImpl) ClockIn(ctx context.Context, req attendance.Cloc
 	go a.notifyManagersOnClockIn(ctx, companyID, employeeID, attendanceResult.ID, nowLocal)
 
 	return attendance.AttendanceResponse{
-		ID:                attendanceResult.ID,
-		EmployeeID:        attendanceResult.EmployeeID,
-		Date:              attendanceResult.Date.Format("2006-01-02"),
-		ClockInTime:       timePtrToString(attendanceResult.ClockIn),
-		ClockOutTime:      timePtrToString(attendanceResult.ClockOut),
-		ClockInLatitude:   attendanceResult.ClockInLatitude,
-		ClockInLongitude:  attendanceResult.ClockInLongitude,
-		ClockOutLatitude:  attendanceResult.ClockOutLatitude,
-		ClockOutLongitude: attendanceResult.ClockOutLongitude,
-		ClockInProofURL:   attendanceResult.ClockInProofURL,
-		ClockOutProofURL:  attendanceResult.ClockOutProofURL,
-		WorkingHours:      nil,
-		Status:            attendanceResult.Status,
-		IsLate:            nil,
-		IsEarlyLeave:      nil,
-		LateMinutes:       attendanceResult.LateMinutes,
-		EarlyLeaveMinutes: attendanceResult.EarlyLeaveMinutes,
+		ID:                     attendanceResult.ID,
+		EmployeeID:             attendanceResult.EmployeeID,
+		Date:                   attendanceResult.Date.Format("2006-01-02"),
+		ClockInTime:            timePtrToString(attendanceResult.ClockIn),
+		ClockOutTime:           timePtrToString(attendanceResult.ClockOut),
+		WorkScheduleLocationID: attendanceResult.WorkScheduleLocationID,
+		ClockInLatitude:        attendanceResult.ClockInLatitude,
+		ClockInLongitude:       attendanceResult.ClockInLongitude,
+		ClockOutLatitude:       attendanceResult.ClockOutLatitude,
+		ClockOutLongitude:      attendanceResult.ClockOutLongitude,
+		ClockInProofURL:        attendanceResult.ClockInProofURL,
+		ClockOutProofURL:       attendanceResult.ClockOutProofURL,
+		WorkingHours:           nil,
+		Status:                 attendanceResult.Status,
+		IsLate:                 nil,
+		IsEarlyLeave:           nil,
+		LateMinutes:            attendanceResult.LateMinutes,
+		EarlyLeaveMinutes:      attendanceResult.EarlyLeaveMinutes,
 	}, nil
 }
 
@@ -260,11 +296,8 @@ func (a *AttendanceServiceImpl) ClockOut(ctx context.Context, req attendance.Clo
 		return attendance.AttendanceResponse{}, fmt.Errorf("failed to get branch location: %w", err)
 	}
 
-	loc, err := time.LoadLocation(timezoneStr)
-	if err != nil {
-		loc = time.UTC
-	}
-	nowUTC := time.Now().UTC()
+	loc := utils.LoadLocation(timezoneStr)
+	nowUTC := a.clock.Now().UTC()
 	nowLocal := nowUTC.In(loc)
 
 	scheduledOut := time.Date(
@@ -281,18 +314,30 @@ func (a *AttendanceServiceImpl) ClockOut(ctx context.Context, req attendance.Clo
 	// 5. Kalkulasi Selisih (Dalam Menit)
 	var earlyLeaveMins int
 	var overtimeMins int
+	var rawOvertimeMins int
 
-	// Cek Pulang Cepat
+	// Cek Pulang Cepat / Lembur
 	if nowUTC.Before(scheduledOut) {
 		diff := scheduledOut.Sub(nowUTC).Minutes()
 		earlyLeaveMins = int(diff)
+	} else {
+		rawOvertimeMins = int(nowUTC.Sub(scheduledOut).Minutes())
+	}
+
+	settings, err := a.settingsFor(ctx, companyID)
+	if err != nil {
+		return attendance.AttendanceResponse{}, err
+	}
+	overtimeMins = settings.ApplyOvertimeRules(rawOvertimeMins)
+	overtimeMultiplier, err := a.overtimeMultiplier(ctx, companyID, settings, attendanceData.Date)
+	if err != nil {
+		return attendance.AttendanceResponse{}, err
 	}
 
-	// Hitung Total Jam Kerja
-	workDuration := nowUTC.Sub(*attendanceData.ClockIn)
-	workHoursMins := int(workDuration.Minutes())
+	// Hitung Total Jam Kerja (dikurangi jam istirahat terjadwal)
+	workHoursMins := workedMinutes(attendanceData.Date, scheduleTime, *attendanceData.ClockIn, nowUTC, loc)
 
-	ProofPhotoURL, err := a.fileService.UploadAttendanceProof(ctx, employeeID, nowLocal.Truncate(24*time.Hour), req.File, req.FileHeader.Filename, "CLOCK_OUT")
+	ProofPhotoURL, err := a.fileService.UploadAttendanceProof(ctx, employeeID, utils.StartOfDay(nowLocal, loc), req.File, req.FileHeader.Filename, "CLOCK_OUT")
 	if err != nil {
 		return attendance.AttendanceResponse{}, fmt.Errorf("failed to upload attendance proof: %w", err)
 	}
@@ -303,6 +348,8 @@ func (a *AttendanceServiceImpl) ClockOut(ctx context.Context, req attendance.Clo
 	attendanceData.ClockOutLongitude = &req.Longitude
 	attendanceData.EarlyLeaveMinutes = &earlyLeaveMins
 	attendanceData.OvertimeMinutes = &overtimeMins
+	attendanceData.RawOvertimeMinutes = &rawOvertimeMins
+	attendanceData.OvertimeMultiplier = &overtimeMultiplier
 	attendanceData.WorkHoursInMinutes = &workHoursMins
 	attendanceData.ClockOutProofURL = req.ProofPhotoURL
 
@@ -328,7 +375,7 @@ func (a *AttendanceServiceImpl) ClockOut(ctx context.Context, req attendance.Clo
 		ClockOutLongitude: attendanceData.ClockOutLongitude,
 		ClockInProofURL:   attendanceData.ClockInProofURL,
 		ClockOutProofURL:  attendanceData.ClockOutProofURL,
-		WorkingHours:      func(v float64) *float64 { return &v }(workDuration.Minutes()),
+		WorkingHours:      func(v float64) *float64 { return &v }(float64(workHoursMins)),
 		Status:            attendanceData.Status,
 		IsLate:            nil,
 		IsEarlyLeave:      nil,
@@ -365,18 +412,14 @@ func (a *AttendanceServiceImpl) GetMyAttendance(ctx context.Context, filter atte
 		responses = append(responses, mapAttendanceToResponse(att))
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(filter.Limit)))
-	showing := fmt.Sprintf("%d-%d of %d", (filter.Page-1)*filter.Limit+1, min((filter.Page)*filter.Limit, int(total)), total)
-	if total == 0 {
-		showing = "0 of 0"
-	}
+	meta := pagination.Meta(filter.Page, filter.Limit, total)
 
 	return attendance.ListAttendanceResponse{
 		TotalCount:  total,
 		Page:        filter.Page,
 		Limit:       filter.Limit,
-		TotalPages:  totalPages,
-		Showing:     showing,
+		TotalPages:  meta.TotalPages,
+		Showing:     meta.Showing,
 		Attendances: responses,
 	}, nil
 }
@@ -393,6 +436,9 @@ func (a *AttendanceServiceImpl) ListAttendance(ctx context.Context, filter atten
 		return attendance.ListAttendanceResponse{}, fmt.Errorf("company_id claim is missing or invalid")
 	}
 
+	// Branch-scoped managers only see their own branch
+	filter.BranchID = scope.BranchFilter(ctx)
+
 	attendances, total, err := a.AttendanceRepository.List(ctx, filter, companyID)
 	if err != nil {
 		return attendance.ListAttendanceResponse{}, fmt.Errorf("failed to list attendances: %w", err)
@@ -404,22 +450,75 @@ func (a *AttendanceServiceImpl) ListAttendance(ctx context.Context, filter atten
 		responses = append(responses, mapAttendanceToResponse(att))
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(filter.Limit)))
-	showing := fmt.Sprintf("%d-%d of %d", (filter.Page-1)*filter.Limit+1, min((filter.Page)*filter.Limit, int(total)), total)
-	if total == 0 {
-		showing = "0 of 0"
-	}
+	meta := pagination.Meta(filter.Page, filter.Limit, total)
 
 	return attendance.ListAttendanceResponse{
 		TotalCount:  total,
 		Page:        filter.Page,
 		Limit:       filter.Limit,
-		TotalPages:  totalPages,
-		Showing:     showing,
+		TotalPages:  meta.TotalPages,
+		Showing:     meta.Showing,
 		Attendances: responses,
 	}, nil
 }
 
+// GetLiveBoard implements attendance.AttendanceService.
+func (a *AttendanceServiceImpl) GetLiveBoard(ctx context.Context, companyID string, filter attendance.LiveBoardFilter) (attendance.LiveBoardResponse, error) {
+	// Branch-scoped managers only see their own branch, whatever branch was requested
+	if scoped := scope.BranchFilter(ctx); scoped != nil {
+		filter.BranchID = scoped
+	}
+
+	now := a.clock.Now()
+	entries, err := a.AttendanceRepository.GetLiveBoardEntries(ctx, companyID, filter.BranchID, now)
+	if err != nil {
+		return attendance.LiveBoardResponse{}, fmt.Errorf("failed to get live board: %w", err)
+	}
+
+	resp := attendance.LiveBoardResponse{
+		GeneratedAt: now,
+		Employees:   make([]attendance.LiveBoardEmployee, 0, len(entries)),
+	}
+
+	for _, e := range entries {
+		status := attendance.LiveBoardNotClockedIn
+		switch {
+		case e.LeaveTypeName != nil:
+			status = attendance.LiveBoardOnLeave
+			resp.Summary.OnLeave++
+		case e.AttendanceStatus != nil && *e.AttendanceStatus == "absent":
+			status = attendance.LiveBoardAbsent
+			resp.Summary.Absent++
+		case e.ClockIn != nil:
+			status = attendance.LiveBoardClockedIn
+			resp.Summary.ClockedIn++
+		default:
+			resp.Summary.NotClockedIn++
+		}
+
+		resp.Employees = append(resp.Employees, attendance.LiveBoardEmployee{
+			EmployeeID:        e.EmployeeID,
+			EmployeeName:      e.EmployeeName,
+			EmployeeCode:      e.EmployeeCode,
+			PositionName:      e.PositionName,
+			BranchID:          e.BranchID,
+			BranchName:        e.BranchName,
+			Date:              e.Date.Format("2006-01-02"),
+			ScheduleName:      e.ScheduleName,
+			ScheduledClockIn:  e.ScheduledClockIn,
+			ScheduledClockOut: e.ScheduledClockOut,
+			Status:            status,
+			AttendanceID:      e.AttendanceID,
+			ClockInAt:         e.ClockInLocal,
+			ClockOutAt:        e.ClockOutLocal,
+			LeaveTypeName:     e.LeaveTypeName,
+		})
+	}
+	resp.Summary.Total = len(resp.Employees)
+
+	return resp, nil
+}
+
 // mapAttendanceToResponse converts an Attendance entity to AttendanceResponse
 func mapAttendanceToResponse(att attendance.Attendance) attendance.AttendanceResponse {
 	var employeeName string
@@ -446,27 +545,33 @@ func mapAttendanceToResponse(att attendance.Attendance) attendance.AttendanceRes
 	}
 
 	return attendance.AttendanceResponse{
-		ID:                att.ID,
-		EmployeeID:        att.EmployeeID,
-		EmployeeName:      employeeName,
-		EmployeePosition:  att.EmployeePosition,
-		Date:              att.Date.Format("2006-01-02"),
-		ClockInTime:       timePtrToString(att.ClockIn),
-		ClockOutTime:      timePtrToString(att.ClockOut),
-		ClockInLatitude:   att.ClockInLatitude,
-		ClockInLongitude:  att.ClockInLongitude,
-		ClockOutLatitude:  att.ClockOutLatitude,
-		ClockOutLongitude: att.ClockOutLongitude,
-		ClockInProofURL:   att.ClockInProofURL,
-		ClockOutProofURL:  att.ClockOutProofURL,
-		WorkingHours:      workingHours,
-		Status:            att.Status,
-		IsLate:            isLate,
-		IsEarlyLeave:      isEarlyLeave,
-		LateMinutes:       att.LateMinutes,
-		EarlyLeaveMinutes: att.EarlyLeaveMinutes,
-		CreatedAt:         att.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:         att.UpdatedAt.Format("2006-01-02 15:04:05"),
+		ID:                     att.ID,
+		EmployeeID:             att.EmployeeID,
+		EmployeeName:           employeeName,
+		EmployeePosition:       att.EmployeePosition,
+		Date:                   att.Date.Format("2006-01-02"),
+		ClockInTime:            timePtrToString(att.ClockIn),
+		ClockOutTime:           timePtrToString(att.ClockOut),
+		WorkScheduleLocationID: att.WorkScheduleLocationID,
+		ClockInLatitude:        att.ClockInLatitude,
+		ClockInLongitude:       att.ClockInLongitude,
+		ClockOutLatitude:       att.ClockOutLatitude,
+		ClockOutLongitude:      att.ClockOutLongitude,
+		ClockInProofURL:        att.ClockInProofURL,
+		ClockOutProofURL:       att.ClockOutProofURL,
+		WorkingHours:           workingHours,
+		Status:                 att.Status,
+		IsLate:                 isLate,
+		IsEarlyLeave:           isEarlyLeave,
+		LateMinutes:            att.LateMinutes,
+		RawLateMinutes:         att.RawLateMinutes,
+		EarlyLeaveMinutes:      att.EarlyLeaveMinutes,
+		OvertimeMinutes:        att.OvertimeMinutes,
+		RawOvertimeMinutes:     att.RawOvertimeMinutes,
+		OvertimeMultiplier:     att.OvertimeMultiplier,
+		AutoClosed:             att.AutoClosed,
+		CreatedAt:              att.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:              att.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
 
@@ -496,21 +601,28 @@ func (a *AttendanceServiceImpl) UpdateAttendance(ctx context.Context, req attend
 		return attendance.AttendanceResponse{}, fmt.Errorf("failed to get attendance: %w", err)
 	}
 
+	if err := a.ensureInBranchScope(ctx, att.EmployeeID); err != nil {
+		return attendance.AttendanceResponse{}, err
+	}
+
+	// Manual times are entered in the employee's local time
+	loc := a.employeeLocation(ctx, att.EmployeeID, companyID)
+
 	// Update fields based on request
 	if req.Date != nil && *req.Date != "" {
-		parsedDate, _ := time.Parse("2006-01-02", *req.Date)
+		parsedDate, _ := utils.ParseDateInLocation(*req.Date, loc)
 		att.Date = parsedDate
 	}
 
 	if req.ClockInTime != nil && *req.ClockInTime != "" {
 		// Try parsing as full datetime first, then as time only
-		clockIn, err := time.Parse("2006-01-02 15:04:05", *req.ClockInTime)
+		clockIn, err := time.ParseInLocation("2006-01-02 15:04:05", *req.ClockInTime, loc)
 		if err != nil {
 			// Try parsing as time only and combine with attendance date
 			clockInTime, err := time.Parse("15:04:05", *req.ClockInTime)
 			if err == nil {
 				clockIn = time.Date(att.Date.Year(), att.Date.Month(), att.Date.Day(),
-					clockInTime.Hour(), clockInTime.Minute(), clockInTime.Second(), 0, time.UTC)
+					clockInTime.Hour(), clockInTime.Minute(), clockInTime.Second(), 0, loc)
 			}
 		}
 		if !clockIn.IsZero() {
@@ -520,13 +632,13 @@ func (a *AttendanceServiceImpl) UpdateAttendance(ctx context.Context, req attend
 
 	if req.ClockOutTime != nil && *req.ClockOutTime != "" {
 		// Try parsing as full datetime first, then as time only
-		clockOut, err := time.Parse("2006-01-02 15:04:05", *req.ClockOutTime)
+		clockOut, err := time.ParseInLocation("2006-01-02 15:04:05", *req.ClockOutTime, loc)
 		if err != nil {
 			// Try parsing as time only and combine with attendance date
 			clockOutTime, err := time.Parse("15:04:05", *req.ClockOutTime)
 			if err == nil {
 				clockOut = time.Date(att.Date.Year(), att.Date.Month(), att.Date.Day(),
-					clockOutTime.Hour(), clockOutTime.Minute(), clockOutTime.Second(), 0, time.UTC)
+					clockOutTime.Hour(), clockOutTime.Minute(), clockOutTime.Second(), 0, loc)
 			}
 		}
 		if !clockOut.IsZero() {
@@ -561,10 +673,31 @@ func (a *AttendanceServiceImpl) UpdateAttendance(ctx context.Context, req attend
 		att.OvertimeMinutes = req.OvertimeMinutes
 	}
 
-	// Recalculate work hours if both clock in and out are present
+	// A changed date can move the record onto a weekend or holiday rate
+	if req.Date != nil {
+		settings, err := a.settingsFor(ctx, companyID)
+		if err != nil {
+			return attendance.AttendanceResponse{}, err
+		}
+		multiplier, err := a.overtimeMultiplier(ctx, companyID, settings, att.Date)
+		if err != nil {
+			return attendance.AttendanceResponse{}, err
+		}
+		att.OvertimeMultiplier = &multiplier
+	}
+
+	// Recalculate work hours if both clock in and out are present, less the scheduled break
 	if att.ClockIn != nil && att.ClockOut != nil {
-		workDuration := att.ClockOut.Sub(*att.ClockIn)
-		workHoursMins := int(workDuration.Minutes())
+		workHoursMins := int(att.ClockOut.Sub(*att.ClockIn).Minutes())
+		if att.WorkScheduleTimeID != nil {
+			scheduleTime, err := a.WorkScheduleTimeRepository.GetByID(ctx, *att.WorkScheduleTimeID, companyID)
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return attendance.AttendanceResponse{}, fmt.Errorf("failed to get work schedule time: %w", err)
+			}
+			if err == nil {
+				workHoursMins = workedMinutes(att.Date, scheduleTime, *att.ClockIn, *att.ClockOut, loc)
+			}
+		}
 		att.WorkHoursInMinutes = &workHoursMins
 	}
 
@@ -602,6 +735,10 @@ func (a *AttendanceServiceImpl) GetAttendance(ctx context.Context, id string) (a
 		return attendance.AttendanceResponse{}, fmt.Errorf("failed to get attendance: %w", err)
 	}
 
+	if err := a.ensureInBranchScope(ctx, att.EmployeeID); err != nil {
+		return attendance.AttendanceResponse{}, err
+	}
+
 	return mapAttendanceToResponse(att), nil
 }
 
@@ -631,6 +768,10 @@ func (a *AttendanceServiceImpl) ApproveAttendance(ctx context.Context, req atten
 		return attendance.AttendanceResponse{}, fmt.Errorf("failed to get attendance: %w", err)
 	}
 
+	if err := a.ensureInBranchScope(ctx, att.EmployeeID); err != nil {
+		return attendance.AttendanceResponse{}, err
+	}
+
 	// Validate that attendance hasn't already been processed
 	if att.Status == "on_time" || att.Status == "late" || att.Status == "approved" {
 		return attendance.AttendanceResponse{}, attendance.ErrAttendanceAlreadyProcessed
@@ -650,11 +791,12 @@ func (a *AttendanceServiceImpl) ApproveAttendance(ctx context.Context, req atten
 			// Get the work schedule for grace period
 			workSchedule, err := a.WorkScheduleRepository.GetByID(ctx, scheduleTime.WorkScheduleID, companyID)
 			if err == nil {
-				// Calculate scheduled clock-in time for the attendance date
+				// Calculate scheduled clock-in time for the attendance date in the employee's timezone
+				loc := a.employeeLocation(ctx, att.EmployeeID, companyID)
 				scheduledInTime := time.Date(
 					att.Date.Year(), att.Date.Month(), att.Date.Day(),
 					scheduleTime.ClockInTime.Hour(), scheduleTime.ClockInTime.Minute(), 0, 0,
-					att.ClockIn.Location(),
+					loc,
 				)
 
 				// Add grace period
@@ -673,13 +815,21 @@ func (a *AttendanceServiceImpl) ApproveAttendance(ctx context.Context, req atten
 		}
 	}
 
+	rawLateMinutes := lateMinutes
+	settings, err := a.settingsFor(ctx, companyID)
+	if err != nil {
+		return attendance.AttendanceResponse{}, err
+	}
+	lateMinutes = settings.RoundLate(rawLateMinutes)
+
 	// Update status and approver info
-	now := time.Now()
+	now := a.clock.Now()
 	att.Status = status
 	att.ApprovedBy = &userID
 	att.ApprovedAt = &now
 	att.RejectionReason = nil // Clear any rejection reason
 	att.LateMinutes = &lateMinutes
+	att.RawLateMinutes = &rawLateMinutes
 
 	// Update in repository
 	if err := a.AttendanceRepository.Update(ctx, att); err != nil {
@@ -726,6 +876,10 @@ func (a *AttendanceServiceImpl) RejectAttendance(ctx context.Context, req attend
 		return attendance.AttendanceResponse{}, fmt.Errorf("failed to get attendance: %w", err)
 	}
 
+	if err := a.ensureInBranchScope(ctx, att.EmployeeID); err != nil {
+		return attendance.AttendanceResponse{}, err
+	}
+
 	// Validate that attendance hasn't already been processed
 	if att.Status == "rejected" {
 		return attendance.AttendanceResponse{}, attendance.ErrAttendanceAlreadyProcessed
@@ -735,7 +889,7 @@ func (a *AttendanceServiceImpl) RejectAttendance(ctx context.Context, req attend
 	}
 
 	// Update status and approver info
-	now := time.Now()
+	now := a.clock.Now()
 	att.Status = "rejected"
 	att.ApprovedBy = &userID
 	att.ApprovedAt = &now
@@ -767,6 +921,19 @@ func (a *AttendanceServiceImpl) DeleteAttendance(ctx context.Context, id string)
 		return fmt.Errorf("company_id claim is missing or invalid")
 	}
 
+	if _, scoped := scope.BranchIDFromContext(ctx); scoped {
+		att, err := a.AttendanceRepository.GetByID(ctx, id, companyID)
+		if err != nil {
+			if errors.Is(err, attendance.ErrAttendanceNotFound) {
+				return attendance.ErrAttendanceNotFound
+			}
+			return fmt.Errorf("failed to get attendance: %w", err)
+		}
+		if err := a.ensureInBranchScope(ctx, att.EmployeeID); err != nil {
+			return err
+		}
+	}
+
 	if err := a.AttendanceRepository.Delete(ctx, id, companyID); err != nil {
 		if errors.Is(err, attendance.ErrAttendanceNotFound) {
 			return attendance.ErrAttendanceNotFound
@@ -777,6 +944,47 @@ func (a *AttendanceServiceImpl) DeleteAttendance(ctx context.Context, id string)
 	return nil
 }
 
+// ensureInBranchScope rejects access to an employee's attendance outside the caller's branch scope
+func (a *AttendanceServiceImpl) ensureInBranchScope(ctx context.Context, employeeID string) error {
+	branchID, scoped := scope.BranchIDFromContext(ctx)
+	if !scoped {
+		return nil
+	}
+
+	emp, err := a.EmployeeRepository.GetByID(ctx, employeeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return employee.ErrEmployeeNotFound
+		}
+		return fmt.Errorf("failed to get employee: %w", err)
+	}
+
+	if emp.BranchID != branchID {
+		return attendance.ErrUnauthorized
+	}
+
+	return nil
+}
+
+// workedMinutes is the time between clock-in and clock-out, less the scheduled break of the shift when it has one
+func workedMinutes(date time.Time, scheduleTime schedule.WorkScheduleTime, clockIn, clockOut time.Time, loc *time.Location) int {
+	breakStart, breakEnd, ok := schedule.ShiftBreak(date, scheduleTime.ClockInTime, scheduleTime.BreakStartTime, scheduleTime.BreakEndTime, loc)
+	if !ok {
+		return int(clockOut.Sub(clockIn).Minutes())
+	}
+	return schedule.WorkedMinutes(clockIn, clockOut, breakStart, breakEnd)
+}
+
+// employeeLocation resolves the timezone of the employee's branch, falling back to the
+// company timezone and finally utils.DefaultTimezone.
+func (a *AttendanceServiceImpl) employeeLocation(ctx context.Context, employeeID, companyID string) *time.Location {
+	timezoneStr, err := a.BranchRepository.GetTimezoneByEmployeeID(ctx, employeeID, companyID)
+	if err != nil {
+		return utils.LoadLocation("")
+	}
+	return utils.LoadLocation(timezoneStr)
+}
+
 // notifyManagersOnClockIn sends notifications to all managers when an employee clocks in
 func (a *AttendanceServiceImpl) notifyManagersOnClockIn(ctx context.Context, companyID, employeeID, attendanceID string, clockInTime time.Time) {
 	// Skip if notification service is not configured
@@ -813,6 +1021,7 @@ func (a *AttendanceServiceImpl) notifyManagersOnClockIn(ctx context.Context, com
 			Message:     fmt.Sprintf("%s has clocked in at %s", employeeName, clockInTime.Format("15:04")),
 			Data: map[string]interface{}{
 				"employee_id":   employeeID,
+				"employee_name": employeeName,
 				"attendance_id": attendanceID,
 				"clock_in_time": clockInTime.Format(time.RFC3339),
 			},
@@ -856,6 +1065,7 @@ func (a *AttendanceServiceImpl) notifyManagersOnClockOut(ctx context.Context, co
 			Message:     fmt.Sprintf("%s has clocked out at %s", employeeName, clockOutTime.Format("15:04")),
 			Data: map[string]interface{}{
 				"employee_id":    employeeID,
+				"employee_name":  employeeName,
 				"attendance_id":  attendanceID,
 				"clock_out_time": clockOutTime.Format(time.RFC3339),
 			},
@@ -886,8 +1096,8 @@ func (a *AttendanceServiceImpl) GetAttendanceStatus(ctx context.Context) (attend
 		return attendance.AttendanceStatusResponse{}, fmt.Errorf("failed to get timezone: %w", err)
 	}
 
-	loc, _ := time.LoadLocation(timezoneStr)
-	nowLocal := time.Now().UTC().In(loc)
+	loc := utils.LoadLocation(timezoneStr)
+	nowLocal := a.clock.Now().In(loc)
 	dateLocal := nowLocal.Format("2006-01-02")
 
 	// Check if employee has schedule today
@@ -915,7 +1125,7 @@ func (a *AttendanceServiceImpl) GetAttendanceStatus(ctx context.Context) (attend
 	// Get today's attendance if exists
 	var todayAttendance *attendance.AttendanceResponse
 	if hasCheckedIn {
-		dateTime, _ := time.Parse("2006-01-02", dateLocal)
+		dateTime := utils.StartOfDay(nowLocal, loc)
 		att, err := a.AttendanceRepository.GetByEmployeeAndDate(ctx, employeeID, dateTime, companyID)
 		if err == nil && att != nil {
 			resp := mapAttendanceToResponse(*att)
@@ -972,8 +1182,11 @@ func NewAttendanceService(
 	workScheduleRepo schedule.WorkScheduleRepository,
 	workScheduleTimeRepo schedule.WorkScheduleTimeRepository,
 	branchRepo branch.BranchRepository,
+	settingsRepo attendance.AttendanceSettingsRepository,
+	calendars company.CalendarProvider,
 	fileService file.FileService,
 	notificationService notification.Service,
+	clk clock.Clock,
 ) attendance.AttendanceService {
 	return &AttendanceServiceImpl{
 		db:                         db,
@@ -982,7 +1195,262 @@ func NewAttendanceService(
 		WorkScheduleRepository:     workScheduleRepo,
 		WorkScheduleTimeRepository: workScheduleTimeRepo,
 		BranchRepository:           branchRepo,
+		settingsRepo:               settingsRepo,
+		calendars:                  calendars,
 		fileService:                fileService,
 		notificationService:        notificationService,
+		clock:                      clk,
+	}
+}
+
+// settingsFor returns the company's attendance settings, or the defaults when it has not saved
+// any. A failed read is returned rather than silently applying the defaults.
+func (a *AttendanceServiceImpl) settingsFor(ctx context.Context, companyID string) (attendance.AttendanceSettings, error) {
+	settings, err := a.settingsRepo.GetSettings(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, attendance.ErrAttendanceSettingsNotFound) {
+			return attendance.DefaultAttendanceSettings(companyID), nil
+		}
+		return attendance.AttendanceSettings{}, fmt.Errorf("failed to get attendance settings: %w", err)
+	}
+	return settings, nil
+}
+
+// overtimeMultiplier returns the overtime pay multiplier for a record dated date, judged by the company calendar
+func (a *AttendanceServiceImpl) overtimeMultiplier(ctx context.Context, companyID string, settings attendance.AttendanceSettings, date time.Time) (float64, error) {
+	calendar, err := a.calendars.Calendar(ctx, companyID, date.Year())
+	if err != nil {
+		return 0, fmt.Errorf("failed to load company calendar: %w", err)
+	}
+	return settings.OvertimeMultiplier(date, calendar), nil
+}
+
+func mapAttendanceSettingsToResponse(s attendance.AttendanceSettings) attendance.AttendanceSettingsResponse {
+	return attendance.AttendanceSettingsResponse{
+		ID:                      s.ID,
+		CompanyID:               s.CompanyID,
+		LateRoundingMode:        string(s.LateRoundingMode),
+		LateRoundingMinutes:     s.LateRoundingMinutes,
+		OvertimeRoundingMode:    string(s.OvertimeRoundingMode),
+		OvertimeRoundingMinutes: s.OvertimeRoundingMinutes,
+		AutoCloseMaxWorkMinutes: s.AutoCloseMaxWorkMinutes,
+		OvertimeMinMinutes:      s.OvertimeMinMinutes,
+		OvertimeDailyCapMinutes: s.OvertimeDailyCapMinutes,
+		WeekendOvertimeRate:     s.WeekendOvertimeRate,
+		HolidayOvertimeRate:     s.HolidayOvertimeRate,
+	}
+}
+
+// GetSettings implements attendance.AttendanceService.
+func (a *AttendanceServiceImpl) GetSettings(ctx context.Context) (attendance.AttendanceSettingsResponse, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return attendance.AttendanceSettingsResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return attendance.AttendanceSettingsResponse{}, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	settings, err := a.settingsRepo.GetSettings(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, attendance.ErrAttendanceSettingsNotFound) {
+			return mapAttendanceSettingsToResponse(attendance.DefaultAttendanceSettings(companyID)), nil
+		}
+		return attendance.AttendanceSettingsResponse{}, err
+	}
+
+	return mapAttendanceSettingsToResponse(settings), nil
+}
+
+// UpdateSettings implements attendance.AttendanceService.
+func (a *AttendanceServiceImpl) UpdateSettings(ctx context.Context, req attendance.UpdateAttendanceSettingsRequest) (attendance.AttendanceSettingsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return attendance.AttendanceSettingsResponse{}, err
+	}
+
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return attendance.AttendanceSettingsResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return attendance.AttendanceSettingsResponse{}, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	current, err := a.settingsRepo.GetSettings(ctx, companyID)
+	if err != nil {
+		if !errors.Is(err, attendance.ErrAttendanceSettingsNotFound) {
+			return attendance.AttendanceSettingsResponse{}, err
+		}
+		current = attendance.DefaultAttendanceSettings(companyID)
+	}
+
+	// Apply updates
+	if req.LateRoundingMode != nil {
+		current.LateRoundingMode = attendance.RoundingMode(*req.LateRoundingMode)
+	}
+	if req.LateRoundingMinutes != nil {
+		current.LateRoundingMinutes = *req.LateRoundingMinutes
+	}
+	if req.OvertimeRoundingMode != nil {
+		current.OvertimeRoundingMode = attendance.RoundingMode(*req.OvertimeRoundingMode)
+	}
+	if req.OvertimeRoundingMinutes != nil {
+		current.OvertimeRoundingMinutes = *req.OvertimeRoundingMinutes
+	}
+	if req.AutoCloseMaxWorkMinutes != nil {
+		current.AutoCloseMaxWorkMinutes = *req.AutoCloseMaxWorkMinutes
+	}
+	if req.OvertimeMinMinutes != nil {
+		current.OvertimeMinMinutes = *req.OvertimeMinMinutes
+	}
+	if req.OvertimeDailyCapMinutes != nil {
+		current.OvertimeDailyCapMinutes = *req.OvertimeDailyCapMinutes
+	}
+	if req.WeekendOvertimeRate != nil {
+		current.WeekendOvertimeRate = *req.WeekendOvertimeRate
+	}
+	if req.HolidayOvertimeRate != nil {
+		current.HolidayOvertimeRate = *req.HolidayOvertimeRate
+	}
+
+	updated, err := a.settingsRepo.UpsertSettings(ctx, current)
+	if err != nil {
+		return attendance.AttendanceSettingsResponse{}, err
+	}
+
+	return mapAttendanceSettingsToResponse(updated), nil
+}
+
+// AutoCloseOpenAttendance implements attendance.AttendanceService.
+func (a *AttendanceServiceImpl) AutoCloseOpenAttendance(ctx context.Context, now time.Time) (int, error) {
+	sessions, err := a.AttendanceRepository.GetStaleOpenSessions(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get open sessions: %w", err)
+	}
+
+	settingsByCompany := make(map[string]attendance.AttendanceSettings)
+	closed := 0
+
+	for _, session := range sessions {
+		if session.WorkScheduleTimeID == nil {
+			continue
+		}
+
+		scheduleTime, err := a.WorkScheduleTimeRepository.GetByID(ctx, *session.WorkScheduleTimeID, session.CompanyID)
+		if err != nil {
+			slog.Error("Failed to get schedule time for auto-close", "attendance_id", session.ID, "error", err)
+			continue
+		}
+
+		loc := a.employeeLocation(ctx, session.EmployeeID, session.CompanyID)
+		scheduledOut := time.Date(
+			session.Date.Year(), session.Date.Month(), session.Date.Day(),
+			scheduleTime.ClockOutTime.Hour(), scheduleTime.ClockOutTime.Minute(), 0, 0,
+			loc,
+		)
+		if scheduleTime.IsNextDayCheckout {
+			scheduledOut = scheduledOut.Add(24 * time.Hour)
+		}
+
+		// The shift is still running, leave it for the employee to clock out
+		if scheduledOut.After(now) {
+			continue
+		}
+
+		settings, ok := settingsByCompany[session.CompanyID]
+		if !ok {
+			var err error
+			settings, err = a.settingsFor(ctx, session.CompanyID)
+			if err != nil {
+				slog.Error("Failed to get attendance settings for auto-close", "company_id", session.CompanyID, "error", err)
+				continue
+			}
+			settingsByCompany[session.CompanyID] = settings
+		}
+
+		// Estimate clock-out at the scheduled end, capped so a forgotten clock-out never inflates worked time
+		clockOut := scheduledOut.UTC()
+		if clockOut.Before(*session.ClockIn) {
+			clockOut = *session.ClockIn
+		}
+		maxWork := time.Duration(settings.AutoCloseMaxWorkMinutes) * time.Minute
+		if clockOut.Sub(*session.ClockIn) > maxWork {
+			clockOut = session.ClockIn.Add(maxWork)
+		}
+		workMinutes := workedMinutes(session.Date, scheduleTime, *session.ClockIn, clockOut, loc)
+
+		if err := a.AttendanceRepository.AutoClose(ctx, session.ID, session.CompanyID, clockOut, workMinutes); err != nil {
+			if !errors.Is(err, attendance.ErrAlreadyCheckedOut) {
+				slog.Error("Failed to auto-close attendance", "attendance_id", session.ID, "employee_id", session.EmployeeID, "error", err)
+			}
+			continue
+		}
+
+		a.notifyOnAutoClose(ctx, session)
+		closed++
+	}
+
+	return closed, nil
+}
+
+// notifyOnAutoClose tells the employee and the company's managers that an attendance was auto-closed
+func (a *AttendanceServiceImpl) notifyOnAutoClose(ctx context.Context, session attendance.Attendance) {
+	// Skip if notification service is not configured
+	if a.notificationService == nil {
+		return
+	}
+
+	emp, err := a.EmployeeRepository.GetByID(ctx, session.EmployeeID)
+	if err != nil {
+		return
+	}
+
+	date := session.Date.Format("2006-01-02")
+	reason := "Auto-closed: no clock-out was recorded, so it was set to the scheduled end time. Please contact your manager if this is incorrect."
+
+	if emp.UserID != nil {
+		_ = a.notificationService.QueueNotification(ctx, notification.CreateNotificationRequest{
+			CompanyID:   session.CompanyID,
+			RecipientID: *emp.UserID,
+			Type:        notification.TypeAttendanceAutoClosed,
+			Title:       "Attendance Auto-Closed",
+			Message:     fmt.Sprintf("Your attendance for %s was automatically closed", date),
+			Data: map[string]interface{}{
+				"employee_name": emp.FullName,
+				"attendance_id": session.ID,
+				"date":          date,
+				"reason":        reason,
+			},
+		})
+	}
+
+	managers, err := a.EmployeeRepository.GetManagersByCompanyID(ctx, session.CompanyID)
+	if err != nil {
+		return
+	}
+
+	for _, manager := range managers {
+		if manager.UserID == nil {
+			continue
+		}
+
+		_ = a.notificationService.QueueNotification(ctx, notification.CreateNotificationRequest{
+			CompanyID:   session.CompanyID,
+			RecipientID: *manager.UserID,
+			SenderID:    emp.UserID,
+			Type:        notification.TypeAttendanceAutoClosed,
+			Title:       "Employee Attendance Auto-Closed",
+			Message:     fmt.Sprintf("%s's attendance for %s was auto-closed", emp.FullName, date),
+			Data: map[string]interface{}{
+				"employee_id":   session.EmployeeID,
+				"employee_name": emp.FullName,
+				"attendance_id": session.ID,
+				"date":          date,
+			},
+		})
 	}
 }
@@ -0,0 +1,65 @@
+package attendance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+)
+
+func TestSettingsFor(t *testing.T) {
+	saved := attendance.AttendanceSettings{CompanyID: "company-1", LateRoundingMinutes: 15, AutoCloseMaxWorkMinutes: 600}
+	dbErr := errors.New("connection reset")
+
+	tests := []struct {
+		name    string
+		repo    *fakeSettingsRepo
+		want    attendance.AttendanceSettings
+		wantErr error
+	}{
+		{name: "saved settings", repo: &fakeSettingsRepo{settings: map[string]attendance.AttendanceSettings{"company-1": saved}}, want: saved},
+		{name: "none saved uses the defaults", repo: &fakeSettingsRepo{}, want: attendance.DefaultAttendanceSettings("company-1")},
+		{name: "read failure is returned", repo: &fakeSettingsRepo{err: dbErr}, wantErr: dbErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &AttendanceServiceImpl{settingsRepo: tt.repo}
+
+			got, err := s.settingsFor(context.Background(), "company-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("settingsFor() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("settingsFor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoCloseSkipsCompaniesWhoseSettingsCannotBeRead(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo := &fakeAttendanceRepo{stale: []attendance.Attendance{
+		{ID: "open", EmployeeID: "emp-1", CompanyID: "company-1", Date: day, WorkScheduleTimeID: stringPtr("day"), ClockIn: timePtr(day.Add(time.Hour))},
+	}}
+	s := &AttendanceServiceImpl{
+		AttendanceRepository: repo,
+		WorkScheduleTimeRepository: &fakeScheduleTimeRepo{times: map[string]schedule.WorkScheduleTime{
+			"day": {ID: "day", ClockInTime: wallClock(8, 0), ClockOutTime: wallClock(17, 0)},
+		}},
+		BranchRepository: &fakeBranchRepo{timezone: "UTC"},
+		settingsRepo:     &fakeSettingsRepo{err: errors.New("connection reset")},
+	}
+
+	// Closing with default limits could cap worked time wrongly, so the session stays open
+	closed, err := s.AutoCloseOpenAttendance(context.Background(), day.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("AutoCloseOpenAttendance() error = %v", err)
+	}
+	if closed != 0 || len(repo.closed) != 0 {
+		t.Errorf("closed = %d, want the session left for the next run", closed)
+	}
+}
@@ -0,0 +1,40 @@
+package attendance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+)
+
+func TestWorkedMinutes(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	date := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := func(d, hour, minute int) time.Time { return time.Date(2026, 3, d, hour, minute, 0, 0, jakarta).UTC() }
+
+	withBreak := schedule.WorkScheduleTime{ClockInTime: wallClock(8, 0), BreakStartTime: timePtr(wallClock(12, 0)), BreakEndTime: timePtr(wallClock(13, 0)), ClockOutTime: wallClock(17, 0)}
+	withoutBreak := schedule.WorkScheduleTime{ClockInTime: wallClock(8, 0), ClockOutTime: wallClock(17, 0)}
+	overnight := schedule.WorkScheduleTime{ClockInTime: wallClock(22, 0), BreakStartTime: timePtr(wallClock(2, 0)), BreakEndTime: timePtr(wallClock(2, 30)), ClockOutTime: wallClock(6, 0), IsNextDayCheckout: true}
+
+	tests := []struct {
+		name              string
+		scheduleTime      schedule.WorkScheduleTime
+		clockIn, clockOut time.Time
+		want              int
+	}{
+		{name: "with break, full shift", scheduleTime: withBreak, clockIn: at(2, 8, 0), clockOut: at(2, 17, 0), want: 480},
+		{name: "with break, left during the break", scheduleTime: withBreak, clockIn: at(2, 8, 0), clockOut: at(2, 12, 30), want: 240},
+		{name: "with break, morning only", scheduleTime: withBreak, clockIn: at(2, 8, 0), clockOut: at(2, 11, 0), want: 180},
+		{name: "without break, full shift", scheduleTime: withoutBreak, clockIn: at(2, 8, 0), clockOut: at(2, 17, 0), want: 540},
+		{name: "without break, partial", scheduleTime: withoutBreak, clockIn: at(2, 9, 15), clockOut: at(2, 12, 0), want: 165},
+		{name: "overnight break after midnight", scheduleTime: overnight, clockIn: at(2, 22, 0), clockOut: at(3, 6, 0), want: 450},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := workedMinutes(date, tt.scheduleTime, tt.clockIn, tt.clockOut, jakarta); got != tt.want {
+				t.Errorf("workedMinutes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -21,7 +21,8 @@ import (
 )
 
 const (
-	passwordResetTokenExpiry = 1 * time.Hour // Token expires in 1 hour
+	passwordResetTokenExpiry = 1 * time.Hour    // Token expires in 1 hour
+	oauthLinkTokenExpiry     = 15 * time.Minute // Pending OAuth links expire in 15 minutes
 )
 
 type AuthServiceImpl struct {
@@ -31,6 +32,7 @@ type AuthServiceImpl struct {
 	jwt.Service
 	postgresql.JWTRepository
 	postgresql.PasswordResetRepository
+	postgresql.OAuthLinkRepository
 	employee.EmployeeRepository
 	emailService        email.EmailService
 	frontendURL         string
@@ -44,6 +46,7 @@ func NewAuthService(
 	jwtService jwt.Service,
 	jwtRepository postgresql.JWTRepository,
 	passwordResetRepo postgresql.PasswordResetRepository,
+	oauthLinkRepo postgresql.OAuthLinkRepository,
 	employeeRepo employee.EmployeeRepository,
 	emailService email.EmailService,
 	frontendURL string,
@@ -56,6 +59,7 @@ func NewAuthService(
 		Service:                 jwtService,
 		JWTRepository:           jwtRepository,
 		PasswordResetRepository: passwordResetRepo,
+		OAuthLinkRepository:     oauthLinkRepo,
 		EmployeeRepository:      employeeRepo,
 		emailService:            emailService,
 		frontendURL:             frontendURL,
@@ -223,7 +227,7 @@ func (a *AuthServiceImpl) Login(ctx context.Context, loginReq auth.LoginRequest,
 	}
 
 	err = postgresql.WithTransaction(ctx, a.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+		txCtx := postgresql.ContextWithTx(ctx, tx)
 
 		// Get subscription claims for JWT (features + expiry)
 		subClaims := a.getSubscriptionClaims(txCtx, userData.CompanyID)
@@ -296,7 +300,7 @@ func (a *AuthServiceImpl) LoginWithEmployeeCode(ctx context.Context, loginEmploy
 	}
 
 	err = postgresql.WithTransaction(ctx, a.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+		txCtx := postgresql.ContextWithTx(ctx, tx)
 
 		// Get subscription claims for JWT (features + expiry)
 		subClaims := a.getSubscriptionClaims(txCtx, userData.CompanyID)
@@ -324,33 +328,29 @@ func (a *AuthServiceImpl) LoginWithEmployeeCode(ctx context.Context, loginEmploy
 	return tokenResponse, nil
 }
 
-// LoginWithGoogle implements auth.AuthService.
-func (a *AuthServiceImpl) LoginWithGoogle(ctx context.Context, googleEmail string, googleID string, sessionTrackReq auth.SessionTrackingRequest) (auth.TokenResponse, error) {
-	var tokenResponse auth.TokenResponse
-	var userExists bool
-
-	userData, err := a.UserRepository.GetByEmail(ctx, googleEmail)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			userExists = false
-		} else {
-			return auth.TokenResponse{}, fmt.Errorf("failed to get user data by email: %w", err)
-		}
+// LoginWithOAuth implements auth.AuthService.
+// The external identity is mapped to a user by email, so only emails the provider
+// has verified are accepted. An existing password account is never linked silently:
+// its owner confirms the link with their password via ConfirmOAuthLink.
+func (a *AuthServiceImpl) LoginWithOAuth(ctx context.Context, identity auth.OAuthIdentity, sessionTrackReq auth.SessionTrackingRequest) (auth.TokenResponse, error) {
+	if identity.Email == "" || !identity.EmailVerified {
+		return auth.TokenResponse{}, auth.ErrOAuthEmailNotVerified
 	}
 
-	if userData.ID != "" {
-		userExists = true
+	userData, err := a.UserRepository.GetByEmail(ctx, identity.Email)
+	if err != nil && err != pgx.ErrNoRows {
+		return auth.TokenResponse{}, fmt.Errorf("failed to get user data by email: %w", err)
 	}
 
 	// User does not exist so we create one
-	if !userExists {
+	if userData.ID == "" {
 		newUser := user.User{
 			CompanyID:               nil,
-			Email:                   googleEmail,
+			Email:                   identity.Email,
 			PasswordHash:            nil,
 			Role:                    user.RolePending,
-			OAuthProvider:           func(s string) *string { return &s }("google"),
-			OAuthProviderID:         &googleID,
+			OAuthProvider:           &identity.Provider,
+			OAuthProviderID:         &identity.Subject,
 			EmailVerified:           true,
 			EmailVerificationToken:  nil,
 			EmailVerificationSentAt: nil,
@@ -360,23 +360,124 @@ func (a *AuthServiceImpl) LoginWithGoogle(ctx context.Context, googleEmail strin
 			return auth.TokenResponse{}, fmt.Errorf("failed to create user: %w", err)
 		}
 
+		return a.issueTokens(ctx, userData, sessionTrackReq)
+	}
+
+	// Already linked: only the same identity may sign in
+	if userData.OAuthProvider != nil && userData.OAuthProviderID != nil {
+		if *userData.OAuthProvider != identity.Provider || *userData.OAuthProviderID != identity.Subject {
+			return auth.TokenResponse{}, auth.ErrOAuthAccountConflict
+		}
+		return a.issueTokens(ctx, userData, sessionTrackReq)
+	}
+
+	// Linking requires the email to be verified on our side too, otherwise whoever
+	// registered the address first could take over the provider's account or vice versa
+	if !userData.EmailVerified {
+		return auth.TokenResponse{}, auth.ErrOAuthLinkEmailNotVerified
 	}
 
-	// If user exists, link google account
-	if userData.OAuthProvider == nil || userData.OAuthProviderID == nil {
-		_, err := a.UserRepository.LinkGoogleAccount(ctx, googleID, userData.Email)
+	// Password account: hand out a link token for the owner to confirm
+	if userData.PasswordHash != nil {
+		expiresAt := time.Now().Add(oauthLinkTokenExpiry)
+		token, err := a.OAuthLinkRepository.CreateOAuthLinkToken(ctx, userData.ID, identity.Provider, identity.Subject, expiresAt, sessionTrackReq.IPAddress)
 		if err != nil {
-			return auth.TokenResponse{}, err
+			return auth.TokenResponse{}, fmt.Errorf("failed to create oauth link token: %w", err)
+		}
+
+		return auth.TokenResponse{}, &auth.OAuthLinkRequiredError{
+			Provider:  identity.Provider,
+			Email:     userData.Email,
+			LinkToken: token,
+		}
+	}
+
+	// Passwordless account with a verified email: nothing to confirm with, link directly
+	if _, err := a.UserRepository.LinkOAuthAccount(ctx, identity.Provider, identity.Subject, userData.Email); err != nil {
+		return auth.TokenResponse{}, err
+	}
+
+	return a.issueTokens(ctx, userData, sessionTrackReq)
+}
+
+// ConfirmOAuthLink implements auth.AuthService.
+func (a *AuthServiceImpl) ConfirmOAuthLink(ctx context.Context, req auth.ConfirmOAuthLinkRequest, sessionTrackReq auth.SessionTrackingRequest) (auth.TokenResponse, error) {
+	if err := req.Validate(); err != nil {
+		return auth.TokenResponse{}, err
+	}
+
+	linkToken, err := a.OAuthLinkRepository.GetOAuthLinkToken(ctx, req.LinkToken)
+	if err != nil {
+		return auth.TokenResponse{}, err // Already returns ErrOAuthLinkTokenNotFound
+	}
+
+	if linkToken.UsedAt != nil {
+		return auth.TokenResponse{}, auth.ErrOAuthLinkTokenUsed
+	}
+
+	if time.Now().After(linkToken.ExpiresAt) {
+		return auth.TokenResponse{}, auth.ErrOAuthLinkTokenExpired
+	}
+
+	userData, err := a.UserRepository.GetByID(ctx, linkToken.UserID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return auth.TokenResponse{}, auth.ErrUserNotFound
 		}
+		return auth.TokenResponse{}, fmt.Errorf("failed to get user by id: %w", err)
+	}
+
+	if userData.PasswordHash == nil {
+		return auth.TokenResponse{}, auth.ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(*userData.PasswordHash), []byte(req.Password)); err != nil {
+		return auth.TokenResponse{}, auth.ErrInvalidCredentials
+	}
+
+	// Re-check both conditions: the account may have changed since the token was issued
+	if !userData.EmailVerified {
+		return auth.TokenResponse{}, auth.ErrOAuthLinkEmailNotVerified
+	}
+	if userData.OAuthProvider != nil && userData.OAuthProviderID != nil {
+		return auth.TokenResponse{}, auth.ErrOAuthAccountConflict
 	}
 
-	// Generate token
 	err = postgresql.WithTransaction(ctx, a.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		if _, err := a.UserRepository.LinkOAuthAccount(txCtx, linkToken.Provider, linkToken.ProviderSubject, userData.Email); err != nil {
+			return fmt.Errorf("failed to link oauth account: %w", err)
+		}
+
+		if err := a.OAuthLinkRepository.MarkOAuthLinkTokenUsed(txCtx, req.LinkToken); err != nil {
+			return fmt.Errorf("failed to mark oauth link token as used: %w", err)
+		}
+
+		if err := a.OAuthLinkRepository.InvalidateAllUserLinkTokens(txCtx, userData.ID); err != nil {
+			return fmt.Errorf("failed to invalidate other oauth link tokens: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return auth.TokenResponse{}, err
+	}
+
+	slog.Info("OAuth identity linked to existing account", "user_id", userData.ID, "provider", linkToken.Provider)
+	return a.issueTokens(ctx, userData, sessionTrackReq)
+}
+
+// issueTokens creates an access/refresh token pair and stores the refresh token
+func (a *AuthServiceImpl) issueTokens(ctx context.Context, userData user.User, sessionTrackReq auth.SessionTrackingRequest) (auth.TokenResponse, error) {
+	var tokenResponse auth.TokenResponse
+
+	err := postgresql.WithTransaction(ctx, a.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
 
 		// Get subscription claims for JWT (features + expiry)
 		subClaims := a.getSubscriptionClaims(txCtx, userData.CompanyID)
 
+		var err error
 		tokenResponse.AccessToken, tokenResponse.AccessTokenExpiresIn, err = a.Service.GenerateAccessToken(userData.ID, userData.Email, userData.EmployeeID, userData.CompanyID, userData.Role, subClaims)
 		if err != nil {
 			return fmt.Errorf("failed to create access token: %w", err)
@@ -402,7 +503,7 @@ func (a *AuthServiceImpl) LoginWithGoogle(ctx context.Context, googleEmail strin
 // Logout implements auth.AuthService.
 func (a *AuthServiceImpl) Logout(ctx context.Context, token string) error {
 	err := postgresql.WithTransaction(ctx, a.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+		txCtx := postgresql.ContextWithTx(ctx, tx)
 
 		_, isRevoked, err := a.JWTRepository.IsRefreshTokenRevoked(txCtx, token)
 		if err != nil {
@@ -515,7 +616,7 @@ func (a *AuthServiceImpl) Register(ctx context.Context, registerReq auth.Registe
 	}
 
 	err = postgresql.WithTransaction(ctx, a.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+		txCtx := postgresql.ContextWithTx(ctx, tx)
 
 		// New user has no company yet, so no subscription claims
 		tokenResponse.AccessToken, tokenResponse.AccessTokenExpiresIn, err = a.Service.GenerateAccessToken(newUser.ID, newUser.Email, nil, nil, newUser.Role, nil)
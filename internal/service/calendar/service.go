@@ -0,0 +1,67 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+)
+
+// Service builds company calendars from their holidays. Calendars are cached per company and
+// year until Invalidate is called for the company.
+type Service struct {
+	holidayRepo company.HolidayRepository
+
+	mu    sync.RWMutex
+	cache map[key]company.Calendar
+}
+
+// key identifies one cached company calendar
+type key struct {
+	companyID string
+	year      int
+}
+
+func NewService(holidayRepo company.HolidayRepository) *Service {
+	return &Service{
+		holidayRepo: holidayRepo,
+		cache:       make(map[key]company.Calendar),
+	}
+}
+
+// Calendar implements company.CalendarProvider.
+func (s *Service) Calendar(ctx context.Context, companyID string, year int) (company.Calendar, error) {
+	k := key{companyID: companyID, year: year}
+
+	s.mu.RLock()
+	cached, ok := s.cache[k]
+	s.mu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	holidays, err := s.holidayRepo.GetByYear(ctx, companyID, year)
+	if err != nil {
+		return company.Calendar{}, fmt.Errorf("failed to get holidays: %w", err)
+	}
+	calendar := company.NewCalendar(year, holidays)
+
+	s.mu.Lock()
+	s.cache[k] = calendar
+	s.mu.Unlock()
+
+	return calendar, nil
+}
+
+// Invalidate drops the company's cached calendars
+func (s *Service) Invalidate(companyID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k := range s.cache {
+		if k.companyID == companyID {
+			delete(s.cache, k)
+		}
+	}
+}
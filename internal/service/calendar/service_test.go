@@ -0,0 +1,69 @@
+package calendar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+)
+
+// fakeHolidayRepo keeps holidays per company and counts lookups
+type fakeHolidayRepo struct {
+	company.HolidayRepository
+	holidays map[string][]company.Holiday
+	lookups  int
+}
+
+func (r *fakeHolidayRepo) GetByYear(_ context.Context, companyID string, year int) ([]company.Holiday, error) {
+	r.lookups++
+	var inYear []company.Holiday
+	for _, h := range r.holidays[companyID] {
+		if h.Date.Year() == year {
+			inYear = append(inYear, h)
+		}
+	}
+	return inYear, nil
+}
+
+func TestServiceCalendar(t *testing.T) {
+	independenceDay := time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC) // a Monday
+	repo := &fakeHolidayRepo{holidays: map[string][]company.Holiday{
+		"company-1": {{Date: independenceDay, Name: "Independence Day"}},
+	}}
+	s := NewService(repo)
+	ctx := context.Background()
+
+	calendar, err := s.Calendar(ctx, "company-1", 2026)
+	if err != nil {
+		t.Fatalf("Calendar() error = %v", err)
+	}
+	if calendar.IsWorkingDay(independenceDay) {
+		t.Error("the holiday is a working day")
+	}
+	if !calendar.IsWorkingDay(independenceDay.AddDate(0, 0, 1)) {
+		t.Error("the day after the holiday is not a working day")
+	}
+
+	other, err := s.Calendar(ctx, "company-2", 2026)
+	if err != nil {
+		t.Fatalf("Calendar() error = %v", err)
+	}
+	if !other.IsWorkingDay(independenceDay) {
+		t.Error("another company's holiday applies")
+	}
+
+	// Cached until invalidated
+	repo.holidays["company-1"] = nil
+	if calendar, _ = s.Calendar(ctx, "company-1", 2026); calendar.IsWorkingDay(independenceDay) || repo.lookups != 2 {
+		t.Errorf("lookups = %d, want the cached calendar", repo.lookups)
+	}
+
+	s.Invalidate("company-1")
+	if calendar, _ = s.Calendar(ctx, "company-1", 2026); !calendar.IsWorkingDay(independenceDay) {
+		t.Error("the deleted holiday still applies after Invalidate")
+	}
+	if _, _ = s.Calendar(ctx, "company-2", 2026); repo.lookups != 3 {
+		t.Errorf("lookups = %d, want 3: invalidating one company must keep the others cached", repo.lookups)
+	}
+}
@@ -0,0 +1,94 @@
+package company
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetCalendar implements company.CompanyService.
+func (c *CompanyServiceImpl) GetCalendar(ctx context.Context, companyID string, year int) (company.CalendarResponse, error) {
+	companyData, err := c.CompanyRepository.GetByID(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return company.CalendarResponse{}, company.ErrCompanyNotFound
+		}
+		return company.CalendarResponse{}, fmt.Errorf("failed to get company by ID: %w", err)
+	}
+
+	if year == 0 {
+		year = time.Now().In(utils.LoadLocation(companyData.Timezone)).Year()
+	}
+
+	calendar, err := c.calendars.Calendar(ctx, companyID, year)
+	if err != nil {
+		return company.CalendarResponse{}, err
+	}
+
+	return mapCalendarToResponse(calendar), nil
+}
+
+// InvalidateCalendar implements company.CompanyService.
+func (c *CompanyServiceImpl) InvalidateCalendar(companyID string) {
+	c.calendars.Invalidate(companyID)
+}
+
+// CreateHoliday implements company.CompanyService.
+func (c *CompanyServiceImpl) CreateHoliday(ctx context.Context, companyID string, req company.CreateHolidayRequest) (company.HolidayResponse, error) {
+	if err := req.Validate(); err != nil {
+		return company.HolidayResponse{}, err
+	}
+	date, _ := time.Parse("2006-01-02", req.Date)
+
+	created, err := c.holidayRepo.Create(ctx, company.Holiday{CompanyID: companyID, Date: date, Name: strings.TrimSpace(req.Name), IsRecurring: req.IsRecurring})
+	if err != nil {
+		return company.HolidayResponse{}, err
+	}
+	c.InvalidateCalendar(companyID)
+
+	return mapHolidayToResponse(created), nil
+}
+
+// DeleteHoliday implements company.CompanyService.
+func (c *CompanyServiceImpl) DeleteHoliday(ctx context.Context, companyID string, id string) error {
+	if _, err := c.holidayRepo.Delete(ctx, companyID, id); err != nil {
+		return err
+	}
+	c.InvalidateCalendar(companyID)
+
+	return nil
+}
+
+func mapHolidayToResponse(h company.Holiday) company.HolidayResponse {
+	return company.HolidayResponse{ID: h.ID, Date: h.Date.Format("2006-01-02"), Name: h.Name, IsRecurring: h.IsRecurring}
+}
+
+func mapCalendarToResponse(calendar company.Calendar) company.CalendarResponse {
+	weekdays := make([]company.CalendarWeekday, 0, len(calendar.WorkingWeekdays))
+	for _, day := range calendar.WorkingWeekdays {
+		// ISO weekday 7 is Sunday, which time.Weekday numbers 0
+		dayOfWeek := int(day)
+		if day == time.Sunday {
+			dayOfWeek = 7
+		}
+		weekdays = append(weekdays, company.CalendarWeekday{DayOfWeek: dayOfWeek, DayName: day.String()})
+	}
+
+	holidays := make([]company.HolidayResponse, 0, len(calendar.Holidays))
+	for _, h := range calendar.Holidays {
+		holidays = append(holidays, mapHolidayToResponse(h))
+	}
+
+	return company.CalendarResponse{
+		Year:             calendar.Year,
+		WorkingWeekdays:  weekdays,
+		Holidays:         holidays,
+		TotalWorkingDays: calendar.WorkingDays(),
+	}
+}
@@ -0,0 +1,85 @@
+package company
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+	calendarservice "github.com/cmlabs-hris/hris-backend-go/internal/service/calendar"
+)
+
+// fakeHolidayRepo keeps one company's holidays in memory
+type fakeHolidayRepo struct {
+	company.HolidayRepository
+	holidays []company.Holiday
+}
+
+func (r *fakeHolidayRepo) GetByYear(context.Context, string, int) ([]company.Holiday, error) {
+	return r.holidays, nil
+}
+
+func (r *fakeHolidayRepo) Create(_ context.Context, holiday company.Holiday) (company.Holiday, error) {
+	for _, h := range r.holidays {
+		if h.Date.Equal(holiday.Date) {
+			return company.Holiday{}, company.ErrHolidayExists
+		}
+	}
+	holiday.ID = "holiday-1"
+	r.holidays = append(r.holidays, holiday)
+	return holiday, nil
+}
+
+func (r *fakeHolidayRepo) Delete(_ context.Context, _ string, id string) (company.Holiday, error) {
+	for i, h := range r.holidays {
+		if h.ID == id {
+			r.holidays = append(r.holidays[:i], r.holidays[i+1:]...)
+			return h, nil
+		}
+	}
+	return company.Holiday{}, company.ErrHolidayNotFound
+}
+
+func TestHolidayWritesInvalidateTheCalendar(t *testing.T) {
+	holidays := &fakeHolidayRepo{}
+	calendars := calendarservice.NewService(holidays)
+	s := &CompanyServiceImpl{holidayRepo: holidays, calendars: calendars}
+	ctx := context.Background()
+	day := time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC)
+
+	isWorkingDay := func() bool {
+		t.Helper()
+		calendar, err := calendars.Calendar(ctx, "company-1", 2026)
+		if err != nil {
+			t.Fatalf("Calendar() error = %v", err)
+		}
+		return calendar.IsWorkingDay(day)
+	}
+
+	if !isWorkingDay() {
+		t.Fatal("17 August is a holiday before one was added")
+	}
+
+	created, err := s.CreateHoliday(ctx, "company-1", company.CreateHolidayRequest{Date: "2026-08-17", Name: " Independence Day "})
+	if err != nil {
+		t.Fatalf("CreateHoliday() error = %v", err)
+	}
+	if created.ID != "holiday-1" || created.Date != "2026-08-17" || created.Name != "Independence Day" {
+		t.Errorf("CreateHoliday() = %+v", created)
+	}
+	if isWorkingDay() {
+		t.Error("the cached calendar misses the new holiday")
+	}
+
+	if _, err := s.CreateHoliday(ctx, "company-1", company.CreateHolidayRequest{Date: "2026-08-17", Name: "Again"}); !errors.Is(err, company.ErrHolidayExists) {
+		t.Errorf("CreateHoliday() on the same date error = %v, want %v", err, company.ErrHolidayExists)
+	}
+
+	if err := s.DeleteHoliday(ctx, "company-1", "holiday-1"); err != nil {
+		t.Fatalf("DeleteHoliday() error = %v", err)
+	}
+	if !isWorkingDay() {
+		t.Error("the cached calendar keeps the deleted holiday")
+	}
+}
@@ -0,0 +1,53 @@
+package company
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetOnboardingStatus implements company.CompanyService.
+// Every step is derived from the company's own data, so the checklist stays accurate however the
+// data was created (seeding, the UI, imports).
+func (c *CompanyServiceImpl) GetOnboardingStatus(ctx context.Context, companyID string) (company.OnboardingStatusResponse, error) {
+	if _, err := c.CompanyRepository.GetByID(ctx, companyID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return company.OnboardingStatusResponse{}, company.ErrCompanyNotFound
+		}
+		return company.OnboardingStatusResponse{}, fmt.Errorf("failed to get company by ID: %w", err)
+	}
+
+	counts, err := c.CompanyRepository.GetOnboardingCounts(ctx, companyID)
+	if err != nil {
+		return company.OnboardingStatusResponse{}, err
+	}
+
+	// The owner is the company's first employee, so inviting someone means more than one
+	invitedEmployees := max(counts.ActiveEmployees-1, 0)
+
+	steps := []company.OnboardingStep{
+		{Key: company.OnboardingStepBranches, Title: "Add your branches", Count: counts.Branches},
+		{Key: company.OnboardingStepPositions, Title: "Set up positions", Count: counts.Positions},
+		{Key: company.OnboardingStepLeaveTypes, Title: "Configure leave types", Count: counts.LeaveTypes},
+		{Key: company.OnboardingStepWorkSchedules, Title: "Create work schedules", Count: counts.WorkSchedules},
+		{Key: company.OnboardingStepEmployees, Title: "Invite employees", Count: invitedEmployees},
+	}
+
+	result := company.OnboardingStatusResponse{TotalSteps: len(steps)}
+	for i := range steps {
+		steps[i].Completed = steps[i].Count > 0
+		if steps[i].Completed {
+			result.CompletedSteps++
+		} else if result.NextStep == nil {
+			next := steps[i].Key
+			result.NextStep = &next
+		}
+	}
+	result.Steps = steps
+	result.IsComplete = result.CompletedSteps == result.TotalSteps
+
+	return result, nil
+}
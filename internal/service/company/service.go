@@ -22,6 +22,7 @@ import (
 	"github.com/cmlabs-hris/hris-backend-go/internal/fixtures"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
 	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
+	calendarservice "github.com/cmlabs-hris/hris-backend-go/internal/service/calendar"
 	"github.com/cmlabs-hris/hris-backend-go/internal/service/file"
 	leaveservice "github.com/cmlabs-hris/hris-backend-go/internal/service/leave"
 	"github.com/go-chi/jwtauth/v5"
@@ -49,6 +50,10 @@ type CompanyServiceImpl struct {
 
 	// Subscription service for creating trial on company creation
 	subscriptionService subscription.SubscriptionService
+
+	// Company holidays and the calendars built from them
+	holidayRepo company.HolidayRepository
+	calendars   *calendarservice.Service
 }
 
 // UploadCompanyLogo implements company.CompanyService.
@@ -72,7 +77,7 @@ func (c *CompanyServiceImpl) UploadCompanyLogo(ctx context.Context, req company.
 		return company.UploadCompanyLogoResponse{}, fmt.Errorf("failed to update company logo URL: %w", err)
 	}
 
-	attachmentURL, _ := c.fileService.GetFileURL(ctx, logoURLResult, 0)
+	attachmentURL, _ := c.fileService.GetFileURL(ctx, logoURLResult, c.fileService.URLExpiry().Default)
 
 	return company.UploadCompanyLogoResponse{LogoURL: attachmentURL}, nil
 }
@@ -82,7 +87,7 @@ func (c *CompanyServiceImpl) UploadCompanyLogo(ctx context.Context, req company.
 func (c *CompanyServiceImpl) Create(ctx context.Context, req company.CreateCompanyRequest) (company.Company, error) {
 	var newCompany company.Company
 	err := postgresql.WithTransaction(ctx, c.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+		txCtx := postgresql.ContextWithTx(ctx, tx)
 		_, err := c.CompanyRepository.GetByUsername(txCtx, req.Username)
 		if err != nil {
 			if !errors.Is(err, pgx.ErrNoRows) {
@@ -116,7 +121,7 @@ func (c *CompanyServiceImpl) Create(ctx context.Context, req company.CreateCompa
 			if err != nil {
 				return fmt.Errorf("failed to upload company logo attachment: %w", err)
 			}
-			attachmentURL, _ = c.fileService.GetFileURL(ctx, attachmentURL, 0)
+			attachmentURL, _ = c.fileService.GetFileURL(ctx, attachmentURL, c.fileService.URLExpiry().Default)
 			req.AttachmentURL = &attachmentURL
 		}
 		newCompany, err = c.CompanyRepository.Create(txCtx, company.Company{
@@ -150,7 +155,7 @@ func (c *CompanyServiceImpl) Create(ctx context.Context, req company.CreateCompa
 		}
 
 		// Seed default master data for the new company
-		seededIDs, err := c.seedDefaultData(txCtx, newCompany.ID, newCompany.Name)
+		seededIDs, err := c.seedDefaultData(txCtx, newCompany.ID, newCompany.Name, req)
 		if err != nil {
 			slog.Error("Failed to seed default data for company", "company_id", newCompany.ID, "error", err)
 			return fmt.Errorf("failed to seed default data: %w", err)
@@ -186,7 +191,11 @@ func (c *CompanyServiceImpl) Create(ctx context.Context, req company.CreateCompa
 		slog.Info("Created owner employee", "company_id", newCompany.ID, "user_id", userID, "employee_id", createdOwnerEmployee.ID)
 
 		// Assign leave quotas for the owner based on eligible leave types
-		assignedQuotas, err := c.quotaService.AssignLeaveQuotasForEmployee(txCtx, createdOwnerEmployee, time.Now().Year())
+		var assignedQuotas []leave.LeaveQuota
+		year, err := c.quotaService.CurrentLeaveYear(txCtx, newCompany.ID)
+		if err == nil {
+			assignedQuotas, err = c.quotaService.AssignLeaveQuotasForEmployee(txCtx, createdOwnerEmployee, year)
+		}
 		if err != nil {
 			slog.Warn("Failed to assign leave quotas for owner", "employee_id", createdOwnerEmployee.ID, "error", err)
 			// Don't fail the transaction, just log the warning
@@ -196,7 +205,11 @@ func (c *CompanyServiceImpl) Create(ctx context.Context, req company.CreateCompa
 
 		// Create trial subscription for the new company
 		if c.subscriptionService != nil {
-			_, err = c.subscriptionService.CreateTrialSubscription(txCtx, newCompany.ID)
+			signupSource := ""
+			if req.SignupSource != nil {
+				signupSource = *req.SignupSource
+			}
+			_, err = c.subscriptionService.CreateTrialSubscription(txCtx, newCompany.ID, signupSource)
 			if err != nil {
 				return fmt.Errorf("failed to create trial subscription: %w", err)
 			}
@@ -218,7 +231,7 @@ func (c *CompanyServiceImpl) Create(ctx context.Context, req company.CreateCompa
 }
 
 // seedDefaultData creates default master data for a newly created company and returns the IDs
-func (c *CompanyServiceImpl) seedDefaultData(ctx context.Context, companyID string, companyName string) (*fixtures.SeededDataIDs, error) {
+func (c *CompanyServiceImpl) seedDefaultData(ctx context.Context, companyID string, companyName string, req company.CreateCompanyRequest) (*fixtures.SeededDataIDs, error) {
 	seededIDs := fixtures.NewSeededDataIDs()
 
 	// 1. Seed default positions
@@ -256,17 +269,21 @@ func (c *CompanyServiceImpl) seedDefaultData(ctx context.Context, companyID stri
 		slog.Info("Seeded default branch", "company_id", companyID, "branch", defaultBranch.Name)
 	}
 
-	// 4. Seed default leave types (Indonesian labor law compliant)
-	leaveTypes := fixtures.GetDefaultLeaveTypes(companyID)
-	for _, lt := range leaveTypes {
-		createdLT, err := c.leaveTypeRepo.Create(ctx, lt)
+	// 4. Seed default leave types (Indonesian labor law compliant unless another template was picked)
+	if req.SkipDefaultLeaveTypes {
+		slog.Info("Skipped default leave types", "company_id", companyID)
+	} else {
+		seeded, err := c.seedLeaveTypes(ctx, companyID, req.LeaveTypeTemplate)
 		if err != nil {
-			slog.Warn("Failed to create default leave type", "leave_type", lt.Name, "error", err)
-		} else if lt.Code != nil {
-			seededIDs.LeaveTypeIDs[*lt.Code] = createdLT.ID
+			return nil, err
 		}
+		for _, lt := range seeded {
+			if lt.Code != nil {
+				seededIDs.LeaveTypeIDs[*lt.Code] = lt.ID
+			}
+		}
+		slog.Info("Seeded default leave types", "company_id", companyID, "template", req.LeaveTypeTemplate, "count", len(seeded))
 	}
-	slog.Info("Seeded default leave types", "company_id", companyID, "count", len(leaveTypes))
 
 	// 5. Seed all default work schedules (Standard, Night Shift, Afternoon Shift, Flexible)
 	allSchedules := fixtures.GetAllDefaultWorkSchedules(companyID)
@@ -304,6 +321,97 @@ func (c *CompanyServiceImpl) seedDefaultData(ctx context.Context, companyID stri
 	return seededIDs, nil
 }
 
+// SeedDefaultLeaveTypes implements company.CompanyService.
+// Adds a template's leave types to an existing company and allocates this year's quota for them
+// to its active employees. Types already present are skipped, so it is safe to run again.
+func (c *CompanyServiceImpl) SeedDefaultLeaveTypes(ctx context.Context, companyID string, req company.SeedLeaveTypesRequest) (company.SeedLeaveTypesResponse, error) {
+	result := company.SeedLeaveTypesResponse{Template: req.Template, Created: []string{}, Skipped: []string{}}
+
+	err := postgresql.WithTransaction(ctx, c.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		seeded, err := c.seedLeaveTypes(txCtx, companyID, req.Template)
+		if err != nil {
+			return err
+		}
+
+		created := make(map[string]bool, len(seeded))
+		for _, lt := range seeded {
+			created[lt.Name] = true
+			result.Created = append(result.Created, lt.Name)
+
+			if lt.HasQuota != nil && *lt.HasQuota {
+				year, err := c.quotaService.CurrentLeaveYear(txCtx, companyID)
+				if err != nil {
+					return err
+				}
+				if err := c.quotaService.AllocateTypeQuota(txCtx, lt, companyID, year); err != nil {
+					return fmt.Errorf("failed to allocate quota for %s: %w", lt.Name, err)
+				}
+			}
+		}
+		for _, lt := range fixtures.GetLeaveTypeTemplate(companyID, req.Template) {
+			if !created[lt.Name] {
+				result.Skipped = append(result.Skipped, lt.Name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return company.SeedLeaveTypesResponse{}, err
+	}
+
+	return result, nil
+}
+
+// seedLeaveTypes creates the template's leave types that the company does not have yet,
+// matching on code first and name second, and returns the ones it created
+func (c *CompanyServiceImpl) seedLeaveTypes(ctx context.Context, companyID string, template string) ([]leave.LeaveType, error) {
+	if template == "" {
+		template = company.LeaveTypeTemplateIndonesia
+	}
+
+	var created []leave.LeaveType
+	for _, lt := range fixtures.GetLeaveTypeTemplate(companyID, template) {
+		exists, err := c.leaveTypeExists(ctx, companyID, lt)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			continue
+		}
+
+		createdLT, err := c.leaveTypeRepo.Create(ctx, lt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create default leave type %s: %w", lt.Name, err)
+		}
+		created = append(created, createdLT)
+	}
+
+	return created, nil
+}
+
+func (c *CompanyServiceImpl) leaveTypeExists(ctx context.Context, companyID string, lt leave.LeaveType) (bool, error) {
+	if lt.Code != nil {
+		_, err := c.leaveTypeRepo.GetByCode(ctx, companyID, *lt.Code)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return false, fmt.Errorf("failed to get leave type by code: %w", err)
+		}
+	}
+
+	_, err := c.leaveTypeRepo.GetByName(ctx, companyID, lt.Name)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return false, fmt.Errorf("failed to get leave type by name: %w", err)
+	}
+	return false, nil
+}
+
 // Delete implements company.CompanyService.
 func (c *CompanyServiceImpl) Delete(ctx context.Context, id string) error {
 	if err := c.CompanyRepository.Delete(ctx, id); err != nil {
@@ -328,19 +436,23 @@ func (c *CompanyServiceImpl) GetByID(ctx context.Context, id string) (company.Co
 	// Generate attachment URL if exists
 	var attachmentURL *string
 	if companyData.LogoURL != nil && *companyData.LogoURL != "" {
-		fullURL, err := c.fileService.GetFileURL(ctx, *companyData.LogoURL, 0)
+		fullURL, err := c.fileService.GetFileURL(ctx, *companyData.LogoURL, c.fileService.URLExpiry().Default)
 		if err == nil {
 			attachmentURL = &fullURL
 		}
 	}
 	return company.CompanyResponse{
-		ID:        companyData.ID,
-		Name:      companyData.Name,
-		Username:  companyData.Username,
-		Address:   companyData.Address,
-		LogoURL:   attachmentURL,
-		CreatedAt: companyData.CreatedAt,
-		UpdatedAt: companyData.UpdatedAt,
+		ID:                    companyData.ID,
+		Name:                  companyData.Name,
+		Username:              companyData.Username,
+		Address:               companyData.Address,
+		LogoURL:               attachmentURL,
+		Timezone:              companyData.Timezone,
+		LeaveYearStartMonth:   companyData.LeaveYearStartMonth,
+		LeaveApprovalSLAHours: companyData.LeaveApprovalSLAHours,
+		LeaveEscalationAction: companyData.LeaveEscalationAction,
+		CreatedAt:             companyData.CreatedAt,
+		UpdatedAt:             companyData.UpdatedAt,
 	}, nil
 }
 
@@ -374,6 +486,8 @@ func NewCompanyService(
 	quotaService *leaveservice.QuotaService,
 	notificationRepo notification.Repository,
 	subscriptionService subscription.SubscriptionService,
+	holidayRepo company.HolidayRepository,
+	calendars *calendarservice.Service,
 ) company.CompanyService {
 	return &CompanyServiceImpl{
 		db:                   db,
@@ -390,5 +504,7 @@ func NewCompanyService(
 		quotaService:         quotaService,
 		notificationRepo:     notificationRepo,
 		subscriptionService:  subscriptionService,
+		holidayRepo:          holidayRepo,
+		calendars:            calendars,
 	}
 }
@@ -3,20 +3,25 @@ package dashboard
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/dashboard"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
 	"github.com/go-chi/jwtauth/v5"
 	"golang.org/x/sync/errgroup"
 )
 
 type DashboardServiceImpl struct {
 	dashboard.DashboardRepository
+	clock clock.Clock
 }
 
-func NewDashboardService(repo dashboard.DashboardRepository) dashboard.DashboardService {
+func NewDashboardService(db *database.DB, repo dashboard.DashboardRepository, clk clock.Clock) dashboard.DashboardService {
 	return &DashboardServiceImpl{
-		DashboardRepository: repo,
+		DashboardRepository: timeoutRepository{repo: repo, db: db},
+		clock:               clk,
 	}
 }
 
@@ -34,9 +39,8 @@ func (s *DashboardServiceImpl) getCompanyID(ctx context.Context) (string, error)
 	return companyID, nil
 }
 
-// parseMonth parses YYYY-MM format, defaults to current month
-func parseMonth(month string) (int, int) {
-	now := time.Now()
+// parseMonth parses YYYY-MM format, defaults to the month of now
+func parseMonth(month string, now time.Time) (int, int) {
 	if month == "" {
 		return now.Year(), int(now.Month())
 	}
@@ -48,9 +52,8 @@ func parseMonth(month string) (int, int) {
 	return parsed.Year(), int(parsed.Month())
 }
 
-// parseDate parses YYYY-MM-DD format, defaults to today
-func parseDate(date string) time.Time {
-	now := time.Now()
+// parseDate parses YYYY-MM-DD format, defaults to now
+func parseDate(date string, now time.Time) time.Time {
 	if date == "" {
 		return now
 	}
@@ -70,7 +73,7 @@ func (s *DashboardServiceImpl) GetDashboard(ctx context.Context) (*dashboard.Das
 		return nil, err
 	}
 
-	now := time.Now()
+	now := s.clock.Now()
 	year, month := now.Year(), int(now.Month())
 	since := now.AddDate(0, 0, -30)
 
@@ -188,7 +191,7 @@ func (s *DashboardServiceImpl) GetEmployeeCurrentNumber(ctx context.Context, mon
 		return nil, err
 	}
 
-	year, m := parseMonth(month)
+	year, m := parseMonth(month, s.clock.Now())
 
 	stats, err := s.GetEmployeeMonthlyStats(ctx, companyID, year, m)
 	if err != nil {
@@ -209,7 +212,7 @@ func (s *DashboardServiceImpl) GetEmployeeStatusStats(ctx context.Context, month
 		return nil, err
 	}
 
-	year, m := parseMonth(month)
+	year, m := parseMonth(month, s.clock.Now())
 
 	stats, err := s.GetEmployeeTypeStats(ctx, companyID, year, m)
 	if err != nil {
@@ -232,7 +235,7 @@ func (s *DashboardServiceImpl) GetMonthlyAttendance(ctx context.Context, month s
 		return nil, err
 	}
 
-	year, m := parseMonth(month)
+	year, m := parseMonth(month, s.clock.Now())
 
 	data, err := s.GetMonthlyAttendanceWithRecords(ctx, companyID, year, m, 10)
 	if err != nil {
@@ -254,7 +257,7 @@ func (s *DashboardServiceImpl) GetDailyAttendanceStats(ctx context.Context, date
 		return nil, err
 	}
 
-	d := parseDate(date)
+	d := parseDate(date, s.clock.Now())
 
 	stats, err := s.GetAttendanceStatsByDay(ctx, companyID, d)
 	if err != nil {
@@ -278,3 +281,94 @@ func (s *DashboardServiceImpl) GetDailyAttendanceStats(ctx context.Context, date
 		AbsentPercent: absentPercent,
 	}, nil
 }
+
+// headcountTrendMonths is the length of the headcount trend window, including the current month
+const headcountTrendMonths = 12
+
+// GetHeadcountAnalytics returns headcount breakdowns, monthly hires/resignations and turnover.
+// The window starts on the first day of the month 11 months ago and runs through today.
+func (s *DashboardServiceImpl) GetHeadcountAnalytics(ctx context.Context, companyID string) (*dashboard.HeadcountAnalyticsResponse, error) {
+	now := s.clock.Now()
+	since := time.Date(now.Year(), now.Month()-(headcountTrendMonths-1), 1, 0, 0, 0, 0, time.UTC)
+
+	var (
+		totals  *dashboard.HeadcountTotals
+		monthly []dashboard.HeadcountMonthlyStats
+	)
+	dimensions := []dashboard.HeadcountDimension{
+		dashboard.HeadcountByBranch,
+		dashboard.HeadcountByPosition,
+		dashboard.HeadcountByGrade,
+		dashboard.HeadcountByEmploymentType,
+	}
+	groups := make([][]dashboard.HeadcountGroupStats, len(dimensions))
+
+	g, gCtx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		var err error
+		totals, err = s.GetHeadcountTotals(gCtx, companyID, since)
+		return err
+	})
+
+	g.Go(func() error {
+		var err error
+		monthly, err = s.GetHeadcountMonthlyMovements(gCtx, companyID, since, headcountTrendMonths)
+		return err
+	})
+
+	for i, dimension := range dimensions {
+		g.Go(func() error {
+			stats, err := s.GetHeadcountByDimension(gCtx, companyID, dimension)
+			if err != nil {
+				return err
+			}
+			groups[i] = stats
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Turnover = departures in the window / average of headcount at its start and now
+	var turnoverRate float64
+	if average := float64(totals.ActiveAtStart+totals.Active) / 2; average > 0 {
+		turnoverRate = math.Round(float64(totals.Resignations)/average*100*100) / 100
+	}
+
+	months := make([]dashboard.HeadcountMonthItem, 0, len(monthly))
+	for _, m := range monthly {
+		months = append(months, dashboard.HeadcountMonthItem{
+			Month:        m.Month.Format("2006-01"),
+			NewHires:     m.NewHires,
+			Resignations: m.Resignations,
+		})
+	}
+
+	return &dashboard.HeadcountAnalyticsResponse{
+		TotalActive:      totals.Active,
+		ByBranch:         toHeadcountGroupItems(groups[0]),
+		ByPosition:       toHeadcountGroupItems(groups[1]),
+		ByGrade:          toHeadcountGroupItems(groups[2]),
+		ByEmploymentType: toHeadcountGroupItems(groups[3]),
+		Monthly:          months,
+		TurnoverRate:     turnoverRate,
+		PeriodStart:      since.Format("2006-01"),
+		PeriodEnd:        now.Format("2006-01"),
+	}, nil
+}
+
+// toHeadcountGroupItems maps repository groups to chart items, never returning nil
+func toHeadcountGroupItems(stats []dashboard.HeadcountGroupStats) []dashboard.HeadcountGroupItem {
+	items := make([]dashboard.HeadcountGroupItem, 0, len(stats))
+	for _, stat := range stats {
+		items = append(items, dashboard.HeadcountGroupItem{
+			ID:    stat.ID,
+			Label: stat.Label,
+			Count: stat.Count,
+		})
+	}
+	return items
+}
@@ -0,0 +1,105 @@
+package dashboard
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/dashboard"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+)
+
+// fakeHeadcountRepo answers the headcount queries and records the window start it was asked for
+type fakeHeadcountRepo struct {
+	dashboard.DashboardRepository
+
+	mu    sync.Mutex
+	since []time.Time
+}
+
+func (r *fakeHeadcountRepo) record(since time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.since = append(r.since, since)
+}
+
+func (r *fakeHeadcountRepo) GetHeadcountTotals(_ context.Context, _ string, since time.Time) (*dashboard.HeadcountTotals, error) {
+	r.record(since)
+	return &dashboard.HeadcountTotals{Active: 9, ActiveAtStart: 7, Resignations: 2}, nil
+}
+
+func (r *fakeHeadcountRepo) GetHeadcountMonthlyMovements(_ context.Context, _ string, since time.Time, months int) ([]dashboard.HeadcountMonthlyStats, error) {
+	r.record(since)
+	stats := make([]dashboard.HeadcountMonthlyStats, months)
+	for i := range stats {
+		stats[i].Month = since.AddDate(0, i, 0)
+	}
+	return stats, nil
+}
+
+func (r *fakeHeadcountRepo) GetHeadcountByDimension(_ context.Context, _ string, dimension dashboard.HeadcountDimension) ([]dashboard.HeadcountGroupStats, error) {
+	id := "group-1"
+	return []dashboard.HeadcountGroupStats{
+		{ID: &id, Label: string(dimension) + " 1", Count: 7},
+		{Label: dashboard.HeadcountUnassignedLabel, Count: 2},
+	}, nil
+}
+
+func TestGetHeadcountAnalyticsUsesTheClock(t *testing.T) {
+	repo := &fakeHeadcountRepo{}
+	s := &DashboardServiceImpl{DashboardRepository: repo, clock: clock.NewFake(time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC))}
+
+	resp, err := s.GetHeadcountAnalytics(context.Background(), "company-1")
+	if err != nil {
+		t.Fatalf("GetHeadcountAnalytics() error = %v", err)
+	}
+
+	wantSince := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	for _, since := range repo.since {
+		if !since.Equal(wantSince) {
+			t.Errorf("window start = %v, want %v", since, wantSince)
+		}
+	}
+	if resp.PeriodStart != "2025-04" || resp.PeriodEnd != "2026-03" {
+		t.Errorf("period = %s to %s, want 2025-04 to 2026-03", resp.PeriodStart, resp.PeriodEnd)
+	}
+	if len(resp.Monthly) != 12 || resp.Monthly[11].Month != "2026-03" {
+		t.Errorf("monthly = %+v, want 12 months ending 2026-03", resp.Monthly)
+	}
+	if resp.TurnoverRate != 25 {
+		t.Errorf("turnover rate = %v, want 25", resp.TurnoverRate)
+	}
+
+	for name, groups := range map[string][]dashboard.HeadcountGroupItem{
+		"branch": resp.ByBranch, "position": resp.ByPosition, "grade": resp.ByGrade, "employment type": resp.ByEmploymentType,
+	} {
+		var total int64
+		for _, g := range groups {
+			total += g.Count
+		}
+		if total != resp.TotalActive {
+			t.Errorf("%s breakdown adds up to %d, want %d", name, total, resp.TotalActive)
+		}
+		if last := groups[len(groups)-1]; last.ID != nil || last.Label != dashboard.HeadcountUnassignedLabel {
+			t.Errorf("%s breakdown ends with %+v, want the unassigned group", name, last)
+		}
+	}
+}
+
+func TestParseMonthDefaultsToNow(t *testing.T) {
+	now := time.Date(2026, 2, 28, 23, 0, 0, 0, time.UTC)
+
+	if year, month := parseMonth("", now); year != 2026 || month != 2 {
+		t.Errorf("parseMonth(\"\") = %d-%d, want 2026-2", year, month)
+	}
+	if year, month := parseMonth("2025-11", now); year != 2025 || month != 11 {
+		t.Errorf("parseMonth(\"2025-11\") = %d-%d, want 2025-11", year, month)
+	}
+	if year, month := parseMonth("bad", now); year != 2026 || month != 2 {
+		t.Errorf("parseMonth(\"bad\") = %d-%d, want 2026-2", year, month)
+	}
+	if got := parseDate("", now); !got.Equal(now) {
+		t.Errorf("parseDate(\"\") = %v, want %v", got, now)
+	}
+}
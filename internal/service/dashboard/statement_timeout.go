@@ -0,0 +1,65 @@
+package dashboard
+
+import (
+	"context"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/dashboard"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
+)
+
+// timeoutRepository runs every dashboard query under the report statement timeout.
+// Each query gets its own transaction, so the parallel queries of a dashboard stay parallel.
+type timeoutRepository struct {
+	repo dashboard.DashboardRepository
+	db   *database.DB
+}
+
+func (r timeoutRepository) GetEmployeeSummary(ctx context.Context, companyID string, since time.Time) (*dashboard.EmployeeSummaryStats, error) {
+	return postgresql.WithStatementTimeout(ctx, r.db, r.db.ReportStatementTimeout, func(ctx context.Context) (*dashboard.EmployeeSummaryStats, error) {
+		return r.repo.GetEmployeeSummary(ctx, companyID, since)
+	})
+}
+
+func (r timeoutRepository) GetEmployeeMonthlyStats(ctx context.Context, companyID string, year, month int) (*dashboard.EmployeeMonthlyStats, error) {
+	return postgresql.WithStatementTimeout(ctx, r.db, r.db.ReportStatementTimeout, func(ctx context.Context) (*dashboard.EmployeeMonthlyStats, error) {
+		return r.repo.GetEmployeeMonthlyStats(ctx, companyID, year, month)
+	})
+}
+
+func (r timeoutRepository) GetEmployeeTypeStats(ctx context.Context, companyID string, year, month int) (*dashboard.EmployeeTypeStats, error) {
+	return postgresql.WithStatementTimeout(ctx, r.db, r.db.ReportStatementTimeout, func(ctx context.Context) (*dashboard.EmployeeTypeStats, error) {
+		return r.repo.GetEmployeeTypeStats(ctx, companyID, year, month)
+	})
+}
+
+func (r timeoutRepository) GetAttendanceStatsByDay(ctx context.Context, companyID string, date time.Time) (*dashboard.AttendanceStats, error) {
+	return postgresql.WithStatementTimeout(ctx, r.db, r.db.ReportStatementTimeout, func(ctx context.Context) (*dashboard.AttendanceStats, error) {
+		return r.repo.GetAttendanceStatsByDay(ctx, companyID, date)
+	})
+}
+
+func (r timeoutRepository) GetMonthlyAttendanceWithRecords(ctx context.Context, companyID string, year, month int, limit int) (*dashboard.MonthlyAttendanceData, error) {
+	return postgresql.WithStatementTimeout(ctx, r.db, r.db.ReportStatementTimeout, func(ctx context.Context) (*dashboard.MonthlyAttendanceData, error) {
+		return r.repo.GetMonthlyAttendanceWithRecords(ctx, companyID, year, month, limit)
+	})
+}
+
+func (r timeoutRepository) GetHeadcountTotals(ctx context.Context, companyID string, since time.Time) (*dashboard.HeadcountTotals, error) {
+	return postgresql.WithStatementTimeout(ctx, r.db, r.db.ReportStatementTimeout, func(ctx context.Context) (*dashboard.HeadcountTotals, error) {
+		return r.repo.GetHeadcountTotals(ctx, companyID, since)
+	})
+}
+
+func (r timeoutRepository) GetHeadcountByDimension(ctx context.Context, companyID string, dimension dashboard.HeadcountDimension) ([]dashboard.HeadcountGroupStats, error) {
+	return postgresql.WithStatementTimeout(ctx, r.db, r.db.ReportStatementTimeout, func(ctx context.Context) ([]dashboard.HeadcountGroupStats, error) {
+		return r.repo.GetHeadcountByDimension(ctx, companyID, dimension)
+	})
+}
+
+func (r timeoutRepository) GetHeadcountMonthlyMovements(ctx context.Context, companyID string, since time.Time, months int) ([]dashboard.HeadcountMonthlyStats, error) {
+	return postgresql.WithStatementTimeout(ctx, r.db, r.db.ReportStatementTimeout, func(ctx context.Context) ([]dashboard.HeadcountMonthlyStats, error) {
+		return r.repo.GetHeadcountMonthlyMovements(ctx, companyID, since, months)
+	})
+}
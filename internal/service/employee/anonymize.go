@@ -0,0 +1,100 @@
+package employee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/audit"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/scope"
+	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// anonymizedFields lists the employee columns Anonymize clears, recorded in the audit trail instead of their values
+var anonymizedFields = []string{
+	"full_name", "nik", "phone_number", "address", "place_of_birth", "dob", "avatar_url", "education",
+	"bank_name", "bank_account_holder_name", "bank_account_number",
+	"emergency_contact_name", "emergency_contact_relationship", "emergency_contact_phone", "user_id",
+}
+
+// Anonymize implements employee.EmployeeService.
+func (s *EmployeeServiceImpl) Anonymize(ctx context.Context, employeeID string) (employee.AnonymizeEmployeeResponse, error) {
+	if err := scope.Require(ctx, user.PermissionUserManage); err != nil {
+		return employee.AnonymizeEmployeeResponse{}, err
+	}
+
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return employee.AnonymizeEmployeeResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+	companyID, _ := claims["company_id"].(string)
+	actorID, _ := claims["user_id"].(string)
+	if companyID == "" {
+		return employee.AnonymizeEmployeeResponse{}, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	// GetByID also finds soft deleted employees, who can still be anonymized
+	emp, err := s.employeeRepo.GetByID(ctx, employeeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return employee.AnonymizeEmployeeResponse{}, employee.ErrEmployeeNotFound
+		}
+		return employee.AnonymizeEmployeeResponse{}, fmt.Errorf("failed to get employee: %w", err)
+	}
+	if emp.CompanyID != companyID {
+		return employee.AnonymizeEmployeeResponse{}, employee.ErrEmployeeNotFound
+	}
+	if emp.EmploymentStatus == employee.EmploymentStatusActive {
+		return employee.AnonymizeEmployeeResponse{}, employee.ErrEmployeeStillActive
+	}
+
+	anonymizedAt := time.Now().UTC()
+	err = postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		if err := s.employeeRepo.Anonymize(txCtx, emp.ID, companyID, anonymizedAt); err != nil {
+			return err
+		}
+
+		if emp.UserID != nil {
+			if err := s.userRepo.DetachFromCompany(txCtx, *emp.UserID); err != nil {
+				return err
+			}
+		}
+
+		entry := audit.Entry{
+			Action:      audit.ActionAnonymize,
+			TableName:   "employees",
+			RecordID:    emp.ID,
+			OldValue:    map[string]interface{}{"cleared_fields": anonymizedFields, "user_id_detached": emp.UserID != nil},
+			NewValue:    map[string]interface{}{"full_name": employee.AnonymizedName, "anonymized_at": anonymizedAt},
+			Description: fmt.Sprintf("Anonymized employee %s", emp.EmployeeCode),
+		}
+		if actorID != "" {
+			entry.UserID = &actorID
+		}
+		return s.auditRepo.Create(txCtx, entry)
+	})
+	if err != nil {
+		return employee.AnonymizeEmployeeResponse{}, err
+	}
+
+	// The avatar is the only stored file that is personal data rather than a record; remove it once committed
+	if emp.AvatarURL != nil && *emp.AvatarURL != "" && s.fileService != nil {
+		if err := s.fileService.DeleteFile(ctx, *emp.AvatarURL); err != nil {
+			slog.Warn("Failed to delete anonymized employee avatar", "employee_id", emp.ID, "error", err)
+		}
+	}
+
+	return employee.AnonymizeEmployeeResponse{
+		EmployeeID:   emp.ID,
+		EmployeeCode: emp.EmployeeCode,
+		AnonymizedAt: anonymizedAt,
+	}, nil
+}
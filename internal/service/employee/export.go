@@ -0,0 +1,211 @@
+package employee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/payroll"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/scope"
+)
+
+// exportPageSize is the page size used to walk paginated history for an export
+const exportPageSize = 500
+
+// ExportEmployeeData implements employee.EmployeeService.
+// Employees may export their own data; anyone else needs the user management permission.
+func (s *EmployeeServiceImpl) ExportEmployeeData(ctx context.Context, employeeID string) (employee.EmployeeDataExport, error) {
+	companyID, requestingEmployeeID, _, err := getClaimsFromContext(ctx)
+	if err != nil {
+		return employee.EmployeeDataExport{}, err
+	}
+
+	if requestingEmployeeID != employeeID {
+		if err := scope.Require(ctx, user.PermissionUserManage); err != nil {
+			return employee.EmployeeDataExport{}, err
+		}
+	}
+
+	emp, err := s.employeeRepo.GetByIDWithDetails(ctx, employeeID, companyID)
+	if err != nil {
+		if errors.Is(err, employee.ErrEmployeeNotFound) {
+			return employee.EmployeeDataExport{}, employee.ErrEmployeeNotFound
+		}
+		return employee.EmployeeDataExport{}, fmt.Errorf("failed to get employee: %w", err)
+	}
+
+	export := employee.EmployeeDataExport{
+		ExportedAt:    time.Now().UTC(),
+		Profile:       mapEmployeeToResponse(emp),
+		Attendance:    []employee.ExportedAttendance{},
+		LeaveRequests: []employee.ExportedLeaveRequest{},
+		LeaveQuotas:   []employee.ExportedLeaveQuota{},
+		Payroll:       []employee.ExportedPayrollRecord{},
+		Documents:     []employee.ExportedDocument{},
+	}
+	if emp.AvatarURL != nil && *emp.AvatarURL != "" {
+		export.Documents = append(export.Documents, employee.ExportedDocument{Type: employee.DocumentAvatar, URL: *emp.AvatarURL, SourceID: emp.ID})
+	}
+
+	if err := s.exportAttendance(ctx, employeeID, companyID, &export); err != nil {
+		return employee.EmployeeDataExport{}, err
+	}
+	if err := s.exportLeave(ctx, employeeID, companyID, &export); err != nil {
+		return employee.EmployeeDataExport{}, err
+	}
+	if err := s.exportPayroll(ctx, employeeID, companyID, &export); err != nil {
+		return employee.EmployeeDataExport{}, err
+	}
+
+	return export, nil
+}
+
+// exportAttendance adds the employee's whole attendance history, oldest first, and its proof photos
+func (s *EmployeeServiceImpl) exportAttendance(ctx context.Context, employeeID, companyID string, export *employee.EmployeeDataExport) error {
+	filter := attendance.MyAttendanceFilter{Page: 1, Limit: exportPageSize, SortBy: "date", SortOrder: "asc"}
+	for {
+		records, total, err := s.attendanceRepo.GetMyAttendance(ctx, employeeID, filter, companyID)
+		if err != nil {
+			return fmt.Errorf("failed to get attendance: %w", err)
+		}
+
+		for _, att := range records {
+			export.Attendance = append(export.Attendance, employee.ExportedAttendance{
+				ID:                 att.ID,
+				Date:               att.Date.Format("2006-01-02"),
+				Status:             att.Status,
+				ClockIn:            att.ClockIn,
+				ClockOut:           att.ClockOut,
+				WorkHoursInMinutes: att.WorkHoursInMinutes,
+				LocationType:       att.ActualLocationType,
+				ClockInLatitude:    att.ClockInLatitude,
+				ClockInLongitude:   att.ClockInLongitude,
+				ClockOutLatitude:   att.ClockOutLatitude,
+				ClockOutLongitude:  att.ClockOutLongitude,
+				LateMinutes:        att.LateMinutes,
+				EarlyLeaveMinutes:  att.EarlyLeaveMinutes,
+				OvertimeMinutes:    att.OvertimeMinutes,
+				LeaveTypeID:        att.LeaveTypeID,
+				RejectionReason:    att.RejectionReason,
+				AutoClosed:         att.AutoClosed,
+				CreatedAt:          att.CreatedAt,
+			})
+			if att.ClockInProofURL != nil && *att.ClockInProofURL != "" {
+				export.Documents = append(export.Documents, employee.ExportedDocument{Type: employee.DocumentClockInProof, URL: *att.ClockInProofURL, SourceID: att.ID})
+			}
+			if att.ClockOutProofURL != nil && *att.ClockOutProofURL != "" {
+				export.Documents = append(export.Documents, employee.ExportedDocument{Type: employee.DocumentClockOutProof, URL: *att.ClockOutProofURL, SourceID: att.ID})
+			}
+		}
+
+		if len(records) < filter.Limit || int64(filter.Page*filter.Limit) >= total {
+			return nil
+		}
+		filter.Page++
+	}
+}
+
+// exportLeave adds the employee's leave requests, their attachments and every leave year's quota
+func (s *EmployeeServiceImpl) exportLeave(ctx context.Context, employeeID, companyID string, export *employee.EmployeeDataExport) error {
+	leaveTypes, err := s.leaveTypeRepo.GetByCompanyID(ctx, companyID)
+	if err != nil {
+		return fmt.Errorf("failed to get leave types: %w", err)
+	}
+	leaveTypeNames := make(map[string]string, len(leaveTypes))
+	for _, lt := range leaveTypes {
+		leaveTypeNames[lt.ID] = lt.Name
+	}
+
+	requests, _, err := s.leaveRequestRepo.GetMyRequest(ctx, employeeID, companyID)
+	if err != nil {
+		return fmt.Errorf("failed to get leave requests: %w", err)
+	}
+	for _, req := range requests {
+		export.LeaveRequests = append(export.LeaveRequests, employee.ExportedLeaveRequest{
+			ID:                 req.ID,
+			LeaveTypeID:        req.LeaveTypeID,
+			LeaveTypeName:      leaveTypeNames[req.LeaveTypeID],
+			StartDate:          req.StartDate.Format("2006-01-02"),
+			EndDate:            req.EndDate.Format("2006-01-02"),
+			DurationType:       string(req.DurationType),
+			TotalDays:          req.TotalDays,
+			WorkingDays:        req.WorkingDays,
+			Reason:             req.Reason,
+			EmergencyLeave:     req.EmergencyLeave,
+			Status:             string(req.Status),
+			SubmittedAt:        req.SubmittedAt,
+			DecidedAt:          req.ApprovedAt,
+			RejectionReason:    req.RejectionReason,
+			CancelledAt:        req.CancelledAt,
+			CancellationReason: req.CancellationReason,
+		})
+		if req.AttachmentURL != nil && *req.AttachmentURL != "" {
+			export.Documents = append(export.Documents, employee.ExportedDocument{Type: employee.DocumentLeaveAttachment, URL: *req.AttachmentURL, SourceID: req.ID})
+		}
+	}
+
+	quotas, err := s.leaveQuotaRepo.GetByEmployee(ctx, employeeID)
+	if err != nil {
+		return fmt.Errorf("failed to get leave quotas: %w", err)
+	}
+	for _, quota := range quotas {
+		export.LeaveQuotas = append(export.LeaveQuotas, mapExportedQuota(quota.ToResponse(leaveTypeNames[quota.LeaveTypeID])))
+	}
+
+	return nil
+}
+
+func mapExportedQuota(quota leave.LeaveQuotaResponse) employee.ExportedLeaveQuota {
+	return employee.ExportedLeaveQuota{
+		LeaveTypeID:     quota.LeaveTypeID,
+		LeaveTypeName:   quota.LeaveTypeName,
+		Year:            quota.Year,
+		OpeningBalance:  quota.OpeningBalance,
+		EarnedQuota:     quota.EarnedQuota,
+		RolloverQuota:   quota.RolloverQuota,
+		AdjustmentQuota: quota.AdjustmentQuota,
+		UsedQuota:       quota.UsedQuota,
+		PendingQuota:    quota.PendingQuota,
+		AvailableQuota:  quota.AvailableQuota,
+	}
+}
+
+// exportPayroll adds the employee's payroll records, oldest first
+func (s *EmployeeServiceImpl) exportPayroll(ctx context.Context, employeeID, companyID string, export *employee.EmployeeDataExport) error {
+	filter := payroll.PayrollFilter{EmployeeID: &employeeID, Page: 1, Limit: exportPageSize, SortOrder: "asc"}
+	for {
+		records, total, err := s.payrollRepo.ListPayrollRecords(ctx, companyID, filter)
+		if err != nil {
+			return fmt.Errorf("failed to get payroll records: %w", err)
+		}
+
+		for _, rec := range records {
+			export.Payroll = append(export.Payroll, employee.ExportedPayrollRecord{
+				ID:               rec.ID,
+				PeriodMonth:      rec.PeriodMonth,
+				PeriodYear:       rec.PeriodYear,
+				BaseSalary:       rec.BaseSalary,
+				AllowancesDetail: rec.AllowancesDetail,
+				DeductionsDetail: rec.DeductionsDetail,
+				TotalAllowances:  rec.TotalAllowances,
+				TotalDeductions:  rec.TotalDeductions,
+				OvertimeAmount:   rec.OvertimeAmount,
+				GrossSalary:      rec.GrossSalary,
+				NetSalary:        rec.NetSalary,
+				TotalWorkDays:    rec.TotalWorkDays,
+				Status:           string(rec.Status),
+				PaidAt:           rec.PaidAt,
+			})
+		}
+
+		if len(records) < filter.Limit || int64(filter.Page*filter.Limit) >= total {
+			return nil
+		}
+		filter.Page++
+	}
+}
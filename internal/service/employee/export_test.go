@@ -0,0 +1,152 @@
+package employee
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/payroll"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/testutil"
+)
+
+type fakeEmployeeRepo struct {
+	employee.EmployeeRepository
+	employees map[string]employee.EmployeeWithDetails
+}
+
+func (r *fakeEmployeeRepo) GetByIDWithDetails(_ context.Context, id, companyID string) (employee.EmployeeWithDetails, error) {
+	emp, ok := r.employees[id]
+	if !ok || emp.CompanyID != companyID {
+		return employee.EmployeeWithDetails{}, employee.ErrEmployeeNotFound
+	}
+	return emp, nil
+}
+
+type fakeAttendanceRepo struct {
+	attendance.AttendanceRepository
+	records []attendance.Attendance
+}
+
+func (r *fakeAttendanceRepo) GetMyAttendance(_ context.Context, _ string, filter attendance.MyAttendanceFilter, _ string) ([]attendance.Attendance, int64, error) {
+	from := min((filter.Page-1)*filter.Limit, len(r.records))
+	to := min(from+filter.Limit, len(r.records))
+	return r.records[from:to], int64(len(r.records)), nil
+}
+
+type fakeLeaveTypeRepo struct {
+	leave.LeaveTypeRepository
+	types []leave.LeaveType
+}
+
+func (r *fakeLeaveTypeRepo) GetByCompanyID(context.Context, string) ([]leave.LeaveType, error) {
+	return r.types, nil
+}
+
+type fakeLeaveQuotaRepo struct {
+	leave.LeaveQuotaRepository
+	quotas []leave.LeaveQuota
+}
+
+func (r *fakeLeaveQuotaRepo) GetByEmployee(context.Context, string) ([]leave.LeaveQuota, error) {
+	return r.quotas, nil
+}
+
+type fakeLeaveRequestRepo struct {
+	leave.LeaveRequestRepository
+	requests []leave.LeaveRequest
+}
+
+func (r *fakeLeaveRequestRepo) GetMyRequest(context.Context, string, string) ([]leave.LeaveRequest, int64, error) {
+	return r.requests, int64(len(r.requests)), nil
+}
+
+type fakePayrollRepo struct {
+	payroll.PayrollRepository
+}
+
+func (r *fakePayrollRepo) ListPayrollRecords(context.Context, string, payroll.PayrollFilter) ([]payroll.PayrollRecord, int64, error) {
+	return nil, 0, nil
+}
+
+func newExportService() *EmployeeServiceImpl {
+	attachment := "leave/req-1.pdf"
+	used, pending, available := 2.0, 0.0, 10.0
+	earned := 12
+
+	return &EmployeeServiceImpl{
+		employeeRepo: &fakeEmployeeRepo{employees: map[string]employee.EmployeeWithDetails{
+			"emp-1": {Employee: employee.Employee{ID: "emp-1", CompanyID: "company-1", FullName: "Dewi Lestari"}},
+		}},
+		attendanceRepo: &fakeAttendanceRepo{},
+		leaveTypeRepo:  &fakeLeaveTypeRepo{types: []leave.LeaveType{{ID: "type-1", Name: "Annual Leave"}}},
+		leaveQuotaRepo: &fakeLeaveQuotaRepo{quotas: []leave.LeaveQuota{{
+			LeaveTypeID: "type-1", Year: 2026, EarnedQuota: &earned,
+			UsedQuota: &used, PendingQuota: &pending, AvailableQuota: &available,
+		}}},
+		leaveRequestRepo: &fakeLeaveRequestRepo{requests: []leave.LeaveRequest{{
+			ID:            "req-1",
+			EmployeeID:    "emp-1",
+			LeaveTypeID:   "type-1",
+			StartDate:     time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+			EndDate:       time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+			DurationType:  leave.LeaveDurationEnum("full_day"),
+			TotalDays:     2,
+			WorkingDays:   2,
+			Reason:        "family event",
+			AttachmentURL: &attachment,
+			Status:        leave.LeaveRequestStatusApproved,
+		}}},
+		payrollRepo: &fakePayrollRepo{},
+	}
+}
+
+func TestExportEmployeeDataIncludesLeave(t *testing.T) {
+	s := newExportService()
+	ctx := testutil.ClaimsContext(t, map[string]any{"company_id": "company-1", "employee_id": "emp-1", "role": "employee"})
+
+	export, err := s.ExportEmployeeData(ctx, "emp-1")
+	if err != nil {
+		t.Fatalf("ExportEmployeeData() error = %v", err)
+	}
+
+	if export.Profile.ID != "emp-1" {
+		t.Errorf("Profile.ID = %q, want emp-1", export.Profile.ID)
+	}
+	if len(export.LeaveRequests) != 1 {
+		t.Fatalf("LeaveRequests = %d, want 1", len(export.LeaveRequests))
+	}
+	req := export.LeaveRequests[0]
+	if req.ID != "req-1" || req.LeaveTypeName != "Annual Leave" || req.StartDate != "2026-03-02" || req.Status != "approved" {
+		t.Errorf("LeaveRequests[0] = %+v", req)
+	}
+
+	if len(export.LeaveQuotas) != 1 {
+		t.Fatalf("LeaveQuotas = %d, want 1", len(export.LeaveQuotas))
+	}
+	quota := export.LeaveQuotas[0]
+	if quota.LeaveTypeName != "Annual Leave" || quota.EarnedQuota != 12 || quota.UsedQuota != 2 || quota.AvailableQuota != 10 || quota.OpeningBalance != 0 {
+		t.Errorf("LeaveQuotas[0] = %+v", quota)
+	}
+
+	if len(export.Documents) != 1 || export.Documents[0].Type != employee.DocumentLeaveAttachment || export.Documents[0].SourceID != "req-1" {
+		t.Errorf("Documents = %+v, want the leave attachment", export.Documents)
+	}
+	if export.Attendance == nil || export.Payroll == nil {
+		t.Error("empty sections should be empty lists, not null")
+	}
+}
+
+func TestExportEmployeeDataOfAnotherEmployeeRequiresPermission(t *testing.T) {
+	s := newExportService()
+	ctx := testutil.ClaimsContext(t, map[string]any{"company_id": "company-1", "employee_id": "emp-2", "role": "employee"})
+
+	_, err := s.ExportEmployeeData(ctx, "emp-1")
+	if !errors.Is(err, user.ErrInsufficientPermissions) {
+		t.Fatalf("ExportEmployeeData() error = %v, want %v", err, user.ErrInsufficientPermissions)
+	}
+}
@@ -5,49 +5,87 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"math"
 	"strings"
 	"time"
 
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/audit"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/invitation"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/master/branch"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/master/grade"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/payroll"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/pagination"
 	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
 	"github.com/cmlabs-hris/hris-backend-go/internal/service/file"
 	leaveservice "github.com/cmlabs-hris/hris-backend-go/internal/service/leave"
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
 )
 
 type EmployeeServiceImpl struct {
 	db                  *database.DB
 	employeeRepo        employee.EmployeeRepository
 	companyRepo         company.CompanyRepository
+	gradeRepo           grade.GradeRepository
+	branchRepo          branch.BranchRepository
+	userRepo            user.UserRepository
 	fileService         file.FileService
 	invitationService   invitation.InvitationService
 	quotaService        *leaveservice.QuotaService
 	subscriptionService subscription.SubscriptionService
+
+	// Data export
+	attendanceRepo   attendance.AttendanceRepository
+	leaveTypeRepo    leave.LeaveTypeRepository
+	leaveQuotaRepo   leave.LeaveQuotaRepository
+	leaveRequestRepo leave.LeaveRequestRepository
+	payrollRepo      payroll.PayrollRepository
+
+	auditRepo audit.AuditRepository
 }
 
 func NewEmployeeService(
 	db *database.DB,
 	employeeRepo employee.EmployeeRepository,
 	companyRepo company.CompanyRepository,
+	gradeRepo grade.GradeRepository,
+	branchRepo branch.BranchRepository,
+	userRepo user.UserRepository,
 	fileService file.FileService,
 	invitationService invitation.InvitationService,
 	quotaService *leaveservice.QuotaService,
 	subscriptionService subscription.SubscriptionService,
+	attendanceRepo attendance.AttendanceRepository,
+	leaveTypeRepo leave.LeaveTypeRepository,
+	leaveQuotaRepo leave.LeaveQuotaRepository,
+	leaveRequestRepo leave.LeaveRequestRepository,
+	payrollRepo payroll.PayrollRepository,
+	auditRepo audit.AuditRepository,
 ) employee.EmployeeService {
 	return &EmployeeServiceImpl{
 		db:                  db,
 		employeeRepo:        employeeRepo,
 		companyRepo:         companyRepo,
+		gradeRepo:           gradeRepo,
+		branchRepo:          branchRepo,
+		userRepo:            userRepo,
 		fileService:         fileService,
 		invitationService:   invitationService,
 		quotaService:        quotaService,
 		subscriptionService: subscriptionService,
+		attendanceRepo:      attendanceRepo,
+		leaveTypeRepo:       leaveTypeRepo,
+		leaveQuotaRepo:      leaveQuotaRepo,
+		leaveRequestRepo:    leaveRequestRepo,
+		payrollRepo:         payrollRepo,
+		auditRepo:           auditRepo,
 	}
 }
 
@@ -69,6 +107,30 @@ func getClaimsFromContext(ctx context.Context) (companyID, employeeID, role stri
 	return companyID, employeeID, role, nil
 }
 
+// checkSalaryBand validates a base salary against the grade's salary band
+// Owners may bypass the band by explicitly setting override
+func (s *EmployeeServiceImpl) checkSalaryBand(ctx context.Context, companyID, gradeID string, salary *decimal.Decimal, role string, override bool) error {
+	if salary == nil || gradeID == "" {
+		return nil
+	}
+	if override && role == "owner" {
+		return nil
+	}
+
+	g, err := s.gradeRepo.GetByID(ctx, gradeID, companyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return grade.ErrGradeNotFound
+		}
+		return fmt.Errorf("failed to get grade: %w", err)
+	}
+
+	if !g.SalaryInBand(*salary) {
+		return employee.ErrSalaryOutsideGradeBand
+	}
+	return nil
+}
+
 // Helper function to map EmployeeWithDetails to EmployeeResponse
 func mapEmployeeToResponse(emp employee.EmployeeWithDetails) employee.EmployeeResponse {
 	var dobStr *string
@@ -148,8 +210,13 @@ func mapEmployeeToResponse(emp employee.EmployeeWithDetails) employee.EmployeeRe
 		BankAccountHolderName: emp.BankAccountHolderName,
 		BankAccountNumber:     &emp.BankAccountNumber,
 		BaseSalary:            emp.BaseSalary,
-		CreatedAt:             emp.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:             emp.UpdatedAt.Format("2006-01-02 15:04:05"),
+
+		EmergencyContactName:         emp.EmergencyContactName,
+		EmergencyContactRelationship: emp.EmergencyContactRelationship,
+		EmergencyContactPhone:        emp.EmergencyContactPhone,
+
+		CreatedAt: emp.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt: emp.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
 
@@ -206,25 +273,57 @@ func (s *EmployeeServiceImpl) GetEmployee(ctx context.Context, id string) (emplo
 	return mapEmployeeToResponse(emp), nil
 }
 
+// GetProfile implements employee.EmployeeService.
+func (s *EmployeeServiceImpl) GetProfile(ctx context.Context, employeeID string) (employee.EmployeeProfileResponse, error) {
+	companyID, requestingEmployeeID, role, err := getClaimsFromContext(ctx)
+	if err != nil {
+		return employee.EmployeeProfileResponse{}, err
+	}
+
+	// Role-based access control: employees can only view their own profile
+	if role == "employee" && requestingEmployeeID != employeeID {
+		return employee.EmployeeProfileResponse{}, employee.ErrUnauthorized
+	}
+
+	profile, err := s.employeeRepo.GetProfile(ctx, employeeID, companyID)
+	if err != nil {
+		if errors.Is(err, employee.ErrEmployeeNotFound) {
+			return employee.EmployeeProfileResponse{}, employee.ErrEmployeeNotFound
+		}
+		return employee.EmployeeProfileResponse{}, fmt.Errorf("failed to get employee profile: %w", err)
+	}
+
+	return employee.EmployeeProfileResponse{
+		EmployeeResponse: mapEmployeeToResponse(profile.EmployeeWithDetails),
+		ManagerID:        profile.ManagerID,
+		ManagerName:      profile.ManagerName,
+		TenureMonths:     profile.TenureMonths,
+	}, nil
+}
+
 // CreateEmployee implements employee.EmployeeService.
 func (s *EmployeeServiceImpl) CreateEmployee(ctx context.Context, req employee.CreateEmployeeRequest) (employee.EmployeeResponse, error) {
 	if err := req.Validate(); err != nil {
 		return employee.EmployeeResponse{}, err
 	}
 
-	companyID, inviterEmployeeID, _, err := getClaimsFromContext(ctx)
+	companyID, inviterEmployeeID, role, err := getClaimsFromContext(ctx)
 	if err != nil {
 		return employee.EmployeeResponse{}, err
 	}
 
-	// Check subscription seat limit before creating employee
+	if err := s.checkSalaryBand(ctx, companyID, req.GradeID, req.BaseSalary, role, req.OverrideSalaryBand); err != nil {
+		return employee.EmployeeResponse{}, err
+	}
+
+	// Fail fast on the seat limit before uploading files; re-checked under lock below
 	if s.subscriptionService != nil {
 		canAdd, err := s.subscriptionService.CanAddEmployee(ctx, companyID)
 		if err != nil {
 			return employee.EmployeeResponse{}, fmt.Errorf("failed to check seat limit: %w", err)
 		}
 		if !canAdd {
-			return employee.EmployeeResponse{}, subscription.ErrMaxSeatsReached
+			return employee.EmployeeResponse{}, subscription.ErrSeatLimitExceeded
 		}
 	}
 
@@ -326,6 +425,10 @@ func (s *EmployeeServiceImpl) CreateEmployee(ctx context.Context, req employee.C
 		BankAccountHolderName: req.BankAccountHolderName,
 		BankAccountNumber:     bankAccountNumber,
 		BaseSalary:            req.BaseSalary,
+
+		EmergencyContactName:         req.EmergencyContactName,
+		EmergencyContactRelationship: req.EmergencyContactRelationship,
+		EmergencyContactPhone:        req.EmergencyContactPhone,
 	}
 
 	var createdEmployee employee.Employee
@@ -333,7 +436,15 @@ func (s *EmployeeServiceImpl) CreateEmployee(ctx context.Context, req employee.C
 	// Wrap employee creation and invitation in a transaction
 	var invReq invitation.CreateRequest
 	err = postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		// Check subscription seat limit (active employees + pending invitations)
+		// The subscription row stays locked until commit so parallel creates can't overshoot
+		if s.subscriptionService != nil {
+			if err := s.subscriptionService.EnsureSeatAvailable(txCtx, companyID); err != nil {
+				return err
+			}
+		}
 
 		// Create employee
 		created, err := s.employeeRepo.Create(txCtx, newEmployee)
@@ -380,7 +491,11 @@ func (s *EmployeeServiceImpl) CreateEmployee(ctx context.Context, req employee.C
 		}
 
 		// Assign leave quotas for the employee based on eligible leave types
-		assignedQuotas, err := s.quotaService.AssignLeaveQuotasForEmployee(txCtx, createdEmployee, time.Now().Year())
+		var assignedQuotas []leave.LeaveQuota
+		year, err := s.quotaService.CurrentLeaveYear(txCtx, createdEmployee.CompanyID)
+		if err == nil {
+			assignedQuotas, err = s.quotaService.AssignLeaveQuotasForEmployee(txCtx, createdEmployee, year)
+		}
 		if err != nil {
 			slog.Warn("Failed to assign leave quotas for employee", "employee_id", createdEmployee.ID, "error", err)
 			// Don't fail the transaction, just log the warning
@@ -413,6 +528,11 @@ func (s *EmployeeServiceImpl) UpdateEmployee(ctx context.Context, req employee.U
 		return employee.EmployeeResponse{}, err
 	}
 
+	// Anyone below manager is held to the self-service rules, since the route is open to every role
+	if role != string(user.RoleOwner) && role != string(user.RoleManager) {
+		role = string(user.RoleEmployee)
+	}
+
 	// Role-based access control: employees can only update their own data
 	if role == "employee" && requestingEmployeeID != req.ID {
 		return employee.EmployeeResponse{}, employee.ErrUnauthorized
@@ -432,6 +552,21 @@ func (s *EmployeeServiceImpl) UpdateEmployee(ctx context.Context, req employee.U
 		return employee.EmployeeResponse{}, fmt.Errorf("failed to get employee: %w", err)
 	}
 
+	// Re-check the salary band when either the salary or the grade changes
+	if req.BaseSalary != nil || req.GradeID != nil {
+		gradeID := existingEmp.GradeID
+		if req.GradeID != nil {
+			gradeID = *req.GradeID
+		}
+		salary := existingEmp.BaseSalary
+		if req.BaseSalary != nil {
+			salary = req.BaseSalary
+		}
+		if err := s.checkSalaryBand(ctx, companyID, gradeID, salary, role, req.OverrideSalaryBand); err != nil {
+			return employee.EmployeeResponse{}, err
+		}
+	}
+
 	// Check for duplicate employee code if being updated
 	if req.EmployeeCode != nil && *req.EmployeeCode != "" && *req.EmployeeCode != existingEmp.EmployeeCode {
 		exists, err := s.employeeRepo.ExistsByIDOrCodeOrNIK(ctx, companyID, nil, req.EmployeeCode, nil)
@@ -514,18 +649,14 @@ func (s *EmployeeServiceImpl) ListEmployees(ctx context.Context, filter employee
 		responses = append(responses, mapEmployeeToResponse(emp))
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(filter.Limit)))
-	showing := fmt.Sprintf("%d-%d of %d", (filter.Page-1)*filter.Limit+1, min((filter.Page)*filter.Limit, int(total)), total)
-	if total == 0 {
-		showing = "0 of 0"
-	}
+	meta := pagination.Meta(filter.Page, filter.Limit, total)
 
 	return employee.ListEmployeeResponse{
 		TotalCount: total,
 		Page:       filter.Page,
 		Limit:      filter.Limit,
-		TotalPages: totalPages,
-		Showing:    showing,
+		TotalPages: meta.TotalPages,
+		Showing:    meta.Showing,
 		Employees:  responses,
 	}, nil
 }
@@ -569,6 +700,61 @@ func (s *EmployeeServiceImpl) InactivateEmployee(ctx context.Context, req employ
 	return mapEmployeeToResponse(emp), nil
 }
 
+// SetBranchScope implements employee.EmployeeService.
+func (s *EmployeeServiceImpl) SetBranchScope(ctx context.Context, req employee.SetBranchScopeRequest) (employee.BranchScopeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return employee.BranchScopeResponse{}, err
+	}
+
+	companyID, _, _, err := getClaimsFromContext(ctx)
+	if err != nil {
+		return employee.BranchScopeResponse{}, err
+	}
+
+	emp, err := s.employeeRepo.GetByIDWithDetails(ctx, req.EmployeeID, companyID)
+	if err != nil {
+		if errors.Is(err, employee.ErrEmployeeNotFound) {
+			return employee.BranchScopeResponse{}, employee.ErrEmployeeNotFound
+		}
+		return employee.BranchScopeResponse{}, fmt.Errorf("failed to get employee: %w", err)
+	}
+
+	if emp.UserID == nil {
+		return employee.BranchScopeResponse{}, employee.ErrNotManager
+	}
+
+	account, err := s.userRepo.GetByID(ctx, *emp.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return employee.BranchScopeResponse{}, employee.ErrNotManager
+		}
+		return employee.BranchScopeResponse{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if account.Role != user.RoleManager {
+		return employee.BranchScopeResponse{}, employee.ErrNotManager
+	}
+
+	// The branch must belong to the same company
+	if req.BranchID != nil {
+		if _, err := s.branchRepo.GetByID(ctx, *req.BranchID, companyID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return employee.BranchScopeResponse{}, branch.ErrBranchNotFound
+			}
+			return employee.BranchScopeResponse{}, fmt.Errorf("failed to get branch: %w", err)
+		}
+	}
+
+	if err := s.userRepo.UpdateManagedBranch(ctx, account.ID, req.BranchID); err != nil {
+		return employee.BranchScopeResponse{}, fmt.Errorf("failed to update branch scope: %w", err)
+	}
+
+	return employee.BranchScopeResponse{
+		EmployeeID: emp.ID,
+		BranchID:   req.BranchID,
+	}, nil
+}
+
 // UploadAvatar implements employee.EmployeeService.
 func (s *EmployeeServiceImpl) UploadAvatar(ctx context.Context, req employee.UploadAvatarRequest) (employee.EmployeeResponse, error) {
 	if err := req.Validate(); err != nil {
@@ -0,0 +1,103 @@
+package employee_dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	empDashboard "github.com/cmlabs-hris/hris-backend-go/internal/domain/employee_dashboard"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
+	"github.com/jackc/pgx/v5"
+)
+
+// payrollExcludedStatuses are the statuses payroll leaves out of work days, late and overtime
+// (see the payroll attendance aggregation), so the recap totals match the payslip
+var payrollExcludedStatuses = map[string]bool{
+	"rejected":         true,
+	"approved":         true,
+	"absent":           true,
+	"waiting_approval": true,
+}
+
+// GetMyAttendanceRecap returns the monthly attendance recap of the caller's employee record
+func (s *EmployeeDashboardServiceImpl) GetMyAttendanceRecap(ctx context.Context, userID string, month, year int) (*empDashboard.AttendanceRecapResponse, error) {
+	now := time.Now()
+	if month == 0 {
+		month = int(now.Month())
+	}
+	if year == 0 {
+		year = now.Year()
+	}
+
+	var errs validator.ValidationErrors
+	if month < 1 || month > 12 {
+		errs = append(errs, validator.ValidationError{Field: "month", Message: "month must be between 1 and 12"})
+	}
+	if year < 2000 || year > 2100 {
+		errs = append(errs, validator.ValidationError{Field: "year", Message: "year must be between 2000 and 2100"})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	emp, err := s.employeeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, employee.ErrEmployeeNotFound
+		}
+		return nil, fmt.Errorf("failed to get employee: %w", err)
+	}
+
+	data, err := s.EmployeeDashboardRepository.GetAttendanceRecap(ctx, emp.ID, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	result := buildAttendanceRecapResponse(data, year, month)
+	return &result, nil
+}
+
+// buildAttendanceRecapResponse counts statuses and sums minutes over the daily records
+func buildAttendanceRecapResponse(data []empDashboard.AttendanceRecapData, year, month int) empDashboard.AttendanceRecapResponse {
+	result := empDashboard.AttendanceRecapResponse{
+		Month: fmt.Sprintf("%04d-%02d", year, month),
+		Days:  make([]empDashboard.AttendanceRecapDay, 0, len(data)),
+	}
+
+	for _, d := range data {
+		switch d.Status {
+		case "on_time":
+			result.OnTimeCount++
+		case "late":
+			result.LateCount++
+		case "absent":
+			result.AbsentCount++
+		case "leave":
+			result.LeaveDays++
+		}
+
+		if !payrollExcludedStatuses[d.Status] {
+			result.WorkDays++
+			result.TotalLateMinutes += d.LateMinutes
+			result.TotalOvertimeMinutes += d.OvertimeMinutes
+		}
+		result.TotalWorkMinutes += d.WorkMinutes
+
+		result.Days = append(result.Days, empDashboard.AttendanceRecapDay{
+			Date:            d.Date.Format("2006-01-02"),
+			DayName:         d.Date.Weekday().String(),
+			Status:          d.Status,
+			LeaveTypeName:   d.LeaveTypeName,
+			ClockIn:         d.ClockIn,
+			ClockOut:        d.ClockOut,
+			WorkMinutes:     d.WorkMinutes,
+			LateMinutes:     d.LateMinutes,
+			OvertimeMinutes: d.OvertimeMinutes,
+		})
+	}
+
+	result.TotalWorkHours = formatWorkHours(result.TotalWorkMinutes)
+	return result
+}
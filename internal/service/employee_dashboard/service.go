@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
 	empDashboard "github.com/cmlabs-hris/hris-backend-go/internal/domain/employee_dashboard"
 	"github.com/go-chi/jwtauth/v5"
 	"golang.org/x/sync/errgroup"
@@ -13,11 +14,13 @@ import (
 
 type EmployeeDashboardServiceImpl struct {
 	empDashboard.EmployeeDashboardRepository
+	employeeRepo employee.EmployeeRepository
 }
 
-func NewEmployeeDashboardService(repo empDashboard.EmployeeDashboardRepository) empDashboard.EmployeeDashboardService {
+func NewEmployeeDashboardService(repo empDashboard.EmployeeDashboardRepository, employeeRepo employee.EmployeeRepository) empDashboard.EmployeeDashboardService {
 	return &EmployeeDashboardServiceImpl{
 		EmployeeDashboardRepository: repo,
+		employeeRepo:                employeeRepo,
 	}
 }
 
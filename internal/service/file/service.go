@@ -37,18 +37,44 @@ type FileService interface {
 	// Generic operations
 	DeleteFile(ctx context.Context, path string) error
 	GetFileURL(ctx context.Context, path string, expiry time.Duration) (string, error)
+	// OpenFile reads a file from storage; the caller must close it
+	OpenFile(ctx context.Context, path string) (io.ReadCloser, error)
+
+	// URLExpiry returns the configured URL lifetimes to pass to GetFileURL
+	URLExpiry() URLExpiry
+}
+
+// URLExpiry holds how long generated file URLs stay valid
+type URLExpiry struct {
+	Default time.Duration // Attachments, logos and other files shown in the app
+	Payslip time.Duration // Downloadable payslips, which are often opened later from an email
 }
 
 type fileServiceImpl struct {
-	storage storage.FileStorage
+	storage   storage.FileStorage
+	urlCache  *urlCache
+	urlExpiry URLExpiry
 }
 
-func NewFileService(storage storage.FileStorage) FileService {
+func NewFileService(storage storage.FileStorage, urlExpiry URLExpiry) FileService {
+	if urlExpiry.Default <= 0 {
+		urlExpiry.Default = DefaultURLExpiry
+	}
+	if urlExpiry.Payslip <= 0 {
+		urlExpiry.Payslip = urlExpiry.Default
+	}
 	return &fileServiceImpl{
-		storage: storage,
+		storage:   storage,
+		urlCache:  newURLCache(),
+		urlExpiry: urlExpiry,
 	}
 }
 
+// URLExpiry implements FileService.
+func (s *fileServiceImpl) URLExpiry() URLExpiry {
+	return s.urlExpiry
+}
+
 // UploadAvatar uploads employee avatar
 func (s *fileServiceImpl) UploadAvatar(ctx context.Context, employeeID string, file io.Reader, filename string) (string, error) {
 	// Validate file extension
@@ -163,12 +189,32 @@ func (s *fileServiceImpl) UploadLeaveAttachment(ctx context.Context, employeeID
 
 // DeleteFile deletes a file
 func (s *fileServiceImpl) DeleteFile(ctx context.Context, path string) error {
+	s.urlCache.invalidate(path)
 	return s.storage.Delete(ctx, path)
 }
 
-// GetFileURL generates URL to access file
+// OpenFile reads a file from storage
+func (s *fileServiceImpl) OpenFile(ctx context.Context, path string) (io.ReadCloser, error) {
+	return s.storage.Download(ctx, path)
+}
+
+// GetFileURL generates URL to access file. An expiry of 0 means the configured default.
+// URLs are reused for a short while, so listing many attachments does not sign each one per request.
 func (s *fileServiceImpl) GetFileURL(ctx context.Context, path string, expiry time.Duration) (string, error) {
-	return s.storage.GetURL(ctx, path, expiry)
+	if expiry <= 0 {
+		expiry = s.urlExpiry.Default
+	}
+
+	if url, ok := s.urlCache.get(path, expiry); ok {
+		return url, nil
+	}
+
+	url, err := s.storage.GetURL(ctx, path, expiry)
+	if err != nil {
+		return "", err
+	}
+	s.urlCache.set(path, expiry, url)
+	return url, nil
 }
 
 // UploadCompanyLogo uploads a company logo
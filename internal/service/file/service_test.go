@@ -0,0 +1,54 @@
+package file
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetFileURLHonorsExpiry(t *testing.T) {
+	issued := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	expiry := URLExpiry{Default: 20 * time.Minute, Payslip: 72 * time.Hour}
+
+	tests := []struct {
+		name       string
+		expiry     time.Duration
+		wantExpiry time.Duration
+	}{
+		{name: "zero means the configured default", expiry: 0, wantExpiry: expiry.Default},
+		{name: "configured default", expiry: expiry.Default, wantExpiry: expiry.Default},
+		{name: "payslip", expiry: expiry.Payslip, wantExpiry: expiry.Payslip},
+		{name: "short expiry", expiry: 2 * time.Minute, wantExpiry: 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := issued
+			clock := func() time.Time { return now }
+			store := &signingStorage{now: clock}
+			s := NewFileService(store, expiry).(*fileServiceImpl)
+			s.urlCache.now = clock
+
+			url, err := s.GetFileURL(context.Background(), "payslips/emp-1/2026-04.pdf", tt.expiry)
+			if err != nil {
+				t.Fatalf("GetFileURL() error = %v", err)
+			}
+			if !store.validAt(url, issued.Add(tt.wantExpiry-time.Second)) {
+				t.Errorf("URL refused a second before its %s expiry", tt.wantExpiry)
+			}
+			if store.validAt(url, issued.Add(tt.wantExpiry)) {
+				t.Errorf("URL still served after its %s expiry", tt.wantExpiry)
+			}
+
+			// A URL handed out again from the cache keeps at least half its lifetime
+			now = issued.Add(urlCacheTTL(tt.wantExpiry) - time.Second)
+			cached, _ := s.GetFileURL(context.Background(), "payslips/emp-1/2026-04.pdf", tt.expiry)
+			if cached != url {
+				t.Fatalf("second call signed a new URL within the cache TTL")
+			}
+			if !store.validAt(cached, now.Add(tt.wantExpiry/2)) {
+				t.Errorf("cached URL handed out at %s expires within half its lifetime", now.Sub(issued))
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package file
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultURLExpiry is the lifetime of a file URL when none is configured
+const DefaultURLExpiry = 15 * time.Minute
+
+// maxURLCacheTTL caps how long a signed URL is reused, however long it stays valid
+const maxURLCacheTTL = 5 * time.Minute
+
+type urlCacheEntry struct {
+	url       string
+	expiresAt time.Time
+}
+
+// urlCache keeps recently generated file URLs so a page listing many attachments signs each
+// object once. Entries live for half the URL's expiry, capped at maxURLCacheTTL, so a cached
+// URL always has at least half its lifetime left when handed out.
+type urlCache struct {
+	mu        sync.Mutex
+	entries   map[string]urlCacheEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newURLCache() *urlCache {
+	return &urlCache{
+		entries: make(map[string]urlCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// urlCacheKey keys an entry by object path and expiry, so URLs signed for different lifetimes are kept apart
+func urlCacheKey(path string, expiry time.Duration) string {
+	return path + "|" + strconv.FormatInt(int64(expiry/time.Second), 10)
+}
+
+// urlCacheTTL returns how long a URL signed for expiry may be reused
+func urlCacheTTL(expiry time.Duration) time.Duration {
+	ttl := expiry / 2
+	if ttl > maxURLCacheTTL {
+		ttl = maxURLCacheTTL
+	}
+	return ttl
+}
+
+func (c *urlCache) get(path string, expiry time.Duration) (string, bool) {
+	key := urlCacheKey(path, expiry)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return "", false
+	}
+	return entry.url, true
+}
+
+func (c *urlCache) set(path string, expiry time.Duration, url string) {
+	ttl := urlCacheTTL(expiry)
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.entries[urlCacheKey(path, expiry)] = urlCacheEntry{url: url, expiresAt: now.Add(ttl)}
+
+	// Entries that are never read again are dropped by a periodic sweep
+	if now.Sub(c.lastSweep) >= maxURLCacheTTL {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+		c.lastSweep = now
+	}
+}
+
+// invalidate drops every cached URL of path, e.g. after the file is deleted
+func (c *urlCache) invalidate(path string) {
+	prefix := path + "|"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
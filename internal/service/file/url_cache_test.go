@@ -0,0 +1,143 @@
+package file
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/storage"
+)
+
+// signingStorage presigns URLs with HMAC-SHA256 the way an S3-compatible object store does:
+// the URL carries its signing time and expiry, and validAt rejects it once the expiry is over.
+// It counts the URLs it signs.
+type signingStorage struct {
+	storage.FileStorage
+	now    func() time.Time // time.Now when nil
+	signed atomic.Int64
+}
+
+func (s *signingStorage) GetURL(_ context.Context, path string, expiry time.Duration) (string, error) {
+	s.signed.Add(1)
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	date := strconv.FormatInt(now().Unix(), 10)
+	expires := strconv.FormatInt(int64(expiry/time.Second), 10)
+	return "https://storage.example/hris/" + path + "?X-Amz-Date=" + date + "&X-Amz-Expires=" + expires +
+		"&X-Amz-Signature=" + presign(path, date, expires), nil
+}
+
+// validAt reports whether the object store would still serve rawURL at t
+func (s *signingStorage) validAt(rawURL string, t time.Time) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	q := u.Query()
+	path := strings.TrimPrefix(u.Path, "/hris/")
+	if !hmac.Equal([]byte(q.Get("X-Amz-Signature")), []byte(presign(path, q.Get("X-Amz-Date"), q.Get("X-Amz-Expires")))) {
+		return false
+	}
+	date, _ := strconv.ParseInt(q.Get("X-Amz-Date"), 10, 64)
+	expires, _ := strconv.ParseInt(q.Get("X-Amz-Expires"), 10, 64)
+	return t.Before(time.Unix(date+expires, 0))
+}
+
+func presign(path, date, expires string) string {
+	mac := hmac.New(sha256.New, []byte("secret-key"))
+	mac.Write([]byte("GET\n/hris/" + path + "\n" + date + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// attachmentPage returns the object paths of a page of leave requests that all have an attachment
+func attachmentPage(n int) []string {
+	paths := make([]string, n)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("leave-attachments/emp-%d/%d.pdf", i%7, i)
+	}
+	return paths
+}
+
+func TestAttachmentPageSignsEachObjectOnce(t *testing.T) {
+	store := &signingStorage{}
+	s := NewFileService(store, URLExpiry{}).(*fileServiceImpl)
+	now := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	s.urlCache.now = func() time.Time { return now }
+	page := attachmentPage(50)
+
+	first := make([]string, len(page))
+	for i, path := range page {
+		url, err := s.GetFileURL(context.Background(), path, DefaultURLExpiry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		first[i] = url
+	}
+	if got := store.signed.Load(); got != 50 {
+		t.Fatalf("first page load signed %d URLs, want 50", got)
+	}
+
+	// Reloading the page within the cache TTL reuses every URL
+	now = now.Add(maxURLCacheTTL - time.Second)
+	for i, path := range page {
+		url, _ := s.GetFileURL(context.Background(), path, 0)
+		if url != first[i] {
+			t.Fatalf("reload returned %q for %s, want the cached %q", url, path, first[i])
+		}
+	}
+	if got := store.signed.Load(); got != 50 {
+		t.Errorf("reload signed %d more URLs, want 0", got-50)
+	}
+
+	// Once the TTL is over the URLs are signed again
+	now = now.Add(time.Second)
+	for _, path := range page {
+		_, _ = s.GetFileURL(context.Background(), path, 0)
+	}
+	if got := store.signed.Load(); got != 100 {
+		t.Errorf("load after the TTL signed %d URLs, want 50", got-50)
+	}
+}
+
+// BenchmarkAttachmentPage measures resolving the attachment URLs of a 50-request page
+func BenchmarkAttachmentPage(b *testing.B) {
+	page := attachmentPage(50)
+	ctx := context.Background()
+
+	b.Run("uncached", func(b *testing.B) {
+		store := &signingStorage{}
+		b.ReportAllocs()
+		for b.Loop() {
+			for _, path := range page {
+				if _, err := store.GetURL(ctx, path, DefaultURLExpiry); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(store.signed.Load())/float64(b.N), "signs/op")
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		store := &signingStorage{}
+		s := NewFileService(store, URLExpiry{})
+		b.ReportAllocs()
+		for b.Loop() {
+			for _, path := range page {
+				if _, err := s.GetFileURL(ctx, path, DefaultURLExpiry); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(store.signed.Load())/float64(b.N), "signs/op")
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/invitation"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/email"
@@ -27,6 +28,7 @@ type InvitationServiceImpl struct {
 	fileService         file.FileService
 	config              config.InvitationConfig
 	notificationService notification.Service
+	subscriptionService subscription.SubscriptionService
 }
 
 // NewInvitationService creates a new invitation service instance
@@ -39,6 +41,7 @@ func NewInvitationService(
 	fileService file.FileService,
 	invitationConfig config.InvitationConfig,
 	notificationService notification.Service,
+	subscriptionService subscription.SubscriptionService,
 ) invitation.InvitationService {
 	return &InvitationServiceImpl{
 		db:                  db,
@@ -49,6 +52,7 @@ func NewInvitationService(
 		fileService:         fileService,
 		config:              invitationConfig,
 		notificationService: notificationService,
+		subscriptionService: subscriptionService,
 	}
 }
 
@@ -72,10 +76,36 @@ func (s *InvitationServiceImpl) CreateAndSend(ctx context.Context, req invitatio
 		ExpiresAt:           expiresAt,
 	}
 
-	// Create invitation record (token is generated by database DEFAULT uuidv7())
-	created, err := s.invitationRepo.Create(ctx, inv)
+	// An active employee already holds a seat; anyone else claims one with this invitation
+	needsSeat := false
+	if s.subscriptionService != nil {
+		emp, err := s.employeeRepo.GetByID(ctx, req.EmployeeID)
+		if err != nil {
+			return invitation.Invitation{}, fmt.Errorf("failed to get employee: %w", err)
+		}
+		needsSeat = emp.EmploymentStatus != employee.EmploymentStatusActive
+	}
+
+	var created invitation.Invitation
+	err := postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		if needsSeat {
+			if err := s.subscriptionService.EnsureSeatAvailable(txCtx, req.CompanyID); err != nil {
+				return err
+			}
+		}
+
+		// Create invitation record (token is generated by database DEFAULT uuidv7())
+		var err error
+		created, err = s.invitationRepo.Create(txCtx, inv)
+		if err != nil {
+			return fmt.Errorf("failed to create invitation: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return invitation.Invitation{}, fmt.Errorf("failed to create invitation: %w", err)
+		return invitation.Invitation{}, err
 	}
 
 	// Build invitation link
@@ -110,7 +140,7 @@ func (s *InvitationServiceImpl) GetByToken(ctx context.Context, token string) (i
 	}
 
 	if inv.CompanyLogo != nil && *inv.CompanyLogo == "" {
-		fullURL, err := s.fileService.GetFileURL(ctx, *inv.CompanyLogo, 0)
+		fullURL, err := s.fileService.GetFileURL(ctx, *inv.CompanyLogo, s.fileService.URLExpiry().Default)
 		if err != nil {
 			return invitation.InvitationDetailResponse{}, fmt.Errorf("failed to get company logo URL: %w", err)
 		}
@@ -142,7 +172,7 @@ func (s *InvitationServiceImpl) ListMyInvitations(ctx context.Context, userEmail
 	for _, inv := range invitations {
 
 		if inv.CompanyLogo != nil && *inv.CompanyLogo != "" {
-			fullURL, err := s.fileService.GetFileURL(ctx, *inv.CompanyLogo, 0)
+			fullURL, err := s.fileService.GetFileURL(ctx, *inv.CompanyLogo, s.fileService.URLExpiry().Default)
 			if err != nil {
 				return []invitation.MyInvitationResponse{}, fmt.Errorf("failed to get company logo URL: %w", err)
 			}
@@ -208,7 +238,7 @@ func (s *InvitationServiceImpl) Accept(ctx context.Context, token, userID, userE
 
 	// Transaction: link user to employee, update user company/role, mark invitation accepted
 	err = postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+		txCtx := postgresql.ContextWithTx(ctx, tx)
 
 		// 1. Link employee to user
 		if err := s.employeeRepo.LinkUser(txCtx, inv.EmployeeID, userID, inv.CompanyID); err != nil {
@@ -0,0 +1,103 @@
+package leave
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/testutil"
+)
+
+func newAttachmentService() (*LeaveServiceImpl, *fakeFileService) {
+	files := &fakeFileService{files: map[string]string{
+		"leave/a.pdf": "company A attachment",
+		"leave/b.pdf": "company B attachment",
+	}}
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	return &LeaveServiceImpl{
+		LeaveRequestRepository: &fakeLeaveRequestRepo{
+			requests: map[string]leave.LeaveRequest{
+				"req-a": {ID: "req-a", EmployeeID: "emp-a1", StartDate: start, AttachmentURL: stringPtr("leave/a.pdf")},
+				"req-b": {ID: "req-b", EmployeeID: "emp-b1", StartDate: start, AttachmentURL: stringPtr("leave/b.pdf")},
+			},
+			companyOf: map[string]string{"req-a": "company-a", "req-b": "company-b"},
+		},
+		EmployeeRepository: &fakeEmployeeRepo{employees: []employee.Employee{
+			{ID: "emp-a1", CompanyID: "company-a", UserID: stringPtr("user-a1")},
+			{ID: "emp-a2", CompanyID: "company-a", UserID: stringPtr("user-a2")},
+			{ID: "emp-b1", CompanyID: "company-b", UserID: stringPtr("user-b1")},
+		}},
+		fileService: files,
+	}, files
+}
+
+func TestGetLeaveAttachment(t *testing.T) {
+	tests := []struct {
+		name    string
+		claims  map[string]any
+		request string
+		wantErr error
+	}{
+		{
+			name:    "owner of the same company",
+			claims:  map[string]any{"company_id": "company-a", "user_id": "owner-a", "role": "owner"},
+			request: "req-a",
+		},
+		{
+			name:    "employee downloads their own attachment",
+			claims:  map[string]any{"company_id": "company-a", "user_id": "user-a1", "role": "employee"},
+			request: "req-a",
+		},
+		{
+			name:    "employee of the same company",
+			claims:  map[string]any{"company_id": "company-a", "user_id": "user-a2", "role": "employee"},
+			request: "req-a",
+			wantErr: leave.ErrUnauthorizedAccess,
+		},
+		{
+			name:    "owner of another company",
+			claims:  map[string]any{"company_id": "company-a", "user_id": "owner-a", "role": "owner"},
+			request: "req-b",
+			wantErr: leave.ErrLeaveRequestNotFound,
+		},
+		{
+			name:    "manager of another company",
+			claims:  map[string]any{"company_id": "company-a", "user_id": "manager-a", "role": "manager"},
+			request: "req-b",
+			wantErr: leave.ErrLeaveRequestNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, files := newAttachmentService()
+
+			attachment, err := s.GetLeaveAttachment(testutil.ClaimsContext(t, tt.claims), tt.request)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetLeaveAttachment() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(files.opened) != 0 {
+					t.Errorf("opened %v, want no file opened", files.opened)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetLeaveAttachment() error = %v", err)
+			}
+			defer attachment.Content.Close()
+
+			body, _ := io.ReadAll(attachment.Content)
+			if string(body) != "company A attachment" {
+				t.Errorf("content = %q, want company A's attachment", body)
+			}
+			if attachment.ContentType != "application/pdf" || attachment.FileName != "leave-2026-03-02-attachment.pdf" {
+				t.Errorf("attachment = %q %q", attachment.ContentType, attachment.FileName)
+			}
+		})
+	}
+}
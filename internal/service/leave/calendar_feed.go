@@ -0,0 +1,39 @@
+package leave
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/google/uuid"
+)
+
+// RotateCalendarFeedNonce implements leave.LeaveService.
+// Feed tokens embed the nonce, so replacing it invalidates every previously issued feed URL.
+func (l *LeaveServiceImpl) RotateCalendarFeedNonce(ctx context.Context, employeeID string) (string, error) {
+	nonce := uuid.New().String()
+	if err := l.EmployeeRepository.SetCalendarFeedNonce(ctx, employeeID, nonce); err != nil {
+		return "", fmt.Errorf("failed to rotate calendar feed nonce: %w", err)
+	}
+	return nonce, nil
+}
+
+// GetCalendarFeedRequests implements leave.LeaveService.
+// Events are derived from the approved requests on every call; nothing about the feed is cached.
+func (l *LeaveServiceImpl) GetCalendarFeedRequests(ctx context.Context, employeeID string, nonce string) ([]leave.LeaveRequest, error) {
+	current, err := l.EmployeeRepository.GetCalendarFeedNonce(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar feed nonce: %w", err)
+	}
+	if current == nil || subtle.ConstantTimeCompare([]byte(*current), []byte(nonce)) != 1 {
+		return nil, leave.ErrInvalidCalendarFeedToken
+	}
+
+	requests, err := l.LeaveRequestRepository.GetApprovedByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approved leave requests: %w", err)
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,126 @@
+package leave
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
+	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
+	"github.com/jackc/pgx/v5"
+)
+
+// EscalateStaleApprovals implements leave.LeaveService.
+// Every request still waiting past its company's approval SLA is escalated once to the company owners;
+// companies with the auto_approve action have it approved on behalf of the first owner instead.
+// It returns how many requests were escalated.
+func (l *LeaveServiceImpl) EscalateStaleApprovals(ctx context.Context) (int, error) {
+	now := l.clock.Now()
+
+	stale, err := l.LeaveRequestRepository.GetStaleApprovals(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	escalated := 0
+	for _, s := range stale {
+		if err := l.escalateRequest(ctx, s, now); err != nil {
+			// Approved, rejected or escalated since it was listed
+			if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, leave.ErrLeaveAlreadyProcessed) {
+				continue
+			}
+			slog.Error("Failed to escalate leave request", "leave_request_id", s.Request.ID, "company_id", s.CompanyID, "error", err)
+			continue
+		}
+		escalated++
+	}
+
+	return escalated, nil
+}
+
+// escalateRequest marks one stale request as escalated, auto-approves it when the company allows,
+// and notifies the owners, all in one transaction
+func (l *LeaveServiceImpl) escalateRequest(ctx context.Context, s leave.StaleApproval, now time.Time) error {
+	owners, err := l.EmployeeRepository.GetOwnersByCompanyID(ctx, s.CompanyID)
+	if err != nil {
+		return fmt.Errorf("failed to get company owners: %w", err)
+	}
+
+	// Owners never approve their own leave, so the requester is left out
+	recipients := make([]employee.Employee, 0, len(owners))
+	for _, owner := range owners {
+		if owner.ID != s.Request.EmployeeID && owner.UserID != nil {
+			recipients = append(recipients, owner)
+		}
+	}
+
+	autoApprove := s.EscalationAction == company.LeaveEscalationAutoApprove && len(recipients) > 0
+
+	return postgresql.WithTransactionRetry(ctx, l.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		if err := l.LeaveRequestRepository.MarkEscalated(txCtx, s.Request.ID, now); err != nil {
+			return err
+		}
+
+		if autoApprove {
+			if _, err := l.approveInTx(txCtx, s.Request.ID, s.CompanyID, *recipients[0].UserID); err != nil {
+				return err
+			}
+		}
+
+		return l.enqueueLeaveEscalatedNotifications(txCtx, s, recipients, autoApprove, now)
+	})
+}
+
+// enqueueLeaveEscalatedNotifications records the owners' escalation notifications in the outbox.
+// ctx must carry the escalation transaction so the notifications commit or roll back with it.
+func (l *LeaveServiceImpl) enqueueLeaveEscalatedNotifications(ctx context.Context, s leave.StaleApproval, recipients []employee.Employee, autoApproved bool, now time.Time) error {
+	if l.notificationService == nil {
+		return nil
+	}
+
+	req := s.Request
+	employeeName := stringValue(req.EmployeeName)
+	leaveTypeName := stringValue(req.LeaveTypeName)
+	waitingHours := int(now.Sub(req.SubmittedAt).Hours())
+
+	title := "Leave Request Awaiting Approval"
+	message := fmt.Sprintf("%s's %s request from %s to %s has been waiting for approval for %d hours",
+		employeeName, leaveTypeName, req.StartDate.Format("02 Jan 2006"), req.EndDate.Format("02 Jan 2006"), waitingHours)
+	if autoApproved {
+		title = "Leave Request Auto-Approved"
+		message = fmt.Sprintf("%s's %s request from %s to %s was approved automatically after waiting %d hours",
+			employeeName, leaveTypeName, req.StartDate.Format("02 Jan 2006"), req.EndDate.Format("02 Jan 2006"), waitingHours)
+	}
+
+	for _, recipient := range recipients {
+		err := l.notificationService.Enqueue(ctx, notification.CreateNotificationRequest{
+			CompanyID:   s.CompanyID,
+			RecipientID: *recipient.UserID,
+			Type:        notification.TypeLeaveEscalated,
+			Title:       title,
+			Message:     message,
+			Data: map[string]interface{}{
+				"employee_id":      req.EmployeeID,
+				"employee_name":    employeeName,
+				"leave_request_id": req.ID,
+				"leave_type":       leaveTypeName,
+				"start_date":       req.StartDate.Format("2006-01-02"),
+				"end_date":         req.EndDate.Format("2006-01-02"),
+				"waiting_hours":    waitingHours,
+				"auto_approved":    autoApproved,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,321 @@
+package leave
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/service/file"
+	"github.com/jackc/pgx/v5"
+)
+
+// fakeLeaveRequestRepo keeps requests in memory, together with the company of their employee
+type fakeLeaveRequestRepo struct {
+	leave.LeaveRequestRepository
+	requests  map[string]leave.LeaveRequest
+	companyOf map[string]string // request ID -> company ID
+	locked    []string
+	updates   []leave.UpdateLeaveRequestRequest
+	updateErr error
+	overlap   bool
+}
+
+func (r *fakeLeaveRequestRepo) GetByID(_ context.Context, id string) (leave.LeaveRequest, error) {
+	req, ok := r.requests[id]
+	if !ok {
+		return leave.LeaveRequest{}, leave.ErrLeaveRequestNotFound
+	}
+	return req, nil
+}
+
+func (r *fakeLeaveRequestRepo) GetByIDInCompany(ctx context.Context, id, companyID string) (leave.LeaveRequest, error) {
+	if r.companyOf[id] != companyID {
+		return leave.LeaveRequest{}, leave.ErrLeaveRequestNotFound
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeLeaveRequestRepo) GetByCompanyID(_ context.Context, companyID string, _ leave.LeaveRequestFilter) ([]leave.LeaveRequest, int64, error) {
+	var requests []leave.LeaveRequest
+	for id, req := range r.requests {
+		if r.companyOf[id] == companyID {
+			requests = append(requests, req)
+		}
+	}
+	return requests, int64(len(requests)), nil
+}
+
+func (r *fakeLeaveRequestRepo) GetByIDForUpdate(ctx context.Context, id string) (leave.LeaveRequest, error) {
+	r.locked = append(r.locked, id)
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeLeaveRequestRepo) Update(_ context.Context, update leave.UpdateLeaveRequestRequest) error {
+	r.updates = append(r.updates, update)
+	return r.updateErr
+}
+
+func (r *fakeLeaveRequestRepo) CheckOverlapping(context.Context, string, time.Time, time.Time) (bool, error) {
+	return r.overlap, nil
+}
+
+func (r *fakeLeaveRequestRepo) Reopen(_ context.Context, id string, submittedAt time.Time) error {
+	req, ok := r.requests[id]
+	if !ok || req.Status != leave.LeaveRequestStatusRejected {
+		return pgx.ErrNoRows
+	}
+	req.Status = leave.LeaveRequestStatusWaitingApproval
+	req.ApprovedAt = nil
+	req.SubmittedAt = submittedAt
+	r.requests[id] = req
+	return nil
+}
+
+// fakeLeaveTypeRepo keeps leave types in memory
+type fakeLeaveTypeRepo struct {
+	leave.LeaveTypeRepository
+	types map[string]leave.LeaveType
+}
+
+func (r *fakeLeaveTypeRepo) GetByID(_ context.Context, id string) (leave.LeaveType, error) {
+	leaveType, ok := r.types[id]
+	if !ok {
+		return leave.LeaveType{}, pgx.ErrNoRows
+	}
+	return leaveType, nil
+}
+
+// txOwnerKey marks the transaction a test runs a call in, so fakeQuotaRepo can hold its row
+// lock for that transaction like FOR UPDATE does
+type txOwnerKey struct{}
+
+// fakeQuotaRepo keeps quotas in memory. With rowLock set, GetByEmployeeTypeYearForUpdate blocks
+// while another transaction holds the lock, until endTx releases it; readDelay widens the window
+// between a read and the writes that follow it. AddPendingQuota applies the same balance guard as
+// the SQL update.
+type fakeQuotaRepo struct {
+	leave.LeaveQuotaRepository
+	rowLock   chan struct{}
+	readDelay time.Duration
+
+	mu     sync.Mutex
+	quotas []leave.LeaveQuota
+	holder any
+}
+
+func (r *fakeQuotaRepo) GetByEmployeeTypeYearForUpdate(ctx context.Context, employeeID, leaveTypeID string, year int) (leave.LeaveQuota, error) {
+	if owner := ctx.Value(txOwnerKey{}); owner != nil && r.rowLock != nil {
+		r.mu.Lock()
+		held := r.holder == owner
+		r.mu.Unlock()
+		if !held {
+			r.rowLock <- struct{}{}
+			r.mu.Lock()
+			r.holder = owner
+			r.mu.Unlock()
+		}
+	}
+	defer time.Sleep(r.readDelay)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, quota := range r.quotas {
+		if quota.EmployeeID == employeeID && quota.LeaveTypeID == leaveTypeID && quota.Year == year {
+			return quota, nil
+		}
+	}
+	return leave.LeaveQuota{}, pgx.ErrNoRows
+}
+
+// GetByEmployeeTypeYear fills in AvailableQuota as the repository query computes it
+func (r *fakeQuotaRepo) GetByEmployeeTypeYear(_ context.Context, employeeID, leaveTypeID string, year int) (leave.LeaveQuota, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, quota := range r.quotas {
+		if quota.EmployeeID == employeeID && quota.LeaveTypeID == leaveTypeID && quota.Year == year {
+			available := quotaBalance(quota)
+			quota.AvailableQuota = &available
+			return quota, nil
+		}
+	}
+	return leave.LeaveQuota{}, pgx.ErrNoRows
+}
+
+func (r *fakeQuotaRepo) AddPendingQuota(_ context.Context, quotaID string, amount float64, allowNegative bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, quota := range r.quotas {
+		if quota.ID != quotaID {
+			continue
+		}
+		if !allowNegative && quotaBalance(quota)-amount < 0 {
+			return leave.ErrInsufficientQuota
+		}
+		pending := *quota.PendingQuota + amount
+		r.quotas[i].PendingQuota = &pending
+		return nil
+	}
+	return leave.ErrInsufficientQuota
+}
+
+func (r *fakeQuotaRepo) AddUsedQuota(_ context.Context, quotaID string, amount float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, quota := range r.quotas {
+		if quota.ID == quotaID {
+			used := *quota.UsedQuota + amount
+			r.quotas[i].UsedQuota = &used
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+func (r *fakeQuotaRepo) MovePendingToUsed(_ context.Context, quotaID string, amount float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, quota := range r.quotas {
+		if quota.ID == quotaID {
+			pending, used := *quota.PendingQuota-amount, *quota.UsedQuota+amount
+			r.quotas[i].PendingQuota, r.quotas[i].UsedQuota = &pending, &used
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+func (r *fakeQuotaRepo) RemovePendingQuota(_ context.Context, quotaID string, amount float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, quota := range r.quotas {
+		if quota.ID == quotaID {
+			pending := *quota.PendingQuota - amount
+			r.quotas[i].PendingQuota = &pending
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+// quotaBalance is the balance the SQL guard checks
+func quotaBalance(q leave.LeaveQuota) float64 {
+	return float64(*q.OpeningBalance+*q.EarnedQuota+*q.RolloverQuota+*q.AdjustmentQuota) - *q.UsedQuota - *q.PendingQuota
+}
+
+// endTx releases the row lock when owner holds it, as commit or rollback would
+func (r *fakeQuotaRepo) endTx(owner any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.holder == owner {
+		r.holder = nil
+		<-r.rowLock
+	}
+}
+
+// fakeCompanyRepo places every company in one timezone and one leave year
+type fakeCompanyRepo struct {
+	company.CompanyRepository
+	timezone      string
+	startMonth    int
+	startMonthErr error
+}
+
+func (r *fakeCompanyRepo) GetTimezone(context.Context, string) (string, error) {
+	return r.timezone, nil
+}
+
+func (r *fakeCompanyRepo) GetLeaveYearStartMonth(context.Context, string) (int, error) {
+	return r.startMonth, r.startMonthErr
+}
+
+// fakeCalendars builds every company's calendar from one holiday list and records the years asked for.
+// workingWeekdays overrides the default Monday to Friday week when set.
+type fakeCalendars struct {
+	holidays        []company.Holiday
+	workingWeekdays []time.Weekday
+	err             error
+	years           []int
+}
+
+func (c *fakeCalendars) Calendar(_ context.Context, _ string, year int) (company.Calendar, error) {
+	c.years = append(c.years, year)
+	if c.err != nil {
+		return company.Calendar{}, c.err
+	}
+	var inYear []company.Holiday
+	for _, h := range c.holidays {
+		if h.Date.Year() == year {
+			inYear = append(inYear, h)
+		}
+	}
+	calendar := company.NewCalendar(year, inYear)
+	if c.workingWeekdays != nil {
+		calendar.WorkingWeekdays = c.workingWeekdays
+	}
+	return calendar, nil
+}
+
+// fakeEmployeeRepo keeps employees in memory, looked up by ID or user ID
+type fakeEmployeeRepo struct {
+	employee.EmployeeRepository
+	employees []employee.Employee
+}
+
+func (r *fakeEmployeeRepo) GetByID(_ context.Context, id string) (employee.Employee, error) {
+	for _, emp := range r.employees {
+		if emp.ID == id {
+			return emp, nil
+		}
+	}
+	return employee.Employee{}, pgx.ErrNoRows
+}
+
+func (r *fakeEmployeeRepo) GetByUserID(_ context.Context, userID string) (employee.Employee, error) {
+	for _, emp := range r.employees {
+		if emp.UserID != nil && *emp.UserID == userID {
+			return emp, nil
+		}
+	}
+	return employee.Employee{}, pgx.ErrNoRows
+}
+
+// fakeAttendanceRepo records the attendances created, keyed by employee and date
+type fakeAttendanceRepo struct {
+	attendance.AttendanceRepository
+	existing map[string]bool // "employeeID/2006-01-02" -> already recorded
+	created  []attendance.Attendance
+}
+
+func (r *fakeAttendanceRepo) GetByEmployeeAndDate(_ context.Context, employeeID string, date time.Time, _ string) (*attendance.Attendance, error) {
+	if r.existing[employeeID+"/"+date.Format("2006-01-02")] {
+		return &attendance.Attendance{EmployeeID: employeeID, Date: date}, nil
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (r *fakeAttendanceRepo) Create(_ context.Context, a attendance.Attendance) (attendance.Attendance, error) {
+	r.created = append(r.created, a)
+	return a, nil
+}
+
+// fakeFileService serves files from memory and records which were opened
+type fakeFileService struct {
+	file.FileService
+	files  map[string]string
+	opened []string
+}
+
+func (f *fakeFileService) OpenFile(_ context.Context, path string) (io.ReadCloser, error) {
+	f.opened = append(f.opened, path)
+	return io.NopCloser(strings.NewReader(f.files[path])), nil
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
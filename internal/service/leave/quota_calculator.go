@@ -4,69 +4,101 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
 )
 
 type QuotaCalculator struct {
+	clock clock.Clock
 }
 
-func NewQuotaCalculator() *QuotaCalculator {
-	return &QuotaCalculator{}
+// QuotaMatch is the outcome of evaluating a leave type's quota rules for one employee
+type QuotaMatch struct {
+	Quota        float64
+	RuleIndex    int  // Index in QuotaRules.Rules of the rule that decided the quota, -1 when none did
+	UsedDefault  bool // The quota is the leave type's default_quota
+	TenureMonths int  // Tenure the rules were evaluated at
+}
+
+func NewQuotaCalculator(clk clock.Clock) *QuotaCalculator {
+	return &QuotaCalculator{clock: clk}
 }
 
 func (c *QuotaCalculator) CalculateQuota(ctx context.Context, employee employee.Employee, leaveType leave.LeaveType) (float64, error) {
+	return c.CalculateQuotaAt(ctx, employee, leaveType, c.clock.Now())
+}
+
+// CalculateQuotaAt calculates quota with tenure measured at asOf instead of now
+func (c *QuotaCalculator) CalculateQuotaAt(ctx context.Context, employee employee.Employee, leaveType leave.LeaveType, asOf time.Time) (float64, error) {
+	match, err := c.MatchQuotaAt(ctx, employee, leaveType, asOf)
+	if err != nil {
+		return 0, err
+	}
+	return match.Quota, nil
+}
+
+// MatchQuotaAt works like CalculateQuotaAt but also reports which rule decided the quota
+func (c *QuotaCalculator) MatchQuotaAt(ctx context.Context, employee employee.Employee, leaveType leave.LeaveType, asOf time.Time) (QuotaMatch, error) {
+	tenureMonths := c.calculateTenureMonths(employee.HireDate, asOf)
+
 	if leaveType.QuotaCalculationType == "fixed" {
-		return float64(leaveType.QuotaRules.DefaultQuota), nil
+		return QuotaMatch{Quota: float64(leaveType.QuotaRules.DefaultQuota), RuleIndex: -1, UsedDefault: true, TenureMonths: tenureMonths}, nil
 	}
 
-	var quota float64
+	var match QuotaMatch
 	var err error
 
 	switch leaveType.QuotaRules.Type {
 	case "tenure":
-		quota, err = c.calculateTenureBased(&employee, &leaveType.QuotaRules)
+		match, err = c.calculateTenureBased(&employee, &leaveType.QuotaRules, asOf)
 	case "position":
-		quota, err = c.calculatePositionBased(ctx, &employee, &leaveType.QuotaRules)
+		match, err = c.calculatePositionBased(ctx, &employee, &leaveType.QuotaRules)
 	case "grade":
-		quota, err = c.calculateGradeBased(ctx, &employee, &leaveType.QuotaRules)
+		match, err = c.calculateGradeBased(ctx, &employee, &leaveType.QuotaRules)
 	case "employment_type":
-		quota, err = c.calculateEmploymentTypeBased(&employee, &leaveType.QuotaRules)
+		match, err = c.calculateEmploymentTypeBased(&employee, &leaveType.QuotaRules)
 	case "combined":
-		quota, err = c.calculateCombined(ctx, &employee, &leaveType.QuotaRules)
+		match, err = c.calculateCombined(ctx, &employee, &leaveType.QuotaRules, asOf)
 	default:
 		// c.logger.Warn("Unknown quota calculation type, using default",
 		// 	zap.String("type", leaveType.QuotaRules.Type),
 		// )
-		return 0, fmt.Errorf("unknown quota calculation type: %s", leaveType.QuotaRules.Type)
+		return QuotaMatch{}, fmt.Errorf("unknown quota calculation type: %s", leaveType.QuotaRules.Type)
 		// quota = float64(leaveType.QuotaPerYear)
 	}
 
 	if err != nil {
-		return 0, err
+		return QuotaMatch{}, err
 	}
 
-	return quota, nil
+	match.TenureMonths = tenureMonths
+	return match, nil
 }
 
 // calculateTenureBased calculates quota based on employee tenure
 func (c *QuotaCalculator) calculateTenureBased(
 	emp *employee.Employee,
 	rules *leave.QuotaRules,
-) (float64, error) {
+	asOf time.Time,
+) (QuotaMatch, error) {
 	if len(rules.Rules) == 0 {
-		return 0, errors.New("no tenure rules defined")
+		if match, ok := c.defaultQuota(emp, rules); ok {
+			return match, nil
+		}
+		return QuotaMatch{}, errors.New("no tenure rules defined")
 	}
 
-	tenureMonths := c.calculateTenureMonths(emp.HireDate)
+	tenureMonths := c.calculateTenureMonths(emp.HireDate, asOf)
 
 	// c.logger.Debug("Calculating tenure-based quota",
 	// 	zap.Int("tenure_months", tenureMonths),
 	// )
 
-	for _, rule := range rules.Rules {
+	for i, rule := range rules.Rules {
 		minMonths := 0
 		if rule.MinMonths != nil {
 			minMonths = *rule.MinMonths
@@ -83,16 +115,16 @@ func (c *QuotaCalculator) calculateTenureBased(
 			// 	zap.Int("max_months", maxMonths),
 			// 	zap.Float64("quota", rule.Quota),
 			// )
-			return rule.Quota, nil
+			return QuotaMatch{Quota: rule.Quota, RuleIndex: i}, nil
 		}
 	}
 
 	// Return default quota if no rule matched
-	if rules.DefaultQuota > 0 {
-		return rules.DefaultQuota, nil
+	if match, ok := c.defaultQuota(emp, rules); ok {
+		return match, nil
 	}
 
-	return 0, fmt.Errorf("no matching tenure rule for %d months", tenureMonths)
+	return QuotaMatch{}, fmt.Errorf("no matching tenure rule for %d months", tenureMonths)
 }
 
 // calculatePositionBased calculates quota based on position
@@ -100,33 +132,36 @@ func (c *QuotaCalculator) calculatePositionBased(
 	ctx context.Context,
 	emp *employee.Employee,
 	rules *leave.QuotaRules,
-) (float64, error) {
+) (QuotaMatch, error) {
 	if len(rules.Rules) == 0 {
-		return 0, errors.New("no position rules defined")
+		if match, ok := c.defaultQuota(emp, rules); ok {
+			return match, nil
+		}
+		return QuotaMatch{}, errors.New("no position rules defined")
 	}
 
 	// c.logger.Debug("Calculating position-based quota",
 	// 	zap.String("position_id", emp.PositionID),
 	// )
 
-	for _, rule := range rules.Rules {
+	for i, rule := range rules.Rules {
 		for _, positionID := range rule.PositionIDs {
 			if positionID == emp.PositionID {
 				// c.logger.Debug("Matched position rule",
 				// 	zap.String("position_id", positionID),
 				// 	zap.Float64("quota", rule.Quota),
 				// )
-				return rule.Quota, nil
+				return QuotaMatch{Quota: rule.Quota, RuleIndex: i}, nil
 			}
 		}
 	}
 
 	// Return default quota if no rule matched
-	if rules.DefaultQuota > 0 {
-		return rules.DefaultQuota, nil
+	if match, ok := c.defaultQuota(emp, rules); ok {
+		return match, nil
 	}
 
-	return 0, fmt.Errorf("no matching position rule for position %s", emp.PositionID)
+	return QuotaMatch{}, fmt.Errorf("no matching position rule for position %s", emp.PositionID)
 }
 
 // calculateGradeBased calculates quota based on grade
@@ -134,74 +169,77 @@ func (c *QuotaCalculator) calculateGradeBased(
 	ctx context.Context,
 	emp *employee.Employee,
 	rules *leave.QuotaRules,
-) (float64, error) {
+) (QuotaMatch, error) {
 	if emp.GradeID == "" {
-		// c.logger.Warn("Employee has no grade, using default quota",
-		// 	zap.String("employee_id", emp.ID),
-		// )
-		if rules.DefaultQuota > 0 {
-			return rules.DefaultQuota, nil
+		if match, ok := c.defaultQuota(emp, rules); ok {
+			return match, nil
 		}
-		return 0, errors.New("employee has no grade")
+		return QuotaMatch{}, errors.New("employee has no grade")
 	}
 
 	if len(rules.Rules) == 0 {
-		return 0, errors.New("no grade rules defined")
+		if match, ok := c.defaultQuota(emp, rules); ok {
+			return match, nil
+		}
+		return QuotaMatch{}, errors.New("no grade rules defined")
 	}
 
 	// c.logger.Debug("Calculating grade-based quota",
 	// 	zap.String("grade_id", emp.GradeID),
 	// )
 
-	for _, rule := range rules.Rules {
+	for i, rule := range rules.Rules {
 		for _, gradeID := range rule.GradeIDs {
 			if gradeID == emp.GradeID {
 				// c.logger.Debug("Matched grade rule",
 				// 	zap.String("grade_id", gradeID),
 				// 	zap.Float64("quota", rule.Quota),
 				// )
-				return rule.Quota, nil
+				return QuotaMatch{Quota: rule.Quota, RuleIndex: i}, nil
 			}
 		}
 	}
 
 	// Return default quota if no rule matched
-	if rules.DefaultQuota > 0 {
-		return rules.DefaultQuota, nil
+	if match, ok := c.defaultQuota(emp, rules); ok {
+		return match, nil
 	}
 
-	return 0, fmt.Errorf("no matching grade rule for grade %s", emp.GradeID)
+	return QuotaMatch{}, fmt.Errorf("no matching grade rule for grade %s", emp.GradeID)
 }
 
 // calculateEmploymentTypeBased calculates quota based on employment type
 func (c *QuotaCalculator) calculateEmploymentTypeBased(
 	emp *employee.Employee,
 	rules *leave.QuotaRules,
-) (float64, error) {
+) (QuotaMatch, error) {
 	if len(rules.Rules) == 0 {
-		return 0, errors.New("no employment type rules defined")
+		if match, ok := c.defaultQuota(emp, rules); ok {
+			return match, nil
+		}
+		return QuotaMatch{}, errors.New("no employment type rules defined")
 	}
 
 	// c.logger.Debug("Calculating employment type-based quota",
 	// 	zap.String("employment_type", string(emp.EmploymentType)),
 	// )
 
-	for _, rule := range rules.Rules {
+	for i, rule := range rules.Rules {
 		if rule.EmploymentType == string(emp.EmploymentType) {
 			// c.logger.Debug("Matched employment type rule",
 			// 	zap.String("employment_type", rule.EmploymentType),
 			// 	zap.Float64("quota", rule.Quota),
 			// )
-			return rule.Quota, nil
+			return QuotaMatch{Quota: rule.Quota, RuleIndex: i}, nil
 		}
 	}
 
 	// Return default quota if no rule matched
-	if rules.DefaultQuota > 0 {
-		return rules.DefaultQuota, nil
+	if match, ok := c.defaultQuota(emp, rules); ok {
+		return match, nil
 	}
 
-	return 0, fmt.Errorf("no matching employment type rule for %s", emp.EmploymentType)
+	return QuotaMatch{}, fmt.Errorf("no matching employment type rule for %s", emp.EmploymentType)
 }
 
 // calculateCombined handles complex rules with multiple conditions
@@ -209,12 +247,16 @@ func (c *QuotaCalculator) calculateCombined(
 	ctx context.Context,
 	emp *employee.Employee,
 	rules *leave.QuotaRules,
-) (float64, error) {
+	asOf time.Time,
+) (QuotaMatch, error) {
 	if len(rules.Rules) == 0 {
-		return 0, errors.New("no combined rules defined")
+		if match, ok := c.defaultQuota(emp, rules); ok {
+			return match, nil
+		}
+		return QuotaMatch{}, errors.New("no combined rules defined")
 	}
 
-	tenureMonths := c.calculateTenureMonths(emp.HireDate)
+	tenureMonths := c.calculateTenureMonths(emp.HireDate, asOf)
 
 	// c.logger.Debug("Calculating combined quota",
 	// 	zap.String("employee_id", emp.ID),
@@ -222,120 +264,35 @@ func (c *QuotaCalculator) calculateCombined(
 	// )
 
 	// Evaluate rules in order (first match wins)
-	for _, rule := range rules.Rules {
-		if rule.Conditions == nil {
-			continue
-		}
-
-		matched := c.matchesConditions(emp, tenureMonths, rule.Conditions)
-
-		if matched {
-			// c.logger.Debug("Matched combined rule",
-			// 	zap.Int("rule_index", i),
-			// 	zap.Float64("quota", rule.Quota),
-			// )
-			return rule.Quota, nil
-		}
+	if i, ok := rules.MatchCombinedRule(emp.PositionID, emp.GradeID, string(emp.EmploymentType), tenureMonths); ok {
+		return QuotaMatch{Quota: rules.Rules[i].Quota, RuleIndex: i}, nil
 	}
 
 	// Return default quota if no rule matched
-	if rules.DefaultQuota > 0 {
-		// c.logger.Debug("No rule matched, using default quota",
-		// 	zap.Float64("default_quota", rules.DefaultQuota),
-		// )
-		return rules.DefaultQuota, nil
+	if match, ok := c.defaultQuota(emp, rules); ok {
+		return match, nil
 	}
 
-	return 0, errors.New("no matching combined rule")
+	return QuotaMatch{}, errors.New("no matching combined rule")
 }
 
-// matchesConditions checks if employee matches all conditions
-func (c *QuotaCalculator) matchesConditions(
-	emp *employee.Employee,
-	tenureMonths int,
-	conditions *leave.QuotaConditions,
-) bool {
-	// Check position IDs
-	if len(conditions.PositionIDs) > 0 {
-		matched := false
-		for _, posID := range conditions.PositionIDs {
-			if posID == emp.PositionID {
-				matched = true
-				break
-			}
-		}
-		if !matched {
-			// c.logger.Debug("Position condition not matched",
-			// 	zap.String("employee_position", emp.PositionID),
-			// 	zap.Strings("required_positions", conditions.PositionIDs),
-			// )
-			return false
-		}
-	}
-
-	// Check grade IDs
-	if len(conditions.GradeIDs) > 0 {
-		if emp.GradeID == "" {
-			// c.logger.Debug("Grade condition not matched: employee has no grade")
-			return false
-		}
-
-		matched := false
-		for _, gradeID := range conditions.GradeIDs {
-			if gradeID == emp.GradeID {
-				matched = true
-				break
-			}
-		}
-		if !matched {
-			// c.logger.Debug("Grade condition not matched",
-			// 	zap.String("employee_grade", emp.GradeID),
-			// 	zap.Strings("required_grades", conditions.GradeIDs),
-			// )
-			return false
-		}
-	}
-
-	// Check employment type
-	if conditions.EmploymentType != "" {
-		if conditions.EmploymentType != string(emp.EmploymentType) {
-			// c.logger.Debug("Employment type condition not matched",
-			// 	zap.String("employee_type", string(emp.EmploymentType)),
-			// 	zap.String("required_type", conditions.EmploymentType),
-			// )
-			return false
-		}
-	}
-
-	// Check minimum tenure
-	if conditions.MinTenureMonths != nil {
-		if tenureMonths < *conditions.MinTenureMonths {
-			// c.logger.Debug("Min tenure condition not matched",
-			// 	zap.Int("employee_tenure", tenureMonths),
-			// 	zap.Int("required_min", *conditions.MinTenureMonths),
-			// )
-			return false
-		}
-	}
-
-	// Check maximum tenure
-	if conditions.MaxTenureMonths != nil {
-		if tenureMonths >= *conditions.MaxTenureMonths {
-			// c.logger.Debug("Max tenure condition not matched",
-			// 	zap.Int("employee_tenure", tenureMonths),
-			// 	zap.Int("required_max", *conditions.MaxTenureMonths),
-			// )
-			return false
-		}
+// defaultQuota returns the leave type's fallback quota for employees no rule matched.
+// A zero default means such employees are not eligible.
+func (c *QuotaCalculator) defaultQuota(emp *employee.Employee, rules *leave.QuotaRules) (QuotaMatch, bool) {
+	if rules.DefaultQuota <= 0 {
+		return QuotaMatch{}, false
 	}
 
-	// c.logger.Debug("All conditions matched")
-	return true
+	slog.Info("No quota rule matched, using default quota",
+		"employee_id", emp.ID,
+		"rule_type", rules.Type,
+		"default_quota", rules.DefaultQuota,
+	)
+	return QuotaMatch{Quota: rules.DefaultQuota, RuleIndex: -1, UsedDefault: true}, true
 }
 
-// calculateTenureMonths calculates tenure in months
-func (c *QuotaCalculator) calculateTenureMonths(hireDate time.Time) int {
-	now := time.Now()
+// calculateTenureMonths calculates tenure in months as of the given date
+func (c *QuotaCalculator) calculateTenureMonths(hireDate time.Time, now time.Time) int {
 
 	years := now.Year() - hireDate.Year()
 	months := int(now.Month()) - int(hireDate.Month())
@@ -355,16 +312,18 @@ func (c *QuotaCalculator) calculateTenureMonths(hireDate time.Time) int {
 	return totalMonths
 }
 
-// CalculateAccruedQuota calculates accrued quota for monthly accrual method
+// CalculateAccruedQuota calculates accrued quota for monthly accrual method,
+// counting months from the start of the leave year asOfDate falls in
 func (c *QuotaCalculator) CalculateAccruedQuota(
 	hireDate time.Time,
 	annualQuota float64,
 	asOfDate time.Time,
+	yearStartMonth int,
 ) float64 {
-	yearStart := time.Date(asOfDate.Year(), 1, 1, 0, 0, 0, 0, asOfDate.Location())
+	yearStart := leave.LeaveYearStart(leave.LeaveYear(asOfDate, yearStartMonth), yearStartMonth, asOfDate.Location())
 
-	// If hired this year, start from hire date
-	if hireDate.Year() == asOfDate.Year() {
+	// If hired this leave year, start from hire date
+	if !hireDate.Before(yearStart) {
 		yearStart = hireDate
 	}
 
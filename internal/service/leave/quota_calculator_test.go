@@ -0,0 +1,81 @@
+package leave
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+)
+
+func TestCombinedQuotaRules(t *testing.T) {
+	asOf := time.Date(2026, time.June, 15, 0, 0, 0, 0, time.UTC)
+	months := func(n int) *int { return &n }
+
+	// Ordered from most to least specific; the first match wins
+	rules := leave.QuotaRules{
+		Type: "combined",
+		Rules: []leave.QuotaRule{
+			{Conditions: &leave.QuotaConditions{PositionIDs: []string{"pos-manager"}, MinTenureMonths: months(60)}, Quota: 18},
+			{Conditions: &leave.QuotaConditions{GradeIDs: []string{"grade-3", "grade-4"}, EmploymentType: "permanent"}, Quota: 15},
+			{Conditions: &leave.QuotaConditions{EmploymentType: "contract"}, Quota: 10},
+			{Conditions: &leave.QuotaConditions{MinTenureMonths: months(12), MaxTenureMonths: months(60)}, Quota: 14},
+		},
+	}
+	withDefault := rules
+	withDefault.DefaultQuota = 12
+
+	tests := []struct {
+		name         string
+		rules        leave.QuotaRules
+		positionID   string
+		gradeID      string
+		employment   employee.EmploymentType
+		tenureMonths int
+		wantQuota    float64
+		wantRule     int // -1 for the default quota
+		wantEligible bool
+	}{
+		{name: "manager at exactly the minimum tenure", rules: rules, positionID: "pos-manager", employment: "permanent", tenureMonths: 60, wantQuota: 18, wantRule: 0, wantEligible: true},
+		{name: "manager one month short falls through to grade", rules: rules, positionID: "pos-manager", gradeID: "grade-3", employment: "permanent", tenureMonths: 59, wantQuota: 15, wantRule: 1, wantEligible: true},
+		{name: "grade matches but employment type does not", rules: rules, gradeID: "grade-4", employment: "contract", tenureMonths: 30, wantQuota: 10, wantRule: 2, wantEligible: true},
+		{name: "grade condition needs a grade", rules: rules, employment: "permanent", tenureMonths: 30, wantQuota: 14, wantRule: 3, wantEligible: true},
+		{name: "tenure range includes its minimum", rules: rules, employment: "probation", tenureMonths: 12, wantQuota: 14, wantRule: 3, wantEligible: true},
+		{name: "tenure range excludes its maximum", rules: withDefault, employment: "permanent", tenureMonths: 60, wantQuota: 12, wantRule: -1, wantEligible: true},
+		{name: "no match falls back to the default quota", rules: withDefault, employment: "probation", tenureMonths: 3, wantQuota: 12, wantRule: -1, wantEligible: true},
+		{name: "no match without a default quota", rules: rules, employment: "probation", tenureMonths: 3, wantEligible: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emp := employee.Employee{
+				ID:             "emp-1",
+				PositionID:     tt.positionID,
+				GradeID:        tt.gradeID,
+				EmploymentType: tt.employment,
+				HireDate:       asOf.AddDate(0, -tt.tenureMonths, 0),
+			}
+			leaveType := leave.LeaveType{ID: "annual", QuotaCalculationType: "rule_based", QuotaRules: tt.rules}
+
+			match, err := NewQuotaCalculator(clock.NewFake(asOf)).MatchQuotaAt(context.Background(), emp, leaveType, asOf)
+			if tt.wantEligible {
+				if err != nil {
+					t.Fatalf("MatchQuotaAt() error = %v", err)
+				}
+				if match.Quota != tt.wantQuota || match.RuleIndex != tt.wantRule || match.TenureMonths != tt.tenureMonths {
+					t.Errorf("MatchQuotaAt() = %+v, want quota %.0f from rule %d at %d months", match, tt.wantQuota, tt.wantRule, tt.tenureMonths)
+				}
+			} else if err == nil {
+				t.Errorf("MatchQuotaAt() = %+v, want no matching rule", match)
+			}
+
+			// Eligibility for a request must agree with whether the calculator grants a quota
+			r := &RequestService{clock: clock.NewFake(asOf)}
+			if got := r.checkCombinedEligibility(emp, &tt.rules); got != tt.wantEligible {
+				t.Errorf("checkCombinedEligibility() = %v, want %v", got, tt.wantEligible)
+			}
+		})
+	}
+}
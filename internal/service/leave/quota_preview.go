@@ -0,0 +1,115 @@
+package leave
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/scope"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// PreviewQuota implements leave.LeaveService.
+// It runs the quota calculator the same way yearly assignment does, so HR can check a leave type's
+// rules against a real employee before any quota exists. A year of 0 means the current leave year.
+// Tenure is measured now for the current leave year and at the first day of any other year.
+func (l *LeaveServiceImpl) PreviewQuota(ctx context.Context, leaveTypeID, employeeID string, year int) (leave.QuotaPreviewResponse, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return leave.QuotaPreviewResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return leave.QuotaPreviewResponse{}, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	leaveType, err := l.GetLeaveType(ctx, leaveTypeID)
+	if err != nil {
+		return leave.QuotaPreviewResponse{}, err
+	}
+	if leaveType.CompanyID != companyID {
+		return leave.QuotaPreviewResponse{}, leave.ErrLeaveTypeNotFound
+	}
+
+	emp, err := l.EmployeeRepository.GetByID(ctx, employeeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return leave.QuotaPreviewResponse{}, employee.ErrEmployeeNotFound
+		}
+		return leave.QuotaPreviewResponse{}, fmt.Errorf("failed to get employee: %w", err)
+	}
+	if emp.CompanyID != companyID {
+		return leave.QuotaPreviewResponse{}, employee.ErrEmployeeNotFound
+	}
+	if branchID, scoped := scope.BranchIDFromContext(ctx); scoped && emp.BranchID != branchID {
+		return leave.QuotaPreviewResponse{}, leave.ErrUnauthorizedAccess
+	}
+
+	startMonth, err := l.quotaService.LeaveYearStartMonth(ctx, companyID)
+	if err != nil {
+		return leave.QuotaPreviewResponse{}, err
+	}
+	now := l.clock.Now()
+	currentYear := leave.LeaveYear(now, startMonth)
+	if year == 0 {
+		year = currentYear
+	}
+	asOf := now
+	if year != currentYear {
+		asOf = leave.LeaveYearStart(year, startMonth, l.requestService.companyLocation(ctx, companyID))
+	}
+
+	preview := leave.QuotaPreviewResponse{
+		LeaveTypeID:   leaveType.ID,
+		LeaveTypeName: leaveType.Name,
+		EmployeeID:    emp.ID,
+		EmployeeName:  emp.FullName,
+		Year:          year,
+		RuleType:      leaveType.QuotaRules.Type,
+		AccrualMethod: leaveType.AccrualMethod,
+	}
+	if leaveType.QuotaCalculationType == "fixed" {
+		preview.RuleType = "fixed"
+	}
+
+	if leaveType.HasQuota != nil && !*leaveType.HasQuota {
+		reason := "leave type does not use quotas"
+		preview.IneligibleReason = &reason
+		return preview, nil
+	}
+
+	match, err := l.quotaService.calculator.MatchQuotaAt(ctx, emp, leaveType, asOf)
+	if err != nil {
+		// Same outcome as yearly assignment: the employee gets no quota for this leave type
+		reason := err.Error()
+		preview.IneligibleReason = &reason
+		return preview, nil
+	}
+
+	preview.TenureMonths = match.TenureMonths
+	preview.UsedDefault = match.UsedDefault
+	preview.Quota = match.Quota
+	if match.RuleIndex >= 0 {
+		index := match.RuleIndex
+		rule := leaveType.QuotaRules.Rules[index]
+		preview.MatchedRuleIndex = &index
+		preview.MatchedRule = &rule
+	}
+	if match.Quota <= 0 {
+		reason := "calculated quota is 0"
+		preview.IneligibleReason = &reason
+		return preview, nil
+	}
+
+	preview.Eligible = true
+	preview.OpeningBalance = int(match.Quota)
+	if leaveType.AccrualMethod != nil && *leaveType.AccrualMethod == "monthly" {
+		preview.OpeningBalance = 0
+		preview.EarnedQuota = int(l.quotaService.calculator.CalculateAccruedQuota(emp.HireDate, match.Quota, asOf, startMonth))
+	}
+
+	return preview, nil
+}
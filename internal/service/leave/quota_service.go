@@ -2,13 +2,18 @@ package leave
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"time"
 
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/jackc/pgx/v5"
 )
@@ -18,19 +23,67 @@ type QuotaService struct {
 	leave.LeaveTypeRepository
 	leave.LeaveQuotaRepository
 	employee.EmployeeRepository
-	calculator *QuotaCalculator
+	companyRepo company.CompanyRepository
+	calculator  *QuotaCalculator
+	clock       clock.Clock
 }
 
-func NewQuotaService(db *database.DB, leaveTypeRepository leave.LeaveTypeRepository, leaveQuotaRepository leave.LeaveQuotaRepository, employeeRepository employee.EmployeeRepository, calculator *QuotaCalculator) *QuotaService {
+func NewQuotaService(db *database.DB, leaveTypeRepository leave.LeaveTypeRepository, leaveQuotaRepository leave.LeaveQuotaRepository, employeeRepository employee.EmployeeRepository, companyRepository company.CompanyRepository, calculator *QuotaCalculator, clk clock.Clock) *QuotaService {
 	return &QuotaService{
 		db:                   db,
 		LeaveTypeRepository:  leaveTypeRepository,
 		LeaveQuotaRepository: leaveQuotaRepository,
 		EmployeeRepository:   employeeRepository,
+		companyRepo:          companyRepository,
 		calculator:           calculator,
+		clock:                clk,
 	}
 }
 
+// LeaveYearStartMonth returns the month the company's leave year starts in
+func (q *QuotaService) LeaveYearStartMonth(ctx context.Context, companyID string) (int, error) {
+	return leaveYearStartMonth(ctx, q.companyRepo, companyID)
+}
+
+// leaveYearStartMonth reads the month a company's leave year starts in. A failed read is
+// returned rather than assuming January, which would charge quota to the wrong year.
+func leaveYearStartMonth(ctx context.Context, companies company.CompanyRepository, companyID string) (int, error) {
+	month, err := companies.GetLeaveYearStartMonth(ctx, companyID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get leave year start month: %w", err)
+	}
+	return month, nil
+}
+
+// CurrentLeaveYear returns the company's leave year as of now
+func (q *QuotaService) CurrentLeaveYear(ctx context.Context, companyID string) (int, error) {
+	startMonth, err := q.LeaveYearStartMonth(ctx, companyID)
+	if err != nil {
+		return 0, err
+	}
+	return leave.LeaveYear(q.clock.Now(), startMonth), nil
+}
+
+// requestLeaveYear returns the leave year whose quota holds a request's days: the year it was
+// created in. Every move of those days, from reservation to approval, rejection or repair, uses
+// it, so a request decided after the next leave year starts still balances its own quota.
+func (q *QuotaService) requestLeaveYear(ctx context.Context, request leave.LeaveRequest) (int, error) {
+	return q.employeeLeaveYear(ctx, request.EmployeeID, request.CreatedAt)
+}
+
+// employeeLeaveYear returns the leave year t falls in for the company of the employee
+func (q *QuotaService) employeeLeaveYear(ctx context.Context, employeeID string, t time.Time) (int, error) {
+	emp, err := q.EmployeeRepository.GetByID(ctx, employeeID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get employee: %w", err)
+	}
+	startMonth, err := q.LeaveYearStartMonth(ctx, emp.CompanyID)
+	if err != nil {
+		return 0, err
+	}
+	return leave.LeaveYear(t, startMonth), nil
+}
+
 // AssignLeaveQuotasForEmployee assigns leave quotas for a single employee based on all active leave types.
 // This is used when creating a new employee (including owner) to automatically assign eligible leave quotas.
 // It checks eligibility based on QuotaRules for each leave type.
@@ -42,6 +95,10 @@ func (q *QuotaService) AssignLeaveQuotasForEmployee(ctx context.Context, emp emp
 	}
 
 	assignedQuotas := make([]leave.LeaveQuota, 0)
+	startMonth, err := q.LeaveYearStartMonth(ctx, emp.CompanyID)
+	if err != nil {
+		return nil, err
+	}
 
 	for _, leaveType := range leaveTypes {
 		// Skip if leave type doesn't have quota
@@ -84,11 +141,14 @@ func (q *QuotaService) AssignLeaveQuotasForEmployee(ctx context.Context, emp emp
 
 		if leaveType.AccrualMethod != nil && *leaveType.AccrualMethod == "monthly" {
 			// For monthly accrual, calculate pro-rated quota
-			accruedQuota := q.calculator.CalculateAccruedQuota(emp.HireDate, calculatedQuota, time.Now())
+			accruedQuota := q.calculator.CalculateAccruedQuota(emp.HireDate, calculatedQuota, q.clock.Now(), startMonth)
 			openingBalance = 0
 			earnedQuota = int(accruedQuota)
 		}
 
+		// Debt from the previous year is settled by starting the new year with it as used quota
+		carriedDebt := q.previousYearDebt(ctx, emp.ID, leaveType, year)
+
 		// Create the quota
 		zeroFloat := 0.0
 		zeroInt := 0
@@ -100,7 +160,7 @@ func (q *QuotaService) AssignLeaveQuotasForEmployee(ctx context.Context, emp emp
 			EarnedQuota:     &earnedQuota,
 			RolloverQuota:   &zeroInt,
 			AdjustmentQuota: &zeroInt,
-			UsedQuota:       &zeroFloat,
+			UsedQuota:       &carriedDebt,
 			PendingQuota:    &zeroFloat,
 		}
 
@@ -127,6 +187,21 @@ func (q *QuotaService) AssignLeaveQuotasForEmployee(ctx context.Context, emp emp
 	return assignedQuotas, nil
 }
 
+// previousYearDebt returns the negative balance left on last year's quota for leave types that
+// allow going negative, or 0 when there is none
+func (q *QuotaService) previousYearDebt(ctx context.Context, employeeID string, leaveType leave.LeaveType, year int) float64 {
+	if leaveType.AllowNegativeBalance == nil || !*leaveType.AllowNegativeBalance {
+		return 0
+	}
+
+	previous, err := q.LeaveQuotaRepository.GetByEmployeeTypeYear(ctx, employeeID, leaveType.ID, year-1)
+	if err != nil || previous.AvailableQuota == nil {
+		return 0
+	}
+
+	return negativeBalance(*previous.AvailableQuota)
+}
+
 // AssignLeaveQuotasForEmployeeByID is a convenience method that fetches the employee first
 func (q *QuotaService) AssignLeaveQuotasForEmployeeByID(ctx context.Context, employeeID string, year int) ([]leave.LeaveQuota, error) {
 	emp, err := q.EmployeeRepository.GetByID(ctx, employeeID)
@@ -142,6 +217,10 @@ func (q *QuotaService) AllocateTypeQuota(ctx context.Context, leaveType leave.Le
 	if err != nil {
 		return fmt.Errorf("failed to get employee: %w", err)
 	}
+	startMonth, err := q.LeaveYearStartMonth(ctx, companyID)
+	if err != nil {
+		return err
+	}
 
 	for _, employee := range employees {
 		exists, err := q.LeaveQuotaRepository.GetByEmployeeTypeYear(ctx, employee.ID, leaveType.ID, year)
@@ -165,7 +244,7 @@ func (q *QuotaService) AllocateTypeQuota(ctx context.Context, leaveType leave.Le
 			earnedQuota := 0
 
 			if leaveType.AccrualMethod != nil && *leaveType.AccrualMethod == "monthly" {
-				accruedQuota := q.calculator.CalculateAccruedQuota(employee.HireDate, calculatedQuota, time.Now())
+				accruedQuota := q.calculator.CalculateAccruedQuota(employee.HireDate, calculatedQuota, q.clock.Now(), startMonth)
 				openingBalance = 0
 				earnedQuota = int(accruedQuota)
 			}
@@ -294,7 +373,11 @@ func (s *QuotaService) RecalculateQuota(
 	earnedQuota := 0
 
 	if leaveType.AccrualMethod != nil && *leaveType.AccrualMethod == "monthly" {
-		accruedQuota := s.calculator.CalculateAccruedQuota(emp.HireDate, calculatedQuota, time.Now())
+		startMonth, err := s.LeaveYearStartMonth(ctx, emp.CompanyID)
+		if err != nil {
+			return err
+		}
+		accruedQuota := s.calculator.CalculateAccruedQuota(emp.HireDate, calculatedQuota, s.clock.Now(), startMonth)
 		openingBalance = 0
 		earnedQuota = int(accruedQuota)
 	}
@@ -320,93 +403,335 @@ func (s *QuotaService) RecalculateQuota(
 	return nil
 }
 
-// MovePendingToUsed moves pending leave days to used leave days upon approval
-func (q *QuotaService) MovePendingToUsed(
-	ctx context.Context,
-	employeeID, leaveTypeID string,
-	days float64,
-) error {
+// RecalculateTenureQuotas re-evaluates tenure-based leave types for every active employee of a company
+// against their tenure at asOf, so employees who crossed a tenure threshold get the higher tier.
+// The difference is booked on earned_quota. Entitlement is never reduced below what has already been
+// used or reserved. Returns the number of quotas adjusted.
+func (q *QuotaService) RecalculateTenureQuotas(ctx context.Context, companyID string, asOf time.Time) (int, error) {
+	leaveTypes, err := q.LeaveTypeRepository.GetActiveByCompanyID(ctx, companyID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get active leave types: %w", err)
+	}
+
+	tenureTypes := make([]leave.LeaveType, 0, len(leaveTypes))
+	for _, leaveType := range leaveTypes {
+		if leaveType.QuotaRules.Type != "tenure" {
+			continue
+		}
+		if leaveType.HasQuota != nil && !*leaveType.HasQuota {
+			continue
+		}
+		tenureTypes = append(tenureTypes, leaveType)
+	}
+	if len(tenureTypes) == 0 {
+		return 0, nil
+	}
+
+	employees, err := q.EmployeeRepository.GetActiveByCompanyID(ctx, companyID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get active employees: %w", err)
+	}
+
+	startMonth, err := q.LeaveYearStartMonth(ctx, companyID)
+	if err != nil {
+		return 0, err
+	}
+
+	adjusted := 0
+	for _, emp := range employees {
+		for _, leaveType := range tenureTypes {
+			changed, err := q.recalculateTenureQuota(ctx, emp, leaveType, asOf, startMonth)
+			if err != nil {
+				slog.Warn("Failed to recalculate tenure quota",
+					"employee_id", emp.ID,
+					"leave_type", leaveType.Name,
+					"error", err,
+				)
+				continue
+			}
+			if changed {
+				adjusted++
+			}
+		}
+	}
+
+	return adjusted, nil
+}
+
+// recalculateTenureQuota applies the tenure delta to the quota of the leave year asOf falls in;
+// it reports whether the quota changed
+func (q *QuotaService) recalculateTenureQuota(ctx context.Context, emp employee.Employee, leaveType leave.LeaveType, asOf time.Time, startMonth int) (bool, error) {
+	calculatedQuota, err := q.calculator.CalculateQuotaAt(ctx, emp, leaveType, asOf)
+	if err != nil {
+		// Not eligible under the current rules; leave the existing quota alone
+		return false, nil
+	}
+
+	targetOpening := int(calculatedQuota)
+	targetEarned := 0
+	if leaveType.AccrualMethod != nil && *leaveType.AccrualMethod == "monthly" {
+		targetOpening = 0
+		targetEarned = int(q.calculator.CalculateAccruedQuota(emp.HireDate, calculatedQuota, asOf, startMonth))
+	}
+
+	changed := false
+	err = postgresql.WithTransaction(ctx, q.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		quota, err := q.LeaveQuotaRepository.GetByEmployeeTypeYearForUpdate(txCtx, emp.ID, leaveType.ID, leave.LeaveYear(asOf, startMonth))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Not allocated yet; the yearly assignment will use the current tenure
+				return nil
+			}
+			return fmt.Errorf("failed to get leave quota: %w", err)
+		}
+
+		opening := intValue(quota.OpeningBalance)
+		earned := intValue(quota.EarnedQuota)
+		delta := (targetOpening + targetEarned) - (opening + earned)
+		if delta == 0 {
+			return nil
+		}
+
+		newEarned := earned + delta
+		if delta < 0 {
+			// Keep enough entitlement to cover what is already used or pending
+			consumed := floatValue(quota.UsedQuota) + floatValue(quota.PendingQuota)
+			minEarned := int(math.Ceil(consumed)) - opening - intValue(quota.RolloverQuota) - intValue(quota.AdjustmentQuota)
+			newEarned = max(newEarned, min(earned, minEarned), 0)
+			if newEarned == earned {
+				return nil
+			}
+		}
+
+		if err := q.LeaveQuotaRepository.Update(txCtx, leave.UpdateLeaveQuotaRequest{
+			ID:          quota.ID,
+			EarnedQuota: &newEarned,
+		}); err != nil {
+			return fmt.Errorf("failed to update quota: %w", err)
+		}
+
+		slog.Info("Recalculated tenure-based leave quota",
+			"employee_id", emp.ID,
+			"leave_type", leaveType.Name,
+			"year", quota.Year,
+			"old_earned_quota", earned,
+			"new_earned_quota", newEarned,
+			"as_of", asOf.Format("2006-01-02"),
+		)
+		changed = true
+		return nil
+	})
+
+	return changed, err
+}
+
+func intValue(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// availableBalance is what is left of a quota after the days used and pending
+func availableBalance(quota leave.LeaveQuota) float64 {
+	return float64(intValue(quota.OpeningBalance)+intValue(quota.EarnedQuota)+intValue(quota.RolloverQuota)+intValue(quota.AdjustmentQuota)) -
+		floatValue(quota.UsedQuota) - floatValue(quota.PendingQuota)
+}
+
+func floatValue(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// MovePendingToUsed moves a request's pending leave days to used leave days upon approval
+func (q *QuotaService) MovePendingToUsed(ctx context.Context, request leave.LeaveRequest) error {
+	year, err := q.requestLeaveYear(ctx, request)
+	if err != nil {
+		return err
+	}
+
 	quota, err := q.LeaveQuotaRepository.GetByEmployeeTypeYear(
 		ctx,
-		employeeID,
-		leaveTypeID,
-		time.Now().Year(),
+		request.EmployeeID,
+		request.LeaveTypeID,
+		year,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to fetch quota: %w", err)
 	}
 
-	err = q.LeaveQuotaRepository.MovePendingToUsed(ctx, quota.ID, days)
+	err = q.LeaveQuotaRepository.MovePendingToUsed(ctx, quota.ID, request.WorkingDays)
 	if err != nil {
 		return fmt.Errorf("failed to move pending to used: %w", err)
 	}
 
 	// Log the operation
 	fmt.Printf("Moved %.2f days from pending to used for employee %s, leave type %s\n",
-		days, employeeID, leaveTypeID)
+		request.WorkingDays, request.EmployeeID, request.LeaveTypeID)
 
 	return nil
 }
 
-// ReleaseQuota releases pending quota (on rejection/cancellation)
-func (q *QuotaService) ReleaseQuota(
-	ctx context.Context,
-	employeeID, leaveTypeID string,
-	days float64,
-) error {
+// ReleaseQuota releases a request's pending quota (on rejection/cancellation)
+func (q *QuotaService) ReleaseQuota(ctx context.Context, request leave.LeaveRequest) error {
+	year, err := q.requestLeaveYear(ctx, request)
+	if err != nil {
+		return err
+	}
+
 	quota, err := q.LeaveQuotaRepository.GetByEmployeeTypeYear(
 		ctx,
-		employeeID,
-		leaveTypeID,
-		time.Now().Year(),
+		request.EmployeeID,
+		request.LeaveTypeID,
+		year,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to fetch quota: %w", err)
 	}
 
-	err = q.LeaveQuotaRepository.RemovePendingQuota(ctx, quota.ID, days)
+	err = q.LeaveQuotaRepository.RemovePendingQuota(ctx, quota.ID, request.WorkingDays)
 	if err != nil {
 		return fmt.Errorf("failed to release pending quota: %w", err)
 	}
 
 	// Log the operation
 	fmt.Printf("Released %.2f days from pending quota for employee %s, leave type %s\n",
-		days, employeeID, leaveTypeID)
+		request.WorkingDays, request.EmployeeID, request.LeaveTypeID)
 
 	return nil
 }
 
-// ReserveQuota reserves quota for a pending request
-func (q *QuotaService) ReserveQuota(
-	ctx context.Context,
-	employeeID, leaveTypeID string,
-	days float64,
-) error {
-	quota, err := q.LeaveQuotaRepository.GetByEmployeeTypeYear(
+// ShiftRequestQuota changes the quota held by a request by delta working days: pending days
+// while it waits for approval, used days once approved. The quota is the one of the year the
+// request was created in, so a repair stays balanced across the year boundary. Fewer days are
+// always released; more days must fit the available balance unless the leave type allows a
+// negative balance.
+func (q *QuotaService) ShiftRequestQuota(ctx context.Context, request leave.LeaveRequest, delta float64) error {
+	year, err := q.requestLeaveYear(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	quota, err := q.LeaveQuotaRepository.GetByEmployeeTypeYearForUpdate(ctx, request.EmployeeID, request.LeaveTypeID, year)
+	if err != nil {
+		return fmt.Errorf("failed to fetch quota: %w", err)
+	}
+
+	if delta > 0 {
+		leaveType, err := q.LeaveTypeRepository.GetByID(ctx, request.LeaveTypeID)
+		if err != nil {
+			return fmt.Errorf("failed to get leave type: %w", err)
+		}
+		allowNegative := leaveType.AllowNegativeBalance != nil && *leaveType.AllowNegativeBalance
+
+		// The quota row is locked, so the balance read above still holds
+		if available := availableBalance(quota); !allowNegative && delta > available {
+			return &leave.InsufficientQuotaError{Available: available, Requested: delta}
+		}
+	}
+
+	if request.Status == leave.LeaveRequestStatusApproved {
+		err = q.LeaveQuotaRepository.AddUsedQuota(ctx, quota.ID, delta)
+	} else {
+		err = q.LeaveQuotaRepository.AddPendingQuota(ctx, quota.ID, delta, true)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to shift quota: %w", err)
+	}
+
+	return nil
+}
+
+// ResizeReservation changes the pending quota held by a waiting request by delta working days.
+// An increase must fit the available balance, checked in the same statement that applies it,
+// unless the leave type allows a negative balance.
+func (q *QuotaService) ResizeReservation(ctx context.Context, request leave.LeaveRequest, delta float64) error {
+	year, err := q.requestLeaveYear(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	quota, err := q.LeaveQuotaRepository.GetByEmployeeTypeYearForUpdate(ctx, request.EmployeeID, request.LeaveTypeID, year)
+	if err != nil {
+		return fmt.Errorf("failed to fetch quota: %w", err)
+	}
+
+	leaveType, err := q.LeaveTypeRepository.GetByID(ctx, request.LeaveTypeID)
+	if err != nil {
+		return fmt.Errorf("failed to get leave type: %w", err)
+	}
+	allowNegative := delta < 0 || (leaveType.AllowNegativeBalance != nil && *leaveType.AllowNegativeBalance)
+
+	if err := q.LeaveQuotaRepository.AddPendingQuota(ctx, quota.ID, delta, allowNegative); err != nil {
+		if errors.Is(err, leave.ErrInsufficientQuota) {
+			return &leave.InsufficientQuotaError{Available: availableBalance(quota), Requested: delta}
+		}
+		return fmt.Errorf("failed to resize reserved quota: %w", err)
+	}
+
+	return nil
+}
+
+// negativeBalance returns how many days a quota is in debt, 0 when the balance is not negative
+func negativeBalance(available float64) float64 {
+	if available >= 0 {
+		return 0
+	}
+	return -available
+}
+
+// ReserveQuota reserves quota for a pending request.
+// Call it inside the request transaction: the quota row stays locked until commit, so
+// concurrent reservations are serialized and each re-checks the balance the previous one left.
+func (q *QuotaService) ReserveQuota(ctx context.Context, request leave.LeaveRequest) error {
+	days := request.WorkingDays
+	year, err := q.requestLeaveYear(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	quota, err := q.LeaveQuotaRepository.GetByEmployeeTypeYearForUpdate(
 		ctx,
-		employeeID,
-		leaveTypeID,
-		time.Now().Year(),
+		request.EmployeeID,
+		request.LeaveTypeID,
+		year,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to fetch quota: %w", err)
 	}
 
-	// Check available quota
-	available := float64(*quota.OpeningBalance) + float64(*quota.EarnedQuota) + float64(*quota.RolloverQuota) + float64(*quota.AdjustmentQuota) - *quota.UsedQuota - *quota.PendingQuota
-	if available < days {
-		return leave.ErrInsufficientQuota
+	leaveType, err := q.LeaveTypeRepository.GetByID(ctx, request.LeaveTypeID)
+	if err != nil {
+		return fmt.Errorf("failed to get leave type: %w", err)
+	}
+	allowNegative := leaveType.AllowNegativeBalance != nil && *leaveType.AllowNegativeBalance
+
+	// Check available quota; types allowing a negative balance may go into debt
+	available := availableBalance(quota)
+	if available < days && !allowNegative {
+		return &leave.InsufficientQuotaError{Available: available, Requested: days}
 	}
 
 	// Add to pending
-	err = q.LeaveQuotaRepository.AddPendingQuota(ctx, quota.ID, days)
+	err = q.LeaveQuotaRepository.AddPendingQuota(ctx, quota.ID, days, allowNegative)
 	if err != nil {
 		return fmt.Errorf("failed to reserve quota: %w", err)
 	}
 
 	// Log the operation
 	fmt.Printf("Reserved %.2f days from available quota for employee %s, leave type %s\n",
-		days, employeeID, leaveTypeID)
+		days, request.EmployeeID, request.LeaveTypeID)
 
 	return nil
 }
@@ -0,0 +1,105 @@
+package leave
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+)
+
+func TestCurrentLeaveYear(t *testing.T) {
+	lookupErr := errors.New("connection reset")
+	now := time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		companies *fakeCompanyRepo
+		want      int
+		wantErr   error
+	}{
+		{name: "calendar leave year", companies: &fakeCompanyRepo{startMonth: 1}, want: 2026},
+		{name: "leave year from april", companies: &fakeCompanyRepo{startMonth: 4}, want: 2025},
+		{name: "lookup failure is returned, not read as january", companies: &fakeCompanyRepo{startMonthErr: lookupErr}, wantErr: lookupErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &QuotaService{companyRepo: tt.companies, clock: clock.NewFake(now)}
+
+			got, err := q.CurrentLeaveYear(context.Background(), "company-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CurrentLeaveYear() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("CurrentLeaveYear() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateRequestLeaveYearLookupError(t *testing.T) {
+	lookupErr := errors.New("connection reset")
+	s := &RequestService{
+		EmployeeRepository:  &fakeEmployeeRepo{employees: []employee.Employee{{ID: "emp-1", CompanyID: "company-1"}}},
+		LeaveTypeRepository: &fakeLeaveTypeRepo{types: map[string]leave.LeaveType{"annual": {ID: "annual", Name: "Annual Leave"}}},
+		companyRepo:         &fakeCompanyRepo{timezone: "Asia/Jakarta", startMonthErr: lookupErr},
+		clock:               clock.NewFake(time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC)),
+	}
+
+	_, err := s.CreateRequest(context.Background(), leave.CreateLeaveRequestRequest{
+		EmployeeID:   "emp-1",
+		LeaveTypeID:  "annual",
+		StartDate:    "2026-02-16",
+		EndDate:      "2026-02-16",
+		DurationType: "full_day",
+	})
+	if !errors.Is(err, lookupErr) {
+		t.Fatalf("CreateRequest() error = %v, want %v", err, lookupErr)
+	}
+}
+
+// A request reserved late in one leave year and decided after the next one starts keeps moving
+// days within the quota of the year it was created in
+func TestRequestQuotaMovesStayInCreationYear(t *testing.T) {
+	tests := []struct {
+		name        string
+		pending     float64 // pending days on the creation year's quota before the call
+		call        func(q *QuotaService, ctx context.Context, request leave.LeaveRequest) error
+		wantPending float64
+		wantUsed    float64
+	}{
+		{name: "reserve", call: (*QuotaService).ReserveQuota, wantPending: 2},
+		{name: "approve", pending: 2, call: (*QuotaService).MovePendingToUsed, wantUsed: 2},
+		{name: "reject", pending: 2, call: (*QuotaService).ReleaseQuota},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, next := newQuota(12, 0, tt.pending), newQuota(12, 0, 0)
+			next.ID, next.Year = "quota-2", 2027
+			quotas := &fakeQuotaRepo{quotas: []leave.LeaveQuota{created, next}}
+			q := newReserveTestService(quotas, leave.LeaveType{Name: "Annual Leave"})
+			q.clock = clock.NewFake(time.Date(2027, time.January, 5, 9, 0, 0, 0, time.UTC))
+
+			request := newPendingRequest(2)
+			request.CreatedAt = time.Date(2026, time.December, 30, 9, 0, 0, 0, time.UTC)
+
+			if err := tt.call(q, context.Background(), request); err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+
+			got := quotas.quotas[0]
+			if *got.PendingQuota != tt.wantPending || *got.UsedQuota != tt.wantUsed {
+				t.Errorf("2026 quota pending %.1f used %.1f, want pending %.1f used %.1f",
+					*got.PendingQuota, *got.UsedQuota, tt.wantPending, tt.wantUsed)
+			}
+			if untouched := quotas.quotas[1]; *untouched.PendingQuota != 0 || *untouched.UsedQuota != 0 {
+				t.Errorf("2027 quota pending %.1f used %.1f, want it untouched", *untouched.PendingQuota, *untouched.UsedQuota)
+			}
+		})
+	}
+}
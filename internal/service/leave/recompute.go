@@ -0,0 +1,97 @@
+package leave
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// RecomputeLeaveRequest implements leave.LeaveService.
+// It recalculates a request's days against the current working-day rules, e.g. after the
+// calendar changed, and moves the quota the request holds by the difference in working days.
+func (l *LeaveServiceImpl) RecomputeLeaveRequest(ctx context.Context, requestID string) (leave.RecomputeLeaveRequestResponse, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return leave.RecomputeLeaveRequestResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+	// Repairs move quota outside the approval flow, so they are reserved for the owner
+	if role, _ := claims["role"].(string); role != string(user.RoleOwner) {
+		return leave.RecomputeLeaveRequestResponse{}, user.ErrOwnerAccessRequired
+	}
+	companyID, _ := claims["company_id"].(string)
+
+	if err := l.ensureRequestInBranchScope(ctx, requestID); err != nil {
+		return leave.RecomputeLeaveRequestResponse{}, err
+	}
+
+	var result leave.RecomputeLeaveRequestResponse
+	err = postgresql.WithTransaction(ctx, l.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		request, err := l.LeaveRequestRepository.GetByID(txCtx, requestID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return leave.ErrLeaveRequestNotFound
+			}
+			return fmt.Errorf("failed to get leave request: %w", err)
+		}
+
+		emp, err := l.EmployeeRepository.GetByID(txCtx, request.EmployeeID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return employee.ErrEmployeeNotFound
+			}
+			return fmt.Errorf("failed to get employee: %w", err)
+		}
+		if emp.CompanyID != companyID {
+			return leave.ErrLeaveRequestNotFound
+		}
+
+		if request.Status != leave.LeaveRequestStatusWaitingApproval && request.Status != leave.LeaveRequestStatusApproved {
+			return leave.ErrLeaveRequestClosed
+		}
+
+		workingDays, err := l.requestService.Calculate(txCtx, companyID, request.StartDate, request.EndDate, string(request.DurationType))
+		if err != nil {
+			return fmt.Errorf("failed to calculate working days: %w", err)
+		}
+		totalDays := l.requestService.calculateTotalDays(request.StartDate, request.EndDate, string(request.DurationType))
+
+		result = leave.RecomputeLeaveRequestResponse{
+			RequestID:  request.ID,
+			Status:     request.Status,
+			Before:     leave.LeaveDayCounts{TotalDays: request.TotalDays, WorkingDays: request.WorkingDays},
+			After:      leave.LeaveDayCounts{TotalDays: totalDays, WorkingDays: workingDays},
+			QuotaDelta: workingDays - request.WorkingDays,
+		}
+		if result.Before == result.After {
+			return nil
+		}
+
+		if err := l.LeaveRequestRepository.Update(txCtx, leave.UpdateLeaveRequestRequest{
+			ID:              request.ID,
+			TotalDays:       &totalDays,
+			WorkingDays:     &workingDays,
+			ExpectedVersion: request.Version,
+		}); err != nil {
+			return fmt.Errorf("failed to update leave request: %w", err)
+		}
+
+		if result.QuotaDelta == 0 {
+			return nil
+		}
+		return l.quotaService.ShiftRequestQuota(txCtx, request, result.QuotaDelta)
+	})
+	if err != nil {
+		return leave.RecomputeLeaveRequestResponse{}, err
+	}
+
+	return result, nil
+}
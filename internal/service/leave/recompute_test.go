@@ -0,0 +1,172 @@
+package leave
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database/dbtest"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/testutil"
+)
+
+func TestRecomputeLeaveRequest(t *testing.T) {
+	// The request runs Monday 9 to Friday 13 March; Wednesday is a holiday only when the
+	// calendar has one
+	start := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)
+	holiday := []company.Holiday{{Date: time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), Name: "Nyepi"}}
+
+	tests := []struct {
+		name        string
+		role        user.Role
+		status      leave.LeaveRequestStatus
+		workingDays float64 // what the request holds before the recompute
+		holidays    []company.Holiday
+		quota       leave.LeaveQuota
+		wantErr     error
+		wantDelta   float64
+		wantUsed    float64
+		wantPending float64
+		wantDays    float64 // working days written back, zero when the request is left alone
+		wantEnd     string  // how the transaction ended, empty when none was started
+	}{
+		{
+			name:        "higher after a holiday was removed",
+			role:        user.RoleOwner,
+			status:      leave.LeaveRequestStatusWaitingApproval,
+			workingDays: 4,
+			quota:       newQuota(12, 0, 4),
+			wantDelta:   1,
+			wantPending: 5,
+			wantDays:    5,
+			wantEnd:     "commit",
+		},
+		{
+			name:        "higher on an approved request",
+			role:        user.RoleOwner,
+			status:      leave.LeaveRequestStatusApproved,
+			workingDays: 4,
+			quota:       newQuota(12, 4, 0),
+			wantDelta:   1,
+			wantUsed:    5,
+			wantDays:    5,
+			wantEnd:     "commit",
+		},
+		{
+			name:        "lower after a holiday was added",
+			role:        user.RoleOwner,
+			status:      leave.LeaveRequestStatusWaitingApproval,
+			workingDays: 5,
+			holidays:    holiday,
+			quota:       newQuota(12, 0, 5),
+			wantDelta:   -1,
+			wantPending: 4,
+			wantDays:    4,
+			wantEnd:     "commit",
+		},
+		{
+			name:        "unchanged",
+			role:        user.RoleOwner,
+			status:      leave.LeaveRequestStatusWaitingApproval,
+			workingDays: 5,
+			quota:       newQuota(12, 0, 5),
+			wantPending: 5,
+			wantEnd:     "commit",
+		},
+		{
+			name:        "higher than the balance left",
+			role:        user.RoleOwner,
+			status:      leave.LeaveRequestStatusWaitingApproval,
+			workingDays: 3,
+			quota:       newQuota(4, 0, 3),
+			wantErr:     &leave.InsufficientQuotaError{Available: 1, Requested: 2},
+			wantPending: 3,
+			wantDays:    5,
+			wantEnd:     "rollback",
+		},
+		{
+			name:        "manager",
+			role:        user.RoleManager,
+			status:      leave.LeaveRequestStatusWaitingApproval,
+			workingDays: 4,
+			quota:       newQuota(12, 0, 4),
+			wantErr:     user.ErrOwnerAccessRequired,
+			wantPending: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, server := dbtest.NewDB(t, nil)
+			requests := &fakeLeaveRequestRepo{
+				requests: map[string]leave.LeaveRequest{"req-1": {
+					ID: "req-1", EmployeeID: "emp-1", LeaveTypeID: "annual", StartDate: start, EndDate: end,
+					DurationType: "full_day", TotalDays: 5, WorkingDays: tt.workingDays, Status: tt.status,
+					Version: 2, CreatedAt: start.AddDate(0, 0, -7),
+				}},
+			}
+			quotas := &fakeQuotaRepo{quotas: []leave.LeaveQuota{tt.quota}}
+			employees := &fakeEmployeeRepo{employees: []employee.Employee{{ID: "emp-1", CompanyID: "company-1"}}}
+			companies := &fakeCompanyRepo{timezone: "UTC", startMonth: 1}
+			s := &LeaveServiceImpl{
+				db:                     db,
+				LeaveRequestRepository: requests,
+				EmployeeRepository:     employees,
+				quotaService: &QuotaService{
+					LeaveTypeRepository:  &fakeLeaveTypeRepo{types: map[string]leave.LeaveType{"annual": {ID: "annual", Name: "Annual Leave"}}},
+					LeaveQuotaRepository: quotas,
+					EmployeeRepository:   employees,
+					companyRepo:          companies,
+					clock:                clock.NewFake(start),
+				},
+				requestService: &RequestService{companyRepo: companies, calendars: &fakeCalendars{holidays: tt.holidays}},
+			}
+			ctx := testutil.ClaimsContext(t, map[string]any{"role": string(tt.role), "company_id": "company-1"})
+
+			resp, err := s.RecomputeLeaveRequest(ctx, "req-1")
+
+			var insufficient *leave.InsufficientQuotaError
+			if want, ok := tt.wantErr.(*leave.InsufficientQuotaError); ok {
+				if !errors.As(err, &insufficient) || *insufficient != *want {
+					t.Fatalf("RecomputeLeaveRequest() error = %v, want %v", err, want)
+				}
+			} else if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RecomputeLeaveRequest() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && resp.QuotaDelta != tt.wantDelta {
+				t.Errorf("QuotaDelta = %v, want %v", resp.QuotaDelta, tt.wantDelta)
+			}
+
+			if got := quotas.quotas[0]; *got.UsedQuota != tt.wantUsed || *got.PendingQuota != tt.wantPending {
+				t.Errorf("quota used %v pending %v, want used %v pending %v", *got.UsedQuota, *got.PendingQuota, tt.wantUsed, tt.wantPending)
+			}
+			switch {
+			case tt.wantDays == 0 && len(requests.updates) != 0:
+				t.Errorf("updates %+v, want the request left alone", requests.updates)
+			case tt.wantDays != 0 && len(requests.updates) != 1:
+				t.Errorf("updates %+v, want one", requests.updates)
+			case tt.wantDays != 0:
+				if update := requests.updates[0]; update.ExpectedVersion != 2 || *update.WorkingDays != tt.wantDays {
+					t.Errorf("update %+v, want %v working days guarded by version 2", update, tt.wantDays)
+				}
+			}
+
+			statements := server.Statements()
+			if tt.wantEnd == "" {
+				if len(statements) != 0 {
+					t.Errorf("statements %v, want nothing sent to the database", statements)
+				}
+				return
+			}
+			if end := strings.ToLower(statements[len(statements)-1]); end != tt.wantEnd {
+				t.Errorf("transaction ended with %q, want %q", end, tt.wantEnd)
+			}
+		})
+	}
+}
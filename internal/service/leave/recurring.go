@@ -0,0 +1,291 @@
+package leave
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateRecurringLeaveRequest implements leave.LeaveService.
+// Every matching working day becomes its own single-day request so quota, overlap and
+// advance-notice rules are checked per date. The whole group is rolled back if any date fails.
+func (l *LeaveServiceImpl) CreateRecurringLeaveRequest(ctx context.Context, req leave.CreateRecurringLeaveRequest) (leave.RecurringLeaveResponse, error) {
+	leaveType, err := l.LeaveTypeRepository.GetByID(ctx, req.LeaveTypeID)
+	if err != nil {
+		return leave.RecurringLeaveResponse{}, fmt.Errorf("failed to get leave type by ID: %w", err)
+	}
+	// Recurring submissions carry no file, so types that need one must be requested individually
+	if leaveType.RequiresAttachment != nil && *leaveType.RequiresAttachment {
+		return leave.RecurringLeaveResponse{}, leave.ErrAttachmentRequired
+	}
+
+	emp, err := l.EmployeeRepository.GetByID(ctx, req.EmployeeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return leave.RecurringLeaveResponse{}, employee.ErrEmployeeNotFound
+		}
+		return leave.RecurringLeaveResponse{}, fmt.Errorf("failed to get employee: %w", err)
+	}
+
+	dates, err := l.expandRecurringDates(ctx, emp.CompanyID, req.StartDate, req.EndDate, req.Weekdays)
+	if err != nil {
+		return leave.RecurringLeaveResponse{}, err
+	}
+	if len(dates) == 0 {
+		return leave.RecurringLeaveResponse{}, leave.ErrNoRecurringLeaveDates
+	}
+
+	recurrenceID := uuid.New().String()
+	response := leave.RecurringLeaveResponse{
+		RecurrenceID: recurrenceID,
+		Requests:     make([]leave.LeaveRequestResponse, 0, len(dates)),
+	}
+
+	err = postgresql.WithTransaction(ctx, l.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		for _, date := range dates {
+			leaveRequest, err := l.requestService.CreateRequest(txCtx, leave.CreateLeaveRequestRequest{
+				EmployeeID:   req.EmployeeID,
+				LeaveTypeID:  req.LeaveTypeID,
+				StartDate:    date,
+				EndDate:      date,
+				DurationType: req.DurationType,
+				Reason:       req.Reason,
+				RecurrenceID: &recurrenceID,
+			})
+			if err != nil {
+				return &leave.RecurringLeaveDateError{Date: date, Err: err}
+			}
+
+			if err := l.quotaService.ReserveQuota(txCtx, leaveRequest); err != nil {
+				return &leave.RecurringLeaveDateError{Date: date, Err: err}
+			}
+
+			response.TotalDays += leaveRequest.WorkingDays
+			response.Requests = append(response.Requests, leave.LeaveRequestResponse{
+				ID:            leaveRequest.ID,
+				EmployeeID:    leaveRequest.EmployeeID,
+				EmployeeName:  stringValue(leaveRequest.EmployeeName),
+				LeaveTypeID:   leaveRequest.LeaveTypeID,
+				LeaveTypeName: stringValue(leaveRequest.LeaveTypeName),
+				StartDate:     leaveRequest.StartDate,
+				EndDate:       leaveRequest.EndDate,
+				DurationType:  string(leaveRequest.DurationType),
+				TotalDays:     leaveRequest.TotalDays,
+				WorkingDays:   leaveRequest.WorkingDays,
+				Reason:        leaveRequest.Reason,
+				Status:        string(leaveRequest.Status),
+				SubmittedAt:   leaveRequest.SubmittedAt,
+				RecurrenceID:  leaveRequest.RecurrenceID,
+				Version:       leaveRequest.Version,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return leave.RecurringLeaveResponse{}, err
+	}
+
+	// Managers get a single notification covering the whole series
+	summary := response.Requests[0]
+	summary.EndDate = response.Requests[len(response.Requests)-1].EndDate
+	summary.TotalDays = response.TotalDays
+
+	// Use context.WithoutCancel to prevent cancellation when HTTP request ends
+	go l.notifyManagersOnLeaveRequest(context.WithoutCancel(ctx), summary)
+
+	return response, nil
+}
+
+// ApproveRecurringLeave implements leave.LeaveService.
+// Requests in the group that were already processed individually are left untouched.
+func (l *LeaveServiceImpl) ApproveRecurringLeave(ctx context.Context, recurrenceID string) (leave.ApproveRecurringLeaveResponse, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return leave.ApproveRecurringLeaveResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	approverID, ok := claims["user_id"].(string)
+	if !ok || approverID == "" {
+		return leave.ApproveRecurringLeaveResponse{}, fmt.Errorf("user_id claim is missing or invalid")
+	}
+
+	companyID, _ := claims["company_id"].(string)
+
+	pending, err := l.pendingRecurringRequests(ctx, recurrenceID, companyID)
+	if err != nil {
+		return leave.ApproveRecurringLeaveResponse{}, err
+	}
+
+	approved := make([]leave.LeaveRequest, 0, len(pending))
+	result := leave.ApproveRecurringLeaveResponse{
+		RecurrenceID: recurrenceID,
+		Requests:     make([]leave.ApproveLeaveResponse, 0, len(pending)),
+	}
+	err = postgresql.WithTransaction(ctx, l.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		for _, p := range pending {
+			request, txErr := l.requestService.Approve(txCtx, p.ID, approverID)
+			if txErr != nil {
+				return fmt.Errorf("failed to approve leave request: %w", txErr)
+			}
+
+			if txErr = l.quotaService.MovePendingToUsed(txCtx, request); txErr != nil {
+				return fmt.Errorf("failed to move pending to used quota: %w", txErr)
+			}
+
+			coverage, txErr := l.createLeaveAttendanceRecords(txCtx, request, companyID, approverID)
+			if txErr != nil {
+				return fmt.Errorf("failed to create leave attendance records: %w", txErr)
+			}
+
+			approved = append(approved, request)
+			result.Requests = append(result.Requests, coverage)
+		}
+		return l.enqueueLeaveApprovedNotification(txCtx, recurringSummary(approved), companyID, approverID)
+	})
+	if err != nil {
+		return leave.ApproveRecurringLeaveResponse{}, err
+	}
+
+	return result, nil
+}
+
+// RejectRecurringLeave implements leave.LeaveService.
+func (l *LeaveServiceImpl) RejectRecurringLeave(ctx context.Context, req leave.RejectRecurringLeaveRequest) error {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	approverID, ok := claims["user_id"].(string)
+	if !ok || approverID == "" {
+		return fmt.Errorf("user_id claim is missing or invalid")
+	}
+
+	companyID, _ := claims["company_id"].(string)
+
+	pending, err := l.pendingRecurringRequests(ctx, req.RecurrenceID, companyID)
+	if err != nil {
+		return err
+	}
+
+	rejected := make([]leave.LeaveRequest, 0, len(pending))
+	err = postgresql.WithTransaction(ctx, l.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		for _, p := range pending {
+			request, txErr := l.requestService.Reject(txCtx, p.ID, req.Reason, approverID)
+			if txErr != nil {
+				return txErr
+			}
+
+			// Release reserved quota
+			if txErr = l.quotaService.ReleaseQuota(txCtx, request); txErr != nil {
+				return txErr
+			}
+
+			rejected = append(rejected, request)
+		}
+		return l.enqueueLeaveRejectedNotification(txCtx, recurringSummary(rejected), companyID, approverID, req.Reason)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// pendingRecurringRequests returns the requests in a recurrence group that still await approval,
+// after checking the group belongs to the caller's company and branch scope
+func (l *LeaveServiceImpl) pendingRecurringRequests(ctx context.Context, recurrenceID, companyID string) ([]leave.LeaveRequest, error) {
+	requests, err := l.LeaveRequestRepository.GetByRecurrenceID(ctx, recurrenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recurring leave requests: %w", err)
+	}
+	if len(requests) == 0 {
+		return nil, leave.ErrRecurrenceNotFound
+	}
+
+	// Every request in a group belongs to the same employee
+	emp, err := l.EmployeeRepository.GetByID(ctx, requests[0].EmployeeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, leave.ErrRecurrenceNotFound
+		}
+		return nil, fmt.Errorf("failed to get employee: %w", err)
+	}
+	if emp.CompanyID != companyID {
+		return nil, leave.ErrRecurrenceNotFound
+	}
+
+	if err := l.ensureEmployeeInBranchScope(ctx, emp.ID); err != nil {
+		return nil, err
+	}
+
+	pending := make([]leave.LeaveRequest, 0, len(requests))
+	for _, request := range requests {
+		if request.Status == leave.LeaveRequestStatusWaitingApproval {
+			pending = append(pending, request)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, leave.ErrLeaveAlreadyProcessed
+	}
+
+	return pending, nil
+}
+
+// recurringSummary collapses a processed group into one request spanning its first to last date
+func recurringSummary(requests []leave.LeaveRequest) leave.LeaveRequest {
+	summary := requests[0]
+	summary.EndDate = requests[len(requests)-1].EndDate
+	return summary
+}
+
+// expandRecurringDates lists the dates between start and end (inclusive) that fall on one of
+// the given ISO weekdays (1=Monday ... 7=Sunday). Days off in the company calendar are skipped
+// because they would consume no quota.
+func (l *LeaveServiceImpl) expandRecurringDates(ctx context.Context, companyID, startDate, endDate string, weekdays []int) ([]string, error) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse start date: %w", err)
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse end date: %w", err)
+	}
+
+	wanted := make(map[time.Weekday]bool, len(weekdays))
+	for _, day := range weekdays {
+		wanted[time.Weekday(day%7)] = true
+	}
+
+	var dates []string
+	var calendar company.Calendar
+	for current := start; !current.After(end); current = current.AddDate(0, 0, 1) {
+		if !wanted[current.Weekday()] {
+			continue
+		}
+		calendar, err = l.requestService.calendarFor(ctx, companyID, calendar, current)
+		if err != nil {
+			return nil, err
+		}
+		if calendar.IsWorkingDay(current) {
+			dates = append(dates, current.Format("2006-01-02"))
+		}
+	}
+
+	return dates, nil
+}
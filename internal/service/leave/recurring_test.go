@@ -0,0 +1,124 @@
+package leave
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+)
+
+var independenceDay = company.Holiday{Date: time.Date(2026, time.August, 17, 0, 0, 0, 0, time.UTC), Name: "Independence Day"} // Monday
+
+func TestExpandRecurringDates(t *testing.T) {
+	sixDayWeek := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+
+	tests := []struct {
+		name            string
+		workingWeekdays []time.Weekday
+		weekdays        []int
+		want            []string
+	}{
+		{name: "weekend and holiday skipped", weekdays: []int{1, 6}, want: []string{"2026-08-10"}},
+		{name: "six day week keeps saturdays", workingWeekdays: sixDayWeek, weekdays: []int{1, 6}, want: []string{"2026-08-10", "2026-08-15", "2026-08-22"}},
+		{name: "sunday is never worked", workingWeekdays: sixDayWeek, weekdays: []int{7}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendars := &fakeCalendars{holidays: []company.Holiday{independenceDay}, workingWeekdays: tt.workingWeekdays}
+			l := &LeaveServiceImpl{requestService: &RequestService{calendars: calendars}}
+
+			got, err := l.expandRecurringDates(context.Background(), "company-1", "2026-08-10", "2026-08-23", tt.weekdays)
+			if err != nil {
+				t.Fatalf("expandRecurringDates() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expandRecurringDates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandRecurringDatesCalendarError(t *testing.T) {
+	lookupErr := errors.New("connection reset")
+	l := &LeaveServiceImpl{requestService: &RequestService{calendars: &fakeCalendars{err: lookupErr}}}
+
+	_, err := l.expandRecurringDates(context.Background(), "company-1", "2026-08-10", "2026-08-23", []int{1})
+	if !errors.Is(err, lookupErr) {
+		t.Fatalf("expandRecurringDates() error = %v, want %v", err, lookupErr)
+	}
+}
+
+func TestCreateLeaveAttendanceRecordsSkipsDaysOff(t *testing.T) {
+	sixDayWeek := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+
+	tests := []struct {
+		name            string
+		workingWeekdays []time.Weekday
+		existing        map[string]bool
+		wantCreated     []string
+		wantSkipped     []leave.SkippedLeaveDate
+	}{
+		{
+			name:        "five day week",
+			wantCreated: []string{"2026-08-14", "2026-08-18"},
+			wantSkipped: []leave.SkippedLeaveDate{
+				{Date: "2026-08-15", Reason: leave.LeaveDateSkippedWeekend},
+				{Date: "2026-08-16", Reason: leave.LeaveDateSkippedWeekend},
+				{Date: "2026-08-17", Reason: leave.LeaveDateSkippedHoliday},
+			},
+		},
+		{
+			name:            "six day week with an existing record",
+			workingWeekdays: sixDayWeek,
+			existing:        map[string]bool{"emp-1/2026-08-18": true},
+			wantCreated:     []string{"2026-08-14", "2026-08-15"},
+			wantSkipped: []leave.SkippedLeaveDate{
+				{Date: "2026-08-16", Reason: leave.LeaveDateSkippedWeekend},
+				{Date: "2026-08-17", Reason: leave.LeaveDateSkippedHoliday},
+				{Date: "2026-08-18", Reason: leave.LeaveDateSkippedAttendanceExists},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attendances := &fakeAttendanceRepo{existing: tt.existing}
+			l := &LeaveServiceImpl{
+				LeaveTypeRepository:  &fakeLeaveTypeRepo{types: map[string]leave.LeaveType{"annual": {ID: "annual", Name: "Annual Leave"}}},
+				AttendanceRepository: attendances,
+				requestService: &RequestService{calendars: &fakeCalendars{
+					holidays:        []company.Holiday{independenceDay},
+					workingWeekdays: tt.workingWeekdays,
+				}},
+				clock: clock.NewFake(time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC)),
+			}
+			request := leave.LeaveRequest{
+				ID:          "req-1",
+				EmployeeID:  "emp-1",
+				LeaveTypeID: "annual",
+				StartDate:   time.Date(2026, time.August, 14, 0, 0, 0, 0, time.UTC), // Friday
+				EndDate:     time.Date(2026, time.August, 18, 0, 0, 0, 0, time.UTC), // Tuesday
+			}
+
+			result, err := l.createLeaveAttendanceRecords(context.Background(), request, "company-1", "approver-1")
+			if err != nil {
+				t.Fatalf("createLeaveAttendanceRecords() error = %v", err)
+			}
+			if !slices.Equal(result.CreatedDates, tt.wantCreated) {
+				t.Errorf("created %v, want %v", result.CreatedDates, tt.wantCreated)
+			}
+			if !slices.Equal(result.SkippedDates, tt.wantSkipped) {
+				t.Errorf("skipped %v, want %v", result.SkippedDates, tt.wantSkipped)
+			}
+			if len(attendances.created) != len(tt.wantCreated) {
+				t.Errorf("%d attendances written, want %d", len(attendances.created), len(tt.wantCreated))
+			}
+		})
+	}
+}
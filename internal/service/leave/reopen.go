@@ -0,0 +1,123 @@
+package leave
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/utils"
+	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// ReopenRequest implements leave.LeaveService.
+// It moves the caller's own rejected request back to waiting_approval so it can be approved
+// without resubmitting, as long as the leave has not started and the rejection is recent.
+func (l *LeaveServiceImpl) ReopenRequest(ctx context.Context, requestID string) (leave.LeaveRequestResponse, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return leave.LeaveRequestResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+	employeeID, _ := claims["employee_id"].(string)
+	if employeeID == "" {
+		return leave.LeaveRequestResponse{}, leave.ErrUnauthorizedAccess
+	}
+
+	var requestResponse leave.LeaveRequestResponse
+	err = postgresql.WithTransactionRetry(ctx, l.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		request, err := l.LeaveRequestRepository.GetByID(txCtx, requestID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return leave.ErrLeaveRequestNotFound
+			}
+			return fmt.Errorf("failed to get leave request: %w", err)
+		}
+		if request.EmployeeID != employeeID {
+			return leave.ErrUnauthorizedAccess
+		}
+
+		emp, err := l.EmployeeRepository.GetByID(txCtx, request.EmployeeID)
+		if err != nil {
+			return fmt.Errorf("failed to get employee: %w", err)
+		}
+
+		now := l.clock.Now()
+		today := utils.StartOfDay(now, l.requestService.companyLocation(txCtx, emp.CompanyID))
+		if err := checkReopenable(request, today, now, l.reopenWindow); err != nil {
+			return err
+		}
+
+		hasOverlap, err := l.LeaveRequestRepository.CheckOverlapping(txCtx, request.EmployeeID, request.StartDate, request.EndDate)
+		if err != nil {
+			return fmt.Errorf("failed to check overlapping leave requests: %w", err)
+		}
+		if hasOverlap {
+			return leave.ErrOverlappingLeave
+		}
+
+		if err := l.LeaveRequestRepository.Reopen(txCtx, request.ID, now); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return leave.ErrLeaveRequestNotRejected
+			}
+			return fmt.Errorf("failed to reopen leave request: %w", err)
+		}
+
+		if err := l.quotaService.ReserveQuota(txCtx, request); err != nil {
+			return fmt.Errorf("failed to reserve quota: %w", err)
+		}
+
+		requestResponse = leave.LeaveRequestResponse{
+			ID:            request.ID,
+			EmployeeID:    request.EmployeeID,
+			EmployeeName:  emp.FullName,
+			LeaveTypeID:   request.LeaveTypeID,
+			LeaveTypeName: stringValue(request.LeaveTypeName),
+			StartDate:     request.StartDate,
+			EndDate:       request.EndDate,
+			DurationType:  string(request.DurationType),
+			TotalDays:     request.TotalDays,
+			WorkingDays:   request.WorkingDays,
+			Reason:        request.Reason,
+			AttachmentURL: request.AttachmentURL,
+			Status:        string(leave.LeaveRequestStatusWaitingApproval),
+			SubmittedAt:   now,
+			Version:       request.Version + 1,
+		}
+		return nil
+	})
+	if err != nil {
+		return leave.LeaveRequestResponse{}, err
+	}
+
+	// Use context.WithoutCancel to prevent cancellation when HTTP request ends
+	go l.notifyManagers(context.WithoutCancel(ctx), requestResponse, "Leave Request Reopened", "reopened")
+
+	return requestResponse, nil
+}
+
+// checkReopenable reports why a request cannot be reopened. The rejection time is ApprovedAt,
+// which Reject stamps; a rejection exactly window ago is still reopenable.
+func checkReopenable(request leave.LeaveRequest, today, now time.Time, window time.Duration) error {
+	switch request.Status {
+	case leave.LeaveRequestStatusRejected:
+	case leave.LeaveRequestStatusCancelled:
+		return leave.ErrLeaveRequestCancelled
+	default:
+		return leave.ErrLeaveRequestNotRejected
+	}
+
+	if !request.StartDate.After(today) {
+		return leave.ErrLeaveAlreadyStarted
+	}
+
+	if request.ApprovedAt == nil || now.Sub(*request.ApprovedAt) > window {
+		return leave.ErrReopenWindowExpired
+	}
+
+	return nil
+}
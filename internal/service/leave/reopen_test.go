@@ -0,0 +1,120 @@
+package leave
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database/dbtest"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/testutil"
+)
+
+const reopenWindow = 7 * 24 * time.Hour
+
+func TestCheckReopenable(t *testing.T) {
+	rejectedAt := time.Date(2026, time.May, 4, 10, 0, 0, 0, time.UTC)
+	today := time.Date(2026, time.May, 11, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		status  leave.LeaveRequestStatus
+		start   time.Time
+		now     time.Time
+		noStamp bool
+		wantErr error
+	}{
+		{name: "rejected a moment ago", status: leave.LeaveRequestStatusRejected, start: today.AddDate(0, 0, 7), now: rejectedAt.Add(time.Minute)},
+		{name: "rejected exactly a window ago", status: leave.LeaveRequestStatusRejected, start: today.AddDate(0, 0, 7), now: rejectedAt.Add(reopenWindow)},
+		{name: "window passed by a second", status: leave.LeaveRequestStatusRejected, start: today.AddDate(0, 0, 7), now: rejectedAt.Add(reopenWindow + time.Second), wantErr: leave.ErrReopenWindowExpired},
+		{name: "no rejection time", status: leave.LeaveRequestStatusRejected, start: today.AddDate(0, 0, 7), now: rejectedAt, noStamp: true, wantErr: leave.ErrReopenWindowExpired},
+		{name: "leave starts tomorrow", status: leave.LeaveRequestStatusRejected, start: today.AddDate(0, 0, 1), now: rejectedAt.Add(time.Hour)},
+		{name: "leave starts today", status: leave.LeaveRequestStatusRejected, start: today, now: rejectedAt.Add(time.Hour), wantErr: leave.ErrLeaveAlreadyStarted},
+		{name: "cancelled", status: leave.LeaveRequestStatusCancelled, start: today.AddDate(0, 0, 7), now: rejectedAt.Add(time.Hour), wantErr: leave.ErrLeaveRequestCancelled},
+		{name: "pending", status: leave.LeaveRequestStatusWaitingApproval, start: today.AddDate(0, 0, 7), now: rejectedAt.Add(time.Hour), wantErr: leave.ErrLeaveRequestNotRejected},
+		{name: "approved", status: leave.LeaveRequestStatusApproved, start: today.AddDate(0, 0, 7), now: rejectedAt.Add(time.Hour), wantErr: leave.ErrLeaveRequestNotRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := leave.LeaveRequest{Status: tt.status, StartDate: tt.start, ApprovedAt: &rejectedAt}
+			if tt.noStamp {
+				request.ApprovedAt = nil
+			}
+
+			if err := checkReopenable(request, today, tt.now, reopenWindow); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkReopenable() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReopenRequestAtWindowBoundary(t *testing.T) {
+	rejectedAt := time.Date(2026, time.May, 4, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		after   time.Duration
+		wantErr error
+	}{
+		{name: "last moment of the window", after: reopenWindow},
+		{name: "a second after the window", after: reopenWindow + time.Second, wantErr: leave.ErrReopenWindowExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := dbtest.NewDB(t, nil)
+			clk := clock.NewFake(rejectedAt)
+			clk.Advance(tt.after)
+
+			employees := &fakeEmployeeRepo{employees: []employee.Employee{{ID: "emp-1", CompanyID: "company-1", FullName: "Dewi"}}}
+			companies := &fakeCompanyRepo{timezone: "Asia/Jakarta", startMonth: 1}
+			requests := &fakeLeaveRequestRepo{requests: map[string]leave.LeaveRequest{
+				"req-1": {
+					ID: "req-1", EmployeeID: "emp-1", LeaveTypeID: "annual", Status: leave.LeaveRequestStatusRejected,
+					StartDate: time.Date(2026, time.May, 25, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, time.May, 26, 0, 0, 0, 0, time.UTC),
+					WorkingDays: 2, ApprovedAt: &rejectedAt, SubmittedAt: rejectedAt.AddDate(0, 0, -1), CreatedAt: rejectedAt.AddDate(0, 0, -1),
+				},
+			}}
+			quotas := &fakeQuotaRepo{quotas: []leave.LeaveQuota{newQuota(12, 0, 0)}}
+			quotaService := newReserveTestService(quotas, leave.LeaveType{Name: "Annual Leave"})
+			quotaService.clock = clk
+
+			l := &LeaveServiceImpl{
+				db:                     db,
+				LeaveRequestRepository: requests,
+				EmployeeRepository:     employees,
+				quotaService:           quotaService,
+				requestService:         &RequestService{companyRepo: companies},
+				clock:                  clk,
+				reopenWindow:           reopenWindow,
+			}
+			ctx := testutil.ClaimsContext(t, map[string]any{"employee_id": "emp-1", "company_id": "company-1"})
+
+			resp, err := l.ReopenRequest(ctx, "req-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReopenRequest() error = %v, want %v", err, tt.wantErr)
+			}
+
+			got := requests.requests["req-1"]
+			pending := *quotas.quotas[0].PendingQuota
+			if tt.wantErr != nil {
+				if got.Status != leave.LeaveRequestStatusRejected || pending != 0 {
+					t.Errorf("after a refused reopen: status %s, %.1f days pending; want rejected, 0", got.Status, pending)
+				}
+				return
+			}
+			if got.Status != leave.LeaveRequestStatusWaitingApproval || !got.SubmittedAt.Equal(clk.Now()) || got.ApprovedAt != nil {
+				t.Errorf("reopened request = %s submitted %s (rejected at %v)", got.Status, got.SubmittedAt, got.ApprovedAt)
+			}
+			if pending != 2 {
+				t.Errorf("pending quota = %.1f, want the 2 working days reserved again", pending)
+			}
+			if resp.Status != string(leave.LeaveRequestStatusWaitingApproval) {
+				t.Errorf("response status = %s", resp.Status)
+			}
+		})
+	}
+}
@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/utils"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -18,18 +21,33 @@ type RequestService struct {
 	leave.LeaveQuotaRepository
 	leave.LeaveRequestRepository
 	employee.EmployeeRepository
+	companyRepo company.CompanyRepository
+	calendars   company.CalendarProvider
+	clock       clock.Clock
 }
 
-func NewRequestService(db *database.DB, leaveTypeRepository leave.LeaveTypeRepository, leaveQuotaRepository leave.LeaveQuotaRepository, leaveRequestRepository leave.LeaveRequestRepository, employeeRepository employee.EmployeeRepository) *RequestService {
+func NewRequestService(db *database.DB, leaveTypeRepository leave.LeaveTypeRepository, leaveQuotaRepository leave.LeaveQuotaRepository, leaveRequestRepository leave.LeaveRequestRepository, employeeRepository employee.EmployeeRepository, companyRepository company.CompanyRepository, calendars company.CalendarProvider, clk clock.Clock) *RequestService {
 	return &RequestService{
 		db:                     db,
 		LeaveTypeRepository:    leaveTypeRepository,
 		LeaveQuotaRepository:   leaveQuotaRepository,
 		LeaveRequestRepository: leaveRequestRepository,
 		EmployeeRepository:     employeeRepository,
+		companyRepo:            companyRepository,
+		calendars:              calendars,
+		clock:                  clk,
 	}
 }
 
+// companyLocation returns the company's configured timezone, or utils.DefaultTimezone if it cannot be read.
+func (r *RequestService) companyLocation(ctx context.Context, companyID string) *time.Location {
+	timezone, err := r.companyRepo.GetTimezone(ctx, companyID)
+	if err != nil {
+		return utils.LoadLocation("")
+	}
+	return utils.LoadLocation(timezone)
+}
+
 func (r *RequestService) Approve(ctx context.Context, requestID string, approvedID string) (leave.LeaveRequest, error) {
 	request, err := r.LeaveRequestRepository.GetByID(ctx, requestID)
 	if err != nil {
@@ -42,17 +60,18 @@ func (r *RequestService) Approve(ctx context.Context, requestID string, approved
 
 	request.Status = leave.LeaveRequestStatusApproved
 
-	approvedAtTime := time.Now()
+	approvedAtTime := r.clock.Now()
 	request.Status = leave.LeaveRequestStatusApproved
 	request.ApprovedAt = &approvedAtTime
 	request.ApprovedBy = &approvedID
 
 	updateStatus := string(leave.LeaveRequestStatusApproved)
 	update := leave.UpdateLeaveRequestRequest{
-		ID:         request.ID,
-		Status:     &updateStatus,
-		ApprovedBy: &approvedID,
-		ApprovedAt: &approvedAtTime,
+		ID:              request.ID,
+		Status:          &updateStatus,
+		ApprovedBy:      &approvedID,
+		ApprovedAt:      &approvedAtTime,
+		ExpectedVersion: request.Version,
 	}
 	if err := r.LeaveRequestRepository.Update(ctx, update); err != nil {
 		return leave.LeaveRequest{}, fmt.Errorf("failed to update leave request: %w", err)
@@ -75,7 +94,16 @@ func (r *RequestService) CreateRequest(ctx context.Context, req leave.CreateLeav
 		return leave.LeaveRequest{}, fmt.Errorf("failed to get leave type by ID: %w", err)
 	}
 
-	isEligiible, err := r.checkEligibility(ctx, emp, leaveType)
+	// Leave dates are calendar days in the company's timezone
+	loc := r.companyLocation(ctx, emp.CompanyID)
+	today := utils.StartOfDay(r.clock.Now(), loc)
+	startMonth, err := leaveYearStartMonth(ctx, r.companyRepo, emp.CompanyID)
+	if err != nil {
+		return leave.LeaveRequest{}, err
+	}
+	leaveYear := leave.LeaveYear(today, startMonth)
+
+	isEligiible, err := r.checkEligibility(ctx, emp, leaveType, leaveYear)
 	if err != nil {
 		return leave.LeaveRequest{}, fmt.Errorf("eligibility check failed: %w", err)
 	}
@@ -83,17 +111,17 @@ func (r *RequestService) CreateRequest(ctx context.Context, req leave.CreateLeav
 		return leave.LeaveRequest{}, leave.ErrNotEligible
 	}
 
-	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	startDate, err := utils.ParseDateInLocation(req.StartDate, loc)
 	if err != nil {
 		return leave.LeaveRequest{}, fmt.Errorf("failed to parse start date: %w", err)
 	}
 
-	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	endDate, err := utils.ParseDateInLocation(req.EndDate, loc)
 	if err != nil {
 		return leave.LeaveRequest{}, fmt.Errorf("failed to parse end date: %w", err)
 	}
 
-	if err := r.validateDates(ctx, leaveType, startDate, endDate); err != nil {
+	if err := r.validateDates(ctx, leaveType, startDate, endDate, today); err != nil {
 		return leave.LeaveRequest{}, fmt.Errorf("date validation failed: %w", err)
 	}
 
@@ -113,6 +141,10 @@ func (r *RequestService) CreateRequest(ctx context.Context, req leave.CreateLeav
 		return leave.LeaveRequest{}, fmt.Errorf("failed to calculate working days: %w", err)
 	}
 
+	if err := r.checkQuotaCoversRequest(ctx, emp.ID, leaveType, leaveYear, workingDays); err != nil {
+		return leave.LeaveRequest{}, err
+	}
+
 	request := leave.LeaveRequest{
 		EmployeeID:    emp.ID,
 		LeaveTypeID:   leaveType.ID,
@@ -124,9 +156,10 @@ func (r *RequestService) CreateRequest(ctx context.Context, req leave.CreateLeav
 		Reason:        req.Reason,
 		AttachmentURL: req.AttachmentURL,
 		Status:        leave.LeaveRequestStatusWaitingApproval,
+		RecurrenceID:  req.RecurrenceID,
 	}
 
-	if startDate.Before(time.Now()) {
+	if startDate.Before(today) {
 		request.IsBackdate = true
 	}
 
@@ -151,7 +184,7 @@ func (r *RequestService) Reject(
 		return leave.LeaveRequest{}, leave.ErrLeaveAlreadyProcessed
 	}
 
-	approvedAtTime := time.Now()
+	approvedAtTime := r.clock.Now()
 	request.Status = leave.LeaveRequestStatusRejected
 	request.RejectionReason = &reason
 	request.ApprovedBy = &approvedID
@@ -164,6 +197,7 @@ func (r *RequestService) Reject(
 		RejectionReason: request.RejectionReason,
 		ApprovedBy:      request.ApprovedBy,
 		ApprovedAt:      request.ApprovedAt,
+		ExpectedVersion: request.Version,
 	}
 	err = r.LeaveRequestRepository.Update(ctx, update)
 	if err != nil {
@@ -173,7 +207,7 @@ func (r *RequestService) Reject(
 	return request, nil
 }
 
-func (r *RequestService) checkEligibility(ctx context.Context, emp employee.Employee, leaveType leave.LeaveType) (bool, error) {
+func (r *RequestService) checkEligibility(ctx context.Context, emp employee.Employee, leaveType leave.LeaveType, year int) (bool, error) {
 	// Check if leave type is active
 	if leaveType.IsActive != nil && !*leaveType.IsActive {
 		return false, leave.ErrLeaveTypeInactive
@@ -181,7 +215,7 @@ func (r *RequestService) checkEligibility(ctx context.Context, emp employee.Empl
 
 	// Check if employee has quota for this leave type (if applicable)
 	if leaveType.HasQuota != nil && *leaveType.HasQuota {
-		quota, err := r.LeaveQuotaRepository.GetByEmployeeTypeYear(ctx, emp.ID, leaveType.ID, time.Now().Year())
+		quota, err := r.LeaveQuotaRepository.GetByEmployeeTypeYear(ctx, emp.ID, leaveType.ID, year)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
 				return false, leave.ErrQuotaNotFound
@@ -189,8 +223,9 @@ func (r *RequestService) checkEligibility(ctx context.Context, emp employee.Empl
 			return false, fmt.Errorf("failed to get leave quota: %w", err)
 		}
 
-		// Check if employee has available quota
-		if quota.AvailableQuota == nil || *quota.AvailableQuota <= 0 {
+		// Check if employee has available quota, unless the type may go negative
+		allowNegative := leaveType.AllowNegativeBalance != nil && *leaveType.AllowNegativeBalance
+		if !allowNegative && (quota.AvailableQuota == nil || *quota.AvailableQuota <= 0) {
 			return false, leave.ErrInsufficientQuota
 		}
 	}
@@ -222,12 +257,42 @@ func (r *RequestService) checkEligibility(ctx context.Context, emp employee.Empl
 	return true, nil
 }
 
+// checkQuotaCoversRequest rejects a request for a quota-backed leave type whose working days
+// exceed the available balance, unless the type allows a negative balance. ReserveQuota re-checks under a row lock inside the same transaction.
+func (r *RequestService) checkQuotaCoversRequest(ctx context.Context, employeeID string, leaveType leave.LeaveType, year int, workingDays float64) error {
+	if leaveType.HasQuota == nil || !*leaveType.HasQuota {
+		return nil
+	}
+	if leaveType.AllowNegativeBalance != nil && *leaveType.AllowNegativeBalance {
+		return nil
+	}
+
+	quota, err := r.LeaveQuotaRepository.GetByEmployeeTypeYear(ctx, employeeID, leaveType.ID, year)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return leave.ErrQuotaNotFound
+		}
+		return fmt.Errorf("failed to get leave quota: %w", err)
+	}
+
+	available := 0.0
+	if quota.AvailableQuota != nil {
+		available = *quota.AvailableQuota
+	}
+
+	if workingDays > available {
+		return &leave.InsufficientQuotaError{Available: available, Requested: workingDays}
+	}
+
+	return nil
+}
+
 func (r *RequestService) checkTenureEligibility(emp employee.Employee, rules *leave.QuotaRules) bool {
 	if len(rules.Rules) == 0 {
 		return true
 	}
 
-	tenureMonths := calculateTenureMonths(emp.HireDate)
+	tenureMonths := calculateTenureMonths(emp.HireDate, r.clock.Now())
 
 	// Check if employee meets any of the tenure rules
 	for _, rule := range rules.Rules {
@@ -246,7 +311,8 @@ func (r *RequestService) checkTenureEligibility(emp employee.Employee, rules *le
 		}
 	}
 
-	return false
+	// Employees outside every rule still get the default quota, if one is set
+	return rules.DefaultQuota > 0
 }
 
 func (r *RequestService) checkPositionEligibility(emp employee.Employee, rules *leave.QuotaRules) bool {
@@ -263,12 +329,12 @@ func (r *RequestService) checkPositionEligibility(emp employee.Employee, rules *
 		}
 	}
 
-	return false
+	return rules.DefaultQuota > 0
 }
 
 func (r *RequestService) checkGradeEligibility(emp employee.Employee, rules *leave.QuotaRules) bool {
 	if emp.GradeID == "" {
-		return false
+		return rules.DefaultQuota > 0
 	}
 
 	if len(rules.Rules) == 0 {
@@ -284,7 +350,7 @@ func (r *RequestService) checkGradeEligibility(emp employee.Employee, rules *lea
 		}
 	}
 
-	return false
+	return rules.DefaultQuota > 0
 }
 
 func (r *RequestService) checkEmploymentTypeEligibility(emp employee.Employee, rules *leave.QuotaRules) bool {
@@ -299,89 +365,26 @@ func (r *RequestService) checkEmploymentTypeEligibility(emp employee.Employee, r
 		}
 	}
 
-	return false
+	return rules.DefaultQuota > 0
 }
 
+// checkCombinedEligibility mirrors QuotaCalculator.calculateCombined: an employee is eligible when any
+// rule matches, or when no rule matches but the leave type grants a default quota
 func (r *RequestService) checkCombinedEligibility(emp employee.Employee, rules *leave.QuotaRules) bool {
 	if len(rules.Rules) == 0 {
 		return true
 	}
 
-	tenureMonths := calculateTenureMonths(emp.HireDate)
-
-	// Check if employee matches any of the combined rules
-	for _, rule := range rules.Rules {
-		if rule.Conditions == nil {
-			continue
-		}
-
-		if r.matchesConditions(emp, tenureMonths, rule.Conditions) {
-			return true
-		}
-	}
-
-	return false
-}
-
-func (r *RequestService) matchesConditions(emp employee.Employee, tenureMonths int, conditions *leave.QuotaConditions) bool {
-	// Check position IDs
-	if len(conditions.PositionIDs) > 0 {
-		matched := false
-		for _, posID := range conditions.PositionIDs {
-			if posID == emp.PositionID {
-				matched = true
-				break
-			}
-		}
-		if !matched {
-			return false
-		}
-	}
-
-	// Check grade IDs
-	if len(conditions.GradeIDs) > 0 {
-		if emp.GradeID == "" {
-			return false
-		}
+	tenureMonths := calculateTenureMonths(emp.HireDate, r.clock.Now())
 
-		matched := false
-		for _, gradeID := range conditions.GradeIDs {
-			if gradeID == emp.GradeID {
-				matched = true
-				break
-			}
-		}
-		if !matched {
-			return false
-		}
-	}
-
-	// Check employment type
-	if conditions.EmploymentType != "" {
-		if conditions.EmploymentType != string(emp.EmploymentType) {
-			return false
-		}
-	}
-
-	// Check minimum tenure
-	if conditions.MinTenureMonths != nil {
-		if tenureMonths < *conditions.MinTenureMonths {
-			return false
-		}
-	}
-
-	// Check maximum tenure
-	if conditions.MaxTenureMonths != nil {
-		if tenureMonths >= *conditions.MaxTenureMonths {
-			return false
-		}
+	if _, ok := rules.MatchCombinedRule(emp.PositionID, emp.GradeID, string(emp.EmploymentType), tenureMonths); ok {
+		return true
 	}
 
-	return true
+	return rules.DefaultQuota > 0
 }
 
-func calculateTenureMonths(hireDate time.Time) int {
-	now := time.Now()
+func calculateTenureMonths(hireDate, now time.Time) int {
 	years := now.Year() - hireDate.Year()
 	months := int(now.Month()) - int(hireDate.Month())
 	totalMonths := years*12 + months
@@ -397,21 +400,20 @@ func calculateTenureMonths(hireDate time.Time) int {
 	return totalMonths
 }
 
-// Validate date rules
+// Validate date rules. today is midnight of the current day in the company's timezone.
 func (r *RequestService) validateDates(
 	ctx context.Context,
 	leaveType leave.LeaveType,
 	startDate, endDate time.Time,
+	today time.Time,
 ) error {
-	now := time.Now()
-
 	// Check backdate
-	if startDate.Before(now) {
+	if startDate.Before(today) {
 		if leaveType.AllowBackdate == nil || !*leaveType.AllowBackdate {
 			return leave.ErrBackdateNotAllowed
 		}
 
-		daysDiff := int(now.Sub(startDate).Hours() / 24)
+		daysDiff := utils.DaysBetween(startDate, today)
 		if leaveType.BackdateMaxDays != nil && daysDiff > *leaveType.BackdateMaxDays {
 			return leave.ErrBackdateTooOld
 		}
@@ -419,7 +421,7 @@ func (r *RequestService) validateDates(
 
 	// Check notice period
 	if leaveType.MinNoticeDays != nil {
-		daysDiff := int(startDate.Sub(now).Hours() / 24)
+		daysDiff := utils.DaysBetween(today, startDate)
 		if daysDiff < *leaveType.MinNoticeDays {
 			return leave.ErrInsufficientNotice
 		}
@@ -427,7 +429,7 @@ func (r *RequestService) validateDates(
 
 	// Check advance limit
 	if leaveType.MaxAdvanceDays != nil {
-		daysDiff := int(startDate.Sub(now).Hours() / 24)
+		daysDiff := utils.DaysBetween(today, startDate)
 		if daysDiff > *leaveType.MaxAdvanceDays {
 			return leave.ErrTooFarAdvance
 		}
@@ -435,7 +437,7 @@ func (r *RequestService) validateDates(
 
 	// Check max days per request
 	if leaveType.MaxDaysPerRequest != nil {
-		totalDays := int(endDate.Sub(startDate).Hours()/24) + 1
+		totalDays := utils.DaysBetween(startDate, endDate) + 1
 		if totalDays > *leaveType.MaxDaysPerRequest {
 			return leave.ErrExceedsMaxDays
 		}
@@ -444,42 +446,30 @@ func (r *RequestService) validateDates(
 	return nil
 }
 
-// type WorkingDaysCalculator struct {
-// 	holidayRepo leave.HolidayRepository
-// }
-
-// Calculate calculates working days excluding weekends and holidays
+// Calculate counts the working days between startDate and endDate inclusive, skipping the
+// company's non-working weekdays and holidays. A half-day duration counts its first and last day as half.
 func (r *RequestService) Calculate(
 	ctx context.Context,
 	companyID string,
 	startDate, endDate time.Time,
 	durationType string,
 ) (float64, error) {
-	// Get public holidays in date range
-	// holidays, err := r.holidayRepo.GetByDateRange(ctx, companyID, startDate, endDate)
-	// if err != nil {
-	// 	return 0, err
-	// }
-
-	// Create holiday map for quick lookup
-	// holidayMap := make(map[string]bool)
-	// for _, h := range holidays {
-	// 	holidayMap[h.Date.Format("2006-01-02")] = true
-	// }
-
 	var workingDays float64
+	var calendar company.Calendar
 	currentDate := startDate
 
 	for !currentDate.After(endDate) {
-		// Skip weekends (Saturday and Sunday)
-		if currentDate.Weekday() == time.Saturday || currentDate.Weekday() == time.Sunday {
+		var err error
+		calendar, err = r.calendarFor(ctx, companyID, calendar, currentDate)
+		if err != nil {
+			return 0, err
+		}
+
+		// Skip days the company does not work
+		if !calendar.IsWorkingDay(currentDate) {
 			currentDate = currentDate.AddDate(0, 0, 1)
 			continue
 		}
-		// if holidayMap[currentDate.Format("2006-01-02")] {
-		// 	currentDate = currentDate.AddDate(0, 0, 1)
-		// 	continue
-		// }
 		if (currentDate.Equal(startDate) || currentDate.Equal(endDate)) &&
 			(durationType == "half_day_morning" || durationType == "half_day_afternoon") {
 			workingDays += 0.5
@@ -493,8 +483,21 @@ func (r *RequestService) Calculate(
 	return workingDays, nil
 }
 
+// calendarFor returns calendar if it covers day's year, otherwise the company's calendar for that year.
+// A range can cross into the next year, whose holidays live in another calendar.
+func (r *RequestService) calendarFor(ctx context.Context, companyID string, calendar company.Calendar, day time.Time) (company.Calendar, error) {
+	if calendar.Year == day.Year() {
+		return calendar, nil
+	}
+	next, err := r.calendars.Calendar(ctx, companyID, day.Year())
+	if err != nil {
+		return company.Calendar{}, fmt.Errorf("failed to load company calendar: %w", err)
+	}
+	return next, nil
+}
+
 func (s *RequestService) calculateTotalDays(startDate, endDate time.Time, durationType string) float64 {
-	days := float64(int(endDate.Sub(startDate).Hours()/24) + 1)
+	days := float64(utils.DaysBetween(startDate, endDate) + 1)
 
 	if durationType == "half_day_morning" || durationType == "half_day_afternoon" {
 		if days == 1 {
@@ -0,0 +1,67 @@
+package leave
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+)
+
+func TestCalculate(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	holidays := []company.Holiday{
+		{Date: date("2026-08-17"), Name: "Independence Day"}, // Monday
+		{Date: date("2027-01-01"), Name: "New Year"},         // Friday
+	}
+
+	tests := []struct {
+		name         string
+		start, end   string
+		durationType string
+		want         float64
+		wantYears    []int
+	}{
+		{name: "holiday mid-range", start: "2026-08-14", end: "2026-08-19", durationType: "full_day", want: 3, wantYears: []int{2026}},
+		{name: "no holiday", start: "2026-08-10", end: "2026-08-14", durationType: "full_day", want: 5, wantYears: []int{2026}},
+		{name: "only the holiday", start: "2026-08-17", end: "2026-08-17", durationType: "full_day", want: 0, wantYears: []int{2026}},
+		{name: "half day ends around a holiday", start: "2026-08-14", end: "2026-08-18", durationType: "half_day_morning", want: 1, wantYears: []int{2026}},
+		{name: "across the new year", start: "2026-12-30", end: "2027-01-04", durationType: "full_day", want: 3, wantYears: []int{2026, 2027}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendars := &fakeCalendars{holidays: holidays}
+			s := &RequestService{calendars: calendars}
+
+			got, err := s.Calculate(context.Background(), "company-1", date(tt.start), date(tt.end), tt.durationType)
+			if err != nil {
+				t.Fatalf("Calculate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Calculate() = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(calendars.years, tt.wantYears) {
+				t.Errorf("calendars loaded for %v, want %v", calendars.years, tt.wantYears)
+			}
+		})
+	}
+}
+
+func TestCalculateCalendarError(t *testing.T) {
+	lookupErr := errors.New("connection reset")
+	s := &RequestService{calendars: &fakeCalendars{err: lookupErr}}
+
+	start := time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC)
+	if _, err := s.Calculate(context.Background(), "company-1", start, start.AddDate(0, 0, 2), "full_day"); !errors.Is(err, lookupErr) {
+		t.Errorf("Calculate() error = %v, want %v", err, lookupErr)
+	}
+}
@@ -0,0 +1,146 @@
+package leave
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database/dbtest"
+	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
+	"github.com/jackc/pgx/v5"
+)
+
+// newQuota returns emp-1's 2026 annual leave quota with the given earned, used and pending days
+func newQuota(earned int, used, pending float64) leave.LeaveQuota {
+	zero := 0
+	return leave.LeaveQuota{
+		ID: "quota-1", EmployeeID: "emp-1", LeaveTypeID: "annual", Year: 2026,
+		OpeningBalance: &zero, EarnedQuota: &earned, RolloverQuota: &zero, AdjustmentQuota: &zero,
+		UsedQuota: &used, PendingQuota: &pending,
+	}
+}
+
+// newPendingRequest returns a request by emp-1 for days of annual leave, created in May 2026
+func newPendingRequest(days float64) leave.LeaveRequest {
+	return leave.LeaveRequest{
+		ID: "req-1", EmployeeID: "emp-1", LeaveTypeID: "annual", WorkingDays: days,
+		Status: leave.LeaveRequestStatusWaitingApproval, CreatedAt: time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func newReserveTestService(quotas *fakeQuotaRepo, leaveType leave.LeaveType) *QuotaService {
+	leaveType.ID = "annual"
+	return &QuotaService{
+		LeaveTypeRepository:  &fakeLeaveTypeRepo{types: map[string]leave.LeaveType{"annual": leaveType}},
+		LeaveQuotaRepository: quotas,
+		EmployeeRepository:   &fakeEmployeeRepo{employees: []employee.Employee{{ID: "emp-1", CompanyID: "company-1"}}},
+		companyRepo:          &fakeCompanyRepo{startMonth: 1},
+		clock:                clock.NewFake(time.Date(2026, time.May, 4, 0, 0, 0, 0, time.UTC)),
+	}
+}
+
+func TestConcurrentReservationsOfTheLastDay(t *testing.T) {
+	const requests = 8
+	db, _ := dbtest.NewDB(t, nil)
+	quotas := &fakeQuotaRepo{quotas: []leave.LeaveQuota{newQuota(1, 0, 0)}, rowLock: make(chan struct{}, 1), readDelay: 5 * time.Millisecond}
+	q := newReserveTestService(quotas, leave.LeaveType{Name: "Annual Leave"})
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([]error, requests)
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = postgresql.WithTransaction(context.Background(), db, func(tx pgx.Tx) error {
+				defer quotas.endTx(tx)
+				txCtx := context.WithValue(postgresql.ContextWithTx(context.Background(), tx), txOwnerKey{}, tx)
+				return q.ReserveQuota(txCtx, newPendingRequest(1))
+			})
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	won := 0
+	for _, err := range errs {
+		var insufficient *leave.InsufficientQuotaError
+		switch {
+		case err == nil:
+			won++
+		case errors.As(err, &insufficient):
+			// The loser waited for the lock and saw the day already reserved
+			if insufficient.Available != 0 {
+				t.Errorf("loser saw %.1f days available, want 0", insufficient.Available)
+			}
+		default:
+			t.Errorf("ReserveQuota() error = %v, want success or insufficient quota", err)
+		}
+	}
+	if won != 1 {
+		t.Errorf("%d reservations succeeded, want exactly 1", won)
+	}
+	if pending := *quotas.quotas[0].PendingQuota; pending != 1 {
+		t.Errorf("pending quota = %.1f, want 1", pending)
+	}
+}
+
+func TestQuotaCoversRequestBoundary(t *testing.T) {
+	hasQuota := true
+
+	tests := []struct {
+		name          string
+		quota         leave.LeaveQuota
+		requested     float64
+		wantShortfall float64 // 0 when the request fits
+	}{
+		{name: "exactly enough", quota: newQuota(3, 0, 0), requested: 3},
+		{name: "exactly enough after used and pending days", quota: newQuota(5, 1.5, 0.5), requested: 3},
+		{name: "half a day short", quota: newQuota(3, 0, 0), requested: 3.5, wantShortfall: 0.5},
+		{name: "one day short", quota: newQuota(3, 0, 0), requested: 4, wantShortfall: 1},
+		{name: "one day short after pending days", quota: newQuota(3, 0, 1), requested: 3, wantShortfall: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaveType := leave.LeaveType{ID: "annual", Name: "Annual Leave", HasQuota: &hasQuota}
+			quotas := &fakeQuotaRepo{quotas: []leave.LeaveQuota{tt.quota}}
+			r := &RequestService{LeaveQuotaRepository: quotas}
+			q := newReserveTestService(quotas, leaveType)
+
+			checkErr := r.checkQuotaCoversRequest(context.Background(), "emp-1", leaveType, 2026, tt.requested)
+			reserveErr := q.ReserveQuota(context.Background(), newPendingRequest(tt.requested))
+
+			for name, err := range map[string]error{"checkQuotaCoversRequest": checkErr, "ReserveQuota": reserveErr} {
+				if tt.wantShortfall == 0 {
+					if err != nil {
+						t.Errorf("%s() error = %v, want nil", name, err)
+					}
+					continue
+				}
+				var insufficient *leave.InsufficientQuotaError
+				if !errors.As(err, &insufficient) {
+					t.Fatalf("%s() error = %v, want InsufficientQuotaError", name, err)
+				}
+				if insufficient.Requested != tt.requested || insufficient.Shortfall() != tt.wantShortfall {
+					t.Errorf("%s() requested %.1f short by %.1f, want %.1f short by %.1f",
+						name, insufficient.Requested, insufficient.Shortfall(), tt.requested, tt.wantShortfall)
+				}
+			}
+
+			wantPending := *tt.quota.PendingQuota
+			if tt.wantShortfall == 0 {
+				wantPending += tt.requested
+			}
+			if pending := *quotas.quotas[0].PendingQuota; pending != wantPending {
+				t.Errorf("pending quota = %.1f, want %.1f", pending, wantPending)
+			}
+		})
+	}
+}
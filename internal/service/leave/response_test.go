@@ -0,0 +1,47 @@
+package leave
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/testutil"
+)
+
+// A soft-deleted employee leaves the joined name NULL; responses map it to an empty name
+func TestLeaveResponsesWithNullEmployeeName(t *testing.T) {
+	start := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	l := &LeaveServiceImpl{
+		LeaveRequestRepository: &fakeLeaveRequestRepo{
+			requests: map[string]leave.LeaveRequest{
+				"req-1": {ID: "req-1", EmployeeID: "emp-deleted", LeaveTypeID: "annual", StartDate: start, EndDate: start,
+					Status: leave.LeaveRequestStatusApproved},
+			},
+			companyOf: map[string]string{"req-1": "company-1"},
+		},
+		LeaveTypeRepository: &fakeLeaveTypeRepo{types: map[string]leave.LeaveType{
+			"annual": {ID: "annual", Name: "Annual Leave"},
+		}},
+	}
+	ctx := testutil.ClaimsContext(t, map[string]any{"company_id": "company-1", "role": string(user.RoleOwner)})
+
+	got, err := l.GetLeaveRequest(ctx, "req-1")
+	if err != nil {
+		t.Fatalf("GetLeaveRequest() error = %v", err)
+	}
+	if got.EmployeeName != "" || got.LeaveTypeName != "Annual Leave" {
+		t.Errorf("GetLeaveRequest() names = %q, %q, want an empty employee name", got.EmployeeName, got.LeaveTypeName)
+	}
+
+	list, err := l.ListLeaveRequest(ctx, "company-1", leave.LeaveRequestFilter{})
+	if err != nil {
+		t.Fatalf("ListLeaveRequest() error = %v", err)
+	}
+	if len(list.Requests) != 1 {
+		t.Fatalf("ListLeaveRequest() returned %d requests, want 1", len(list.Requests))
+	}
+	if got := list.Requests[0]; got.EmployeeName != "" || got.LeaveTypeName != "" {
+		t.Errorf("ListLeaveRequest() names = %q, %q, want both empty", got.EmployeeName, got.LeaveTypeName)
+	}
+}
@@ -2,19 +2,28 @@ package leave
 
 import (
 	"context"
+	"encoding/csv"
 	"errors"
 	"fmt"
-	"math"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/attendance"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/pagination"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/scope"
 	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
 	"github.com/cmlabs-hris/hris-backend-go/internal/service/file"
 	"github.com/go-chi/jwtauth/v5"
@@ -32,48 +41,18 @@ type LeaveServiceImpl struct {
 	requestService      *RequestService
 	fileService         file.FileService
 	notificationService notification.Service
+	clock               clock.Clock
+	// Repeated leave request notifications from one employee within this window are coalesced
+	managerNotifyWindow time.Duration
+	// A rejected request can be reopened by its employee for this long after the rejection
+	reopenWindow time.Duration
 }
 
 // GetLeaveRequest implements leave.LeaveService.
 func (l *LeaveServiceImpl) GetLeaveRequest(ctx context.Context, requestID string) (leave.LeaveRequestResponse, error) {
-	// Extract claims from JWT context
-	_, claims, err := jwtauth.FromContext(ctx)
-	if err != nil {
-		return leave.LeaveRequestResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
-	}
-
-	roleStr, _ := claims["user_role"].(string)
-	role := user.Role(roleStr)
-
-	// Fetch the leave request by ID
-	request, err := l.LeaveRequestRepository.GetByID(ctx, requestID)
+	request, err := l.getViewableRequest(ctx, requestID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return leave.LeaveRequestResponse{}, leave.ErrLeaveRequestNotFound
-		}
-		return leave.LeaveRequestResponse{}, fmt.Errorf("failed to get leave request: %w", err)
-	}
-
-	// If the role is employee, ensure the request belongs to the employee
-	if role == user.RoleEmployee {
-		userID, ok := claims["user_id"].(string)
-		if !ok || userID == "" {
-			return leave.LeaveRequestResponse{}, fmt.Errorf("user_id claim is missing or invalid")
-		}
-
-		// Fetch employee by user ID
-		employeeData, err := l.EmployeeRepository.GetByUserID(ctx, userID)
-		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
-				return leave.LeaveRequestResponse{}, employee.ErrEmployeeNotFound
-			}
-			return leave.LeaveRequestResponse{}, fmt.Errorf("failed to get employee by user ID: %w", err)
-		}
-
-		// Compare employee ID from request and context
-		if request.EmployeeID != employeeData.ID {
-			return leave.LeaveRequestResponse{}, leave.ErrUnauthorizedAccess
-		}
+		return leave.LeaveRequestResponse{}, err
 	}
 
 	// Fetch the leave type details
@@ -85,7 +64,7 @@ func (l *LeaveServiceImpl) GetLeaveRequest(ctx context.Context, requestID string
 	// Generate attachment URL if exists
 	var attachmentURL *string
 	if request.AttachmentURL != nil && *request.AttachmentURL != "" {
-		fullURL, err := l.fileService.GetFileURL(ctx, *request.AttachmentURL, 0)
+		fullURL, err := l.fileService.GetFileURL(ctx, *request.AttachmentURL, l.fileService.URLExpiry().Default)
 		if err == nil {
 			attachmentURL = &fullURL
 		}
@@ -95,7 +74,7 @@ func (l *LeaveServiceImpl) GetLeaveRequest(ctx context.Context, requestID string
 	response := leave.LeaveRequestResponse{
 		ID:              request.ID,
 		EmployeeID:      request.EmployeeID,
-		EmployeeName:    *request.EmployeeName,
+		EmployeeName:    stringValue(request.EmployeeName),
 		LeaveTypeID:     request.LeaveTypeID,
 		LeaveTypeName:   leaveType.Name,
 		StartDate:       request.StartDate,
@@ -110,6 +89,9 @@ func (l *LeaveServiceImpl) GetLeaveRequest(ctx context.Context, requestID string
 		ApprovedBy:      request.ApprovedBy,
 		ApprovedAt:      request.ApprovedAt,
 		RejectionReason: request.RejectionReason,
+		RecurrenceID:    request.RecurrenceID,
+		EscalatedAt:     request.EscalatedAt,
+		Version:         request.Version,
 	}
 
 	return response, nil
@@ -158,6 +140,94 @@ func (l *LeaveServiceImpl) GetMyRequest(ctx context.Context, userID string, comp
 	}, nil
 }
 
+// getViewableRequest fetches a leave request the caller may view: only requests of the caller's company,
+// employees only their own, branch-scoped managers only those of their branch
+func (l *LeaveServiceImpl) getViewableRequest(ctx context.Context, requestID string) (leave.LeaveRequest, error) {
+	// Extract claims from JWT context
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return leave.LeaveRequest{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return leave.LeaveRequest{}, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	roleStr, _ := claims["role"].(string)
+	role := user.Role(roleStr)
+
+	// Requests of other companies are reported as missing rather than forbidden
+	request, err := l.LeaveRequestRepository.GetByIDInCompany(ctx, requestID, companyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, leave.ErrLeaveRequestNotFound) {
+			return leave.LeaveRequest{}, leave.ErrLeaveRequestNotFound
+		}
+		return leave.LeaveRequest{}, fmt.Errorf("failed to get leave request: %w", err)
+	}
+
+	// If the role is employee, ensure the request belongs to the employee
+	if role == user.RoleEmployee {
+		userID, ok := claims["user_id"].(string)
+		if !ok || userID == "" {
+			return leave.LeaveRequest{}, fmt.Errorf("user_id claim is missing or invalid")
+		}
+
+		// Fetch employee by user ID
+		employeeData, err := l.EmployeeRepository.GetByUserID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return leave.LeaveRequest{}, employee.ErrEmployeeNotFound
+			}
+			return leave.LeaveRequest{}, fmt.Errorf("failed to get employee by user ID: %w", err)
+		}
+
+		// Compare employee ID from request and context
+		if request.EmployeeID != employeeData.ID {
+			return leave.LeaveRequest{}, leave.ErrUnauthorizedAccess
+		}
+	}
+
+	// Branch-scoped managers may only see requests from their own branch
+	if err := l.ensureEmployeeInBranchScope(ctx, request.EmployeeID); err != nil {
+		return leave.LeaveRequest{}, err
+	}
+
+	return request, nil
+}
+
+// GetLeaveAttachment implements leave.LeaveService.
+// Access follows GetLeaveRequest. The file is read through the app, so the storage key and
+// URL are never handed to the client.
+func (l *LeaveServiceImpl) GetLeaveAttachment(ctx context.Context, requestID string) (leave.LeaveAttachment, error) {
+	request, err := l.getViewableRequest(ctx, requestID)
+	if err != nil {
+		return leave.LeaveAttachment{}, err
+	}
+
+	if request.AttachmentURL == nil || *request.AttachmentURL == "" {
+		return leave.LeaveAttachment{}, leave.ErrAttachmentNotFound
+	}
+
+	content, err := l.fileService.OpenFile(ctx, *request.AttachmentURL)
+	if err != nil {
+		return leave.LeaveAttachment{}, fmt.Errorf("failed to open leave attachment: %w", err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(*request.AttachmentURL))
+	contentType := mime.TypeByExtension(ext)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return leave.LeaveAttachment{
+		Content:     content,
+		FileName:    fmt.Sprintf("leave-%s-attachment%s", request.StartDate.Format("2006-01-02"), ext),
+		ContentType: contentType,
+		ModTime:     request.UpdatedAt,
+	}, nil
+}
+
 // ListLeaveRequest implements leave.LeaveService.
 func (l *LeaveServiceImpl) ListLeaveRequest(
 	ctx context.Context,
@@ -170,6 +240,9 @@ func (l *LeaveServiceImpl) ListLeaveRequest(
 		return leave.ListLeaveRequestResponse{}, err
 	}
 
+	// Branch-scoped managers only see their own branch
+	filter.BranchID = scope.BranchFilter(ctx)
+
 	// Get requests from repository
 	leaveRequests, totalCount, err := l.LeaveRequestRepository.GetByCompanyID(ctx, companyID, filter)
 	if err != nil {
@@ -185,7 +258,7 @@ func (l *LeaveServiceImpl) ListLeaveRequest(
 		// Generate attachment URL if exists
 		var attachmentURL *string
 		if req.AttachmentURL != nil && *req.AttachmentURL != "" {
-			fullURL, err := l.fileService.GetFileURL(ctx, *req.AttachmentURL, 0)
+			fullURL, err := l.fileService.GetFileURL(ctx, *req.AttachmentURL, l.fileService.URLExpiry().Default)
 			if err == nil {
 				attachmentURL = &fullURL
 			}
@@ -194,9 +267,9 @@ func (l *LeaveServiceImpl) ListLeaveRequest(
 		leaveRequestResponses = append(leaveRequestResponses, leave.LeaveRequestResponse{
 			ID:              req.ID,
 			EmployeeID:      req.EmployeeID,
-			EmployeeName:    *req.EmployeeName,
+			EmployeeName:    stringValue(req.EmployeeName),
 			LeaveTypeID:     req.LeaveTypeID,
-			LeaveTypeName:   *req.LeaveTypeName,
+			LeaveTypeName:   stringValue(req.LeaveTypeName),
 			StartDate:       req.StartDate,
 			EndDate:         req.EndDate,
 			DurationType:    string(req.DurationType),
@@ -209,34 +282,163 @@ func (l *LeaveServiceImpl) ListLeaveRequest(
 			ApprovedBy:      req.ApprovedBy,
 			ApprovedAt:      req.ApprovedAt,
 			RejectionReason: req.RejectionReason,
+			EscalatedAt:     req.EscalatedAt,
+			Version:         req.Version,
 		})
 	}
 
 	// Calculate pagination metadata
-	totalPages := int(math.Ceil(float64(totalCount) / float64(filter.Limit)))
-
-	// Calculate "showing" text
-	start := (filter.Page-1)*filter.Limit + 1
-	end := start + len(leaveRequestResponses) - 1
-	if end > int(totalCount) {
-		end = int(totalCount)
-	}
-
-	showing := fmt.Sprintf("%d-%d of %d results", start, end, totalCount)
-	if totalCount == 0 {
-		showing = "0 results"
-	}
+	meta := pagination.Meta(filter.Page, filter.Limit, totalCount)
 
 	return leave.ListLeaveRequestResponse{
 		TotalCount: totalCount,
 		Page:       filter.Page,
 		Limit:      filter.Limit,
-		TotalPages: totalPages,
-		Showing:    showing,
+		TotalPages: meta.TotalPages,
+		Showing:    meta.Showing,
 		Requests:   leaveRequestResponses,
 	}, nil
 }
 
+// leaveExportFlushEvery is how many CSV rows are buffered before they are flushed to the client
+const leaveExportFlushEvery = 500
+
+// ExportLeaveRequests implements leave.LeaveService.
+// It writes every request matching the filter as CSV; pagination in the filter is ignored.
+// Nothing reaches w until the query has started returning rows, so early errors can still
+// be reported as a normal error response.
+func (l *LeaveServiceImpl) ExportLeaveRequests(
+	ctx context.Context,
+	companyID string,
+	filter leave.LeaveRequestFilter,
+	w io.Writer,
+) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	// Branch-scoped managers only see their own branch
+	filter.BranchID = scope.BranchFilter(ctx)
+
+	writer := csv.NewWriter(w)
+	header := []string{
+		"employee_code", "employee_name", "leave_type", "start_date", "end_date",
+		"total_days", "working_days", "status", "submitted_at", "approved_at", "rejection_reason",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	written := 0
+	err := l.LeaveRequestRepository.StreamByCompanyID(ctx, companyID, filter, func(req leave.LeaveRequest) error {
+		var approvedAt string
+		if req.ApprovedAt != nil {
+			approvedAt = req.ApprovedAt.Format(time.RFC3339)
+		}
+
+		record := []string{
+			stringValue(req.EmployeeCode),
+			stringValue(req.EmployeeName),
+			stringValue(req.LeaveTypeName),
+			req.StartDate.Format("2006-01-02"),
+			req.EndDate.Format("2006-01-02"),
+			strconv.FormatFloat(req.TotalDays, 'f', -1, 64),
+			strconv.FormatFloat(req.WorkingDays, 'f', -1, 64),
+			string(req.Status),
+			req.SubmittedAt.Format(time.RFC3339),
+			approvedAt,
+			stringValue(req.RejectionReason),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv record: %w", err)
+		}
+
+		written++
+		if written%leaveExportFlushEvery == 0 {
+			writer.Flush()
+			return writer.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export leave requests: %w", err)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush csv: %w", err)
+	}
+
+	return nil
+}
+
+// CountPendingApprovals implements leave.LeaveService.
+// Every manager of the company is responsible for its waiting requests, except their own;
+// branch-scoped managers only count requests from their branch.
+func (l *LeaveServiceImpl) CountPendingApprovals(ctx context.Context, managerUserID string) (int64, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return 0, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	return l.LeaveRequestRepository.CountPendingApprovals(ctx, companyID, managerUserID, scope.BranchFilter(ctx))
+}
+
+// ListEmployeeRequests implements leave.LeaveService.
+// It lists the requests of one employee with the company-wide list's scoping and pagination;
+// branch-scoped managers only reach employees of their branch.
+func (l *LeaveServiceImpl) ListEmployeeRequests(
+	ctx context.Context,
+	employeeID string,
+	filter leave.MyLeaveRequestFilter,
+) (leave.ListLeaveRequestResponse, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return leave.ListLeaveRequestResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return leave.ListLeaveRequestResponse{}, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	if err := filter.Validate(); err != nil {
+		return leave.ListLeaveRequestResponse{}, err
+	}
+
+	emp, err := l.EmployeeRepository.GetByID(ctx, employeeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return leave.ListLeaveRequestResponse{}, employee.ErrEmployeeNotFound
+		}
+		return leave.ListLeaveRequestResponse{}, fmt.Errorf("failed to get employee: %w", err)
+	}
+	if emp.CompanyID != companyID {
+		return leave.ListLeaveRequestResponse{}, employee.ErrEmployeeNotFound
+	}
+	if branchID, scoped := scope.BranchIDFromContext(ctx); scoped && emp.BranchID != branchID {
+		return leave.ListLeaveRequestResponse{}, leave.ErrUnauthorizedAccess
+	}
+
+	return l.ListLeaveRequest(ctx, companyID, leave.LeaveRequestFilter{
+		EmployeeID:  &emp.ID,
+		LeaveTypeID: filter.LeaveTypeID,
+		Status:      filter.Status,
+		StartDate:   filter.StartDate,
+		EndDate:     filter.EndDate,
+		DateMode:    filter.DateMode,
+		Page:        filter.Page,
+		Limit:       filter.Limit,
+		SortBy:      filter.SortBy,
+		SortOrder:   filter.SortOrder,
+	})
+}
+
 // ListMyLeaveRequests implements leave.LeaveService - filtered version for authenticated user
 func (l *LeaveServiceImpl) ListMyLeaveRequests(
 	ctx context.Context,
@@ -265,7 +467,7 @@ func (l *LeaveServiceImpl) ListMyLeaveRequests(
 		// Generate attachment URL if exists
 		var attachmentURL *string
 		if req.AttachmentURL != nil && *req.AttachmentURL != "" {
-			fullURL, err := l.fileService.GetFileURL(ctx, *req.AttachmentURL, 0)
+			fullURL, err := l.fileService.GetFileURL(ctx, *req.AttachmentURL, l.fileService.URLExpiry().Default)
 			if err == nil {
 				attachmentURL = &fullURL
 			}
@@ -274,9 +476,9 @@ func (l *LeaveServiceImpl) ListMyLeaveRequests(
 		leaveRequestResponses = append(leaveRequestResponses, leave.LeaveRequestResponse{
 			ID:              req.ID,
 			EmployeeID:      req.EmployeeID,
-			EmployeeName:    *req.EmployeeName,
+			EmployeeName:    stringValue(req.EmployeeName),
 			LeaveTypeID:     req.LeaveTypeID,
-			LeaveTypeName:   *req.LeaveTypeName,
+			LeaveTypeName:   stringValue(req.LeaveTypeName),
 			StartDate:       req.StartDate,
 			EndDate:         req.EndDate,
 			DurationType:    string(req.DurationType),
@@ -289,39 +491,29 @@ func (l *LeaveServiceImpl) ListMyLeaveRequests(
 			ApprovedBy:      req.ApprovedBy,
 			ApprovedAt:      req.ApprovedAt,
 			RejectionReason: req.RejectionReason,
+			EscalatedAt:     req.EscalatedAt,
+			Version:         req.Version,
 		})
 	}
 
 	// Calculate pagination metadata
-	totalPages := int(math.Ceil(float64(totalCount) / float64(filter.Limit)))
-
-	// Calculate "showing" text
-	start := (filter.Page-1)*filter.Limit + 1
-	end := start + len(leaveRequestResponses) - 1
-	if end > int(totalCount) {
-		end = int(totalCount)
-	}
-
-	showing := fmt.Sprintf("%d-%d of %d results", start, end, totalCount)
-	if totalCount == 0 {
-		showing = "0 results"
-	}
+	meta := pagination.Meta(filter.Page, filter.Limit, totalCount)
 
 	return leave.ListLeaveRequestResponse{
 		TotalCount: totalCount,
 		Page:       filter.Page,
 		Limit:      filter.Limit,
-		TotalPages: totalPages,
-		Showing:    showing,
+		TotalPages: meta.TotalPages,
+		Showing:    meta.Showing,
 		Requests:   leaveRequestResponses,
 	}, nil
 }
 
 // CreateLeaveRequest implements leave.LeaveService.
-func (l *LeaveServiceImpl) GetMyQuota(ctx context.Context, userID string, year int) ([]leave.LeaveQuotaResponse, error) {
+func (l *LeaveServiceImpl) GetMyQuota(ctx context.Context, employeeID string) ([]leave.LeaveQuotaResponse, error) {
 	var leaveQuotaReponse []leave.LeaveQuotaResponse
 
-	emp, err := l.EmployeeRepository.GetByID(ctx, userID)
+	emp, err := l.EmployeeRepository.GetByID(ctx, employeeID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, employee.ErrEmployeeNotFound
@@ -330,6 +522,10 @@ func (l *LeaveServiceImpl) GetMyQuota(ctx context.Context, userID string, year i
 		}
 	}
 
+	year, err := l.quotaService.CurrentLeaveYear(ctx, emp.CompanyID)
+	if err != nil {
+		return nil, err
+	}
 	leaveQuotas, err := l.LeaveQuotaRepository.GetByEmployeeYear(ctx, emp.ID, year)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -343,20 +539,7 @@ func (l *LeaveServiceImpl) GetMyQuota(ctx context.Context, userID string, year i
 		if err != nil {
 			return nil, fmt.Errorf("failed to get leave type by ID: %w", err)
 		}
-		leaveQuotaReponse = append(leaveQuotaReponse, leave.LeaveQuotaResponse{
-			ID:              leaveQuota.ID,
-			EmployeeID:      leaveQuota.EmployeeID,
-			LeaveTypeID:     leaveQuota.LeaveTypeID,
-			LeaveTypeName:   leaveType.Name,
-			Year:            leaveQuota.Year,
-			OpeningBalance:  *leaveQuota.OpeningBalance,
-			EarnedQuota:     *leaveQuota.EarnedQuota,
-			RolloverQuota:   *leaveQuota.RolloverQuota,
-			AdjustmentQuota: *leaveQuota.AdjustmentQuota,
-			UsedQuota:       *leaveQuota.UsedQuota,
-			PendingQuota:    *leaveQuota.PendingQuota,
-			AvailableQuota:  *leaveQuota.AvailableQuota,
-		})
+		leaveQuotaReponse = append(leaveQuotaReponse, leaveQuota.ToResponse(leaveType.Name))
 	}
 
 	return leaveQuotaReponse, nil
@@ -364,8 +547,12 @@ func (l *LeaveServiceImpl) GetMyQuota(ctx context.Context, userID string, year i
 
 // AdjustLeaveQuota implements leave.LeaveService.
 func (l *LeaveServiceImpl) AdjustLeaveQuota(ctx context.Context, req leave.AdjustQuotaRequest) error {
+	if err := scope.Require(ctx, user.PermissionLeaveAdjustQuota); err != nil {
+		return err
+	}
+
 	return postgresql.WithTransaction(ctx, l.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+		txCtx := postgresql.ContextWithTx(ctx, tx)
 		return l.quotaService.AdjustQuota(txCtx, req.EmployeeID, req.LeaveTypeID, req.Year, req.Adjustment, req.Reason)
 	})
 }
@@ -383,79 +570,110 @@ func (l *LeaveServiceImpl) GetByEmployeeYear(ctx context.Context, employeeID str
 }
 
 // ApproveLeaveRequest implements leave.LeaveService.
-func (l *LeaveServiceImpl) ApproveLeaveRequest(ctx context.Context, requestID string) error {
+func (l *LeaveServiceImpl) ApproveLeaveRequest(ctx context.Context, requestID string) (leave.ApproveLeaveResponse, error) {
 	_, claims, err := jwtauth.FromContext(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to extract claims from context: %w", err)
+		return leave.ApproveLeaveResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
 	}
 
 	approverID, ok := claims["user_id"].(string)
 	if !ok || approverID == "" {
-		return fmt.Errorf("user_id claim is missing or invalid")
+		return leave.ApproveLeaveResponse{}, fmt.Errorf("user_id claim is missing or invalid")
 	}
 
 	companyID, _ := claims["company_id"].(string)
 
-	var request leave.LeaveRequest
-	err = postgresql.WithTransaction(ctx, l.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+	if err := l.ensureRequestInBranchScope(ctx, requestID); err != nil {
+		return leave.ApproveLeaveResponse{}, err
+	}
 
+	var result leave.ApproveLeaveResponse
+	err = postgresql.WithTransactionRetry(ctx, l.db, func(tx pgx.Tx) error {
 		var txErr error
-		request, txErr = l.requestService.Approve(txCtx, requestID, approverID)
-		if txErr != nil {
-			return fmt.Errorf("failed to approve leave request: %w", txErr)
-		}
+		result, txErr = l.approveInTx(postgresql.ContextWithTx(ctx, tx), requestID, companyID, approverID)
+		return txErr
+	})
+	if err != nil {
+		return leave.ApproveLeaveResponse{}, err
+	}
 
-		if txErr = l.quotaService.MovePendingToUsed(ctx, request.EmployeeID, request.LeaveTypeID, request.WorkingDays); txErr != nil {
-			return fmt.Errorf("failed to move pending to used quota: %w", txErr)
-		}
+	return result, nil
+}
 
-		// Create attendance records for each day of the leave period
-		if txErr = l.createLeaveAttendanceRecords(txCtx, request, companyID, approverID); txErr != nil {
-			return fmt.Errorf("failed to create leave attendance records: %w", txErr)
-		}
+// approveInTx approves a waiting request on behalf of approverID: it moves the reserved quota to used,
+// creates the leave attendance records and enqueues the employee's notification.
+// ctx must carry the approval transaction.
+func (l *LeaveServiceImpl) approveInTx(ctx context.Context, requestID, companyID, approverID string) (leave.ApproveLeaveResponse, error) {
+	request, err := l.requestService.Approve(ctx, requestID, approverID)
+	if err != nil {
+		return leave.ApproveLeaveResponse{}, fmt.Errorf("failed to approve leave request: %w", err)
+	}
 
-		return nil
-	})
+	if err := l.quotaService.MovePendingToUsed(ctx, request); err != nil {
+		return leave.ApproveLeaveResponse{}, fmt.Errorf("failed to move pending to used quota: %w", err)
+	}
+
+	// Create attendance records for each day of the leave period
+	result, err := l.createLeaveAttendanceRecords(ctx, request, companyID, approverID)
 	if err != nil {
-		return err
+		return leave.ApproveLeaveResponse{}, fmt.Errorf("failed to create leave attendance records: %w", err)
 	}
 
 	// Notify employee that their leave request was approved
-	// Use context.WithoutCancel to prevent cancellation when HTTP request ends
-	go l.notifyEmployeeOnLeaveApproved(context.WithoutCancel(ctx), request, companyID, approverID)
+	if err := l.enqueueLeaveApprovedNotification(ctx, request, companyID, approverID); err != nil {
+		return leave.ApproveLeaveResponse{}, err
+	}
 
-	return nil
+	return result, nil
 }
 
-// createLeaveAttendanceRecords creates attendance records with status as leave type name for each day in the leave period
-func (l *LeaveServiceImpl) createLeaveAttendanceRecords(ctx context.Context, request leave.LeaveRequest, companyID string, approverID string) error {
+// createLeaveAttendanceRecords creates attendance records with status as leave type name for each day in the leave period.
+// The company's non-working weekdays, its holidays and days that already have an attendance are skipped and reported; any other failure aborts the
+// caller's transaction with a LeaveAttendanceDateError naming the day.
+func (l *LeaveServiceImpl) createLeaveAttendanceRecords(ctx context.Context, request leave.LeaveRequest, companyID string, approverID string) (leave.ApproveLeaveResponse, error) {
+	result := leave.ApproveLeaveResponse{
+		RequestID:    request.ID,
+		Status:       request.Status,
+		CreatedDates: []string{},
+		SkippedDates: []leave.SkippedLeaveDate{},
+	}
+
 	// Get leave type name for attendance status
 	leaveType, err := l.LeaveTypeRepository.GetByID(ctx, request.LeaveTypeID)
 	if err != nil {
-		return fmt.Errorf("failed to get leave type: %w", err)
+		return result, fmt.Errorf("failed to get leave type: %w", err)
 	}
 
-	currentDate := request.StartDate
-	now := time.Now()
+	now := l.clock.Now()
+	var calendar company.Calendar
+
+	for currentDate := request.StartDate; !currentDate.After(request.EndDate); currentDate = currentDate.AddDate(0, 0, 1) {
+		date := currentDate.Format("2006-01-02")
 
-	for !currentDate.After(request.EndDate) {
-		// Skip weekends (Saturday = 6, Sunday = 0)
-		weekday := currentDate.Weekday()
-		if weekday == time.Saturday || weekday == time.Sunday {
-			currentDate = currentDate.AddDate(0, 0, 1)
+		calendar, err = l.requestService.calendarFor(ctx, companyID, calendar, currentDate)
+		if err != nil {
+			return result, &leave.LeaveAttendanceDateError{Date: date, Err: err}
+		}
+
+		// Days the company does not work consume no leave, so they get no record
+		if !calendar.IsWorkingWeekday(currentDate) {
+			result.SkippedDates = append(result.SkippedDates, leave.SkippedLeaveDate{Date: date, Reason: leave.LeaveDateSkippedWeekend})
+			continue
+		}
+		if calendar.IsHoliday(currentDate) {
+			result.SkippedDates = append(result.SkippedDates, leave.SkippedLeaveDate{Date: date, Reason: leave.LeaveDateSkippedHoliday})
 			continue
 		}
 
 		// Check if attendance already exists for this date
 		existingAttendance, err := l.AttendanceRepository.GetByEmployeeAndDate(ctx, request.EmployeeID, currentDate, companyID)
 		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-			return fmt.Errorf("failed to check existing attendance: %w", err)
+			return result, &leave.LeaveAttendanceDateError{Date: date, Err: fmt.Errorf("failed to check existing attendance: %w", err)}
 		}
 
-		// Skip if attendance already exists
+		// Keep the existing record, e.g. a day the employee already clocked in
 		if existingAttendance != nil {
-			currentDate = currentDate.AddDate(0, 0, 1)
+			result.SkippedDates = append(result.SkippedDates, leave.SkippedLeaveDate{Date: date, Reason: leave.LeaveDateSkippedAttendanceExists})
 			continue
 		}
 
@@ -470,15 +688,13 @@ func (l *LeaveServiceImpl) createLeaveAttendanceRecords(ctx context.Context, req
 			ApprovedAt:  &now,
 		}
 
-		_, err = l.AttendanceRepository.Create(ctx, leaveAttendance)
-		if err != nil {
-			return fmt.Errorf("failed to create leave attendance for date %s: %w", currentDate.Format("2006-01-02"), err)
+		if _, err = l.AttendanceRepository.Create(ctx, leaveAttendance); err != nil {
+			return result, &leave.LeaveAttendanceDateError{Date: date, Err: err}
 		}
-
-		currentDate = currentDate.AddDate(0, 0, 1)
+		result.CreatedDates = append(result.CreatedDates, date)
 	}
 
-	return nil
+	return result, nil
 }
 
 // CancelLeaveRequest implements leave.LeaveService.
@@ -493,58 +709,41 @@ func (l *LeaveServiceImpl) CreateLeaveQuota(ctx context.Context, req leave.Creat
 
 // CreateLeaveRequest implements leave.LeaveService.
 func (l *LeaveServiceImpl) CreateLeaveRequest(ctx context.Context, req leave.CreateLeaveRequestRequest) (leave.LeaveRequestResponse, error) {
-	var requestResponse leave.LeaveRequestResponse
-	err := postgresql.WithTransaction(ctx, l.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
-
-		leaveType, err := l.LeaveTypeRepository.GetByID(txCtx, req.LeaveTypeID)
+	leaveType, err := l.LeaveTypeRepository.GetByID(ctx, req.LeaveTypeID)
+	if err != nil {
+		return leave.LeaveRequestResponse{}, fmt.Errorf("failed to get leave type by ID: %w", err)
+	}
+	// Uploaded before the transaction, which may be retried
+	if *leaveType.RequiresAttachment {
+		if req.File == nil || req.FileHeader == nil {
+			return leave.LeaveRequestResponse{}, leave.ErrAttachmentRequired
+		}
+		attachmentURL, err := l.uploadAttachment(ctx, req.EmployeeID, req.File, req.FileHeader)
 		if err != nil {
-			return fmt.Errorf("failed to get leave type by ID: %w", err)
+			return leave.LeaveRequestResponse{}, err
 		}
-		if *leaveType.RequiresAttachment {
-			if req.File == nil || req.FileHeader == nil {
-				return leave.ErrAttachmentRequired
-			}
-			if req.FileHeader.Size > 5<<20 {
-				return leave.ErrFileSizeExceeds
-			}
-
-			allowedExts := []string{".pdf", ".jpg", ".jpeg", ".png"}
-			ext := strings.ToLower(filepath.Ext(req.FileHeader.Filename))
-
-			isValidExt := false
-			for _, allowed := range allowedExts {
-				if ext == allowed {
-					isValidExt = true
-					break
-				}
-			}
+		req.AttachmentURL = &attachmentURL
+	}
 
-			if !isValidExt {
-				return leave.ErrFileTypeNotAllowed
-			}
+	var requestResponse leave.LeaveRequestResponse
+	err = postgresql.WithTransactionRetry(ctx, l.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
 
-			attachmentURL, err := l.fileService.UploadLeaveAttachment(ctx, req.EmployeeID, req.File, req.FileHeader.Filename)
-			if err != nil {
-				return fmt.Errorf("failed to upload leave attachment: %w", err)
-			}
-			req.AttachmentURL = &attachmentURL
-		}
 		leaveRequest, err := l.requestService.CreateRequest(txCtx, req)
 		if err != nil {
 			return fmt.Errorf("failed to create leave request: %w", err)
 		}
 
-		err = l.quotaService.ReserveQuota(txCtx, leaveRequest.EmployeeID, leaveRequest.LeaveTypeID, leaveRequest.WorkingDays)
+		err = l.quotaService.ReserveQuota(txCtx, leaveRequest)
 		if err != nil {
 			return fmt.Errorf("failed to reserve quota: %w", err)
 		}
 		requestResponse = leave.LeaveRequestResponse{
 			ID:            leaveRequest.ID,
 			EmployeeID:    leaveRequest.EmployeeID,
-			EmployeeName:  *leaveRequest.EmployeeName,
+			EmployeeName:  stringValue(leaveRequest.EmployeeName),
 			LeaveTypeID:   leaveRequest.LeaveTypeID,
-			LeaveTypeName: *leaveRequest.LeaveTypeName,
+			LeaveTypeName: stringValue(leaveRequest.LeaveTypeName),
 			StartDate:     leaveRequest.StartDate,
 			EndDate:       leaveRequest.EndDate,
 			DurationType:  string(leaveRequest.DurationType),
@@ -554,6 +753,7 @@ func (l *LeaveServiceImpl) CreateLeaveRequest(ctx context.Context, req leave.Cre
 			AttachmentURL: leaveRequest.AttachmentURL,
 			Status:        string(leaveRequest.Status),
 			SubmittedAt:   leaveRequest.SubmittedAt,
+			Version:       leaveRequest.Version,
 		}
 		return nil
 	})
@@ -568,6 +768,34 @@ func (l *LeaveServiceImpl) CreateLeaveRequest(ctx context.Context, req leave.Cre
 	return requestResponse, nil
 }
 
+// uploadAttachment checks the size and type of a leave attachment and uploads it
+func (l *LeaveServiceImpl) uploadAttachment(ctx context.Context, employeeID string, file multipart.File, fileHeader *multipart.FileHeader) (string, error) {
+	if fileHeader.Size > 5<<20 {
+		return "", leave.ErrFileSizeExceeds
+	}
+
+	allowedExts := []string{".pdf", ".jpg", ".jpeg", ".png"}
+	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+
+	isValidExt := false
+	for _, allowed := range allowedExts {
+		if ext == allowed {
+			isValidExt = true
+			break
+		}
+	}
+
+	if !isValidExt {
+		return "", leave.ErrFileTypeNotAllowed
+	}
+
+	attachmentURL, err := l.fileService.UploadLeaveAttachment(ctx, employeeID, file, fileHeader.Filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload leave attachment: %w", err)
+	}
+	return attachmentURL, nil
+}
+
 // CreateLeaveType implements leave.LeaveService.
 func (l *LeaveServiceImpl) CreateLeaveType(ctx context.Context, req leave.CreateLeaveTypeRequest) (leave.LeaveType, error) {
 	_, claims, err := jwtauth.FromContext(ctx)
@@ -607,6 +835,7 @@ func (l *LeaveServiceImpl) CreateLeaveType(ctx context.Context, req leave.Create
 		AttachmentRequiredAfterDays: req.AttachmentRequiredAfterDays,
 		HasQuota:                    req.HasQuota,
 		AccrualMethod:               req.AccrualMethod,
+		AllowNegativeBalance:        req.AllowNegativeBalance,
 		DeductionType:               req.DeductionType,
 		AllowHalfDay:                req.AllowHalfDay,
 		MaxDaysPerRequest:           req.MaxDaysPerRequest,
@@ -629,7 +858,10 @@ func (l *LeaveServiceImpl) CreateLeaveType(ctx context.Context, req leave.Create
 		// Use context.WithoutCancel to prevent cancellation when HTTP request ends
 		bgCtx := context.WithoutCancel(ctx)
 		go func() {
-			err := l.quotaService.AllocateTypeQuota(bgCtx, leaveType, companyID, time.Now().Year())
+			year, err := l.quotaService.CurrentLeaveYear(bgCtx, companyID)
+			if err == nil {
+				err = l.quotaService.AllocateTypeQuota(bgCtx, leaveType, companyID, year)
+			}
 			if err != nil {
 				fmt.Printf("failed to allocate type quota for leave type %s: %v\n", leaveType.ID, err)
 			} else {
@@ -735,21 +967,7 @@ func (l *LeaveServiceImpl) ListLeaveQuota(ctx context.Context, companyID string)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get leave type by ID: %w", err)
 		}
-		leaveQuotaResponse = append(leaveQuotaResponse, leave.LeaveQuotaResponse{
-			ID:              leaveQuota.ID,
-			EmployeeID:      leaveQuota.EmployeeID,
-			LeaveTypeID:     leaveQuota.LeaveTypeID,
-			LeaveTypeName:   leaveType.Name,
-			Year:            leaveQuota.Year,
-			OpeningBalance:  *leaveQuota.OpeningBalance,
-			EarnedQuota:     *leaveQuota.EarnedQuota,
-			RolloverQuota:   *leaveQuota.RolloverQuota,
-			AdjustmentQuota: *leaveQuota.AdjustmentQuota,
-			UsedQuota:       *leaveQuota.UsedQuota,
-			PendingQuota:    *leaveQuota.PendingQuota,
-			AvailableQuota:  *leaveQuota.AvailableQuota,
-			EmployeeName:    leaveQuota.EmployeeName,
-		})
+		leaveQuotaResponse = append(leaveQuotaResponse, leaveQuota.ToResponse(leaveType.Name))
 	}
 
 	return leaveQuotaResponse, nil
@@ -781,6 +999,7 @@ func (l *LeaveServiceImpl) ListLeaveType(ctx context.Context, companyID string)
 			RequiresApproval:     leaveType.RequiresApproval,
 			HasQuota:             leaveType.HasQuota,
 			AccrualMethod:        leaveType.AccrualMethod,
+			AllowNegativeBalance: leaveType.AllowNegativeBalance,
 			QuotaCalculationType: leaveType.QuotaCalculationType,
 			QuotaRules:           leaveType.QuotaRules,
 		})
@@ -803,9 +1022,13 @@ func (l *LeaveServiceImpl) RejectLeaveRequest(ctx context.Context, req leave.Rej
 
 	companyID, _ := claims["company_id"].(string)
 
+	if err := l.ensureRequestInBranchScope(ctx, req.RequestID); err != nil {
+		return err
+	}
+
 	var request leave.LeaveRequest
 	err = postgresql.WithTransaction(ctx, l.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+		txCtx := postgresql.ContextWithTx(ctx, tx)
 
 		var txErr error
 		request, txErr = l.requestService.Reject(ctx, req.RequestID, *req.Reason, approverID)
@@ -814,20 +1037,18 @@ func (l *LeaveServiceImpl) RejectLeaveRequest(ctx context.Context, req leave.Rej
 		}
 
 		// Release reserved quota
-		txErr = l.quotaService.ReleaseQuota(txCtx, request.EmployeeID, request.LeaveTypeID, request.WorkingDays)
+		txErr = l.quotaService.ReleaseQuota(txCtx, request)
 		if txErr != nil {
 			return txErr
 		}
-		return nil
+
+		// Notify employee that their leave request was rejected
+		return l.enqueueLeaveRejectedNotification(txCtx, request, companyID, approverID, *req.Reason)
 	})
 	if err != nil {
 		return err
 	}
 
-	// Notify employee that their leave request was rejected
-	// Use context.WithoutCancel to prevent cancellation when HTTP request ends
-	go l.notifyEmployeeOnLeaveRejected(context.WithoutCancel(ctx), request, companyID, approverID, *req.Reason)
-
 	return nil
 }
 
@@ -847,8 +1068,55 @@ func (l *LeaveServiceImpl) UpdateLeaveType(ctx context.Context, req leave.Update
 	return nil
 }
 
-// notifyManagersOnLeaveRequest sends notifications to all managers when a leave request is submitted
+// ensureRequestInBranchScope rejects access to a leave request outside the caller's branch scope
+func (l *LeaveServiceImpl) ensureRequestInBranchScope(ctx context.Context, requestID string) error {
+	if _, scoped := scope.BranchIDFromContext(ctx); !scoped {
+		return nil
+	}
+
+	request, err := l.LeaveRequestRepository.GetByID(ctx, requestID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return leave.ErrLeaveRequestNotFound
+		}
+		return fmt.Errorf("failed to get leave request: %w", err)
+	}
+
+	return l.ensureEmployeeInBranchScope(ctx, request.EmployeeID)
+}
+
+// ensureEmployeeInBranchScope rejects access to an employee outside the caller's branch scope
+func (l *LeaveServiceImpl) ensureEmployeeInBranchScope(ctx context.Context, employeeID string) error {
+	branchID, scoped := scope.BranchIDFromContext(ctx)
+	if !scoped {
+		return nil
+	}
+
+	emp, err := l.EmployeeRepository.GetByID(ctx, employeeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return employee.ErrEmployeeNotFound
+		}
+		return fmt.Errorf("failed to get employee: %w", err)
+	}
+
+	if emp.BranchID != branchID {
+		return leave.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+// notifyManagersOnLeaveRequest notifies the managers of the employee's branch when a leave request is
+// submitted, or every manager and owner when the branch has none. Requests from the same employee
+// within managerNotifyWindow are coalesced into one unread notification per manager.
 func (l *LeaveServiceImpl) notifyManagersOnLeaveRequest(ctx context.Context, req leave.LeaveRequestResponse) {
+	l.notifyManagers(ctx, req, "New Leave Request", "submitted")
+}
+
+// notifyManagers sends a leave request notification to the employee's managers; action is the
+// verb in the message, e.g. "submitted" or "reopened"
+func (l *LeaveServiceImpl) notifyManagers(ctx context.Context, req leave.LeaveRequestResponse, title, action string) {
 	if l.notificationService == nil {
 		return
 	}
@@ -859,8 +1127,7 @@ func (l *LeaveServiceImpl) notifyManagersOnLeaveRequest(ctx context.Context, req
 		return
 	}
 
-	// Get managers of the company
-	managers, err := l.EmployeeRepository.GetManagersByCompanyID(ctx, emp.CompanyID)
+	managers, err := l.leaveRequestRecipients(ctx, emp)
 	if err != nil {
 		return
 	}
@@ -875,42 +1142,52 @@ func (l *LeaveServiceImpl) notifyManagersOnLeaveRequest(ctx context.Context, req
 			RecipientID: *manager.UserID,
 			SenderID:    emp.UserID,
 			Type:        notification.TypeLeaveRequest,
-			Title:       "New Leave Request",
-			Message:     fmt.Sprintf("%s submitted a %s request from %s to %s", req.EmployeeName, req.LeaveTypeName, req.StartDate.Format("02 Jan 2006"), req.EndDate.Format("02 Jan 2006")),
+			Title:       title,
+			Message:     fmt.Sprintf("%s %s a %s request from %s to %s", req.EmployeeName, action, req.LeaveTypeName, req.StartDate.Format("02 Jan 2006"), req.EndDate.Format("02 Jan 2006")),
 			Data: map[string]interface{}{
 				"employee_id":      req.EmployeeID,
+				"employee_name":    req.EmployeeName,
 				"leave_request_id": req.ID,
 				"leave_type":       req.LeaveTypeName,
 				"start_date":       req.StartDate.Format("2006-01-02"),
 				"end_date":         req.EndDate.Format("2006-01-02"),
 				"total_days":       req.TotalDays,
 			},
+			DedupKey:    "leave_request:" + req.EmployeeID,
+			DedupWindow: l.managerNotifyWindow,
 		})
 	}
 }
 
-// notifyEmployeeOnLeaveApproved sends notification to employee when leave is approved
-func (l *LeaveServiceImpl) notifyEmployeeOnLeaveApproved(ctx context.Context, req leave.LeaveRequest, companyID, approverID string) {
+// leaveRequestRecipients returns the managers of the employee's branch, falling back to
+// every manager and owner of the company when no manager is assigned to that branch
+func (l *LeaveServiceImpl) leaveRequestRecipients(ctx context.Context, emp employee.Employee) ([]employee.Employee, error) {
+	if emp.BranchID != "" {
+		managers, err := l.EmployeeRepository.GetManagersByBranchID(ctx, emp.CompanyID, emp.BranchID)
+		if err != nil {
+			return nil, err
+		}
+		if len(managers) > 0 {
+			return managers, nil
+		}
+	}
 
+	return l.EmployeeRepository.GetManagersByCompanyID(ctx, emp.CompanyID)
+}
+
+// enqueueLeaveApprovedNotification records the employee's approval notification in the outbox.
+// ctx must carry the approval transaction so the notification commits or rolls back with it.
+func (l *LeaveServiceImpl) enqueueLeaveApprovedNotification(ctx context.Context, req leave.LeaveRequest, companyID, approverID string) error {
 	if l.notificationService == nil {
-		return
+		return nil
 	}
 
-	// Get employee user ID
-	emp, err := l.EmployeeRepository.GetByID(ctx, req.EmployeeID)
-
+	emp, leaveType, err := l.leaveNotificationSubject(ctx, req)
 	if err != nil || emp.UserID == nil {
-		fmt.Printf("failed to get employee user ID for notification: %v\n", err)
-		return
-	}
-	// Get leave type name
-	leaveType, err := l.LeaveTypeRepository.GetByID(ctx, req.LeaveTypeID)
-	if err != nil {
-		fmt.Printf("failed to get leave type for notification: %v\n", err)
-		return
+		return err
 	}
 
-	_ = l.notificationService.QueueNotification(ctx, notification.CreateNotificationRequest{
+	return l.notificationService.Enqueue(ctx, notification.CreateNotificationRequest{
 		CompanyID:   companyID,
 		RecipientID: *emp.UserID,
 		SenderID:    &approverID,
@@ -918,6 +1195,7 @@ func (l *LeaveServiceImpl) notifyEmployeeOnLeaveApproved(ctx context.Context, re
 		Title:       "Leave Request Approved",
 		Message:     fmt.Sprintf("Your %s request from %s to %s has been approved", leaveType.Name, req.StartDate.Format("02 Jan 2006"), req.EndDate.Format("02 Jan 2006")),
 		Data: map[string]interface{}{
+			"employee_name":    emp.FullName,
 			"leave_request_id": req.ID,
 			"leave_type":       leaveType.Name,
 			"start_date":       req.StartDate.Format("2006-01-02"),
@@ -926,25 +1204,19 @@ func (l *LeaveServiceImpl) notifyEmployeeOnLeaveApproved(ctx context.Context, re
 	})
 }
 
-// notifyEmployeeOnLeaveRejected sends notification to employee when leave is rejected
-func (l *LeaveServiceImpl) notifyEmployeeOnLeaveRejected(ctx context.Context, req leave.LeaveRequest, companyID, approverID, reason string) {
+// enqueueLeaveRejectedNotification records the employee's rejection notification in the outbox.
+// ctx must carry the rejection transaction so the notification commits or rolls back with it.
+func (l *LeaveServiceImpl) enqueueLeaveRejectedNotification(ctx context.Context, req leave.LeaveRequest, companyID, approverID, reason string) error {
 	if l.notificationService == nil {
-		return
+		return nil
 	}
 
-	// Get employee user ID
-	emp, err := l.EmployeeRepository.GetByID(ctx, req.EmployeeID)
+	emp, leaveType, err := l.leaveNotificationSubject(ctx, req)
 	if err != nil || emp.UserID == nil {
-		return
-	}
-
-	// Get leave type name
-	leaveType, err := l.LeaveTypeRepository.GetByID(ctx, req.LeaveTypeID)
-	if err != nil {
-		return
+		return err
 	}
 
-	_ = l.notificationService.QueueNotification(ctx, notification.CreateNotificationRequest{
+	return l.notificationService.Enqueue(ctx, notification.CreateNotificationRequest{
 		CompanyID:   companyID,
 		RecipientID: *emp.UserID,
 		SenderID:    &approverID,
@@ -952,6 +1224,7 @@ func (l *LeaveServiceImpl) notifyEmployeeOnLeaveRejected(ctx context.Context, re
 		Title:       "Leave Request Rejected",
 		Message:     fmt.Sprintf("Your %s request from %s to %s has been rejected. Reason: %s", leaveType.Name, req.StartDate.Format("02 Jan 2006"), req.EndDate.Format("02 Jan 2006"), reason),
 		Data: map[string]interface{}{
+			"employee_name":    emp.FullName,
 			"leave_request_id": req.ID,
 			"leave_type":       leaveType.Name,
 			"start_date":       req.StartDate.Format("2006-01-02"),
@@ -961,6 +1234,21 @@ func (l *LeaveServiceImpl) notifyEmployeeOnLeaveRejected(ctx context.Context, re
 	})
 }
 
+// leaveNotificationSubject loads the employee and leave type a decision notification refers to
+func (l *LeaveServiceImpl) leaveNotificationSubject(ctx context.Context, req leave.LeaveRequest) (employee.Employee, leave.LeaveType, error) {
+	emp, err := l.EmployeeRepository.GetByID(ctx, req.EmployeeID)
+	if err != nil {
+		return employee.Employee{}, leave.LeaveType{}, fmt.Errorf("failed to get employee for notification: %w", err)
+	}
+
+	leaveType, err := l.LeaveTypeRepository.GetByID(ctx, req.LeaveTypeID)
+	if err != nil {
+		return employee.Employee{}, leave.LeaveType{}, fmt.Errorf("failed to get leave type for notification: %w", err)
+	}
+
+	return emp, leaveType, nil
+}
+
 func NewLeaveService(
 	db *database.DB,
 	leaveTypeRepo leave.LeaveTypeRepository,
@@ -972,7 +1260,12 @@ func NewLeaveService(
 	requestService *RequestService,
 	fileService file.FileService,
 	notificationService notification.Service,
+	clk clock.Clock,
+	managerNotifyWindow time.Duration,
+	reopenWindow time.Duration,
 ) leave.LeaveService {
+	// Without a notification service every notify call is skipped rather than failing
+	slog.Info("leave notifications", "enabled", notificationService != nil)
 	return &LeaveServiceImpl{
 		db:                     db,
 		LeaveTypeRepository:    leaveTypeRepo,
@@ -984,5 +1277,8 @@ func NewLeaveService(
 		requestService:         requestService,
 		fileService:            fileService,
 		notificationService:    notificationService,
+		clock:                  clk,
+		managerNotifyWindow:    managerNotifyWindow,
+		reopenWindow:           reopenWindow,
 	}
 }
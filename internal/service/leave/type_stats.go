@@ -0,0 +1,66 @@
+package leave
+
+import (
+	"context"
+	"math"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/scope"
+)
+
+// GetLeaveTypeStats implements leave.LeaveService.
+// Requests are counted in the leave year their start date falls in. Branch-scoped managers
+// always get their own branch, whatever branch the filter asks for.
+func (l *LeaveServiceImpl) GetLeaveTypeStats(ctx context.Context, companyID string, filter leave.LeaveTypeStatsFilter) (leave.LeaveTypeStatsResponse, error) {
+	if err := filter.Validate(); err != nil {
+		return leave.LeaveTypeStatsResponse{}, err
+	}
+
+	if branchID := scope.BranchFilter(ctx); branchID != nil {
+		filter.BranchID = branchID
+	}
+
+	startMonth, err := l.quotaService.LeaveYearStartMonth(ctx, companyID)
+	if err != nil {
+		return leave.LeaveTypeStatsResponse{}, err
+	}
+	year := leave.LeaveYear(l.clock.Now(), startMonth)
+	if filter.Year != nil {
+		year = *filter.Year
+	}
+
+	loc := l.requestService.companyLocation(ctx, companyID)
+	periodStart := leave.LeaveYearStart(year, startMonth, loc)
+	periodEnd := leave.LeaveYearStart(year+1, startMonth, loc)
+
+	stats, err := l.LeaveRequestRepository.GetTypeStats(ctx, companyID, periodStart, periodEnd, filter.BranchID, filter.PositionID)
+	if err != nil {
+		return leave.LeaveTypeStatsResponse{}, err
+	}
+
+	items := make([]leave.LeaveTypeStatItem, 0, len(stats))
+	for _, s := range stats {
+		rejectionRate := 0.0
+		if decided := s.ApprovedRequests + s.RejectedRequests; decided > 0 {
+			rejectionRate = math.Round(float64(s.RejectedRequests)/float64(decided)*100*100) / 100
+		}
+
+		items = append(items, leave.LeaveTypeStatItem{
+			LeaveTypeID:      s.LeaveTypeID,
+			Label:            s.LeaveTypeName,
+			TotalRequests:    s.TotalRequests,
+			ApprovedRequests: s.ApprovedRequests,
+			RejectedRequests: s.RejectedRequests,
+			ApprovedDays:     s.ApprovedDays,
+			RejectionRate:    rejectionRate,
+			AverageDays:      math.Round(s.AverageDays*100) / 100,
+		})
+	}
+
+	return leave.LeaveTypeStatsResponse{
+		Year:        year,
+		PeriodStart: periodStart.Format("2006-01-02"),
+		PeriodEnd:   periodEnd.AddDate(0, 0, -1).Format("2006-01-02"),
+		Items:       items,
+	}, nil
+}
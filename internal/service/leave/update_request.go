@@ -0,0 +1,180 @@
+package leave
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/utils"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
+	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// UpdateMyRequest implements leave.LeaveService.
+// The caller's own request can be edited until it is processed. Days are recalculated from the
+// new dates and the pending quota is moved by the difference in the same transaction.
+func (l *LeaveServiceImpl) UpdateMyRequest(ctx context.Context, requestID string, req leave.UpdateMyLeaveRequestRequest) (leave.LeaveRequestResponse, error) {
+	if err := req.Validate(); err != nil {
+		return leave.LeaveRequestResponse{}, err
+	}
+
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return leave.LeaveRequestResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+	employeeID, _ := claims["employee_id"].(string)
+	if employeeID == "" {
+		return leave.LeaveRequestResponse{}, leave.ErrUnauthorizedAccess
+	}
+
+	var requestResponse leave.LeaveRequestResponse
+	err = postgresql.WithTransaction(ctx, l.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		// The lock keeps an approval or cancellation from landing between the checks below and the update
+		request, err := l.LeaveRequestRepository.GetByIDForUpdate(txCtx, requestID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, leave.ErrLeaveRequestNotFound) {
+				return leave.ErrLeaveRequestNotFound
+			}
+			return fmt.Errorf("failed to get leave request: %w", err)
+		}
+		if request.EmployeeID != employeeID {
+			return leave.ErrUnauthorizedAccess
+		}
+		if request.Status != leave.LeaveRequestStatusWaitingApproval {
+			return leave.ErrLeaveAlreadyProcessed
+		}
+		if *req.Version != request.Version {
+			return leave.ErrConcurrentModification
+		}
+
+		emp, err := l.EmployeeRepository.GetByID(txCtx, request.EmployeeID)
+		if err != nil {
+			return fmt.Errorf("failed to get employee: %w", err)
+		}
+		leaveType, err := l.LeaveTypeRepository.GetByID(txCtx, request.LeaveTypeID)
+		if err != nil {
+			return fmt.Errorf("failed to get leave type by ID: %w", err)
+		}
+
+		loc := l.requestService.companyLocation(txCtx, emp.CompanyID)
+		today := utils.StartOfDay(l.clock.Now(), loc)
+
+		startDate, endDate := request.StartDate, request.EndDate
+		if req.StartDate != nil {
+			if startDate, err = utils.ParseDateInLocation(*req.StartDate, loc); err != nil {
+				return validator.ValidationErrors{{Field: "start_date", Message: "start date format is invalid (use YYYY-MM-DD)"}}
+			}
+		}
+		if req.EndDate != nil {
+			if endDate, err = utils.ParseDateInLocation(*req.EndDate, loc); err != nil {
+				return validator.ValidationErrors{{Field: "end_date", Message: "end date format is invalid (use YYYY-MM-DD)"}}
+			}
+		}
+		if endDate.Before(startDate) {
+			return validator.ValidationErrors{{Field: "end_date", Message: "end date must not be before start date"}}
+		}
+		durationType := string(request.DurationType)
+		if req.DurationType != nil {
+			durationType = *req.DurationType
+		}
+
+		// The row is locked and its version checked above; the status guard still refuses to edit a
+		// request that is no longer waiting for approval
+		pending := string(leave.LeaveRequestStatusWaitingApproval)
+		update := leave.UpdateLeaveRequestRequest{ID: request.ID, Reason: req.Reason, ExpectedVersion: request.Version, ExpectedStatus: &pending}
+
+		datesChanged := !startDate.Equal(request.StartDate) || !endDate.Equal(request.EndDate) || durationType != string(request.DurationType)
+		if datesChanged {
+			if err := l.requestService.validateDates(txCtx, leaveType, startDate, endDate, today); err != nil {
+				return fmt.Errorf("date validation failed: %w", err)
+			}
+
+			hasOverlap, err := l.LeaveRequestRepository.CheckOverlappingExcluding(txCtx, request.EmployeeID, startDate, endDate, request.ID)
+			if err != nil {
+				return fmt.Errorf("failed to check overlapping leave requests: %w", err)
+			}
+			if hasOverlap {
+				return leave.ErrOverlappingLeave
+			}
+
+			workingDays, err := l.requestService.Calculate(txCtx, emp.CompanyID, startDate, endDate, durationType)
+			if err != nil {
+				return fmt.Errorf("failed to calculate working days: %w", err)
+			}
+			if delta := workingDays - request.WorkingDays; delta != 0 {
+				if err := l.quotaService.ResizeReservation(txCtx, request, delta); err != nil {
+					return err
+				}
+			}
+
+			startStr, endStr := startDate.Format("2006-01-02"), endDate.Format("2006-01-02")
+			totalDays := l.requestService.calculateTotalDays(startDate, endDate, durationType)
+			isBackdate := startDate.Before(today)
+			update.StartDate = &startStr
+			update.EndDate = &endStr
+			update.DurationType = &durationType
+			update.TotalDays = &totalDays
+			update.WorkingDays = &workingDays
+			update.IsBackdate = &isBackdate
+
+			request.StartDate, request.EndDate = startDate, endDate
+			request.DurationType = leave.LeaveDurationEnum(durationType)
+			request.TotalDays, request.WorkingDays = totalDays, workingDays
+		}
+
+		if req.File != nil && req.FileHeader != nil {
+			attachmentURL, err := l.uploadAttachment(ctx, request.EmployeeID, req.File, req.FileHeader)
+			if err != nil {
+				return err
+			}
+			update.AttachmentURL = &attachmentURL
+			request.AttachmentURL = &attachmentURL
+		}
+		if req.Reason != nil {
+			request.Reason = *req.Reason
+		}
+
+		if update.Reason != nil || update.StartDate != nil || update.AttachmentURL != nil {
+			if err := l.LeaveRequestRepository.Update(txCtx, update); err != nil {
+				if errors.Is(err, leave.ErrLeaveAlreadyProcessed) || errors.Is(err, leave.ErrConcurrentModification) {
+					return err
+				}
+				return fmt.Errorf("failed to update leave request: %w", err)
+			}
+			request.Version++
+		}
+
+		requestResponse = leave.LeaveRequestResponse{
+			ID:            request.ID,
+			EmployeeID:    request.EmployeeID,
+			EmployeeName:  emp.FullName,
+			LeaveTypeID:   request.LeaveTypeID,
+			LeaveTypeName: leaveType.Name,
+			StartDate:     request.StartDate,
+			EndDate:       request.EndDate,
+			DurationType:  string(request.DurationType),
+			TotalDays:     request.TotalDays,
+			WorkingDays:   request.WorkingDays,
+			Reason:        request.Reason,
+			AttachmentURL: request.AttachmentURL,
+			Status:        string(request.Status),
+			SubmittedAt:   request.SubmittedAt,
+			RecurrenceID:  request.RecurrenceID,
+			Version:       request.Version,
+		}
+		return nil
+	})
+	if err != nil {
+		return leave.LeaveRequestResponse{}, err
+	}
+
+	// Use context.WithoutCancel to prevent cancellation when HTTP request ends
+	go l.notifyManagers(context.WithoutCancel(ctx), requestResponse, "Leave Request Updated", "updated")
+
+	return requestResponse, nil
+}
@@ -0,0 +1,130 @@
+package leave
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/leave"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database/dbtest"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/testutil"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
+)
+
+func TestUpdateMyRequest(t *testing.T) {
+	reason := "moving house, need the extra day"
+	badDate := "2026-02-30"
+	loaded, stale := 2, 1
+
+	tests := []struct {
+		name      string
+		status    leave.LeaveRequestStatus
+		req       leave.UpdateMyLeaveRequestRequest
+		updateErr error // what the guarded UPDATE returns
+		wantErr   error
+		wantField string // field of the expected validation error
+		wantEnd   string // how the transaction ended, empty when none was started
+	}{
+		{
+			name:    "pending request",
+			status:  leave.LeaveRequestStatusWaitingApproval,
+			req:     leave.UpdateMyLeaveRequestRequest{Reason: &reason, Version: &loaded},
+			wantEnd: "commit",
+		},
+		{
+			name:    "already approved",
+			status:  leave.LeaveRequestStatusApproved,
+			req:     leave.UpdateMyLeaveRequestRequest{Reason: &reason, Version: &loaded},
+			wantErr: leave.ErrLeaveAlreadyProcessed,
+			wantEnd: "rollback",
+		},
+		{
+			name:      "processed before the update landed",
+			status:    leave.LeaveRequestStatusWaitingApproval,
+			req:       leave.UpdateMyLeaveRequestRequest{Reason: &reason, Version: &loaded},
+			updateErr: leave.ErrLeaveAlreadyProcessed,
+			wantErr:   leave.ErrLeaveAlreadyProcessed,
+			wantEnd:   "rollback",
+		},
+		{
+			name:      "unparseable start date",
+			status:    leave.LeaveRequestStatusWaitingApproval,
+			req:       leave.UpdateMyLeaveRequestRequest{StartDate: &badDate, Version: &loaded},
+			wantField: "start_date",
+		},
+		{
+			name:      "no version",
+			status:    leave.LeaveRequestStatusWaitingApproval,
+			req:       leave.UpdateMyLeaveRequestRequest{Reason: &reason},
+			wantField: "version",
+		},
+		{
+			name:    "stale version",
+			status:  leave.LeaveRequestStatusWaitingApproval,
+			req:     leave.UpdateMyLeaveRequestRequest{Reason: &reason, Version: &stale},
+			wantErr: leave.ErrConcurrentModification,
+			wantEnd: "rollback",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, server := dbtest.NewDB(t, nil)
+			start := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+			requests := &fakeLeaveRequestRepo{
+				requests: map[string]leave.LeaveRequest{"req-1": {
+					ID: "req-1", EmployeeID: "emp-1", LeaveTypeID: "annual", StartDate: start, EndDate: start,
+					DurationType: "full_day", TotalDays: 1, WorkingDays: 1, Status: tt.status, Version: 2,
+				}},
+				updateErr: tt.updateErr,
+			}
+			s := &LeaveServiceImpl{
+				db:                     db,
+				LeaveRequestRepository: requests,
+				EmployeeRepository:     &fakeEmployeeRepo{employees: []employee.Employee{{ID: "emp-1", CompanyID: "company-1"}}},
+				LeaveTypeRepository:    &fakeLeaveTypeRepo{types: map[string]leave.LeaveType{"annual": {ID: "annual", Name: "Annual Leave"}}},
+				requestService:         &RequestService{companyRepo: &fakeCompanyRepo{timezone: "Asia/Jakarta"}},
+				clock:                  clock.NewFake(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)),
+			}
+			ctx := testutil.ClaimsContext(t, map[string]any{"employee_id": "emp-1", "company_id": "company-1"})
+
+			resp, err := s.UpdateMyRequest(ctx, "req-1", tt.req)
+
+			var validationErrs validator.ValidationErrors
+			switch {
+			case tt.wantField != "":
+				if !errors.As(err, &validationErrs) || validationErrs[0].Field != tt.wantField {
+					t.Fatalf("UpdateMyRequest() error = %v, want a validation error on %s", err, tt.wantField)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Fatalf("UpdateMyRequest() error = %v, want %v", err, tt.wantErr)
+			case err == nil && (resp.Reason != reason || resp.Version != 3):
+				t.Errorf("UpdateMyRequest() = %+v", resp)
+			}
+
+			statements := server.Statements()
+			if tt.wantEnd == "" {
+				// Rejected by validation before any row is read
+				if len(statements) != 0 || len(requests.locked) != 0 {
+					t.Errorf("statements %v, locked %v, want nothing sent to the database", statements, requests.locked)
+				}
+				return
+			}
+
+			if len(requests.locked) != 1 {
+				t.Errorf("locked %v, want the request locked before it is checked", requests.locked)
+			}
+			for _, update := range requests.updates {
+				if update.ExpectedStatus == nil || *update.ExpectedStatus != "waiting_approval" || update.ExpectedVersion != loaded {
+					t.Errorf("update %+v is not guarded by the pending status and loaded version", update)
+				}
+			}
+			if end := strings.ToLower(statements[len(statements)-1]); end != tt.wantEnd {
+				t.Errorf("transaction ended with %q, want %q", end, tt.wantEnd)
+			}
+		})
+	}
+}
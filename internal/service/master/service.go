@@ -8,6 +8,8 @@ import (
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/master/branch"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/master/grade"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/master/position"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -19,35 +21,44 @@ type MasterService interface {
 	GetBranch(ctx context.Context, id string) (branch.BranchResponse, error)
 	ListBranches(ctx context.Context, companyID string) ([]branch.BranchResponse, error)
 	UpdateBranch(ctx context.Context, req branch.UpdateBranchRequest) error
-	DeleteBranch(ctx context.Context, id string) error
+	// DeleteBranch deletes a branch; employees and managers still assigned to it block the deletion
+	// unless reassignTo names another branch to move them to first. A branch in job history is kept.
+	DeleteBranch(ctx context.Context, id string, reassignTo string) error
 
 	// Grade operations
 	CreateGrade(ctx context.Context, companyID string, req grade.CreateGradeRequest) (grade.GradeResponse, error)
 	GetGrade(ctx context.Context, id string) (grade.GradeResponse, error)
 	ListGrades(ctx context.Context, companyID string) ([]grade.GradeResponse, error)
 	UpdateGrade(ctx context.Context, req grade.UpdateGradeRequest) error
-	DeleteGrade(ctx context.Context, id string) error
+	// DeleteGrade deletes a grade; employees still assigned to it block the deletion
+	// unless reassignTo names another grade to move them to first
+	DeleteGrade(ctx context.Context, id string, reassignTo string) error
 
 	// Position operations
 	CreatePosition(ctx context.Context, companyID string, req position.CreatePositionRequest) (position.PositionResponse, error)
 	GetPosition(ctx context.Context, id string) (position.PositionResponse, error)
 	ListPositions(ctx context.Context, companyID string) ([]position.PositionResponse, error)
 	UpdatePosition(ctx context.Context, req position.UpdatePositionRequest) error
-	DeletePosition(ctx context.Context, id string) error
+	// DeletePosition deletes a position; employees still assigned to it block the deletion
+	// unless reassignTo names another position to move them to first
+	DeletePosition(ctx context.Context, id string, reassignTo string) error
 }
 
 type masterServiceImpl struct {
+	db           *database.DB
 	branchRepo   branch.BranchRepository
 	gradeRepo    grade.GradeRepository
 	positionRepo position.PositionRepository
 }
 
 func NewMasterService(
+	db *database.DB,
 	branchRepo branch.BranchRepository,
 	gradeRepo grade.GradeRepository,
 	positionRepo position.PositionRepository,
 ) MasterService {
 	return &masterServiceImpl{
+		db:           db,
 		branchRepo:   branchRepo,
 		gradeRepo:    gradeRepo,
 		positionRepo: positionRepo,
@@ -188,7 +199,7 @@ func (s *masterServiceImpl) UpdateBranch(ctx context.Context, req branch.UpdateB
 	return nil
 }
 
-func (s *masterServiceImpl) DeleteBranch(ctx context.Context, id string) error {
+func (s *masterServiceImpl) DeleteBranch(ctx context.Context, id string, reassignTo string) error {
 	// Extract company_id from JWT
 	_, claims, err := jwtauth.FromContext(ctx)
 	if err != nil {
@@ -200,15 +211,24 @@ func (s *masterServiceImpl) DeleteBranch(ctx context.Context, id string) error {
 		return fmt.Errorf("company_id not found in token")
 	}
 
-	err = s.branchRepo.Delete(ctx, id, companyID)
-	if err != nil {
-		// Check if branch not found
-		if errors.Is(err, pgx.ErrNoRows) {
-			return branch.ErrBranchNotFound
-		}
-		return err
-	}
-	return nil
+	return s.deleteWithReassign(ctx, id, reassignTo, referenceGuard{
+		exists: func(ctx context.Context, id string) error {
+			_, err := s.branchRepo.GetByID(ctx, id, companyID)
+			return err
+		},
+		count: func(ctx context.Context, id string) (int, error) {
+			return s.branchRepo.CountReferences(ctx, id, companyID)
+		},
+		reassign: func(ctx context.Context, fromID, toID string) error {
+			return s.branchRepo.ReassignReferences(ctx, fromID, toID, companyID)
+		},
+		remove: func(ctx context.Context, id string) error {
+			return s.branchRepo.Delete(ctx, id, companyID)
+		},
+		errNotFound:      branch.ErrBranchNotFound,
+		errInUse:         branch.ErrBranchInUse,
+		errInvalidTarget: branch.ErrInvalidReassignTarget,
+	})
 }
 
 // ==================== GRADE OPERATIONS ====================
@@ -223,6 +243,8 @@ func (s *masterServiceImpl) CreateGrade(ctx context.Context, companyID string, r
 	entity := grade.Grade{
 		CompanyID: companyID,
 		Name:      req.Name,
+		MinSalary: req.MinSalary,
+		MaxSalary: req.MaxSalary,
 	}
 
 	// Save to database
@@ -241,8 +263,10 @@ func (s *masterServiceImpl) CreateGrade(ctx context.Context, companyID string, r
 
 	// Map to response
 	return grade.GradeResponse{
-		ID:   created.ID,
-		Name: created.Name,
+		ID:        created.ID,
+		Name:      created.Name,
+		MinSalary: created.MinSalary,
+		MaxSalary: created.MaxSalary,
 	}, nil
 }
 
@@ -268,8 +292,10 @@ func (s *masterServiceImpl) GetGrade(ctx context.Context, id string) (grade.Grad
 	}
 
 	return grade.GradeResponse{
-		ID:   entity.ID,
-		Name: entity.Name,
+		ID:        entity.ID,
+		Name:      entity.Name,
+		MinSalary: entity.MinSalary,
+		MaxSalary: entity.MaxSalary,
 	}, nil
 }
 
@@ -287,8 +313,10 @@ func (s *masterServiceImpl) ListGrades(ctx context.Context, companyID string) ([
 	var responses []grade.GradeResponse
 	for _, g := range grades {
 		responses = append(responses, grade.GradeResponse{
-			ID:   g.ID,
-			Name: g.Name,
+			ID:        g.ID,
+			Name:      g.Name,
+			MinSalary: g.MinSalary,
+			MaxSalary: g.MaxSalary,
 		})
 	}
 
@@ -335,7 +363,7 @@ func (s *masterServiceImpl) UpdateGrade(ctx context.Context, req grade.UpdateGra
 	return nil
 }
 
-func (s *masterServiceImpl) DeleteGrade(ctx context.Context, id string) error {
+func (s *masterServiceImpl) DeleteGrade(ctx context.Context, id string, reassignTo string) error {
 	// Extract company_id from JWT
 	_, claims, err := jwtauth.FromContext(ctx)
 	if err != nil {
@@ -347,15 +375,24 @@ func (s *masterServiceImpl) DeleteGrade(ctx context.Context, id string) error {
 		return fmt.Errorf("company_id not found in token")
 	}
 
-	err = s.gradeRepo.Delete(ctx, id, companyID)
-	if err != nil {
-		// Check if grade not found
-		if errors.Is(err, pgx.ErrNoRows) {
-			return grade.ErrGradeNotFound
-		}
-		return err
-	}
-	return nil
+	return s.deleteWithReassign(ctx, id, reassignTo, referenceGuard{
+		exists: func(ctx context.Context, id string) error {
+			_, err := s.gradeRepo.GetByID(ctx, id, companyID)
+			return err
+		},
+		count: func(ctx context.Context, id string) (int, error) {
+			return s.gradeRepo.CountReferences(ctx, id, companyID)
+		},
+		reassign: func(ctx context.Context, fromID, toID string) error {
+			return s.gradeRepo.ReassignReferences(ctx, fromID, toID, companyID)
+		},
+		remove: func(ctx context.Context, id string) error {
+			return s.gradeRepo.Delete(ctx, id, companyID)
+		},
+		errNotFound:      grade.ErrGradeNotFound,
+		errInUse:         grade.ErrGradeInUse,
+		errInvalidTarget: grade.ErrInvalidReassignTarget,
+	})
 }
 
 // ==================== POSITION OPERATIONS ====================
@@ -485,7 +522,7 @@ func (s *masterServiceImpl) UpdatePosition(ctx context.Context, req position.Upd
 	return nil
 }
 
-func (s *masterServiceImpl) DeletePosition(ctx context.Context, id string) error {
+func (s *masterServiceImpl) DeletePosition(ctx context.Context, id string, reassignTo string) error {
 	// Extract company_id from JWT
 	_, claims, err := jwtauth.FromContext(ctx)
 	if err != nil {
@@ -497,13 +534,89 @@ func (s *masterServiceImpl) DeletePosition(ctx context.Context, id string) error
 		return fmt.Errorf("company_id not found in token")
 	}
 
-	err = s.positionRepo.Delete(ctx, id, companyID)
-	if err != nil {
-		// Check if position not found
-		if errors.Is(err, pgx.ErrNoRows) {
-			return position.ErrPositionNotFound
-		}
-		return err
+	return s.deleteWithReassign(ctx, id, reassignTo, referenceGuard{
+		exists: func(ctx context.Context, id string) error {
+			_, err := s.positionRepo.GetByID(ctx, id, companyID)
+			return err
+		},
+		count: func(ctx context.Context, id string) (int, error) {
+			return s.positionRepo.CountReferences(ctx, id, companyID)
+		},
+		reassign: func(ctx context.Context, fromID, toID string) error {
+			return s.positionRepo.ReassignReferences(ctx, fromID, toID, companyID)
+		},
+		remove: func(ctx context.Context, id string) error {
+			return s.positionRepo.Delete(ctx, id, companyID)
+		},
+		errNotFound:      position.ErrPositionNotFound,
+		errInUse:         position.ErrPositionInUse,
+		errInvalidTarget: position.ErrInvalidReassignTarget,
+	})
+}
+
+// ==================== DELETE HELPERS ====================
+
+// referenceGuard bundles the repository calls needed to delete a master record
+// that employees may still reference
+type referenceGuard struct {
+	exists           func(ctx context.Context, id string) error
+	count            func(ctx context.Context, id string) (int, error)
+	reassign         func(ctx context.Context, fromID, toID string) error
+	remove           func(ctx context.Context, id string) error
+	errNotFound      error
+	errInUse         error
+	errInvalidTarget error
+}
+
+// deleteWithReassign optionally moves referencing employees to reassignTo, then deletes id,
+// all in one transaction. Deletion is refused while any reference remains, job history included.
+func (s *masterServiceImpl) deleteWithReassign(ctx context.Context, id, reassignTo string, g referenceGuard) error {
+	if reassignTo == id {
+		return g.errInvalidTarget
 	}
-	return nil
+
+	return postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		if err := g.exists(txCtx, id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return g.errNotFound
+			}
+			return err
+		}
+
+		if reassignTo != "" {
+			if err := g.exists(txCtx, reassignTo); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return g.errInvalidTarget
+				}
+				return err
+			}
+			if err := g.reassign(txCtx, id, reassignTo); err != nil {
+				return err
+			}
+		}
+
+		count, err := g.count(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return g.errInUse
+		}
+
+		if err := g.remove(txCtx, id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return g.errNotFound
+			}
+			// A reference added concurrently still trips the foreign key
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+				return g.errInUse
+			}
+			return err
+		}
+
+		return nil
+	})
 }
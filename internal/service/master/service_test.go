@@ -0,0 +1,98 @@
+package master
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/master/branch"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database/dbtest"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/testutil"
+	"github.com/jackc/pgx/v5"
+)
+
+// fakeBranchRepo counts references per branch: employees and managers, which a reassign moves,
+// and job history, which it leaves in place
+type fakeBranchRepo struct {
+	branch.BranchRepository
+	branches map[string]bool
+	current  map[string]int
+	history  map[string]int
+	deleted  []string
+}
+
+func (r *fakeBranchRepo) GetByID(_ context.Context, id, _ string) (branch.Branch, error) {
+	if !r.branches[id] {
+		return branch.Branch{}, fmt.Errorf("branch not found: %w", pgx.ErrNoRows)
+	}
+	return branch.Branch{ID: id}, nil
+}
+
+func (r *fakeBranchRepo) CountReferences(_ context.Context, id, _ string) (int, error) {
+	return r.current[id] + r.history[id], nil
+}
+
+func (r *fakeBranchRepo) ReassignReferences(_ context.Context, fromID, toID, _ string) error {
+	r.current[toID] += r.current[fromID]
+	r.current[fromID] = 0
+	return nil
+}
+
+func (r *fakeBranchRepo) Delete(_ context.Context, id, _ string) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func TestDeleteBranch(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		reassignTo string
+		history    int
+		wantErr    error
+		wantEnd    string // how the transaction ended
+	}{
+		{name: "in use without a target", id: "jakarta", wantErr: branch.ErrBranchInUse, wantEnd: "rollback"},
+		{name: "reassigned to another branch", id: "jakarta", reassignTo: "bandung", wantEnd: "commit"},
+		{name: "target is the branch itself", id: "jakarta", reassignTo: "jakarta", wantErr: branch.ErrInvalidReassignTarget},
+		{name: "target does not exist", id: "jakarta", reassignTo: "surabaya", wantErr: branch.ErrInvalidReassignTarget, wantEnd: "rollback"},
+		{name: "missing branch", id: "medan", reassignTo: "bandung", wantErr: branch.ErrBranchNotFound, wantEnd: "rollback"},
+		{name: "job history keeps the branch", id: "jakarta", reassignTo: "bandung", history: 3, wantErr: branch.ErrBranchInUse, wantEnd: "rollback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, server := dbtest.NewDB(t, nil)
+			repo := &fakeBranchRepo{
+				branches: map[string]bool{"jakarta": true, "bandung": true},
+				current:  map[string]int{"jakarta": 4},
+				history:  map[string]int{"jakarta": tt.history},
+			}
+			s := &masterServiceImpl{db: db, branchRepo: repo}
+
+			ctx := testutil.ClaimsContext(t, map[string]any{"company_id": "company-1"})
+
+			err := s.DeleteBranch(ctx, tt.id, tt.reassignTo)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteBranch() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (len(repo.deleted) != 1 || repo.current["bandung"] != 4) {
+				t.Errorf("deleted %v with bandung holding %d references, want jakarta deleted and 4 moved", repo.deleted, repo.current["bandung"])
+			}
+			if tt.wantErr != nil && len(repo.deleted) != 0 {
+				t.Errorf("deleted %v, want nothing deleted", repo.deleted)
+			}
+
+			statements := server.Statements()
+			end := ""
+			if len(statements) > 0 {
+				end = strings.ToLower(statements[len(statements)-1])
+			}
+			if end != tt.wantEnd {
+				t.Errorf("transaction ended with %q, want %q", end, tt.wantEnd)
+			}
+		})
+	}
+}
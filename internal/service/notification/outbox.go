@@ -0,0 +1,98 @@
+package notification
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
+)
+
+const (
+	// outboxLease is how long a claimed entry is hidden from other runs while it is being delivered
+	outboxLease = 2 * time.Minute
+
+	outboxBaseBackoff = 30 * time.Second
+	outboxMaxBackoff  = time.Hour
+)
+
+// Enqueue records the notification in the outbox. Called with a transaction context, it commits or
+// rolls back together with the change it announces; DeliverOutbox sends it afterwards.
+func (s *service) Enqueue(ctx context.Context, req notification.CreateNotificationRequest) error {
+	return s.repo.EnqueueOutbox(ctx, req)
+}
+
+// DeliverOutbox sends the outbox entries that are due. A failed delivery is retried with
+// exponential backoff until OutboxMaxAttempts, after which the entry is marked failed.
+func (s *service) DeliverOutbox(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	entries, err := s.repo.ClaimOutbox(ctx, s.config.OutboxBatchSize, now, outboxLease)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, entry := range entries {
+		if err := s.deliver(ctx, entry.Request); err != nil {
+			s.recordOutboxFailure(ctx, entry, err, now)
+			continue
+		}
+
+		if err := s.repo.MarkOutboxSent(ctx, entry.ID); err != nil {
+			// The lease expires and the entry is delivered again; a duplicate beats a lost notification
+			log.Printf("[NotificationService] Failed to mark outbox entry %s sent: %v", entry.ID, err)
+			continue
+		}
+		delivered++
+	}
+
+	return delivered, nil
+}
+
+// deliver runs a queued notification through the same preference, template and coalescing
+// steps as QueueNotification, but inserts it synchronously so the outcome is known
+func (s *service) deliver(ctx context.Context, req notification.CreateNotificationRequest) error {
+	enabled, err := s.repo.IsNotificationEnabled(ctx, req.RecipientID, req.Type)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return nil
+	}
+
+	req = s.applyTemplate(ctx, req)
+
+	if req.DedupKey != "" && req.DedupWindow > 0 {
+		return s.coalesceOrInsert(ctx, req)
+	}
+	return s.directInsert(ctx, req)
+}
+
+// recordOutboxFailure schedules the entry's next attempt, or gives up on it once it is out of attempts
+func (s *service) recordOutboxFailure(ctx context.Context, entry *notification.OutboxEntry, cause error, now time.Time) {
+	if entry.Attempts >= s.config.OutboxMaxAttempts {
+		log.Printf("[NotificationService] Giving up on outbox entry %s after %d attempts: %v", entry.ID, entry.Attempts, cause)
+		if err := s.repo.MarkOutboxFailed(ctx, entry.ID, cause.Error()); err != nil {
+			log.Printf("[NotificationService] Failed to mark outbox entry %s failed: %v", entry.ID, err)
+		}
+		return
+	}
+
+	log.Printf("[NotificationService] Outbox entry %s failed on attempt %d: %v", entry.ID, entry.Attempts, cause)
+	if err := s.repo.MarkOutboxRetry(ctx, entry.ID, cause.Error(), now.Add(outboxBackoff(entry.Attempts))); err != nil {
+		log.Printf("[NotificationService] Failed to reschedule outbox entry %s: %v", entry.ID, err)
+	}
+}
+
+// outboxBackoff doubles the delay after each failed attempt, up to outboxMaxBackoff
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts && delay < outboxMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > outboxMaxBackoff {
+		delay = outboxMaxBackoff
+	}
+	return delay
+}
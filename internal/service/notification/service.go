@@ -2,6 +2,8 @@ package notification
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -17,6 +19,9 @@ type Config struct {
 	FlushInterval time.Duration // default: 5 seconds
 	WorkerCount   int           // default: 2
 	QueueSize     int           // default: 1000
+
+	OutboxBatchSize   int // entries delivered per outbox run, default: 100
+	OutboxMaxAttempts int // deliveries tried before an outbox entry is marked failed, default: 8
 }
 
 type service struct {
@@ -44,6 +49,12 @@ func NewNotificationService(repo notification.Repository, hub *sse.Hub, cfg Conf
 	if cfg.QueueSize == 0 {
 		cfg.QueueSize = 1000
 	}
+	if cfg.OutboxBatchSize == 0 {
+		cfg.OutboxBatchSize = 100
+	}
+	if cfg.OutboxMaxAttempts == 0 {
+		cfg.OutboxMaxAttempts = 8
+	}
 
 	s := &service{
 		repo:   repo,
@@ -144,6 +155,12 @@ func (s *service) QueueNotification(ctx context.Context, req notification.Create
 		return nil // Skip if disabled
 	}
 
+	req = s.applyTemplate(ctx, req)
+
+	if req.DedupKey != "" && req.DedupWindow > 0 {
+		return s.coalesceOrInsert(ctx, req)
+	}
+
 	select {
 	case s.queue <- req:
 		return nil
@@ -155,6 +172,92 @@ func (s *service) QueueNotification(ctx context.Context, req notification.Create
 	}
 }
 
+// applyTemplate replaces the default title and message with the company's template for the type.
+// Any failure to load or render the template keeps the defaults, so dispatch never breaks on it.
+func (s *service) applyTemplate(ctx context.Context, req notification.CreateNotificationRequest) notification.CreateNotificationRequest {
+	if req.CompanyID == "" {
+		return req
+	}
+
+	tmpl, err := s.repo.GetTemplate(ctx, req.CompanyID, req.Type)
+	if err != nil {
+		if !errors.Is(err, notification.ErrTemplateNotFound) {
+			log.Printf("[NotificationService] Failed to load %s template for company %s: %v", req.Type, req.CompanyID, err)
+		}
+		return req
+	}
+
+	title, err := notification.RenderTemplate(tmpl.Title, req.Data)
+	if err != nil {
+		log.Printf("[NotificationService] Failed to render %s title for company %s: %v", req.Type, req.CompanyID, err)
+		return req
+	}
+	message, err := notification.RenderTemplate(tmpl.Message, req.Data)
+	if err != nil {
+		log.Printf("[NotificationService] Failed to render %s message for company %s: %v", req.Type, req.CompanyID, err)
+		return req
+	}
+	if title == "" || message == "" {
+		return req
+	}
+
+	req.Title = title
+	req.Message = message
+	return req
+}
+
+// coalesceOrInsert folds the notification into the recipient's unread one with the same dedup key
+// from within the window, or inserts it when there is none. Any failure to coalesce falls back to
+// inserting, so the recipient still gets at least one unread notification per request.
+func (s *service) coalesceOrInsert(ctx context.Context, req notification.CreateNotificationRequest) error {
+	now := time.Now()
+
+	existing, err := s.repo.GetLatestUnreadByDedupKey(ctx, req.RecipientID, req.DedupKey, now.Add(-req.DedupWindow))
+	if err != nil {
+		if !errors.Is(err, notification.ErrNotificationNotFound) {
+			log.Printf("[NotificationService] Failed to look up %s for coalescing: %v", req.DedupKey, err)
+		}
+		return s.directInsert(ctx, req)
+	}
+
+	count := coalescedCount(existing.Data) + 1
+	data := make(map[string]interface{}, len(req.Data)+1)
+	for k, v := range req.Data {
+		data[k] = v
+	}
+	data["coalesced_count"] = count
+
+	existing.SenderID = req.SenderID
+	existing.Title = req.Title
+	existing.Message = fmt.Sprintf("%s (+%d more)", req.Message, count-1)
+	existing.Data = data
+	existing.CreatedAt = now
+
+	if err := s.repo.Coalesce(ctx, existing); err != nil {
+		if !errors.Is(err, notification.ErrNotificationNotFound) {
+			log.Printf("[NotificationService] Failed to coalesce %s: %v", req.DedupKey, err)
+		}
+		return s.directInsert(ctx, req)
+	}
+
+	s.hub.Publish(existing.RecipientID, sse.Event{
+		UserID: existing.RecipientID,
+		Event:  "notification",
+		Data:   s.toResponse(existing),
+	})
+
+	return nil
+}
+
+// coalescedCount returns how many notifications have been folded into one so far
+func coalescedCount(data map[string]interface{}) int {
+	// JSON numbers come back from the data column as float64
+	if count, ok := data["coalesced_count"].(float64); ok && count > 1 {
+		return int(count)
+	}
+	return 1
+}
+
 // QueueBulkNotification queues multiple notifications for async processing
 func (s *service) QueueBulkNotification(ctx context.Context, reqs []notification.CreateNotificationRequest) error {
 	for _, req := range reqs {
@@ -179,6 +282,9 @@ func (s *service) directInsert(ctx context.Context, req notification.CreateNotif
 		IsRead:      false,
 		CreatedAt:   time.Now(),
 	}
+	if req.DedupKey != "" {
+		n.DedupKey = &req.DedupKey
+	}
 
 	if err := s.repo.Create(ctx, n); err != nil {
 		return err
@@ -310,6 +416,68 @@ func (s *service) UpdatePreference(ctx context.Context, userID string, req notif
 	return s.repo.UpsertPreference(ctx, pref)
 }
 
+// ListTemplates returns every notification type with the company's template, if any
+func (s *service) ListTemplates(ctx context.Context, companyID string) ([]notification.TemplateResponse, error) {
+	templates, err := s.repo.ListTemplates(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	tmplMap := make(map[notification.NotificationType]*notification.NotificationTemplate)
+	for _, t := range templates {
+		tmplMap[t.Type] = t
+	}
+
+	allTypes := notification.AllNotificationTypes()
+	responses := make([]notification.TemplateResponse, len(allTypes))
+
+	for i, t := range allTypes {
+		responses[i] = notification.TemplateResponse{
+			Type:         t,
+			Placeholders: notification.TemplatePlaceholders[t],
+		}
+		if tmpl, ok := tmplMap[t]; ok {
+			responses[i].Title = tmpl.Title
+			responses[i].Message = tmpl.Message
+			responses[i].IsCustom = true
+			responses[i].UpdatedAt = &tmpl.UpdatedAt
+		}
+	}
+
+	return responses, nil
+}
+
+// UpsertTemplate saves a company's template for a notification type
+func (s *service) UpsertTemplate(ctx context.Context, companyID string, req notification.UpsertTemplateRequest) (notification.TemplateResponse, error) {
+	if err := req.Validate(); err != nil {
+		return notification.TemplateResponse{}, err
+	}
+
+	saved, err := s.repo.UpsertTemplate(ctx, &notification.NotificationTemplate{
+		CompanyID: companyID,
+		Type:      req.Type,
+		Title:     req.Title,
+		Message:   req.Message,
+	})
+	if err != nil {
+		return notification.TemplateResponse{}, err
+	}
+
+	return notification.TemplateResponse{
+		Type:         saved.Type,
+		Title:        saved.Title,
+		Message:      saved.Message,
+		IsCustom:     true,
+		Placeholders: notification.TemplatePlaceholders[saved.Type],
+		UpdatedAt:    &saved.UpdatedAt,
+	}, nil
+}
+
+// DeleteTemplate removes a company's template, restoring the default text
+func (s *service) DeleteTemplate(ctx context.Context, companyID string, notifType notification.NotificationType) error {
+	return s.repo.DeleteTemplate(ctx, companyID, notifType)
+}
+
 // Subscribe creates an SSE subscription for a user
 func (s *service) Subscribe(ctx context.Context, userID string) (<-chan notification.SSEEvent, func()) {
 	ch, cleanup := s.hub.Subscribe(userID)
@@ -0,0 +1,208 @@
+package payroll
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/payroll"
+	"github.com/shopspring/decimal"
+)
+
+// BCA fixed-width detail line: account number, amount in sen, beneficiary name, remark
+const (
+	bcaAccountNumberLength = 10
+	bcaAmountWidth         = 15
+	bcaNameWidth           = 35
+	bcaRemarkWidth         = 18
+)
+
+// ExportBankFile implements payroll.PayrollService.
+// Builds a transfer file from the period's finalized (paid) records. Records that cannot be
+// transferred in the requested format are left out and listed in Skipped.
+func (s *PayrollServiceImpl) ExportBankFile(ctx context.Context, companyID string, month, year int, format string) (payroll.BankFileExport, error) {
+	if month < 1 || month > 12 || year < 2000 {
+		return payroll.BankFileExport{}, payroll.ErrInvalidPeriod
+	}
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = payroll.BankFileFormatCSV
+	}
+	if format != payroll.BankFileFormatCSV && format != payroll.BankFileFormatBCA {
+		return payroll.BankFileExport{}, payroll.ErrUnsupportedBankFileFormat
+	}
+
+	lines, err := s.payrollRepo.GetBankTransferLines(ctx, companyID, month, year)
+	if err != nil {
+		return payroll.BankFileExport{}, err
+	}
+
+	result := payroll.BankFileExport{
+		Format:      format,
+		PeriodMonth: month,
+		PeriodYear:  year,
+		TotalAmount: decimal.Zero,
+		Skipped:     []payroll.BankFileSkippedEmployee{},
+	}
+
+	var transfers []payroll.BankTransferLine
+	for _, line := range lines {
+		if reason := bankFileSkipReason(line, format); reason != "" {
+			result.Skipped = append(result.Skipped, payroll.BankFileSkippedEmployee{
+				RecordID:     line.RecordID,
+				EmployeeID:   line.EmployeeID,
+				EmployeeName: line.EmployeeName,
+				EmployeeCode: line.EmployeeCode,
+				NetSalary:    line.NetSalary,
+				Reason:       reason,
+			})
+			continue
+		}
+		transfers = append(transfers, line)
+		result.TotalAmount = result.TotalAmount.Add(line.NetSalary)
+	}
+	result.Transfers = len(transfers)
+
+	remark := fmt.Sprintf("GAJI %02d %04d", month, year)
+	switch format {
+	case payroll.BankFileFormatBCA:
+		result.Content = writeBCABankFile(transfers, remark)
+		result.ContentType = "text/plain"
+		result.Filename = fmt.Sprintf("payroll-bank-bca-%04d-%02d.txt", year, month)
+	default:
+		content, err := writeCSVBankFile(transfers, remark)
+		if err != nil {
+			return payroll.BankFileExport{}, err
+		}
+		result.Content = content
+		result.ContentType = "text/csv"
+		result.Filename = fmt.Sprintf("payroll-bank-%04d-%02d.csv", year, month)
+	}
+
+	return result, nil
+}
+
+// bankFileSkipReason returns why a record cannot go in a bank file of the format, or "" if it can
+func bankFileSkipReason(line payroll.BankTransferLine, format string) string {
+	bankName := strings.TrimSpace(stringValue(line.BankName))
+	accountNumber := normalizeAccountNumber(stringValue(line.AccountNumber))
+
+	if bankName == "" || accountNumber == "" {
+		return payroll.BankFileSkipMissingBankDetails
+	}
+	if !isDigits(accountNumber) {
+		return payroll.BankFileSkipInvalidAccountNumber
+	}
+	if format == payroll.BankFileFormatBCA {
+		if !isBCA(bankName) {
+			return payroll.BankFileSkipUnsupportedBank
+		}
+		if len(accountNumber) != bcaAccountNumberLength {
+			return payroll.BankFileSkipInvalidAccountNumber
+		}
+	}
+	if !line.NetSalary.IsPositive() {
+		return payroll.BankFileSkipNonPositiveNetSalary
+	}
+
+	return ""
+}
+
+func writeCSVBankFile(transfers []payroll.BankTransferLine, remark string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	header := []string{"employee_code", "employee_name", "bank_name", "account_holder_name", "account_number", "amount", "remark"}
+	if err := writer.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	for _, t := range transfers {
+		record := []string{
+			t.EmployeeCode,
+			t.EmployeeName,
+			strings.TrimSpace(stringValue(t.BankName)),
+			accountHolderName(t),
+			normalizeAccountNumber(stringValue(t.AccountNumber)),
+			t.NetSalary.StringFixed(2),
+			remark,
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, fmt.Errorf("failed to write csv record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to flush csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeBCABankFile writes one CRLF terminated, 78 character line per transfer:
+// account number (10), amount in sen zero padded (15), beneficiary name (35), remark (18)
+func writeBCABankFile(transfers []payroll.BankTransferLine, remark string) []byte {
+	var buf bytes.Buffer
+	for _, t := range transfers {
+		sen := t.NetSalary.Mul(decimal.NewFromInt(100)).Round(0).IntPart()
+
+		buf.WriteString(normalizeAccountNumber(stringValue(t.AccountNumber)))
+		buf.WriteString(fmt.Sprintf("%0*d", bcaAmountWidth, sen))
+		buf.WriteString(fixedWidth(accountHolderName(t), bcaNameWidth))
+		buf.WriteString(fixedWidth(remark, bcaRemarkWidth))
+		buf.WriteString("\r\n")
+	}
+
+	return buf.Bytes()
+}
+
+// accountHolderName falls back to the employee's name when no holder name was recorded
+func accountHolderName(t payroll.BankTransferLine) string {
+	if name := strings.TrimSpace(stringValue(t.AccountHolderName)); name != "" {
+		return name
+	}
+	return t.EmployeeName
+}
+
+// fixedWidth upper-cases s, replaces characters banks commonly reject with spaces and pads or cuts it to width
+func fixedWidth(s string, width int) string {
+	cleaned := []byte(strings.Map(func(c rune) rune {
+		if c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == ' ' || c == '.' || c == ',' || c == '-' {
+			return c
+		}
+		return ' '
+	}, strings.ToUpper(s)))
+	if len(cleaned) > width {
+		cleaned = cleaned[:width]
+	}
+	return fmt.Sprintf("%-*s", width, cleaned)
+}
+
+// normalizeAccountNumber drops the spaces, dashes and dots account numbers are often written with
+func normalizeAccountNumber(s string) string {
+	return strings.NewReplacer(" ", "", "-", "", ".", "").Replace(strings.TrimSpace(s))
+}
+
+func isBCA(bankName string) bool {
+	name := strings.ToUpper(bankName)
+	return name == "BCA" || name == "BANK BCA" || strings.Contains(name, "BANK CENTRAL ASIA")
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
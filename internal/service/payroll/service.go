@@ -4,17 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/payroll"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/scope"
+	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/jackc/pgx/v5"
 	"github.com/shopspring/decimal"
 )
 
+// payrollRunStaleAfter is how long a running run may go without saving progress before another
+// generation for the period treats it as abandoned and resumes it
+const payrollRunStaleAfter = 5 * time.Minute
+
 type PayrollServiceImpl struct {
 	db                  *database.DB
 	payrollRepo         payroll.PayrollRepository
@@ -173,13 +181,25 @@ func (s *PayrollServiceImpl) CreateComponent(ctx context.Context, req payroll.Cr
 		isTaxable = *req.IsTaxable
 	}
 
+	displayOrder := 0
+	if req.DisplayOrder != nil {
+		displayOrder = *req.DisplayOrder
+	}
+
+	var category *string
+	if req.Category != nil && *req.Category != "" {
+		category = req.Category
+	}
+
 	component := payroll.PayrollComponent{
-		CompanyID:   companyID,
-		Name:        req.Name,
-		Type:        payroll.ComponentType(req.Type),
-		Description: req.Description,
-		IsTaxable:   isTaxable,
-		IsActive:    true,
+		CompanyID:    companyID,
+		Name:         req.Name,
+		Type:         payroll.ComponentType(req.Type),
+		Description:  req.Description,
+		IsTaxable:    isTaxable,
+		IsActive:     true,
+		DisplayOrder: displayOrder,
+		Category:     category,
 	}
 
 	created, err := s.payrollRepo.CreateComponent(ctx, component)
@@ -187,15 +207,7 @@ func (s *PayrollServiceImpl) CreateComponent(ctx context.Context, req payroll.Cr
 		return payroll.PayrollComponentResponse{}, err
 	}
 
-	return payroll.PayrollComponentResponse{
-		ID:          created.ID,
-		CompanyID:   created.CompanyID,
-		Name:        created.Name,
-		Type:        string(created.Type),
-		Description: created.Description,
-		IsTaxable:   created.IsTaxable,
-		IsActive:    created.IsActive,
-	}, nil
+	return mapToComponentResponse(created), nil
 }
 
 func (s *PayrollServiceImpl) GetComponent(ctx context.Context, id string) (payroll.PayrollComponentResponse, error) {
@@ -209,15 +221,7 @@ func (s *PayrollServiceImpl) GetComponent(ctx context.Context, id string) (payro
 		return payroll.PayrollComponentResponse{}, err
 	}
 
-	return payroll.PayrollComponentResponse{
-		ID:          component.ID,
-		CompanyID:   component.CompanyID,
-		Name:        component.Name,
-		Type:        string(component.Type),
-		Description: component.Description,
-		IsTaxable:   component.IsTaxable,
-		IsActive:    component.IsActive,
-	}, nil
+	return mapToComponentResponse(component), nil
 }
 
 func (s *PayrollServiceImpl) ListComponents(ctx context.Context, activeOnly bool) ([]payroll.PayrollComponentResponse, error) {
@@ -233,21 +237,17 @@ func (s *PayrollServiceImpl) ListComponents(ctx context.Context, activeOnly bool
 
 	var result []payroll.PayrollComponentResponse
 	for _, c := range components {
-		result = append(result, payroll.PayrollComponentResponse{
-			ID:          c.ID,
-			CompanyID:   c.CompanyID,
-			Name:        c.Name,
-			Type:        string(c.Type),
-			Description: c.Description,
-			IsTaxable:   c.IsTaxable,
-			IsActive:    c.IsActive,
-		})
+		result = append(result, mapToComponentResponse(c))
 	}
 
 	return result, nil
 }
 
 func (s *PayrollServiceImpl) UpdateComponent(ctx context.Context, req payroll.UpdatePayrollComponentRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
 	companyID, _, err := getClaimsFromContext(ctx)
 	if err != nil {
 		return err
@@ -390,35 +390,106 @@ func (s *PayrollServiceImpl) RemoveEmployeeComponent(ctx context.Context, id str
 
 // ========== PAYROLL GENERATION ==========
 
-func (s *PayrollServiceImpl) GeneratePayroll(ctx context.Context, req payroll.GeneratePayrollRequest) ([]payroll.PayrollRecordResponse, error) {
+func (s *PayrollServiceImpl) GeneratePayroll(ctx context.Context, req payroll.GeneratePayrollRequest) (payroll.GeneratePayrollResponse, error) {
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return payroll.GeneratePayrollResponse{}, err
 	}
 
-	companyID, _, err := getClaimsFromContext(ctx)
+	companyID, userID, err := getClaimsFromContext(ctx)
 	if err != nil {
-		return nil, err
+		return payroll.GeneratePayrollResponse{}, err
 	}
 
-	// Get payroll settings
-	settings, err := s.payrollRepo.GetSettings(ctx, companyID)
-	if err != nil && !errors.Is(err, payroll.ErrPayrollSettingsNotFound) {
-		return nil, err
+	settings, err := s.getSettingsOrDefault(ctx, companyID)
+	if err != nil {
+		return payroll.GeneratePayrollResponse{}, err
 	}
-	// If not found, use defaults
-	if errors.Is(err, payroll.ErrPayrollSettingsNotFound) {
-		settings = payroll.PayrollSettings{
-			LateDeductionEnabled:         true,
-			LateDeductionPerMinute:       decimal.Zero,
-			OvertimeEnabled:              true,
-			OvertimePayPerMinute:         decimal.Zero,
-			EarlyLeaveDeductionEnabled:   false,
-			EarlyLeaveDeductionPerMinute: decimal.Zero,
+
+	run, err := s.startPayrollRun(ctx, companyID, userID, req.PeriodMonth, req.PeriodYear)
+	if err != nil {
+		return payroll.GeneratePayrollResponse{}, err
+	}
+
+	records, genErr := s.generateRunRecords(ctx, &run, req, settings)
+
+	// Employees are told about every record created, including those of a run that then failed,
+	// since a resumed run skips them
+	go s.notifyEmployeesOnPayrollGenerated(ctx, records, companyID, req.PeriodMonth, req.PeriodYear)
+
+	// Record the outcome even if the request was cancelled, so the run can be resumed
+	finishCtx := context.WithoutCancel(ctx)
+	if genErr != nil {
+		message := genErr.Error()
+		run.Status = payroll.PayrollRunStatusFailed
+		run.LastError = &message
+		if err := s.payrollRepo.FinishPayrollRun(finishCtx, run); err != nil {
+			slog.Error("Failed to mark payroll run as failed", "run_id", run.ID, "error", err)
+		}
+		return payroll.GeneratePayrollResponse{}, genErr
+	}
+
+	now := time.Now()
+	run.Status = payroll.PayrollRunStatusCompleted
+	run.LastError = nil
+	run.CompletedAt = &now
+	if err := s.payrollRepo.FinishPayrollRun(finishCtx, run); err != nil {
+		return payroll.GeneratePayrollResponse{}, err
+	}
+
+	return payroll.GeneratePayrollResponse{
+		Run:     mapToRunResponse(run),
+		Records: mapToRecordResponses(records),
+	}, nil
+}
+
+// startPayrollRun opens a run for the period, or resumes the period's failed or abandoned one
+func (s *PayrollServiceImpl) startPayrollRun(ctx context.Context, companyID, userID string, month, year int) (payroll.PayrollRun, error) {
+	var run payroll.PayrollRun
+	err := postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		open, err := s.payrollRepo.GetOpenPayrollRunForUpdate(txCtx, companyID, month, year)
+		if errors.Is(err, payroll.ErrPayrollRunNotFound) {
+			var createdBy *string
+			if userID != "" {
+				createdBy = &userID
+			}
+			run, err = s.payrollRepo.CreatePayrollRun(txCtx, payroll.PayrollRun{
+				CompanyID:   companyID,
+				PeriodMonth: month,
+				PeriodYear:  year,
+				CreatedBy:   createdBy,
+			})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		// A running run saves progress after every employee; one that did so recently is still going
+		if open.Status == payroll.PayrollRunStatusRunning && time.Since(open.UpdatedAt) < payrollRunStaleAfter {
+			return payroll.ErrPayrollRunInProgress
 		}
+
+		run, err = s.payrollRepo.RestartPayrollRun(txCtx, open.ID, companyID)
+		return err
+	})
+	if err != nil {
+		return payroll.PayrollRun{}, err
 	}
 
+	return run, nil
+}
+
+// generateRunRecords creates the missing records of a run and saves its progress after each employee.
+// Employees that already have a record for the period are counted as processed and skipped, which
+// makes resuming a run safe. It returns the records created so far, also when it fails.
+func (s *PayrollServiceImpl) generateRunRecords(ctx context.Context, run *payroll.PayrollRun, req payroll.GeneratePayrollRequest, settings payroll.PayrollSettings) ([]payroll.PayrollRecord, error) {
+	companyID := run.CompanyID
+
 	// Get employees
 	var employees []employee.Employee
+	var err error
 	if len(req.EmployeeIDs) > 0 {
 		// TODO: Get employees by IDs - for now, get all and filter
 		allEmployees, err := s.employeeRepo.GetActiveByCompanyID(ctx, companyID)
@@ -455,104 +526,110 @@ func (s *PayrollServiceImpl) GeneratePayroll(ctx context.Context, req payroll.Ge
 		attendanceMap[a.EmployeeID] = a
 	}
 
+	run.TotalEmployees = len(employees)
+	run.ProcessedEmployees = 0
+	if err := s.payrollRepo.UpdatePayrollRunProgress(ctx, *run); err != nil {
+		return nil, err
+	}
+
 	// Generate payroll for each employee
 	var records []payroll.PayrollRecord
 	for _, emp := range employees {
-		if emp.BaseSalary == nil || emp.BaseSalary.IsZero() {
-			continue // Skip employees without base salary
-		}
-
-		// Check if record already exists
-		_, err := s.payrollRepo.GetPayrollRecordByEmployeePeriod(ctx, emp.ID, req.PeriodMonth, req.PeriodYear, companyID)
-		if err == nil {
-			continue // Skip if already exists
+		created, err := s.generateEmployeeRecord(ctx, run, emp, req, settings, attendanceMap[emp.ID])
+		if err != nil {
+			return records, err
 		}
-		if !errors.Is(err, payroll.ErrPayrollRecordNotFound) {
-			return nil, fmt.Errorf("failed to check existing payroll record: %w", err)
+		if created != nil {
+			records = append(records, *created)
+			run.CreatedRecords++
 		}
 
-		// Get employee components
-		components, _ := s.payrollRepo.GetEmployeeComponents(ctx, emp.ID, companyID, true)
-
-		totalAllowances := decimal.Zero
-		totalDeductions := decimal.Zero
-		allowancesDetail := make(map[string]decimal.Decimal)
-		deductionsDetail := make(map[string]decimal.Decimal)
-
-		for _, comp := range components {
-			if comp.ComponentType != nil {
-				if *comp.ComponentType == payroll.ComponentTypeAllowance {
-					totalAllowances = totalAllowances.Add(comp.Amount)
-					if comp.ComponentName != nil {
-						allowancesDetail[*comp.ComponentName] = comp.Amount
-					}
-				} else {
-					totalDeductions = totalDeductions.Add(comp.Amount)
-					if comp.ComponentName != nil {
-						deductionsDetail[*comp.ComponentName] = comp.Amount
-					}
-				}
-			}
+		run.ProcessedEmployees++
+		if err := s.payrollRepo.UpdatePayrollRunProgress(ctx, *run); err != nil {
+			return records, err
 		}
+	}
 
-		// Get attendance data
-		att := attendanceMap[emp.ID]
+	return records, nil
+}
 
-		// Calculate late/overtime deductions using decimal
-		lateDeduction := decimal.Zero
-		earlyLeaveDeduction := decimal.Zero
-		overtimeAmount := decimal.Zero
+// generateEmployeeRecord creates an employee's record for the run's period. It returns nil without an
+// error when the employee has no base salary or already has a record for the period.
+func (s *PayrollServiceImpl) generateEmployeeRecord(ctx context.Context, run *payroll.PayrollRun, emp employee.Employee, req payroll.GeneratePayrollRequest, settings payroll.PayrollSettings, att payroll.AttendanceSummary) (*payroll.PayrollRecord, error) {
+	companyID := run.CompanyID
 
-		if settings.LateDeductionEnabled {
-			lateDeduction = decimal.NewFromInt(int64(att.TotalLateMinutes)).Mul(settings.LateDeductionPerMinute)
-		}
-		if settings.EarlyLeaveDeductionEnabled {
-			earlyLeaveDeduction = decimal.NewFromInt(int64(att.TotalEarlyLeaveMinutes)).Mul(settings.EarlyLeaveDeductionPerMinute)
-		}
-		if settings.OvertimeEnabled {
-			overtimeAmount = decimal.NewFromInt(int64(att.TotalOvertimeMinutes)).Mul(settings.OvertimePayPerMinute)
-		}
+	if emp.BaseSalary == nil || emp.BaseSalary.IsZero() {
+		return nil, nil // Skip employees without base salary
+	}
 
-		// Calculate final salary using decimal arithmetic
-		grossSalary := emp.BaseSalary.Add(totalAllowances).Add(overtimeAmount)
-		netSalary := grossSalary.Sub(totalDeductions).Sub(lateDeduction).Sub(earlyLeaveDeduction)
-
-		record := payroll.PayrollRecord{
-			EmployeeID:                emp.ID,
-			CompanyID:                 companyID,
-			PeriodMonth:               req.PeriodMonth,
-			PeriodYear:                req.PeriodYear,
-			BaseSalary:                *emp.BaseSalary,
-			TotalAllowances:           totalAllowances,
-			TotalDeductions:           totalDeductions,
-			AllowancesDetail:          allowancesDetail,
-			DeductionsDetail:          deductionsDetail,
-			TotalWorkDays:             att.TotalWorkDays,
-			TotalLateMinutes:          att.TotalLateMinutes,
-			LateDeductionAmount:       lateDeduction,
-			TotalEarlyLeaveMinutes:    att.TotalEarlyLeaveMinutes,
-			EarlyLeaveDeductionAmount: earlyLeaveDeduction,
-			TotalOvertimeMinutes:      att.TotalOvertimeMinutes,
-			OvertimeAmount:            overtimeAmount,
-			GrossSalary:               grossSalary,
-			NetSalary:                 netSalary,
-			Status:                    payroll.PayrollStatusDraft,
-		}
+	// Check if record already exists
+	_, err := s.payrollRepo.GetPayrollRecordByEmployeePeriod(ctx, emp.ID, req.PeriodMonth, req.PeriodYear, companyID)
+	if err == nil {
+		return nil, nil // Skip if already exists
+	}
+	if !errors.Is(err, payroll.ErrPayrollRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing payroll record: %w", err)
+	}
 
-		created, err := s.payrollRepo.CreatePayrollRecord(ctx, record)
-		if err != nil {
-			if errors.Is(err, payroll.ErrPayrollRecordAlreadyExists) {
-				continue
+	// Get employee components
+	components, _ := s.payrollRepo.GetEmployeeComponents(ctx, emp.ID, companyID, true)
+
+	totalAllowances := decimal.Zero
+	totalDeductions := decimal.Zero
+	allowancesDetail := make(map[string]decimal.Decimal)
+	deductionsDetail := make(map[string]decimal.Decimal)
+	var lines []payroll.PayrollLine
+
+	for _, comp := range components {
+		if comp.ComponentType != nil {
+			if *comp.ComponentType == payroll.ComponentTypeAllowance {
+				totalAllowances = totalAllowances.Add(comp.Amount)
+				if comp.ComponentName != nil {
+					allowancesDetail[*comp.ComponentName] = comp.Amount
+				}
+			} else {
+				totalDeductions = totalDeductions.Add(comp.Amount)
+				if comp.ComponentName != nil {
+					deductionsDetail[*comp.ComponentName] = comp.Amount
+				}
+			}
+			if comp.ComponentName != nil {
+				lines = append(lines, payroll.PayrollLine{
+					Name:         *comp.ComponentName,
+					Type:         *comp.ComponentType,
+					Category:     comp.ComponentCategory,
+					DisplayOrder: comp.ComponentDisplayOrder,
+					Amount:       comp.Amount,
+				})
 			}
-			return nil, fmt.Errorf("failed to create payroll record for employee %s: %w", emp.ID, err)
 		}
-		records = append(records, created)
 	}
 
-	// Notify employees about generated payroll
-	go s.notifyEmployeesOnPayrollGenerated(ctx, records, companyID, req.PeriodMonth, req.PeriodYear)
+	record := payroll.PayrollRecord{
+		EmployeeID:       emp.ID,
+		CompanyID:        companyID,
+		PeriodMonth:      req.PeriodMonth,
+		PeriodYear:       req.PeriodYear,
+		BaseSalary:       *emp.BaseSalary,
+		TotalAllowances:  totalAllowances,
+		TotalDeductions:  totalDeductions,
+		AllowancesDetail: allowancesDetail,
+		DeductionsDetail: deductionsDetail,
+		ComponentLines:   lines,
+		Status:           payroll.PayrollStatusDraft,
+		PayrollRunID:     &run.ID,
+	}
+	applyAttendance(&record, att, settings)
+
+	created, err := s.payrollRepo.CreatePayrollRecord(ctx, record)
+	if err != nil {
+		if errors.Is(err, payroll.ErrPayrollRecordAlreadyExists) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to create payroll record for employee %s: %w", emp.ID, err)
+	}
 
-	return mapToRecordResponses(records), nil
+	return &created, nil
 }
 
 func (s *PayrollServiceImpl) GetPayrollRecord(ctx context.Context, id string) (payroll.PayrollRecordResponse, error) {
@@ -589,6 +666,10 @@ func (s *PayrollServiceImpl) ListPayrollRecords(ctx context.Context, filter payr
 }
 
 func (s *PayrollServiceImpl) UpdatePayrollRecord(ctx context.Context, req payroll.UpdatePayrollRecordRequest) (payroll.PayrollRecordResponse, error) {
+	if err := req.Validate(); err != nil {
+		return payroll.PayrollRecordResponse{}, err
+	}
+
 	companyID, _, err := getClaimsFromContext(ctx)
 	if err != nil {
 		return payroll.PayrollRecordResponse{}, err
@@ -601,11 +682,98 @@ func (s *PayrollServiceImpl) UpdatePayrollRecord(ctx context.Context, req payrol
 	return s.GetPayrollRecord(ctx, req.ID)
 }
 
+// RecalculateRecord re-pulls the attendance summary for an unpaid record's period and recomputes its
+// late, early leave and overtime amounts and salary totals. Base salary, allowances and deductions keep
+// their current values, including manual edits.
+func (s *PayrollServiceImpl) RecalculateRecord(ctx context.Context, recordID string) (payroll.RecalculatePayrollRecordResponse, error) {
+	companyID, _, err := getClaimsFromContext(ctx)
+	if err != nil {
+		return payroll.RecalculatePayrollRecordResponse{}, err
+	}
+
+	record, err := s.payrollRepo.GetPayrollRecordByID(ctx, recordID, companyID)
+	if err != nil {
+		return payroll.RecalculatePayrollRecordResponse{}, err
+	}
+	if record.Status == payroll.PayrollStatusPaid {
+		return payroll.RecalculatePayrollRecordResponse{}, payroll.ErrPayrollRecordAlreadyPaid
+	}
+	before := recordTotals(record)
+
+	settings, err := s.getSettingsOrDefault(ctx, companyID)
+	if err != nil {
+		return payroll.RecalculatePayrollRecordResponse{}, err
+	}
+
+	summaries, err := s.payrollRepo.GetAttendanceSummary(ctx, companyID, record.PeriodMonth, record.PeriodYear, []string{record.EmployeeID})
+	if err != nil {
+		return payroll.RecalculatePayrollRecordResponse{}, fmt.Errorf("failed to get attendance summary: %w", err)
+	}
+	// No attendance left in the period means all attendance figures drop to zero
+	var att payroll.AttendanceSummary
+	for _, summary := range summaries {
+		if summary.EmployeeID == record.EmployeeID {
+			att = summary
+		}
+	}
+
+	applyAttendance(&record, att, settings)
+	if err := s.payrollRepo.UpdatePayrollRecordAttendance(ctx, record); err != nil {
+		return payroll.RecalculatePayrollRecordResponse{}, err
+	}
+
+	return payroll.RecalculatePayrollRecordResponse{
+		Before: before,
+		After:  recordTotals(record),
+		Record: mapToRecordResponse(record),
+	}, nil
+}
+
+// ========== RUNS ==========
+
+func (s *PayrollServiceImpl) GetPayrollRun(ctx context.Context, id string) (payroll.PayrollRunResponse, error) {
+	companyID, _, err := getClaimsFromContext(ctx)
+	if err != nil {
+		return payroll.PayrollRunResponse{}, err
+	}
+
+	run, err := s.payrollRepo.GetPayrollRunByID(ctx, id, companyID)
+	if err != nil {
+		return payroll.PayrollRunResponse{}, err
+	}
+
+	return mapToRunResponse(run), nil
+}
+
+func (s *PayrollServiceImpl) ListPayrollRuns(ctx context.Context, filter payroll.PayrollRunFilter) ([]payroll.PayrollRunResponse, error) {
+	companyID, _, err := getClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := s.payrollRepo.ListPayrollRuns(ctx, companyID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]payroll.PayrollRunResponse, 0, len(runs))
+	for _, run := range runs {
+		result = append(result, mapToRunResponse(run))
+	}
+
+	return result, nil
+}
+
 func (s *PayrollServiceImpl) FinalizePayroll(ctx context.Context, req payroll.FinalizePayrollRequest) error {
 	if err := req.Validate(); err != nil {
 		return err
 	}
 
+	// Finalizing locks records for payout; enforce here as well as on the route
+	if err := scope.Require(ctx, user.PermissionPayrollFinalize); err != nil {
+		return err
+	}
+
 	companyID, userID, err := getClaimsFromContext(ctx)
 	if err != nil {
 		return err
@@ -615,6 +783,10 @@ func (s *PayrollServiceImpl) FinalizePayroll(ctx context.Context, req payroll.Fi
 }
 
 func (s *PayrollServiceImpl) DeletePayrollRecord(ctx context.Context, id string) error {
+	if err := scope.Require(ctx, user.PermissionPayrollFinalize); err != nil {
+		return err
+	}
+
 	companyID, _, err := getClaimsFromContext(ctx)
 	if err != nil {
 		return err
@@ -631,11 +803,108 @@ func (s *PayrollServiceImpl) GetPayrollSummary(ctx context.Context, month, year
 		return payroll.PayrollSummaryResponse{}, err
 	}
 
-	return s.payrollRepo.GetPayrollSummary(ctx, companyID, month, year)
+	return postgresql.WithStatementTimeout(ctx, s.db, s.db.ReportStatementTimeout, func(ctx context.Context) (payroll.PayrollSummaryResponse, error) {
+		return s.payrollRepo.GetPayrollSummary(ctx, companyID, month, year)
+	})
 }
 
 // ========== HELPERS ==========
 
+func mapToRunResponse(run payroll.PayrollRun) payroll.PayrollRunResponse {
+	progress := 0
+	if run.TotalEmployees > 0 {
+		progress = run.ProcessedEmployees * 100 / run.TotalEmployees
+	}
+	if run.Status == payroll.PayrollRunStatusCompleted {
+		progress = 100
+	}
+
+	var completedAt *string
+	if run.CompletedAt != nil {
+		str := run.CompletedAt.Format(time.RFC3339)
+		completedAt = &str
+	}
+
+	return payroll.PayrollRunResponse{
+		ID:                 run.ID,
+		PeriodMonth:        run.PeriodMonth,
+		PeriodYear:         run.PeriodYear,
+		Status:             string(run.Status),
+		TotalEmployees:     run.TotalEmployees,
+		ProcessedEmployees: run.ProcessedEmployees,
+		CreatedRecords:     run.CreatedRecords,
+		ProgressPercent:    progress,
+		LastError:          run.LastError,
+		CreatedBy:          run.CreatedBy,
+		StartedAt:          run.StartedAt.Format(time.RFC3339),
+		CompletedAt:        completedAt,
+	}
+}
+
+// getSettingsOrDefault returns the company's payroll settings, or the defaults if none were saved
+func (s *PayrollServiceImpl) getSettingsOrDefault(ctx context.Context, companyID string) (payroll.PayrollSettings, error) {
+	settings, err := s.payrollRepo.GetSettings(ctx, companyID)
+	if errors.Is(err, payroll.ErrPayrollSettingsNotFound) {
+		return payroll.PayrollSettings{
+			LateDeductionEnabled:         true,
+			LateDeductionPerMinute:       decimal.Zero,
+			OvertimeEnabled:              true,
+			OvertimePayPerMinute:         decimal.Zero,
+			EarlyLeaveDeductionEnabled:   false,
+			EarlyLeaveDeductionPerMinute: decimal.Zero,
+		}, nil
+	}
+	if err != nil {
+		return payroll.PayrollSettings{}, err
+	}
+
+	return settings, nil
+}
+
+// applyAttendance sets a record's attendance figures from the summary, prices them with the settings
+// and recomputes gross and net salary from the record's base salary, allowances and deductions
+func applyAttendance(record *payroll.PayrollRecord, att payroll.AttendanceSummary, settings payroll.PayrollSettings) {
+	lateDeduction := decimal.Zero
+	earlyLeaveDeduction := decimal.Zero
+	overtimeAmount := decimal.Zero
+
+	if settings.LateDeductionEnabled {
+		lateDeduction = decimal.NewFromInt(int64(att.TotalLateMinutes)).Mul(settings.LateDeductionPerMinute)
+	}
+	if settings.EarlyLeaveDeductionEnabled {
+		earlyLeaveDeduction = decimal.NewFromInt(int64(att.TotalEarlyLeaveMinutes)).Mul(settings.EarlyLeaveDeductionPerMinute)
+	}
+	if settings.OvertimeEnabled {
+		overtimeAmount = att.PayableOvertimeMinutes.Mul(settings.OvertimePayPerMinute)
+	}
+
+	record.TotalWorkDays = att.TotalWorkDays
+	record.TotalLateMinutes = att.TotalLateMinutes
+	record.LateDeductionAmount = lateDeduction
+	record.TotalEarlyLeaveMinutes = att.TotalEarlyLeaveMinutes
+	record.EarlyLeaveDeductionAmount = earlyLeaveDeduction
+	record.TotalOvertimeMinutes = att.TotalOvertimeMinutes
+	record.OvertimeAmount = overtimeAmount
+
+	// Calculate final salary using decimal arithmetic
+	record.GrossSalary = record.BaseSalary.Add(record.TotalAllowances).Add(overtimeAmount)
+	record.NetSalary = record.GrossSalary.Sub(record.TotalDeductions).Sub(lateDeduction).Sub(earlyLeaveDeduction)
+}
+
+func recordTotals(r payroll.PayrollRecord) payroll.PayrollRecordTotals {
+	return payroll.PayrollRecordTotals{
+		TotalWorkDays:             r.TotalWorkDays,
+		TotalLateMinutes:          r.TotalLateMinutes,
+		LateDeductionAmount:       r.LateDeductionAmount,
+		TotalEarlyLeaveMinutes:    r.TotalEarlyLeaveMinutes,
+		EarlyLeaveDeductionAmount: r.EarlyLeaveDeductionAmount,
+		TotalOvertimeMinutes:      r.TotalOvertimeMinutes,
+		OvertimeAmount:            r.OvertimeAmount,
+		GrossSalary:               r.GrossSalary,
+		NetSalary:                 r.NetSalary,
+	}
+}
+
 func mapToRecordResponse(r payroll.PayrollRecord) payroll.PayrollRecordResponse {
 	var paidAtStr *string
 	if r.PaidAt != nil {
@@ -666,6 +935,8 @@ func mapToRecordResponse(r payroll.PayrollRecord) payroll.PayrollRecordResponse
 		TotalDeductions:           r.TotalDeductions,
 		AllowancesDetail:          r.AllowancesDetail,
 		DeductionsDetail:          r.DeductionsDetail,
+		Allowances:                mapToLineResponses(r.Lines(payroll.ComponentTypeAllowance)),
+		Deductions:                mapToLineResponses(r.Lines(payroll.ComponentTypeDeduction)),
 		TotalWorkDays:             r.TotalWorkDays,
 		TotalLateMinutes:          r.TotalLateMinutes,
 		LateDeductionAmount:       r.LateDeductionAmount,
@@ -678,6 +949,33 @@ func mapToRecordResponse(r payroll.PayrollRecord) payroll.PayrollRecordResponse
 		Status:                    string(r.Status),
 		PaidAt:                    paidAtStr,
 		Notes:                     r.Notes,
+		Version:                   r.Version,
+	}
+}
+
+func mapToLineResponses(lines []payroll.PayrollLine) []payroll.PayrollLineResponse {
+	result := make([]payroll.PayrollLineResponse, 0, len(lines))
+	for _, line := range lines {
+		result = append(result, payroll.PayrollLineResponse{
+			Name:     line.Name,
+			Category: line.Category,
+			Amount:   line.Amount,
+		})
+	}
+	return result
+}
+
+func mapToComponentResponse(c payroll.PayrollComponent) payroll.PayrollComponentResponse {
+	return payroll.PayrollComponentResponse{
+		ID:           c.ID,
+		CompanyID:    c.CompanyID,
+		Name:         c.Name,
+		Type:         string(c.Type),
+		Description:  c.Description,
+		IsTaxable:    c.IsTaxable,
+		IsActive:     c.IsActive,
+		DisplayOrder: c.DisplayOrder,
+		Category:     c.Category,
 	}
 }
 
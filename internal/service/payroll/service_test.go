@@ -0,0 +1,24 @@
+package payroll
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/payroll"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/validator"
+)
+
+// An edit without the version it was made against could overwrite a newer change, so it is
+// rejected before anything is read or written
+func TestUpdatePayrollRecordRequiresVersion(t *testing.T) {
+	s := &PayrollServiceImpl{}
+	notes := "corrected allowance"
+
+	_, err := s.UpdatePayrollRecord(context.Background(), payroll.UpdatePayrollRecordRequest{ID: "rec-1", Notes: &notes})
+
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) || errs.ToMap()["version"] != "version is required" {
+		t.Fatalf("UpdatePayrollRecord() error = %v, want a version validation error", err)
+	}
+}
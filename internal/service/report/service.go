@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/report"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/scope"
 	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
 	"github.com/go-chi/jwtauth/v5"
 )
@@ -52,8 +53,8 @@ func (s *ReportServiceImpl) GenerateMonthlyAttendanceReport(ctx context.Context,
 	periodStart := time.Date(req.Year, time.Month(req.Month), 1, 0, 0, 0, 0, time.Local)
 	periodEnd := periodStart.AddDate(0, 1, -1)
 
-	// Get data from repository
-	employees, err := s.reportRepo.GetMonthlyAttendanceReport(ctx, companyID, req.Month, req.Year)
+	// Get data from repository, limited to the caller's branch for branch-scoped managers
+	employees, err := s.reportRepo.GetMonthlyAttendanceReport(ctx, companyID, scope.BranchFilter(ctx), req.Month, req.Year)
 	if err != nil {
 		return report.MonthlyAttendanceReport{}, fmt.Errorf("failed to get attendance data: %w", err)
 	}
@@ -118,8 +119,8 @@ func (s *ReportServiceImpl) GenerateLeaveBalanceReport(ctx context.Context, req
 		return report.LeaveBalanceReport{}, err
 	}
 
-	// Get data from repository
-	rows, err := s.reportRepo.GetLeaveBalanceReport(ctx, companyID, req.Year)
+	// Get data from repository, limited to the caller's branch for branch-scoped managers
+	rows, err := s.reportRepo.GetLeaveBalanceReport(ctx, companyID, scope.BranchFilter(ctx), req.Year)
 	if err != nil {
 		return report.LeaveBalanceReport{}, fmt.Errorf("failed to get leave balance data: %w", err)
 	}
@@ -0,0 +1,238 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/role"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/go-chi/jwtauth/v5"
+)
+
+type RoleServiceImpl struct {
+	roleRepo     role.RoleRepository
+	employeeRepo employee.EmployeeRepository
+}
+
+func NewRoleService(roleRepo role.RoleRepository, employeeRepo employee.EmployeeRepository) role.RoleService {
+	return &RoleServiceImpl{
+		roleRepo:     roleRepo,
+		employeeRepo: employeeRepo,
+	}
+}
+
+// getCompanyIDFromContext extracts company_id from JWT claims
+func getCompanyIDFromContext(ctx context.Context) (string, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return "", fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	return companyID, nil
+}
+
+// ListPermissions implements role.RoleService.
+func (s *RoleServiceImpl) ListPermissions(ctx context.Context) role.PermissionCatalogResponse {
+	all := make([]string, 0, len(user.AllPermissions))
+	assignable := make([]string, 0, len(user.AllPermissions))
+	for _, p := range user.AllPermissions {
+		all = append(all, string(p))
+		if user.IsAssignablePermission(p) {
+			assignable = append(assignable, string(p))
+		}
+	}
+
+	defaults := make(map[string][]string, len(user.RolePermissions))
+	for r, permissions := range user.RolePermissions {
+		names := make([]string, 0, len(permissions))
+		for _, p := range permissions {
+			names = append(names, string(p))
+		}
+		defaults[string(r)] = names
+	}
+
+	return role.PermissionCatalogResponse{
+		Permissions:  all,
+		Assignable:   assignable,
+		RoleDefaults: defaults,
+	}
+}
+
+// ListRoles implements role.RoleService.
+func (s *RoleServiceImpl) ListRoles(ctx context.Context) ([]role.RoleResponse, error) {
+	companyID, err := getCompanyIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.roleRepo.ListByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]role.RoleResponse, 0, len(roles))
+	for _, r := range roles {
+		responses = append(responses, mapRoleToResponse(r))
+	}
+
+	return responses, nil
+}
+
+// GetRole implements role.RoleService.
+func (s *RoleServiceImpl) GetRole(ctx context.Context, id string) (role.RoleResponse, error) {
+	companyID, err := getCompanyIDFromContext(ctx)
+	if err != nil {
+		return role.RoleResponse{}, err
+	}
+
+	found, err := s.roleRepo.GetByID(ctx, id, companyID)
+	if err != nil {
+		return role.RoleResponse{}, err
+	}
+
+	return mapRoleToResponse(found), nil
+}
+
+// CreateRole implements role.RoleService.
+func (s *RoleServiceImpl) CreateRole(ctx context.Context, req role.CreateRoleRequest) (role.RoleResponse, error) {
+	if err := req.Validate(); err != nil {
+		return role.RoleResponse{}, err
+	}
+
+	companyID, err := getCompanyIDFromContext(ctx)
+	if err != nil {
+		return role.RoleResponse{}, err
+	}
+
+	created, err := s.roleRepo.Create(ctx, role.Role{
+		CompanyID:   companyID,
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: toPermissions(req.Permissions),
+	})
+	if err != nil {
+		return role.RoleResponse{}, err
+	}
+
+	return mapRoleToResponse(created), nil
+}
+
+// UpdateRole implements role.RoleService.
+func (s *RoleServiceImpl) UpdateRole(ctx context.Context, req role.UpdateRoleRequest) (role.RoleResponse, error) {
+	if err := req.Validate(); err != nil {
+		return role.RoleResponse{}, err
+	}
+
+	companyID, err := getCompanyIDFromContext(ctx)
+	if err != nil {
+		return role.RoleResponse{}, err
+	}
+
+	existing, err := s.roleRepo.GetByID(ctx, req.ID, companyID)
+	if err != nil {
+		return role.RoleResponse{}, err
+	}
+
+	if req.Name != nil {
+		existing.Name = *req.Name
+	}
+	if req.Description != nil {
+		existing.Description = req.Description
+	}
+	if req.Permissions != nil {
+		existing.Permissions = toPermissions(*req.Permissions)
+	}
+
+	if err := s.roleRepo.Update(ctx, existing); err != nil {
+		return role.RoleResponse{}, err
+	}
+
+	updated, err := s.roleRepo.GetByID(ctx, req.ID, companyID)
+	if err != nil {
+		return role.RoleResponse{}, err
+	}
+
+	return mapRoleToResponse(updated), nil
+}
+
+// DeleteRole implements role.RoleService.
+func (s *RoleServiceImpl) DeleteRole(ctx context.Context, id string) error {
+	companyID, err := getCompanyIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.roleRepo.Delete(ctx, id, companyID)
+}
+
+// AssignRole implements role.RoleService.
+func (s *RoleServiceImpl) AssignRole(ctx context.Context, req role.AssignRoleRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	companyID, err := getCompanyIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	emp, err := s.employeeRepo.GetByIDWithDetails(ctx, req.EmployeeID, companyID)
+	if err != nil {
+		if errors.Is(err, employee.ErrEmployeeNotFound) {
+			return employee.ErrEmployeeNotFound
+		}
+		return fmt.Errorf("failed to get employee: %w", err)
+	}
+
+	// Only employees who have accepted their invitation have an account to attach a role to
+	if emp.UserID == nil {
+		return user.ErrUserNotFound
+	}
+
+	// The role must belong to the same company
+	if req.RoleID != nil {
+		if _, err := s.roleRepo.GetByID(ctx, *req.RoleID, companyID); err != nil {
+			return err
+		}
+	}
+
+	return s.roleRepo.AssignToUser(ctx, *emp.UserID, req.RoleID)
+}
+
+func mapRoleToResponse(r role.Role) role.RoleResponse {
+	permissions := make([]string, 0, len(r.Permissions))
+	for _, p := range r.Permissions {
+		permissions = append(permissions, string(p))
+	}
+
+	return role.RoleResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: permissions,
+		CreatedAt:   r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   r.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// toPermissions converts validated permission names, dropping duplicates
+func toPermissions(names []string) []user.Permission {
+	seen := make(map[string]struct{}, len(names))
+	permissions := make([]user.Permission, 0, len(names))
+	for _, name := range names {
+		if _, dup := seen[name]; dup {
+			continue
+		}
+		seen[name] = struct{}{}
+		permissions = append(permissions, user.Permission(name))
+	}
+	return permissions
+}
@@ -0,0 +1,93 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/testutil"
+)
+
+// newAssignmentTestService has one employee hired on 2 March 2026 who resigned on 30 September
+// 2026. Both dates carry a time of day, which the check must ignore.
+func newAssignmentTestService() (*scheduleServiceImpl, *fakeAssignmentRepo) {
+	resigned := time.Date(2026, 9, 30, 17, 0, 0, 0, time.UTC)
+	assignments := &fakeAssignmentRepo{}
+	return &scheduleServiceImpl{
+		employeeRepo: &fakeEmployeeRepo{employees: map[string]employee.Employee{
+			"emp-1": {ID: "emp-1", CompanyID: "company-1", HireDate: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), ResignationDate: &resigned},
+			"emp-2": {ID: "emp-2", CompanyID: "company-1", HireDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+		}},
+		employeeScheduleAssignRepo: assignments,
+	}, assignments
+}
+
+func TestUpdateAssignmentWithinEmploymentBoundary(t *testing.T) {
+	tests := []struct {
+		name       string
+		employeeID string
+		start, end string
+		wantErr    error
+	}{
+		{"starts on the hire date", "emp-1", "2026-03-02", "2026-03-31", nil},
+		{"starts the day before hire", "emp-1", "2026-03-01", "2026-03-31", schedule.ErrAssignmentOutsideEmployment},
+		{"ends on the resignation date", "emp-1", "2026-09-01", "2026-09-30", nil},
+		{"ends the day after resignation", "emp-1", "2026-09-01", "2026-10-01", schedule.ErrAssignmentOutsideEmployment},
+		{"single day on the resignation date", "emp-1", "2026-09-30", "2026-09-30", nil},
+		{"starts after resignation", "emp-1", "2026-10-01", "2026-10-31", schedule.ErrAssignmentOutsideEmployment},
+		{"no resignation", "emp-2", "2026-03-02", "2030-12-31", nil},
+		{"employee of another company", "emp-other", "2026-03-02", "2026-03-31", employee.ErrEmployeeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, assignments := newAssignmentTestService()
+			ctx := testutil.ClaimsContext(t, map[string]any{"company_id": "company-1"})
+
+			err := s.UpdateEmployeeScheduleAssignment(ctx, schedule.UpdateEmployeeScheduleAssignmentRequest{
+				ID: "esa-1", EmployeeID: tt.employeeID, WorkScheduleID: "ws-1", StartDate: tt.start, EndDate: tt.end,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateEmployeeScheduleAssignment() error = %v, want %v", err, tt.wantErr)
+			}
+			// A rejected assignment never reaches the repository
+			if reached := len(assignments.updates) == 1; reached != (tt.wantErr == nil) {
+				t.Errorf("repository updated = %v, want %v", reached, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestOpenEndedAssignmentWithinEmployment(t *testing.T) {
+	s, _ := newAssignmentTestService()
+	ctx := testutil.ClaimsContext(t, map[string]any{"company_id": "company-1"})
+	date := func(day string) time.Time {
+		d, _ := time.Parse("2006-01-02", day)
+		return d
+	}
+
+	tests := []struct {
+		name       string
+		employeeID string
+		start      string
+		companyID  string
+		wantErr    error
+	}{
+		{"starts on the hire date", "emp-1", "2026-03-02", "company-1", nil},
+		{"starts the day before hire", "emp-2", "2026-03-01", "company-1", schedule.ErrAssignmentOutsideEmployment},
+		{"starts on the resignation date", "emp-1", "2026-09-30", "company-1", nil},
+		{"starts the day after resignation", "emp-1", "2026-10-01", "company-1", schedule.ErrAssignmentOutsideEmployment},
+		{"employee of another company", "emp-1", "2026-03-02", "company-2", employee.ErrEmployeeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkWithinEmployment(ctx, tt.employeeID, tt.companyID, date(tt.start), nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkWithinEmployment() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
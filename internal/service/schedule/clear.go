@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ClearScheduleTimes implements schedule.ScheduleService.
+func (s *scheduleServiceImpl) ClearScheduleTimes(ctx context.Context, scheduleID string, force bool) (schedule.ClearScheduleResponse, error) {
+	companyID, err := s.checkScheduleClearable(ctx, scheduleID, force)
+	if err != nil {
+		return schedule.ClearScheduleResponse{}, err
+	}
+
+	deleted, err := s.workScheduleTimeRepo.BulkDeleteByWorkScheduleID(ctx, scheduleID, companyID)
+	if err != nil {
+		// Attendance keeps a reference to the time it was recorded against
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+			return schedule.ClearScheduleResponse{}, schedule.ErrWorkScheduleTimeInUse
+		}
+		return schedule.ClearScheduleResponse{}, fmt.Errorf("failed to clear work schedule times: %w", err)
+	}
+
+	return schedule.ClearScheduleResponse{WorkScheduleID: scheduleID, Deleted: deleted}, nil
+}
+
+// ClearScheduleLocations implements schedule.ScheduleService.
+func (s *scheduleServiceImpl) ClearScheduleLocations(ctx context.Context, scheduleID string, force bool) (schedule.ClearScheduleResponse, error) {
+	companyID, err := s.checkScheduleClearable(ctx, scheduleID, force)
+	if err != nil {
+		return schedule.ClearScheduleResponse{}, err
+	}
+
+	deleted, err := s.workScheduleLocationRepo.BulkDeleteByWorkScheduleID(ctx, scheduleID, companyID)
+	if err != nil {
+		return schedule.ClearScheduleResponse{}, fmt.Errorf("failed to clear work schedule locations: %w", err)
+	}
+
+	return schedule.ClearScheduleResponse{WorkScheduleID: scheduleID, Deleted: deleted}, nil
+}
+
+// checkScheduleClearable resolves the caller's company and makes sure the schedule exists in it.
+// Unless forced, a schedule still assigned to employees may not be cleared.
+func (s *scheduleServiceImpl) checkScheduleClearable(ctx context.Context, scheduleID string, force bool) (string, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return "", fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	if _, err := s.workScheduleRepo.GetByID(ctx, scheduleID, companyID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", schedule.ErrWorkScheduleNotFound
+		}
+		return "", fmt.Errorf("failed to get work schedule: %w", err)
+	}
+
+	if force {
+		return companyID, nil
+	}
+
+	assigned, err := s.workScheduleRepo.CountActiveAssignments(ctx, scheduleID, companyID)
+	if err != nil {
+		return "", err
+	}
+	if assigned > 0 {
+		return "", schedule.ErrWorkScheduleInUse
+	}
+
+	return companyID, nil
+}
@@ -0,0 +1,43 @@
+package schedule
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/testutil"
+)
+
+func TestClearScheduleLocations(t *testing.T) {
+	tests := []struct {
+		name        string
+		assigned    int
+		force       bool
+		wantErr     error
+		wantDeleted []string
+	}{
+		{name: "clears an unassigned schedule", wantDeleted: []string{"ws-1"}},
+		{name: "refuses an assigned schedule", assigned: 2, wantErr: schedule.ErrWorkScheduleInUse},
+		{name: "forces an assigned schedule", assigned: 2, force: true, wantDeleted: []string{"ws-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locations := &fakeWorkScheduleLocationRepo{}
+			s := &scheduleServiceImpl{
+				workScheduleRepo:         &fakeWorkScheduleRepo{ws: schedule.WorkSchedule{ID: "ws-1", CompanyID: "company-1"}, assigned: tt.assigned},
+				workScheduleLocationRepo: locations,
+			}
+			ctx := testutil.ClaimsContext(t, map[string]any{"company_id": "company-1"})
+
+			_, err := s.ClearScheduleLocations(ctx, "ws-1", tt.force)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ClearScheduleLocations() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(locations.deleted, tt.wantDeleted) {
+				t.Errorf("locations deleted for %v, want %v", locations.deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
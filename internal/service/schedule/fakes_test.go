@@ -0,0 +1,89 @@
+package schedule
+
+import (
+	"context"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/jackc/pgx/v5"
+)
+
+// fakeWorkScheduleRepo applies updates to a single stored schedule
+type fakeWorkScheduleRepo struct {
+	schedule.WorkScheduleRepository
+	ws        schedule.WorkSchedule
+	assigned  int
+	updateErr error
+}
+
+func (r *fakeWorkScheduleRepo) GetByID(_ context.Context, id, companyID string) (schedule.WorkSchedule, error) {
+	if r.ws.ID != id || r.ws.CompanyID != companyID {
+		return schedule.WorkSchedule{}, pgx.ErrNoRows
+	}
+	return r.ws, nil
+}
+
+func (r *fakeWorkScheduleRepo) CountActiveAssignments(_ context.Context, _, _ string) (int, error) {
+	return r.assigned, nil
+}
+
+func (r *fakeWorkScheduleRepo) Update(_ context.Context, req schedule.UpdateWorkScheduleRequest) (schedule.WorkSchedule, error) {
+	if r.updateErr != nil {
+		return schedule.WorkSchedule{}, r.updateErr
+	}
+	if req.Type != nil {
+		r.ws.Type = schedule.WorkArrangement(*req.Type)
+	}
+	return r.ws, nil
+}
+
+// fakeWorkScheduleLocationRepo records which schedules had their locations removed
+type fakeWorkScheduleLocationRepo struct {
+	schedule.WorkScheduleLocationRepository
+	deleteErr error
+	deleted   []string
+}
+
+func (r *fakeWorkScheduleLocationRepo) BulkDeleteByWorkScheduleID(_ context.Context, workScheduleID, _ string) (int64, error) {
+	if r.deleteErr != nil {
+		return 0, r.deleteErr
+	}
+	r.deleted = append(r.deleted, workScheduleID)
+	return 1, nil
+}
+
+// fakeCompanyRepo places every company in one timezone
+type fakeCompanyRepo struct {
+	company.CompanyRepository
+	timezone string
+}
+
+func (r *fakeCompanyRepo) GetTimezone(context.Context, string) (string, error) {
+	return r.timezone, nil
+}
+
+// fakeEmployeeRepo keeps employees in memory
+type fakeEmployeeRepo struct {
+	employee.EmployeeRepository
+	employees map[string]employee.Employee
+}
+
+func (r *fakeEmployeeRepo) GetByID(_ context.Context, id string) (employee.Employee, error) {
+	emp, ok := r.employees[id]
+	if !ok {
+		return employee.Employee{}, pgx.ErrNoRows
+	}
+	return emp, nil
+}
+
+// fakeAssignmentRepo records the assignment updates that reach it
+type fakeAssignmentRepo struct {
+	schedule.EmployeeScheduleAssignmentRepository
+	updates []schedule.UpdateEmployeeScheduleAssignmentRequest
+}
+
+func (r *fakeAssignmentRepo) Update(_ context.Context, req schedule.UpdateEmployeeScheduleAssignmentRequest, _ string) error {
+	r.updates = append(r.updates, req)
+	return nil
+}
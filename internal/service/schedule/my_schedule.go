@@ -0,0 +1,182 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetMyScheduleRange implements schedule.ScheduleService.
+// Each day resolves like GetActiveSchedule: the latest-starting dated assignment covering the
+// day wins, otherwise the employee's default schedule applies. Consecutive days that resolve
+// to the same times are merged into one period.
+func (s *scheduleServiceImpl) GetMyScheduleRange(ctx context.Context, userID string, startDate, endDate string) (schedule.MyScheduleRangeResponse, error) {
+	filter := schedule.MyScheduleRangeFilter{StartDate: startDate, EndDate: endDate}
+	if err := filter.Validate(); err != nil {
+		return schedule.MyScheduleRangeResponse{}, err
+	}
+
+	emp, err := s.employeeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return schedule.MyScheduleRangeResponse{}, employee.ErrEmployeeNotFound
+		}
+		return schedule.MyScheduleRangeResponse{}, fmt.Errorf("failed to get employee: %w", err)
+	}
+
+	loc := s.companyLocation(ctx, emp.CompanyID)
+	start, err := utils.ParseDateInLocation(startDate, loc)
+	if err != nil {
+		return schedule.MyScheduleRangeResponse{}, schedule.ErrInvalidDateFormat
+	}
+	end, err := utils.ParseDateInLocation(endDate, loc)
+	if err != nil {
+		return schedule.MyScheduleRangeResponse{}, schedule.ErrInvalidDateFormat
+	}
+
+	assignments, err := s.employeeScheduleAssignRepo.GetScheduleAssignments(ctx, emp.ID, start, end)
+	if err != nil {
+		return schedule.MyScheduleRangeResponse{}, fmt.Errorf("failed to get schedule assignments: %w", err)
+	}
+
+	resolver := &scheduleDayResolver{service: s, companyID: emp.CompanyID, schedules: make(map[string]*resolvedSchedule)}
+	result := schedule.MyScheduleRangeResponse{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Periods:   []schedule.MySchedulePeriod{},
+	}
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		source, scheduleID := schedule.ScheduleSourceDefault, emp.WorkScheduleID
+		if assignment := latestAssignmentOn(assignments, day); assignment != nil {
+			source, scheduleID = schedule.ScheduleSourceOverride, assignment.WorkScheduleID
+		}
+
+		period, err := resolver.period(ctx, source, scheduleID, day)
+		if err != nil {
+			return schedule.MyScheduleRangeResponse{}, err
+		}
+
+		date := day.Format("2006-01-02")
+		if n := len(result.Periods); n > 0 && samePeriodSchedule(result.Periods[n-1], period) {
+			result.Periods[n-1].EndDate = date
+			result.Periods[n-1].Days++
+			continue
+		}
+		period.StartDate, period.EndDate, period.Days = date, date, 1
+		result.Periods = append(result.Periods, period)
+	}
+
+	return result, nil
+}
+
+// latestAssignmentOn returns the assignment covering day with the latest start, the same
+// precedence GetActiveSchedule applies; assignments are ordered by start date
+func latestAssignmentOn(assignments []schedule.EmployeeScheduleAssignment, day time.Time) *schedule.EmployeeScheduleAssignment {
+	date := day.Format("2006-01-02")
+	var latest *schedule.EmployeeScheduleAssignment
+	for i := range assignments {
+		a := &assignments[i]
+		if a.StartDate.Format("2006-01-02") <= date && date <= a.EndDate.Format("2006-01-02") {
+			latest = a
+		}
+	}
+	return latest
+}
+
+type resolvedSchedule struct {
+	schedule schedule.WorkSchedule
+	times    map[int]schedule.WorkScheduleTime // keyed by ISO day of week
+}
+
+// scheduleDayResolver loads each schedule once while walking the range
+type scheduleDayResolver struct {
+	service   *scheduleServiceImpl
+	companyID string
+	schedules map[string]*resolvedSchedule
+}
+
+func (r *scheduleDayResolver) period(ctx context.Context, source, scheduleID string, day time.Time) (schedule.MySchedulePeriod, error) {
+	none := schedule.MySchedulePeriod{Source: schedule.ScheduleSourceNone}
+	if scheduleID == "" {
+		return none, nil
+	}
+
+	resolved, ok := r.schedules[scheduleID]
+	if !ok {
+		ws, err := r.service.workScheduleRepo.GetByID(ctx, scheduleID, r.companyID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return schedule.MySchedulePeriod{}, fmt.Errorf("failed to get work schedule: %w", err)
+		}
+		if err == nil {
+			times, err := r.service.workScheduleTimeRepo.GetByWorkScheduleID(ctx, scheduleID, r.companyID)
+			if err != nil {
+				return schedule.MySchedulePeriod{}, fmt.Errorf("failed to get work schedule times: %w", err)
+			}
+			resolved = &resolvedSchedule{schedule: ws, times: make(map[int]schedule.WorkScheduleTime, len(times))}
+			for _, t := range times {
+				resolved.times[t.DayOfWeek] = t
+			}
+		}
+		// A deleted schedule is cached as nil, so the days it covered show no schedule
+		r.schedules[scheduleID] = resolved
+	}
+	if resolved == nil {
+		return none, nil
+	}
+
+	period := schedule.MySchedulePeriod{
+		Source:           source,
+		WorkScheduleID:   &resolved.schedule.ID,
+		WorkScheduleName: &resolved.schedule.Name,
+	}
+
+	isoDay := int(day.Weekday())
+	if isoDay == 0 {
+		isoDay = 7
+	}
+	t, working := resolved.times[isoDay]
+	if !working {
+		return period, nil
+	}
+
+	period.IsWorkingDay = true
+	period.ClockInTime = formatClock(&t.ClockInTime)
+	period.ClockOutTime = formatClock(&t.ClockOutTime)
+	period.BreakStartTime = formatClock(t.BreakStartTime)
+	period.BreakEndTime = formatClock(t.BreakEndTime)
+	period.IsNextDayCheckout = t.IsNextDayCheckout
+	locationType := string(t.LocationType)
+	if locationType == "" {
+		locationType = string(resolved.schedule.Type)
+	}
+	period.LocationType = &locationType
+	return period, nil
+}
+
+func formatClock(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("15:04")
+	return &formatted
+}
+
+// samePeriodSchedule reports whether two days can be merged into one period
+func samePeriodSchedule(a, b schedule.MySchedulePeriod) bool {
+	return a.Source == b.Source &&
+		stringOrEmpty(a.WorkScheduleID) == stringOrEmpty(b.WorkScheduleID) &&
+		a.IsWorkingDay == b.IsWorkingDay &&
+		stringOrEmpty(a.ClockInTime) == stringOrEmpty(b.ClockInTime) &&
+		stringOrEmpty(a.ClockOutTime) == stringOrEmpty(b.ClockOutTime) &&
+		stringOrEmpty(a.BreakStartTime) == stringOrEmpty(b.BreakStartTime) &&
+		stringOrEmpty(a.BreakEndTime) == stringOrEmpty(b.BreakEndTime) &&
+		a.IsNextDayCheckout == b.IsNextDayCheckout &&
+		stringOrEmpty(a.LocationType) == stringOrEmpty(b.LocationType)
+}
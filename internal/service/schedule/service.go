@@ -1,16 +1,23 @@
 package schedule
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"errors"
 	"fmt"
-	"math"
+	"log/slog"
+	"strconv"
 	"time"
 
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/company"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/pagination"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/utils"
 	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/jackc/pgx/v5"
@@ -23,8 +30,20 @@ type scheduleServiceImpl struct {
 	workScheduleTimeRepo       schedule.WorkScheduleTimeRepository
 	workScheduleLocationRepo   schedule.WorkScheduleLocationRepository
 	employeeScheduleAssignRepo schedule.EmployeeScheduleAssignmentRepository
+	shiftSwapRepo              schedule.ShiftSwapRepository
 	employeeRepo               employee.EmployeeRepository
+	companyRepo                company.CompanyRepository
 	notificationService        notification.Service
+	clock                      clock.Clock
+}
+
+// companyLocation returns the company's configured timezone, or utils.DefaultTimezone if it cannot be read.
+func (s *scheduleServiceImpl) companyLocation(ctx context.Context, companyID string) *time.Location {
+	timezone, err := s.companyRepo.GetTimezone(ctx, companyID)
+	if err != nil {
+		return utils.LoadLocation("")
+	}
+	return utils.LoadLocation(timezone)
 }
 
 // AssignSchedule implements schedule.ScheduleService.
@@ -43,15 +62,31 @@ func (s *scheduleServiceImpl) AssignSchedule(ctx context.Context, req schedule.A
 		return schedule.AssignScheduleResponse{}, fmt.Errorf("company_id claim is missing or invalid")
 	}
 
+	warnings, err := s.checkScheduleCompleteness(ctx, req.WorkScheduleID, companyID)
+	if err != nil {
+		return schedule.AssignScheduleResponse{}, err
+	}
+
+	assignStart, _ := time.Parse("2006-01-02", req.StartDate)
+	var assignEnd *time.Time
+	if req.EndDate != nil && *req.EndDate != "" {
+		end, _ := time.Parse("2006-01-02", *req.EndDate)
+		assignEnd = &end
+	}
+	if err := s.checkWithinEmployment(ctx, req.EmployeeID, companyID, assignStart, assignEnd); err != nil {
+		return schedule.AssignScheduleResponse{}, err
+	}
+
 	var response = schedule.AssignScheduleResponse{
 		EmployeeID:     req.EmployeeID,
 		WorkScheduleID: req.WorkScheduleID,
 		StartDate:      &req.StartDate,
 		EndDate:        req.EndDate,
+		Warnings:       warnings,
 	}
 	if req.EndDate == nil || *req.EndDate == "" {
 		err := postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
-			txCtx := context.WithValue(ctx, "tx", tx)
+			txCtx := postgresql.ContextWithTx(ctx, tx)
 			if err := s.employeeRepo.UpdateSchedule(txCtx, req.EmployeeID, req.WorkScheduleID, companyID); err != nil {
 				return schedule.ErrInvalidRequestData
 			}
@@ -62,7 +97,9 @@ func (s *scheduleServiceImpl) AssignSchedule(ctx context.Context, req schedule.A
 					return fmt.Errorf("failed to delete future assignments: %w", err)
 				}
 			}
-			return nil
+
+			// Notify employee about schedule assignment/update
+			return s.enqueueScheduleUpdatedNotification(txCtx, req.EmployeeID, req.WorkScheduleID, companyID, req.StartDate)
 		})
 		if err != nil {
 			return schedule.AssignScheduleResponse{}, err
@@ -76,19 +113,31 @@ func (s *scheduleServiceImpl) AssignSchedule(ctx context.Context, req schedule.A
 			StartDate:      startDate,
 			EndDate:        endDate,
 		}
-		created, err := s.employeeScheduleAssignRepo.Create(ctx, assignment, companyID)
-		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
-				return schedule.AssignScheduleResponse{}, schedule.ErrInvalidRequestData
-			}
-			var pgErr *pgconn.PgError
-			if errors.As(err, &pgErr) {
-				// Check for exclusion violation (SQL state code '23P01')
-				if pgErr.Code == "23P01" && pgErr.ConstraintName == "no_overlapping_schedules" {
-					return schedule.AssignScheduleResponse{}, schedule.ErrOverlappingScheduleAssignment
+		var created schedule.EmployeeScheduleAssignment
+		err := postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
+			txCtx := postgresql.ContextWithTx(ctx, tx)
+
+			var err error
+			created, err = s.employeeScheduleAssignRepo.Create(txCtx, assignment, companyID)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return schedule.ErrInvalidRequestData
+				}
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) {
+					// Check for exclusion violation (SQL state code '23P01')
+					if pgErr.Code == "23P01" && pgErr.ConstraintName == "no_overlapping_schedules" {
+						return schedule.ErrOverlappingScheduleAssignment
+					}
 				}
+				return fmt.Errorf("failed to create employee schedule assignment: %w", err)
 			}
-			return schedule.AssignScheduleResponse{}, fmt.Errorf("failed to create employee schedule assignment: %w", err)
+
+			// Notify employee about schedule assignment/update
+			return s.enqueueScheduleUpdatedNotification(txCtx, req.EmployeeID, req.WorkScheduleID, companyID, req.StartDate)
+		})
+		if err != nil {
+			return schedule.AssignScheduleResponse{}, err
 		}
 		createdAt := created.CreatedAt.Format("2006-01-02T15:04:05Z")
 		updatedAt := created.UpdatedAt.Format("2006-01-02T15:04:05Z")
@@ -96,12 +145,74 @@ func (s *scheduleServiceImpl) AssignSchedule(ctx context.Context, req schedule.A
 		response.UpdatedAt = &updatedAt
 	}
 
-	// Notify employee about schedule assignment/update
-	go s.notifyEmployeeOnScheduleUpdated(ctx, req.EmployeeID, req.WorkScheduleID, companyID, req.StartDate)
-
 	return response, nil
 }
 
+// checkScheduleCompleteness rejects schedules without any time rows, since attendance would have
+// no expected clock-in, and returns a warning for each default working day the schedule does not cover
+func (s *scheduleServiceImpl) checkScheduleCompleteness(ctx context.Context, workScheduleID, companyID string) ([]string, error) {
+	if _, err := s.workScheduleRepo.GetByID(ctx, workScheduleID, companyID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, schedule.ErrWorkScheduleNotFound
+		}
+		return nil, fmt.Errorf("failed to get work schedule: %w", err)
+	}
+
+	times, err := s.workScheduleTimeRepo.GetByWorkScheduleID(ctx, workScheduleID, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work schedule times: %w", err)
+	}
+	if len(times) == 0 {
+		return nil, schedule.ErrIncompleteSchedule
+	}
+
+	covered := make(map[int]bool, len(times))
+	for _, t := range times {
+		covered[t.DayOfWeek] = true
+	}
+
+	var warnings []string
+	for _, day := range schedule.DefaultWorkingDays {
+		if !covered[day] {
+			warnings = append(warnings, fmt.Sprintf("work schedule has no times for %s", time.Weekday(day%7)))
+		}
+	}
+	if len(warnings) > 0 {
+		slog.Warn("Assigning work schedule that does not cover every working day", "work_schedule_id", workScheduleID, "missing", len(warnings))
+	}
+
+	return warnings, nil
+}
+
+// checkWithinEmployment rejects an assignment starting before the employee's hire date, or
+// starting or ending after their resignation date. endDate is nil for an open-ended assignment.
+func (s *scheduleServiceImpl) checkWithinEmployment(ctx context.Context, employeeID, companyID string, startDate time.Time, endDate *time.Time) error {
+	emp, err := s.employeeRepo.GetByID(ctx, employeeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return employee.ErrEmployeeNotFound
+		}
+		return fmt.Errorf("failed to get employee: %w", err)
+	}
+	if emp.CompanyID != companyID {
+		return employee.ErrEmployeeNotFound
+	}
+
+	// Compare calendar dates only, whatever time of day the values carry
+	const layout = "2006-01-02"
+	if startDate.Format(layout) < emp.HireDate.Format(layout) {
+		return schedule.ErrAssignmentOutsideEmployment
+	}
+	if emp.ResignationDate != nil {
+		resigned := emp.ResignationDate.Format(layout)
+		if startDate.Format(layout) > resigned || (endDate != nil && endDate.Format(layout) > resigned) {
+			return schedule.ErrAssignmentOutsideEmployment
+		}
+	}
+
+	return nil
+}
+
 // CreateEmployeeScheduleAssignment implements schedule.ScheduleService.
 func (s *scheduleServiceImpl) CreateEmployeeScheduleAssignment(ctx context.Context, req schedule.CreateEmployeeScheduleAssignmentRequest) (schedule.EmployeeScheduleAssignmentResponse, error) {
 	panic("unimplemented")
@@ -172,6 +283,9 @@ func (s *scheduleServiceImpl) CreateWorkSchedule(ctx context.Context, req schedu
 		Type:               schedule.WorkArrangement(req.Type),
 		GracePeriodMinutes: *req.GracePeriodMinutes,
 	}
+	if req.EarliestClockInMinutes != nil {
+		ws.EarliestClockInMinutes = *req.EarliestClockInMinutes
+	}
 
 	createdSchedule, err := s.workScheduleRepo.Create(ctx, ws)
 	if err != nil {
@@ -183,16 +297,98 @@ func (s *scheduleServiceImpl) CreateWorkSchedule(ctx context.Context, req schedu
 	}
 
 	return schedule.WorkScheduleResponse{
-		ID:                 createdSchedule.ID,
-		CompanyID:          createdSchedule.CompanyID,
-		Name:               createdSchedule.Name,
-		Type:               string(createdSchedule.Type),
-		GracePeriodMinutes: createdSchedule.GracePeriodMinutes,
-		CreatedAt:          createdSchedule.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:          createdSchedule.UpdatedAt.Format(time.RFC3339),
+		ID:                     createdSchedule.ID,
+		CompanyID:              createdSchedule.CompanyID,
+		Name:                   createdSchedule.Name,
+		Type:                   string(createdSchedule.Type),
+		GracePeriodMinutes:     createdSchedule.GracePeriodMinutes,
+		EarliestClockInMinutes: createdSchedule.EarliestClockInMinutes,
+		CreatedAt:              createdSchedule.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:              createdSchedule.UpdatedAt.Format(time.RFC3339),
 	}, nil
 }
 
+// CloneWorkSchedule implements schedule.ScheduleService.
+// Copies the schedule with all its times and locations under a new name in one transaction.
+func (s *scheduleServiceImpl) CloneWorkSchedule(ctx context.Context, sourceID string, newName string) (schedule.WorkScheduleResponse, error) {
+	req := schedule.CloneWorkScheduleRequest{Name: newName}
+	if err := req.Validate(); err != nil {
+		return schedule.WorkScheduleResponse{}, err
+	}
+
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return schedule.WorkScheduleResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return schedule.WorkScheduleResponse{}, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	source, err := s.workScheduleRepo.GetByID(ctx, sourceID, companyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return schedule.WorkScheduleResponse{}, schedule.ErrWorkScheduleNotFound
+		}
+		return schedule.WorkScheduleResponse{}, fmt.Errorf("failed to get work schedule: %w", err)
+	}
+
+	times, err := s.workScheduleTimeRepo.GetByWorkScheduleID(ctx, source.ID, companyID)
+	if err != nil {
+		return schedule.WorkScheduleResponse{}, fmt.Errorf("failed to get work schedule times: %w", err)
+	}
+
+	locations, err := s.workScheduleLocationRepo.GetByWorkScheduleID(ctx, source.ID, companyID)
+	if err != nil {
+		return schedule.WorkScheduleResponse{}, fmt.Errorf("failed to get work schedule locations: %w", err)
+	}
+
+	var cloneID string
+	err = postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		clone, err := s.workScheduleRepo.Create(txCtx, schedule.WorkSchedule{
+			CompanyID:              companyID,
+			Name:                   req.Name,
+			Type:                   source.Type,
+			GracePeriodMinutes:     source.GracePeriodMinutes,
+			EarliestClockInMinutes: source.EarliestClockInMinutes,
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+				return schedule.ErrWorkScheduleNameExists
+			}
+			return fmt.Errorf("failed to create work schedule: %w", err)
+		}
+		cloneID = clone.ID
+
+		for _, t := range times {
+			t.ID = ""
+			t.WorkScheduleID = clone.ID
+			if _, err := s.workScheduleTimeRepo.Create(txCtx, t, companyID); err != nil {
+				return fmt.Errorf("failed to copy work schedule time: %w", err)
+			}
+		}
+
+		for _, loc := range locations {
+			loc.ID = ""
+			loc.WorkScheduleID = clone.ID
+			if _, err := s.workScheduleLocationRepo.Create(txCtx, loc, companyID); err != nil {
+				return fmt.Errorf("failed to copy work schedule location: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return schedule.WorkScheduleResponse{}, err
+	}
+
+	return s.GetWorkSchedule(ctx, cloneID)
+}
+
 // CreateWorkScheduleLocation implements schedule.ScheduleService.
 func (s *scheduleServiceImpl) CreateWorkScheduleLocation(ctx context.Context, req schedule.CreateWorkScheduleLocationRequest) (schedule.WorkScheduleLocationResponse, error) {
 	if err := req.Validate(); err != nil {
@@ -506,15 +702,16 @@ func (s *scheduleServiceImpl) GetWorkSchedule(ctx context.Context, id string) (s
 	}
 
 	return schedule.WorkScheduleResponse{
-		ID:                 ws.ID,
-		CompanyID:          ws.CompanyID,
-		Name:               ws.Name,
-		Type:               string(ws.Type),
-		Times:              timeResponses,
-		Locations:          locationResponses,
-		GracePeriodMinutes: ws.GracePeriodMinutes,
-		CreatedAt:          ws.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:          ws.UpdatedAt.Format(time.RFC3339),
+		ID:                     ws.ID,
+		CompanyID:              ws.CompanyID,
+		Name:                   ws.Name,
+		Type:                   string(ws.Type),
+		Times:                  timeResponses,
+		Locations:              locationResponses,
+		GracePeriodMinutes:     ws.GracePeriodMinutes,
+		EarliestClockInMinutes: ws.EarliestClockInMinutes,
+		CreatedAt:              ws.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:              ws.UpdatedAt.Format(time.RFC3339),
 	}, nil
 }
 
@@ -608,15 +805,16 @@ func (s *scheduleServiceImpl) ListWorkSchedules(ctx context.Context, filter sche
 			locationResponse = append(locationResponse, s.mapWorkScheduleLocationToResponse(wsLocation))
 		}
 		workScheduleResponses = append(workScheduleResponses, schedule.WorkScheduleResponse{
-			ID:                 ws.ID,
-			CompanyID:          ws.CompanyID,
-			Name:               ws.Name,
-			Type:               string(ws.Type),
-			GracePeriodMinutes: ws.GracePeriodMinutes,
-			Times:              timeResponse,
-			Locations:          locationResponse,
-			CreatedAt:          ws.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:          ws.UpdatedAt.Format(time.RFC3339),
+			ID:                     ws.ID,
+			CompanyID:              ws.CompanyID,
+			Name:                   ws.Name,
+			Type:                   string(ws.Type),
+			GracePeriodMinutes:     ws.GracePeriodMinutes,
+			EarliestClockInMinutes: ws.EarliestClockInMinutes,
+			Times:                  timeResponse,
+			Locations:              locationResponse,
+			CreatedAt:              ws.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:              ws.UpdatedAt.Format(time.RFC3339),
 		})
 
 	}
@@ -634,26 +832,14 @@ func (s *scheduleServiceImpl) ListWorkSchedules(ctx context.Context, filter sche
 	}
 
 	// Calculate pagination metadata
-	totalPages := int(math.Ceil(float64(totalCount) / float64(filter.Limit)))
-
-	// Calculate "showing" text
-	start := (filter.Page-1)*filter.Limit + 1
-	end := start + len(workScheduleResponses) - 1
-	if end > int(totalCount) {
-		end = int(totalCount)
-	}
-
-	showing := fmt.Sprintf("%d-%d of %d results", start, end, totalCount)
-	if totalCount == 0 {
-		showing = "0 results"
-	}
+	meta := pagination.Meta(filter.Page, filter.Limit, totalCount)
 
 	return schedule.ListWorkScheduleResponse{
 		TotalCount:    totalCount,
 		Page:          filter.Page,
 		Limit:         filter.Limit,
-		TotalPages:    totalPages,
-		Showing:       showing,
+		TotalPages:    meta.TotalPages,
+		Showing:       meta.Showing,
 		WorkSchedules: workScheduleResponses,
 	}, nil
 }
@@ -674,6 +860,12 @@ func (s *scheduleServiceImpl) UpdateEmployeeScheduleAssignment(ctx context.Conte
 		return fmt.Errorf("company_id claim is missing or invalid")
 	}
 
+	startDate, _ := time.Parse("2006-01-02", req.StartDate)
+	endDate, _ := time.Parse("2006-01-02", req.EndDate)
+	if err := s.checkWithinEmployment(ctx, req.EmployeeID, companyID, startDate, &endDate); err != nil {
+		return err
+	}
+
 	err = s.employeeScheduleAssignRepo.Update(ctx, req, companyID)
 	if err != nil {
 		if errors.Is(err, schedule.ErrEmployeeScheduleAssignmentNotFound) {
@@ -707,8 +899,8 @@ func (s *scheduleServiceImpl) UpdateWorkSchedule(ctx context.Context, req schedu
 
 	req.CompanyID = companyID
 
-	postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+	return postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
 		ws, err := s.workScheduleRepo.Update(txCtx, req)
 		if err != nil {
 			if errors.Is(err, schedule.ErrWorkScheduleNotFound) {
@@ -723,16 +915,15 @@ func (s *scheduleServiceImpl) UpdateWorkSchedule(ctx context.Context, req schedu
 			return fmt.Errorf("failed to update work schedule: %w", err)
 		}
 
-		if req.Type != nil && ws.Type != schedule.WorkArrangementWFA && *req.Type == string(schedule.WorkArrangementWFA) {
-			if err := s.workScheduleLocationRepo.BulkDeleteByWorkScheduleID(txCtx, ws.ID, companyID); err != nil {
+		// ws already carries the new type, so decide on the request; WFA schedules have no locations
+		if req.Type != nil && *req.Type == string(schedule.WorkArrangementWFA) {
+			if _, err := s.workScheduleLocationRepo.BulkDeleteByWorkScheduleID(txCtx, ws.ID, companyID); err != nil {
 				return fmt.Errorf("failed to delete work schedule locations: %w", err)
 			}
 		}
 
 		return nil
 	})
-
-	return nil
 }
 
 // UpdateWorkScheduleLocation implements schedule.ScheduleService.
@@ -808,6 +999,18 @@ func (s *scheduleServiceImpl) UpdateWorkScheduleTime(ctx context.Context, req sc
 		return schedule.ErrMismatchedLocationType
 	}
 
+	// Moving to another day must not collide with that day's existing time row.
+	// The unique constraint below stays as a backstop for concurrent updates.
+	if req.DayOfWeek != nil && *req.DayOfWeek != wsTimeData.DayOfWeek {
+		existing, err := s.workScheduleTimeRepo.GetTimeByScheduleAndDay(ctx, wsTimeData.WorkScheduleID, *req.DayOfWeek, companyID)
+		if err == nil && existing.ID != wsTimeData.ID {
+			return schedule.ErrWorkScheduleTimeExists
+		}
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("failed to check work schedule time for day %d: %w", *req.DayOfWeek, err)
+		}
+	}
+
 	err = s.workScheduleTimeRepo.Update(ctx, req)
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -829,17 +1032,25 @@ func NewScheduleService(
 	workScheduleTimeRepo schedule.WorkScheduleTimeRepository,
 	workScheduleLocationRepo schedule.WorkScheduleLocationRepository,
 	employeeScheduleAssignRepo schedule.EmployeeScheduleAssignmentRepository,
+	shiftSwapRepo schedule.ShiftSwapRepository,
 	employeeRepo employee.EmployeeRepository,
+	companyRepo company.CompanyRepository,
 	notificationService notification.Service,
+	clk clock.Clock,
 ) schedule.ScheduleService {
+	// Without a notification service every notify call is skipped rather than failing
+	slog.Info("schedule notifications", "enabled", notificationService != nil)
 	return &scheduleServiceImpl{
 		db:                         db,
 		workScheduleRepo:           workScheduleRepo,
 		workScheduleTimeRepo:       workScheduleTimeRepo,
 		workScheduleLocationRepo:   workScheduleLocationRepo,
 		employeeScheduleAssignRepo: employeeScheduleAssignRepo,
+		shiftSwapRepo:              shiftSwapRepo,
 		employeeRepo:               employeeRepo,
+		companyRepo:                companyRepo,
 		notificationService:        notificationService,
+		clock:                      clk,
 	}
 }
 
@@ -924,22 +1135,12 @@ func (s *scheduleServiceImpl) GetEmployeeScheduleTimeline(ctx context.Context, e
 		return schedule.EmployeeScheduleTimelineResponse{}, fmt.Errorf("failed to get employee schedule timeline: %w", err)
 	}
 
-	// Calculate status and actions for each item
-	today := time.Now()
-	for i := range items {
-		// Calculate status
-		items[i].Status = s.calculateTimelineStatus(&items[i], today)
-
-		// Calculate is_active_today
-		items[i].IsActiveToday = s.isScheduleActiveToday(&items[i], today, items)
-
-		// Calculate actions
-		items[i].Actions = s.calculateTimelineActions(&items[i])
-	}
+	// Calculate status and actions for each item against today in the company's timezone
+	today := utils.StartOfDay(s.clock.Now(), s.companyLocation(ctx, companyID))
+	s.decorateTimelineItems(items, today)
 
 	// Calculate pagination metadata
-	totalPages := int(math.Ceil(float64(total) / float64(filter.Limit)))
-	showing := s.calculateShowingText(filter.Page, filter.Limit, total)
+	meta := pagination.Meta(filter.Page, filter.Limit, total)
 
 	response := schedule.EmployeeScheduleTimelineResponse{
 		EmployeeID:   employeeID,
@@ -947,14 +1148,109 @@ func (s *scheduleServiceImpl) GetEmployeeScheduleTimeline(ctx context.Context, e
 		TotalCount:   total,
 		Page:         filter.Page,
 		Limit:        filter.Limit,
-		TotalPages:   totalPages,
-		Showing:      showing,
+		TotalPages:   meta.TotalPages,
+		Showing:      meta.Showing,
 		Timeline:     items,
 	}
 
 	return response, nil
 }
 
+// ExportEmployeeTimeline implements schedule.ScheduleService.
+func (s *scheduleServiceImpl) ExportEmployeeTimeline(ctx context.Context, employeeID string, format string) ([]byte, error) {
+	// Extract company_id from JWT
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return nil, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	if format == "" {
+		format = schedule.ExportFormatCSV
+	}
+	if format != schedule.ExportFormatCSV {
+		return nil, schedule.ErrUnsupportedExportFormat
+	}
+
+	// Collect the whole history page by page
+	const pageSize = 100
+	var items []schedule.EmployeeScheduleTimelineItem
+	for page := 1; ; page++ {
+		pageItems, total, _, err := s.workScheduleRepo.GetEmployeeScheduleTimeline(ctx, employeeID, companyID, page, pageSize)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				if page > 1 {
+					break
+				}
+				return nil, schedule.ErrEmployeeScheduleTimelineNotFound
+			}
+			return nil, fmt.Errorf("failed to get employee schedule timeline: %w", err)
+		}
+		items = append(items, pageItems...)
+		if int64(len(items)) >= total {
+			break
+		}
+	}
+
+	today := utils.StartOfDay(s.clock.Now(), s.companyLocation(ctx, companyID))
+	s.decorateTimelineItems(items, today)
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	header := []string{"type", "status", "is_active_today", "start_date", "end_date", "schedule_name", "schedule_type", "grace_period_minutes"}
+	if err := writer.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	for _, item := range items {
+		record := []string{
+			item.Type,
+			item.Status,
+			strconv.FormatBool(item.IsActiveToday),
+			stringOrEmpty(item.DateRange.Start),
+			stringOrEmpty(item.DateRange.End),
+			item.ScheduleSnapshot.Name,
+			item.ScheduleSnapshot.Type,
+			strconv.Itoa(item.ScheduleSnapshot.GracePeriodMinutes),
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, fmt.Errorf("failed to write csv record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to flush csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decorateTimelineItems computes status, is_active_today and actions for timeline items
+func (s *scheduleServiceImpl) decorateTimelineItems(items []schedule.EmployeeScheduleTimelineItem, today time.Time) {
+	for i := range items {
+		// Calculate status
+		items[i].Status = s.calculateTimelineStatus(&items[i], today)
+
+		// Calculate is_active_today
+		items[i].IsActiveToday = s.isScheduleActiveToday(&items[i], today, items)
+
+		// Calculate actions
+		items[i].Actions = s.calculateTimelineActions(&items[i])
+	}
+}
+
+func stringOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
 // calculateTimelineStatus determines the status of a timeline item
 func (s *scheduleServiceImpl) calculateTimelineStatus(item *schedule.EmployeeScheduleTimelineItem, today time.Time) string {
 	if item.Type == "default" {
@@ -963,10 +1259,10 @@ func (s *scheduleServiceImpl) calculateTimelineStatus(item *schedule.EmployeeSch
 
 	// For override schedules
 	if item.DateRange.Start != nil {
-		startDate, _ := time.Parse("2006-01-02", *item.DateRange.Start)
+		startDate, _ := utils.ParseDateInLocation(*item.DateRange.Start, today.Location())
 
 		if item.DateRange.End != nil {
-			endDate, _ := time.Parse("2006-01-02", *item.DateRange.End)
+			endDate, _ := utils.ParseDateInLocation(*item.DateRange.End, today.Location())
 
 			if today.Before(startDate) {
 				return "upcoming"
@@ -1046,41 +1342,27 @@ func (s *scheduleServiceImpl) calculateTimelineActions(item *schedule.EmployeeSc
 	}
 }
 
-// calculateShowingText generates the "showing X-Y of Z results" text
-func (s *scheduleServiceImpl) calculateShowingText(page, limit int, total int64) string {
-	if total == 0 {
-		return "0-0 of 0 results"
-	}
-
-	start := (page-1)*limit + 1
-	end := start + limit - 1
-
-	if end > int(total) {
-		end = int(total)
-	}
-
-	return fmt.Sprintf("%d-%d of %d results", start, end, total)
-}
-
-// notifyEmployeeOnScheduleUpdated sends notification to employee when their schedule is assigned/updated
-func (s *scheduleServiceImpl) notifyEmployeeOnScheduleUpdated(ctx context.Context, employeeID, workScheduleID, companyID, startDate string) {
+// enqueueScheduleUpdatedNotification records the employee's schedule notification in the outbox.
+// ctx must carry the assignment transaction so the notification commits or rolls back with it.
+func (s *scheduleServiceImpl) enqueueScheduleUpdatedNotification(ctx context.Context, employeeID, workScheduleID, companyID, startDate string) error {
 	if s.notificationService == nil {
-		return
+		return nil
 	}
 
-	// Get employee user ID
 	emp, err := s.employeeRepo.GetByID(ctx, employeeID)
-	if err != nil || emp.UserID == nil {
-		return
+	if err != nil {
+		return fmt.Errorf("failed to get employee for notification: %w", err)
+	}
+	if emp.UserID == nil {
+		return nil
 	}
 
-	// Get work schedule name
 	ws, err := s.workScheduleRepo.GetByID(ctx, workScheduleID, companyID)
 	if err != nil {
-		return
+		return fmt.Errorf("failed to get work schedule for notification: %w", err)
 	}
 
-	_ = s.notificationService.QueueNotification(ctx, notification.CreateNotificationRequest{
+	return s.notificationService.Enqueue(ctx, notification.CreateNotificationRequest{
 		CompanyID:   companyID,
 		RecipientID: *emp.UserID,
 		SenderID:    nil,
@@ -0,0 +1,59 @@
+package schedule
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database/dbtest"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/testutil"
+)
+
+func TestUpdateWorkScheduleTransaction(t *testing.T) {
+	wfa := string(schedule.WorkArrangementWFA)
+	deleteErr := errors.New("connection reset")
+
+	tests := []struct {
+		name        string
+		updateErr   error
+		deleteErr   error
+		wantErr     error
+		wantDeleted []string
+		wantEnd     string
+	}{
+		{name: "switch to WFA removes locations", wantDeleted: []string{"ws-1"}, wantEnd: "commit"},
+		{name: "failed location cleanup surfaces and rolls back", deleteErr: deleteErr, wantErr: deleteErr, wantEnd: "rollback"},
+		{name: "missing schedule surfaces and rolls back", updateErr: schedule.ErrWorkScheduleNotFound, wantErr: schedule.ErrWorkScheduleNotFound, wantEnd: "rollback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, server := dbtest.NewDB(t, nil)
+			locations := &fakeWorkScheduleLocationRepo{deleteErr: tt.deleteErr}
+			s := &scheduleServiceImpl{
+				db:                       db,
+				workScheduleRepo:         &fakeWorkScheduleRepo{ws: schedule.WorkSchedule{ID: "ws-1", CompanyID: "company-1", Type: schedule.WorkArrangementWFO}, updateErr: tt.updateErr},
+				workScheduleLocationRepo: locations,
+			}
+			ctx := testutil.ClaimsContext(t, map[string]any{"company_id": "company-1"})
+
+			err := s.UpdateWorkSchedule(ctx, schedule.UpdateWorkScheduleRequest{ID: "ws-1", Type: &wfa})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("UpdateWorkSchedule() error = %v", err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateWorkSchedule() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(locations.deleted, tt.wantDeleted) {
+				t.Errorf("locations deleted for %v, want %v", locations.deleted, tt.wantDeleted)
+			}
+
+			statements := server.Statements()
+			if len(statements) != 2 || !strings.EqualFold(statements[1], tt.wantEnd) {
+				t.Errorf("statements = %q, want begin then %s", statements, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,364 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/user"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/utils"
+	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// RequestShiftSwap implements schedule.ScheduleService.
+func (s *scheduleServiceImpl) RequestShiftSwap(ctx context.Context, fromEmployeeID, toEmployeeID string, date time.Time) (schedule.ShiftSwapResponse, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return schedule.ShiftSwapResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return schedule.ShiftSwapResponse{}, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	if fromEmployeeID == toEmployeeID {
+		return schedule.ShiftSwapResponse{}, schedule.ErrShiftSwapSameEmployee
+	}
+
+	today := utils.StartOfDay(s.clock.Now(), s.companyLocation(ctx, companyID))
+	if date.Format("2006-01-02") < today.Format("2006-01-02") {
+		return schedule.ShiftSwapResponse{}, schedule.ErrShiftSwapDateInPast
+	}
+
+	for _, employeeID := range []string{fromEmployeeID, toEmployeeID} {
+		if err := s.ensureEmployeeInCompany(ctx, employeeID, companyID); err != nil {
+			return schedule.ShiftSwapResponse{}, err
+		}
+	}
+
+	swap := schedule.ShiftSwapRequest{
+		CompanyID:      companyID,
+		FromEmployeeID: fromEmployeeID,
+		ToEmployeeID:   toEmployeeID,
+		SwapDate:       date,
+	}
+
+	// Reject early if the swap could never be approved
+	if _, _, err := s.resolveShiftSwapSchedules(ctx, swap); err != nil {
+		return schedule.ShiftSwapResponse{}, err
+	}
+
+	if userID, ok := claims["user_id"].(string); ok && userID != "" {
+		swap.RequestedBy = &userID
+	}
+
+	created, err := s.shiftSwapRepo.Create(ctx, swap)
+	if err != nil {
+		return schedule.ShiftSwapResponse{}, err
+	}
+
+	created, err = s.shiftSwapRepo.GetByID(ctx, created.ID, companyID)
+	if err != nil {
+		return schedule.ShiftSwapResponse{}, err
+	}
+
+	// Use context.WithoutCancel to prevent cancellation when HTTP request ends
+	go s.notifyOnShiftSwapRequested(context.WithoutCancel(ctx), created)
+
+	return s.mapShiftSwapToResponse(created), nil
+}
+
+// ApproveShiftSwap implements schedule.ScheduleService.
+func (s *scheduleServiceImpl) ApproveShiftSwap(ctx context.Context, id string) (schedule.ShiftSwapResponse, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return schedule.ShiftSwapResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return schedule.ShiftSwapResponse{}, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok || userID == "" {
+		return schedule.ShiftSwapResponse{}, fmt.Errorf("user_id claim is missing or invalid")
+	}
+
+	swap, err := s.shiftSwapRepo.GetByID(ctx, id, companyID)
+	if err != nil {
+		return schedule.ShiftSwapResponse{}, err
+	}
+	if swap.Status != schedule.ShiftSwapStatusPending {
+		return schedule.ShiftSwapResponse{}, schedule.ErrShiftSwapAlreadyProcessed
+	}
+
+	err = postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		// Schedules may have changed since the request was made, so resolve them again
+		fromSchedule, toSchedule, err := s.resolveShiftSwapSchedules(txCtx, swap)
+		if err != nil {
+			return err
+		}
+
+		overrides := []schedule.EmployeeScheduleAssignment{
+			{EmployeeID: swap.FromEmployeeID, WorkScheduleID: toSchedule.ID, StartDate: swap.SwapDate, EndDate: swap.SwapDate},
+			{EmployeeID: swap.ToEmployeeID, WorkScheduleID: fromSchedule.ID, StartDate: swap.SwapDate, EndDate: swap.SwapDate},
+		}
+		for _, override := range overrides {
+			if _, err := s.employeeScheduleAssignRepo.Create(txCtx, override, companyID); err != nil {
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.Code == "23P01" {
+					return schedule.ErrShiftSwapOverlap
+				}
+				return fmt.Errorf("failed to create shift swap assignment: %w", err)
+			}
+		}
+
+		swap.Status = schedule.ShiftSwapStatusApproved
+		swap.ReviewedBy = &userID
+		return s.shiftSwapRepo.Review(txCtx, swap)
+	})
+	if err != nil {
+		return schedule.ShiftSwapResponse{}, err
+	}
+
+	swap, err = s.shiftSwapRepo.GetByID(ctx, id, companyID)
+	if err != nil {
+		return schedule.ShiftSwapResponse{}, err
+	}
+
+	// Use context.WithoutCancel to prevent cancellation when HTTP request ends
+	go s.notifyOnShiftSwapReviewed(context.WithoutCancel(ctx), swap)
+
+	return s.mapShiftSwapToResponse(swap), nil
+}
+
+// RejectShiftSwap implements schedule.ScheduleService.
+func (s *scheduleServiceImpl) RejectShiftSwap(ctx context.Context, id string, reason string) (schedule.ShiftSwapResponse, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return schedule.ShiftSwapResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return schedule.ShiftSwapResponse{}, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok || userID == "" {
+		return schedule.ShiftSwapResponse{}, fmt.Errorf("user_id claim is missing or invalid")
+	}
+
+	swap, err := s.shiftSwapRepo.GetByID(ctx, id, companyID)
+	if err != nil {
+		return schedule.ShiftSwapResponse{}, err
+	}
+	if swap.Status != schedule.ShiftSwapStatusPending {
+		return schedule.ShiftSwapResponse{}, schedule.ErrShiftSwapAlreadyProcessed
+	}
+
+	swap.Status = schedule.ShiftSwapStatusRejected
+	swap.ReviewedBy = &userID
+	if reason != "" {
+		swap.RejectionReason = &reason
+	}
+	if err := s.shiftSwapRepo.Review(ctx, swap); err != nil {
+		return schedule.ShiftSwapResponse{}, err
+	}
+
+	swap, err = s.shiftSwapRepo.GetByID(ctx, id, companyID)
+	if err != nil {
+		return schedule.ShiftSwapResponse{}, err
+	}
+
+	// Use context.WithoutCancel to prevent cancellation when HTTP request ends
+	go s.notifyOnShiftSwapReviewed(context.WithoutCancel(ctx), swap)
+
+	return s.mapShiftSwapToResponse(swap), nil
+}
+
+// ListShiftSwaps implements schedule.ScheduleService.
+func (s *scheduleServiceImpl) ListShiftSwaps(ctx context.Context, filter schedule.ShiftSwapFilter) ([]schedule.ShiftSwapResponse, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return nil, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	// Employees only see swaps they are part of
+	if role, _ := claims["role"].(string); user.Role(role) == user.RoleEmployee {
+		employeeID, ok := claims["employee_id"].(string)
+		if !ok || employeeID == "" {
+			return nil, employee.ErrEmployeeNotFound
+		}
+		filter.EmployeeID = &employeeID
+	}
+
+	swaps, err := s.shiftSwapRepo.List(ctx, companyID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]schedule.ShiftSwapResponse, 0, len(swaps))
+	for _, swap := range swaps {
+		responses = append(responses, s.mapShiftSwapToResponse(swap))
+	}
+
+	return responses, nil
+}
+
+// ensureEmployeeInCompany returns employee.ErrEmployeeNotFound unless the employee belongs to the company
+func (s *scheduleServiceImpl) ensureEmployeeInCompany(ctx context.Context, employeeID, companyID string) error {
+	emp, err := s.employeeRepo.GetByID(ctx, employeeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return employee.ErrEmployeeNotFound
+		}
+		return fmt.Errorf("failed to get employee: %w", err)
+	}
+	if emp.CompanyID != companyID {
+		return employee.ErrEmployeeNotFound
+	}
+	return nil
+}
+
+// resolveShiftSwapSchedules returns the schedules both employees would work on the swap date.
+// Either employee already having a dated assignment on that day is treated as an overlap,
+// because the single-day override created on approval would collide with it.
+func (s *scheduleServiceImpl) resolveShiftSwapSchedules(ctx context.Context, swap schedule.ShiftSwapRequest) (schedule.WorkSchedule, schedule.WorkSchedule, error) {
+	schedules := make([]schedule.WorkSchedule, 0, 2)
+	for _, employeeID := range []string{swap.FromEmployeeID, swap.ToEmployeeID} {
+		assignments, err := s.employeeScheduleAssignRepo.GetScheduleAssignments(ctx, employeeID, swap.SwapDate, swap.SwapDate)
+		if err != nil {
+			return schedule.WorkSchedule{}, schedule.WorkSchedule{}, fmt.Errorf("failed to get schedule assignments: %w", err)
+		}
+		if len(assignments) > 0 {
+			return schedule.WorkSchedule{}, schedule.WorkSchedule{}, schedule.ErrShiftSwapOverlap
+		}
+
+		ws, err := s.employeeScheduleAssignRepo.GetActiveSchedule(ctx, employeeID, swap.SwapDate)
+		if err != nil {
+			return schedule.WorkSchedule{}, schedule.WorkSchedule{}, schedule.ErrWorkScheduleNotFound
+		}
+		schedules = append(schedules, ws)
+	}
+
+	if schedules[0].ID == schedules[1].ID {
+		return schedule.WorkSchedule{}, schedule.WorkSchedule{}, schedule.ErrShiftSwapSameSchedule
+	}
+
+	return schedules[0], schedules[1], nil
+}
+
+func (s *scheduleServiceImpl) mapShiftSwapToResponse(swap schedule.ShiftSwapRequest) schedule.ShiftSwapResponse {
+	resp := schedule.ShiftSwapResponse{
+		ID:               swap.ID,
+		FromEmployeeID:   swap.FromEmployeeID,
+		FromEmployeeName: swap.FromEmployeeName,
+		ToEmployeeID:     swap.ToEmployeeID,
+		ToEmployeeName:   swap.ToEmployeeName,
+		Date:             swap.SwapDate.Format("2006-01-02"),
+		Status:           string(swap.Status),
+		ReviewedBy:       swap.ReviewedBy,
+		RejectionReason:  swap.RejectionReason,
+		CreatedAt:        swap.CreatedAt.Format(time.RFC3339),
+	}
+	if swap.ReviewedAt != nil {
+		reviewedAt := swap.ReviewedAt.Format(time.RFC3339)
+		resp.ReviewedAt = &reviewedAt
+	}
+	return resp
+}
+
+// notifyOnShiftSwapRequested tells the colleague that someone asked to swap shifts with them
+func (s *scheduleServiceImpl) notifyOnShiftSwapRequested(ctx context.Context, swap schedule.ShiftSwapRequest) {
+	if s.notificationService == nil {
+		return
+	}
+
+	emp, err := s.employeeRepo.GetByID(ctx, swap.ToEmployeeID)
+	if err != nil || emp.UserID == nil {
+		return
+	}
+
+	requester := "A colleague"
+	if swap.FromEmployeeName != nil {
+		requester = *swap.FromEmployeeName
+	}
+	date := swap.SwapDate.Format("2006-01-02")
+
+	_ = s.notificationService.QueueNotification(ctx, notification.CreateNotificationRequest{
+		CompanyID:   swap.CompanyID,
+		RecipientID: *emp.UserID,
+		SenderID:    swap.RequestedBy,
+		Type:        notification.TypeShiftSwapRequested,
+		Title:       "Shift Swap Requested",
+		Message:     fmt.Sprintf("%s requested to swap shifts with you on %s", requester, date),
+		Data: map[string]interface{}{
+			"shift_swap_id":    swap.ID,
+			"from_employee_id": swap.FromEmployeeID,
+			"to_employee_id":   swap.ToEmployeeID,
+			"date":             date,
+		},
+	})
+}
+
+// notifyOnShiftSwapReviewed tells both employees that the swap was approved or rejected
+func (s *scheduleServiceImpl) notifyOnShiftSwapReviewed(ctx context.Context, swap schedule.ShiftSwapRequest) {
+	if s.notificationService == nil {
+		return
+	}
+
+	notifType := notification.TypeShiftSwapApproved
+	title := "Shift Swap Approved"
+	verb := "approved"
+	if swap.Status == schedule.ShiftSwapStatusRejected {
+		notifType = notification.TypeShiftSwapRejected
+		title = "Shift Swap Rejected"
+		verb = "rejected"
+	}
+	date := swap.SwapDate.Format("2006-01-02")
+
+	message := fmt.Sprintf("Your shift swap on %s has been %s", date, verb)
+	if swap.RejectionReason != nil && *swap.RejectionReason != "" {
+		message = fmt.Sprintf("%s: %s", message, *swap.RejectionReason)
+	}
+
+	for _, employeeID := range []string{swap.FromEmployeeID, swap.ToEmployeeID} {
+		emp, err := s.employeeRepo.GetByID(ctx, employeeID)
+		if err != nil || emp.UserID == nil {
+			continue
+		}
+
+		_ = s.notificationService.QueueNotification(ctx, notification.CreateNotificationRequest{
+			CompanyID:   swap.CompanyID,
+			RecipientID: *emp.UserID,
+			SenderID:    swap.ReviewedBy,
+			Type:        notifType,
+			Title:       title,
+			Message:     message,
+			Data: map[string]interface{}{
+				"shift_swap_id":    swap.ID,
+				"from_employee_id": swap.FromEmployeeID,
+				"to_employee_id":   swap.ToEmployeeID,
+				"date":             date,
+				"status":           string(swap.Status),
+			},
+		})
+	}
+}
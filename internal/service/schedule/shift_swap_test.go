@@ -0,0 +1,44 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/employee"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/testutil"
+)
+
+func TestRequestShiftSwapDateInThePast(t *testing.T) {
+	// 00:30 on 5 May in Jakarta, still 4 May in UTC
+	now := time.Date(2026, time.May, 4, 17, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		date    time.Time
+		wantErr error
+	}{
+		{name: "yesterday", date: time.Date(2026, time.May, 3, 0, 0, 0, 0, time.UTC), wantErr: schedule.ErrShiftSwapDateInPast},
+		{name: "today in UTC is yesterday in the company timezone", date: time.Date(2026, time.May, 4, 0, 0, 0, 0, time.UTC), wantErr: schedule.ErrShiftSwapDateInPast},
+		// Past the date check, the unknown employees are rejected next
+		{name: "today in the company timezone", date: time.Date(2026, time.May, 5, 0, 0, 0, 0, time.UTC), wantErr: employee.ErrEmployeeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &scheduleServiceImpl{
+				companyRepo:  &fakeCompanyRepo{timezone: "Asia/Jakarta"},
+				employeeRepo: &fakeEmployeeRepo{},
+				clock:        clock.NewFake(now),
+			}
+			ctx := testutil.ClaimsContext(t, map[string]any{"company_id": "company-1"})
+
+			_, err := s.RequestShiftSwap(ctx, "emp-1", "emp-2", tt.date)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RequestShiftSwap() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
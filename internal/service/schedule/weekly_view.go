@@ -0,0 +1,83 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/schedule"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetWeeklyView implements schedule.ScheduleService.
+// Groups the schedule's time rows by weekday and marks the days without any as off.
+func (s *scheduleServiceImpl) GetWeeklyView(ctx context.Context, scheduleID string) (schedule.WeeklyScheduleResponse, error) {
+	_, claims, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return schedule.WeeklyScheduleResponse{}, fmt.Errorf("failed to extract claims from context: %w", err)
+	}
+
+	companyID, ok := claims["company_id"].(string)
+	if !ok || companyID == "" {
+		return schedule.WeeklyScheduleResponse{}, fmt.Errorf("company_id claim is missing or invalid")
+	}
+
+	ws, err := s.workScheduleRepo.GetByID(ctx, scheduleID, companyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return schedule.WeeklyScheduleResponse{}, schedule.ErrWorkScheduleNotFound
+		}
+		return schedule.WeeklyScheduleResponse{}, fmt.Errorf("failed to get work schedule: %w", err)
+	}
+
+	times, err := s.workScheduleTimeRepo.GetByWorkScheduleID(ctx, ws.ID, companyID)
+	if err != nil {
+		return schedule.WeeklyScheduleResponse{}, fmt.Errorf("failed to get work schedule times: %w", err)
+	}
+
+	return s.buildWeeklyView(ws, times), nil
+}
+
+// buildWeeklyView places each time row on its weekday, ordered by clock-in, and fills the rest of
+// the week with off days. A day's location type is that of its first block.
+func (s *scheduleServiceImpl) buildWeeklyView(ws schedule.WorkSchedule, times []schedule.WorkScheduleTime) schedule.WeeklyScheduleResponse {
+	byDay := make(map[int][]schedule.WorkScheduleTime, 7)
+	for _, t := range times {
+		byDay[t.DayOfWeek] = append(byDay[t.DayOfWeek], t)
+	}
+
+	days := make([]schedule.WeeklyScheduleDay, 0, 7)
+	for dayOfWeek := 1; dayOfWeek <= 7; dayOfWeek++ {
+		// ISO weekday 7 is Sunday, which time.Weekday numbers 0
+		day := schedule.WeeklyScheduleDay{
+			DayOfWeek: dayOfWeek,
+			DayName:   time.Weekday(dayOfWeek % 7).String(),
+			IsOff:     len(byDay[dayOfWeek]) == 0,
+			Times:     []schedule.WorkScheduleTimeResponse{},
+		}
+
+		blocks := byDay[dayOfWeek]
+		sort.SliceStable(blocks, func(i, j int) bool {
+			return blocks[i].ClockInTime.Before(blocks[j].ClockInTime)
+		})
+		for _, t := range blocks {
+			day.Times = append(day.Times, s.mapWorkScheduleTimeToResponse(t))
+		}
+		if !day.IsOff {
+			locationType := string(blocks[0].LocationType)
+			day.LocationType = &locationType
+		}
+
+		days = append(days, day)
+	}
+
+	return schedule.WeeklyScheduleResponse{
+		WorkScheduleID: ws.ID,
+		Name:           ws.Name,
+		Type:           string(ws.Type),
+		Days:           days,
+	}
+}
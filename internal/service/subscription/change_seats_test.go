@@ -0,0 +1,108 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/config"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database/dbtest"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+)
+
+// newSeatTestService returns a service with one active 5-seat Standard subscription for company-1
+func newSeatTestService(t *testing.T, invoices *fakeInvoiceRepo, maxRetries int) (*subscriptionService, *fakePaymentProvider, *dbtest.Server) {
+	t.Helper()
+	db, server := dbtest.NewDB(t, nil)
+	db.TxRetry = database.TxRetryPolicy{MaxRetries: maxRetries}
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	provider := &fakePaymentProvider{}
+	s := &subscriptionService{
+		planRepo: &fakePlanRepo{plans: []subscription.Plan{
+			{ID: "plan-standard", Name: "Standard", PricePerSeat: decimal.NewFromInt(30000), Currency: subscription.CurrencyIDR},
+		}},
+		subscriptionRepo: &fakeSubscriptionRepo{subs: map[string]subscription.Subscription{
+			"company-1": {ID: "sub-1", CompanyID: "company-1", PlanID: "plan-standard", Status: subscription.StatusActive, MaxSeats: 5,
+				CurrentPeriodStart: start, CurrentPeriodEnd: start.AddDate(0, 1, 0), BillingCycle: subscription.BillingCycleMonthly},
+		}},
+		invoiceRepo:     invoices,
+		paymentProvider: provider,
+		db:              db,
+		cfg:             &config.Config{},
+		clock:           clock.NewFake(start.AddDate(0, 0, 10)),
+	}
+	return s, provider, server
+}
+
+func TestChangeSeatsRetriesSerializationFailure(t *testing.T) {
+	conflict := &pgconn.PgError{Code: "40001", Message: "could not serialize access due to concurrent update"}
+	invoices := &fakeInvoiceRepo{createErrs: []error{conflict}}
+	s, provider, server := newSeatTestService(t, invoices, 2)
+
+	resp, err := s.ChangeSeats(context.Background(), "company-1", subscription.ChangeSeatRequest{SeatCount: 8})
+	if err != nil {
+		t.Fatalf("ChangeSeats() error = %v", err)
+	}
+	if resp.Invoice == nil || len(invoices.created) != 1 {
+		t.Fatalf("ChangeSeats() = %+v, want one stored invoice", resp)
+	}
+
+	if len(provider.created) != 1 {
+		t.Errorf("provider invoices created = %d, want 1 reused by the retry", len(provider.created))
+	}
+	if len(provider.expired) != 0 {
+		t.Errorf("provider invoices expired = %v, want none", provider.expired)
+	}
+	if got := invoices.created[0].XenditInvoiceID; got == nil || *got != "provider-1" {
+		t.Errorf("stored provider invoice ID = %v, want provider-1", got)
+	}
+	// The retry allocates a fresh number; the aborted attempt's was rolled back
+	if got := invoices.created[0].InvoiceNumber; !strings.HasSuffix(got, "2") {
+		t.Errorf("invoice number = %q, want the second allocation", got)
+	}
+
+	statements := strings.ToLower(strings.Join(server.Statements(), ";"))
+	if statements != "begin;rollback;begin;commit" {
+		t.Errorf("statements = %q, want one rolled back attempt and one committed", statements)
+	}
+}
+
+func TestChangeSeatsGivesUpAfterRetries(t *testing.T) {
+	conflict := &pgconn.PgError{Code: "40001", Message: "could not serialize access due to concurrent update"}
+	invoices := &fakeInvoiceRepo{createErrs: []error{conflict, conflict, conflict}}
+	s, provider, _ := newSeatTestService(t, invoices, 2)
+
+	_, err := s.ChangeSeats(context.Background(), "company-1", subscription.ChangeSeatRequest{SeatCount: 8})
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "40001" {
+		t.Fatalf("ChangeSeats() error = %v, want the serialization failure", err)
+	}
+	if len(provider.created) != 1 {
+		t.Errorf("provider invoices created = %d, want 1", len(provider.created))
+	}
+	if len(provider.expired) != 1 || provider.expired[0] != "provider-1" {
+		t.Errorf("provider invoices expired = %v, want the orphaned provider-1", provider.expired)
+	}
+}
+
+func TestChangeSeatsDoesNotRetryOtherErrors(t *testing.T) {
+	invoices := &fakeInvoiceRepo{createErrs: []error{errors.New("connection reset")}}
+	s, provider, server := newSeatTestService(t, invoices, 2)
+
+	if _, err := s.ChangeSeats(context.Background(), "company-1", subscription.ChangeSeatRequest{SeatCount: 8}); err == nil {
+		t.Fatal("ChangeSeats() error = nil")
+	}
+	if got := len(server.Statements()); got != 2 {
+		t.Errorf("statements = %q, want a single attempt", server.Statements())
+	}
+	if len(provider.expired) != 1 {
+		t.Errorf("provider invoices expired = %v, want 1", provider.expired)
+	}
+}
@@ -0,0 +1,161 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/config"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database/dbtest"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/xendit"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/xendit/xendittest"
+	"github.com/shopspring/decimal"
+)
+
+const testCallbackToken = "callback-token"
+
+// newCheckoutTestService returns a service billing through the real Xendit provider backed by
+// an in-memory client. company-1 has an active 5-seat Standard subscription and 3 employees.
+func newCheckoutTestService(t *testing.T, invoices *fakeInvoiceRepo) (*subscriptionService, *xendittest.Client, *fakeSubscriptionRepo) {
+	t.Helper()
+	db, _ := dbtest.NewDB(t, nil)
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start.AddDate(0, 1, 0))
+	client := xendittest.NewClient(clk.Now)
+	subs := &fakeSubscriptionRepo{subs: map[string]subscription.Subscription{
+		"company-1": {ID: "sub-1", CompanyID: "company-1", PlanID: "plan-standard", Status: subscription.StatusActive, MaxSeats: 5,
+			CurrentPeriodStart: start, CurrentPeriodEnd: start.AddDate(0, 1, 0), BillingCycle: subscription.BillingCycleMonthly},
+	}}
+
+	s := &subscriptionService{
+		planRepo: &fakePlanRepo{plans: []subscription.Plan{
+			{ID: "plan-standard", Name: "Standard", PricePerSeat: decimal.NewFromInt(30000), Currency: subscription.CurrencyIDR},
+		}},
+		subscriptionRepo: subs,
+		invoiceRepo:      invoices,
+		employeeCounter:  &fakeEmployeeCounter{active: 3},
+		paymentProvider:  xendit.NewProvider(client, xendit.NewWebhookVerifier(testCallbackToken)),
+		db:               db,
+		cfg: &config.Config{Xendit: config.XenditConfig{
+			InvoiceExpiry:   24,
+			SuccessRedirect: "https://app.example/billing/success",
+			FailureRedirect: "https://app.example/billing/failed",
+		}},
+		clock: clk,
+	}
+	return s, client, subs
+}
+
+var testCheckout = subscription.CheckoutRequest{
+	PlanID:       "plan-standard",
+	SeatCount:    10,
+	BillingCycle: subscription.BillingCycleMonthly,
+	PayerEmail:   "owner@example.com",
+}
+
+func TestCheckoutThroughXendit(t *testing.T) {
+	invoices := &fakeInvoiceRepo{}
+	s, client, subs := newCheckoutTestService(t, invoices)
+
+	resp, err := s.Checkout(context.Background(), "company-1", testCheckout)
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	sent := client.Created()
+	if len(sent) != 1 {
+		t.Fatalf("Xendit invoices requested = %d, want 1", len(sent))
+	}
+	if !sent[0].Amount.Equal(decimal.NewFromInt(300000)) || sent[0].Currency != "IDR" {
+		t.Errorf("Xendit invoice amount = %s %s, want 300000 IDR", sent[0].Amount, sent[0].Currency)
+	}
+	if sent[0].InvoiceDuration != 24*3600 || sent[0].PayerEmail != testCheckout.PayerEmail {
+		t.Errorf("Xendit invoice duration %d, payer %q", sent[0].InvoiceDuration, sent[0].PayerEmail)
+	}
+	if sent[0].SuccessRedirectURL != "https://app.example/billing/success" {
+		t.Errorf("Xendit success redirect = %q", sent[0].SuccessRedirectURL)
+	}
+
+	if len(invoices.created) != 1 {
+		t.Fatalf("stored invoices = %d, want 1", len(invoices.created))
+	}
+	stored := invoices.created[0]
+	if stored.XenditInvoiceID == nil || *stored.XenditInvoiceID != "xnd-inv-1" {
+		t.Errorf("stored Xendit invoice ID = %v, want xnd-inv-1", stored.XenditInvoiceID)
+	}
+	if resp.PaymentURL == nil || *resp.PaymentURL != "https://checkout.xendit.test/xnd-inv-1" {
+		t.Errorf("checkout URL = %v", resp.PaymentURL)
+	}
+
+	// The customer pays and Xendit calls back
+	body, err := client.Pay("xnd-inv-1", "BANK_TRANSFER", "BCA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	callback, err := s.paymentProvider.VerifyCallback(http.Header{"X-Callback-Token": {testCallbackToken}}, body)
+	if err != nil {
+		t.Fatalf("VerifyCallback() error = %v", err)
+	}
+	if err := s.HandleWebhook(context.Background(), callback); err != nil {
+		t.Fatalf("HandleWebhook() error = %v", err)
+	}
+
+	paid := invoices.created[0]
+	if paid.Status != subscription.InvoiceStatusPaid || paid.PaymentChannel == nil || *paid.PaymentChannel != "BCA" {
+		t.Errorf("invoice after payment = %s via %v, want paid via BCA", paid.Status, paid.PaymentChannel)
+	}
+	if sub := subs.subs["company-1"]; sub.MaxSeats != 10 || !sub.CurrentPeriodStart.Equal(stored.PeriodStart) {
+		t.Errorf("subscription after payment has %d seats from %s, want 10 from %s", sub.MaxSeats, sub.CurrentPeriodStart, stored.PeriodStart)
+	}
+}
+
+func TestCheckoutExpiresXenditInvoiceWhenStoringFails(t *testing.T) {
+	storeErr := errors.New("disk full")
+	invoices := &fakeInvoiceRepo{createErrs: []error{storeErr}}
+	s, client, _ := newCheckoutTestService(t, invoices)
+
+	// A cancelled request must still clean up the invoice it already created at Xendit
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.invoiceRepo = &cancellingInvoiceRepo{fakeInvoiceRepo: invoices, cancel: cancel}
+
+	if _, err := s.Checkout(ctx, "company-1", testCheckout); !errors.Is(err, storeErr) {
+		t.Fatalf("Checkout() error = %v, want %v", err, storeErr)
+	}
+
+	if expired := client.Expired(); len(expired) != 1 || expired[0] != "xnd-inv-1" {
+		t.Fatalf("Xendit invoices expired = %v, want [xnd-inv-1]", expired)
+	}
+	if inv, _ := client.Invoice("xnd-inv-1"); inv.Status != xendit.InvoiceStatusExpired {
+		t.Errorf("Xendit invoice status = %s, want %s", inv.Status, xendit.InvoiceStatusExpired)
+	}
+}
+
+func TestCheckoutDoesNotStoreInvoiceXenditRejected(t *testing.T) {
+	invoices := &fakeInvoiceRepo{}
+	s, client, _ := newCheckoutTestService(t, invoices)
+	client.CreateErr = errors.New("API_VALIDATION_ERROR")
+
+	if _, err := s.Checkout(context.Background(), "company-1", testCheckout); !errors.Is(err, client.CreateErr) {
+		t.Fatalf("Checkout() error = %v, want %v", err, client.CreateErr)
+	}
+	if len(invoices.created) != 0 || invoices.numbers != 0 {
+		t.Errorf("stored %d invoices and allocated %d numbers, want none", len(invoices.created), invoices.numbers)
+	}
+}
+
+// cancellingInvoiceRepo cancels the request as the insert fails, like a client disconnecting
+type cancellingInvoiceRepo struct {
+	*fakeInvoiceRepo
+	cancel context.CancelFunc
+}
+
+func (r *cancellingInvoiceRepo) Create(ctx context.Context, invoice subscription.Invoice) (subscription.Invoice, error) {
+	defer r.cancel()
+	return r.fakeInvoiceRepo.Create(ctx, invoice)
+}
@@ -0,0 +1,80 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+)
+
+// sendDunningNotice asks the owners to pay again after an invoice expired or its payment failed.
+// Each invoice gets at most one notice no matter how often the provider redelivers the webhook.
+func (s *subscriptionService) sendDunningNotice(ctx context.Context, invoice subscription.Invoice, status subscription.InvoiceStatus) {
+	claimed, err := s.invoiceRepo.MarkDunningSent(ctx, invoice.ID)
+	if err != nil {
+		log.Printf("Failed to mark dunning notice for invoice %s: %v", invoice.ID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	owners, err := s.billingContacts.ListOwnersByCompanyID(ctx, invoice.CompanyID)
+	if err != nil {
+		log.Printf("Failed to get owners of company %s: %v", invoice.CompanyID, err)
+		return
+	}
+
+	amount := invoice.Currency.Format(invoice.Amount)
+	checkoutLink := fmt.Sprintf("%s/subscription", s.cfg.App.FrontendURL)
+
+	notificationType := notification.TypePaymentFailed
+	title := "Payment Failed"
+	message := fmt.Sprintf("Payment of %s for invoice %s (%s) failed. Please try again with another payment method.",
+		amount, invoice.InvoiceNumber, invoice.PlanSnapshotName)
+	if status == subscription.InvoiceStatusExpired {
+		notificationType = notification.TypeInvoiceExpired
+		title = "Invoice Expired"
+		message = fmt.Sprintf("Invoice %s (%s, %s) expired before it was paid. Start a new checkout to keep your subscription running.",
+			invoice.InvoiceNumber, invoice.PlanSnapshotName, amount)
+	}
+
+	for _, owner := range owners {
+		if s.notificationSvc != nil {
+			_ = s.notificationSvc.QueueNotification(ctx, notification.CreateNotificationRequest{
+				CompanyID:   invoice.CompanyID,
+				RecipientID: owner.UserID,
+				Type:        notificationType,
+				Title:       title,
+				Message:     message,
+				Data: map[string]interface{}{
+					"invoice_id":     invoice.ID,
+					"invoice_number": invoice.InvoiceNumber,
+					"plan_name":      invoice.PlanSnapshotName,
+					"amount":         invoice.Amount,
+					"checkout_url":   checkoutLink,
+				},
+			})
+		}
+
+		if s.emailService != nil {
+			send := s.emailService.SendPaymentFailed
+			if status == subscription.InvoiceStatusExpired {
+				send = s.emailService.SendInvoiceExpired
+			}
+			if err := send(
+				owner.Email,
+				owner.Name,
+				owner.CompanyName,
+				invoice.InvoiceNumber,
+				invoice.PlanSnapshotName,
+				amount,
+				checkoutLink,
+			); err != nil {
+				log.Printf("Failed to email dunning notice to %s: %v", owner.Email, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/config"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+)
+
+var signupAt = time.Date(2026, time.March, 3, 9, 30, 0, 0, time.UTC)
+
+func newExpiryTestService(subs *fakeSubscriptionRepo, clk *clock.FakeClock) *subscriptionService {
+	return &subscriptionService{
+		planRepo:         &fakePlanRepo{plans: []subscription.Plan{{ID: "plan-trial", Name: TrialPlanName}}},
+		subscriptionRepo: subs,
+		cfg: &config.Config{Subscription: config.SubscriptionConfig{
+			Trial: config.TrialTerms{DurationDays: 14, MaxSeats: 10},
+		}},
+		clock: clk,
+	}
+}
+
+func TestTrialExpiresExactlyAtDay14(t *testing.T) {
+	clk := clock.NewFake(signupAt)
+	subs := &fakeSubscriptionRepo{subs: map[string]subscription.Subscription{}}
+	s := newExpiryTestService(subs, clk)
+	ctx := context.Background()
+
+	trial, err := s.CreateTrialSubscription(ctx, "company-1", "")
+	if err != nil {
+		t.Fatalf("CreateTrialSubscription() error = %v", err)
+	}
+	trialEnd := signupAt.AddDate(0, 0, 14)
+	if !trial.CurrentPeriodEnd.Equal(trialEnd) || trial.TrialEndsAt == nil || !trial.TrialEndsAt.Equal(trialEnd) {
+		t.Fatalf("trial ends at %s (TrialEndsAt %v), want %s", trial.CurrentPeriodEnd, trial.TrialEndsAt, trialEnd)
+	}
+
+	clk.AdvanceDays(14)
+	clk.Advance(-time.Nanosecond)
+	if trial.IsExpired(clk.Now()) {
+		t.Errorf("IsExpired() at %s = true, want false", clk.Now())
+	}
+	if err := s.ProcessExpiredTrials(ctx); err != nil {
+		t.Fatalf("ProcessExpiredTrials() error = %v", err)
+	}
+	if got := subs.subs["company-1"].Status; got != subscription.StatusTrial {
+		t.Fatalf("status a nanosecond before day 14 = %s, want %s", got, subscription.StatusTrial)
+	}
+
+	clk.Advance(time.Nanosecond)
+	if !trial.IsExpired(clk.Now()) {
+		t.Errorf("IsExpired() at %s = false, want true", clk.Now())
+	}
+	if err := s.ProcessExpiredTrials(ctx); err != nil {
+		t.Fatalf("ProcessExpiredTrials() error = %v", err)
+	}
+	if got := subs.subs["company-1"].Status; got != subscription.StatusExpired {
+		t.Fatalf("status at day 14 = %s, want %s", got, subscription.StatusExpired)
+	}
+}
+
+func TestGracePeriodEndsExactlyAtDay7(t *testing.T) {
+	periodEnd := signupAt.AddDate(0, 1, 0)
+	clk := clock.NewFake(periodEnd.Add(-time.Nanosecond))
+	subs := &fakeSubscriptionRepo{subs: map[string]subscription.Subscription{
+		"company-1": {ID: "sub-1", CompanyID: "company-1", Status: subscription.StatusActive,
+			CurrentPeriodStart: signupAt, CurrentPeriodEnd: periodEnd},
+	}}
+	s := newExpiryTestService(subs, clk)
+	ctx := context.Background()
+
+	step := func(wantStatus subscription.SubscriptionStatus, wantGrace bool) {
+		t.Helper()
+		if err := s.ProcessPastDueSubscriptions(ctx); err != nil {
+			t.Fatalf("ProcessPastDueSubscriptions() error = %v", err)
+		}
+		sub := subs.subs["company-1"]
+		if sub.Status != wantStatus {
+			t.Errorf("status at %s = %s, want %s", clk.Now(), sub.Status, wantStatus)
+		}
+		if got := sub.IsInGracePeriod(clk.Now()); got != wantGrace {
+			t.Errorf("IsInGracePeriod() at %s = %v, want %v", clk.Now(), got, wantGrace)
+		}
+	}
+
+	step(subscription.StatusActive, false)
+
+	// The period ends: the subscription is past due from that instant
+	clk.Set(periodEnd)
+	step(subscription.StatusPastDue, true)
+
+	clk.AdvanceDays(subscription.GracePeriodDays)
+	clk.Advance(-time.Nanosecond)
+	step(subscription.StatusPastDue, true)
+
+	clk.Advance(time.Nanosecond)
+	sub := subs.subs["company-1"]
+	if want := periodEnd.AddDate(0, 0, subscription.GracePeriodDays); !sub.GraceEndsAt().Equal(want) || !clk.Now().Equal(want) {
+		t.Fatalf("grace ends at %s, want %s", sub.GraceEndsAt(), want)
+	}
+	step(subscription.StatusExpired, false)
+}
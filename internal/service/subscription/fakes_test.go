@@ -0,0 +1,226 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+	"github.com/jackc/pgx/v5"
+)
+
+// fakeSubscriptionRepo keeps one subscription per company
+type fakeSubscriptionRepo struct {
+	subscription.SubscriptionRepository
+	subs        map[string]subscription.Subscription // by company ID
+	pendingSeat []*int                               // every SetPendingMaxSeats value, in order
+	// racing subscriptions are committed by another signup just before Create inserts, by company ID
+	racing map[string]subscription.Subscription
+}
+
+func (r *fakeSubscriptionRepo) GetByCompanyID(_ context.Context, companyID string) (subscription.Subscription, error) {
+	sub, ok := r.subs[companyID]
+	if !ok {
+		return subscription.Subscription{}, pgx.ErrNoRows
+	}
+	return sub, nil
+}
+
+func (r *fakeSubscriptionRepo) GetByCompanyIDForUpdate(ctx context.Context, companyID string) (subscription.Subscription, error) {
+	return r.GetByCompanyID(ctx, companyID)
+}
+
+func (r *fakeSubscriptionRepo) GetByCompanyIDWithFeatures(ctx context.Context, companyID string) (subscription.Subscription, error) {
+	return r.GetByCompanyID(ctx, companyID)
+}
+
+func (r *fakeSubscriptionRepo) GetByID(_ context.Context, id string) (subscription.Subscription, error) {
+	for _, sub := range r.subs {
+		if sub.ID == id {
+			return sub, nil
+		}
+	}
+	return subscription.Subscription{}, pgx.ErrNoRows
+}
+
+func (r *fakeSubscriptionRepo) Update(_ context.Context, sub subscription.Subscription) error {
+	r.subs[sub.CompanyID] = sub
+	return nil
+}
+
+func (r *fakeSubscriptionRepo) Create(_ context.Context, sub subscription.Subscription) (subscription.Subscription, error) {
+	if racer, ok := r.racing[sub.CompanyID]; ok {
+		r.subs[sub.CompanyID] = racer
+		delete(r.racing, sub.CompanyID)
+	}
+	if _, ok := r.subs[sub.CompanyID]; ok {
+		return subscription.Subscription{}, subscription.ErrAlreadySubscribed
+	}
+	sub.ID = fmt.Sprintf("sub-%d", len(r.subs)+1)
+	r.subs[sub.CompanyID] = sub
+	return sub, nil
+}
+
+func (r *fakeSubscriptionRepo) UpdateStatus(_ context.Context, id string, status subscription.SubscriptionStatus) error {
+	for companyID, sub := range r.subs {
+		if sub.ID == id {
+			sub.Status = status
+			r.subs[companyID] = sub
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+// ListExpiring and UpdateExpiredToStatus mirror the SQL: the period has ended once
+// current_period_end <= the given time
+func (r *fakeSubscriptionRepo) ListExpiring(_ context.Context, before interface{}) ([]subscription.Subscription, error) {
+	var subs []subscription.Subscription
+	for _, sub := range r.subs {
+		if (sub.Status == subscription.StatusTrial || sub.Status == subscription.StatusActive) &&
+			!sub.CurrentPeriodEnd.After(before.(time.Time)) {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+func (r *fakeSubscriptionRepo) UpdateExpiredToStatus(_ context.Context, cutoffTime interface{}, fromStatuses []subscription.SubscriptionStatus, toStatus subscription.SubscriptionStatus) ([]subscription.StatusChangedEvent, error) {
+	var events []subscription.StatusChangedEvent
+	for companyID, sub := range r.subs {
+		if !slices.Contains(fromStatuses, sub.Status) || sub.CurrentPeriodEnd.After(cutoffTime.(time.Time)) {
+			continue
+		}
+		events = append(events, subscription.StatusChangedEvent{
+			SubscriptionID: sub.ID, CompanyID: companyID, OldStatus: sub.Status, NewStatus: toStatus, CurrentPeriodEnd: sub.CurrentPeriodEnd,
+		})
+		sub.Status = toStatus
+		r.subs[companyID] = sub
+	}
+	return events, nil
+}
+
+func (r *fakeSubscriptionRepo) SetPendingMaxSeats(_ context.Context, _ string, pendingMaxSeats *int) error {
+	r.pendingSeat = append(r.pendingSeat, pendingMaxSeats)
+	return nil
+}
+
+// fakePlanRepo looks plans up by ID or name
+type fakePlanRepo struct {
+	subscription.PlanRepository
+	plans []subscription.Plan
+}
+
+func (r *fakePlanRepo) GetByID(_ context.Context, id string) (subscription.Plan, error) {
+	for _, p := range r.plans {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return subscription.Plan{}, pgx.ErrNoRows
+}
+
+func (r *fakePlanRepo) GetByName(_ context.Context, name string) (subscription.Plan, error) {
+	for _, p := range r.plans {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return subscription.Plan{}, pgx.ErrNoRows
+}
+
+// fakeInvoiceRepo stores created invoices; createErrs are returned by successive Create calls first
+type fakeInvoiceRepo struct {
+	subscription.InvoiceRepository
+	pending    int
+	createErrs []error
+	numbers    int64
+	created    []subscription.Invoice
+}
+
+func (r *fakeInvoiceRepo) HasPendingInvoice(_ context.Context, companyID string) (bool, error) {
+	for _, inv := range r.created {
+		if inv.CompanyID == companyID && inv.Status == subscription.InvoiceStatusPending {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeInvoiceRepo) GetByXenditID(_ context.Context, xenditID string) (subscription.Invoice, error) {
+	for _, inv := range r.created {
+		if inv.XenditInvoiceID != nil && *inv.XenditInvoiceID == xenditID {
+			return inv, nil
+		}
+	}
+	return subscription.Invoice{}, pgx.ErrNoRows
+}
+
+func (r *fakeInvoiceRepo) UpdatePayment(_ context.Context, id string, status subscription.InvoiceStatus, paidAt interface{}, method, channel string) error {
+	for i := range r.created {
+		if r.created[i].ID == id {
+			at := paidAt.(time.Time)
+			r.created[i].Status = status
+			r.created[i].PaidAt = &at
+			r.created[i].PaymentMethod = &method
+			r.created[i].PaymentChannel = &channel
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+func (r *fakeInvoiceRepo) CountPendingInvoicesBySubscription(context.Context, string) (int, error) {
+	return r.pending, nil
+}
+
+func (r *fakeInvoiceRepo) AllocateNumber(context.Context, string) (int64, error) {
+	r.numbers++
+	return r.numbers, nil
+}
+
+func (r *fakeInvoiceRepo) Create(_ context.Context, invoice subscription.Invoice) (subscription.Invoice, error) {
+	if len(r.createErrs) > 0 {
+		err := r.createErrs[0]
+		r.createErrs = r.createErrs[1:]
+		return subscription.Invoice{}, err
+	}
+	invoice.ID = fmt.Sprintf("invoice-%d", len(r.created)+1)
+	r.created = append(r.created, invoice)
+	return invoice, nil
+}
+
+// fakeEmployeeCounter reports the same head count for every company
+type fakeEmployeeCounter struct {
+	subscription.EmployeeCounter
+	active  int
+	invited int // pending invitations for employees that are not active yet
+}
+
+func (c *fakeEmployeeCounter) CountActiveByCompanyID(context.Context, string) (int, error) {
+	return c.active, nil
+}
+
+func (c *fakeEmployeeCounter) CountSeatsInUseByCompanyID(context.Context, string) (int, error) {
+	return c.active + c.invited, nil
+}
+
+// fakePaymentProvider records the invoices created and expired at the provider
+type fakePaymentProvider struct {
+	subscription.PaymentProvider
+	created []subscription.PaymentInvoiceRequest
+	expired []string
+}
+
+func (p *fakePaymentProvider) Name() string { return "fake" }
+
+func (p *fakePaymentProvider) CreateInvoice(_ context.Context, req subscription.PaymentInvoiceRequest) (subscription.PaymentInvoice, error) {
+	p.created = append(p.created, req)
+	return subscription.PaymentInvoice{ID: fmt.Sprintf("provider-%d", len(p.created)), URL: "https://pay.example/" + req.ExternalID}, nil
+}
+
+func (p *fakePaymentProvider) ExpireInvoice(_ context.Context, providerInvoiceID string) error {
+	p.expired = append(p.expired, providerInvoiceID)
+	return nil
+}
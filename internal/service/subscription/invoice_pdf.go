@@ -0,0 +1,113 @@
+package subscription
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+	"github.com/go-pdf/fpdf"
+	"github.com/jackc/pgx/v5"
+)
+
+const pdfDateFormat = "02 Jan 2006"
+
+// GenerateInvoicePDF renders a printable invoice from the invoice snapshot fields
+func (s *subscriptionService) GenerateInvoicePDF(ctx context.Context, companyID, invoiceID string) ([]byte, error) {
+	invoice, err := s.invoiceRepo.GetByID(ctx, invoiceID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, subscription.ErrInvoiceNotFound
+		}
+		return nil, fmt.Errorf("get invoice: %w", err)
+	}
+
+	// Verify company owns this invoice
+	if invoice.CompanyID != companyID {
+		return nil, subscription.ErrInvoiceNotFound
+	}
+
+	return renderInvoicePDF(invoice)
+}
+
+// renderInvoicePDF lays out a single-page A4 invoice
+func renderInvoicePDF(inv subscription.Invoice) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("Invoice %s", inv.InvoiceNumber), true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+
+	// Header
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.CellFormat(0, 10, "INVOICE", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, fmt.Sprintf("Invoice No: %s", inv.InvoiceNumber), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, fmt.Sprintf("Invoice ID: %s", inv.ID), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, fmt.Sprintf("Issue Date: %s", inv.IssueDate.Format(pdfDateFormat)), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, fmt.Sprintf("Status: %s", strings.ToUpper(string(inv.Status))), "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	// Subscription details
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 8, "Subscription", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	writeInvoiceRow(pdf, "Plan", inv.PlanSnapshotName)
+	writeInvoiceRow(pdf, "Billing Cycle", string(inv.BillingCycleSnapshot))
+	writeInvoiceRow(pdf, "Period", fmt.Sprintf("%s - %s", inv.PeriodStart.Format(pdfDateFormat), inv.PeriodEnd.Format(pdfDateFormat)))
+	if inv.IsProrated {
+		writeInvoiceRow(pdf, "Type", "Prorated seat increase")
+	}
+	if inv.Description != nil && *inv.Description != "" {
+		writeInvoiceRow(pdf, "Description", *inv.Description)
+	}
+	pdf.Ln(6)
+
+	// Line item table
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(235, 235, 235)
+	pdf.CellFormat(80, 8, "Item", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(20, 8, "Seats", "1", 0, "R", true, 0, "")
+	pdf.CellFormat(35, 8, "Price / Seat", "1", 0, "R", true, 0, "")
+	pdf.CellFormat(35, 8, "Amount", "1", 1, "R", true, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(80, 8, fmt.Sprintf("%s (%s)", inv.PlanSnapshotName, inv.BillingCycleSnapshot), "1", 0, "L", false, 0, "")
+	pdf.CellFormat(20, 8, fmt.Sprintf("%d", inv.SeatCountSnapshot), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(35, 8, inv.Currency.Format(inv.PricePerSeatSnapshot), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(35, 8, inv.Currency.Format(inv.Amount), "1", 1, "R", false, 0, "")
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(135, 8, "Total", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(35, 8, inv.Currency.Format(inv.Amount), "1", 1, "R", false, 0, "")
+	pdf.Ln(6)
+
+	// Payment details
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 8, "Payment", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	paidAt := "-"
+	if inv.PaidAt != nil {
+		paidAt = inv.PaidAt.Format(pdfDateFormat)
+	}
+	writeInvoiceRow(pdf, "Paid Date", paidAt)
+	writeInvoiceRow(pdf, "Payment Method", stringOrDash(inv.PaymentMethod))
+	writeInvoiceRow(pdf, "Payment Channel", stringOrDash(inv.PaymentChannel))
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render invoice pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeInvoiceRow(pdf *fpdf.Fpdf, label, value string) {
+	pdf.CellFormat(45, 6, label, "", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 6, value, "", 1, "L", false, 0, "")
+}
+
+func stringOrDash(s *string) string {
+	if s == nil || *s == "" {
+		return "-"
+	}
+	return *s
+}
@@ -0,0 +1,57 @@
+package subscription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+	"github.com/shopspring/decimal"
+)
+
+func TestProrateSeatUpsell(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name          string
+		cycle         subscription.BillingCycle
+		start, end    time.Time
+		now           time.Time
+		pricePerSeat  int64
+		seats         int
+		wantTotalDays float64
+		wantRemaining float64
+		wantAmount    int64
+	}{
+		{name: "february", cycle: subscription.BillingCycleMonthly, start: date(2026, 2, 1), end: date(2026, 3, 1), now: date(2026, 2, 15),
+			pricePerSeat: 30000, seats: 2, wantTotalDays: 28, wantRemaining: 14, wantAmount: 30000},
+		{name: "leap year february", cycle: subscription.BillingCycleMonthly, start: date(2028, 2, 1), end: date(2028, 3, 1), now: date(2028, 2, 15),
+			pricePerSeat: 29000, seats: 1, wantTotalDays: 29, wantRemaining: 15, wantAmount: 15000},
+		{name: "thirty-one day month", cycle: subscription.BillingCycleMonthly, start: date(2026, 1, 1), end: date(2026, 2, 1), now: date(2026, 1, 21),
+			pricePerSeat: 31000, seats: 3, wantTotalDays: 31, wantRemaining: 11, wantAmount: 33000},
+		{name: "whole february", cycle: subscription.BillingCycleMonthly, start: date(2026, 2, 1), end: date(2026, 3, 1), now: date(2026, 2, 1),
+			pricePerSeat: 30000, seats: 1, wantTotalDays: 28, wantRemaining: 28, wantAmount: 30000},
+		{name: "leap year", cycle: subscription.BillingCycleYearly, start: date(2028, 1, 1), end: date(2029, 1, 1), now: date(2028, 7, 2),
+			pricePerSeat: 366000, seats: 1, wantTotalDays: 366, wantRemaining: 183, wantAmount: 183000},
+		{name: "common year", cycle: subscription.BillingCycleYearly, start: date(2026, 1, 1), end: date(2027, 1, 1), now: date(2026, 12, 2),
+			pricePerSeat: 365000, seats: 1, wantTotalDays: 365, wantRemaining: 30, wantAmount: 30000},
+		{name: "malformed period falls back to the nominal month", cycle: subscription.BillingCycleMonthly, start: date(2026, 3, 1), end: date(2026, 3, 1), now: date(2026, 2, 14),
+			pricePerSeat: 30000, seats: 1, wantTotalDays: 30, wantRemaining: 15, wantAmount: 15000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := subscription.Subscription{BillingCycle: tt.cycle, CurrentPeriodStart: tt.start, CurrentPeriodEnd: tt.end}
+			plan := subscription.Plan{PricePerSeat: decimal.NewFromInt(tt.pricePerSeat), Currency: subscription.CurrencyIDR}
+
+			amount, remaining, total := prorateSeatUpsell(sub, plan, tt.seats, tt.now)
+			if total != tt.wantTotalDays || remaining != tt.wantRemaining {
+				t.Errorf("prorateSeatUpsell() = %.2f of %.2f days, want %.2f of %.2f", remaining, total, tt.wantRemaining, tt.wantTotalDays)
+			}
+			if !amount.Equal(decimal.NewFromInt(tt.wantAmount)) {
+				t.Errorf("prorateSeatUpsell() amount = %s, want %d", amount, tt.wantAmount)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
+	"github.com/jackc/pgx/v5"
+)
+
+// ReconcileSeats finds companies with more active employees than paid seats.
+// Each violation is re-checked with the subscription row locked. Active subscriptions get a
+// pending seat increase when SeatReconcileAutoSchedule is on; everything else is flagged and
+// the owners are notified once, until the company is back within its seats.
+func (s *subscriptionService) ReconcileSeats(ctx context.Context) ([]subscription.SeatViolation, error) {
+	cleared, err := s.subscriptionRepo.ClearResolvedSeatViolations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("clear resolved seat violations: %w", err)
+	}
+	if cleared > 0 {
+		log.Printf("Cron: Cleared %d resolved seat violations", cleared)
+	}
+
+	candidates, err := s.subscriptionRepo.ListSeatViolations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list seat violations: %w", err)
+	}
+
+	violations := make([]subscription.SeatViolation, 0, len(candidates))
+	for _, candidate := range candidates {
+		violation, found, notify, err := s.reconcileCompanySeats(ctx, candidate.CompanyID)
+		if err != nil {
+			log.Printf("Cron: Failed to reconcile seats for company %s: %v", candidate.CompanyID, err)
+			continue
+		}
+		if !found {
+			continue
+		}
+
+		log.Printf("Cron: Company %s has %d active employees for %d seats (%s)",
+			violation.CompanyID, violation.ActiveEmployees, violation.MaxSeats, violation.Action)
+		if notify {
+			s.notifySeatViolation(ctx, violation)
+		}
+		violations = append(violations, violation)
+	}
+
+	if len(violations) > 0 {
+		log.Printf("Cron: Found %d companies over their seat limit", len(violations))
+	}
+
+	return violations, nil
+}
+
+// reconcileCompanySeats re-counts one company's seats inside a transaction and applies the action.
+// notify reports whether the company was newly flagged.
+func (s *subscriptionService) reconcileCompanySeats(ctx context.Context, companyID string) (violation subscription.SeatViolation, found bool, notify bool, err error) {
+	err = postgresql.WithTransactionRetry(ctx, s.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+		// Reset what an aborted attempt may have set
+		violation, found, notify = subscription.SeatViolation{}, false, false
+
+		sub, err := s.subscriptionRepo.GetByCompanyIDForUpdate(txCtx, companyID)
+		if err != nil {
+			return fmt.Errorf("get subscription: %w", err)
+		}
+
+		activeEmployees, err := s.employeeCounter.CountActiveByCompanyID(txCtx, companyID)
+		if err != nil {
+			return fmt.Errorf("count active employees: %w", err)
+		}
+		if activeEmployees <= sub.MaxSeats {
+			return nil
+		}
+
+		found = true
+		violation = subscription.SeatViolation{
+			CompanyID:       sub.CompanyID,
+			SubscriptionID:  sub.ID,
+			Status:          sub.Status,
+			MaxSeats:        sub.MaxSeats,
+			PendingMaxSeats: sub.PendingMaxSeats,
+			ActiveEmployees: activeEmployees,
+			ExcessSeats:     activeEmployees - sub.MaxSeats,
+		}
+
+		// Pending seats are applied at the next renewal, which only active subscriptions have
+		if s.cfg.Subscription.SeatReconcileAutoSchedule && sub.Status == subscription.StatusActive {
+			if sub.PendingMaxSeats == nil || *sub.PendingMaxSeats < activeEmployees {
+				if err := s.subscriptionRepo.SetPendingMaxSeats(txCtx, sub.ID, &activeEmployees); err != nil {
+					return fmt.Errorf("schedule pending seats: %w", err)
+				}
+				violation.PendingMaxSeats = &activeEmployees
+			}
+			violation.Action = subscription.SeatActionPendingIncreaseScheduled
+			return nil
+		}
+
+		notify, err = s.subscriptionRepo.MarkSeatViolationFlagged(txCtx, sub.ID)
+		if err != nil {
+			return fmt.Errorf("flag seat violation: %w", err)
+		}
+		violation.Action = subscription.SeatActionFlagged
+		return nil
+	})
+	if err != nil {
+		return subscription.SeatViolation{}, false, false, err
+	}
+	return violation, found, notify, nil
+}
+
+// notifySeatViolation asks the owners to buy more seats or offboard employees
+func (s *subscriptionService) notifySeatViolation(ctx context.Context, violation subscription.SeatViolation) {
+	if s.notificationSvc == nil {
+		return
+	}
+
+	owners, err := s.billingContacts.ListOwnersByCompanyID(ctx, violation.CompanyID)
+	if err != nil {
+		log.Printf("Cron: Failed to get owners of company %s: %v", violation.CompanyID, err)
+		return
+	}
+
+	for _, owner := range owners {
+		_ = s.notificationSvc.QueueNotification(ctx, notification.CreateNotificationRequest{
+			CompanyID:   violation.CompanyID,
+			RecipientID: owner.UserID,
+			Type:        notification.TypeSeatLimitExceeded,
+			Title:       "Seat Limit Exceeded",
+			Message: fmt.Sprintf("Your company has %d active employees but only %d seats. Add seats or deactivate employees.",
+				violation.ActiveEmployees, violation.MaxSeats),
+			Data: map[string]interface{}{
+				"subscription_id":  violation.SubscriptionID,
+				"max_seats":        violation.MaxSeats,
+				"active_employees": violation.ActiveEmployees,
+				"excess_seats":     violation.ExcessSeats,
+				"subscription_url": fmt.Sprintf("%s/subscription", s.cfg.App.FrontendURL),
+			},
+		})
+	}
+}
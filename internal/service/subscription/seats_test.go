@@ -0,0 +1,63 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+)
+
+func TestSeatLimitBoundary(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  subscription.SubscriptionStatus
+		active  int
+		invited int
+		wantErr error
+	}{
+		{name: "one seat left", status: subscription.StatusActive, active: 4, wantErr: nil},
+		{name: "last seat taken", status: subscription.StatusActive, active: 5, wantErr: subscription.ErrSeatLimitExceeded},
+		{name: "last seat promised to an invitation", status: subscription.StatusActive, active: 4, invited: 1, wantErr: subscription.ErrSeatLimitExceeded},
+		{name: "one seat left with invitations", status: subscription.StatusActive, active: 2, invited: 2, wantErr: nil},
+		{name: "over the limit after a downgrade", status: subscription.StatusActive, active: 6, wantErr: subscription.ErrSeatLimitExceeded},
+		{name: "trial counts seats too", status: subscription.StatusTrial, active: 5, wantErr: subscription.ErrSeatLimitExceeded},
+		{name: "expired subscription", status: subscription.StatusExpired, active: 0, wantErr: subscription.ErrSubscriptionExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &subscriptionService{
+				subscriptionRepo: &fakeSubscriptionRepo{subs: map[string]subscription.Subscription{
+					"company-1": {ID: "sub-1", CompanyID: "company-1", Status: tt.status, MaxSeats: 5},
+				}},
+				employeeCounter: &fakeEmployeeCounter{active: tt.active, invited: tt.invited},
+			}
+			ctx := context.Background()
+
+			err := s.EnsureSeatAvailable(ctx, "company-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("EnsureSeatAvailable() error = %v, want %v", err, tt.wantErr)
+			}
+
+			canAdd, err := s.CanAddEmployee(ctx, "company-1")
+			if err != nil {
+				t.Fatalf("CanAddEmployee() error = %v", err)
+			}
+			if canAdd != (tt.wantErr == nil) {
+				t.Errorf("CanAddEmployee() = %v, want %v", canAdd, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestSeatLimitWithoutSubscription(t *testing.T) {
+	s := &subscriptionService{
+		subscriptionRepo: &fakeSubscriptionRepo{subs: map[string]subscription.Subscription{}},
+		employeeCounter:  &fakeEmployeeCounter{},
+	}
+
+	if err := s.EnsureSeatAvailable(context.Background(), "company-1"); !errors.Is(err, subscription.ErrSubscriptionNotFound) {
+		t.Fatalf("EnsureSeatAvailable() error = %v, want %v", err, subscription.ErrSubscriptionNotFound)
+	}
+}
@@ -8,9 +8,12 @@ import (
 	"time"
 
 	"github.com/cmlabs-hris/hris-backend-go/internal/config"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
 	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
 	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/database"
-	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/xendit"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/email"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/pagination"
 	"github.com/cmlabs-hris/hris-backend-go/internal/repository/postgresql"
 	"github.com/jackc/pgx/v5"
 	"github.com/shopspring/decimal"
@@ -19,8 +22,6 @@ import (
 // Constants
 const (
 	TrialPlanName       = "Free Trial"
-	TrialDurationDays   = 14
-	GracePeriodDays     = 7
 	YearlyMonthsCharged = 10 // 12 months for price of 10 (2 months free)
 )
 
@@ -33,6 +34,28 @@ func exceedsPlanMaxSeats(plan subscription.Plan, seatCount int) bool {
 	return seatCount > *plan.MaxSeats
 }
 
+// prorateSeatUpsell prices additional seats for the rest of the current period.
+// Shared by ChangeSeats and PreviewSeatChange so the preview always matches the invoice.
+func prorateSeatUpsell(sub subscription.Subscription, plan subscription.Plan, seatDifference int, now time.Time) (amount decimal.Decimal, daysRemaining, totalDays float64) {
+	daysRemaining = sub.CurrentPeriodEnd.Sub(now).Hours() / 24
+
+	// Use the real period length so February, 31-day months and leap years prorate correctly
+	totalDays = sub.CurrentPeriodEnd.Sub(sub.CurrentPeriodStart).Hours() / 24
+	if totalDays <= 0 {
+		// Malformed period: fall back to the nominal cycle length rather than dividing by zero
+		if sub.BillingCycle == subscription.BillingCycleYearly {
+			totalDays = 365
+		} else {
+			totalDays = 30
+		}
+	}
+
+	amount = plan.Currency.Round(plan.PricePerSeat.
+		Mul(decimal.NewFromInt(int64(seatDifference))).
+		Mul(decimal.NewFromFloat(daysRemaining / totalDays)))
+	return amount, daysRemaining, totalDays
+}
+
 // planMaxSeatsValue returns the plan's max seats or a default value if nil
 func planMaxSeatsValue(plan subscription.Plan, defaultVal int) int {
 	if plan.MaxSeats == nil {
@@ -47,9 +70,14 @@ type subscriptionService struct {
 	subscriptionRepo subscription.SubscriptionRepository
 	invoiceRepo      subscription.InvoiceRepository
 	employeeCounter  subscription.EmployeeCounter
-	xenditClient     *xendit.Client
+	paymentProvider  subscription.PaymentProvider
+	billingContacts  subscription.BillingContactFinder
+	notificationSvc  notification.Service
+	emailService     email.EmailService
+	eventPublisher   subscription.EventPublisher
 	db               *database.DB
 	cfg              *config.Config
+	clock            clock.Clock
 }
 
 func NewSubscriptionService(
@@ -58,9 +86,14 @@ func NewSubscriptionService(
 	subscriptionRepo subscription.SubscriptionRepository,
 	invoiceRepo subscription.InvoiceRepository,
 	employeeCounter subscription.EmployeeCounter,
-	xenditClient *xendit.Client,
+	paymentProvider subscription.PaymentProvider,
+	billingContacts subscription.BillingContactFinder,
+	notificationSvc notification.Service,
+	emailService email.EmailService,
+	eventPublisher subscription.EventPublisher,
 	db *database.DB,
 	cfg *config.Config,
+	clk clock.Clock,
 ) subscription.SubscriptionService {
 	return &subscriptionService{
 		featureRepo:      featureRepo,
@@ -68,9 +101,14 @@ func NewSubscriptionService(
 		subscriptionRepo: subscriptionRepo,
 		invoiceRepo:      invoiceRepo,
 		employeeCounter:  employeeCounter,
-		xenditClient:     xenditClient,
+		paymentProvider:  paymentProvider,
+		billingContacts:  billingContacts,
+		notificationSvc:  notificationSvc,
+		emailService:     emailService,
+		eventPublisher:   eventPublisher,
 		db:               db,
 		cfg:              cfg,
+		clock:            clk,
 	}
 }
 
@@ -133,27 +171,34 @@ func (s *subscriptionService) GetMySubscription(ctx context.Context, companyID s
 	return toSubscriptionResponse(sub, usedSeats), nil
 }
 
-func (s *subscriptionService) CreateTrialSubscription(ctx context.Context, companyID string) (subscription.Subscription, error) {
+// CreateTrialSubscription starts the free trial for a company
+// It is idempotent: if the company already has a subscription, that subscription is returned
+func (s *subscriptionService) CreateTrialSubscription(ctx context.Context, companyID string, signupSource string) (subscription.Subscription, error) {
+	existing, err := s.subscriptionRepo.GetByCompanyID(ctx, companyID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return subscription.Subscription{}, fmt.Errorf("get subscription: %w", err)
+	}
+
 	// Get the Free Trial plan
 	trialPlan, err := s.planRepo.GetByName(ctx, TrialPlanName)
 	if err != nil {
 		return subscription.Subscription{}, fmt.Errorf("get trial plan: %w", err)
 	}
 
-	now := time.Now()
-	trialEnd := now.AddDate(0, 0, TrialDurationDays)
+	// Trial length and seats come from config; a signup source/campaign may override them
+	terms := s.cfg.Subscription.TrialTermsFor(signupSource)
 
-	// Default max seats for trial
-	maxSeats := 5
-	if trialPlan.MaxSeats != nil {
-		maxSeats = *trialPlan.MaxSeats
-	}
+	now := s.clock.Now()
+	trialEnd := now.AddDate(0, 0, terms.DurationDays)
 
 	sub := subscription.Subscription{
 		CompanyID:          companyID,
 		PlanID:             trialPlan.ID,
 		Status:             subscription.StatusTrial,
-		MaxSeats:           maxSeats,
+		MaxSeats:           terms.MaxSeats,
 		CurrentPeriodStart: now,
 		CurrentPeriodEnd:   trialEnd,
 		TrialEndsAt:        &trialEnd,
@@ -163,6 +208,15 @@ func (s *subscriptionService) CreateTrialSubscription(ctx context.Context, compa
 
 	created, err := s.subscriptionRepo.Create(ctx, sub)
 	if err != nil {
+		// A concurrent signup created it first - return that one. Create undoes only its own
+		// insert, so this read works inside the caller's transaction too.
+		if errors.Is(err, subscription.ErrAlreadySubscribed) {
+			existing, getErr := s.subscriptionRepo.GetByCompanyID(ctx, companyID)
+			if getErr != nil {
+				return subscription.Subscription{}, fmt.Errorf("get subscription: %w", getErr)
+			}
+			return existing, nil
+		}
 		return subscription.Subscription{}, fmt.Errorf("create trial subscription: %w", err)
 	}
 
@@ -195,6 +249,11 @@ func (s *subscriptionService) Checkout(ctx context.Context, companyID string, re
 		return subscription.InvoiceResponse{}, fmt.Errorf("get plan: %w", err)
 	}
 
+	// The provider can only bill currencies we know the minor units of
+	if !plan.Currency.IsSupported() {
+		return subscription.InvoiceResponse{}, subscription.ErrUnsupportedCurrency
+	}
+
 	// Validate seats (if plan has max limit)
 	if plan.MaxSeats != nil && req.SeatCount > *plan.MaxSeats {
 		return subscription.InvoiceResponse{}, subscription.ErrSeatLimitExceeded
@@ -217,44 +276,44 @@ func (s *subscriptionService) Checkout(ctx context.Context, companyID string, re
 
 	// Calculate period
 	billingCycle := subscription.BillingCycle(req.BillingCycle)
-	now := time.Now()
+	now := s.clock.Now()
 	periodStart := now
 	periodEnd := postgresql.CalculatePeriodEnd(periodStart, billingCycle)
 
 	// Calculate amount (no proration - full price for new period)
-	amount := postgresql.CalculateAmount(plan.PricePerSeat, req.SeatCount, billingCycle)
+	amount := plan.Currency.Round(postgresql.CalculateAmount(plan.PricePerSeat, req.SeatCount, billingCycle))
 	description := postgresql.FormatInvoiceDescription(plan.Name, req.SeatCount, billingCycle)
 
 	// Invoice expiry in seconds (InvoiceExpiry is in hours)
 	invoiceExpirySecs := s.cfg.Xendit.InvoiceExpiry * 3600
 
-	// Create Xendit invoice
-	xenditReq := xendit.CreateInvoiceRequest{
+	// Create provider invoice
+	paymentReq := subscription.PaymentInvoiceRequest{
 		ExternalID:         fmt.Sprintf("sub-%s-%d", companyID, now.Unix()),
 		Amount:             amount,
 		PayerEmail:         req.PayerEmail,
 		Description:        description,
-		Currency:           "IDR",
-		InvoiceDuration:    invoiceExpirySecs,
+		Currency:           string(plan.Currency),
+		DurationSeconds:    invoiceExpirySecs,
 		SuccessRedirectURL: s.cfg.Xendit.SuccessRedirect,
 		FailureRedirectURL: s.cfg.Xendit.FailureRedirect,
 	}
 
-	xenditResp, err := s.xenditClient.CreateInvoice(xenditReq)
+	paymentInvoice, err := s.paymentProvider.CreateInvoice(ctx, paymentReq)
 	if err != nil {
-		return subscription.InvoiceResponse{}, fmt.Errorf("create xendit invoice: %w", err)
+		return subscription.InvoiceResponse{}, fmt.Errorf("create %s invoice: %w", s.paymentProvider.Name(), err)
 	}
 
 	// Create invoice record with snapshot data
-	xenditID := xenditResp.ID
-	xenditURL := xenditResp.InvoiceURL
+	// Provider references are stored in the xendit_* columns regardless of provider
 	invoice := subscription.Invoice{
 		CompanyID:            companyID,
 		SubscriptionID:       sub.ID,
-		XenditInvoiceID:      &xenditID,
-		XenditInvoiceURL:     &xenditURL,
-		XenditExpiryDate:     &xenditResp.ExpiryDate,
+		XenditInvoiceID:      &paymentInvoice.ID,
+		XenditInvoiceURL:     &paymentInvoice.URL,
+		XenditExpiryDate:     &paymentInvoice.ExpiryDate,
 		Amount:               amount,
+		Currency:             plan.Currency,
 		PlanSnapshotName:     plan.Name,
 		PricePerSeatSnapshot: plan.PricePerSeat,
 		SeatCountSnapshot:    req.SeatCount,
@@ -265,53 +324,72 @@ func (s *subscriptionService) Checkout(ctx context.Context, companyID string, re
 		Description:          &description,
 	}
 
-	created, err := s.invoiceRepo.Create(ctx, invoice)
+	// Allocate the invoice number in the same transaction as the insert so a failed insert leaves no gap
+	var created subscription.Invoice
+	err = postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
+		txCtx := postgresql.ContextWithTx(ctx, tx)
+
+		number, err := s.invoiceRepo.AllocateNumber(txCtx, companyID)
+		if err != nil {
+			return fmt.Errorf("allocate invoice number: %w", err)
+		}
+		invoice.InvoiceNumber = subscription.FormatInvoiceNumber(now.Year(), number)
+
+		created, err = s.invoiceRepo.Create(txCtx, invoice)
+		return err
+	})
 	if err != nil {
-		// Try to expire the Xendit invoice if we failed to save
-		_, _ = s.xenditClient.ExpireInvoice(xenditResp.ID)
+		// Roll back: expire the provider invoice so the customer cannot pay an invoice we never stored
+		// The customer may have gone away, but the invoice must still be expired
+		if expireErr := s.paymentProvider.ExpireInvoice(context.WithoutCancel(ctx), paymentInvoice.ID); expireErr != nil {
+			log.Printf("Checkout: failed to expire orphaned %s invoice %s: %v", s.paymentProvider.Name(), paymentInvoice.ID, expireErr)
+		}
 		return subscription.InvoiceResponse{}, fmt.Errorf("create invoice: %w", err)
 	}
 
 	return toInvoiceResponse(created), nil
 }
 
-func (s *subscriptionService) HandleWebhook(ctx context.Context, payload subscription.XenditWebhookPayload) error {
-	// Get invoice by Xendit ID
-	invoice, err := s.invoiceRepo.GetByXenditID(ctx, payload.ID)
+func (s *subscriptionService) HandleWebhook(ctx context.Context, callback subscription.PaymentCallback) error {
+	// Get invoice by provider invoice ID
+	invoice, err := s.invoiceRepo.GetByXenditID(ctx, callback.ProviderInvoiceID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			// Unknown invoice - log and ignore
-			log.Printf("Webhook: Unknown invoice ID %s", payload.ID)
+			log.Printf("Webhook: Unknown invoice ID %s", callback.ProviderInvoiceID)
 			return nil
 		}
 		return fmt.Errorf("get invoice: %w", err)
 	}
 
-	// Handle based on status
-	switch payload.Status {
-	case "PAID", "SETTLED":
-		return s.handlePaymentSuccess(ctx, invoice, payload)
-	case "EXPIRED":
+	// Handle based on the status mapped by the provider
+	switch callback.Status {
+	case subscription.InvoiceStatusPaid:
+		return s.handlePaymentSuccess(ctx, invoice, callback)
+	case subscription.InvoiceStatusExpired:
 		return s.handlePaymentExpired(ctx, invoice)
-	case "FAILED":
+	case subscription.InvoiceStatusFailed:
 		return s.handlePaymentFailed(ctx, invoice)
 	default:
-		log.Printf("Webhook: Unhandled status %s for invoice %s", payload.Status, invoice.ID)
+		log.Printf("Webhook: Unhandled status %s for invoice %s", callback.ProviderStatus, invoice.ID)
 	}
 
 	return nil
 }
 
-func (s *subscriptionService) handlePaymentSuccess(ctx context.Context, invoice subscription.Invoice, payload subscription.XenditWebhookPayload) error {
+func (s *subscriptionService) handlePaymentSuccess(ctx context.Context, invoice subscription.Invoice, callback subscription.PaymentCallback) error {
 	// Update invoice
-	paidAt := time.Now()
+	paidAt := s.clock.Now()
+	if callback.PaidAt != nil {
+		paidAt = *callback.PaidAt
+	}
 	if err := s.invoiceRepo.UpdatePayment(
 		ctx,
 		invoice.ID,
 		subscription.InvoiceStatusPaid,
 		paidAt,
-		payload.PaymentMethod,
-		payload.PaymentChannel,
+		callback.PaymentMethod,
+		callback.PaymentChannel,
 	); err != nil {
 		return fmt.Errorf("update invoice payment: %w", err)
 	}
@@ -328,6 +406,10 @@ func (s *subscriptionService) handlePaymentSuccess(ctx context.Context, invoice
 		return fmt.Errorf("get plan by name: %w", err)
 	}
 
+	// A regular payment on a trial is the trial-to-paid conversion
+	converted := sub.Status == subscription.StatusTrial && !invoice.IsProrated
+	previousStatus := sub.Status
+
 	// Update subscription based on invoice type
 	if invoice.IsProrated {
 		// Prorated invoice (mid-cycle seat increase) - update seats only, don't extend period
@@ -355,6 +437,20 @@ func (s *subscriptionService) handlePaymentSuccess(ctx context.Context, invoice
 		return fmt.Errorf("update subscription: %w", err)
 	}
 
+	if converted {
+		go s.notifyTrialConverted(context.WithoutCancel(ctx), sub, plan.Name)
+	}
+
+	if previousStatus != sub.Status {
+		s.publishStatusChange(context.WithoutCancel(ctx), subscription.StatusChangedEvent{
+			SubscriptionID:   sub.ID,
+			CompanyID:        sub.CompanyID,
+			OldStatus:        previousStatus,
+			NewStatus:        sub.Status,
+			CurrentPeriodEnd: sub.CurrentPeriodEnd,
+		})
+	}
+
 	return nil
 }
 
@@ -363,6 +459,12 @@ func (s *subscriptionService) handlePaymentExpired(ctx context.Context, invoice
 		return fmt.Errorf("update invoice status: %w", err)
 	}
 	log.Printf("Invoice expired: %s for company %s", invoice.ID, invoice.CompanyID)
+
+	// Invoices we voided ourselves (cancellation, stale cleanup) are no longer pending when
+	// the provider confirms the expiry, so only genuinely abandoned invoices trigger a notice
+	if invoice.Status == subscription.InvoiceStatusPending {
+		go s.sendDunningNotice(context.WithoutCancel(ctx), invoice, subscription.InvoiceStatusExpired)
+	}
 	return nil
 }
 
@@ -371,6 +473,10 @@ func (s *subscriptionService) handlePaymentFailed(ctx context.Context, invoice s
 		return fmt.Errorf("update invoice status: %w", err)
 	}
 	log.Printf("Payment failed: %s for company %s", invoice.ID, invoice.CompanyID)
+
+	if invoice.Status == subscription.InvoiceStatusPending {
+		go s.sendDunningNotice(context.WithoutCancel(ctx), invoice, subscription.InvoiceStatusFailed)
+	}
 	return nil
 }
 
@@ -521,17 +627,32 @@ func (s *subscriptionService) CancelDowngrade(ctx context.Context, companyID str
 
 // ==================== Invoice Operations ====================
 
-func (s *subscriptionService) GetInvoices(ctx context.Context, companyID string) ([]subscription.InvoiceResponse, error) {
-	invoices, err := s.invoiceRepo.ListByCompanyID(ctx, companyID)
+func (s *subscriptionService) GetInvoices(ctx context.Context, companyID string, filter subscription.InvoiceFilter) (subscription.ListInvoiceResponse, error) {
+	if err := filter.Validate(); err != nil {
+		return subscription.ListInvoiceResponse{}, err
+	}
+
+	invoices, totalCount, err := s.invoiceRepo.ListByCompanyID(ctx, companyID, filter)
 	if err != nil {
-		return nil, fmt.Errorf("list invoices: %w", err)
+		return subscription.ListInvoiceResponse{}, fmt.Errorf("list invoices: %w", err)
 	}
 
 	responses := make([]subscription.InvoiceResponse, len(invoices))
 	for i, inv := range invoices {
 		responses[i] = toInvoiceResponse(inv)
 	}
-	return responses, nil
+
+	// Calculate pagination metadata
+	meta := pagination.Meta(filter.Page, filter.Limit, totalCount)
+
+	return subscription.ListInvoiceResponse{
+		TotalCount: totalCount,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: meta.TotalPages,
+		Showing:    meta.Showing,
+		Invoices:   responses,
+	}, nil
 }
 
 func (s *subscriptionService) GetInvoiceByID(ctx context.Context, companyID, invoiceID string) (subscription.InvoiceResponse, error) {
@@ -554,10 +675,10 @@ func (s *subscriptionService) GetInvoiceByID(ctx context.Context, companyID, inv
 // ==================== Cron Jobs ====================
 
 func (s *subscriptionService) ProcessExpiredTrials(ctx context.Context) error {
-	now := time.Now()
+	now := s.clock.Now()
 
 	// Find trial subscriptions past their end date
-	count, err := s.subscriptionRepo.UpdateExpiredToStatus(
+	expiredTrials, err := s.subscriptionRepo.UpdateExpiredToStatus(
 		ctx,
 		now,
 		[]subscription.SubscriptionStatus{subscription.StatusTrial},
@@ -567,12 +688,15 @@ func (s *subscriptionService) ProcessExpiredTrials(ctx context.Context) error {
 		return fmt.Errorf("update expired trials: %w", err)
 	}
 
-	if count > 0 {
-		log.Printf("Cron: Expired %d trial subscriptions", count)
+	if len(expiredTrials) > 0 {
+		log.Printf("Cron: Expired %d trial subscriptions", len(expiredTrials))
+	}
+	for _, event := range expiredTrials {
+		s.publishStatusChange(ctx, event)
 	}
 
 	// Find cancelled subscriptions past their period end
-	cancelledCount, err := s.subscriptionRepo.UpdateExpiredToStatus(
+	expiredCancelled, err := s.subscriptionRepo.UpdateExpiredToStatus(
 		ctx,
 		now,
 		[]subscription.SubscriptionStatus{subscription.StatusCancelled},
@@ -582,16 +706,19 @@ func (s *subscriptionService) ProcessExpiredTrials(ctx context.Context) error {
 		return fmt.Errorf("update expired cancelled subscriptions: %w", err)
 	}
 
-	if cancelledCount > 0 {
-		log.Printf("Cron: Expired %d cancelled subscriptions", cancelledCount)
+	if len(expiredCancelled) > 0 {
+		log.Printf("Cron: Expired %d cancelled subscriptions", len(expiredCancelled))
+	}
+	for _, event := range expiredCancelled {
+		s.publishStatusChange(ctx, event)
 	}
 
 	return nil
 }
 
 func (s *subscriptionService) ProcessPastDueSubscriptions(ctx context.Context) error {
-	now := time.Now()
-	graceCutoff := now.AddDate(0, 0, -GracePeriodDays)
+	now := s.clock.Now()
+	graceCutoff := now.AddDate(0, 0, -subscription.GracePeriodDays)
 
 	// Find active subscriptions past their period end (enter grace period)
 	subs, err := s.subscriptionRepo.ListExpiring(ctx, now)
@@ -600,18 +727,25 @@ func (s *subscriptionService) ProcessPastDueSubscriptions(ctx context.Context) e
 	}
 
 	for _, sub := range subs {
-		if sub.Status == subscription.StatusActive && sub.CurrentPeriodEnd.Before(now) {
+		if sub.Status == subscription.StatusActive && sub.IsExpired(now) {
 			// Move to past_due (grace period)
 			if err := s.subscriptionRepo.UpdateStatus(ctx, sub.ID, subscription.StatusPastDue); err != nil {
 				log.Printf("Cron: Failed to set past_due for subscription %s: %v", sub.ID, err)
 				continue
 			}
 			log.Printf("Cron: Subscription %s entered grace period", sub.ID)
+			s.publishStatusChange(ctx, subscription.StatusChangedEvent{
+				SubscriptionID:   sub.ID,
+				CompanyID:        sub.CompanyID,
+				OldStatus:        sub.Status,
+				NewStatus:        subscription.StatusPastDue,
+				CurrentPeriodEnd: sub.CurrentPeriodEnd,
+			})
 		}
 	}
 
 	// Find past_due subscriptions past grace period -> expired
-	count, err := s.subscriptionRepo.UpdateExpiredToStatus(
+	expired, err := s.subscriptionRepo.UpdateExpiredToStatus(
 		ctx,
 		graceCutoff,
 		[]subscription.SubscriptionStatus{subscription.StatusPastDue},
@@ -621,8 +755,11 @@ func (s *subscriptionService) ProcessPastDueSubscriptions(ctx context.Context) e
 		return fmt.Errorf("update expired past_due: %w", err)
 	}
 
-	if count > 0 {
-		log.Printf("Cron: Expired %d past_due subscriptions after grace period", count)
+	if len(expired) > 0 {
+		log.Printf("Cron: Expired %d past_due subscriptions after grace period", len(expired))
+	}
+	for _, event := range expired {
+		s.publishStatusChange(ctx, event)
 	}
 
 	return nil
@@ -630,7 +767,7 @@ func (s *subscriptionService) ProcessPastDueSubscriptions(ctx context.Context) e
 
 func (s *subscriptionService) ExpireStaleInvoices(ctx context.Context) error {
 	// Expire invoices older than configured expiry time (InvoiceExpiry is in hours)
-	cutoff := time.Now().Add(-time.Duration(s.cfg.Xendit.InvoiceExpiry) * time.Hour)
+	cutoff := s.clock.Now().Add(-time.Duration(s.cfg.Xendit.InvoiceExpiry) * time.Hour)
 
 	count, err := s.invoiceRepo.ExpireStaleInvoices(ctx, cutoff)
 	if err != nil {
@@ -671,15 +808,82 @@ func (s *subscriptionService) CanAddEmployee(ctx context.Context, companyID stri
 		return false, nil
 	}
 
-	// Count current employees
-	count, err := s.employeeCounter.CountActiveByCompanyID(ctx, companyID)
+	// Count occupied seats, including outstanding invitations
+	count, err := s.employeeCounter.CountSeatsInUseByCompanyID(ctx, companyID)
 	if err != nil {
-		return false, fmt.Errorf("count employees: %w", err)
+		return false, fmt.Errorf("count seats in use: %w", err)
 	}
 
 	return sub.CanAddEmployee(count), nil
 }
 
+// GetSeatUsageBreakdown explains the seat count CanAddEmployee and EnsureSeatAvailable check
+func (s *subscriptionService) GetSeatUsageBreakdown(ctx context.Context, companyID string) (subscription.SeatUsageResponse, error) {
+	sub, err := s.subscriptionRepo.GetByCompanyID(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return subscription.SeatUsageResponse{}, subscription.ErrSubscriptionNotFound
+		}
+		return subscription.SeatUsageResponse{}, fmt.Errorf("get subscription: %w", err)
+	}
+
+	active, err := s.employeeCounter.CountActiveByCompanyID(ctx, companyID)
+	if err != nil {
+		return subscription.SeatUsageResponse{}, fmt.Errorf("count active employees: %w", err)
+	}
+	inactive, err := s.employeeCounter.CountInactiveByCompanyID(ctx, companyID)
+	if err != nil {
+		return subscription.SeatUsageResponse{}, fmt.Errorf("count inactive employees: %w", err)
+	}
+	pending, err := s.employeeCounter.CountPendingInvitationsByCompanyID(ctx, companyID)
+	if err != nil {
+		return subscription.SeatUsageResponse{}, fmt.Errorf("count pending invitations: %w", err)
+	}
+
+	inUse := active + pending
+	available := sub.MaxSeats - inUse
+	if available < 0 {
+		available = 0
+	}
+
+	return subscription.SeatUsageResponse{
+		MaxSeats:           sub.MaxSeats,
+		PendingMaxSeats:    sub.PendingMaxSeats,
+		ActiveEmployees:    active,
+		InactiveEmployees:  inactive,
+		PendingInvitations: pending,
+		SeatsInUse:         inUse,
+		AvailableSeats:     available,
+		CanAddEmployee:     sub.IsActive() && sub.CanAddEmployee(inUse),
+	}, nil
+}
+
+func (s *subscriptionService) EnsureSeatAvailable(ctx context.Context, companyID string) error {
+	// Lock the subscription row so concurrent creates are serialized on the seat count
+	sub, err := s.subscriptionRepo.GetByCompanyIDForUpdate(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return subscription.ErrSubscriptionNotFound
+		}
+		return fmt.Errorf("get subscription: %w", err)
+	}
+
+	if !sub.IsActive() {
+		return subscription.ErrSubscriptionExpired
+	}
+
+	count, err := s.employeeCounter.CountSeatsInUseByCompanyID(ctx, companyID)
+	if err != nil {
+		return fmt.Errorf("count seats in use: %w", err)
+	}
+
+	if !sub.CanAddEmployee(count) {
+		return subscription.ErrSeatLimitExceeded
+	}
+
+	return nil
+}
+
 // CancelSubscription cancels the subscription (access until period end)
 // Voids all pending invoices and prevents future billing
 func (s *subscriptionService) CancelSubscription(ctx context.Context, companyID string, req subscription.CancelRequest) error {
@@ -700,7 +904,7 @@ func (s *subscriptionService) CancelSubscription(ctx context.Context, companyID
 	// Use database transaction to ensure consistency
 	var expiredCount int
 	err = postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+		txCtx := postgresql.ContextWithTx(ctx, tx)
 
 		// 1. Get all invoices for this subscription and filter pending ones
 		allInvoices, err := s.invoiceRepo.ListBySubscriptionID(txCtx, sub.ID)
@@ -716,17 +920,17 @@ func (s *subscriptionService) CancelSubscription(ctx context.Context, companyID
 			}
 		}
 
-		// 2. Void pending invoices in Xendit and update DB status
+		// 2. Void pending invoices at the payment provider and update DB status
 		for _, inv := range pendingInvoices {
-			// Expire invoice in Xendit if xendit_invoice_id exists
+			// Expire invoice at the provider if a provider invoice ID exists
 			if inv.XenditInvoiceID != nil && *inv.XenditInvoiceID != "" {
-				_, err := s.xenditClient.ExpireInvoice(*inv.XenditInvoiceID)
+				err := s.paymentProvider.ExpireInvoice(ctx, *inv.XenditInvoiceID)
 				if err != nil {
 					// Log error but don't fail the cancellation
 					// The invoice will still be marked expired in our DB
-					log.Printf("Warning: Failed to expire Xendit invoice %s: %v", *inv.XenditInvoiceID, err)
+					log.Printf("Warning: Failed to expire %s invoice %s: %v", s.paymentProvider.Name(), *inv.XenditInvoiceID, err)
 				} else {
-					log.Printf("Expired Xendit invoice %s for cancelled subscription", *inv.XenditInvoiceID)
+					log.Printf("Expired %s invoice %s for cancelled subscription", s.paymentProvider.Name(), *inv.XenditInvoiceID)
 				}
 			}
 
@@ -751,6 +955,15 @@ func (s *subscriptionService) CancelSubscription(ctx context.Context, companyID
 
 	log.Printf("Subscription cancelled: Company %s, expired %d pending invoices, access until %v",
 		companyID, expiredCount, sub.CurrentPeriodEnd)
+
+	// Use context.WithoutCancel to prevent cancellation when HTTP request ends
+	s.publishStatusChange(context.WithoutCancel(ctx), subscription.StatusChangedEvent{
+		SubscriptionID:   sub.ID,
+		CompanyID:        sub.CompanyID,
+		OldStatus:        sub.Status,
+		NewStatus:        subscription.StatusCancelled,
+		CurrentPeriodEnd: sub.CurrentPeriodEnd,
+	})
 	return nil
 }
 
@@ -786,16 +999,16 @@ func (s *subscriptionService) CancelPendingInvoice(ctx context.Context, companyI
 
 	// Use transaction for consistency
 	err = postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
-		txCtx := context.WithValue(ctx, "tx", tx)
+		txCtx := postgresql.ContextWithTx(ctx, tx)
 
-		// Void invoice in Xendit if xendit_invoice_id exists
+		// Void invoice at the payment provider if a provider invoice ID exists
 		if invoice.XenditInvoiceID != nil && *invoice.XenditInvoiceID != "" {
-			_, err := s.xenditClient.ExpireInvoice(*invoice.XenditInvoiceID)
+			err := s.paymentProvider.ExpireInvoice(ctx, *invoice.XenditInvoiceID)
 			if err != nil {
 				// Log warning but continue with DB update
-				log.Printf("Warning: Failed to expire Xendit invoice %s: %v", *invoice.XenditInvoiceID, err)
+				log.Printf("Warning: Failed to expire %s invoice %s: %v", s.paymentProvider.Name(), *invoice.XenditInvoiceID, err)
 			} else {
-				log.Printf("Expired Xendit invoice %s upon user cancellation", *invoice.XenditInvoiceID)
+				log.Printf("Expired %s invoice %s upon user cancellation", s.paymentProvider.Name(), *invoice.XenditInvoiceID)
 			}
 		}
 
@@ -815,6 +1028,83 @@ func (s *subscriptionService) CancelPendingInvoice(ctx context.Context, companyI
 	return nil
 }
 
+// PreviewSeatChange reports what ChangeSeats would do for newSeatCount without creating anything
+func (s *subscriptionService) PreviewSeatChange(ctx context.Context, companyID string, newSeatCount int) (subscription.SeatChangePreviewResponse, error) {
+	if newSeatCount < 1 {
+		return subscription.SeatChangePreviewResponse{}, subscription.ErrInvalidSeatCount
+	}
+
+	sub, err := s.subscriptionRepo.GetByCompanyIDWithFeatures(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return subscription.SeatChangePreviewResponse{}, subscription.ErrSubscriptionNotFound
+		}
+		return subscription.SeatChangePreviewResponse{}, fmt.Errorf("get subscription: %w", err)
+	}
+
+	if newSeatCount == sub.MaxSeats {
+		return subscription.SeatChangePreviewResponse{}, subscription.ErrSameAsCurrentSeats
+	}
+
+	// A pending payment would block the real change, so surface it in the preview too
+	pendingCount, err := s.invoiceRepo.CountPendingInvoicesBySubscription(ctx, sub.ID)
+	if err != nil {
+		return subscription.SeatChangePreviewResponse{}, fmt.Errorf("count pending invoices: %w", err)
+	}
+	if pendingCount > 0 {
+		return subscription.SeatChangePreviewResponse{}, subscription.ErrPendingInvoiceExists
+	}
+
+	plan, err := s.planRepo.GetByID(ctx, sub.PlanID)
+	if err != nil {
+		return subscription.SeatChangePreviewResponse{}, fmt.Errorf("get plan: %w", err)
+	}
+
+	if exceedsPlanMaxSeats(plan, newSeatCount) {
+		return subscription.SeatChangePreviewResponse{}, subscription.ErrSeatLimitExceeded
+	}
+
+	preview := subscription.SeatChangePreviewResponse{
+		CurrentSeats: sub.MaxSeats,
+		NewSeats:     newSeatCount,
+		PricePerSeat: plan.PricePerSeat,
+		Currency:     plan.Currency,
+		BillingCycle: sub.BillingCycle,
+	}
+
+	// UPSELL: charged now, prorated to the end of the current period
+	if newSeatCount > sub.MaxSeats {
+		if sub.Status == subscription.StatusPastDue {
+			return subscription.SeatChangePreviewResponse{}, subscription.ErrCannotUpgradeDuringGracePeriod
+		}
+
+		seatDifference := newSeatCount - sub.MaxSeats
+		amount, daysRemaining, totalDays := prorateSeatUpsell(sub, plan, seatDifference, s.clock.Now())
+
+		preview.Type = subscription.SeatChangeUpsell
+		preview.ProratedAmount = &amount
+		preview.DaysRemaining = &daysRemaining
+		preview.TotalDays = &totalDays
+		preview.Message = fmt.Sprintf("You'll be charged %s now for %d additional seats. Seats are added after payment.", plan.Currency.Format(amount), seatDifference)
+		return preview, nil
+	}
+
+	// DOWNSELL: free, applied at the next renewal, must still fit active employees
+	activeEmployees, err := s.employeeCounter.CountActiveByCompanyID(ctx, companyID)
+	if err != nil {
+		return subscription.SeatChangePreviewResponse{}, fmt.Errorf("count employees: %w", err)
+	}
+	if newSeatCount < activeEmployees {
+		return subscription.SeatChangePreviewResponse{}, subscription.ErrSeatsBelowActiveEmployees
+	}
+
+	effectiveDate := sub.CurrentPeriodEnd.Format("2006-01-02")
+	preview.Type = subscription.SeatChangeDownsell
+	preview.EffectiveDate = &effectiveDate
+	preview.Message = fmt.Sprintf("Seat count will be reduced from %d to %d at next renewal on %s. No charge.", sub.MaxSeats, newSeatCount, effectiveDate)
+	return preview, nil
+}
+
 // ChangeSeats changes the number of seats
 func (s *subscriptionService) ChangeSeats(ctx context.Context, companyID string, req subscription.ChangeSeatRequest) (subscription.ChangeSeatResponse, error) {
 	if err := req.Validate(); err != nil {
@@ -860,7 +1150,7 @@ func (s *subscriptionService) ChangeSeats(ctx context.Context, companyID string,
 		return subscription.ChangeSeatResponse{}, subscription.ErrSeatLimitExceeded
 	}
 
-	now := time.Now()
+	now := s.clock.Now()
 
 	// UPSELL: Adding seats (prorated, immediate after payment)
 	if req.SeatCount > sub.MaxSeats {
@@ -869,19 +1159,13 @@ func (s *subscriptionService) ChangeSeats(ctx context.Context, companyID string,
 			return subscription.ChangeSeatResponse{}, subscription.ErrCannotUpgradeDuringGracePeriod
 		}
 
-		// Calculate prorated amount
-		daysRemaining := sub.CurrentPeriodEnd.Sub(now).Hours() / 24
-		var totalDays float64
-		if sub.BillingCycle == subscription.BillingCycleYearly {
-			totalDays = 365
-		} else {
-			totalDays = 30
+		if !plan.Currency.IsSupported() {
+			return subscription.ChangeSeatResponse{}, subscription.ErrUnsupportedCurrency
 		}
 
+		// Calculate prorated amount
 		seatDifference := req.SeatCount - sub.MaxSeats
-		proratedAmount := plan.PricePerSeat.
-			Mul(decimal.NewFromInt(int64(seatDifference))).
-			Mul(decimal.NewFromFloat(daysRemaining / totalDays))
+		proratedAmount, daysRemaining, _ := prorateSeatUpsell(sub, plan, seatDifference, now)
 
 		// Calculate invoice expiry matching subscription remaining days (minimum 24 hours)
 		expiryHours := daysRemaining * 24
@@ -897,6 +1181,7 @@ func (s *subscriptionService) ChangeSeats(ctx context.Context, companyID string,
 			CompanyID:            companyID,
 			SubscriptionID:       sub.ID,
 			Amount:               proratedAmount,
+			Currency:             plan.Currency,
 			IsProrated:           true,
 			PlanSnapshotName:     plan.Name,
 			PricePerSeatSnapshot: plan.PricePerSeat,
@@ -910,44 +1195,51 @@ func (s *subscriptionService) ChangeSeats(ctx context.Context, companyID string,
 		}
 
 		var createdInvoice subscription.Invoice
-		var xenditResp *xendit.InvoiceResponse
-
-		// Use transaction for multi-step operation
-		err = postgresql.WithTransaction(ctx, s.db, func(tx pgx.Tx) error {
-			txCtx := context.WithValue(ctx, "tx", tx)
-
-			// Get payer email from company
-			// Note: You may need to add this to the company domain if not already present
-			payerEmail := fmt.Sprintf("billing+%s@company.com", companyID) // Placeholder
-
-			// Create Xendit invoice
-			xenditReq := xendit.CreateInvoiceRequest{
-				ExternalID:         fmt.Sprintf("seat-up-%s-%d", sub.ID, now.Unix()),
-				Amount:             proratedAmount,
-				PayerEmail:         payerEmail,
-				Description:        description,
-				Currency:           "IDR",
-				InvoiceDuration:    invoiceExpirySecs,
-				SuccessRedirectURL: s.cfg.Xendit.SuccessRedirect,
-				FailureRedirectURL: s.cfg.Xendit.FailureRedirect,
-			}
+		var paymentInvoice subscription.PaymentInvoice
 
-			xResp, err := s.xenditClient.CreateInvoice(xenditReq)
+		// Retried when Postgres aborts it on a conflict. The provider invoice is created on the
+		// first attempt only and reused by the retries, so a conflict never bills twice.
+		err = postgresql.WithTransactionRetry(ctx, s.db, func(tx pgx.Tx) error {
+			txCtx := postgresql.ContextWithTx(ctx, tx)
+
+			// Allocate the invoice number first; a failure below rolls it back with everything else
+			number, err := s.invoiceRepo.AllocateNumber(txCtx, companyID)
 			if err != nil {
-				return fmt.Errorf("create xendit invoice: %w", err)
+				return fmt.Errorf("allocate invoice number: %w", err)
+			}
+			invoice.InvoiceNumber = subscription.FormatInvoiceNumber(now.Year(), number)
+
+			if paymentInvoice.ID == "" {
+				// Get payer email from company
+				// Note: You may need to add this to the company domain if not already present
+				payerEmail := fmt.Sprintf("billing+%s@company.com", companyID) // Placeholder
+
+				// Create provider invoice
+				paymentReq := subscription.PaymentInvoiceRequest{
+					ExternalID:         fmt.Sprintf("seat-up-%s-%d", sub.ID, now.Unix()),
+					Amount:             proratedAmount,
+					PayerEmail:         payerEmail,
+					Description:        description,
+					Currency:           string(plan.Currency),
+					DurationSeconds:    invoiceExpirySecs,
+					SuccessRedirectURL: s.cfg.Xendit.SuccessRedirect,
+					FailureRedirectURL: s.cfg.Xendit.FailureRedirect,
+				}
+
+				paymentInvoice, err = s.paymentProvider.CreateInvoice(ctx, paymentReq)
+				if err != nil {
+					return fmt.Errorf("create %s invoice: %w", s.paymentProvider.Name(), err)
+				}
 			}
-			xenditResp = xResp
 
-			// Set Xendit details
-			invoice.XenditInvoiceID = &xenditResp.ID
-			invoice.XenditInvoiceURL = &xenditResp.InvoiceURL
-			invoice.XenditExpiryDate = &xenditResp.ExpiryDate
+			// Set provider details
+			invoice.XenditInvoiceID = &paymentInvoice.ID
+			invoice.XenditInvoiceURL = &paymentInvoice.URL
+			invoice.XenditExpiryDate = &paymentInvoice.ExpiryDate
 
 			// Save invoice to database
 			created, err := s.invoiceRepo.Create(txCtx, invoice)
 			if err != nil {
-				// Try to expire the Xendit invoice on failure
-				_, _ = s.xenditClient.ExpireInvoice(*invoice.XenditInvoiceID)
 				return fmt.Errorf("create invoice: %w", err)
 			}
 			createdInvoice = created
@@ -963,6 +1255,12 @@ func (s *subscriptionService) ChangeSeats(ctx context.Context, companyID string,
 		})
 
 		if err != nil {
+			// No stored invoice points at the provider invoice, so expire it
+			if paymentInvoice.ID != "" {
+				if expireErr := s.paymentProvider.ExpireInvoice(context.WithoutCancel(ctx), paymentInvoice.ID); expireErr != nil {
+					log.Printf("ChangeSeats: failed to expire orphaned %s invoice %s: %v", s.paymentProvider.Name(), paymentInvoice.ID, expireErr)
+				}
+			}
 			return subscription.ChangeSeatResponse{}, fmt.Errorf("upsell transaction: %w", err)
 		}
 
@@ -1057,7 +1355,7 @@ func (s *subscriptionService) ApplyPendingDowngrades(ctx context.Context) error
 
 	for _, sub := range subs {
 		// Only apply if period has ended
-		if time.Now().Before(sub.CurrentPeriodEnd) {
+		if s.clock.Now().Before(sub.CurrentPeriodEnd) {
 			continue
 		}
 
@@ -1107,6 +1405,7 @@ func toPlanResponse(plan subscription.Plan) subscription.PlanResponse {
 		ID:           plan.ID,
 		Name:         plan.Name,
 		PricePerSeat: plan.PricePerSeat,
+		Currency:     plan.Currency,
 		TierLevel:    plan.TierLevel,
 		MaxSeats:     plan.MaxSeats,
 		Features:     features,
@@ -1178,7 +1477,9 @@ func toInvoiceResponse(inv subscription.Invoice) subscription.InvoiceResponse {
 
 	return subscription.InvoiceResponse{
 		ID:             inv.ID,
+		InvoiceNumber:  inv.InvoiceNumber,
 		Amount:         inv.Amount,
+		Currency:       inv.Currency,
 		Status:         inv.Status,
 		PlanName:       inv.PlanSnapshotName,
 		SeatCount:      inv.SeatCountSnapshot,
@@ -0,0 +1,94 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+)
+
+// notificationEventPublisher is the in-process EventPublisher: every status change
+// becomes an in-app notification for the owners of the company
+type notificationEventPublisher struct {
+	billingContacts subscription.BillingContactFinder
+	notificationSvc notification.Service
+}
+
+func NewNotificationEventPublisher(billingContacts subscription.BillingContactFinder, notificationSvc notification.Service) subscription.EventPublisher {
+	return &notificationEventPublisher{
+		billingContacts: billingContacts,
+		notificationSvc: notificationSvc,
+	}
+}
+
+func (p *notificationEventPublisher) PublishStatusChanged(ctx context.Context, event subscription.StatusChangedEvent) error {
+	// Trial conversions already get a dedicated subscription_activated notification
+	if event.OldStatus == subscription.StatusTrial && event.NewStatus == subscription.StatusActive {
+		return nil
+	}
+
+	owners, err := p.billingContacts.ListOwnersByCompanyID(ctx, event.CompanyID)
+	if err != nil {
+		return fmt.Errorf("get owners of company %s: %w", event.CompanyID, err)
+	}
+
+	title, message := statusChangedMessage(event)
+	for _, owner := range owners {
+		if err := p.notificationSvc.QueueNotification(ctx, notification.CreateNotificationRequest{
+			CompanyID:   event.CompanyID,
+			RecipientID: owner.UserID,
+			Type:        notification.TypeSubscriptionStatusChanged,
+			Title:       title,
+			Message:     message,
+			Data: map[string]interface{}{
+				"subscription_id": event.SubscriptionID,
+				"old_status":      event.OldStatus,
+				"new_status":      event.NewStatus,
+				"period_end":      event.CurrentPeriodEnd,
+			},
+		}); err != nil {
+			return fmt.Errorf("queue notification for %s: %w", owner.UserID, err)
+		}
+	}
+
+	return nil
+}
+
+// statusChangedMessage returns the notification title and message for a transition
+func statusChangedMessage(event subscription.StatusChangedEvent) (string, string) {
+	periodEnd := event.CurrentPeriodEnd.Format("02 January 2006")
+
+	switch event.NewStatus {
+	case subscription.StatusActive:
+		return "Subscription Active", "Payment received. Your subscription is active again."
+	case subscription.StatusPastDue:
+		return "Subscription Past Due",
+			fmt.Sprintf("Your subscription period ended on %s. Renew within %d days to keep access.", periodEnd, subscription.GracePeriodDays)
+	case subscription.StatusCancelled:
+		return "Subscription Cancelled",
+			fmt.Sprintf("Your subscription has been cancelled. You keep access until %s.", periodEnd)
+	case subscription.StatusExpired:
+		return "Subscription Expired", "Your subscription has expired. Choose a plan to restore access."
+	default:
+		return "Subscription Updated", fmt.Sprintf("Your subscription status changed to %s.", event.NewStatus)
+	}
+}
+
+// publishStatusChange hands a committed transition to the event publisher without blocking the caller
+func (s *subscriptionService) publishStatusChange(ctx context.Context, event subscription.StatusChangedEvent) {
+	if s.eventPublisher == nil {
+		return
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = s.clock.Now()
+	}
+
+	go func() {
+		if err := s.eventPublisher.PublishStatusChanged(ctx, event); err != nil {
+			log.Printf("Failed to publish status change %s -> %s for subscription %s: %v",
+				event.OldStatus, event.NewStatus, event.SubscriptionID, err)
+		}
+	}()
+}
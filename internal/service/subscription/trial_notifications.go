@@ -0,0 +1,132 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/notification"
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+)
+
+// NotifyExpiringTrials sends a reminder for each configured lead time (e.g. T-3 and T-1 days)
+// Lead times are processed shortest first so a trial that is already inside several windows
+// only gets the most urgent reminder; earlier windows are then skipped for it.
+func (s *subscriptionService) NotifyExpiringTrials(ctx context.Context) error {
+	now := s.clock.Now()
+
+	leadDays := slices.Clone(s.cfg.Subscription.TrialReminderDays)
+	slices.Sort(leadDays)
+	leadDays = slices.Compact(leadDays)
+
+	sent := 0
+	for _, days := range leadDays {
+		subs, err := s.subscriptionRepo.ListTrialsForReminder(ctx, now, now.AddDate(0, 0, days), days)
+		if err != nil {
+			return fmt.Errorf("list trials for %d-day reminder: %w", days, err)
+		}
+
+		for _, sub := range subs {
+			if err := s.subscriptionRepo.MarkTrialReminderSent(ctx, sub.ID, days); err != nil {
+				log.Printf("Cron: Failed to mark trial reminder for subscription %s: %v", sub.ID, err)
+				continue
+			}
+			s.sendTrialReminder(ctx, sub, now)
+			sent++
+		}
+	}
+
+	if sent > 0 {
+		log.Printf("Cron: Sent %d trial ending reminders", sent)
+	}
+
+	return nil
+}
+
+// sendTrialReminder notifies every owner of the company in-app and by email
+func (s *subscriptionService) sendTrialReminder(ctx context.Context, sub subscription.Subscription, now time.Time) {
+	trialEndsAt := sub.CurrentPeriodEnd
+	if sub.TrialEndsAt != nil {
+		trialEndsAt = *sub.TrialEndsAt
+	}
+	daysLeft := int(math.Ceil(trialEndsAt.Sub(now).Hours() / 24))
+	checkoutLink := fmt.Sprintf("%s/subscription", s.cfg.App.FrontendURL)
+
+	owners, err := s.billingContacts.ListOwnersByCompanyID(ctx, sub.CompanyID)
+	if err != nil {
+		log.Printf("Cron: Failed to get owners of company %s: %v", sub.CompanyID, err)
+		return
+	}
+
+	for _, owner := range owners {
+		if s.notificationSvc != nil {
+			_ = s.notificationSvc.QueueNotification(ctx, notification.CreateNotificationRequest{
+				CompanyID:   sub.CompanyID,
+				RecipientID: owner.UserID,
+				Type:        notification.TypeTrialEnding,
+				Title:       "Trial Ending Soon",
+				Message:     fmt.Sprintf("Your trial ends in %d day(s). Choose a plan to keep using HRIS.", daysLeft),
+				Data: map[string]interface{}{
+					"subscription_id": sub.ID,
+					"trial_ends_at":   trialEndsAt,
+					"days_left":       daysLeft,
+					"checkout_url":    checkoutLink,
+				},
+			})
+		}
+
+		if s.emailService != nil {
+			if err := s.emailService.SendTrialReminder(
+				owner.Email,
+				owner.Name,
+				owner.CompanyName,
+				daysLeft,
+				trialEndsAt.Format("02 January 2006"),
+				checkoutLink,
+			); err != nil {
+				log.Printf("Cron: Failed to email trial reminder to %s: %v", owner.Email, err)
+			}
+		}
+	}
+}
+
+// notifyTrialConverted confirms to the owners that the trial is now a paid subscription
+func (s *subscriptionService) notifyTrialConverted(ctx context.Context, sub subscription.Subscription, planName string) {
+	owners, err := s.billingContacts.ListOwnersByCompanyID(ctx, sub.CompanyID)
+	if err != nil {
+		log.Printf("Failed to get owners of company %s: %v", sub.CompanyID, err)
+		return
+	}
+
+	for _, owner := range owners {
+		if s.notificationSvc != nil {
+			_ = s.notificationSvc.QueueNotification(ctx, notification.CreateNotificationRequest{
+				CompanyID:   sub.CompanyID,
+				RecipientID: owner.UserID,
+				Type:        notification.TypeSubscriptionActivated,
+				Title:       "Subscription Activated",
+				Message:     fmt.Sprintf("Thank you! Your %s subscription is now active.", planName),
+				Data: map[string]interface{}{
+					"subscription_id": sub.ID,
+					"plan_name":       planName,
+					"period_end":      sub.CurrentPeriodEnd,
+				},
+			})
+		}
+
+		if s.emailService != nil {
+			if err := s.emailService.SendSubscriptionActivated(
+				owner.Email,
+				owner.Name,
+				owner.CompanyName,
+				planName,
+				sub.CurrentPeriodEnd.Format("02 January 2006"),
+			); err != nil {
+				log.Printf("Failed to email subscription activation to %s: %v", owner.Email, err)
+			}
+		}
+	}
+}
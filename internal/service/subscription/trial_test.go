@@ -0,0 +1,30 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cmlabs-hris/hris-backend-go/internal/domain/subscription"
+	"github.com/cmlabs-hris/hris-backend-go/internal/pkg/clock"
+)
+
+func TestCreateTrialSubscriptionLosesRace(t *testing.T) {
+	// Both signups found no subscription; the other one inserted first
+	winner := subscription.Subscription{ID: "sub-winner", CompanyID: "company-1", PlanID: "plan-trial", Status: subscription.StatusTrial}
+	subs := &fakeSubscriptionRepo{
+		subs:   map[string]subscription.Subscription{},
+		racing: map[string]subscription.Subscription{"company-1": winner},
+	}
+	s := newExpiryTestService(subs, clock.NewFake(signupAt))
+
+	got, err := s.CreateTrialSubscription(context.Background(), "company-1", "")
+	if err != nil {
+		t.Fatalf("CreateTrialSubscription() error = %v, want the existing subscription", err)
+	}
+	if got.ID != winner.ID {
+		t.Errorf("CreateTrialSubscription() = %s, want %s", got.ID, winner.ID)
+	}
+	if len(subs.subs) != 1 || subs.subs["company-1"].ID != winner.ID {
+		t.Errorf("subscriptions %+v, want only %s", subs.subs, winner.ID)
+	}
+}